@@ -281,6 +281,19 @@ bool MVCCIsValidSplitKey(DBSlice key);
 DBStatus MVCCFindSplitKey(DBIterator* iter, DBKey start, DBKey end, DBKey min_split,
                           int64_t target_size, DBString* split_key);
 
+// DBIgnoredSeqNumRange is an inclusive range of sequence numbers
+// whose writes a transaction has rolled back. Its layout matches that
+// of enginepb.IgnoredSeqNumRange.
+typedef struct {
+  int32_t start;
+  int32_t end;
+} DBIgnoredSeqNumRange;
+
+typedef struct {
+  DBIgnoredSeqNumRange* ranges;
+  int len;
+} DBIgnoredSeqNums;
+
 // DBTxn contains the fields from a roachpb.Transaction that are
 // necessary for MVCC Get and Scan operations. Note that passing a
 // serialized roachpb.Transaction appears to be a non-starter as an
@@ -293,6 +306,7 @@ typedef struct {
   DBSlice id;
   uint32_t epoch;
   DBTimestamp max_timestamp;
+  DBIgnoredSeqNums ignored_seqnums;
 } DBTxn;
 
 typedef struct {
@@ -60,6 +60,7 @@ template <bool reverse> class mvccScanner {
         txn_id_(ToSlice(txn.id)),
         txn_epoch_(txn.epoch),
         txn_max_timestamp_(txn.max_timestamp),
+        txn_ignored_seqnums_(txn.ignored_seqnums),
         consistent_(consistent),
         tombstones_(tombstones),
         check_uncertainty_(timestamp < txn.max_timestamp),
@@ -174,6 +175,22 @@ template <bool reverse> class mvccScanner {
     return false;
   }
 
+  // seqIsIgnored returns true iff the sequence number falls in one of
+  // the ranges our txn has rolled back. The ranges are sorted and
+  // non-overlapping, and the list is usually very short.
+  bool seqIsIgnored(int32_t seq) const {
+    for (int i = 0; i < txn_ignored_seqnums_.len; ++i) {
+      const DBIgnoredSeqNumRange& r = txn_ignored_seqnums_.ranges[i];
+      if (seq < r.start) {
+        return false;
+      }
+      if (seq <= r.end) {
+        return true;
+      }
+    }
+    return false;
+  }
+
   bool getAndAdvance() {
     const bool is_value = cur_timestamp_ != kZeroTimestamp;
 
@@ -262,15 +279,33 @@ template <bool reverse> class mvccScanner {
     }
 
     if (txn_epoch_ == meta_.txn().epoch()) {
-      // 8. We're reading our own txn's intent. Note that we read at
-      // the intent timestamp, not at our read timestamp as the intent
-      // timestamp may have been pushed forward by another
-      // transaction. Txn's always need to read their own writes.
-      return seekVersion(meta_timestamp, false);
+      if (!seqIsIgnored(meta_.txn().sequence())) {
+        // 8. We're reading our own txn's intent. Note that we read at
+        // the intent timestamp, not at our read timestamp as the
+        // intent timestamp may have been pushed forward by another
+        // transaction. Txn's always need to read their own writes.
+        return seekVersion(meta_timestamp, false);
+      }
+
+      // 9. We're reading our own txn's intent but the write at the
+      // intent's sequence number was rolled back. Read the latest
+      // value from the intent history that wasn't rolled back, at the
+      // intent timestamp. If there is none, all of our txn's writes to
+      // the key were rolled back and we read the previous value as if
+      // the intent didn't exist.
+      for (int i = meta_.intent_history_size() - 1; i >= 0; --i) {
+        const auto& entry = meta_.intent_history(i);
+        if (!seqIsIgnored(entry.sequence())) {
+          const std::string raw_key =
+              EncodeKey(cur_key_, meta_timestamp.wall_time, meta_timestamp.logical);
+          return addAndAdvance(raw_key, entry.value());
+        }
+      }
+      return seekVersion(PrevTimestamp(meta_timestamp), false);
     }
 
     if (txn_epoch_ < meta_.txn().epoch()) {
-      // 9. We're reading our own txn's intent but the current txn has
+      // 10. We're reading our own txn's intent but the current txn has
       // an earlier epoch than the intent. Return an error so that the
       // earlier incarnation of our transaction aborts (presumably
       // this is some operation that was retried).
@@ -278,7 +313,7 @@ template <bool reverse> class mvccScanner {
                                  txn_epoch_, meta_.txn().epoch()));
     }
 
-    // 10. We're reading our own txn's intent but the current txn has a
+    // 11. We're reading our own txn's intent but the current txn has a
     // later epoch than the intent. This can happen if the txn was
     // restarted and an earlier iteration wrote the value we're now
     // reading. In this case, we ignore the intent and read the
@@ -418,11 +453,13 @@ template <bool reverse> class mvccScanner {
     }
   }
 
-  bool addAndAdvance(const rocksdb::Slice& value) {
+  bool addAndAdvance(const rocksdb::Slice& value) { return addAndAdvance(cur_raw_key_, value); }
+
+  bool addAndAdvance(const rocksdb::Slice& raw_key, const rocksdb::Slice& value) {
     // Don't include deleted versions (value.size() == 0), unless we've been
     // instructed to include tombstones in the results.
     if (value.size() > 0 || tombstones_) {
-      kvs_->Put(cur_raw_key_, value);
+      kvs_->Put(raw_key, value);
       if (kvs_->Count() > max_keys_) {
         return false;
       }
@@ -607,6 +644,7 @@ template <bool reverse> class mvccScanner {
   const rocksdb::Slice txn_id_;
   const uint32_t txn_epoch_;
   const DBTimestamp txn_max_timestamp_;
+  const DBIgnoredSeqNums txn_ignored_seqnums_;
   const bool consistent_;
   const bool tombstones_;
   const bool check_uncertainty_;
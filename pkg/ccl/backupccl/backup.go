@@ -52,6 +52,12 @@ const (
 	// BackupDescriptorCheckpointName is the file name used to store the
 	// serialized BackupDescriptor proto while the backup is in progress.
 	BackupDescriptorCheckpointName = "BACKUP-CHECKPOINT"
+	// BackupEncryptionInfoName is the file name used to store the serialized
+	// EncryptionInfo proto of encrypted backups.
+	BackupEncryptionInfoName = "ENCRYPTION-INFO"
+	// LatestFileName is the file name used, in a collection of backups, to
+	// list the paths of its latest backup chain.
+	LatestFileName = "LATEST"
 	// BackupFormatInitialVersion is the first version of backup and its files.
 	BackupFormatInitialVersion uint32 = 0
 	// BackupFormatDescriptorTrackingVersion added tracking of complete DBs.
@@ -60,10 +66,16 @@ const (
 
 const (
 	backupOptRevisionHistory = "revision_history"
+	backupOptEncPassphrase   = "encryption_passphrase"
+	backupOptEncKeyFile      = "encryption_key_file"
+	backupOptCollection      = "collection"
 )
 
 var backupOptionExpectValues = map[string]bool{
 	backupOptRevisionHistory: false,
+	backupOptEncPassphrase:   true,
+	backupOptEncKeyFile:      true,
+	backupOptCollection:      true,
 }
 
 // BackupCheckpointInterval is the interval at which backup progress is saved
@@ -72,16 +84,20 @@ var BackupCheckpointInterval = time.Minute
 
 // ReadBackupDescriptorFromURI creates an export store from the given URI, then
 // reads and unmarshals a BackupDescriptor at the standard location in the
-// export storage.
+// export storage. encryption must be set if and only if the backup is
+// encrypted.
 func ReadBackupDescriptorFromURI(
-	ctx context.Context, uri string, settings *cluster.Settings,
+	ctx context.Context,
+	uri string,
+	settings *cluster.Settings,
+	encryption *roachpb.FileEncryptionOptions,
 ) (BackupDescriptor, error) {
 	exportStore, err := storageccl.ExportStorageFromURI(ctx, uri, settings)
 	if err != nil {
 		return BackupDescriptor{}, err
 	}
 	defer exportStore.Close()
-	backupDesc, err := readBackupDescriptor(ctx, exportStore, BackupDescriptorName)
+	backupDesc, err := readBackupDescriptor(ctx, exportStore, BackupDescriptorName, encryption)
 	if err != nil {
 		return BackupDescriptor{}, err
 	}
@@ -92,9 +108,12 @@ func ReadBackupDescriptorFromURI(
 }
 
 // readBackupDescriptor reads and unmarshals a BackupDescriptor from filename in
-// the provided export store.
+// the provided export store, decrypting it if encryption is set.
 func readBackupDescriptor(
-	ctx context.Context, exportStore storageccl.ExportStorage, filename string,
+	ctx context.Context,
+	exportStore storageccl.ExportStorage,
+	filename string,
+	encryption *roachpb.FileEncryptionOptions,
 ) (BackupDescriptor, error) {
 	r, err := exportStore.ReadFile(ctx, filename)
 	if err != nil {
@@ -105,6 +124,18 @@ func readBackupDescriptor(
 	if err != nil {
 		return BackupDescriptor{}, err
 	}
+	if encryption != nil {
+		if !storageccl.AppearsEncrypted(descBytes) {
+			return BackupDescriptor{}, errors.Errorf(
+				"%s is not encrypted: remove the %s or %s option", filename, backupOptEncPassphrase, backupOptEncKeyFile)
+		}
+		if descBytes, err = storageccl.DecryptFile(descBytes, encryption.Key); err != nil {
+			return BackupDescriptor{}, errors.Wrapf(err, "decrypting %s", filename)
+		}
+	} else if storageccl.AppearsEncrypted(descBytes) {
+		return BackupDescriptor{}, errors.Errorf(
+			"%s is encrypted: use the %s or %s option", filename, backupOptEncPassphrase, backupOptEncKeyFile)
+	}
 	var backupDesc BackupDescriptor
 	if err := protoutil.Unmarshal(descBytes, &backupDesc); err != nil {
 		return BackupDescriptor{}, err
@@ -456,13 +487,17 @@ func optsToKVOptions(opts map[string]string) tree.KVOptions {
 func backupJobDescription(
 	backup *tree.Backup, to string, incrementalFrom []string, opts map[string]string,
 ) (string, error) {
+	opts, err := redactEncryptionOpts(opts)
+	if err != nil {
+		return "", err
+	}
 	b := &tree.Backup{
 		AsOf:    backup.AsOf,
 		Options: optsToKVOptions(opts),
 		Targets: backup.Targets,
 	}
 
-	to, err := storageccl.SanitizeExportStorageURI(to)
+	to, err = storageccl.SanitizeExportStorageURI(to)
 	if err != nil {
 		return "", err
 	}
@@ -505,6 +540,7 @@ func writeBackupDescriptor(
 	exportStore storageccl.ExportStorage,
 	filename string,
 	desc *BackupDescriptor,
+	encryption *roachpb.FileEncryptionOptions,
 ) error {
 	sort.Sort(BackupFileDescriptors(desc.Files))
 
@@ -512,6 +548,11 @@ func writeBackupDescriptor(
 	if err != nil {
 		return err
 	}
+	if encryption != nil {
+		if descBuf, err = storageccl.EncryptFile(descBuf, encryption.Key); err != nil {
+			return err
+		}
+	}
 
 	return exportStore.WriteFile(ctx, filename, bytes.NewReader(descBuf))
 }
@@ -576,6 +617,7 @@ func backup(
 	job *jobs.Job,
 	backupDesc *BackupDescriptor,
 	checkpointDesc *BackupDescriptor,
+	encryption *roachpb.FileEncryptionOptions,
 	resultsCh chan<- tree.Datums,
 ) (roachpb.BulkOpSummary, error) {
 	// TODO(dan): Figure out how permissions should work. #6713 is tracking this
@@ -680,12 +722,13 @@ func backup(
 		span := allSpans[i]
 		g.GoCtx(func(ctx context.Context) error {
 			defer func() { <-exportsSem }()
-			header := roachpb.Header{Timestamp: span.end}
+			header := roachpb.Header{Timestamp: span.end, AdmissionPriority: roachpb.ADMISSION_BULK}
 			req := &roachpb.ExportRequest{
 				RequestHeader: roachpb.RequestHeaderFromSpan(span.span),
 				Storage:       exportStore.Conf(),
 				StartTime:     span.start,
 				MVCCFilter:    roachpb.MVCCFilter(backupDesc.MVCCFilter),
+				Encryption:    encryption,
 			}
 			rawRes, pErr := client.SendWrappedWith(ctx, db.NonTransactionalSender(), header, req)
 			if pErr != nil {
@@ -726,7 +769,7 @@ func backup(
 				checkpointMu.Lock()
 				backupDesc.Files = checkpointFiles
 				err := writeBackupDescriptor(
-					ctx, exportStore, BackupDescriptorCheckpointName, backupDesc,
+					ctx, exportStore, BackupDescriptorCheckpointName, backupDesc, encryption,
 				)
 				checkpointMu.Unlock()
 				if err != nil {
@@ -746,7 +789,9 @@ func backup(
 	backupDesc.Files = mu.files
 	backupDesc.EntryCounts = mu.exported
 
-	if err := writeBackupDescriptor(ctx, exportStore, BackupDescriptorName, backupDesc); err != nil {
+	if err := writeBackupDescriptor(
+		ctx, exportStore, BackupDescriptorName, backupDesc, encryption,
+	); err != nil {
 		return mu.exported, err
 	}
 
@@ -775,13 +820,301 @@ func VerifyUsableExportTarget(
 			readable, BackupDescriptorCheckpointName)
 	}
 	if err := writeBackupDescriptor(
-		ctx, exportStore, BackupDescriptorCheckpointName, &BackupDescriptor{},
+		ctx, exportStore, BackupDescriptorCheckpointName, &BackupDescriptor{}, nil, /* encryption */
 	); err != nil {
 		return errors.Wrapf(err, "cannot write to %s", readable)
 	}
 	return nil
 }
 
+// planBackup resolves and validates a BACKUP of the given targets to the
+// given destination, incremental from the given previous backups, and returns
+// the record of the job that performs it.
+func planBackup(
+	ctx context.Context,
+	p sql.PlanHookState,
+	backupStmt *tree.Backup,
+	to string,
+	incrementalFrom []string,
+	opts map[string]string,
+) (jobs.Record, error) {
+	requireVersion2 := false
+
+	endTime := p.ExecCfg().Clock.Now()
+	if backupStmt.AsOf.Expr != nil {
+		var err error
+		if endTime, err = p.EvalAsOfTimestamp(backupStmt.AsOf, endTime); err != nil {
+			return jobs.Record{}, err
+		}
+	}
+
+	exportStore, err := storageccl.ExportStorageFromURI(ctx, to, p.ExecCfg().Settings)
+	if err != nil {
+		return jobs.Record{}, err
+	}
+	defer exportStore.Close()
+
+	mvccFilter := MVCCFilter_Latest
+	if _, ok := opts[backupOptRevisionHistory]; ok {
+		mvccFilter = MVCCFilter_All
+		requireVersion2 = true
+	}
+
+	targetDescs, completeDBs, err := ResolveTargetsToDescriptors(ctx, p, endTime, backupStmt.Targets)
+	if err != nil {
+		return jobs.Record{}, err
+	}
+
+	var tables []*sqlbase.TableDescriptor
+	for _, desc := range targetDescs {
+		if dbDesc := desc.GetDatabase(); dbDesc != nil {
+			if err := p.CheckPrivilege(ctx, dbDesc, privilege.SELECT); err != nil {
+				return jobs.Record{}, err
+			}
+		}
+		if tableDesc := desc.GetTable(); tableDesc != nil {
+			if err := p.CheckPrivilege(ctx, tableDesc, privilege.SELECT); err != nil {
+				return jobs.Record{}, err
+			}
+			tables = append(tables, tableDesc)
+		}
+	}
+
+	if err := ensureInterleavesIncluded(tables); err != nil {
+		return jobs.Record{}, err
+	}
+
+	secret, err := encryptionSecret(ctx, opts, p.ExecCfg().Settings)
+	if err != nil {
+		return jobs.Record{}, err
+	}
+	var encryption *roachpb.FileEncryptionOptions
+	var encryptionInfo *EncryptionInfo
+	if secret != nil {
+		// Older nodes ignore the encryption field of the ExportRequest and
+		// would write the files in plaintext.
+		if !p.ExecCfg().Settings.Version.IsMinSupported(cluster.VersionBackupEncryption) {
+			return jobs.Record{}, errors.Errorf(
+				"encrypted backups require all nodes to be upgraded to %s",
+				cluster.VersionByKey(cluster.VersionBackupEncryption))
+		}
+		if len(incrementalFrom) > 0 {
+			// Incremental backups use the key of the chain they extend.
+			prevStore, err := storageccl.ExportStorageFromURI(ctx, incrementalFrom[0], p.ExecCfg().Settings)
+			if err != nil {
+				return jobs.Record{}, err
+			}
+			encryptionInfo, err = readEncryptionInfo(ctx, prevStore)
+			prevStore.Close()
+			if err != nil {
+				return jobs.Record{}, errors.Wrapf(err, "failed to read backup from %q", incrementalFrom[0])
+			}
+		} else {
+			salt, err := storageccl.GenerateSalt()
+			if err != nil {
+				return jobs.Record{}, err
+			}
+			encryptionInfo = &EncryptionInfo{Salt: salt}
+		}
+		encryption = &roachpb.FileEncryptionOptions{Key: storageccl.GenerateKey(secret, encryptionInfo.Salt)}
+	}
+
+	var prevBackups []BackupDescriptor
+	if len(incrementalFrom) > 0 {
+		clusterID := p.ExecCfg().ClusterID()
+		prevBackups = make([]BackupDescriptor, len(incrementalFrom))
+		for i, uri := range incrementalFrom {
+			desc, err := ReadBackupDescriptorFromURI(ctx, uri, p.ExecCfg().Settings, encryption)
+			if err != nil {
+				return jobs.Record{}, errors.Wrapf(err, "failed to read backup from %q", uri)
+			}
+			// IDs are how we identify tables, and those are only meaningful in the
+			// context of their own cluster, so we need to ensure we only allow
+			// incremental previous backups that we created.
+			if !desc.ClusterID.Equal(clusterID) {
+				return jobs.Record{}, errors.Errorf("previous BACKUP %q belongs to cluster %s", uri, desc.ClusterID.String())
+			}
+			prevBackups[i] = desc
+		}
+	}
+
+	var startTime hlc.Timestamp
+	var newSpans roachpb.Spans
+	if len(prevBackups) > 0 {
+		startTime = prevBackups[len(prevBackups)-1].EndTime
+	}
+
+	var priorIDs map[sqlbase.ID]sqlbase.ID
+
+	var revs []BackupDescriptor_DescriptorRevision
+	if mvccFilter == MVCCFilter_All {
+		priorIDs = make(map[sqlbase.ID]sqlbase.ID)
+		revs, err = getRelevantDescChanges(ctx, p.ExecCfg().DB, startTime, endTime, targetDescs, completeDBs, priorIDs)
+		if err != nil {
+			return jobs.Record{}, err
+		}
+	}
+
+	spans := spansForAllTableIndexes(tables, revs)
+
+	if len(prevBackups) > 0 {
+		tablesInPrev := make(map[sqlbase.ID]struct{})
+		dbsInPrev := make(map[sqlbase.ID]struct{})
+		for _, d := range prevBackups[len(prevBackups)-1].Descriptors {
+			if t := d.GetTable(); t != nil {
+				tablesInPrev[t.ID] = struct{}{}
+			}
+		}
+		for _, d := range prevBackups[len(prevBackups)-1].CompleteDbs {
+			dbsInPrev[d] = struct{}{}
+		}
+
+		for _, d := range targetDescs {
+			if t := d.GetTable(); t != nil {
+				// If we're trying to use a previous backup for this table, ideally it
+				// actually contains this table.
+				if _, ok := tablesInPrev[t.ID]; ok {
+					continue
+				}
+				// This table isn't in the previous backup... maybe was added to a
+				// DB that the previous backup captured?
+				if _, ok := dbsInPrev[t.ParentID]; ok {
+					continue
+				}
+				// Maybe this table is missing from the previous backup because it was
+				// truncated?
+				if t.ReplacementOf.ID != sqlbase.InvalidID {
+
+					// Check if we need to lazy-load the priorIDs (i.e. if this is the first
+					// truncate we've encountered in non-MVCC backup).
+					if priorIDs == nil {
+						priorIDs = make(map[sqlbase.ID]sqlbase.ID)
+						_, err := getAllDescChanges(ctx, p.ExecCfg().DB, startTime, endTime, priorIDs)
+						if err != nil {
+							return jobs.Record{}, err
+						}
+					}
+					found := false
+					for was := t.ReplacementOf.ID; was != sqlbase.InvalidID && !found; was = priorIDs[was] {
+						_, found = tablesInPrev[was]
+					}
+					if found {
+						continue
+					}
+				}
+				return jobs.Record{}, errors.Errorf("previous backup does not contain table %q", t.Name)
+			}
+		}
+
+		var err error
+		_, coveredTime, err := makeImportSpans(spans, prevBackups, keys.MinKey,
+			func(span intervalccl.Range, start, end hlc.Timestamp) error {
+				if (start == hlc.Timestamp{}) {
+					newSpans = append(newSpans, roachpb.Span{Key: span.Start, EndKey: span.End})
+					return nil
+				}
+				return errOnMissingRange(span, start, end)
+			})
+		if err != nil {
+			return jobs.Record{}, errors.Wrap(err, "invalid previous backups (a new full backup may be required if a table has been created, dropped or truncated)")
+		}
+		if coveredTime != startTime {
+			return jobs.Record{}, errors.Errorf("expected previous backups to cover until time %v, got %v", startTime, coveredTime)
+		}
+	}
+
+	// older nodes don't know about many new fields, e.g. MVCCAll and may
+	// incorrectly evaluate either an export RPC, or a resumed backup job.
+	if requireVersion2 && !p.ExecCfg().Settings.Version.IsMinSupported(cluster.Version2_0) {
+		return jobs.Record{}, errors.Errorf(
+			"BACKUP features introduced in 2.0 requires cluster version >= %s (",
+			cluster.VersionByKey(cluster.Version2_0).String(),
+		)
+	}
+
+	// if CompleteDbs is lost by a 1.x node, FormatDescriptorTrackingVersion
+	// means that a 2.0 node will disallow `RESTORE DATABASE foo`, but `RESTORE
+	// foo.table1, foo.table2...` will still work. MVCCFilter would be
+	// mis-handled, but is disallowed above. IntroducedSpans may also be lost by
+	// a 1.x node, meaning that if 1.1 nodes may resume a backup, the limitation
+	// of requiring full backups after schema changes remains.
+
+	backupDesc := BackupDescriptor{
+		StartTime:         startTime,
+		EndTime:           endTime,
+		MVCCFilter:        mvccFilter,
+		Descriptors:       targetDescs,
+		DescriptorChanges: revs,
+		CompleteDbs:       completeDBs,
+		Spans:             spans,
+		IntroducedSpans:   newSpans,
+		FormatVersion:     BackupFormatDescriptorTrackingVersion,
+		BuildInfo:         build.GetInfo(),
+		NodeID:            p.ExecCfg().NodeID.Get(),
+		ClusterID:         p.ExecCfg().ClusterID(),
+	}
+
+	// Sanity check: re-run the validation that RESTORE will do, but this time
+	// including this backup, to ensure that the this backup plus any previous
+	// backups does cover the interval expected.
+	if _, coveredEnd, err := makeImportSpans(
+		spans, append(prevBackups, backupDesc), keys.MinKey, errOnMissingRange,
+	); err != nil {
+		return jobs.Record{}, err
+	} else if coveredEnd != endTime {
+		return jobs.Record{}, errors.Errorf("expected backup (along with any previous backups) to cover to %v, not %v", endTime, coveredEnd)
+	}
+
+	descBytes, err := protoutil.Marshal(&backupDesc)
+	if err != nil {
+		return jobs.Record{}, err
+	}
+
+	description, err := backupJobDescription(backupStmt, to, incrementalFrom, opts)
+	if err != nil {
+		return jobs.Record{}, err
+	}
+
+	if err := VerifyUsableExportTarget(ctx, exportStore, to); err != nil {
+		return jobs.Record{}, err
+	}
+	// A backup into a collection becomes the end of its latest chain once it
+	// succeeds.
+	collection := opts[backupOptCollection]
+	var chain []string
+	if collection != "" {
+		if chain, err = collectionChain(collection, to, incrementalFrom); err != nil {
+			return jobs.Record{}, err
+		}
+	}
+	if encryptionInfo != nil {
+		if err := writeEncryptionInfo(ctx, exportStore, encryptionInfo); err != nil {
+			return jobs.Record{}, err
+		}
+	}
+
+	return jobs.Record{
+		Description: description,
+		Username:    p.User(),
+		DescriptorIDs: func() (sqlDescIDs []sqlbase.ID) {
+			for _, sqlDesc := range backupDesc.Descriptors {
+				sqlDescIDs = append(sqlDescIDs, sqlDesc.GetID())
+			}
+			return sqlDescIDs
+		}(),
+		Details: jobspb.BackupDetails{
+			StartTime:        startTime,
+			EndTime:          endTime,
+			URI:              to,
+			BackupDescriptor: descBytes,
+			Encryption:       encryption,
+			CollectionURI:    collection,
+			CollectionChain:  chain,
+		},
+		Progress: jobspb.BackupProgress{},
+	}, nil
+}
+
 // backupPlanHook implements PlanHookFn.
 func backupPlanHook(
 	_ context.Context, stmt tree.Statement, p sql.PlanHookState,
@@ -833,8 +1166,6 @@ func backupPlanHook(
 			return errors.Errorf("BACKUP cannot be used inside a transaction")
 		}
 
-		requireVersion2 := false
-
 		to, err := toFn()
 		if err != nil {
 			return err
@@ -843,233 +1174,16 @@ func backupPlanHook(
 		if err != nil {
 			return err
 		}
-
-		endTime := p.ExecCfg().Clock.Now()
-		if backupStmt.AsOf.Expr != nil {
-			var err error
-			if endTime, err = p.EvalAsOfTimestamp(backupStmt.AsOf, endTime); err != nil {
-				return err
-			}
-		}
-
-		exportStore, err := storageccl.ExportStorageFromURI(ctx, to, p.ExecCfg().Settings)
-		if err != nil {
-			return err
-		}
-		defer exportStore.Close()
-
 		opts, err := optsFn()
 		if err != nil {
 			return err
 		}
 
-		mvccFilter := MVCCFilter_Latest
-		if _, ok := opts[backupOptRevisionHistory]; ok {
-			mvccFilter = MVCCFilter_All
-			requireVersion2 = true
-		}
-
-		targetDescs, completeDBs, err := ResolveTargetsToDescriptors(ctx, p, endTime, backupStmt.Targets)
-		if err != nil {
-			return err
-		}
-
-		var tables []*sqlbase.TableDescriptor
-		for _, desc := range targetDescs {
-			if dbDesc := desc.GetDatabase(); dbDesc != nil {
-				if err := p.CheckPrivilege(ctx, dbDesc, privilege.SELECT); err != nil {
-					return err
-				}
-			}
-			if tableDesc := desc.GetTable(); tableDesc != nil {
-				if err := p.CheckPrivilege(ctx, tableDesc, privilege.SELECT); err != nil {
-					return err
-				}
-				tables = append(tables, tableDesc)
-			}
-		}
-
-		if err := ensureInterleavesIncluded(tables); err != nil {
-			return err
-		}
-
-		var prevBackups []BackupDescriptor
-		if len(incrementalFrom) > 0 {
-			clusterID := p.ExecCfg().ClusterID()
-			prevBackups = make([]BackupDescriptor, len(incrementalFrom))
-			for i, uri := range incrementalFrom {
-				desc, err := ReadBackupDescriptorFromURI(ctx, uri, p.ExecCfg().Settings)
-				if err != nil {
-					return errors.Wrapf(err, "failed to read backup from %q", uri)
-				}
-				// IDs are how we identify tables, and those are only meaningful in the
-				// context of their own cluster, so we need to ensure we only allow
-				// incremental previous backups that we created.
-				if !desc.ClusterID.Equal(clusterID) {
-					return errors.Errorf("previous BACKUP %q belongs to cluster %s", uri, desc.ClusterID.String())
-				}
-				prevBackups[i] = desc
-			}
-		}
-
-		var startTime hlc.Timestamp
-		var newSpans roachpb.Spans
-		if len(prevBackups) > 0 {
-			startTime = prevBackups[len(prevBackups)-1].EndTime
-		}
-
-		var priorIDs map[sqlbase.ID]sqlbase.ID
-
-		var revs []BackupDescriptor_DescriptorRevision
-		if mvccFilter == MVCCFilter_All {
-			priorIDs = make(map[sqlbase.ID]sqlbase.ID)
-			revs, err = getRelevantDescChanges(ctx, p.ExecCfg().DB, startTime, endTime, targetDescs, completeDBs, priorIDs)
-			if err != nil {
-				return err
-			}
-		}
-
-		spans := spansForAllTableIndexes(tables, revs)
-
-		if len(prevBackups) > 0 {
-			tablesInPrev := make(map[sqlbase.ID]struct{})
-			dbsInPrev := make(map[sqlbase.ID]struct{})
-			for _, d := range prevBackups[len(prevBackups)-1].Descriptors {
-				if t := d.GetTable(); t != nil {
-					tablesInPrev[t.ID] = struct{}{}
-				}
-			}
-			for _, d := range prevBackups[len(prevBackups)-1].CompleteDbs {
-				dbsInPrev[d] = struct{}{}
-			}
-
-			for _, d := range targetDescs {
-				if t := d.GetTable(); t != nil {
-					// If we're trying to use a previous backup for this table, ideally it
-					// actually contains this table.
-					if _, ok := tablesInPrev[t.ID]; ok {
-						continue
-					}
-					// This table isn't in the previous backup... maybe was added to a
-					// DB that the previous backup captured?
-					if _, ok := dbsInPrev[t.ParentID]; ok {
-						continue
-					}
-					// Maybe this table is missing from the previous backup because it was
-					// truncated?
-					if t.ReplacementOf.ID != sqlbase.InvalidID {
-
-						// Check if we need to lazy-load the priorIDs (i.e. if this is the first
-						// truncate we've encountered in non-MVCC backup).
-						if priorIDs == nil {
-							priorIDs = make(map[sqlbase.ID]sqlbase.ID)
-							_, err := getAllDescChanges(ctx, p.ExecCfg().DB, startTime, endTime, priorIDs)
-							if err != nil {
-								return err
-							}
-						}
-						found := false
-						for was := t.ReplacementOf.ID; was != sqlbase.InvalidID && !found; was = priorIDs[was] {
-							_, found = tablesInPrev[was]
-						}
-						if found {
-							continue
-						}
-					}
-					return errors.Errorf("previous backup does not contain table %q", t.Name)
-				}
-			}
-
-			var err error
-			_, coveredTime, err := makeImportSpans(spans, prevBackups, keys.MinKey,
-				func(span intervalccl.Range, start, end hlc.Timestamp) error {
-					if (start == hlc.Timestamp{}) {
-						newSpans = append(newSpans, roachpb.Span{Key: span.Start, EndKey: span.End})
-						return nil
-					}
-					return errOnMissingRange(span, start, end)
-				})
-			if err != nil {
-				return errors.Wrap(err, "invalid previous backups (a new full backup may be required if a table has been created, dropped or truncated)")
-			}
-			if coveredTime != startTime {
-				return errors.Errorf("expected previous backups to cover until time %v, got %v", startTime, coveredTime)
-			}
-		}
-
-		// older nodes don't know about many new fields, e.g. MVCCAll and may
-		// incorrectly evaluate either an export RPC, or a resumed backup job.
-		if requireVersion2 && !p.ExecCfg().Settings.Version.IsMinSupported(cluster.Version2_0) {
-			return errors.Errorf(
-				"BACKUP features introduced in 2.0 requires cluster version >= %s (",
-				cluster.VersionByKey(cluster.Version2_0).String(),
-			)
-		}
-
-		// if CompleteDbs is lost by a 1.x node, FormatDescriptorTrackingVersion
-		// means that a 2.0 node will disallow `RESTORE DATABASE foo`, but `RESTORE
-		// foo.table1, foo.table2...` will still work. MVCCFilter would be
-		// mis-handled, but is disallowed above. IntroducedSpans may also be lost by
-		// a 1.x node, meaning that if 1.1 nodes may resume a backup, the limitation
-		// of requiring full backups after schema changes remains.
-
-		backupDesc := BackupDescriptor{
-			StartTime:         startTime,
-			EndTime:           endTime,
-			MVCCFilter:        mvccFilter,
-			Descriptors:       targetDescs,
-			DescriptorChanges: revs,
-			CompleteDbs:       completeDBs,
-			Spans:             spans,
-			IntroducedSpans:   newSpans,
-			FormatVersion:     BackupFormatDescriptorTrackingVersion,
-			BuildInfo:         build.GetInfo(),
-			NodeID:            p.ExecCfg().NodeID.Get(),
-			ClusterID:         p.ExecCfg().ClusterID(),
-		}
-
-		// Sanity check: re-run the validation that RESTORE will do, but this time
-		// including this backup, to ensure that the this backup plus any previous
-		// backups does cover the interval expected.
-		if _, coveredEnd, err := makeImportSpans(
-			spans, append(prevBackups, backupDesc), keys.MinKey, errOnMissingRange,
-		); err != nil {
-			return err
-		} else if coveredEnd != endTime {
-			return errors.Errorf("expected backup (along with any previous backups) to cover to %v, not %v", endTime, coveredEnd)
-		}
-
-		descBytes, err := protoutil.Marshal(&backupDesc)
-		if err != nil {
-			return err
-		}
-
-		description, err := backupJobDescription(backupStmt, to, incrementalFrom, opts)
+		record, err := planBackup(ctx, p, backupStmt, to, incrementalFrom, opts)
 		if err != nil {
 			return err
 		}
-
-		if err := VerifyUsableExportTarget(ctx, exportStore, to); err != nil {
-			return err
-		}
-
-		_, errCh, err := p.ExecCfg().JobRegistry.StartJob(ctx, resultsCh, jobs.Record{
-			Description: description,
-			Username:    p.User(),
-			DescriptorIDs: func() (sqlDescIDs []sqlbase.ID) {
-				for _, sqlDesc := range backupDesc.Descriptors {
-					sqlDescIDs = append(sqlDescIDs, sqlDesc.GetID())
-				}
-				return sqlDescIDs
-			}(),
-			Details: jobspb.BackupDetails{
-				StartTime:        startTime,
-				EndTime:          endTime,
-				URI:              to,
-				BackupDescriptor: descBytes,
-			},
-			Progress: jobspb.BackupProgress{},
-		})
+		_, errCh, err := p.ExecCfg().JobRegistry.StartJob(ctx, resultsCh, record)
 		if err != nil {
 			return err
 		}
@@ -1106,7 +1220,9 @@ func (b *backupResumer) Resume(
 		return err
 	}
 	var checkpointDesc *BackupDescriptor
-	if desc, err := readBackupDescriptor(ctx, exportStore, BackupDescriptorCheckpointName); err == nil {
+	if desc, err := readBackupDescriptor(
+		ctx, exportStore, BackupDescriptorCheckpointName, details.Encryption,
+	); err == nil {
 		// If the checkpoint is from a different cluster, it's meaningless to us.
 		// More likely though are dummy/lock-out checkpoints with no ClusterID.
 		if desc.ClusterID.Equal(p.ExecCfg().ClusterID()) {
@@ -1129,10 +1245,24 @@ func (b *backupResumer) Resume(
 		job,
 		&backupDesc,
 		checkpointDesc,
+		details.Encryption,
 		resultsCh,
 	)
 	b.res = res
-	return err
+	if err != nil {
+		return err
+	}
+	if details.CollectionURI != "" {
+		// The backup itself is complete and usable at this point, so failing to
+		// advertise it as the latest one of its collection doesn't fail it.
+		if err := writeLatestFile(
+			ctx, details.CollectionURI, details.CollectionChain, p.ExecCfg().Settings,
+		); err != nil {
+			log.Warningf(ctx, "unable to update %s file of collection of backup job %d: %+v",
+				LatestFileName, *job.ID(), err)
+		}
+	}
+	return nil
 }
 
 func (b *backupResumer) OnFailOrCancel(context.Context, *client.Txn, *jobs.Job) error { return nil }
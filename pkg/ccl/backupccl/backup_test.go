@@ -33,6 +33,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl"
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl/sampledataccl"
 	"github.com/cockroachdb/cockroach/pkg/config"
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
@@ -41,6 +42,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/server"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
@@ -2179,6 +2182,119 @@ func TestTimestampMismatch(t *testing.T) {
 	})
 }
 
+func TestRestoreFromLatest(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	const numAccounts = 10
+
+	_, _, sqlDB, rawDir, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, initNone)
+	defer cleanupFn()
+
+	collection := localFoo + "/coll"
+	sqlDB.Exec(t, `BACKUP DATABASE data TO $1 WITH revision_history, collection = $2`,
+		collection+"/full", collection)
+	sqlDB.Exec(t, `UPDATE data.bank SET balance = balance + 1 WHERE id = 1`)
+	var ts1 string
+	sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&ts1)
+	sqlDB.Exec(t, `BACKUP DATABASE data TO $1 INCREMENTAL FROM $2 WITH revision_history, collection = $3`,
+		collection+"/inc1", collection+"/full", collection)
+	sqlDB.Exec(t, `UPDATE data.bank SET balance = balance + 1 WHERE id = 2`)
+	sqlDB.Exec(t, `BACKUP DATABASE data TO $1 INCREMENTAL FROM $2, $3 WITH revision_history, collection = $4`,
+		collection+"/inc2", collection+"/full", collection+"/inc1", collection)
+	var ts2 string
+	sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&ts2)
+
+	latestFile := filepath.Join(rawDir, "foo", "coll", backupccl.LatestFileName)
+	checkLatest := func(chain ...string) {
+		t.Helper()
+		contents, err := ioutil.ReadFile(latestFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := strings.Join(chain, "\n") + "\n"; string(contents) != expected {
+			t.Fatalf("expected %s file %q, got %q", backupccl.LatestFileName, expected, contents)
+		}
+	}
+
+	// Every backup into the collection became its latest one.
+	checkLatest("full", "inc1", "inc2")
+	sqlDB.Exec(t, `CREATE DATABASE latest`)
+	sqlDB.Exec(t, `RESTORE data.bank FROM LATEST IN $1 WITH into_db = 'latest'`, collection)
+	sqlDB.CheckQueryResults(t,
+		`SELECT * FROM latest.bank ORDER BY id`,
+		sqlDB.QueryStr(t, `SELECT * FROM data.bank ORDER BY id`),
+	)
+
+	sqlDB.Exec(t, `CREATE DATABASE asof`)
+	sqlDB.Exec(t,
+		fmt.Sprintf(`RESTORE data.bank FROM LATEST IN $1 AS OF SYSTEM TIME %s WITH into_db = 'asof'`, ts1),
+		collection,
+	)
+	sqlDB.CheckQueryResults(t,
+		`SELECT * FROM asof.bank ORDER BY id`,
+		sqlDB.QueryStr(t, fmt.Sprintf(`SELECT * FROM data.bank AS OF SYSTEM TIME %s ORDER BY id`, ts1)),
+	)
+
+	// Only backups taken with the collection option update the LATEST file of
+	// their collection.
+	sqlDB.Exec(t, `BACKUP DATABASE data TO $1 INCREMENTAL FROM $2, $3, $4 WITH revision_history`,
+		collection+"/other", collection+"/full", collection+"/inc1", collection+"/inc2")
+	checkLatest("full", "inc1", "inc2")
+	for _, dir := range []string{rawDir, filepath.Join(rawDir, "foo")} {
+		if _, err := os.Stat(filepath.Join(dir, backupccl.LatestFileName)); !os.IsNotExist(err) {
+			t.Fatalf("expected no %s file in %s, got %v", backupccl.LatestFileName, dir, err)
+		}
+	}
+	if _, err := sqlDB.DB.Exec(`BACKUP DATABASE data TO $1 WITH collection = $2`,
+		localFoo+"/elsewhere", collection,
+	); !testutils.IsError(err, "is not in collection") {
+		t.Fatalf("expected backup outside of its collection to fail, got %v", err)
+	}
+
+	// Damage the LATEST file to check that RESTORE validates the chain it
+	// lists.
+	sqlDB.Exec(t, `CREATE DATABASE failed`)
+	for _, c := range []struct {
+		chain []string
+		asOf  string
+		err   string
+	}{
+		{[]string{"inc1", "inc2"}, "", "backup chain starts with incremental backup inc1: its full backup is missing"},
+		{[]string{"full", "inc2"}, "", "is an incremental backup missing"},
+		{[]string{"full", "inc1", "full"}, "", "backup chain contains full backup full after backup inc1"},
+		{[]string{"full", "inc1"}, ts2, "backup chain ends at .* with backup inc1, before the requested time"},
+		{[]string{"full", "inc3"}, "", "failed to read backup descriptor"},
+	} {
+		if err := ioutil.WriteFile(latestFile, []byte(strings.Join(c.chain, "\n")), 0644); err != nil {
+			t.Fatal(err)
+		}
+		stmt := `RESTORE data.bank FROM LATEST IN $1`
+		if c.asOf != "" {
+			stmt += ` AS OF SYSTEM TIME ` + c.asOf
+		}
+		stmt += ` WITH into_db = 'failed'`
+		if _, err := sqlDB.DB.Exec(stmt, collection); !testutils.IsError(err, c.err) {
+			t.Fatalf("%q: expected error %q, got %v", c.chain, c.err, err)
+		}
+	}
+
+	// A new full backup starts a new latest chain.
+	sqlDB.Exec(t, `UPDATE data.bank SET balance = balance + 1 WHERE id = 3`)
+	sqlDB.Exec(t, `BACKUP DATABASE data TO $1 WITH collection = $2`, collection+"/2018/full2", collection)
+	checkLatest("2018/full2")
+	sqlDB.Exec(t, `CREATE DATABASE latest2`)
+	sqlDB.Exec(t, `RESTORE data.bank FROM LATEST IN $1 WITH into_db = 'latest2'`, collection)
+	sqlDB.CheckQueryResults(t,
+		`SELECT * FROM latest2.bank ORDER BY id`,
+		sqlDB.QueryStr(t, `SELECT * FROM data.bank ORDER BY id`),
+	)
+
+	if _, err := sqlDB.DB.Exec(
+		`RESTORE data.bank FROM LATEST IN $1 WITH into_db = 'failed'`, localFoo+"/nope",
+	); !testutils.IsError(err, "is it a collection of backups") {
+		t.Fatalf("expected missing LATEST file error, got %v", err)
+	}
+}
+
 func TestBackupLevelDB(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -2870,3 +2986,131 @@ func TestBackupRestoreShowJob(t *testing.T) {
 		{"RESTORE TABLE data.bank FROM 'nodelocal:///foo' WITH into_db = 'data 2', skip_missing_foreign_keys"},
 	})
 }
+
+func TestBackupRestoreEncrypted(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const numAccounts = 20
+	_, _, sqlDB, dir, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, initNone)
+	defer cleanupFn()
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "key"), []byte("some key material\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		opts     string
+		wrongKey string
+	}{
+		{"passphrase", `encryption_passphrase = 'abc'`, `encryption_passphrase = 'abd'`},
+		{"key-file", `encryption_key_file = 'nodelocal:///key'`, `encryption_passphrase = 'abc'`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			full, inc := localFoo+"/"+tc.name+"/full", localFoo+"/"+tc.name+"/inc"
+			sqlDB.Exec(t, fmt.Sprintf(`BACKUP DATABASE data TO $1 WITH %s`, tc.opts), full)
+			sqlDB.Exec(t, `UPDATE data.bank SET balance = balance + 1`)
+			sqlDB.Exec(t, fmt.Sprintf(`BACKUP DATABASE data TO $1 INCREMENTAL FROM $2 WITH %s`, tc.opts), inc, full)
+
+			// Nothing but the salt is written in the clear.
+			for _, backup := range []string{"full", "inc"} {
+				files, err := ioutil.ReadDir(filepath.Join(dir, "foo", tc.name, backup))
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, f := range files {
+					if f.Name() == backupccl.BackupEncryptionInfoName {
+						continue
+					}
+					contents, err := ioutil.ReadFile(filepath.Join(dir, "foo", tc.name, backup, f.Name()))
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !storageccl.AppearsEncrypted(contents) {
+						t.Fatalf("expected %s/%s to be encrypted", backup, f.Name())
+					}
+				}
+			}
+
+			if _, err := sqlDB.DB.Exec(
+				`BACKUP DATABASE data TO $1 INCREMENTAL FROM $2`, localFoo+"/"+tc.name+"/inc2", full,
+			); !testutils.IsError(err, "BACKUP is encrypted: use the encryption_passphrase") {
+				t.Fatalf("expected encrypted backup error, got %v", err)
+			}
+
+			sqlDB.Exec(t, `DROP DATABASE IF EXISTS restored CASCADE`)
+			sqlDB.Exec(t, `CREATE DATABASE restored`)
+			if _, err := sqlDB.DB.Exec(
+				`RESTORE data.* FROM $1, $2 WITH into_db = 'restored'`, full, inc,
+			); !testutils.IsError(err, "BACKUP is encrypted: use the encryption_passphrase") {
+				t.Fatalf("expected encrypted backup error, got %v", err)
+			}
+			if _, err := sqlDB.DB.Exec(
+				fmt.Sprintf(`RESTORE data.* FROM $1, $2 WITH into_db = 'restored', %s`, tc.wrongKey), full, inc,
+			); !testutils.IsError(err, "wrong encryption passphrase or key") {
+				t.Fatalf("expected wrong key error, got %v", err)
+			}
+
+			sqlDB.Exec(t, fmt.Sprintf(`RESTORE data.* FROM $1, $2 WITH into_db = 'restored', %s`, tc.opts), full, inc)
+			sqlDB.CheckQueryResults(t,
+				`SELECT * FROM restored.bank ORDER BY id`,
+				sqlDB.QueryStr(t, `SELECT * FROM data.bank ORDER BY id`),
+			)
+		})
+	}
+
+	if _, err := sqlDB.DB.Exec(
+		`BACKUP DATABASE data TO $1 WITH encryption_passphrase = 'abc', encryption_key_file = 'nodelocal:///key'`,
+		localFoo+"/both",
+	); !testutils.IsError(err, "cannot use both encryption_passphrase and encryption_key_file") {
+		t.Fatalf("expected conflicting options error, got %v", err)
+	}
+
+	plain := localFoo + "/plain"
+	sqlDB.Exec(t, `BACKUP DATABASE data TO $1`, plain)
+	if _, err := sqlDB.DB.Exec(
+		`RESTORE data.* FROM $1 WITH into_db = 'restored', encryption_passphrase = 'abc'`, plain,
+	); !testutils.IsError(err, `is the backup encrypted\?`) {
+		t.Fatalf("expected unencrypted backup error, got %v", err)
+	}
+
+	// The passphrase is not revealed by the descriptions of the jobs.
+	for _, row := range sqlDB.QueryStr(t, `SELECT description FROM [SHOW JOBS]`) {
+		if strings.Contains(row[0], "'abc'") {
+			t.Fatalf("expected passphrase to be redacted in %q", row[0])
+		}
+	}
+}
+
+// TestBackupRestoreEncryptedRequiresVersion verifies that encrypted backups
+// can't be created or restored until all the nodes have been upgraded to a
+// version which encrypts and decrypts the files.
+func TestBackupRestoreEncryptedRequiresVersion(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	oldVersion := cluster.VersionByKey(cluster.VersionEnums)
+	bootstrapVersion := cluster.ClusterVersion{UseVersion: oldVersion, MinimumVersion: oldVersion}
+	params := base.TestClusterArgs{ServerArgs: base.TestServerArgs{
+		Settings: cluster.MakeTestingClusterSettingsWithVersion(oldVersion, cluster.BinaryServerVersion),
+		Knobs: base.TestingKnobs{
+			Store:   &storage.StoreTestingKnobs{BootstrapVersion: &bootstrapVersion},
+			Upgrade: &server.UpgradeTestingKnobs{DisableUpgrade: 1},
+		},
+	}}
+	const numAccounts = 1
+	_, _, sqlDB, _, cleanupFn := backupRestoreTestSetupWithParams(t, singleNode, numAccounts, initNone, params)
+	defer cleanupFn()
+
+	if _, err := sqlDB.DB.Exec(
+		`BACKUP DATABASE data TO $1 WITH encryption_passphrase = 'abc'`, localFoo,
+	); !testutils.IsError(err, "encrypted backups require all nodes to be upgraded") {
+		t.Fatalf("expected version error, got %v", err)
+	}
+	sqlDB.Exec(t, `BACKUP DATABASE data TO $1`, localFoo)
+	sqlDB.Exec(t, `CREATE DATABASE restored`)
+	if _, err := sqlDB.DB.Exec(
+		`RESTORE data.* FROM $1 WITH into_db = 'restored', encryption_passphrase = 'abc'`, localFoo,
+	); !testutils.IsError(err, "restoring encrypted backups requires all nodes to be upgraded") {
+		t.Fatalf("expected version error, got %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+)
+
+// A collection is a directory holding a backup in each of its subdirectories,
+// such as the backups created by a schedule or by BACKUP statements with the
+// collection option. Its LatestFileName file lists the paths, within the
+// collection, of its latest backup chain: a full backup followed by the
+// incremental backups taken on top of it, in order.
+
+// appendPathToURI returns uri with elem appended to its path.
+func appendPathToURI(uri string, elem string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	u.Path = path.Join(u.Path, elem)
+	return u.String(), nil
+}
+
+// pathInCollection returns the path of the backup at uri within the given
+// collection.
+func pathInCollection(collection string, uri string) (string, error) {
+	c, err := url.Parse(collection)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	prefix := strings.TrimSuffix(c.Path, "/") + "/"
+	if u.Scheme != c.Scheme || u.Host != c.Host || !strings.HasPrefix(u.Path, prefix) {
+		return "", errors.Errorf("backup %s is not in collection %s", u.Path, c.Path)
+	}
+	return strings.TrimPrefix(u.Path, prefix), nil
+}
+
+// collectionChain returns the paths within the given collection of the backup
+// chain made of the backups at incrementalFrom followed by the backup at uri,
+// all of which must be in the collection.
+func collectionChain(collection string, uri string, incrementalFrom []string) ([]string, error) {
+	chain := make([]string, 0, len(incrementalFrom)+1)
+	for _, b := range append(append([]string(nil), incrementalFrom...), uri) {
+		elem, err := pathInCollection(collection, b)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, elem)
+	}
+	return chain, nil
+}
+
+// writeLatestFile records the given backup chain as the latest one of the
+// collection.
+func writeLatestFile(
+	ctx context.Context, collection string, chain []string, settings *cluster.Settings,
+) error {
+	store, err := storageccl.ExportStorageFromURI(ctx, collection, settings)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	contents := strings.Join(chain, "\n") + "\n"
+	return store.WriteFile(ctx, LatestFileName, strings.NewReader(contents))
+}
+
+// readLatestFile returns the paths, within the collection, of the latest
+// backup chain of the collection.
+func readLatestFile(
+	ctx context.Context, collection string, settings *cluster.Settings,
+) ([]string, error) {
+	store, err := storageccl.ExportStorageFromURI(ctx, collection, settings)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+	r, err := store.ReadFile(ctx, LatestFileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s file of collection (is it a collection of backups?)", LatestFileName)
+	}
+	defer r.Close()
+	contents, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var chain []string
+	for _, line := range bytes.Split(contents, []byte("\n")) {
+		if line := strings.TrimSpace(string(line)); line != "" {
+			chain = append(chain, line)
+		}
+	}
+	if len(chain) == 0 {
+		return nil, errors.Errorf("%s file of collection lists no backups", LatestFileName)
+	}
+	return chain, nil
+}
+
+// validateBackupChain checks that the given backups, whose paths within their
+// collection are given by names, form a chain: a full backup followed by
+// incremental backups, each starting where the previous one ends. If endTime
+// is set, the chain must also reach it.
+func validateBackupChain(
+	names []string, backupDescs []BackupDescriptor, endTime hlc.Timestamp,
+) error {
+	for i := range backupDescs {
+		desc := &backupDescs[i]
+		if i == 0 {
+			if !desc.StartTime.IsEmpty() {
+				return errors.Errorf(
+					"backup chain starts with incremental backup %s: its full backup is missing", names[i])
+			}
+			continue
+		}
+		prev := &backupDescs[i-1]
+		if desc.StartTime.IsEmpty() {
+			return errors.Errorf(
+				"backup chain contains full backup %s after backup %s", names[i], names[i-1])
+		}
+		if desc.StartTime != prev.EndTime {
+			return errors.Errorf(
+				"backup chain is not contiguous: %s ends at %s but %s starts at %s (is an incremental backup missing?)",
+				names[i-1], prev.EndTime, names[i], desc.StartTime)
+		}
+	}
+	if last := backupDescs[len(backupDescs)-1]; !endTime.IsEmpty() && last.EndTime.Less(endTime) {
+		return errors.Errorf(
+			"backup chain ends at %s with backup %s, before the requested time %s",
+			last.EndTime, names[len(names)-1], endTime)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+)
+
+// Backups are encrypted with a key derived from a secret, either a passphrase
+// or the contents of a key file, and a random salt. Every full backup picks a
+// new salt, which the incremental backups on top of it reuse, so that a chain
+// of backups shares a single key. The salt is stored, unencrypted, in the
+// EncryptionInfo of each backup, while its SSTs, BACKUP descriptor and
+// checkpoints are encrypted.
+
+// encryptionSecret returns the secret given by the encryption options in opts,
+// or nil if the options do not ask for encryption.
+func encryptionSecret(
+	ctx context.Context, opts map[string]string, settings *cluster.Settings,
+) ([]byte, error) {
+	passphrase, hasPassphrase := opts[backupOptEncPassphrase]
+	keyFile, hasKeyFile := opts[backupOptEncKeyFile]
+	switch {
+	case hasPassphrase && hasKeyFile:
+		return nil, errors.Errorf("cannot use both %s and %s", backupOptEncPassphrase, backupOptEncKeyFile)
+	case hasPassphrase:
+		if passphrase == "" {
+			return nil, errors.Errorf("%s cannot be empty", backupOptEncPassphrase)
+		}
+		return []byte(passphrase), nil
+	case hasKeyFile:
+		store, err := storageccl.ExportStorageFromURI(ctx, keyFile, settings)
+		if err != nil {
+			return nil, errors.Wrapf(err, "opening %s", backupOptEncKeyFile)
+		}
+		defer store.Close()
+		r, err := store.ReadFile(ctx, "")
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading %s", backupOptEncKeyFile)
+		}
+		defer r.Close()
+		secret, err := ioutil.ReadAll(r)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading %s", backupOptEncKeyFile)
+		}
+		if secret = bytes.TrimSpace(secret); len(secret) == 0 {
+			return nil, errors.Errorf("%s is empty", backupOptEncKeyFile)
+		}
+		return secret, nil
+	}
+	return nil, nil
+}
+
+// redactEncryptionOpts returns a copy of opts that does not reveal the
+// encryption secret or the credentials of the URIs of the options, to be used
+// in descriptions of jobs.
+func redactEncryptionOpts(opts map[string]string) (map[string]string, error) {
+	redacted := make(map[string]string, len(opts))
+	for k, v := range opts {
+		switch k {
+		case backupOptEncPassphrase:
+			v = "redacted"
+		case backupOptEncKeyFile, backupOptCollection:
+			var err error
+			if v, err = storageccl.SanitizeExportStorageURI(v); err != nil {
+				return nil, err
+			}
+		}
+		redacted[k] = v
+	}
+	return redacted, nil
+}
+
+func writeEncryptionInfo(
+	ctx context.Context, exportStore storageccl.ExportStorage, info *EncryptionInfo,
+) error {
+	buf, err := protoutil.Marshal(info)
+	if err != nil {
+		return err
+	}
+	return exportStore.WriteFile(ctx, BackupEncryptionInfoName, bytes.NewReader(buf))
+}
+
+func readEncryptionInfo(
+	ctx context.Context, exportStore storageccl.ExportStorage,
+) (*EncryptionInfo, error) {
+	r, err := exportStore.ReadFile(ctx, BackupEncryptionInfoName)
+	if err != nil {
+		// ExportStorage does not consistently report missing files, so this
+		// cannot tell an unencrypted backup from an unreadable one.
+		return nil, errors.Wrapf(err, "reading %s (is the backup encrypted?)", BackupEncryptionInfoName)
+	}
+	defer r.Close()
+	buf, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var info EncryptionInfo
+	if err := protoutil.Unmarshal(buf, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// readEncryptionOptionsFromURI returns the encryption options needed to read
+// the backup at uri, given the encryption secret. It returns nil if secret is
+// nil.
+func readEncryptionOptionsFromURI(
+	ctx context.Context, uri string, secret []byte, settings *cluster.Settings,
+) (*roachpb.FileEncryptionOptions, error) {
+	if secret == nil {
+		return nil, nil
+	}
+	exportStore, err := storageccl.ExportStorageFromURI(ctx, uri, settings)
+	if err != nil {
+		return nil, err
+	}
+	defer exportStore.Close()
+	info, err := readEncryptionInfo(ctx, exportStore)
+	if err != nil {
+		return nil, err
+	}
+	return &roachpb.FileEncryptionOptions{Key: storageccl.GenerateKey(secret, info.Salt)}, nil
+}
@@ -56,15 +56,20 @@ var restoreOptionExpectValues = map[string]bool{
 	restoreOptIntoDB:               true,
 	restoreOptSkipMissingFKs:       false,
 	restoreOptSkipMissingSequences: false,
+	backupOptEncPassphrase:         true,
+	backupOptEncKeyFile:            true,
 }
 
 func loadBackupDescs(
-	ctx context.Context, uris []string, settings *cluster.Settings,
+	ctx context.Context,
+	uris []string,
+	settings *cluster.Settings,
+	encryption *roachpb.FileEncryptionOptions,
 ) ([]BackupDescriptor, error) {
 	backupDescs := make([]BackupDescriptor, len(uris))
 
 	for i, uri := range uris {
-		desc, err := ReadBackupDescriptorFromURI(ctx, uri, settings)
+		desc, err := ReadBackupDescriptorFromURI(ctx, uri, settings, encryption)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to read backup descriptor")
 		}
@@ -936,11 +941,15 @@ func WriteTableDescs(
 func restoreJobDescription(
 	restore *tree.Restore, from []string, opts map[string]string,
 ) (string, error) {
+	opts, err := redactEncryptionOpts(opts)
+	if err != nil {
+		return "", err
+	}
 	r := &tree.Restore{
 		AsOf:    restore.AsOf,
 		Options: optsToKVOptions(opts),
 		Targets: restore.Targets,
-		From:    make(tree.Exprs, len(restore.From)),
+		From:    make(tree.Exprs, len(from)),
 	}
 
 	for i, f := range from {
@@ -965,6 +974,7 @@ func restore(
 	sqlDescs []sqlbase.Descriptor,
 	tableRewrites TableRewriteMap,
 	overrideDB string,
+	encryption *roachpb.FileEncryptionOptions,
 	job *jobs.Job,
 	resultsCh chan<- tree.Datums,
 ) (roachpb.BulkOpSummary, []*sqlbase.DatabaseDescriptor, []*sqlbase.TableDescriptor, error) {
@@ -1108,6 +1118,12 @@ func restore(
 		}
 		idx := readyForImportSpan.progressIdx
 
+		if encryption != nil {
+			for i := range readyForImportSpan.files {
+				readyForImportSpan.files[i].Encryption = encryption
+			}
+		}
+
 		importRequest := &roachpb.ImportRequest{
 			// Import is a point request because we don't want DistSender to split
 			// it. Assume (but don't require) the entire post-rewrite span is on the
@@ -1240,6 +1256,21 @@ func restorePlanHook(
 		if err != nil {
 			return err
 		}
+		// chain names the backups of a collection's latest backup chain, which
+		// are validated once their descriptors are loaded.
+		var chain []string
+		if restoreStmt.Latest {
+			if chain, err = readLatestFile(ctx, from[0], p.ExecCfg().Settings); err != nil {
+				return err
+			}
+			collection := from[0]
+			from = make([]string, len(chain))
+			for i, elem := range chain {
+				if from[i], err = appendPathToURI(collection, elem); err != nil {
+					return err
+				}
+			}
+		}
 		var endTime hlc.Timestamp
 		if restoreStmt.AsOf.Expr != nil {
 			// Use Now() for the max timestamp because Restore does its own
@@ -1255,7 +1286,7 @@ func restorePlanHook(
 		if err != nil {
 			return err
 		}
-		return doRestorePlan(ctx, restoreStmt, p, from, endTime, opts, resultsCh)
+		return doRestorePlan(ctx, restoreStmt, p, from, chain, endTime, opts, resultsCh)
 	}
 	return fn, RestoreHeader, nil, nil
 }
@@ -1265,14 +1296,38 @@ func doRestorePlan(
 	restoreStmt *tree.Restore,
 	p sql.PlanHookState,
 	from []string,
+	chain []string,
 	endTime hlc.Timestamp,
 	opts map[string]string,
 	resultsCh chan<- tree.Datums,
 ) error {
-	backupDescs, err := loadBackupDescs(ctx, from, p.ExecCfg().Settings)
+	secret, err := encryptionSecret(ctx, opts, p.ExecCfg().Settings)
 	if err != nil {
 		return err
 	}
+	// Older nodes ignore the encryption field of the ImportRequest and can't
+	// decrypt the files they read.
+	if secret != nil && !p.ExecCfg().Settings.Version.IsMinSupported(cluster.VersionBackupEncryption) {
+		return errors.Errorf(
+			"restoring encrypted backups requires all nodes to be upgraded to %s",
+			cluster.VersionByKey(cluster.VersionBackupEncryption))
+	}
+	// All the backups of a chain are encrypted with the same key.
+	var encryption *roachpb.FileEncryptionOptions
+	if len(from) > 0 {
+		if encryption, err = readEncryptionOptionsFromURI(ctx, from[0], secret, p.ExecCfg().Settings); err != nil {
+			return err
+		}
+	}
+	backupDescs, err := loadBackupDescs(ctx, from, p.ExecCfg().Settings, encryption)
+	if err != nil {
+		return err
+	}
+	if chain != nil {
+		if err := validateBackupChain(chain, backupDescs, endTime); err != nil {
+			return err
+		}
+	}
 
 	if !endTime.IsEmpty() {
 		ok := false
@@ -1344,6 +1399,7 @@ func doRestorePlan(
 			URIs:          from,
 			TableDescs:    tables,
 			OverrideDB:    opts[restoreOptIntoDB],
+			Encryption:    encryption,
 		},
 		Progress: jobspb.RestoreProgress{},
 	})
@@ -1356,7 +1412,7 @@ func doRestorePlan(
 func loadBackupSQLDescs(
 	ctx context.Context, details jobspb.RestoreDetails, settings *cluster.Settings,
 ) ([]BackupDescriptor, []sqlbase.Descriptor, error) {
-	backupDescs, err := loadBackupDescs(ctx, details.URIs, settings)
+	backupDescs, err := loadBackupDescs(ctx, details.URIs, settings, details.Encryption)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1399,6 +1455,7 @@ func (r *restoreResumer) Resume(
 		sqlDescs,
 		details.TableRewrites,
 		details.OverrideDB,
+		details.Encryption,
 		job,
 		resultsCh,
 	)
@@ -0,0 +1,344 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/cron"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+)
+
+// scheduledBackupExecutorType is the executor type of the schedules created
+// by CREATE SCHEDULE FOR BACKUP.
+const scheduledBackupExecutorType = "scheduled-backup"
+
+// scheduledBackupDirFormat is the layout of the name of the directory, within
+// the collection, of each scheduled backup.
+const scheduledBackupDirFormat = "20060102/150405.00"
+
+// createScheduledBackupHook implements PlanHookFn for CREATE SCHEDULE FOR
+// BACKUP.
+func createScheduledBackupHook(
+	_ context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, sqlbase.ResultColumns, []sql.PlanNode, error) {
+	schedStmt, ok := stmt.(*tree.ScheduledBackup)
+	if !ok {
+		return nil, nil, nil, nil
+	}
+
+	const opName = "CREATE SCHEDULE FOR BACKUP"
+	var nameFn func() (string, error)
+	if schedStmt.ScheduleName != nil {
+		var err error
+		if nameFn, err = p.TypeAsString(schedStmt.ScheduleName, opName); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	toFn, err := p.TypeAsString(schedStmt.To, opName)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	recurrenceFn, err := p.TypeAsString(schedStmt.Recurrence, opName)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var fullBackupFn func() (string, error)
+	if schedStmt.FullBackup != nil {
+		if fullBackupFn, err = p.TypeAsString(schedStmt.FullBackup, opName); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	optsFn, err := p.TypeAsStringOpts(schedStmt.BackupOptions, backupOptionExpectValues)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	header := sqlbase.ResultColumns{
+		{Name: "schedule_id", Typ: types.Int},
+		{Name: "name", Typ: types.String},
+	}
+
+	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, stmt.StatementTag())
+		defer tracing.FinishSpan(span)
+
+		if err := utilccl.CheckEnterpriseEnabled(
+			p.ExecCfg().Settings, p.ExecCfg().ClusterID(), p.ExecCfg().Organization(), "BACKUP",
+		); err != nil {
+			return err
+		}
+
+		if err := p.RequireSuperUser(ctx, opName); err != nil {
+			return err
+		}
+
+		to, err := toFn()
+		if err != nil {
+			return err
+		}
+		if _, err := storageccl.ExportStorageConfFromURI(to); err != nil {
+			return err
+		}
+		recurrence, err := recurrenceFn()
+		if err != nil {
+			return err
+		}
+		args := ScheduledBackupExecutionArgs{Database: p.CurrentDatabase()}
+		if fullBackupFn != nil {
+			if args.FullBackupScheduleExpr, err = fullBackupFn(); err != nil {
+				return err
+			}
+			if _, err := cron.Parse(args.FullBackupScheduleExpr); err != nil {
+				return err
+			}
+		}
+		opts, err := optsFn()
+		if err != nil {
+			return err
+		}
+
+		// Resolve the targets now to report mistakes early. They are resolved
+		// again by every run, so that tables created since are included in
+		// backups of whole databases.
+		if _, _, err := ResolveTargetsToDescriptors(
+			ctx, p, p.ExecCfg().Clock.Now(), schedStmt.Targets,
+		); err != nil {
+			return err
+		}
+
+		backupStmt := &tree.Backup{
+			Targets: schedStmt.Targets,
+			To:      tree.NewDString(to),
+			Options: optsToKVOptions(opts),
+		}
+		args.BackupStatement = tree.AsString(backupStmt)
+
+		var name string
+		if nameFn != nil {
+			if name, err = nameFn(); err != nil {
+				return err
+			}
+		} else if name, err = backupJobDescription(backupStmt, to, nil /* incrementalFrom */, opts); err != nil {
+			return err
+		}
+
+		argsBytes, err := protoutil.Marshal(&args)
+		if err != nil {
+			return err
+		}
+		id, err := p.ExecCfg().JobRegistry.CreateSchedule(ctx, p.ExtendedEvalContext().Txn, &jobs.ScheduledJob{
+			Name:          name,
+			Owner:         p.User(),
+			ScheduleExpr:  recurrence,
+			ExecutorType:  scheduledBackupExecutorType,
+			ExecutionArgs: argsBytes,
+		})
+		if err != nil {
+			return err
+		}
+		resultsCh <- tree.Datums{
+			tree.NewDInt(tree.DInt(id)),
+			tree.NewDString(name),
+		}
+		return nil
+	}
+	return fn, header, nil, nil
+}
+
+// scheduledBackupExecutor runs the schedules created by CREATE SCHEDULE FOR
+// BACKUP.
+//
+// Every run starts a backup job into a new directory of the collection. The
+// backup is incremental from the chain of the latest full backup and the
+// incremental backups taken since, unless the full backup schedule is due, in
+// which case it is a full backup that starts a new chain. Since backup jobs
+// run asynchronously, the outcome of the job of a run is only folded into the
+// chain by the next run, which is skipped if that job is still running.
+type scheduledBackupExecutor struct{}
+
+var _ jobs.ScheduledJobExecutor = scheduledBackupExecutor{}
+
+// ExecuteJob implements the jobs.ScheduledJobExecutor interface.
+func (e scheduledBackupExecutor) ExecuteJob(
+	ctx context.Context, r *jobs.Registry, phs interface{}, schedule *jobs.ScheduledJob,
+) error {
+	p := phs.(sql.PlanHookState)
+	var args ScheduledBackupExecutionArgs
+	if err := protoutil.Unmarshal(schedule.ExecutionArgs, &args); err != nil {
+		return errors.Wrap(err, "unmarshal execution args")
+	}
+	execErr := e.executeBackup(ctx, r, p, &args, schedule)
+	// The chain may have been updated even if the backup could not be started.
+	argsBytes, err := protoutil.Marshal(&args)
+	if err != nil {
+		return err
+	}
+	schedule.ExecutionArgs = argsBytes
+	return execErr
+}
+
+func (e scheduledBackupExecutor) executeBackup(
+	ctx context.Context,
+	r *jobs.Registry,
+	p sql.PlanHookState,
+	args *ScheduledBackupExecutionArgs,
+	schedule *jobs.ScheduledJob,
+) error {
+	if running, err := updateBackupChain(ctx, p, args); err != nil {
+		return err
+	} else if running != "" {
+		schedule.Status = fmt.Sprintf("skipped: backup job %d is %s", args.PendingJobID, running)
+		return nil
+	}
+
+	if err := utilccl.CheckEnterpriseEnabled(
+		p.ExecCfg().Settings, p.ExecCfg().ClusterID(), p.ExecCfg().Organization(), "BACKUP",
+	); err != nil {
+		return err
+	}
+
+	stmt, err := parser.ParseOne(args.BackupStatement)
+	if err != nil {
+		return err
+	}
+	backupStmt, ok := stmt.(*tree.Backup)
+	if !ok {
+		return errors.Errorf("unexpected statement %q in scheduled backup", args.BackupStatement)
+	}
+	collectionFn, err := p.TypeAsString(backupStmt.To, "BACKUP")
+	if err != nil {
+		return err
+	}
+	collection, err := collectionFn()
+	if err != nil {
+		return err
+	}
+	optsFn, err := p.TypeAsStringOpts(backupStmt.Options, backupOptionExpectValues)
+	if err != nil {
+		return err
+	}
+	opts, err := optsFn()
+	if err != nil {
+		return err
+	}
+
+	now := p.ExecCfg().Clock.PhysicalTime()
+	full := len(args.Chain) == 0 || args.FullBackupScheduleExpr == "" ||
+		now.UnixNano() >= args.NextFullBackup
+	var incrementalFrom []string
+	if !full {
+		incrementalFrom = args.Chain
+	}
+	to, err := appendPathToURI(collection, now.Format(scheduledBackupDirFormat))
+	if err != nil {
+		return err
+	}
+
+	// The backup is part of the schedule's collection.
+	opts[backupOptCollection] = collection
+
+	// The planner is dedicated to this run: resolve the targets in the database
+	// the schedule was created in.
+	p.SessionData().Database = args.Database
+	record, err := planBackup(ctx, p, backupStmt, to, incrementalFrom, opts)
+	if err != nil {
+		return err
+	}
+	resultsCh := make(chan tree.Datums)
+	job, errCh, err := r.StartJob(ctx, resultsCh, record)
+	if err != nil {
+		return err
+	}
+	go func() {
+		// Drain and ignore results.
+		for range resultsCh {
+		}
+	}()
+	go func() {
+		// The outcome of the job is checked by the next run.
+		<-errCh
+		close(resultsCh)
+	}()
+
+	if full && args.FullBackupScheduleExpr != "" {
+		sched, err := cron.Parse(args.FullBackupScheduleExpr)
+		if err != nil {
+			return err
+		}
+		args.NextFullBackup = math.MaxInt64
+		if next := sched.Next(now); !next.IsZero() {
+			args.NextFullBackup = next.UnixNano()
+		}
+	}
+	args.PendingJobID, args.PendingURI, args.PendingFull = *job.ID(), to, full
+	kind := "incremental"
+	if full {
+		kind = "full"
+	}
+	schedule.Status = fmt.Sprintf("started %s backup job %d", kind, *job.ID())
+	return nil
+}
+
+// updateBackupChain folds the outcome of the pending backup job, if any, into
+// the backup chain. If that job is not done yet, it returns its status.
+func updateBackupChain(
+	ctx context.Context, p sql.PlanHookState, args *ScheduledBackupExecutionArgs,
+) (jobs.Status, error) {
+	if args.PendingJobID == 0 {
+		return "", nil
+	}
+	row, err := p.ExecCfg().InternalExecutor.QueryRow(
+		ctx, "scheduled-backup-job-status", nil /* txn */, `SELECT status FROM system.jobs WHERE id = $1`,
+		args.PendingJobID,
+	)
+	if err != nil {
+		return "", err
+	}
+	// A job that no longer exists is treated as failed.
+	status := jobs.StatusFailed
+	if row != nil {
+		status = jobs.Status(tree.MustBeDString(row[0]))
+	}
+	switch status {
+	case jobs.StatusSucceeded:
+		if args.PendingFull {
+			args.Chain = []string{args.PendingURI}
+		} else {
+			args.Chain = append(args.Chain, args.PendingURI)
+		}
+	case jobs.StatusFailed, jobs.StatusCanceled:
+		if args.PendingFull {
+			// Retry the full backup on the next run.
+			args.NextFullBackup = 0
+		}
+	default:
+		return status, nil
+	}
+	args.PendingJobID, args.PendingURI, args.PendingFull = 0, "", false
+	return "", nil
+}
+
+func init() {
+	sql.AddPlanHook(createScheduledBackupHook)
+	jobs.RegisterScheduledJobExecutor(scheduledBackupExecutorType, scheduledBackupExecutor{})
+}
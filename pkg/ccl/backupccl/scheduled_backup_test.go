@@ -0,0 +1,152 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+func TestScheduledBackup(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	defer func(oldInterval time.Duration) {
+		jobs.DefaultAdoptInterval = oldInterval
+	}(jobs.DefaultAdoptInterval)
+	jobs.DefaultAdoptInterval = 10 * time.Millisecond
+
+	const numAccounts = 10
+	ctx, tc, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, initNone)
+	defer cleanupFn()
+
+	var id int64
+	var name string
+	sqlDB.QueryRow(t,
+		`CREATE SCHEDULE 'test' FOR BACKUP DATABASE data INTO $1 RECURRING '@hourly' FULL BACKUP '@yearly'`,
+		localFoo,
+	).Scan(&id, &name)
+	if name != "test" {
+		t.Fatalf("expected name %q, got %q", "test", name)
+	}
+	sqlDB.CheckQueryResults(t,
+		`SELECT name, owner, recurrence, executor_type FROM [SHOW SCHEDULES]`,
+		[][]string{{"test", "root", "@hourly", "scheduled-backup"}},
+	)
+
+	for _, c := range []struct {
+		stmt, err string
+	}{
+		{`CREATE SCHEDULE FOR BACKUP DATABASE data INTO $1 RECURRING '@sometimes'`, `unknown cron macro`},
+		{`CREATE SCHEDULE FOR BACKUP DATABASE data INTO $1 RECURRING '@hourly' FULL BACKUP '* *'`,
+			`expected 5 fields`},
+		{`CREATE SCHEDULE FOR BACKUP DATABASE nope INTO $1 RECURRING '@hourly'`, `unknown database "nope"`},
+	} {
+		if _, err := sqlDB.DB.Exec(c.stmt, localFoo); !testutils.IsError(err, c.err) {
+			t.Fatalf("%s: expected error %q, got %v", c.stmt, c.err, err)
+		}
+	}
+
+	// run makes the schedule due and waits until it started a backup job of the
+	// expected kind and that job succeeded.
+	var lastJobID int64
+	run := func(expectedKind string) backupccl.ScheduledBackupExecutionArgs {
+		sqlDB.Exec(t, `UPDATE system.scheduled_jobs SET next_run = $2 WHERE schedule_id = $1`,
+			id, timeutil.Now().Add(-time.Second))
+		var args backupccl.ScheduledBackupExecutionArgs
+		testutils.SucceedsSoon(t, func() error {
+			var status string
+			var argsBytes []byte
+			sqlDB.QueryRow(t,
+				`SELECT schedule_status, execution_args FROM system.scheduled_jobs WHERE schedule_id = $1`, id,
+			).Scan(&status, &argsBytes)
+			args = backupccl.ScheduledBackupExecutionArgs{}
+			if err := protoutil.Unmarshal(argsBytes, &args); err != nil {
+				t.Fatal(err)
+			}
+			if strings.HasPrefix(status, "error") {
+				t.Fatalf("schedule failed: %s", status)
+			}
+			if args.PendingJobID == lastJobID {
+				return errors.Errorf("schedule did not run yet: %s", status)
+			}
+			if expected := "started " + expectedKind + " backup job"; !strings.HasPrefix(status, expected) {
+				t.Fatalf("expected status %q, got %q", expected, status)
+			}
+			return nil
+		})
+		lastJobID = args.PendingJobID
+		var jobStatus string
+		testutils.SucceedsSoon(t, func() error {
+			sqlDB.QueryRow(t, `SELECT status FROM system.jobs WHERE id = $1`, lastJobID).Scan(&jobStatus)
+			if jobStatus != string(jobs.StatusSucceeded) {
+				return errors.Errorf("expected backup job %d to succeed, got %s", lastJobID, jobStatus)
+			}
+			return nil
+		})
+		return args
+	}
+
+	full := run("full")
+	if len(full.Chain) != 0 || !full.PendingFull {
+		t.Fatalf("expected a pending full backup and no chain, got %+v", full)
+	}
+
+	sqlDB.Exec(t, `UPDATE data.bank SET balance = balance + 1 WHERE id = 1`)
+	inc1 := run("incremental")
+	if expected := []string{full.PendingURI}; len(inc1.Chain) != 1 || inc1.Chain[0] != expected[0] {
+		t.Fatalf("expected chain %q, got %q", expected, inc1.Chain)
+	}
+
+	sqlDB.Exec(t, `UPDATE data.bank SET balance = balance + 1 WHERE id = 2`)
+	inc2 := run("incremental")
+	if expected := []string{full.PendingURI, inc1.PendingURI}; len(inc2.Chain) != 2 ||
+		inc2.Chain[0] != expected[0] || inc2.Chain[1] != expected[1] {
+		t.Fatalf("expected chain %q, got %q", expected, inc2.Chain)
+	}
+
+	// Every backup is incremental from the previous one.
+	settings := tc.Server(0).ClusterSettings()
+	var prevEnd string
+	for i, uri := range []string{full.PendingURI, inc1.PendingURI, inc2.PendingURI} {
+		desc, err := backupccl.ReadBackupDescriptorFromURI(ctx, uri, settings, nil /* encryption */)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i > 0 && desc.StartTime.String() != prevEnd {
+			t.Fatalf("expected backup %d to start at %s, got %s", i, prevEnd, desc.StartTime)
+		}
+		prevEnd = desc.EndTime.String()
+	}
+
+	sqlDB.Exec(t, `CREATE DATABASE restored`)
+	sqlDB.Exec(t, `RESTORE data.* FROM $1, $2, $3 WITH into_db = 'restored'`,
+		full.PendingURI, inc1.PendingURI, inc2.PendingURI)
+	sqlDB.CheckQueryResults(t,
+		`SELECT * FROM restored.bank ORDER BY id`,
+		sqlDB.QueryStr(t, `SELECT * FROM data.bank ORDER BY id`),
+	)
+
+	// The backups of the schedule are the latest chain of its collection.
+	sqlDB.Exec(t, `CREATE DATABASE latest`)
+	sqlDB.Exec(t, `RESTORE data.* FROM LATEST IN $1 WITH into_db = 'latest'`, localFoo)
+	sqlDB.CheckQueryResults(t,
+		`SELECT * FROM latest.bank ORDER BY id`,
+		sqlDB.QueryStr(t, `SELECT * FROM data.bank ORDER BY id`),
+	)
+}
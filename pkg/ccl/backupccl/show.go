@@ -23,6 +23,11 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 )
 
+var showBackupOptionExpectValues = map[string]bool{
+	backupOptEncPassphrase: true,
+	backupOptEncKeyFile:    true,
+}
+
 // showBackupPlanHook implements PlanHookFn.
 func showBackupPlanHook(
 	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
@@ -47,6 +52,11 @@ func showBackupPlanHook(
 		return nil, nil, nil, err
 	}
 
+	optsFn, err := p.TypeAsStringOpts(backup.Options, showBackupOptionExpectValues)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
 	var shower backupShower
 	switch backup.Details {
 	case tree.BackupRangeDetails:
@@ -66,12 +76,24 @@ func showBackupPlanHook(
 		if err != nil {
 			return err
 		}
-		desc, err := ReadBackupDescriptorFromURI(ctx, str, p.ExecCfg().Settings)
+		opts, err := optsFn()
+		if err != nil {
+			return err
+		}
+		secret, err := encryptionSecret(ctx, opts, p.ExecCfg().Settings)
+		if err != nil {
+			return err
+		}
+		encryption, err := readEncryptionOptionsFromURI(ctx, str, secret, p.ExecCfg().Settings)
+		if err != nil {
+			return err
+		}
+		desc, err := ReadBackupDescriptorFromURI(ctx, str, p.ExecCfg().Settings, encryption)
 		if err != nil {
 			return err
 		}
 
-		for _, row := range shower.fn(desc) {
+		for _, row := range shower.fn(desc, encryption != nil) {
 			select {
 			case <-ctx.Done():
 				return ctx.Err()
@@ -86,7 +108,7 @@ func showBackupPlanHook(
 
 type backupShower struct {
 	header sqlbase.ResultColumns
-	fn     func(BackupDescriptor) []tree.Datums
+	fn     func(desc BackupDescriptor, encrypted bool) []tree.Datums
 }
 
 var backupShowerDefault = backupShower{
@@ -97,9 +119,10 @@ var backupShowerDefault = backupShower{
 		{Name: "end_time", Typ: types.Timestamp},
 		{Name: "size_bytes", Typ: types.Int},
 		{Name: "rows", Typ: types.Int},
+		{Name: "is_encrypted", Typ: types.Bool},
 	},
 
-	fn: func(desc BackupDescriptor) []tree.Datums {
+	fn: func(desc BackupDescriptor, encrypted bool) []tree.Datums {
 		descs := make(map[sqlbase.ID]string)
 		for _, descriptor := range desc.Descriptors {
 			if database := descriptor.GetDatabase(); database != nil {
@@ -138,6 +161,7 @@ var backupShowerDefault = backupShower{
 					tree.MakeDTimestamp(timeutil.Unix(0, desc.EndTime.WallTime), time.Nanosecond),
 					tree.NewDInt(tree.DInt(descSizes[table.ID].DataSize)),
 					tree.NewDInt(tree.DInt(descSizes[table.ID].Rows)),
+					tree.MakeDBool(tree.DBool(encrypted)),
 				})
 			}
 		}
@@ -153,7 +177,7 @@ var backupShowerRanges = backupShower{
 		{Name: "end_key", Typ: types.Bytes},
 	},
 
-	fn: func(desc BackupDescriptor) (rows []tree.Datums) {
+	fn: func(desc BackupDescriptor, _ bool) (rows []tree.Datums) {
 		for _, span := range desc.Spans {
 			rows = append(rows, tree.Datums{
 				tree.NewDString(span.Key.String()),
@@ -177,7 +201,7 @@ var backupShowerFiles = backupShower{
 		{Name: "rows", Typ: types.Int},
 	},
 
-	fn: func(desc BackupDescriptor) (rows []tree.Datums) {
+	fn: func(desc BackupDescriptor, _ bool) (rows []tree.Datums) {
 		for _, file := range desc.Files {
 			rows = append(rows, tree.Datums{
 				tree.NewDString(file.Path),
@@ -17,6 +17,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 )
@@ -37,7 +38,7 @@ func TestShowBackup(t *testing.T) {
 	var start, end *time.Time
 	var dataSize, rows uint64
 	sqlDB.QueryRow(t, `SELECT * FROM [SHOW BACKUP $1] WHERE table_name = 'bank'`, full).Scan(
-		&unused, &unused, &start, &end, &dataSize, &rows,
+		&unused, &unused, &start, &end, &dataSize, &rows, &unused,
 	)
 	if start != nil {
 		t.Errorf("expected null start time on full backup, got %v", *start)
@@ -66,7 +67,7 @@ func TestShowBackup(t *testing.T) {
 	sqlDB.Exec(t, `BACKUP data.bank TO $1 INCREMENTAL FROM $2`, inc, full)
 
 	sqlDB.QueryRow(t, `SELECT * FROM [SHOW BACKUP $1] WHERE table_name = 'bank'`, inc).Scan(
-		&unused, &unused, &start, &end, &dataSize, &rows,
+		&unused, &unused, &start, &end, &dataSize, &rows, &unused,
 	)
 	if start == nil {
 		t.Errorf("expected start time on inc backup, got %v", *start)
@@ -116,4 +117,25 @@ func TestShowBackup(t *testing.T) {
 	if len(pathRows) != 2 {
 		t.Fatalf("expected 2 files, but got %d", len(pathRows))
 	}
+
+	encrypted := localFoo + "/encrypted"
+	sqlDB.Exec(t, `BACKUP data.bank TO $1 WITH encryption_passphrase = 'abc'`, encrypted)
+	sqlDB.CheckQueryResults(t,
+		fmt.Sprintf(`SELECT DISTINCT is_encrypted FROM [SHOW BACKUP '%s']`, full),
+		[][]string{{"false"}},
+	)
+	sqlDB.CheckQueryResults(t,
+		fmt.Sprintf(`SELECT table_name, rows, is_encrypted FROM [SHOW BACKUP '%s' WITH encryption_passphrase = 'abc']`, encrypted),
+		[][]string{{"bank", fmt.Sprint(numAccounts), "true"}},
+	)
+	if _, err := sqlDB.DB.Exec(`SHOW BACKUP $1`, encrypted); !testutils.IsError(err,
+		`BACKUP is encrypted: use the encryption_passphrase or encryption_key_file option`,
+	) {
+		t.Fatalf("expected encrypted backup error, got %v", err)
+	}
+	if _, err := sqlDB.DB.Exec(
+		`SHOW BACKUP $1 WITH encryption_passphrase = 'abd'`, encrypted,
+	); !testutils.IsError(err, `decrypting BACKUP: wrong encryption passphrase or key`) {
+		t.Fatalf("expected wrong passphrase error, got %v", err)
+	}
 }
@@ -0,0 +1,509 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"encoding/binary"
+	gojson "encoding/json"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/cockroachdb/apd"
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl/avroccl"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/pkg/errors"
+)
+
+// This file is a minimal implementation of the Avro 1.8 binary encoding, which
+// is all that is needed to produce messages in the Confluent wire format. See
+// https://avro.apache.org/docs/1.8.2/spec.html for the details.
+
+const (
+	avroSchemaBoolean = `boolean`
+	avroSchemaBytes   = `bytes`
+	avroSchemaDouble  = `double`
+	avroSchemaInt     = `int`
+	avroSchemaLong    = `long`
+	avroSchemaNull    = `null`
+	avroSchemaRecord  = `record`
+	avroSchemaString  = `string`
+
+	avroLogicalDate            = `date`
+	avroLogicalDecimal         = `decimal`
+	avroLogicalTimestampMicros = `timestamp-micros`
+	avroLogicalUUID            = `uuid`
+)
+
+// avroSchemaType is one of the set of avro primitive types (a string), a
+// logical type (an *avroLogicalType), a union of types (a slice of these), or a
+// record (an *avroRecord).
+type avroSchemaType interface{}
+
+// avroLogicalType is an avro primitive type annotated with extra information
+// about how it should be interpreted.
+type avroLogicalType struct {
+	SchemaType  avroSchemaType `json:"type"`
+	LogicalType string         `json:"logicalType"`
+	Precision   int            `json:"precision,omitempty"`
+	Scale       int            `json:"scale,omitempty"`
+}
+
+// avroSchemaField is the schema for one field of an avro record. It also knows
+// how to convert a datum for the corresponding SQL column to and from the avro
+// binary encoding.
+type avroSchemaField struct {
+	SchemaType avroSchemaType `json:"type"`
+	Name       string         `json:"name"`
+	Default    *string        `json:"default"`
+
+	// nullable is true if SchemaType is a union of null and the column type.
+	nullable bool
+
+	encodeFn func([]byte, tree.Datum) ([]byte, error)
+	decodeFn func([]byte) (tree.Datum, []byte, error)
+}
+
+// avroRecord is an avro record schema. It is marshalled to json to make the
+// schema that is registered with the schema registry.
+type avroRecord struct {
+	SchemaType string             `json:"type"`
+	Name       string             `json:"name"`
+	Fields     []*avroSchemaField `json:"fields"`
+}
+
+// avroDataRecord is an avroRecord that represents (a subset of) the columns of
+// a SQL table. It maps each field back to the ordinal of the table column it
+// was generated from.
+type avroDataRecord struct {
+	avroRecord
+
+	colIdxByFieldIdx map[int]int
+}
+
+// avroEnvelopeRecord is the record used to wrap every value message. The after
+// field is the data record of the changed row (or null if the row was deleted),
+// the updated field is set when the `updated` option was requested, and the
+// resolved field is only set on resolved timestamp messages.
+type avroEnvelopeRecord struct {
+	avroRecord
+
+	opts  avroEnvelopeOpts
+	after *avroDataRecord
+}
+
+type avroEnvelopeOpts struct {
+	afterField    bool
+	updatedField  bool
+	resolvedField bool
+}
+
+func columnDescToAvroSchema(colDesc *sqlbase.ColumnDescriptor) (*avroSchemaField, error) {
+	schema := &avroSchemaField{
+		Name: avroccl.SQLNameToAvroName(colDesc.Name),
+	}
+
+	switch colDesc.Type.SemanticType {
+	case sqlbase.ColumnType_INT:
+		schema.SchemaType = avroSchemaLong
+		schema.encodeFn = func(b []byte, d tree.Datum) ([]byte, error) {
+			return avroccl.AppendLong(b, int64(*d.(*tree.DInt))), nil
+		}
+		schema.decodeFn = func(b []byte) (tree.Datum, []byte, error) {
+			i, b, err := avroccl.ConsumeLong(b)
+			return tree.NewDInt(tree.DInt(i)), b, err
+		}
+	case sqlbase.ColumnType_BOOL:
+		schema.SchemaType = avroSchemaBoolean
+		schema.encodeFn = func(b []byte, d tree.Datum) ([]byte, error) {
+			if *d.(*tree.DBool) {
+				return append(b, 1), nil
+			}
+			return append(b, 0), nil
+		}
+		schema.decodeFn = func(b []byte) (tree.Datum, []byte, error) {
+			if len(b) < 1 {
+				return nil, nil, errors.New(`insufficient bytes to decode boolean`)
+			}
+			return tree.MakeDBool(b[0] != 0), b[1:], nil
+		}
+	case sqlbase.ColumnType_FLOAT:
+		schema.SchemaType = avroSchemaDouble
+		schema.encodeFn = func(b []byte, d tree.Datum) ([]byte, error) {
+			var buf [8]byte
+			binary.LittleEndian.PutUint64(buf[:], math.Float64bits(float64(*d.(*tree.DFloat))))
+			return append(b, buf[:]...), nil
+		}
+		schema.decodeFn = func(b []byte) (tree.Datum, []byte, error) {
+			if len(b) < 8 {
+				return nil, nil, errors.New(`insufficient bytes to decode double`)
+			}
+			f := math.Float64frombits(binary.LittleEndian.Uint64(b))
+			return tree.NewDFloat(tree.DFloat(f)), b[8:], nil
+		}
+	case sqlbase.ColumnType_STRING:
+		schema.SchemaType = avroSchemaString
+		schema.encodeFn = func(b []byte, d tree.Datum) ([]byte, error) {
+			return avroccl.AppendBytes(b, []byte(*d.(*tree.DString))), nil
+		}
+		schema.decodeFn = func(b []byte) (tree.Datum, []byte, error) {
+			s, b, err := avroccl.ConsumeBytes(b)
+			return tree.NewDString(string(s)), b, err
+		}
+	case sqlbase.ColumnType_BYTES:
+		schema.SchemaType = avroSchemaBytes
+		schema.encodeFn = func(b []byte, d tree.Datum) ([]byte, error) {
+			return avroccl.AppendBytes(b, []byte(*d.(*tree.DBytes))), nil
+		}
+		schema.decodeFn = func(b []byte) (tree.Datum, []byte, error) {
+			s, b, err := avroccl.ConsumeBytes(b)
+			return tree.NewDBytes(tree.DBytes(s)), b, err
+		}
+	case sqlbase.ColumnType_DATE:
+		schema.SchemaType = &avroLogicalType{
+			SchemaType:  avroSchemaInt,
+			LogicalType: avroLogicalDate,
+		}
+		schema.encodeFn = func(b []byte, d tree.Datum) ([]byte, error) {
+			return avroccl.AppendLong(b, int64(*d.(*tree.DDate))), nil
+		}
+		schema.decodeFn = func(b []byte) (tree.Datum, []byte, error) {
+			i, b, err := avroccl.ConsumeLong(b)
+			return tree.NewDDate(tree.DDate(i)), b, err
+		}
+	case sqlbase.ColumnType_TIMESTAMP:
+		schema.SchemaType = &avroLogicalType{
+			SchemaType:  avroSchemaLong,
+			LogicalType: avroLogicalTimestampMicros,
+		}
+		schema.encodeFn = func(b []byte, d tree.Datum) ([]byte, error) {
+			return avroccl.AppendLong(b, d.(*tree.DTimestamp).UnixNano()/1000), nil
+		}
+		schema.decodeFn = func(b []byte) (tree.Datum, []byte, error) {
+			micros, b, err := avroccl.ConsumeLong(b)
+			t := timeutil.Unix(0, micros*1000)
+			return tree.MakeDTimestamp(t, time.Microsecond), b, err
+		}
+	case sqlbase.ColumnType_TIMESTAMPTZ:
+		schema.SchemaType = &avroLogicalType{
+			SchemaType:  avroSchemaLong,
+			LogicalType: avroLogicalTimestampMicros,
+		}
+		schema.encodeFn = func(b []byte, d tree.Datum) ([]byte, error) {
+			return avroccl.AppendLong(b, d.(*tree.DTimestampTZ).UnixNano()/1000), nil
+		}
+		schema.decodeFn = func(b []byte) (tree.Datum, []byte, error) {
+			micros, b, err := avroccl.ConsumeLong(b)
+			t := timeutil.Unix(0, micros*1000)
+			return tree.MakeDTimestampTZ(t, time.Microsecond), b, err
+		}
+	case sqlbase.ColumnType_DECIMAL:
+		if colDesc.Type.Precision == 0 {
+			return nil, errors.Errorf(
+				`column %s: avro requires a precision for DECIMAL columns`, colDesc.Name)
+		}
+		scale := int(colDesc.Type.Width)
+		schema.SchemaType = &avroLogicalType{
+			SchemaType:  avroSchemaBytes,
+			LogicalType: avroLogicalDecimal,
+			Precision:   int(colDesc.Type.Precision),
+			Scale:       scale,
+		}
+		schema.encodeFn = func(b []byte, d tree.Datum) ([]byte, error) {
+			var dec apd.Decimal
+			if _, err := tree.HighPrecisionCtx.Quantize(
+				&dec, &d.(*tree.DDecimal).Decimal, -int32(scale),
+			); err != nil {
+				return nil, err
+			}
+			unscaled := new(big.Int).Set(&dec.Coeff)
+			if dec.Negative {
+				unscaled.Neg(unscaled)
+			}
+			return avroccl.AppendBytes(b, avroccl.BigIntToTwosComplement(unscaled)), nil
+		}
+		schema.decodeFn = func(b []byte) (tree.Datum, []byte, error) {
+			raw, b, err := avroccl.ConsumeBytes(b)
+			if err != nil {
+				return nil, nil, err
+			}
+			unscaled := avroccl.TwosComplementToBigInt(raw)
+			d := &tree.DDecimal{}
+			d.Decimal.Exponent = -int32(scale)
+			if unscaled.Sign() < 0 {
+				d.Decimal.Negative = true
+				unscaled.Neg(unscaled)
+			}
+			d.Decimal.Coeff.Set(unscaled)
+			return d, b, nil
+		}
+	case sqlbase.ColumnType_UUID:
+		schema.SchemaType = &avroLogicalType{
+			SchemaType:  avroSchemaString,
+			LogicalType: avroLogicalUUID,
+		}
+		schema.encodeFn = func(b []byte, d tree.Datum) ([]byte, error) {
+			return avroccl.AppendBytes(b, []byte(d.(*tree.DUuid).UUID.String())), nil
+		}
+		schema.decodeFn = func(b []byte) (tree.Datum, []byte, error) {
+			s, b, err := avroccl.ConsumeBytes(b)
+			if err != nil {
+				return nil, nil, err
+			}
+			u, err := uuid.FromString(string(s))
+			return tree.NewDUuid(tree.DUuid{UUID: u}), b, err
+		}
+	case sqlbase.ColumnType_INET:
+		schema.SchemaType = avroSchemaString
+		schema.encodeFn = func(b []byte, d tree.Datum) ([]byte, error) {
+			return avroccl.AppendBytes(b, []byte(d.(*tree.DIPAddr).IPAddr.String())), nil
+		}
+		schema.decodeFn = func(b []byte) (tree.Datum, []byte, error) {
+			s, b, err := avroccl.ConsumeBytes(b)
+			if err != nil {
+				return nil, nil, err
+			}
+			d, err := tree.ParseDIPAddrFromINetString(string(s))
+			return d, b, err
+		}
+	case sqlbase.ColumnType_JSONB:
+		schema.SchemaType = avroSchemaString
+		schema.encodeFn = func(b []byte, d tree.Datum) ([]byte, error) {
+			return avroccl.AppendBytes(b, []byte(d.(*tree.DJSON).JSON.String())), nil
+		}
+		schema.decodeFn = func(b []byte) (tree.Datum, []byte, error) {
+			s, b, err := avroccl.ConsumeBytes(b)
+			if err != nil {
+				return nil, nil, err
+			}
+			d, err := tree.ParseDJSON(string(s))
+			return d, b, err
+		}
+	case sqlbase.ColumnType_ENUM:
+		// Enums are encoded as their labels rather than as avro enums, whose
+		// symbols are restricted to identifiers and which could not be
+		// reordered by ALTER TYPE ... ADD VALUE compatibly.
+		typ := colDesc.Type.ToDatumType().(types.TEnum)
+		schema.SchemaType = avroSchemaString
+		schema.encodeFn = func(b []byte, d tree.Datum) ([]byte, error) {
+			return avroccl.AppendBytes(b, []byte(d.(*tree.DEnum).Logical)), nil
+		}
+		schema.decodeFn = func(b []byte) (tree.Datum, []byte, error) {
+			s, b, err := avroccl.ConsumeBytes(b)
+			if err != nil {
+				return nil, nil, err
+			}
+			d, err := tree.MakeDEnumFromLogicalRepresentation(typ, string(s))
+			return d, b, err
+		}
+	default:
+		return nil, errors.Errorf(`column %s: type %s not yet supported with avro`,
+			colDesc.Name, colDesc.Type.SQLString())
+	}
+
+	// Make every field optional by unioning it with null, so that all schema
+	// evolutions for a table are considered "backward compatible" by avro. This
+	// means that the Avro type doesn't mirror the column's nullability, but it
+	// makes it much easier to work with long-lived changefeeds.
+	//
+	// The default for a union type is the default for the first element of the
+	// union.
+	schema.SchemaType = []avroSchemaType{avroSchemaNull, schema.SchemaType}
+	schema.nullable = true
+
+	return schema, nil
+}
+
+// encodeDatum appends the avro binary encoding of the datum to b.
+func (f *avroSchemaField) encodeDatum(b []byte, d tree.Datum) ([]byte, error) {
+	if f.nullable {
+		if d == tree.DNull {
+			return avroccl.AppendLong(b, 0 /* null branch */), nil
+		}
+		b = avroccl.AppendLong(b, 1 /* non-null branch */)
+	} else if d == tree.DNull {
+		return nil, errors.Errorf(`field %s cannot be null`, f.Name)
+	}
+	return f.encodeFn(b, d)
+}
+
+// decodeDatum is the inverse of encodeDatum. It returns the decoded datum and
+// the remaining bytes.
+func (f *avroSchemaField) decodeDatum(b []byte) (tree.Datum, []byte, error) {
+	if f.nullable {
+		branch, rest, err := avroccl.ConsumeLong(b)
+		if err != nil {
+			return nil, nil, err
+		}
+		if branch == 0 {
+			return tree.DNull, rest, nil
+		}
+		b = rest
+	}
+	return f.decodeFn(b)
+}
+
+// indexToAvroSchema converts the columns of an index into the corresponding avro
+// record schema. The fields are kept in the same order as columns in the index.
+func indexToAvroSchema(
+	tableDesc *sqlbase.TableDescriptor, indexDesc *sqlbase.IndexDescriptor,
+) (*avroDataRecord, error) {
+	schema := &avroDataRecord{
+		avroRecord: avroRecord{
+			Name:       avroccl.SQLNameToAvroName(tableDesc.Name),
+			SchemaType: avroSchemaRecord,
+		},
+		colIdxByFieldIdx: make(map[int]int),
+	}
+	colIdxByID := tableDesc.ColumnIdxMap()
+	for _, colID := range indexDesc.ColumnIDs {
+		colIdx, ok := colIdxByID[colID]
+		if !ok {
+			return nil, errors.Errorf(`unknown column id: %d`, colID)
+		}
+		col := tableDesc.Columns[colIdx]
+		field, err := columnDescToAvroSchema(&col)
+		if err != nil {
+			return nil, err
+		}
+		schema.colIdxByFieldIdx[len(schema.Fields)] = colIdx
+		schema.Fields = append(schema.Fields, field)
+	}
+	return schema, nil
+}
+
+// tableToAvroSchema converts the columns of a table into the corresponding avro
+// record schema. The fields are kept in the same order as `tableDesc.Columns`.
+func tableToAvroSchema(tableDesc *sqlbase.TableDescriptor) (*avroDataRecord, error) {
+	schema := &avroDataRecord{
+		avroRecord: avroRecord{
+			Name:       avroccl.SQLNameToAvroName(tableDesc.Name),
+			SchemaType: avroSchemaRecord,
+		},
+		colIdxByFieldIdx: make(map[int]int),
+	}
+	for colIdx := range tableDesc.Columns {
+		field, err := columnDescToAvroSchema(&tableDesc.Columns[colIdx])
+		if err != nil {
+			return nil, err
+		}
+		schema.colIdxByFieldIdx[len(schema.Fields)] = colIdx
+		schema.Fields = append(schema.Fields, field)
+	}
+	return schema, nil
+}
+
+// BinaryFromRow appends the avro binary encoding of the given row to buf. The
+// row must be in `tableDesc.Columns` order, as returned by the rowfetcher.
+func (r *avroDataRecord) BinaryFromRow(buf []byte, row tree.Datums) ([]byte, error) {
+	for fieldIdx, field := range r.Fields {
+		var err error
+		buf, err = field.encodeDatum(buf, row[r.colIdxByFieldIdx[fieldIdx]])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
+
+// RowFromBinary decodes the avro binary encoding of a record into a row of
+// datums indexed the same way as the row given to BinaryFromRow. Columns that
+// are not part of this record are left nil. It returns any bytes remaining
+// after the record.
+func (r *avroDataRecord) RowFromBinary(buf []byte, numCols int) (tree.Datums, []byte, error) {
+	row := make(tree.Datums, numCols)
+	for fieldIdx, field := range r.Fields {
+		var err error
+		row[r.colIdxByFieldIdx[fieldIdx]], buf, err = field.decodeDatum(buf)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, `decoding field %s`, field.Name)
+		}
+	}
+	return row, buf, nil
+}
+
+// envelopeToAvroSchema creates an avro record schema for an envelope containing
+// the requested fields.
+func envelopeToAvroSchema(
+	topic string, opts avroEnvelopeOpts, after *avroDataRecord,
+) *avroEnvelopeRecord {
+	schema := &avroEnvelopeRecord{
+		avroRecord: avroRecord{
+			Name:       avroccl.SQLNameToAvroName(topic) + `_envelope`,
+			SchemaType: avroSchemaRecord,
+		},
+		opts:  opts,
+		after: after,
+	}
+	if opts.afterField {
+		schema.Fields = append(schema.Fields, &avroSchemaField{
+			Name:       `after`,
+			SchemaType: []avroSchemaType{avroSchemaNull, &after.avroRecord},
+			nullable:   true,
+		})
+	}
+	if opts.updatedField {
+		schema.Fields = append(schema.Fields, &avroSchemaField{
+			Name:       `updated`,
+			SchemaType: []avroSchemaType{avroSchemaNull, avroSchemaString},
+			nullable:   true,
+		})
+	}
+	if opts.resolvedField {
+		schema.Fields = append(schema.Fields, &avroSchemaField{
+			Name:       `resolved`,
+			SchemaType: []avroSchemaType{avroSchemaNull, avroSchemaString},
+			nullable:   true,
+		})
+	}
+	return schema
+}
+
+// BinaryFromRow appends the avro binary encoding of an envelope to buf. The
+// after row may be nil to indicate a deletion and the updated and resolved
+// strings are only used if the corresponding field was requested.
+func (r *avroEnvelopeRecord) BinaryFromRow(
+	buf []byte, after tree.Datums, updated, resolved string,
+) ([]byte, error) {
+	var err error
+	if r.opts.afterField {
+		if after == nil {
+			buf = avroccl.AppendLong(buf, 0 /* null branch */)
+		} else {
+			buf = avroccl.AppendLong(buf, 1 /* non-null branch */)
+			if buf, err = r.after.BinaryFromRow(buf, after); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if r.opts.updatedField {
+		buf = avroccl.AppendLong(buf, 1 /* non-null branch */)
+		buf = avroccl.AppendBytes(buf, []byte(updated))
+	}
+	if r.opts.resolvedField {
+		buf = avroccl.AppendLong(buf, 1 /* non-null branch */)
+		buf = avroccl.AppendBytes(buf, []byte(resolved))
+	}
+	return buf, nil
+}
+
+// String returns the json representation of the schema, which is what is
+// registered with the schema registry.
+func (r *avroRecord) String() string {
+	b, err := gojson.Marshal(r)
+	if err != nil {
+		// All of the types in the schema are known to marshal without error.
+		panic(err)
+	}
+	return string(b)
+}
@@ -0,0 +1,150 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+	"github.com/stretchr/testify/require"
+)
+
+func parseTableDesc(createTableStmt string) (*sqlbase.TableDescriptor, error) {
+	ctx := context.Background()
+	const parentID, tableID = sqlbase.ID(keys.MinUserDescID), sqlbase.ID(keys.MinUserDescID + 1)
+	tableDesc, err := sql.CreateTestTableDescriptor(
+		ctx, parentID, tableID, createTableStmt, sqlbase.NewDefaultPrivilegeDescriptor())
+	if err != nil {
+		return nil, err
+	}
+	return &tableDesc, tableDesc.ValidateTable(cluster.MakeTestingClusterSettings())
+}
+
+func TestAvroSchema(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	t.Run(`schema`, func(t *testing.T) {
+		tableDesc, err := parseTableDesc(`CREATE TABLE "foo.bar" (a INT PRIMARY KEY, b STRING NOT NULL)`)
+		require.NoError(t, err)
+		schema, err := tableToAvroSchema(tableDesc)
+		require.NoError(t, err)
+		require.Equal(t,
+			`{"type":"record","name":"foo_bar","fields":[`+
+				`{"type":["null","long"],"name":"a","default":null},`+
+				`{"type":["null","string"],"name":"b","default":null}]}`,
+			schema.String())
+
+		keySchema, err := indexToAvroSchema(tableDesc, &tableDesc.PrimaryIndex)
+		require.NoError(t, err)
+		require.Equal(t,
+			`{"type":"record","name":"foo_bar","fields":[`+
+				`{"type":["null","long"],"name":"a","default":null}]}`,
+			keySchema.String())
+	})
+
+	t.Run(`unsupported`, func(t *testing.T) {
+		for _, typ := range []string{`INTERVAL`, `DECIMAL`, `INT[]`, `TIME`} {
+			tableDesc, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b ` + typ + `)`)
+			require.NoError(t, err)
+			_, err = tableToAvroSchema(tableDesc)
+			if !testutils.IsError(err, `column b: `) {
+				t.Errorf(`%s: expected unsupported error got: %+v`, typ, err)
+			}
+		}
+	})
+
+	t.Run(`roundtrip`, func(t *testing.T) {
+		rng, _ := randutil.NewPseudoRand()
+		evalCtx := tree.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
+
+		typesToTest := []string{
+			`INT`, `BOOL`, `FLOAT`, `STRING`, `BYTES`, `DATE`, `TIMESTAMP`, `TIMESTAMPTZ`,
+			`UUID`, `INET`, `JSONB`, `DECIMAL(10,3)`,
+		}
+		for _, sqlType := range typesToTest {
+			tableDesc, err := parseTableDesc(
+				`CREATE TABLE foo (a INT PRIMARY KEY, b ` + sqlType + `)`)
+			require.NoError(t, err)
+			schema, err := tableToAvroSchema(tableDesc)
+			require.NoError(t, err)
+			colType := tableDesc.Columns[1].Type
+
+			for i := 0; i < 20; i++ {
+				datum := sqlbase.RandDatum(rng, colType, true /* nullOk */)
+				// Round the datum the same way it would be on insert.
+				switch d := datum.(type) {
+				case *tree.DDecimal:
+					if err := tree.LimitDecimalWidth(
+						&d.Decimal, int(colType.Precision), int(colType.Width),
+					); err != nil {
+						// Out of range for the column, skip it.
+						continue
+					}
+				case *tree.DTimestamp:
+					datum = tree.MakeDTimestamp(d.Time, time.Microsecond)
+				case *tree.DTimestampTZ:
+					datum = tree.MakeDTimestampTZ(d.Time, time.Microsecond)
+				}
+				row := tree.Datums{tree.NewDInt(tree.DInt(i)), datum}
+				encoded, err := schema.BinaryFromRow(nil, row)
+				require.NoError(t, err)
+				decoded, rest, err := schema.RowFromBinary(encoded, len(row))
+				require.NoError(t, err)
+				require.Len(t, rest, 0)
+				for colIdx := range row {
+					if row[colIdx].Compare(&evalCtx, decoded[colIdx]) != 0 {
+						t.Errorf(`%s: expected %s got %s`, sqlType, row[colIdx], decoded[colIdx])
+					}
+				}
+			}
+		}
+	})
+
+	t.Run(`enum`, func(t *testing.T) {
+		evalCtx := tree.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
+		tableDesc, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		require.NoError(t, err)
+		physical := sqlbase.GenerateEnumPhysicalRepresentations(3)
+		typ := types.MakeEnum(100, `mood`, []types.EnumMember{
+			{Logical: `sad`, Physical: physical[0]},
+			{Logical: `ok`, Physical: physical[1]},
+			{Logical: `happy`, Physical: physical[2]},
+		})
+		tableDesc.Columns[1].Type = sqlbase.MakeEnumColumnType(typ)
+		schema, err := tableToAvroSchema(tableDesc)
+		require.NoError(t, err)
+		require.Equal(t,
+			`{"type":"record","name":"foo","fields":[`+
+				`{"type":["null","long"],"name":"a","default":null},`+
+				`{"type":["null","string"],"name":"b","default":null}]}`,
+			schema.String())
+
+		for i, m := range typ.Members() {
+			datum, err := tree.MakeDEnumFromLogicalRepresentation(typ, m.Logical)
+			require.NoError(t, err)
+			row := tree.Datums{tree.NewDInt(tree.DInt(i)), datum}
+			encoded, err := schema.BinaryFromRow(nil, row)
+			require.NoError(t, err)
+			decoded, rest, err := schema.RowFromBinary(encoded, len(row))
+			require.NoError(t, err)
+			require.Len(t, rest, 0)
+			require.Equal(t, 0, row[1].Compare(&evalCtx, decoded[1]))
+		}
+	})
+}
@@ -17,7 +17,11 @@ import (
 )
 
 type bufferEntry struct {
-	kv       roachpb.KeyValue
+	kv roachpb.KeyValue
+	// prevVal is the value of kv.Key immediately before kv. It's only populated
+	// if the changefeed needs it (`envelope=diff`) and is empty if the key did
+	// not previously exist or was deleted.
+	prevVal  roachpb.Value
 	resolved *jobspb.ResolvedSpan
 }
 
@@ -33,13 +37,14 @@ func makeBuffer() *buffer {
 	return &buffer{entriesCh: make(chan bufferEntry)}
 }
 
-// AddKV inserts a changed kv into the buffer.
+// AddKV inserts a changed kv into the buffer, along with the previous value of
+// its key.
 //
 // TODO(dan): AddKV currently requires that each key is added in increasing mvcc
 // timestamp order. This will have to change when we add support for RangeFeed,
 // which starts out in a catchup state without this guarantee.
-func (b *buffer) AddKV(ctx context.Context, kv roachpb.KeyValue) error {
-	return b.addEntry(ctx, bufferEntry{kv: kv})
+func (b *buffer) AddKV(ctx context.Context, kv roachpb.KeyValue, prevVal roachpb.Value) error {
+	return b.addEntry(ctx, bufferEntry{kv: kv, prevVal: prevVal})
 }
 
 // AddResolved inserts a resolved timestamp notification in the buffer.
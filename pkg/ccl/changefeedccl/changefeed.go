@@ -9,9 +9,7 @@
 package changefeedccl
 
 import (
-	"bytes"
 	"context"
-	gojson "encoding/json"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/jobs"
@@ -23,7 +21,6 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/bufalloc"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
-	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 )
 
@@ -53,6 +50,17 @@ type emitRow struct {
 	// tableDesc is a TableDescriptor for the table containing `datums`.
 	// It's valid for interpreting the row at `timestamp`.
 	tableDesc *sqlbase.TableDescriptor
+	// prevDatums is the value of the row immediately before this change. It's
+	// only populated if the changefeed needs it (`envelope=diff`) and is nil if
+	// the row did not previously exist.
+	prevDatums tree.Datums
+	// projection, if non-nil, is the `CREATE CHANGEFEED ... AS SELECT`
+	// projection of the changefeed. In this case, the value is encoded from
+	// `projected` and `prevProjected`, while the key is still encoded from the
+	// primary key columns in `datums`.
+	projection    *changefeedProjection
+	projected     tree.Datums
+	prevProjected tree.Datums
 }
 
 type emitEntry struct {
@@ -71,14 +79,20 @@ type emitEntry struct {
 func kvsToRows(
 	leaseMgr *sql.LeaseManager,
 	tableHist *tableHistory,
+	evalCtx *tree.EvalContext,
 	details jobspb.ChangefeedDetails,
 	inputFn func(context.Context) (bufferEntry, error),
 ) func(context.Context) ([]emitEntry, error) {
 	rfCache := newRowFetcherCache(leaseMgr, tableHist)
+	withPrev := needsPrevValues(details)
+	var projCache *projectionCache
+	if details.Select != `` {
+		projCache = newProjectionCache(details.Select)
+	}
 
 	var kvs sqlbase.SpanKVFetcher
 	appendEmitEntryForKV := func(
-		ctx context.Context, output []emitEntry, kv roachpb.KeyValue,
+		ctx context.Context, output []emitEntry, kv roachpb.KeyValue, prevVal roachpb.Value,
 	) ([]emitEntry, error) {
 		// Reuse kvs to save allocations.
 		kvs.KVs = kvs.KVs[:0]
@@ -105,6 +119,7 @@ func kvsToRows(
 			return nil, err
 		}
 
+		start := len(output)
 		for {
 			var r emitEntry
 			r.row.datums, r.row.tableDesc, _, err = rf.NextRowDecoded(ctx)
@@ -120,7 +135,39 @@ func kvsToRows(
 			r.row.timestamp = kv.Value.Timestamp
 			output = append(output, r)
 		}
-		return output, nil
+
+		// Only decode the previous value if it's needed and the row existed.
+		if withPrev && len(prevVal.RawBytes) > 0 {
+			// Decode the previous value with the same descriptor as the new
+			// one.
+			//
+			// TODO(dan): Use the descriptor that was valid when the previous
+			// value was written, which differs if a column was added or dropped
+			// between the two.
+			kvs.KVs = append(kvs.KVs[:0], roachpb.KeyValue{Key: kv.Key, Value: prevVal})
+			if err := rf.StartScanFrom(ctx, &kvs); err != nil {
+				return nil, err
+			}
+			prevDatums, _, _, err := rf.NextRowDecoded(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if prevDatums != nil && !rf.RowIsDeleted() {
+				prevDatums = append(tree.Datums(nil), prevDatums...)
+				for i := start; i < len(output); i++ {
+					output[i].row.prevDatums = prevDatums
+				}
+			}
+		}
+
+		if projCache == nil {
+			return output, nil
+		}
+		proj, err := projCache.ProjectionForTableDesc(desc)
+		if err != nil {
+			return nil, err
+		}
+		return projectRows(evalCtx, proj, output, start)
 	}
 
 	var output []emitEntry
@@ -136,7 +183,7 @@ func kvsToRows(
 				if log.V(3) {
 					log.Infof(ctx, "changed key %s %s", input.kv.Key, input.kv.Value.Timestamp)
 				}
-				output, err = appendEmitEntryForKV(ctx, output, input.kv)
+				output, err = appendEmitEntryForKV(ctx, output, input.kv, input.prevVal)
 				if err != nil {
 					return nil, err
 				}
@@ -151,57 +198,90 @@ func kvsToRows(
 	}
 }
 
+// needsPrevValues returns whether the changefeed needs the previous value of
+// each changed kv, either to emit it with `envelope=diff` or to know whether
+// the previous version of a row passed the filter of a `CREATE CHANGEFEED ...
+// AS SELECT ... WHERE`.
+func needsPrevValues(details jobspb.ChangefeedDetails) bool {
+	return envelopeType(details.Opts[optEnvelope]) == optEnvelopeDiff ||
+		selectHasFilter(details.Select)
+}
+
+// projectRows applies a `CREATE CHANGEFEED ... AS SELECT` projection and filter
+// to the rows in `output[start:]`. A row is emitted as long as either it or its
+// previous version passes the filter: a row which stops passing it (including
+// by being deleted) is emitted as a deletion, and the rows which never passed
+// it are dropped.
+func projectRows(
+	evalCtx *tree.EvalContext, proj *changefeedProjection, output []emitEntry, start int,
+) ([]emitEntry, error) {
+	kept := output[:start]
+	for _, r := range output[start:] {
+		var matches, prevMatches bool
+		var err error
+		if !r.row.deleted {
+			if matches, err = proj.Matches(evalCtx, r.row.datums); err != nil {
+				return nil, err
+			}
+		}
+		if r.row.prevDatums != nil {
+			if prevMatches, err = proj.Matches(evalCtx, r.row.prevDatums); err != nil {
+				return nil, err
+			}
+		} else if !proj.HasFilter() {
+			// Without a filter, the previous values aren't fetched unless
+			// they're needed for `envelope=diff`, but every row passes.
+			prevMatches = r.row.deleted
+		}
+		if !matches && !prevMatches {
+			continue
+		}
+
+		if matches {
+			if r.row.projected, err = proj.Project(evalCtx, r.row.datums); err != nil {
+				return nil, err
+			}
+		} else {
+			r.row.deleted = true
+		}
+		if prevMatches && r.row.prevDatums != nil {
+			if r.row.prevProjected, err = proj.Project(evalCtx, r.row.prevDatums); err != nil {
+				return nil, err
+			}
+		}
+		r.row.projection = proj
+		kept = append(kept, r)
+	}
+	return kept, nil
+}
+
 // emitEntries connects to a sink, receives rows from a closure, and repeatedly
 // emits them to the sink. It returns a closure that may be repeatedly called to
 // advance the changefeed and which returns span-level resolved timestamp
-// updates. The returned closure is not threadsafe.
+// updates. The returned closure is not threadsafe. The span frontier is
+// forwarded by the resolved spans and is used to tell the sink what has been
+// resolved each time it's flushed.
 func emitEntries(
-	details jobspb.ChangefeedDetails,
+	encoder Encoder,
 	sink Sink,
 	inputFn func(context.Context) ([]emitEntry, error),
+	sf *spanFrontier,
 	knobs TestingKnobs,
 ) func(context.Context) ([]jobspb.ResolvedSpan, error) {
 	var scratch bufalloc.ByteAllocator
-	var key, value bytes.Buffer
 	emitRowFn := func(ctx context.Context, row emitRow) error {
-		key.Reset()
-		value.Reset()
-
-		keyColumns := row.tableDesc.PrimaryIndex.ColumnNames
-		jsonKeyRaw := make([]interface{}, len(keyColumns))
-		jsonValueRaw := make(map[string]interface{}, len(row.datums))
-		if _, ok := details.Opts[optUpdatedTimestamps]; ok {
-			jsonValueRaw[jsonMetaSentinel] = map[string]interface{}{
-				`updated`: tree.TimestampToDecimal(row.timestamp).Decimal.String(),
-			}
-		}
-		for i := range row.datums {
-			var err error
-			jsonValueRaw[row.tableDesc.Columns[i].Name], err = tree.AsJSON(row.datums[i])
-			if err != nil {
-				return err
-			}
-		}
-		for i, columnName := range keyColumns {
-			jsonKeyRaw[i] = jsonValueRaw[columnName]
-		}
-
-		jsonKey, err := json.MakeJSON(jsonKeyRaw)
+		var keyCopy, valueCopy []byte
+		encodedKey, err := encoder.EncodeKey(row)
 		if err != nil {
 			return err
 		}
-		jsonKey.Format(&key)
-		if !row.deleted && envelopeType(details.Opts[optEnvelope]) == optEnvelopeRow {
-			jsonValue, err := json.MakeJSON(jsonValueRaw)
-			if err != nil {
-				return err
-			}
-			jsonValue.Format(&value)
+		scratch, keyCopy = scratch.Copy(encodedKey, 0 /* extraCap */)
+		encodedValue, err := encoder.EncodeValue(row)
+		if err != nil {
+			return err
 		}
+		scratch, valueCopy = scratch.Copy(encodedValue, 0 /* extraCap */)
 
-		var keyCopy, valueCopy []byte
-		scratch, keyCopy = scratch.Copy(key.Bytes(), 0 /* extraCap */)
-		scratch, valueCopy = scratch.Copy(value.Bytes(), 0 /* extraCap */)
 		if knobs.BeforeEmitRow != nil {
 			if err := knobs.BeforeEmitRow(); err != nil {
 				return err
@@ -230,6 +310,7 @@ func emitEntries(
 			}
 			if input.resolved != nil {
 				resolvedSpans = append(resolvedSpans, *input.resolved)
+				sf.Forward(input.resolved.Span, input.resolved.Timestamp)
 			}
 		}
 		if len(resolvedSpans) > 0 {
@@ -240,7 +321,7 @@ func emitEntries(
 			//
 			// TODO(dan): We'll probably want some rate limiting on these
 			// flushes.
-			if err := sink.Flush(ctx); err != nil {
+			if err := sink.Flush(ctx, sf.Frontier()); err != nil {
 				return nil, err
 			}
 			if knobs.AfterSinkFlush != nil {
@@ -258,6 +339,7 @@ func emitEntries(
 func emitResolvedTimestamp(
 	ctx context.Context,
 	details jobspb.ChangefeedDetails,
+	encoder Encoder,
 	sink Sink,
 	jobProgressedFn func(context.Context, jobs.HighWaterProgressedFn) error,
 	sf *spanFrontier,
@@ -287,19 +369,14 @@ func emitResolvedTimestamp(
 	}
 
 	if _, ok := details.Opts[optResolvedTimestamps]; ok {
-		resolvedMetaRaw := map[string]interface{}{
-			jsonMetaSentinel: map[string]interface{}{
-				`resolved`: tree.TimestampToDecimal(resolved).Decimal.String(),
-			},
-		}
-		resolvedMeta, err := gojson.Marshal(resolvedMetaRaw)
+		payload, err := encoder.EncodeResolvedTimestamp(resolved)
 		if err != nil {
 			return err
 		}
 
 		// TODO(dan): Emit more fine-grained (table level) resolved
 		// timestamps.
-		if err := sink.EmitResolvedTimestamp(ctx, resolvedMeta); err != nil {
+		if err := sink.EmitResolvedTimestamp(ctx, payload, resolved); err != nil {
 			return err
 		}
 	}
@@ -47,6 +47,8 @@ type changeAggregator struct {
 	// tableHistUpdaterDoneCh is closed when the tableHistUpdater exits.
 	tableHistUpdaterDoneCh chan struct{}
 
+	// encoder is the Encoder to use for key and value serialization.
+	encoder Encoder
 	// sink is the Sink to write rows to. Resolved timestamps are never written
 	// by changeAggregator.
 	sink Sink
@@ -105,7 +107,10 @@ func newChangeAggregatorProcessor(
 	// CHANGEFEED statement. Therefore, we create a "canary" sink, which will be
 	// immediately closed, only to check for errors.
 	{
-		canarySink, err := getSink(ca.spec.Feed.SinkURI, ca.spec.Feed.Targets)
+		canarySink, err := getSink(
+			ctx, ca.spec.Feed.SinkURI, flowCtx.EvalCtx.NodeID, ca.spec.Feed.Opts,
+			ca.spec.Feed.Targets, flowCtx.Settings,
+		)
 		if err != nil {
 			return nil, err
 		}
@@ -129,7 +134,15 @@ func (ca *changeAggregator) Start(ctx context.Context) context.Context {
 	ctx = ca.StartInternal(ctx, changeAggregatorProcName)
 
 	var err error
-	if ca.sink, err = getSink(ca.spec.Feed.SinkURI, ca.spec.Feed.Targets); err != nil {
+	if ca.encoder, err = getEncoder(ca.spec.Feed.Opts); err != nil {
+		ca.MoveToDraining(err)
+		ca.cancel()
+		return ctx
+	}
+	if ca.sink, err = getSink(
+		ctx, ca.spec.Feed.SinkURI, ca.flowCtx.EvalCtx.NodeID, ca.spec.Feed.Opts,
+		ca.spec.Feed.Targets, ca.flowCtx.Settings,
+	); err != nil {
 		// Early abort in the case that there is an error creating the sink.
 		ca.MoveToDraining(err)
 		ca.cancel()
@@ -150,6 +163,18 @@ func (ca *changeAggregator) Start(ctx context.Context) context.Context {
 			initialHighWater = watch.InitialResolved
 		}
 	}
+	sf := makeSpanFrontier(spans...)
+	for _, watch := range ca.spec.Watches {
+		sf.Forward(watch.Span, watch.InitialResolved)
+	}
+
+	// Let the (still empty) sink know that every row it's about to receive is
+	// above the initial high-water.
+	if err := ca.sink.Flush(ctx, sf.Frontier()); err != nil {
+		ca.MoveToDraining(err)
+		ca.cancel()
+		return ctx
+	}
 
 	// The job registry has a set of metrics used to monitor the various jobs it
 	// runs. They're all stored as the `metric.Struct` interface because of
@@ -171,7 +196,7 @@ func (ca *changeAggregator) Start(ctx context.Context) context.Context {
 		// descriptor through its `ModificationTime` before using it, so this
 		// validation function can't depend on anything that changes after a new
 		// `Version` of a table desc is written.
-		return validateChangefeedTable(ca.spec.Feed.Targets, desc)
+		return validateChangefeedTable(ca.spec.Feed, desc)
 	}, initialHighWater)
 	ca.tableHistUpdater = &tableHistoryUpdater{
 		settings: ca.flowCtx.Settings,
@@ -179,13 +204,13 @@ func (ca *changeAggregator) Start(ctx context.Context) context.Context {
 		targets:  ca.spec.Feed.Targets,
 		m:        tableHist,
 	}
-	rowsFn := kvsToRows(leaseMgr, tableHist, ca.spec.Feed, buf.Get)
+	rowsFn := kvsToRows(leaseMgr, tableHist, ca.flowCtx.NewEvalCtx(), ca.spec.Feed, buf.Get)
 
 	var knobs TestingKnobs
 	if cfKnobs, ok := ca.flowCtx.TestingKnobs().Changefeed.(*TestingKnobs); ok {
 		knobs = *cfKnobs
 	}
-	ca.tickFn = emitEntries(ca.spec.Feed, ca.sink, rowsFn, knobs)
+	ca.tickFn = emitEntries(ca.encoder, ca.sink, rowsFn, sf, knobs)
 
 	// Give errCh enough buffer both possible errors from supporting goroutines,
 	// but only the first one is ever used.
@@ -316,6 +341,8 @@ type changeFrontier struct {
 	// sf contains the current resolved timestamp high-water for the tracked
 	// span set.
 	sf *spanFrontier
+	// encoder is the Encoder to use for resolved timestamp serialization.
+	encoder Encoder
 	// sink is the Sink to write resolved timestamps to. Rows are never written
 	// by changeFrontier.
 	sink Sink
@@ -377,7 +404,10 @@ func newChangeFrontierProcessor(
 	// See comment in newChangeAggregatorProcessor for details on the use of canary
 	// sinks.
 	{
-		canarySink, err := getSink(spec.Feed.SinkURI, spec.Feed.Targets)
+		canarySink, err := getSink(
+			ctx, spec.Feed.SinkURI, flowCtx.EvalCtx.NodeID, spec.Feed.Opts, spec.Feed.Targets,
+			flowCtx.Settings,
+		)
 		if err != nil {
 			return nil, err
 		}
@@ -402,7 +432,14 @@ func (cf *changeFrontier) Start(ctx context.Context) context.Context {
 	ctx = cf.StartInternal(ctx, changeFrontierProcName)
 
 	var err error
-	if cf.sink, err = getSink(cf.spec.Feed.SinkURI, cf.spec.Feed.Targets); err != nil {
+	if cf.encoder, err = getEncoder(cf.spec.Feed.Opts); err != nil {
+		cf.MoveToDraining(err)
+		return ctx
+	}
+	if cf.sink, err = getSink(
+		ctx, cf.spec.Feed.SinkURI, cf.flowCtx.EvalCtx.NodeID, cf.spec.Feed.Opts,
+		cf.spec.Feed.Targets, cf.flowCtx.Settings,
+	); err != nil {
 		cf.MoveToDraining(err)
 		return ctx
 	}
@@ -516,7 +553,7 @@ func (cf *changeFrontier) noteResolvedSpan(d sqlbase.EncDatum) error {
 		}
 		cf.metrics.mu.Unlock()
 		if err := emitResolvedTimestamp(
-			cf.Ctx, cf.spec.Feed, cf.sink, cf.jobProgressedFn, cf.sf,
+			cf.Ctx, cf.spec.Feed, cf.encoder, cf.sink, cf.jobProgressedFn, cf.sf,
 		); err != nil {
 			return err
 		}
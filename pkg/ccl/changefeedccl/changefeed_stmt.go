@@ -41,11 +41,13 @@ type envelopeType string
 type formatType string
 
 const (
-	optCursor             = `cursor`
-	optEnvelope           = `envelope`
-	optFormat             = `format`
-	optResolvedTimestamps = `resolved`
-	optUpdatedTimestamps  = `updated`
+	optConfluentSchemaRegistry = `confluent_schema_registry`
+	optCursor                  = `cursor`
+	optEnvelope                = `envelope`
+	optFormat                  = `format`
+	optInitialScan             = `initial_scan`
+	optResolvedTimestamps      = `resolved`
+	optUpdatedTimestamps       = `updated`
 
 	optEnvelopeKeyOnly envelopeType = `key_only`
 	optEnvelopeRow     envelopeType = `row`
@@ -54,20 +56,34 @@ const (
 	optFormatJSON formatType = `json`
 	optFormatAvro formatType = `avro`
 
-	sinkParamConfluentSchemaRegistry = `confluent_schema_registry`
-	sinkParamTopicPrefix             = `topic_prefix`
-	sinkParamSchemaTopic             = `schema_topic`
-	sinkSchemeBuffer                 = ``
-	sinkSchemeExperimentalSQL        = `experimental-sql`
-	sinkSchemeKafka                  = `kafka`
+	sinkParamBatchSize              = `batch_size`
+	sinkParamFileSize               = `file_size`
+	sinkParamFlushFrequency         = `flush_frequency`
+	sinkParamTopicPrefix            = `topic_prefix`
+	sinkParamSchemaTopic            = `schema_topic`
+	sinkSchemeBuffer                = ``
+	sinkSchemeExperimentalPrefix    = `experimental-`
+	sinkSchemeExperimentalAzure     = sinkSchemeExperimentalPrefix + `azure`
+	sinkSchemeExperimentalGS        = sinkSchemeExperimentalPrefix + `gs`
+	sinkSchemeExperimentalHTTP      = sinkSchemeExperimentalPrefix + `http`
+	sinkSchemeExperimentalHTTPS     = sinkSchemeExperimentalPrefix + `https`
+	sinkSchemeExperimentalNodelocal = sinkSchemeExperimentalPrefix + `nodelocal`
+	sinkSchemeExperimentalS3        = sinkSchemeExperimentalPrefix + `s3`
+	sinkSchemeExperimentalSQL       = sinkSchemeExperimentalPrefix + `sql`
+	sinkSchemeKafka                 = `kafka`
+	sinkSchemeWebhookPrefix         = `webhook-`
+	sinkSchemeWebhookHTTP           = sinkSchemeWebhookPrefix + `http`
+	sinkSchemeWebhookHTTPS          = sinkSchemeWebhookPrefix + `https`
 )
 
 var changefeedOptionExpectValues = map[string]bool{
-	optCursor:             true,
-	optEnvelope:           true,
-	optFormat:             true,
-	optResolvedTimestamps: false,
-	optUpdatedTimestamps:  false,
+	optConfluentSchemaRegistry: true,
+	optCursor:                  true,
+	optEnvelope:                true,
+	optFormat:                  true,
+	optInitialScan:             false,
+	optResolvedTimestamps:      false,
+	optUpdatedTimestamps:       false,
 }
 
 // changefeedPlanHook implements sql.PlanHookFn.
@@ -151,6 +167,12 @@ func changefeedPlanHook(
 			}
 			statementTime = initialHighWater
 		}
+		if _, ok := opts[optInitialScan]; ok {
+			// The changefeed scans every existing row as of the statement time
+			// (which is the cursor, if there is one) whenever it starts with an
+			// empty high-water. This is always the case without a cursor.
+			initialHighWater = hlc.Timestamp{}
+		}
 
 		// For now, disallow targeting a database or wildcard table selection.
 		// Getting it right as tables enter and leave the set over time is
@@ -181,9 +203,6 @@ func changefeedPlanHook(
 				targets[tableDesc.ID] = jobspb.ChangefeedTarget{
 					StatementTimeName: tableDesc.Name,
 				}
-				if err := validateChangefeedTable(targets, tableDesc); err != nil {
-					return err
-				}
 			}
 		}
 
@@ -193,6 +212,16 @@ func changefeedPlanHook(
 			SinkURI:       sinkURI,
 			StatementTime: statementTime,
 		}
+		if changefeedStmt.Select != nil {
+			details.Select = tree.AsStringWithFlags(changefeedStmt.Select, tree.FmtParsable)
+		}
+		for _, desc := range targetDescs {
+			if tableDesc := desc.GetTable(); tableDesc != nil {
+				if err := validateChangefeedTable(details, tableDesc); err != nil {
+					return err
+				}
+			}
+		}
 		progress := jobspb.Progress{
 			Progress: &jobspb.Progress_HighWater{HighWater: &initialHighWater},
 			Details: &jobspb.Progress_Changefeed{
@@ -251,6 +280,7 @@ func changefeedJobDescription(
 ) string {
 	c := &tree.CreateChangefeed{
 		Targets: changefeed.Targets,
+		Select:  changefeed.Select,
 		// If/when we start accepting export storage uris (or ones with
 		// secrets), we'll need to sanitize sinkURI.
 		SinkURI: tree.NewDString(sinkURI),
@@ -280,8 +310,7 @@ func validateDetails(details jobspb.ChangefeedDetails) (jobspb.ChangefeedDetails
 	case optEnvelopeKeyOnly:
 		details.Opts[optEnvelope] = string(optEnvelopeKeyOnly)
 	case optEnvelopeDiff:
-		return jobspb.ChangefeedDetails{}, errors.Errorf(
-			`%s=%s is not yet supported`, optEnvelope, optEnvelopeDiff)
+		details.Opts[optEnvelope] = string(optEnvelopeDiff)
 	default:
 		return jobspb.ChangefeedDetails{}, errors.Errorf(
 			`unknown %s: %s`, optEnvelope, details.Opts[optEnvelope])
@@ -291,8 +320,19 @@ func validateDetails(details jobspb.ChangefeedDetails) (jobspb.ChangefeedDetails
 	case ``, optFormatJSON:
 		details.Opts[optFormat] = string(optFormatJSON)
 	case optFormatAvro:
-		return jobspb.ChangefeedDetails{}, errors.Errorf(
-			`%s=%s is not yet supported`, optFormat, optFormatAvro)
+		details.Opts[optFormat] = string(optFormatAvro)
+		if details.Opts[optConfluentSchemaRegistry] == `` {
+			return jobspb.ChangefeedDetails{}, errors.Errorf(
+				`%s=%s requires the %s option`, optFormat, optFormatAvro, optConfluentSchemaRegistry)
+		}
+		if details.Opts[optEnvelope] == string(optEnvelopeDiff) {
+			return jobspb.ChangefeedDetails{}, errors.Errorf(
+				`%s=%s is not supported with %s=%s`, optEnvelope, optEnvelopeDiff, optFormat, optFormatAvro)
+		}
+		if details.Select != `` {
+			return jobspb.ChangefeedDetails{}, errors.Errorf(
+				`CREATE CHANGEFEED ... AS SELECT is not supported with %s=%s`, optFormat, optFormatAvro)
+		}
 	default:
 		return jobspb.ChangefeedDetails{}, errors.Errorf(
 			`unknown %s: %s`, optFormat, details.Opts[optFormat])
@@ -302,9 +342,9 @@ func validateDetails(details jobspb.ChangefeedDetails) (jobspb.ChangefeedDetails
 }
 
 func validateChangefeedTable(
-	targets jobspb.ChangefeedTargets, tableDesc *sqlbase.TableDescriptor,
+	details jobspb.ChangefeedDetails, tableDesc *sqlbase.TableDescriptor,
 ) error {
-	t, ok := targets[tableDesc.ID]
+	t, ok := details.Targets[tableDesc.ID]
 	if !ok {
 		return errors.Errorf(`unwatched table: %s`, tableDesc.Name)
 	}
@@ -343,6 +383,26 @@ func validateChangefeedTable(
 		return errors.Errorf(`CHANGEFEEDs cannot operate on tables being backfilled`)
 	}
 
+	// Every version of the table must be representable in the requested
+	// format. This is checked as each new version is seen by the tableHistory,
+	// so a schema change that adds an unsupported column fails the changefeed
+	// at the timestamp of the schema change.
+	if formatType(details.Opts[optFormat]) == optFormatAvro {
+		if _, err := tableToAvroSchema(tableDesc); err != nil {
+			return err
+		}
+	}
+
+	// The same goes for the projection and filter of a `CREATE CHANGEFEED ...
+	// AS SELECT`, which must resolve and type check against every version of
+	// the table.
+	if details.Select != `` {
+		if _, err := makeChangefeedProjection(tableDesc, details.Select); err != nil {
+			return errors.Wrapf(err, `"%s" is incompatible with CHANGEFEED expression %s`,
+				tableDesc.Name, details.Select)
+		}
+	}
+
 	return nil
 }
 
@@ -369,7 +429,9 @@ func (b *changefeedResumer) Resume(
 		// progress high-water when creating a job (currently only the progress
 		// details can be set). I didn't want to pick off the refactor to get this
 		// fix in, but it'd be nice to remove this hack.
-		if _, ok := details.Opts[optCursor]; ok {
+		_, cursor := details.Opts[optCursor]
+		_, initialScan := details.Opts[optInitialScan]
+		if cursor && !initialScan {
 			if h := progress.GetHighWater(); h == nil || *h == (hlc.Timestamp{}) {
 				progress.Progress = &jobspb.Progress_HighWater{HighWater: &details.StatementTime}
 			}
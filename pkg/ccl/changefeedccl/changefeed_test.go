@@ -91,6 +91,30 @@ func TestChangefeedEnvelope(t *testing.T) {
 			defer foo.Close(t)
 			assertPayloads(t, foo, []string{`foo: [1]->`})
 		})
+		t.Run(`envelope=diff`, func(t *testing.T) {
+			foo := f.Feed(t, `CREATE CHANGEFEED FOR foo WITH envelope='diff'`)
+			defer foo.Close(t)
+			assertPayloads(t, foo, []string{
+				`foo: [1]->{"after": {"a": 1, "b": "a"}, "before": null}`,
+			})
+
+			sqlDB.Exec(t, `INSERT INTO foo VALUES (2, 'b')`)
+			assertPayloads(t, foo, []string{
+				`foo: [2]->{"after": {"a": 2, "b": "b"}, "before": null}`,
+			})
+			sqlDB.Exec(t, `UPDATE foo SET b = 'c' WHERE a = 2`)
+			assertPayloads(t, foo, []string{
+				`foo: [2]->{"after": {"a": 2, "b": "c"}, "before": {"a": 2, "b": "b"}}`,
+			})
+			sqlDB.Exec(t, `DELETE FROM foo WHERE a = 2`)
+			assertPayloads(t, foo, []string{
+				`foo: [2]->{"after": null, "before": {"a": 2, "b": "c"}}`,
+			})
+			sqlDB.Exec(t, `INSERT INTO foo VALUES (2, 'd')`)
+			assertPayloads(t, foo, []string{
+				`foo: [2]->{"after": {"a": 2, "b": "d"}, "before": null}`,
+			})
+		})
 	}
 
 	t.Run(`sinkless`, sinklessTest(testFn))
@@ -143,6 +167,82 @@ func TestChangefeedCursor(t *testing.T) {
 	t.Run(`enterprise`, enterpriseTest(testFn))
 }
 
+func TestChangefeedInitialScan(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testFn := func(t *testing.T, db *gosql.DB, f testfeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'before'), (2, 'before')`)
+		var ts string
+		sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&ts)
+		sqlDB.Exec(t, `UPDATE foo SET b = 'after' WHERE a = 2`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (3, 'after')`)
+
+		// With a cursor, the initial scan is as of the cursor and is followed by
+		// every change after it.
+		foo := f.Feed(t, `CREATE CHANGEFEED FOR foo WITH cursor=$1, initial_scan, resolved`, ts)
+		defer foo.Close(t)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"a": 1, "b": "before"}`,
+			`foo: [2]->{"a": 2, "b": "before"}`,
+		})
+		assertPayloads(t, foo, []string{
+			`foo: [2]->{"a": 2, "b": "after"}`,
+			`foo: [3]->{"a": 3, "b": "after"}`,
+		})
+		// Once the scan finishes, the feed resolves past the cursor.
+		expectResolvedTimestampGreaterThan(t, foo, ts)
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+}
+
+func TestChangefeedProjection(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testFn := func(t *testing.T, db *gosql.DB, f testfeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c INT)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a', 10), (2, 'b', 20), (3, NULL, 30)`)
+
+		foo := f.Feed(t, `CREATE CHANGEFEED AS SELECT b, c * 2 AS d FROM foo WHERE a > 1 AND c < 100`)
+		defer foo.Close(t)
+		assertPayloads(t, foo, []string{
+			`foo: [2]->{"b": "b", "d": 40}`,
+			`foo: [3]->{"b": null, "d": 60}`,
+		})
+		sqlDB.Exec(t, `UPSERT INTO foo VALUES (1, 'c', 100), (4, 'd', 40)`)
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 2`)
+		// Deletes are emitted for the rows which passed the filter.
+		assertPayloads(t, foo, []string{
+			`foo: [4]->{"b": "d", "d": 80}`,
+			`foo: [2]->`,
+		})
+		// A row which stops passing the filter is emitted as a deletion, and
+		// the deletion of a row which never passed it isn't emitted at all.
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 1`)
+		sqlDB.Exec(t, `UPDATE foo SET c = 200 WHERE a = 3`)
+		assertPayloads(t, foo, []string{
+			`foo: [3]->`,
+		})
+
+		// Renaming a column used by the expression breaks it.
+		sqlDB.Exec(t, `ALTER TABLE foo RENAME COLUMN c TO e`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (5, 'e', 50)`)
+		foo.Next(t)
+		if err := foo.Err(); !testutils.IsError(
+			err, `"foo" is incompatible with CHANGEFEED expression .* column "c" does not exist`,
+		) {
+			t.Errorf(`expected 'column "c" does not exist' error got: %+v`, err)
+		}
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+}
+
 func TestChangefeedTimestamps(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -797,8 +897,14 @@ func TestChangefeedErrors(t *testing.T) {
 
 		if _, err := sqlDB.DB.Exec(
 			`CREATE CHANGEFEED FOR foo WITH format=avro`,
-		); !testutils.IsError(err, `format=avro is not yet supported`) {
-			t.Errorf(`expected 'format=avro is not yet supported' error got: %+v`, err)
+		); !testutils.IsError(err, `format=avro requires the confluent_schema_registry option`) {
+			t.Errorf(`expected 'format=avro requires the confluent_schema_registry option' error got: %+v`, err)
+		}
+		sqlDB.Exec(t, `CREATE TABLE interval_col (a INT PRIMARY KEY, b INTERVAL)`)
+		if _, err := sqlDB.DB.Exec(
+			`CREATE CHANGEFEED FOR interval_col WITH format=avro, confluent_schema_registry='http://nope'`,
+		); !testutils.IsError(err, `column b: type INTERVAL not yet supported with avro`) {
+			t.Errorf(`expected 'column b: type INTERVAL not yet supported with avro' error got: %+v`, err)
 		}
 		if _, err := sqlDB.DB.Exec(
 			`CREATE CHANGEFEED FOR foo WITH format=nope`,
@@ -807,9 +913,34 @@ func TestChangefeedErrors(t *testing.T) {
 		}
 
 		if _, err := sqlDB.DB.Exec(
-			`CREATE CHANGEFEED FOR foo WITH envelope=diff`,
-		); !testutils.IsError(err, `envelope=diff is not yet supported`) {
-			t.Errorf(`expected 'envelope=diff is not yet supported' error got: %+v`, err)
+			`CREATE CHANGEFEED FOR foo WITH envelope=diff, format=avro, confluent_schema_registry='http://nope'`,
+		); !testutils.IsError(err, `envelope=diff is not supported with format=avro`) {
+			t.Errorf(`expected 'envelope=diff is not supported with format=avro' error got: %+v`, err)
+		}
+		if _, err := sqlDB.DB.Exec(
+			`CREATE CHANGEFEED WITH format=avro, confluent_schema_registry='http://nope' AS SELECT a FROM foo`,
+		); !testutils.IsError(err, `AS SELECT is not supported with format=avro`) {
+			t.Errorf(`expected 'AS SELECT is not supported with format=avro' error got: %+v`, err)
+		}
+		if _, err := sqlDB.DB.Exec(
+			`CREATE CHANGEFEED AS SELECT nope FROM foo`,
+		); !testutils.IsError(err, `column "nope" does not exist`) {
+			t.Errorf(`expected 'column "nope" does not exist' error got: %+v`, err)
+		}
+		if _, err := sqlDB.DB.Exec(
+			`CREATE CHANGEFEED AS SELECT a FROM foo WHERE b`,
+		); !testutils.IsError(err, `argument of WHERE must be type bool, not type string`) {
+			t.Errorf(`expected 'argument of WHERE must be type bool' error got: %+v`, err)
+		}
+		if _, err := sqlDB.DB.Exec(
+			`CREATE CHANGEFEED AS SELECT max(a) FROM foo`,
+		); !testutils.IsError(err, `aggregate functions are not allowed in CHANGEFEED expressions`) {
+			t.Errorf(`expected 'aggregate functions are not allowed' error got: %+v`, err)
+		}
+		if _, err := sqlDB.DB.Exec(
+			`CREATE CHANGEFEED AS SELECT a, now() FROM foo`,
+		); !testutils.IsError(err, `impure functions are not allowed in CHANGEFEED expressions`) {
+			t.Errorf(`expected 'impure functions are not allowed' error got: %+v`, err)
 		}
 		if _, err := sqlDB.DB.Exec(
 			`CREATE CHANGEFEED FOR foo WITH envelope=nope`,
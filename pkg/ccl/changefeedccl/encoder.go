@@ -0,0 +1,386 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bytes"
+	"encoding/binary"
+	gojson "encoding/json"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl/avroccl"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/httputil"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/pkg/errors"
+)
+
+// Encoder turns a row into a serialized changefeed key, value, or resolved
+// timestamp. It represents one of the `format=` changefeed options.
+type Encoder interface {
+	// EncodeKey encodes the primary key of the given row. The columns of the
+	// row are expected to match 1:1 with the `Columns` field of the
+	// `TableDescriptor`.
+	EncodeKey(emitRow) ([]byte, error)
+	// EncodeValue encodes the given row. The columns of the row are expected to
+	// match 1:1 with the `Columns` field of the `TableDescriptor`. A nil return
+	// with no error means that nothing should be emitted for the value.
+	EncodeValue(emitRow) ([]byte, error)
+	// EncodeResolvedTimestamp encodes a resolved timestamp payload.
+	EncodeResolvedTimestamp(hlc.Timestamp) ([]byte, error)
+}
+
+func getEncoder(opts map[string]string) (Encoder, error) {
+	switch formatType(opts[optFormat]) {
+	case ``, optFormatJSON:
+		return makeJSONEncoder(opts), nil
+	case optFormatAvro:
+		return newConfluentAvroEncoder(opts)
+	default:
+		return nil, errors.Errorf(`unknown %s: %s`, optFormat, opts[optFormat])
+	}
+}
+
+// jsonEncoder encodes changefeed entries as JSON. Keys are the primary key
+// columns in a JSON array. Values are a JSON object mapping every column name
+// to its value. Updated timestamps in rows and resolved timestamp payloads are
+// stored in a sub-object under the `__crdb__` key in the top-level JSON object.
+//
+// With `envelope=diff`, values are instead a JSON object with the row before
+// and after the change under the `before` and `after` keys. The row before an
+// insert and the row after a delete are null.
+type jsonEncoder struct {
+	updatedField, keyOnly, diff bool
+
+	keyBuf, valueBuf bytes.Buffer
+}
+
+var _ Encoder = &jsonEncoder{}
+
+func makeJSONEncoder(opts map[string]string) *jsonEncoder {
+	_, updatedField := opts[optUpdatedTimestamps]
+	return &jsonEncoder{
+		updatedField: updatedField,
+		keyOnly:      envelopeType(opts[optEnvelope]) == optEnvelopeKeyOnly,
+		diff:         envelopeType(opts[optEnvelope]) == optEnvelopeDiff,
+	}
+}
+
+// EncodeKey implements the Encoder interface.
+func (e *jsonEncoder) EncodeKey(row emitRow) ([]byte, error) {
+	colIdxByID := row.tableDesc.ColumnIdxMap()
+	jsonEntries := make([]interface{}, len(row.tableDesc.PrimaryIndex.ColumnIDs))
+	for i, colID := range row.tableDesc.PrimaryIndex.ColumnIDs {
+		idx, ok := colIdxByID[colID]
+		if !ok {
+			return nil, errors.Errorf(`unknown column id: %d`, colID)
+		}
+		var err error
+		jsonEntries[i], err = tree.AsJSON(row.datums[idx])
+		if err != nil {
+			return nil, err
+		}
+	}
+	j, err := json.MakeJSON(jsonEntries)
+	if err != nil {
+		return nil, err
+	}
+	e.keyBuf.Reset()
+	j.Format(&e.keyBuf)
+	return e.keyBuf.Bytes(), nil
+}
+
+// EncodeValue implements the Encoder interface.
+func (e *jsonEncoder) EncodeValue(row emitRow) ([]byte, error) {
+	if e.keyOnly || (row.deleted && !e.diff) {
+		return nil, nil
+	}
+
+	cols, datums, prevDatums := row.tableDesc.Columns, row.datums, row.prevDatums
+	if row.projection != nil {
+		cols, datums, prevDatums = row.projection.cols, row.projected, row.prevProjected
+	}
+
+	var jsonEntries map[string]interface{}
+	if e.diff {
+		// The before and after rows are stored as untyped nils when missing,
+		// so that they're encoded as JSON nulls.
+		jsonEntries = map[string]interface{}{`before`: nil, `after`: nil}
+		if prevDatums != nil {
+			before, err := e.rowAsJSON(cols, prevDatums)
+			if err != nil {
+				return nil, err
+			}
+			jsonEntries[`before`] = before
+		}
+		if !row.deleted {
+			after, err := e.rowAsJSON(cols, datums)
+			if err != nil {
+				return nil, err
+			}
+			jsonEntries[`after`] = after
+		}
+	} else {
+		var err error
+		if jsonEntries, err = e.rowAsJSON(cols, datums); err != nil {
+			return nil, err
+		}
+	}
+	if e.updatedField {
+		jsonEntries[jsonMetaSentinel] = map[string]interface{}{
+			`updated`: tree.TimestampToDecimal(row.timestamp).Decimal.String(),
+		}
+	}
+	j, err := json.MakeJSON(jsonEntries)
+	if err != nil {
+		return nil, err
+	}
+	e.valueBuf.Reset()
+	j.Format(&e.valueBuf)
+	return e.valueBuf.Bytes(), nil
+}
+
+// rowAsJSON returns a map from every column name to its value.
+func (e *jsonEncoder) rowAsJSON(
+	cols []sqlbase.ColumnDescriptor, datums tree.Datums,
+) (map[string]interface{}, error) {
+	jsonEntries := make(map[string]interface{}, len(datums)+1)
+	for i := range datums {
+		var err error
+		jsonEntries[cols[i].Name], err = tree.AsJSON(datums[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return jsonEntries, nil
+}
+
+// EncodeResolvedTimestamp implements the Encoder interface.
+func (e *jsonEncoder) EncodeResolvedTimestamp(resolved hlc.Timestamp) ([]byte, error) {
+	meta := map[string]interface{}{
+		jsonMetaSentinel: map[string]interface{}{
+			`resolved`: tree.TimestampToDecimal(resolved).Decimal.String(),
+		},
+	}
+	return gojson.Marshal(meta)
+}
+
+const (
+	// confluentAvroWireFormatMagic is the magic byte that begins every message
+	// in the Confluent Schema Registry wire format. It is followed by the
+	// 4-byte big-endian id of the registered schema and then the avro binary
+	// encoding of the datum.
+	confluentAvroWireFormatMagic = byte(0)
+
+	confluentSubjectSuffixKey   = `-key`
+	confluentSubjectSuffixValue = `-value`
+	// confluentSubjectResolved is the subject that the schema of resolved
+	// timestamp messages is registered under. Resolved timestamps are emitted
+	// to every topic, so they are not registered under any one of them.
+	confluentSubjectResolved = `__crdb__resolved` + confluentSubjectSuffixValue
+
+	confluentRegistryContentType = `application/vnd.schemaregistry.v1+json`
+	confluentRegistryTimeout     = 10 * time.Second
+)
+
+// confluentAvroEncoder encodes changefeed entries in Avro's binary format.
+// Keys are the primary key columns in a record. Values are all columns in a
+// record wrapped in an envelope record, which also carries the updated
+// timestamp (if requested). Resolved timestamps are an envelope record with
+// only the resolved field set.
+//
+// Every distinct schema (one per version of each table's descriptor) is
+// registered with a Confluent Schema Registry and the returned id is embedded
+// in each message, as is required by the Confluent wire format.
+type confluentAvroEncoder struct {
+	registryURL           string
+	updatedField, keyOnly bool
+	client                *http.Client
+
+	keyCache      map[tableIDAndVersion]confluentRegisteredKeySchema
+	valueCache    map[tableIDAndVersion]confluentRegisteredEnvelopeSchema
+	resolvedCache *confluentRegisteredEnvelopeSchema
+}
+
+var _ Encoder = &confluentAvroEncoder{}
+
+// tableIDAndVersion is a unique identifier for a version of a table
+// descriptor. Schema changes that do not add or remove columns still get a new
+// entry, which is harmless because the schema registry deduplicates identical
+// schemas and returns the same id.
+type tableIDAndVersion uint64
+
+func makeTableIDAndVersion(id sqlbase.ID, version sqlbase.DescriptorVersion) tableIDAndVersion {
+	return tableIDAndVersion(id)<<32 + tableIDAndVersion(version)
+}
+
+type confluentRegisteredKeySchema struct {
+	schema     *avroDataRecord
+	registryID int32
+}
+
+type confluentRegisteredEnvelopeSchema struct {
+	schema     *avroEnvelopeRecord
+	registryID int32
+}
+
+func newConfluentAvroEncoder(opts map[string]string) (*confluentAvroEncoder, error) {
+	registryURL := opts[optConfluentSchemaRegistry]
+	if registryURL == `` {
+		return nil, errors.Errorf(`%s=%s requires the %s option`,
+			optFormat, optFormatAvro, optConfluentSchemaRegistry)
+	}
+	if _, err := url.Parse(registryURL); err != nil {
+		return nil, errors.Wrapf(err, `parsing %s`, optConfluentSchemaRegistry)
+	}
+	_, updatedField := opts[optUpdatedTimestamps]
+	return &confluentAvroEncoder{
+		registryURL:  registryURL,
+		updatedField: updatedField,
+		keyOnly:      envelopeType(opts[optEnvelope]) == optEnvelopeKeyOnly,
+		client:       &http.Client{Timeout: confluentRegistryTimeout},
+		keyCache:     make(map[tableIDAndVersion]confluentRegisteredKeySchema),
+		valueCache:   make(map[tableIDAndVersion]confluentRegisteredEnvelopeSchema),
+	}, nil
+}
+
+// EncodeKey implements the Encoder interface.
+func (e *confluentAvroEncoder) EncodeKey(row emitRow) ([]byte, error) {
+	cacheKey := makeTableIDAndVersion(row.tableDesc.ID, row.tableDesc.Version)
+	registered, ok := e.keyCache[cacheKey]
+	if !ok {
+		var err error
+		registered.schema, err = indexToAvroSchema(row.tableDesc, &row.tableDesc.PrimaryIndex)
+		if err != nil {
+			return nil, err
+		}
+		subject := avroccl.SQLNameToAvroName(row.tableDesc.Name) + confluentSubjectSuffixKey
+		registered.registryID, err = e.register(&registered.schema.avroRecord, subject)
+		if err != nil {
+			return nil, err
+		}
+		e.keyCache[cacheKey] = registered
+	}
+	return registered.schema.BinaryFromRow(e.wireFormatHeader(registered.registryID), row.datums)
+}
+
+// EncodeValue implements the Encoder interface.
+func (e *confluentAvroEncoder) EncodeValue(row emitRow) ([]byte, error) {
+	if e.keyOnly {
+		return nil, nil
+	}
+
+	cacheKey := makeTableIDAndVersion(row.tableDesc.ID, row.tableDesc.Version)
+	registered, ok := e.valueCache[cacheKey]
+	if !ok {
+		afterDataSchema, err := tableToAvroSchema(row.tableDesc)
+		if err != nil {
+			return nil, err
+		}
+		opts := avroEnvelopeOpts{afterField: true, updatedField: e.updatedField}
+		registered.schema = envelopeToAvroSchema(row.tableDesc.Name, opts, afterDataSchema)
+		subject := avroccl.SQLNameToAvroName(row.tableDesc.Name) + confluentSubjectSuffixValue
+		registered.registryID, err = e.register(&registered.schema.avroRecord, subject)
+		if err != nil {
+			return nil, err
+		}
+		e.valueCache[cacheKey] = registered
+	}
+	var after tree.Datums
+	if !row.deleted {
+		after = row.datums
+	}
+	var updated string
+	if e.updatedField {
+		updated = tree.TimestampToDecimal(row.timestamp).Decimal.String()
+	}
+	return registered.schema.BinaryFromRow(
+		e.wireFormatHeader(registered.registryID), after, updated, `` /* resolved */)
+}
+
+// EncodeResolvedTimestamp implements the Encoder interface.
+func (e *confluentAvroEncoder) EncodeResolvedTimestamp(resolved hlc.Timestamp) ([]byte, error) {
+	if e.resolvedCache == nil {
+		registered := confluentRegisteredEnvelopeSchema{
+			schema: envelopeToAvroSchema(
+				jsonMetaSentinel, avroEnvelopeOpts{resolvedField: true}, nil /* after */),
+		}
+		var err error
+		registered.registryID, err = e.register(
+			&registered.schema.avroRecord, confluentSubjectResolved)
+		if err != nil {
+			return nil, err
+		}
+		e.resolvedCache = &registered
+	}
+	return e.resolvedCache.schema.BinaryFromRow(
+		e.wireFormatHeader(e.resolvedCache.registryID),
+		nil, /* after */
+		``,  /* updated */
+		tree.TimestampToDecimal(resolved).Decimal.String(),
+	)
+}
+
+func (e *confluentAvroEncoder) wireFormatHeader(registryID int32) []byte {
+	header := make([]byte, 5, 64)
+	header[0] = confluentAvroWireFormatMagic
+	binary.BigEndian.PutUint32(header[1:5], uint32(registryID))
+	return header
+}
+
+// register adds the schema to the schema registry under the given subject and
+// returns the id assigned by the registry. Registering the same schema under
+// the same subject more than once is idempotent.
+func (e *confluentAvroEncoder) register(schema *avroRecord, subject string) (int32, error) {
+	type confluentSchemaVersionRequest struct {
+		Schema string `json:"schema"`
+	}
+	type confluentSchemaVersionResponse struct {
+		ID int32 `json:"id"`
+	}
+
+	u, err := url.Parse(e.registryURL)
+	if err != nil {
+		return 0, err
+	}
+	u.Path = path.Join(u.Path, `subjects`, subject, `versions`)
+
+	body, err := gojson.Marshal(confluentSchemaVersionRequest{Schema: schema.String()})
+	if err != nil {
+		return 0, err
+	}
+	resp, err := e.client.Post(u.String(), confluentRegistryContentType, bytes.NewReader(body))
+	if err != nil {
+		// The registry being unreachable is likely transient, so let the
+		// changefeed retry loop handle it.
+		return 0, retryableSinkError{cause: errors.Wrapf(err, `contacting confluent schema registry`)}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var buf bytes.Buffer
+		_, _ = buf.ReadFrom(resp.Body)
+		err := errors.Errorf(`registering schema for %s with confluent schema registry: %s: %s`,
+			subject, resp.Status, buf.String())
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return 0, retryableSinkError{cause: err}
+		}
+		return 0, err
+	}
+	var res confluentSchemaVersionResponse
+	if err := gojson.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return 0, errors.Wrapf(err, `decoding confluent schema registry response (%s)`,
+			resp.Header.Get(httputil.ContentTypeHeader))
+	}
+	return res.ID, nil
+}
@@ -0,0 +1,411 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	gosql "database/sql"
+	"encoding/binary"
+	gojson "encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl/avroccl"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// testSchemaRegistry is an in-process stand-in for the subset of the Confluent
+// Schema Registry API used by confluentAvroEncoder.
+type testSchemaRegistry struct {
+	server *httptest.Server
+	mu     struct {
+		syncutil.Mutex
+		idAlloc  int32
+		schemas  map[int32]string
+		subjects map[string][]int32
+	}
+}
+
+func makeTestSchemaRegistry() *testSchemaRegistry {
+	r := &testSchemaRegistry{}
+	r.mu.schemas = make(map[int32]string)
+	r.mu.subjects = make(map[string][]int32)
+	r.server = httptest.NewServer(http.HandlerFunc(r.handle))
+	return r
+}
+
+// Close shuts down the registry's http server.
+func (r *testSchemaRegistry) Close() {
+	r.server.Close()
+}
+
+func (r *testSchemaRegistry) handle(w http.ResponseWriter, req *http.Request) {
+	// POST /subjects/<subject>/versions
+	parts := strings.Split(strings.Trim(req.URL.Path, `/`), `/`)
+	if req.Method != http.MethodPost || len(parts) != 3 ||
+		parts[0] != `subjects` || parts[2] != `versions` {
+		http.Error(w, `not found`, http.StatusNotFound)
+		return
+	}
+	subject := parts[1]
+	var body struct {
+		Schema string `json:"schema"`
+	}
+	if err := gojson.NewDecoder(req.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	var schema interface{}
+	if err := gojson.Unmarshal([]byte(body.Schema), &schema); err != nil {
+		http.Error(w, `invalid schema: `+err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	r.mu.Lock()
+	id := int32(-1)
+	for existingID, existing := range r.mu.schemas {
+		if existing == body.Schema {
+			id = existingID
+		}
+	}
+	if id == -1 {
+		r.mu.idAlloc++
+		id = r.mu.idAlloc
+		r.mu.schemas[id] = body.Schema
+	}
+	r.mu.subjects[subject] = append(r.mu.subjects[subject], id)
+	r.mu.Unlock()
+
+	w.Header().Set(`Content-Type`, confluentRegistryContentType)
+	fmt.Fprintf(w, `{"id":%d}`, id)
+}
+
+// Subjects returns the sorted names of every subject with a registered schema.
+func (r *testSchemaRegistry) Subjects() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var subjects []string
+	for subject := range r.mu.subjects {
+		subjects = append(subjects, subject)
+	}
+	sort.Strings(subjects)
+	return subjects
+}
+
+// encodedAvroToJSON decodes a message in the Confluent wire format using the
+// schema it references and returns it as avro's textual json encoding. This
+// intentionally doesn't reuse any of the encoding code so that it checks that
+// the registered schemas actually describe the bytes in the message.
+func (r *testSchemaRegistry) encodedAvroToJSON(b []byte) (string, error) {
+	if len(b) < 5 || b[0] != confluentAvroWireFormatMagic {
+		return ``, errors.Errorf(`not a confluent avro message: %x`, b)
+	}
+	id := int32(binary.BigEndian.Uint32(b[1:5]))
+	r.mu.Lock()
+	rawSchema, ok := r.mu.schemas[id]
+	r.mu.Unlock()
+	if !ok {
+		return ``, errors.Errorf(`unknown schema id: %d`, id)
+	}
+	var schema interface{}
+	if err := gojson.Unmarshal([]byte(rawSchema), &schema); err != nil {
+		return ``, err
+	}
+	native, rest, err := decodeAvroNative(schema, b[5:])
+	if err != nil {
+		return ``, err
+	}
+	if len(rest) > 0 {
+		return ``, errors.Errorf(`%d unexpected trailing bytes`, len(rest))
+	}
+	j, err := gojson.Marshal(native)
+	return string(j), err
+}
+
+// decodeAvroNative decodes avro binary data according to a parsed json schema,
+// producing values that marshal to avro's json encoding.
+func decodeAvroNative(schema interface{}, b []byte) (interface{}, []byte, error) {
+	switch s := schema.(type) {
+	case string:
+		switch s {
+		case `null`:
+			return nil, b, nil
+		case `boolean`:
+			if len(b) < 1 {
+				return nil, nil, errors.New(`short boolean`)
+			}
+			return b[0] != 0, b[1:], nil
+		case `int`, `long`:
+			return avroccl.ConsumeLong(b)
+		case `double`:
+			if len(b) < 8 {
+				return nil, nil, errors.New(`short double`)
+			}
+			return math.Float64frombits(binary.LittleEndian.Uint64(b)), b[8:], nil
+		case `string`:
+			s, rest, err := avroccl.ConsumeBytes(b)
+			return string(s), rest, err
+		case `bytes`:
+			s, rest, err := avroccl.ConsumeBytes(b)
+			return fmt.Sprintf(`%x`, s), rest, err
+		}
+		return nil, nil, errors.Errorf(`unknown type: %s`, s)
+	case []interface{}:
+		branch, rest, err := avroccl.ConsumeLong(b)
+		if err != nil {
+			return nil, nil, err
+		}
+		if branch < 0 || int(branch) >= len(s) {
+			return nil, nil, errors.Errorf(`invalid union branch %d`, branch)
+		}
+		value, rest, err := decodeAvroNative(s[branch], rest)
+		if err != nil || value == nil {
+			return nil, rest, err
+		}
+		return map[string]interface{}{avroTypeName(s[branch]): value}, rest, nil
+	case map[string]interface{}:
+		if s[`type`] != `record` {
+			return decodeAvroNative(s[`type`], b)
+		}
+		record := make(map[string]interface{})
+		for _, f := range s[`fields`].([]interface{}) {
+			field := f.(map[string]interface{})
+			var value interface{}
+			var err error
+			value, b, err = decodeAvroNative(field[`type`], b)
+			if err != nil {
+				return nil, nil, err
+			}
+			record[field[`name`].(string)] = value
+		}
+		return record, b, nil
+	}
+	return nil, nil, errors.Errorf(`unknown schema: %v`, schema)
+}
+
+func avroTypeName(schema interface{}) string {
+	if m, ok := schema.(map[string]interface{}); ok {
+		if m[`type`] == `record` {
+			return m[`name`].(string)
+		}
+		return avroTypeName(m[`type`]) + `.` + m[`logicalType`].(string)
+	}
+	return schema.(string)
+}
+
+func assertAvroPayloads(
+	t testing.TB, reg *testSchemaRegistry, f testfeed, expected []string,
+) {
+	t.Helper()
+
+	var actual []string
+	for len(actual) < len(expected) {
+		topic, _, key, value, resolved, ok := f.Next(t)
+		if !ok {
+			break
+		} else if resolved != nil {
+			continue
+		}
+		keyJSON, err := reg.encodedAvroToJSON(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		valueJSON, err := reg.encodedAvroToJSON(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual = append(actual, fmt.Sprintf(`%s: %s->%s`, topic, keyJSON, valueJSON))
+	}
+
+	sort.Strings(expected)
+	sort.Strings(actual)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("expected\n  %s\ngot\n  %s",
+			strings.Join(expected, "\n  "), strings.Join(actual, "\n  "))
+	}
+}
+
+func TestEncoders(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	tableDesc, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+	require.NoError(t, err)
+	row := emitRow{
+		datums:    tree.Datums{tree.NewDInt(1), tree.NewDString(`bar`)},
+		timestamp: hlc.Timestamp{WallTime: 1, Logical: 2},
+		tableDesc: tableDesc,
+	}
+	deleted := row
+	deleted.deleted = true
+	deleted.datums = tree.Datums{tree.NewDInt(1), tree.DNull}
+	deleted.prevDatums = row.datums
+	resolved := hlc.Timestamp{WallTime: 3, Logical: 4}
+
+	type encoded struct {
+		insert, delete, resolved string
+	}
+	tests := []struct {
+		opts     map[string]string
+		expected encoded
+	}{
+		{
+			opts: map[string]string{optEnvelope: string(optEnvelopeRow)},
+			expected: encoded{
+				insert:   `[1]->{"a": 1, "b": "bar"}`,
+				delete:   `[1]->`,
+				resolved: `{"__crdb__":{"resolved":"3.0000000004"}}`,
+			},
+		},
+		{
+			opts: map[string]string{optEnvelope: string(optEnvelopeKeyOnly)},
+			expected: encoded{
+				insert:   `[1]->`,
+				delete:   `[1]->`,
+				resolved: `{"__crdb__":{"resolved":"3.0000000004"}}`,
+			},
+		},
+		{
+			opts: map[string]string{optEnvelope: string(optEnvelopeRow), optUpdatedTimestamps: ``},
+			expected: encoded{
+				insert:   `[1]->{"__crdb__": {"updated": "1.0000000002"}, "a": 1, "b": "bar"}`,
+				delete:   `[1]->`,
+				resolved: `{"__crdb__":{"resolved":"3.0000000004"}}`,
+			},
+		},
+		{
+			opts: map[string]string{optEnvelope: string(optEnvelopeDiff)},
+			expected: encoded{
+				insert:   `[1]->{"after": {"a": 1, "b": "bar"}, "before": null}`,
+				delete:   `[1]->{"after": null, "before": {"a": 1, "b": "bar"}}`,
+				resolved: `{"__crdb__":{"resolved":"3.0000000004"}}`,
+			},
+		},
+		{
+			opts: map[string]string{optEnvelope: string(optEnvelopeDiff), optUpdatedTimestamps: ``},
+			expected: encoded{
+				insert: `[1]->{"__crdb__": {"updated": "1.0000000002"}, ` +
+					`"after": {"a": 1, "b": "bar"}, "before": null}`,
+				delete: `[1]->{"__crdb__": {"updated": "1.0000000002"}, ` +
+					`"after": null, "before": {"a": 1, "b": "bar"}}`,
+				resolved: `{"__crdb__":{"resolved":"3.0000000004"}}`,
+			},
+		},
+		{
+			opts: map[string]string{optEnvelope: string(optEnvelopeRow), optFormat: string(optFormatAvro)},
+			expected: encoded{
+				insert:   `{"a":{"long":1}}->{"after":{"foo":{"a":{"long":1},"b":{"string":"bar"}}}}`,
+				delete:   `{"a":{"long":1}}->{"after":null}`,
+				resolved: `{"resolved":{"string":"3.0000000004"}}`,
+			},
+		},
+		{
+			opts: map[string]string{
+				optEnvelope: string(optEnvelopeRow), optFormat: string(optFormatAvro),
+				optUpdatedTimestamps: ``,
+			},
+			expected: encoded{
+				insert: `{"a":{"long":1}}->{"after":{"foo":{"a":{"long":1},"b":{"string":"bar"}}},` +
+					`"updated":{"string":"1.0000000002"}}`,
+				delete:   `{"a":{"long":1}}->{"after":null,"updated":{"string":"1.0000000002"}}`,
+				resolved: `{"resolved":{"string":"3.0000000004"}}`,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprint(test.opts), func(t *testing.T) {
+			reg := makeTestSchemaRegistry()
+			defer reg.Close()
+			if test.opts[optFormat] == string(optFormatAvro) {
+				test.opts[optConfluentSchemaRegistry] = reg.server.URL
+			}
+			e, err := getEncoder(test.opts)
+			require.NoError(t, err)
+
+			toString := func(b []byte) string {
+				if test.opts[optFormat] != string(optFormatAvro) || b == nil {
+					return string(b)
+				}
+				s, err := reg.encodedAvroToJSON(b)
+				require.NoError(t, err)
+				return s
+			}
+			encodeRow := func(r emitRow) string {
+				key, err := e.EncodeKey(r)
+				require.NoError(t, err)
+				keyStr := toString(key)
+				value, err := e.EncodeValue(r)
+				require.NoError(t, err)
+				return keyStr + `->` + toString(value)
+			}
+			require.Equal(t, test.expected.insert, encodeRow(row))
+			require.Equal(t, test.expected.delete, encodeRow(deleted))
+			resolvedPayload, err := e.EncodeResolvedTimestamp(resolved)
+			require.NoError(t, err)
+			require.Equal(t, test.expected.resolved, toString(resolvedPayload))
+		})
+	}
+}
+
+func TestAvroEncoder(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testFn := func(t *testing.T, db *gosql.DB, f testfeedFactory) {
+		reg := makeTestSchemaRegistry()
+		defer reg.Close()
+
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'bar'), (2, NULL)`)
+
+		foo := f.Feed(t, `CREATE CHANGEFEED FOR foo WITH format=$1, confluent_schema_registry=$2`,
+			optFormatAvro, reg.server.URL)
+		defer foo.Close(t)
+		assertAvroPayloads(t, reg, foo, []string{
+			`foo: {"a":{"long":1}}->{"after":{"foo":{"a":{"long":1},"b":{"string":"bar"}}}}`,
+			`foo: {"a":{"long":2}}->{"after":{"foo":{"a":{"long":2},"b":null}}}`,
+		})
+
+		// Schema changes are picked up through the table history and register
+		// a new value schema.
+		sqlDB.Exec(t, `ALTER TABLE foo ADD COLUMN c INT`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (3, 'baz', 4)`)
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 1`)
+		assertAvroPayloads(t, reg, foo, []string{
+			`foo: {"a":{"long":3}}->{"after":{"foo":{"a":{"long":3},"b":{"string":"baz"},"c":{"long":4}}}}`,
+			`foo: {"a":{"long":1}}->{"after":null}`,
+		})
+		require.Equal(t, []string{`foo-key`, `foo-value`}, reg.Subjects())
+
+		// A column that can't be represented in avro fails the feed at the
+		// timestamp of the schema change.
+		sqlDB.Exec(t, `ALTER TABLE foo ADD COLUMN d INTERVAL`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (5, 'qux', 6, '1s')`)
+		if _, _, _, _, _, ok := foo.Next(t); ok {
+			t.Fatal(`unexpected row`)
+		}
+		if err := foo.Err(); !testutils.IsError(err, `type INTERVAL not yet supported with avro`) {
+			t.Fatalf(`expected "type INTERVAL not yet supported with avro" error got: %+v`, err)
+		}
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+}
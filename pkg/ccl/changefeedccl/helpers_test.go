@@ -62,11 +62,11 @@ func makeBenchSink() *benchSink {
 func (s *benchSink) EmitRow(ctx context.Context, _ string, k, v []byte) error {
 	return s.emit(int64(len(k) + len(v)))
 }
-func (s *benchSink) EmitResolvedTimestamp(_ context.Context, p []byte) error {
+func (s *benchSink) EmitResolvedTimestamp(_ context.Context, p []byte, _ hlc.Timestamp) error {
 	return s.emit(int64(len(p)))
 }
-func (s *benchSink) Flush(_ context.Context) error { return nil }
-func (s *benchSink) Close() error                  { return nil }
+func (s *benchSink) Flush(_ context.Context, _ hlc.Timestamp) error { return nil }
+func (s *benchSink) Close() error                                   { return nil }
 func (s *benchSink) emit(bytes int64) error {
 	s.Lock()
 	defer s.Unlock()
@@ -131,8 +131,10 @@ func createBenchmarkChangefeed(
 		targets:  details.Targets,
 		m:        th,
 	}
-	rowsFn := kvsToRows(s.LeaseManager().(*sql.LeaseManager), th, details, buf.Get)
-	tickFn := emitEntries(details, sink, rowsFn, TestingKnobs{})
+	evalCtx := tree.MakeTestingEvalContext(s.ClusterSettings())
+	rowsFn := kvsToRows(s.LeaseManager().(*sql.LeaseManager), th, &evalCtx, details, buf.Get)
+	encoder := makeJSONEncoder(details.Opts)
+	tickFn := emitEntries(encoder, sink, rowsFn, makeSpanFrontier(spans...), TestingKnobs{})
 
 	ctx, cancel := context.WithCancel(ctx)
 	go func() { _ = poller.Run(ctx) }()
@@ -156,7 +158,7 @@ func createBenchmarkChangefeed(
 				// of overhead here.
 				for _, rs := range resolvedSpans {
 					if sf.Forward(rs.Span, rs.Timestamp) {
-						if err := emitResolvedTimestamp(ctx, details, sink, nil, sf); err != nil {
+						if err := emitResolvedTimestamp(ctx, details, encoder, sink, nil, sf); err != nil {
 							return err
 						}
 					}
@@ -43,9 +43,11 @@ func (s *metricsSink) EmitRow(ctx context.Context, topic string, key, value []by
 	return err
 }
 
-func (s *metricsSink) EmitResolvedTimestamp(ctx context.Context, payload []byte) error {
+func (s *metricsSink) EmitResolvedTimestamp(
+	ctx context.Context, payload []byte, resolved hlc.Timestamp,
+) error {
 	start := timeutil.Now()
-	err := s.wrapped.EmitResolvedTimestamp(ctx, payload)
+	err := s.wrapped.EmitResolvedTimestamp(ctx, payload, resolved)
 	if err == nil {
 		s.metrics.EmittedMessages.Inc(1)
 		s.metrics.EmittedBytes.Inc(int64(len(payload)))
@@ -54,9 +56,9 @@ func (s *metricsSink) EmitResolvedTimestamp(ctx context.Context, payload []byte)
 	return err
 }
 
-func (s *metricsSink) Flush(ctx context.Context) error {
+func (s *metricsSink) Flush(ctx context.Context, ts hlc.Timestamp) error {
 	start := timeutil.Now()
-	err := s.wrapped.Flush(ctx)
+	err := s.wrapped.Flush(ctx, ts)
 	if err == nil {
 		s.metrics.Flushes.Inc(1)
 		s.metrics.FlushNanos.Inc(timeutil.Since(start).Nanoseconds())
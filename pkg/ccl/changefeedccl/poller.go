@@ -47,6 +47,8 @@ type poller struct {
 	spans    []roachpb.Span
 	details  jobspb.ChangefeedDetails
 	buf      *buffer
+	// withDiff is true if the previous value of each changed kv is needed.
+	withDiff bool
 
 	highWater hlc.Timestamp
 }
@@ -70,6 +72,7 @@ func makePoller(
 		spans:     spans,
 		details:   details,
 		buf:       buf,
+		withDiff:  needsPrevValues(details),
 	}
 }
 
@@ -217,7 +220,7 @@ func (p *poller) Run(ctx context.Context) error {
 				}
 				startTime = timeutil.Now()
 				for _, file := range res.(*roachpb.ExportResponse).Files {
-					if err := p.slurpSST(ctx, file.SST); err != nil {
+					if err := p.slurpSST(ctx, file.SST, p.highWater); err != nil {
 						return err
 					}
 				}
@@ -241,21 +244,14 @@ func (p *poller) Run(ctx context.Context) error {
 
 // slurpSST iterates an encoded sst and inserts the contained kvs into the
 // buffer.
-func (p *poller) slurpSST(ctx context.Context, sst []byte) error {
-	var previousKey roachpb.Key
+//
+// If the changefeed needs the previous value of each kv, the previous value of
+// each revision but the oldest one of a key is the revision before it. The
+// previous value of the oldest one is looked up as of prevTS, which must be the
+// (exclusive) start time of the sst. An empty prevTS means the sst is from an
+// initial scan, so there are no previous values.
+func (p *poller) slurpSST(ctx context.Context, sst []byte, prevTS hlc.Timestamp) error {
 	var kvs []roachpb.KeyValue
-	slurpKVs := func() error {
-		sort.Sort(byValueTimestamp(kvs))
-		for _, kv := range kvs {
-			if err := p.buf.AddKV(ctx, kv); err != nil {
-				return err
-			}
-		}
-		previousKey = previousKey[:0]
-		kvs = kvs[:0]
-		return nil
-	}
-
 	var scratch bufalloc.ByteAllocator
 	it, err := engineccl.NewMemSSTIterator(sst, false /* verify */)
 	if err != nil {
@@ -274,33 +270,91 @@ func (p *poller) slurpSST(ctx context.Context, sst []byte) error {
 		var value []byte
 		scratch, key = scratch.Copy(unsafeKey.Key, 0 /* extraCap */)
 		scratch, value = scratch.Copy(it.UnsafeValue(), 0 /* extraCap */)
-
-		// The buffer currently requires that each key's mvcc revisions are
-		// added in increasing timestamp order. The sst is guaranteed to be in
-		// key order, but decresing timestamp order. So, buffer up kvs until the
-		// key changes, then sort by increasing timestamp before handing them
-		// all to AddKV.
-		if !previousKey.Equal(key) {
-			if err := slurpKVs(); err != nil {
-				return err
-			}
-			previousKey = key
-		}
 		kvs = append(kvs, roachpb.KeyValue{
 			Key:   key,
 			Value: roachpb.Value{RawBytes: value, Timestamp: unsafeKey.Timestamp},
 		})
 	}
 
-	return slurpKVs()
+	// The buffer currently requires that each key's mvcc revisions are added in
+	// increasing timestamp order. The sst is guaranteed to be in key order, but
+	// decresing timestamp order. So, sort each key's revisions by increasing
+	// timestamp before handing them to AddKV.
+	for start := 0; start < len(kvs); {
+		end := start + 1
+		for end < len(kvs) && kvs[end].Key.Equal(kvs[start].Key) {
+			end++
+		}
+		sort.Sort(byValueTimestamp(kvs[start:end]))
+		start = end
+	}
+
+	var prevVals map[string]roachpb.Value
+	if p.withDiff && prevTS != (hlc.Timestamp{}) {
+		if prevVals, err = p.fetchPrevValues(ctx, kvs, prevTS); err != nil {
+			return err
+		}
+	}
+	for i, kv := range kvs {
+		var prevVal roachpb.Value
+		if p.withDiff {
+			if i > 0 && kvs[i-1].Key.Equal(kv.Key) {
+				prevVal = kvs[i-1].Value
+			} else {
+				prevVal = prevVals[string(kv.Key)]
+			}
+		}
+		if err := p.buf.AddKV(ctx, kv, prevVal); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchPrevValues returns the value as of ts of each distinct key in kvs. Keys
+// that don't exist as of ts are omitted.
+func (p *poller) fetchPrevValues(
+	ctx context.Context, kvs []roachpb.KeyValue, ts hlc.Timestamp,
+) (map[string]roachpb.Value, error) {
+	prevVals := make(map[string]roachpb.Value)
+	err := p.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		txn.SetFixedTimestamp(ctx, ts)
+		b := txn.NewBatch()
+		for i, kv := range kvs {
+			if i == 0 || !kvs[i-1].Key.Equal(kv.Key) {
+				b.Get(kv.Key)
+			}
+		}
+		if err := txn.Run(ctx, b); err != nil {
+			return err
+		}
+		for _, result := range b.Results {
+			for _, row := range result.Rows {
+				if row.Value != nil {
+					prevVals[string(row.Key)] = *row.Value
+				}
+			}
+		}
+		return nil
+	})
+	return prevVals, errors.Wrap(err, `fetching previous values`)
 }
 
 // TODO(nvanbenschoten): this should probably be a whole different type that
 // shares a common interface with poller.
 func (p *poller) runUsingRangefeeds(ctx context.Context) error {
-	g := ctxgroup.WithContext(ctx)
 	sender := p.db.NonTransactionalSender()
 	if p.highWater == (hlc.Timestamp{}) {
+		// The initial scan emits every row as of the statement time and then
+		// resolves each span at the statement time. It finishes before the
+		// rangefeeds (which start at the statement time, exclusive) are
+		// consumed, so that no span is resolved past the statement time while
+		// its scan is still running and no key's later revisions are buffered
+		// before its scanned value. If the flow restarts during the scan, the
+		// high-water is still empty and the scan starts over, which duplicates
+		// rows but none across a resolved timestamp, since nothing above the
+		// statement time has been resolved yet.
+		//
 		// TODO(nvanbenschoten/danhhz): This should be replaced by a series of
 		// ScanRequests and this structure should be completely reworked. Right
 		// now it's copied verbatim from above.
@@ -347,58 +401,61 @@ func (p *poller) runUsingRangefeeds(ctx context.Context) error {
 			requests = append(requests, roachpb.Span{Key: chunk.Start, EndKey: chunk.End})
 		}
 
-		g.GoCtx(func(ctx context.Context) error {
-			maxConcurrentExports := clusterNodeCount(p.gossip) *
-				int(storage.ExportRequestsLimit.Get(&p.settings.SV))
-			exportsSem := make(chan struct{}, maxConcurrentExports)
+		maxConcurrentExports := clusterNodeCount(p.gossip) *
+			int(storage.ExportRequestsLimit.Get(&p.settings.SV))
+		exportsSem := make(chan struct{}, maxConcurrentExports)
 
-			var atomicFinished int64
+		var atomicFinished int64
 
-			for _, span := range requests {
-				span := span
+		scanG := ctxgroup.WithContext(ctx)
+		for _, span := range requests {
+			span := span
 
-				select {
-				case <-ctx.Done():
-					return ctx.Err()
-				case exportsSem <- struct{}{}:
-				}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case exportsSem <- struct{}{}:
+			}
 
-				g.GoCtx(func(ctx context.Context) error {
-					defer func() { <-exportsSem }()
-					if log.V(2) {
-						log.Infof(ctx, `sending ExportRequest [%s,%s)`, span.Key, span.EndKey)
-					}
-					header := roachpb.Header{Timestamp: p.details.StatementTime}
-					req := &roachpb.ExportRequest{
-						RequestHeader: roachpb.RequestHeaderFromSpan(span),
-						StartTime:     hlc.Timestamp{},
-						MVCCFilter:    roachpb.MVCCFilter_Latest,
-						ReturnSST:     true,
-						OmitChecksum:  true,
-					}
-					startTime := timeutil.Now()
-					res, pErr := client.SendWrappedWith(ctx, sender, header, req)
-					finished := atomic.AddInt64(&atomicFinished, 1)
-					if log.V(2) {
-						log.Infof(ctx, `finished ExportRequest [%s,%s) %d of %d took %s`,
-							span.Key, span.EndKey, finished, len(requests), timeutil.Since(startTime))
-					}
-					if pErr != nil {
-						return errors.Wrapf(
-							pErr.GoError(), `fetching changes for [%s,%s)`, span.Key, span.EndKey)
-					}
-					for _, file := range res.(*roachpb.ExportResponse).Files {
-						if err := p.slurpSST(ctx, file.SST); err != nil {
-							return err
-						}
+			scanG.GoCtx(func(ctx context.Context) error {
+				defer func() { <-exportsSem }()
+				if log.V(2) {
+					log.Infof(ctx, `sending ExportRequest [%s,%s)`, span.Key, span.EndKey)
+				}
+				header := roachpb.Header{Timestamp: p.details.StatementTime}
+				req := &roachpb.ExportRequest{
+					RequestHeader: roachpb.RequestHeaderFromSpan(span),
+					StartTime:     hlc.Timestamp{},
+					MVCCFilter:    roachpb.MVCCFilter_Latest,
+					ReturnSST:     true,
+					OmitChecksum:  true,
+				}
+				startTime := timeutil.Now()
+				res, pErr := client.SendWrappedWith(ctx, sender, header, req)
+				finished := atomic.AddInt64(&atomicFinished, 1)
+				if log.V(2) {
+					log.Infof(ctx, `finished ExportRequest [%s,%s) %d of %d took %s`,
+						span.Key, span.EndKey, finished, len(requests), timeutil.Since(startTime))
+				}
+				if pErr != nil {
+					return errors.Wrapf(
+						pErr.GoError(), `fetching changes for [%s,%s)`, span.Key, span.EndKey)
+				}
+				for _, file := range res.(*roachpb.ExportResponse).Files {
+					if err := p.slurpSST(ctx, file.SST, hlc.Timestamp{}); err != nil {
+						return err
 					}
-					return p.buf.AddResolved(ctx, span, p.details.StatementTime)
-				})
-			}
-			return nil
-		})
+				}
+				return p.buf.AddResolved(ctx, span, p.details.StatementTime)
+			})
+		}
+		if err := scanG.Wait(); err != nil {
+			return err
+		}
 	}
 
+	g := ctxgroup.WithContext(ctx)
+
 	rangeFeedTS := p.details.StatementTime
 	if rangeFeedTS.Less(p.highWater) {
 		rangeFeedTS = p.highWater
@@ -412,7 +469,8 @@ func (p *poller) runUsingRangefeeds(ctx context.Context) error {
 			Header: roachpb.Header{
 				Timestamp: rangeFeedTS,
 			},
-			Span: span,
+			Span:     span,
+			WithDiff: p.withDiff,
 		}
 		g.GoCtx(func(ctx context.Context) error {
 			return ds.RangeFeed(ctx, req, eventC).GoError()
@@ -425,7 +483,7 @@ func (p *poller) runUsingRangefeeds(ctx context.Context) error {
 				switch t := e.GetValue().(type) {
 				case *roachpb.RangeFeedValue:
 					kv := roachpb.KeyValue{Key: t.Key, Value: t.Value}
-					if err := p.buf.AddKV(ctx, kv); err != nil {
+					if err := p.buf.AddKV(ctx, kv, t.PrevValue); err != nil {
 						return err
 					}
 				case *roachpb.RangeFeedCheckpoint:
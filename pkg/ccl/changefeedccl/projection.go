@@ -0,0 +1,269 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/pkg/errors"
+)
+
+// changefeedProjection is the projection and filter of a `CREATE CHANGEFEED
+// ... AS SELECT` statement, type checked against one version of the watched
+// table. Since the expression is type checked against each version of the
+// table seen by the changefeed, a schema change that breaks it (for example,
+// by dropping a referenced column) fails the changefeed at the timestamp of the
+// schema change.
+type changefeedProjection struct {
+	tableDesc *sqlbase.TableDescriptor
+
+	// cols describes the projected columns. Only `Name` and `Type` are set.
+	cols []sqlbase.ColumnDescriptor
+	// exprs has one expression per projected column.
+	exprs []tree.TypedExpr
+	// filter is the WHERE clause, or nil if there isn't one.
+	filter tree.TypedExpr
+
+	// curRow is the row being evaluated, as decoded by the RowFetcher for
+	// tableDesc.
+	curRow tree.Datums
+}
+
+var _ tree.IndexedVarContainer = &changefeedProjection{}
+
+// selectHasFilter returns whether the `SELECT` of a `CREATE CHANGEFEED ... AS
+// SELECT` has a WHERE clause.
+func selectHasFilter(sel string) bool {
+	if sel == `` {
+		return false
+	}
+	stmt, err := parser.ParseOne(sel)
+	if err != nil {
+		// The statement was checked when the changefeed was created.
+		return false
+	}
+	if s, ok := stmt.(*tree.Select); ok {
+		if clause, ok := s.Select.(*tree.SelectClause); ok {
+			return clause.Where != nil
+		}
+	}
+	return false
+}
+
+// makeChangefeedProjection parses and type checks the `SELECT` of a `CREATE
+// CHANGEFEED ... AS SELECT` against the given version of the watched table.
+func makeChangefeedProjection(
+	tableDesc *sqlbase.TableDescriptor, sel string,
+) (*changefeedProjection, error) {
+	stmt, err := parser.ParseOne(sel)
+	if err != nil {
+		return nil, err
+	}
+	var clause *tree.SelectClause
+	if s, ok := stmt.(*tree.Select); ok {
+		clause, _ = s.Select.(*tree.SelectClause)
+	}
+	if clause == nil || clause.From == nil || len(clause.From.Tables) != 1 {
+		return nil, errors.Errorf(`unsupported CHANGEFEED expression: %s`, sel)
+	}
+	from, ok := clause.From.Tables[0].(*tree.AliasedTableExpr)
+	if !ok {
+		return nil, errors.Errorf(`unsupported CHANGEFEED expression: %s`, sel)
+	}
+	ntn, ok := from.Expr.(*tree.NormalizableTableName)
+	if !ok {
+		return nil, errors.Errorf(`unsupported CHANGEFEED expression: %s`, sel)
+	}
+	tn, err := ntn.Normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	p := &changefeedProjection{
+		tableDesc: tableDesc,
+		curRow:    make(tree.Datums, len(tableDesc.Columns)),
+	}
+	sources := sqlbase.MakeMultiSourceInfo(sqlbase.NewSourceInfoForSingleTable(
+		*tn, sqlbase.ResultColumnsFromColDescs(tableDesc.Columns),
+	))
+	ivarHelper := tree.MakeIndexedVarHelper(p, len(tableDesc.Columns))
+	searchPath := sessiondata.MakeSearchPath(nil)
+	semaCtx := tree.MakeSemaContext(false /* privileged */)
+	semaCtx.IVarContainer = p
+	analyzeExpr := func(
+		_ context.Context,
+		raw tree.Expr,
+		sources sqlbase.MultiSourceInfo,
+		ivarHelper tree.IndexedVarHelper,
+		expectedType types.T,
+		requireType bool,
+		typingContext string,
+	) (tree.TypedExpr, error) {
+		expr, _, _, err := sqlbase.ResolveNames(raw, sources, ivarHelper, searchPath)
+		if err != nil {
+			return nil, err
+		}
+		// The expressions are evaluated by the changefeed's processors, outside
+		// of any transaction, so functions which depend on the transaction or
+		// the statement (like now()) are rejected along with the rest of the
+		// impure functions.
+		semaCtx.Properties.Require(`CHANGEFEED expressions`,
+			tree.RejectSpecial|tree.RejectSubqueries|tree.RejectImpureFunctions)
+		if requireType {
+			return tree.TypeCheckAndRequire(expr, &semaCtx, expectedType, typingContext)
+		}
+		return tree.TypeCheck(expr, &semaCtx, expectedType)
+	}
+
+	for _, target := range clause.Exprs {
+		isStar, cols, exprs, err := sqlbase.CheckRenderStar(
+			context.TODO(), analyzeExpr, target, sources, ivarHelper)
+		if err != nil {
+			return nil, err
+		}
+		if isStar {
+			for i := range cols {
+				if err := p.addColumn(cols[i].Name, exprs[i]); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		name, err := tree.GetRenderColName(searchPath, target)
+		if err != nil {
+			return nil, err
+		}
+		expr, err := analyzeExpr(
+			context.TODO(), target.Expr, sources, ivarHelper, types.Any, false, "")
+		if err != nil {
+			return nil, err
+		}
+		if err := p.addColumn(name, expr); err != nil {
+			return nil, err
+		}
+	}
+
+	if clause.Where != nil {
+		p.filter, err = analyzeExpr(
+			context.TODO(), clause.Where.Expr, sources, ivarHelper, types.Bool, true, "WHERE")
+		if err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+func (p *changefeedProjection) addColumn(name string, expr tree.TypedExpr) error {
+	colType, err := sqlbase.DatumTypeToColumnType(expr.ResolvedType())
+	if err != nil {
+		return err
+	}
+	p.cols = append(p.cols, sqlbase.ColumnDescriptor{Name: name, Type: colType})
+	p.exprs = append(p.exprs, expr)
+	return nil
+}
+
+// IndexedVarEval implements the tree.IndexedVarContainer interface.
+func (p *changefeedProjection) IndexedVarEval(
+	idx int, ctx *tree.EvalContext,
+) (tree.Datum, error) {
+	return p.curRow[idx].Eval(ctx)
+}
+
+// IndexedVarResolvedType implements the tree.IndexedVarContainer interface.
+func (p *changefeedProjection) IndexedVarResolvedType(idx int) types.T {
+	return p.tableDesc.Columns[idx].Type.ToDatumType()
+}
+
+// IndexedVarNodeFormatter implements the tree.IndexedVarContainer interface.
+func (p *changefeedProjection) IndexedVarNodeFormatter(idx int) tree.NodeFormatter {
+	n := tree.Name(p.tableDesc.Columns[idx].Name)
+	return &n
+}
+
+// HasFilter returns whether the projection has a WHERE clause.
+func (p *changefeedProjection) HasFilter() bool {
+	return p.filter != nil
+}
+
+// Matches returns whether the given row, which must have been decoded with
+// tableDesc, passes the filter.
+func (p *changefeedProjection) Matches(evalCtx *tree.EvalContext, datums tree.Datums) (bool, error) {
+	if p.filter == nil {
+		return true, nil
+	}
+	copy(p.curRow, datums)
+	d, err := p.filter.Eval(evalCtx)
+	if err != nil {
+		return false, err
+	}
+	return d == tree.DBoolTrue, nil
+}
+
+// Project returns the projection of the given row, which must have been decoded
+// with tableDesc.
+func (p *changefeedProjection) Project(
+	evalCtx *tree.EvalContext, datums tree.Datums,
+) (tree.Datums, error) {
+	copy(p.curRow, datums)
+	projected := make(tree.Datums, len(p.exprs))
+	for i, expr := range p.exprs {
+		var err error
+		if projected[i], err = expr.Eval(evalCtx); err != nil {
+			return nil, err
+		}
+	}
+	return projected, nil
+}
+
+// projectionCache maintains a cache of changefeedProjections, one per version
+// of the watched tables. Since rows are mostly seen in timestamp order, the
+// projections for the older versions of a table are evicted once a newer
+// version is seen. They are rebuilt in the rare case they're needed again.
+type projectionCache struct {
+	sel         string
+	projections map[idVersion]*changefeedProjection
+}
+
+type idVersion struct {
+	id      sqlbase.ID
+	version sqlbase.DescriptorVersion
+}
+
+func newProjectionCache(sel string) *projectionCache {
+	return &projectionCache{
+		sel:         sel,
+		projections: make(map[idVersion]*changefeedProjection),
+	}
+}
+
+func (c *projectionCache) ProjectionForTableDesc(
+	tableDesc *sqlbase.TableDescriptor,
+) (*changefeedProjection, error) {
+	key := idVersion{id: tableDesc.ID, version: tableDesc.Version}
+	if p, ok := c.projections[key]; ok {
+		return p, nil
+	}
+	p, err := makeChangefeedProjection(tableDesc, c.sel)
+	if err != nil {
+		return nil, err
+	}
+	for k := range c.projections {
+		if k.id == key.id && k.version < key.version {
+			delete(c.projections, k)
+		}
+	}
+	c.projections[key] = p
+	return p, nil
+}
@@ -17,13 +17,17 @@ import (
 	"net/url"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/Shopify/sarama"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/pkg/errors"
@@ -36,19 +40,28 @@ type Sink interface {
 	EmitRow(ctx context.Context, topic string, key, value []byte) error
 	// EmitResolvedTimestamp enqueues a resolved timestamp message for
 	// asynchronous delivery on every partition of every topic that has been
-	// seen by EmitRow. The list of partitions used may be stale. An error may
-	// be returned if a previously enqueued message has failed.
-	EmitResolvedTimestamp(ctx context.Context, payload []byte) error
+	// seen by EmitRow. The list of partitions used may be stale. The payload is
+	// the encoded form of the resolved timestamp. An error may be returned if a
+	// previously enqueued message has failed.
+	EmitResolvedTimestamp(ctx context.Context, payload []byte, resolved hlc.Timestamp) error
 	// Flush blocks until every message enqueued by EmitRow and
 	// EmitResolvedTimestamp has been acknowledged by the sink. If an error is
 	// returned, no guarantees are given about which messages have been
-	// delivered or not delivered.
-	Flush(ctx context.Context) error
+	// delivered or not delivered. Every row emitted after Flush returns is
+	// guaranteed to have an updated timestamp greater than ts.
+	Flush(ctx context.Context, ts hlc.Timestamp) error
 	// Close does not guarantee delivery of outstanding messages.
 	Close() error
 }
 
-func getSink(sinkURI string, targets jobspb.ChangefeedTargets) (Sink, error) {
+func getSink(
+	ctx context.Context,
+	sinkURI string,
+	nodeID roachpb.NodeID,
+	opts map[string]string,
+	targets jobspb.ChangefeedTargets,
+	settings *cluster.Settings,
+) (Sink, error) {
 	u, err := url.Parse(sinkURI)
 	if err != nil {
 		return nil, err
@@ -67,11 +80,6 @@ func getSink(sinkURI string, targets jobspb.ChangefeedTargets) (Sink, error) {
 		if schemaTopic != `` {
 			return nil, errors.Errorf(`%s is not yet supported`, sinkParamSchemaTopic)
 		}
-		confluentSchemaRegistry := q.Get(sinkParamConfluentSchemaRegistry)
-		q.Del(sinkParamConfluentSchemaRegistry)
-		if confluentSchemaRegistry != `` {
-			return nil, errors.Errorf(`%s is not yet supported`, sinkParamConfluentSchemaRegistry)
-		}
 		s, err = getKafkaSink(kafkaTopicPrefix, u.Host, targets)
 		if err != nil {
 			return nil, err
@@ -92,6 +100,50 @@ func getSink(sinkURI string, targets jobspb.ChangefeedTargets) (Sink, error) {
 		q.Del(`sslkey`)
 		q.Del(`sslmode`)
 		q.Del(`sslrootcert`)
+	case sinkSchemeExperimentalS3, sinkSchemeExperimentalGS, sinkSchemeExperimentalAzure,
+		sinkSchemeExperimentalNodelocal, sinkSchemeExperimentalHTTP, sinkSchemeExperimentalHTTPS:
+		fileSize := int64(cloudStorageSinkDefaultFileSize)
+		if sizeParam := q.Get(sinkParamFileSize); sizeParam != `` {
+			if fileSize, err = humanizeutil.ParseBytes(sizeParam); err != nil {
+				return nil, errors.Wrapf(err, `parsing %s`, sinkParamFileSize)
+			}
+		}
+		q.Del(sinkParamFileSize)
+		// The rest of the parameters are for the underlying ExportStorage, so
+		// strip the experimental prefix from the scheme and hand them off.
+		u.Scheme = strings.TrimPrefix(u.Scheme, sinkSchemeExperimentalPrefix)
+		u.RawQuery = q.Encode()
+		s, err = makeCloudStorageSink(ctx, u.String(), nodeID, fileSize, settings, opts)
+		if err != nil {
+			return nil, err
+		}
+		// Remove parameters we know about for the unknown parameter check.
+		for _, p := range cloudStorageSinkParams {
+			q.Del(p)
+		}
+	case sinkSchemeWebhookHTTP, sinkSchemeWebhookHTTPS:
+		batchSize := int64(webhookSinkDefaultBatchSize)
+		if sizeParam := q.Get(sinkParamBatchSize); sizeParam != `` {
+			if batchSize, err = humanizeutil.ParseBytes(sizeParam); err != nil {
+				return nil, errors.Wrapf(err, `parsing %s`, sinkParamBatchSize)
+			}
+		}
+		q.Del(sinkParamBatchSize)
+		flushFrequency := webhookSinkDefaultFlushFrequency
+		if freqParam := q.Get(sinkParamFlushFrequency); freqParam != `` {
+			if flushFrequency, err = time.ParseDuration(freqParam); err != nil {
+				return nil, errors.Wrapf(err, `parsing %s`, sinkParamFlushFrequency)
+			}
+		}
+		q.Del(sinkParamFlushFrequency)
+		// Every other parameter is rejected below, so none of them are passed
+		// along to the endpoint.
+		u.Scheme = strings.TrimPrefix(u.Scheme, sinkSchemeWebhookPrefix)
+		u.RawQuery = ``
+		s, err = makeWebhookSink(u.String(), int(batchSize), flushFrequency, opts, targets)
+		if err != nil {
+			return nil, err
+		}
 	default:
 		return nil, errors.Errorf(`unsupported sink: %s`, u.Scheme)
 	}
@@ -224,7 +276,9 @@ func (s *kafkaSink) EmitRow(ctx context.Context, tableName string, key, value []
 }
 
 // EmitResolvedTimestamp implements the Sink interface.
-func (s *kafkaSink) EmitResolvedTimestamp(ctx context.Context, payload []byte) error {
+func (s *kafkaSink) EmitResolvedTimestamp(
+	ctx context.Context, payload []byte, _ hlc.Timestamp,
+) error {
 	// Staleness here does not impact correctness. Some new partitions will miss
 	// this resolved timestamp, but they'll eventually be picked up and get
 	// later ones.
@@ -251,7 +305,7 @@ func (s *kafkaSink) EmitResolvedTimestamp(ctx context.Context, payload []byte) e
 }
 
 // Flush implements the Sink interface.
-func (s *kafkaSink) Flush(ctx context.Context) error {
+func (s *kafkaSink) Flush(ctx context.Context, _ hlc.Timestamp) error {
 	flushCh := make(chan struct{}, 1)
 
 	s.mu.Lock()
@@ -437,7 +491,9 @@ func (s *sqlSink) EmitRow(ctx context.Context, topic string, key, value []byte)
 }
 
 // EmitResolvedTimestamp implements the Sink interface.
-func (s *sqlSink) EmitResolvedTimestamp(ctx context.Context, payload []byte) error {
+func (s *sqlSink) EmitResolvedTimestamp(
+	ctx context.Context, payload []byte, _ hlc.Timestamp,
+) error {
 	var noKey, noValue []byte
 	for topic := range s.topics {
 		for partition := int32(0); partition < sqlSinkNumPartitions; partition++ {
@@ -458,13 +514,17 @@ func (s *sqlSink) emit(
 	messageID := builtins.GenerateUniqueInt(roachpb.NodeID(partition))
 	s.rowBuf = append(s.rowBuf, topic, partition, messageID, key, value, resolved)
 	if len(s.rowBuf)/sqlSinkEmitCols >= sqlSinkRowBatchSize {
-		return s.Flush(ctx)
+		return s.flush(ctx)
 	}
 	return nil
 }
 
 // Flush implements the Sink interface.
-func (s *sqlSink) Flush(ctx context.Context) error {
+func (s *sqlSink) Flush(ctx context.Context, _ hlc.Timestamp) error {
+	return s.flush(ctx)
+}
+
+func (s *sqlSink) flush(ctx context.Context) error {
 	if len(s.rowBuf) == 0 {
 		return nil
 	}
@@ -534,7 +594,9 @@ func (s *bufferSink) EmitRow(_ context.Context, topic string, key, value []byte)
 }
 
 // EmitResolvedTimestamp implements the Sink interface.
-func (s *bufferSink) EmitResolvedTimestamp(_ context.Context, payload []byte) error {
+func (s *bufferSink) EmitResolvedTimestamp(
+	_ context.Context, payload []byte, _ hlc.Timestamp,
+) error {
 	if s.closed {
 		return errors.New(`cannot EmitRow on a closed sink`)
 	}
@@ -548,7 +610,7 @@ func (s *bufferSink) EmitResolvedTimestamp(_ context.Context, payload []byte) er
 }
 
 // Flush implements the Sink interface.
-func (s *bufferSink) Flush(_ context.Context) error {
+func (s *bufferSink) Flush(_ context.Context, _ hlc.Timestamp) error {
 	return nil
 }
 
@@ -0,0 +1,221 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/pkg/errors"
+)
+
+const (
+	// cloudStorageSinkDefaultFileSize is the size at which a buffered data file
+	// is written out, even if the sink has not been flushed.
+	cloudStorageSinkDefaultFileSize = 16 << 20
+
+	cloudStorageDataFileExt     = `.ndjson`
+	cloudStorageResolvedFileExt = `.RESOLVED`
+)
+
+// cloudStorageSinkParams are the query parameters consumed by the
+// ExportStorage underlying a cloudStorageSink.
+var cloudStorageSinkParams = []string{
+	storageccl.AuthParam,
+	storageccl.AzureAccountKeyParam,
+	storageccl.AzureAccountNameParam,
+	storageccl.GoogleBillingProjectParam,
+	storageccl.S3AccessKeyParam,
+	storageccl.S3EndpointParam,
+	storageccl.S3RegionParam,
+	storageccl.S3SecretParam,
+}
+
+// cloudStorageFormatTime formats a timestamp such that the lexical order of
+// the resulting strings matches the order of the timestamps.
+func cloudStorageFormatTime(ts hlc.Timestamp) string {
+	// TODO(dan): Consider partitioning the files into directories by date, so
+	// that it's easier to list a subset of them.
+	const f = `20060102150405`
+	t := ts.GoTime()
+	return fmt.Sprintf(`%s%09d%010d`, t.Format(f), t.Nanosecond(), ts.Logical)
+}
+
+// cloudStorageSink emits to newline-delimited JSON files in some cloud storage
+// (or anything else that has an ExportStorage implementation). It is not
+// concurrency-safe; all calls to Emit and Flush should be from the same
+// goroutine.
+//
+// Rows are buffered per topic and written out as data files named
+// `<timestamp>-<nodeID>-<sinkID>-<fileID>-<topic>.ndjson`. Each line in a data
+// file is the value of one message or, for messages without a value (such as
+// deletions), the key. Files are written when they reach the target size or
+// when the sink is flushed.
+//
+// Resolved timestamps are written as marker files named
+// `<timestamp>.RESOLVED`. Timestamps are formatted so that the lexical order of
+// the file names matches the timestamp order.
+//
+// The timestamp in a data file name is the one after the timestamp passed to
+// the most recent Flush, so it is a lower bound for every row in the file.
+// Because a changefeed always flushes every row at or below a timestamp before
+// resolving it, this gives readers two guarantees. First, every row at or
+// below the timestamp of a marker file is in a data file that sorts before it.
+// Second, every file written after a marker file sorts after it, so a reader
+// that has processed everything up to a marker file only needs to look at the
+// files that sort after it. As with every other sink, rows may be
+// duplicated.
+type cloudStorageSink struct {
+	es                storageccl.ExportStorage
+	targetMaxFileSize int64
+
+	// nodeID and sinkID make file names unique across the nodes running the
+	// changefeed and across restarts of it. fileID makes them unique (and
+	// ordered) within one sink.
+	nodeID roachpb.NodeID
+	sinkID int64
+	fileID int64
+
+	// dataFileTs is the formatted timestamp used to name the data files
+	// written until the next Flush.
+	dataFileTs string
+	files      map[string]*bytes.Buffer
+}
+
+var _ Sink = &cloudStorageSink{}
+
+func makeCloudStorageSink(
+	ctx context.Context,
+	baseURI string,
+	nodeID roachpb.NodeID,
+	targetMaxFileSize int64,
+	settings *cluster.Settings,
+	opts map[string]string,
+) (*cloudStorageSink, error) {
+	switch formatType(opts[optFormat]) {
+	case optFormatJSON:
+	default:
+		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
+			optFormat, opts[optFormat])
+	}
+
+	es, err := storageccl.ExportStorageFromURI(ctx, baseURI, settings)
+	if err != nil {
+		return nil, err
+	}
+	s := &cloudStorageSink{
+		es:                es,
+		targetMaxFileSize: targetMaxFileSize,
+		nodeID:            nodeID,
+		sinkID:            int64(builtins.GenerateUniqueInt(nodeID)),
+		dataFileTs:        cloudStorageFormatTime(hlc.Timestamp{}),
+		files:             make(map[string]*bytes.Buffer),
+	}
+	return s, nil
+}
+
+// EmitRow implements the Sink interface.
+func (s *cloudStorageSink) EmitRow(
+	ctx context.Context, topic string, key, value []byte,
+) error {
+	if s.files == nil {
+		return errors.New(`cannot EmitRow on a closed sink`)
+	}
+
+	file, ok := s.files[topic]
+	if !ok {
+		file = &bytes.Buffer{}
+		s.files[topic] = file
+	}
+
+	// A message without a value is a deletion (or the key_only envelope), so
+	// write the key instead. With the JSON format, keys are arrays and values
+	// are objects, so readers can tell them apart.
+	if len(value) == 0 {
+		value = key
+	}
+	file.Write(value)
+	file.WriteByte('\n')
+
+	if int64(file.Len()) > s.targetMaxFileSize {
+		if err := s.flushFile(ctx, topic, file); err != nil {
+			return err
+		}
+		delete(s.files, topic)
+	}
+	return nil
+}
+
+// EmitResolvedTimestamp implements the Sink interface.
+func (s *cloudStorageSink) EmitResolvedTimestamp(
+	ctx context.Context, payload []byte, resolved hlc.Timestamp,
+) error {
+	if s.files == nil {
+		return errors.New(`cannot EmitResolvedTimestamp on a closed sink`)
+	}
+
+	// Unlike the data files, the marker file name is not made unique. Every
+	// writer of a given resolved timestamp writes the same payload, so it's
+	// fine if one overwrites another.
+	name := cloudStorageFormatTime(resolved) + cloudStorageResolvedFileExt
+	if log.V(1) {
+		log.Infof(ctx, "writing resolved timestamp file %s", name)
+	}
+	content := append(append([]byte(nil), payload...), '\n')
+	if err := s.es.WriteFile(ctx, name, bytes.NewReader(content)); err != nil {
+		return retryableSinkError{cause: err}
+	}
+	return nil
+}
+
+// Flush implements the Sink interface.
+func (s *cloudStorageSink) Flush(ctx context.Context, ts hlc.Timestamp) error {
+	if s.files == nil {
+		return errors.New(`cannot Flush on a closed sink`)
+	}
+
+	for topic, file := range s.files {
+		if err := s.flushFile(ctx, topic, file); err != nil {
+			return err
+		}
+		delete(s.files, topic)
+	}
+	s.dataFileTs = cloudStorageFormatTime(ts.Next())
+	return nil
+}
+
+func (s *cloudStorageSink) flushFile(
+	ctx context.Context, topic string, file *bytes.Buffer,
+) error {
+	s.fileID++
+	name := fmt.Sprintf(`%s-%d-%d-%08d-%s%s`,
+		s.dataFileTs, s.nodeID, s.sinkID, s.fileID, url.PathEscape(topic),
+		cloudStorageDataFileExt)
+	if log.V(1) {
+		log.Infof(ctx, "writing data file %s with %d bytes", name, file.Len())
+	}
+	if err := s.es.WriteFile(ctx, name, bytes.NewReader(file.Bytes())); err != nil {
+		return retryableSinkError{cause: err}
+	}
+	return nil
+}
+
+// Close implements the Sink interface.
+func (s *cloudStorageSink) Close() error {
+	s.files = nil
+	return s.es.Close()
+}
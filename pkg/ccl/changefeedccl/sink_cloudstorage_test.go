@@ -0,0 +1,139 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloudStorageSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	dir, dirCleanupFn := testutils.TempDir(t)
+	defer dirCleanupFn()
+
+	// slurpDir returns the name and contents of every file in a subdirectory,
+	// in the order a reader listing them would see them.
+	slurpDir := func(t *testing.T, subdir string) []string {
+		t.Helper()
+		files, err := ioutil.ReadDir(filepath.Join(dir, subdir))
+		if os.IsNotExist(err) {
+			return nil
+		}
+		require.NoError(t, err)
+		var contents []string
+		for _, file := range files {
+			b, err := ioutil.ReadFile(filepath.Join(dir, subdir, file.Name()))
+			require.NoError(t, err)
+			contents = append(contents, file.Name()+`: `+string(b))
+		}
+		return contents
+	}
+
+	ts := func(i int64) hlc.Timestamp { return hlc.Timestamp{WallTime: i} }
+	settings := cluster.MakeTestingClusterSettings()
+	settings.ExternalIODir = dir
+	opts := map[string]string{optFormat: string(optFormatJSON)}
+	const unlimitedFileSize = 1 << 30
+
+	t.Run(`golden`, func(t *testing.T) {
+		s, err := makeCloudStorageSink(
+			ctx, `nodelocal:///golden`, 1, unlimitedFileSize, settings, opts)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, s.Close()) }()
+
+		require.NoError(t, s.Flush(ctx, ts(1)))
+		require.NoError(t, s.EmitRow(ctx, `foo`, []byte(`[1]`), []byte(`{"a":1}`)))
+		require.NoError(t, s.EmitRow(ctx, `foo`, []byte(`[2]`), nil))
+		require.Equal(t, []string(nil), slurpDir(t, `golden`))
+		require.NoError(t, s.Flush(ctx, ts(5)))
+		require.NoError(t, s.EmitResolvedTimestamp(ctx, []byte(`r5`), ts(5)))
+
+		require.Equal(t, []string{
+			fmt.Sprintf("197001010000000000000010000000001-1-%d-00000001-foo.ndjson: "+
+				"{\"a\":1}\n[2]\n", s.sinkID),
+			"197001010000000000000050000000000.RESOLVED: r5\n",
+		}, slurpDir(t, `golden`))
+	})
+
+	t.Run(`ordering`, func(t *testing.T) {
+		s, err := makeCloudStorageSink(
+			ctx, `nodelocal:///ordering`, 1, unlimitedFileSize, settings, opts)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, s.Close()) }()
+
+		require.NoError(t, s.Flush(ctx, ts(1)))
+		require.NoError(t, s.EmitRow(ctx, `foo`, []byte(`[1]`), []byte(`v1`)))
+		require.NoError(t, s.Flush(ctx, ts(2)))
+		require.NoError(t, s.EmitResolvedTimestamp(ctx, []byte(`r2`), ts(2)))
+		// A row above the resolved timestamp, which must sort after the marker,
+		// even though it's flushed at the same timestamp.
+		require.NoError(t, s.EmitRow(ctx, `foo`, []byte(`[1]`), []byte(`v2`)))
+		require.NoError(t, s.Flush(ctx, ts(2)))
+		require.NoError(t, s.EmitResolvedTimestamp(ctx, []byte(`r2`), ts(2)))
+
+		var order []string
+		for _, f := range slurpDir(t, `ordering`) {
+			order = append(order, f[len(f)-3:len(f)-1])
+		}
+		require.Equal(t, []string{`v1`, `r2`, `v2`}, order)
+	})
+
+	t.Run(`file size`, func(t *testing.T) {
+		s, err := makeCloudStorageSink(ctx, `nodelocal:///size`, 1, 3, settings, opts)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, s.Close()) }()
+
+		require.NoError(t, s.Flush(ctx, ts(1)))
+		require.NoError(t, s.EmitRow(ctx, `foo`, []byte(`[1]`), []byte(`v1`)))
+		// Nothing has hit the target size yet.
+		require.Equal(t, []string(nil), slurpDir(t, `size`))
+		require.NoError(t, s.EmitRow(ctx, `foo`, []byte(`[1]`), []byte(`v2`)))
+		require.Len(t, slurpDir(t, `size`), 1)
+		require.NoError(t, s.EmitRow(ctx, `foo`, []byte(`[1]`), []byte(`v3`)))
+		require.NoError(t, s.Flush(ctx, ts(2)))
+
+		prefix := fmt.Sprintf(`197001010000000000000010000000001-1-%d-`, s.sinkID)
+		require.Equal(t, []string{
+			prefix + "00000001-foo.ndjson: v1\nv2\n",
+			prefix + "00000002-foo.ndjson: v3\n",
+		}, slurpDir(t, `size`))
+	})
+
+	t.Run(`errors`, func(t *testing.T) {
+		_, err := makeCloudStorageSink(ctx, `nodelocal:///errors`, 1, unlimitedFileSize,
+			settings, map[string]string{optFormat: string(optFormatAvro)})
+		require.EqualError(t, err, `this sink is incompatible with format=avro`)
+
+		targets := jobspb.ChangefeedTargets{0: jobspb.ChangefeedTarget{StatementTimeName: `foo`}}
+		_, err = getSink(ctx, `experimental-nodelocal:///errors?nope=1`, 1, opts, targets, settings)
+		require.EqualError(t, err, `unknown sink query parameter: nope`)
+		_, err = getSink(ctx, `experimental-nodelocal:///errors?file_size=nope`, 1, opts, targets, settings)
+		require.Error(t, err)
+		s, err := getSink(ctx, `experimental-nodelocal:///errors?file_size=1KiB`, 1, opts, targets, settings)
+		require.NoError(t, err)
+		require.Equal(t, int64(1<<10), s.(*cloudStorageSink).targetMaxFileSize)
+		require.NoError(t, s.Close())
+
+		require.EqualError(t, s.EmitRow(ctx, `foo`, nil, nil), `cannot EmitRow on a closed sink`)
+	})
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/pkg/errors"
 )
@@ -66,7 +67,7 @@ func TestKafkaSink(t *testing.T) {
 	}()
 
 	// No inflight
-	if err := sink.Flush(ctx); err != nil {
+	if err := sink.Flush(ctx, hlc.Timestamp{}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -78,17 +79,17 @@ func TestKafkaSink(t *testing.T) {
 	for i := 0; i < 2; i++ {
 		timeoutCtx, cancel := context.WithTimeout(ctx, time.Millisecond)
 		defer cancel()
-		if err := sink.Flush(timeoutCtx); !testutils.IsError(err, `context deadline exceeded`) {
+		if err := sink.Flush(timeoutCtx, hlc.Timestamp{}); !testutils.IsError(err, `context deadline exceeded`) {
 			t.Fatalf(`expected "context deadline exceeded" error got: %+v`, err)
 		}
 	}
 	go func() { p.successesCh <- m1 }()
-	if err := sink.Flush(ctx); err != nil {
+	if err := sink.Flush(ctx, hlc.Timestamp{}); err != nil {
 		t.Fatal(err)
 	}
 
 	// Check no inflight again now that we've sent something
-	if err := sink.Flush(ctx); err != nil {
+	if err := sink.Flush(ctx, hlc.Timestamp{}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -113,7 +114,7 @@ func TestKafkaSink(t *testing.T) {
 		}
 	}()
 	go func() { p.successesCh <- m4 }()
-	if err := sink.Flush(ctx); !testutils.IsError(err, `m3`) {
+	if err := sink.Flush(ctx, hlc.Timestamp{}); !testutils.IsError(err, `m3`) {
 		t.Fatalf(`expected "m3" error got: %+v`, err)
 	}
 
@@ -123,7 +124,7 @@ func TestKafkaSink(t *testing.T) {
 	}
 	m5 := <-p.inputCh
 	go func() { p.successesCh <- m5 }()
-	if err := sink.Flush(ctx); err != nil {
+	if err := sink.Flush(ctx, hlc.Timestamp{}); err != nil {
 		t.Fatal(err)
 	}
 }
@@ -150,7 +151,7 @@ func TestSQLSink(t *testing.T) {
 	defer func() { require.NoError(t, sink.Close()) }()
 
 	// Empty
-	require.NoError(t, sink.Flush(ctx))
+	require.NoError(t, sink.Flush(ctx, hlc.Timestamp{}))
 
 	// Undeclared topic
 	require.EqualError(t, sink.EmitRow(ctx, `nope`, nil, nil), `cannot emit to undeclared topic: nope`)
@@ -160,7 +161,7 @@ func TestSQLSink(t *testing.T) {
 	sqlDB.CheckQueryResults(t, `SELECT key, value FROM sink ORDER BY PRIMARY KEY sink`,
 		[][]string{},
 	)
-	require.NoError(t, sink.Flush(ctx))
+	require.NoError(t, sink.Flush(ctx, hlc.Timestamp{}))
 	sqlDB.CheckQueryResults(t, `SELECT key, value FROM sink ORDER BY PRIMARY KEY sink`,
 		[][]string{{`k1`, `v0`}},
 	)
@@ -173,7 +174,7 @@ func TestSQLSink(t *testing.T) {
 	}
 	// Should have auto flushed after sqlSinkRowBatchSize
 	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM sink`, [][]string{{`3`}})
-	require.NoError(t, sink.Flush(ctx))
+	require.NoError(t, sink.Flush(ctx, hlc.Timestamp{}))
 	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM sink`, [][]string{{`4`}})
 	sqlDB.Exec(t, `TRUNCATE sink`)
 
@@ -181,7 +182,7 @@ func TestSQLSink(t *testing.T) {
 	require.NoError(t, sink.EmitRow(ctx, `foo`, []byte(`kfoo`), []byte(`v0`)))
 	require.NoError(t, sink.EmitRow(ctx, `bar`, []byte(`kbar`), []byte(`v0`)))
 	require.NoError(t, sink.EmitRow(ctx, `foo`, []byte(`kfoo`), []byte(`v1`)))
-	require.NoError(t, sink.Flush(ctx))
+	require.NoError(t, sink.Flush(ctx, hlc.Timestamp{}))
 	sqlDB.CheckQueryResults(t, `SELECT topic, key, value FROM sink ORDER BY PRIMARY KEY sink`,
 		[][]string{{`bar`, `kbar`, `v0`}, {`foo`, `kfoo`, `v0`}, {`foo`, `kfoo`, `v1`}},
 	)
@@ -195,7 +196,7 @@ func TestSQLSink(t *testing.T) {
 	for i := 0; i < sqlSinkNumPartitions+1; i++ {
 		require.NoError(t, sink.EmitRow(ctx, `foo`, []byte(`v`+strconv.Itoa(i)), []byte(`v1`)))
 	}
-	require.NoError(t, sink.Flush(ctx))
+	require.NoError(t, sink.Flush(ctx, hlc.Timestamp{}))
 	sqlDB.CheckQueryResults(t, `SELECT partition, key, value FROM sink ORDER BY PRIMARY KEY sink`,
 		[][]string{
 			{`0`, `v3`, `v0`},
@@ -211,10 +212,10 @@ func TestSQLSink(t *testing.T) {
 	sqlDB.Exec(t, `TRUNCATE sink`)
 
 	// Emit resolved
-	require.NoError(t, sink.EmitResolvedTimestamp(ctx, []byte(`r0`)))
+	require.NoError(t, sink.EmitResolvedTimestamp(ctx, []byte(`r0`), hlc.Timestamp{}))
 	require.NoError(t, sink.EmitRow(ctx, `foo`, []byte(`foo0`), []byte(`v0`)))
-	require.NoError(t, sink.EmitResolvedTimestamp(ctx, []byte(`r1`)))
-	require.NoError(t, sink.Flush(ctx))
+	require.NoError(t, sink.EmitResolvedTimestamp(ctx, []byte(`r1`), hlc.Timestamp{}))
+	require.NoError(t, sink.Flush(ctx, hlc.Timestamp{}))
 	sqlDB.CheckQueryResults(t,
 		`SELECT topic, partition, key, value, resolved FROM sink ORDER BY PRIMARY KEY sink`,
 		[][]string{
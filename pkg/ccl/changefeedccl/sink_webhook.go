@@ -0,0 +1,350 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bytes"
+	"context"
+	gojson "encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/pkg/errors"
+)
+
+const (
+	webhookSinkDefaultBatchSize      = 1 << 20
+	webhookSinkDefaultFlushFrequency = time.Second
+	webhookSinkContentType           = `application/json`
+	webhookSinkTimeout               = 10 * time.Second
+	// webhookSinkMaxAttempts is the number of times a request is attempted
+	// before giving up and handing the error to the changefeed, which retries
+	// the whole flow.
+	webhookSinkMaxAttempts = 3
+)
+
+// webhookSinkMessage is the JSON representation of one row in a batch.
+type webhookSinkMessage struct {
+	Topic string            `json:"topic"`
+	Key   gojson.RawMessage `json:"key"`
+	Value gojson.RawMessage `json:"value"`
+}
+
+// webhookSinkBatch is the JSON representation of the body of a batch request.
+type webhookSinkBatch struct {
+	Payload []webhookSinkMessage `json:"payload"`
+	Length  int                  `json:"length"`
+}
+
+// webhookSink emits to an HTTP(S) endpoint. It is not concurrency-safe; all
+// calls to Emit and Flush should be from the same goroutine.
+//
+// Rows are buffered and POSTed as a JSON batch of the form
+// `{"payload":[{"topic":...,"key":...,"value":...},...],"length":N}`. The
+// value of a deleted row is null. A batch is sent when it reaches the target
+// size, when its oldest row has been buffered for the flush frequency, or when
+// the sink is flushed, which the changefeed does before forwarding any resolved
+// timestamp. Resolved timestamps are POSTed on their own, with the encoded
+// resolved timestamp as the body.
+//
+// Batches which are due are sent by a worker goroutine, so that rows don't sit
+// in the buffer when no more rows arrive. An error of such a background flush
+// is returned by the next call to the sink.
+//
+// A request that fails with a network error or a 5xx or 429 response is
+// retried a few times and then returned as a retryableSinkError, which
+// restarts the changefeed from its last resolved timestamp. Any other non-2xx
+// response is a permanent error.
+type webhookSink struct {
+	url    string
+	client *http.Client
+	topics map[string]struct{}
+
+	targetBatchSize int
+	flushFrequency  time.Duration
+	retryOpts       retry.Options
+	// now and after are overridden in tests.
+	now   func() time.Time
+	after func(time.Duration) <-chan time.Time
+
+	closed bool
+	// The worker is started by the first EmitRow.
+	stopWorkerCh chan struct{}
+	cancelWorker context.CancelFunc
+	worker       sync.WaitGroup
+	// batchStartedCh wakes up the worker when a row is added to an empty batch.
+	batchStartedCh chan struct{}
+
+	// Only synchronized between the client goroutine and the worker goroutine.
+	// It is held while a batch is sent.
+	mu struct {
+		syncutil.Mutex
+		batch         []webhookSinkMessage
+		batchSize     int
+		batchStart    time.Time
+		flushErr      error
+		scratchBuffer bytes.Buffer
+	}
+}
+
+var _ Sink = &webhookSink{}
+
+func makeWebhookSink(
+	sinkURL string,
+	targetBatchSize int,
+	flushFrequency time.Duration,
+	opts map[string]string,
+	targets jobspb.ChangefeedTargets,
+) (*webhookSink, error) {
+	switch formatType(opts[optFormat]) {
+	case optFormatJSON:
+	default:
+		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
+			optFormat, opts[optFormat])
+	}
+
+	s := &webhookSink{
+		url:             sinkURL,
+		client:          &http.Client{Timeout: webhookSinkTimeout},
+		topics:          make(map[string]struct{}),
+		targetBatchSize: targetBatchSize,
+		flushFrequency:  flushFrequency,
+		retryOpts: retry.Options{
+			InitialBackoff: 50 * time.Millisecond,
+			Multiplier:     2,
+			MaxBackoff:     time.Second,
+			MaxRetries:     webhookSinkMaxAttempts - 1,
+		},
+		now:            timeutil.Now,
+		after:          time.After,
+		batchStartedCh: make(chan struct{}, 1),
+	}
+	for _, t := range targets {
+		s.topics[t.StatementTimeName] = struct{}{}
+	}
+	return s, nil
+}
+
+// EmitRow implements the Sink interface.
+func (s *webhookSink) EmitRow(ctx context.Context, topic string, key, value []byte) error {
+	if s.closed {
+		return errors.New(`cannot EmitRow on a closed sink`)
+	}
+	if _, ok := s.topics[topic]; !ok {
+		return errors.Errorf(`cannot emit to undeclared topic: %s`, topic)
+	}
+	if s.stopWorkerCh == nil {
+		s.start()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.takeFlushErrLocked(); err != nil {
+		return err
+	}
+	msg := webhookSinkMessage{Topic: topic, Key: key, Value: value}
+	if len(value) == 0 {
+		msg.Value = gojson.RawMessage(`null`)
+	}
+	if len(s.mu.batch) == 0 {
+		s.mu.batchStart = s.now()
+		select {
+		case s.batchStartedCh <- struct{}{}:
+		default:
+		}
+	}
+	s.mu.batch = append(s.mu.batch, msg)
+	s.mu.batchSize += len(msg.Key) + len(msg.Value)
+
+	if s.mu.batchSize >= s.targetBatchSize || s.now().Sub(s.mu.batchStart) >= s.flushFrequency {
+		return s.sendBatchLocked(ctx)
+	}
+	return nil
+}
+
+// EmitResolvedTimestamp implements the Sink interface.
+func (s *webhookSink) EmitResolvedTimestamp(
+	ctx context.Context, payload []byte, _ hlc.Timestamp,
+) error {
+	if s.closed {
+		return errors.New(`cannot EmitResolvedTimestamp on a closed sink`)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.takeFlushErrLocked(); err != nil {
+		return err
+	}
+	// Send any buffered rows first, so the endpoint never sees a resolved
+	// timestamp before a row it covers.
+	if err := s.sendBatchLocked(ctx); err != nil {
+		return err
+	}
+	return s.post(ctx, payload)
+}
+
+// Flush implements the Sink interface.
+func (s *webhookSink) Flush(ctx context.Context, _ hlc.Timestamp) error {
+	if s.closed {
+		return errors.New(`cannot Flush on a closed sink`)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.takeFlushErrLocked(); err != nil {
+		return err
+	}
+	return s.sendBatchLocked(ctx)
+}
+
+// Close implements the Sink interface.
+func (s *webhookSink) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if s.stopWorkerCh != nil {
+		close(s.stopWorkerCh)
+		// Abandon any request of the worker which is in flight.
+		s.cancelWorker()
+		s.worker.Wait()
+	}
+	s.mu.Lock()
+	s.mu.batch = nil
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *webhookSink) start() {
+	s.stopWorkerCh = make(chan struct{})
+	var ctx context.Context
+	ctx, s.cancelWorker = context.WithCancel(context.Background())
+	s.worker.Add(1)
+	go s.workerLoop(ctx)
+}
+
+// workerLoop sends the buffered batch whenever it has been buffered for the
+// flush frequency.
+func (s *webhookSink) workerLoop(ctx context.Context) {
+	defer s.worker.Done()
+
+	for {
+		var wait time.Duration
+		var armTimer bool
+		s.mu.Lock()
+		// The deadline computed here accounts for any batch started so far.
+		select {
+		case <-s.batchStartedCh:
+		default:
+		}
+		// After a failure, nothing is sent until the changefeed has seen the
+		// error.
+		if len(s.mu.batch) > 0 && s.mu.flushErr == nil {
+			wait = s.mu.batchStart.Add(s.flushFrequency).Sub(s.now())
+			armTimer = true
+		}
+		s.mu.Unlock()
+
+		var timerCh <-chan time.Time
+		if armTimer {
+			timerCh = s.after(wait)
+		}
+
+		select {
+		case <-s.stopWorkerCh:
+			return
+		case <-s.batchStartedCh:
+		case <-timerCh:
+			s.mu.Lock()
+			if len(s.mu.batch) > 0 && s.now().Sub(s.mu.batchStart) >= s.flushFrequency {
+				s.mu.flushErr = s.sendBatchLocked(ctx)
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// takeFlushErrLocked returns and clears the error of a failed background
+// flush, if any.
+func (s *webhookSink) takeFlushErrLocked() error {
+	err := s.mu.flushErr
+	s.mu.flushErr = nil
+	return err
+}
+
+func (s *webhookSink) sendBatchLocked(ctx context.Context) error {
+	if len(s.mu.batch) == 0 {
+		return nil
+	}
+	s.mu.scratchBuffer.Reset()
+	if err := gojson.NewEncoder(&s.mu.scratchBuffer).Encode(webhookSinkBatch{
+		Payload: s.mu.batch,
+		Length:  len(s.mu.batch),
+	}); err != nil {
+		return err
+	}
+	if log.V(2) {
+		log.Infof(ctx, "sending batch of %d rows to webhook", len(s.mu.batch))
+	}
+	if err := s.post(ctx, s.mu.scratchBuffer.Bytes()); err != nil {
+		return err
+	}
+	s.mu.batch = s.mu.batch[:0]
+	s.mu.batchSize = 0
+	return nil
+}
+
+// post sends one request to the endpoint, retrying transient failures a
+// limited number of times.
+func (s *webhookSink) post(ctx context.Context, body []byte) error {
+	var err error
+	for r := retry.StartWithCtx(ctx, s.retryOpts); r.Next(); {
+		err = s.postOnce(ctx, body)
+		if err == nil || !isRetryableSinkError(err) {
+			return err
+		}
+		log.Infof(ctx, "webhook request failed, retrying: %v", err)
+	}
+	if err == nil {
+		// The loop body never ran, which only happens if the context is done.
+		err = ctx.Err()
+	}
+	return err
+}
+
+func (s *webhookSink) postOnce(ctx context.Context, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set(`Content-Type`, webhookSinkContentType)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return retryableSinkError{cause: errors.Wrap(err, `contacting webhook`)}
+	}
+	defer resp.Body.Close()
+	respBody, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1<<10))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	err = errors.Errorf(`webhook returned %s: %s`, resp.Status, bytes.TrimSpace(respBody))
+	if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+		return retryableSinkError{cause: err}
+	}
+	return err
+}
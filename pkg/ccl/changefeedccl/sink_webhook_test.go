@@ -0,0 +1,335 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	gojson "encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// testWebhookEndpoint is an httptest server that records the body of every
+// request it accepts. The status codes of the next responses can be scripted.
+type testWebhookEndpoint struct {
+	server *httptest.Server
+	mu     struct {
+		syncutil.Mutex
+		bodies   []string
+		statuses []int
+	}
+}
+
+func makeTestWebhookEndpoint() *testWebhookEndpoint {
+	e := &testWebhookEndpoint{}
+	e.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		if len(e.mu.statuses) > 0 {
+			status := e.mu.statuses[0]
+			e.mu.statuses = e.mu.statuses[1:]
+			if status != http.StatusOK {
+				http.Error(w, `scripted failure`, status)
+				return
+			}
+		}
+		e.mu.bodies = append(e.mu.bodies, strings.TrimSpace(string(body)))
+	}))
+	return e
+}
+
+func (e *testWebhookEndpoint) URL() string { return e.server.URL }
+
+func (e *testWebhookEndpoint) Close() { e.server.Close() }
+
+// ScriptStatuses sets the status codes returned for the next requests.
+func (e *testWebhookEndpoint) ScriptStatuses(statuses ...int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.mu.statuses = append(e.mu.statuses, statuses...)
+}
+
+// Bodies returns and clears the bodies of the accepted requests.
+func (e *testWebhookEndpoint) Bodies() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	bodies := e.mu.bodies
+	e.mu.bodies = nil
+	return bodies
+}
+
+func TestWebhookSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	endpoint := makeTestWebhookEndpoint()
+	defer endpoint.Close()
+
+	targets := jobspb.ChangefeedTargets{
+		0: jobspb.ChangefeedTarget{StatementTimeName: `foo`},
+		1: jobspb.ChangefeedTarget{StatementTimeName: `bar`},
+	}
+	opts := map[string]string{optFormat: string(optFormatJSON)}
+	makeSink := func(t *testing.T, batchSize int, flushFrequency time.Duration) *webhookSink {
+		s, err := makeWebhookSink(endpoint.URL(), batchSize, flushFrequency, opts, targets)
+		require.NoError(t, err)
+		s.retryOpts.InitialBackoff = time.Millisecond
+		s.retryOpts.MaxBackoff = time.Millisecond
+		return s
+	}
+
+	t.Run(`batching`, func(t *testing.T) {
+		s := makeSink(t, 1<<20, time.Hour)
+		defer func() { require.NoError(t, s.Close()) }()
+
+		// Undeclared topic
+		require.EqualError(t, s.EmitRow(ctx, `nope`, nil, nil), `cannot emit to undeclared topic: nope`)
+
+		// Nothing is sent until Flush is called.
+		require.NoError(t, s.Flush(ctx, hlc.Timestamp{}))
+		require.NoError(t, s.EmitRow(ctx, `foo`, []byte(`[1]`), []byte(`{"a":1}`)))
+		require.NoError(t, s.EmitRow(ctx, `bar`, []byte(`[2]`), nil))
+		require.Equal(t, []string(nil), endpoint.Bodies())
+		require.NoError(t, s.Flush(ctx, hlc.Timestamp{}))
+		require.Equal(t, []string{
+			`{"payload":[` +
+				`{"topic":"foo","key":[1],"value":{"a":1}},` +
+				`{"topic":"bar","key":[2],"value":null}` +
+				`],"length":2}`,
+		}, endpoint.Bodies())
+
+		// A resolved timestamp is sent on its own, after any buffered rows.
+		require.NoError(t, s.EmitRow(ctx, `foo`, []byte(`[3]`), []byte(`{"a":3}`)))
+		require.NoError(t, s.EmitResolvedTimestamp(ctx, []byte(`{"resolved":"1"}`), hlc.Timestamp{}))
+		require.Equal(t, []string{
+			`{"payload":[{"topic":"foo","key":[3],"value":{"a":3}}],"length":1}`,
+			`{"resolved":"1"}`,
+		}, endpoint.Bodies())
+	})
+
+	t.Run(`batch size`, func(t *testing.T) {
+		s := makeSink(t, 10, time.Hour)
+		defer func() { require.NoError(t, s.Close()) }()
+
+		require.NoError(t, s.EmitRow(ctx, `foo`, []byte(`[1]`), []byte(`{}`)))
+		require.Equal(t, []string(nil), endpoint.Bodies())
+		require.NoError(t, s.EmitRow(ctx, `foo`, []byte(`[2]`), []byte(`{}`)))
+		require.Len(t, endpoint.Bodies(), 1)
+		require.NoError(t, s.EmitRow(ctx, `foo`, []byte(`[3]`), []byte(`{}`)))
+		require.Equal(t, []string(nil), endpoint.Bodies())
+	})
+
+	t.Run(`flush frequency`, func(t *testing.T) {
+		s := makeSink(t, 1<<20, time.Second)
+		defer func() { require.NoError(t, s.Close()) }()
+		clock := hlc.NewManualClock(1)
+		s.now = func() time.Time { return timeutil.Unix(0, clock.UnixNano()) }
+		// The timer of the worker never fires.
+		s.after = func(time.Duration) <-chan time.Time { return nil }
+
+		require.NoError(t, s.EmitRow(ctx, `foo`, []byte(`[1]`), []byte(`{}`)))
+		clock.Increment(int64(999 * time.Millisecond))
+		require.NoError(t, s.EmitRow(ctx, `foo`, []byte(`[2]`), []byte(`{}`)))
+		require.Equal(t, []string(nil), endpoint.Bodies())
+		clock.Increment(int64(time.Millisecond))
+		require.NoError(t, s.EmitRow(ctx, `foo`, []byte(`[3]`), []byte(`{}`)))
+		require.Len(t, endpoint.Bodies(), 1)
+	})
+
+	t.Run(`flush timer`, func(t *testing.T) {
+		s := makeSink(t, 1<<20, time.Second)
+		defer func() { require.NoError(t, s.Close()) }()
+		clock := hlc.NewManualClock(1)
+		s.now = func() time.Time { return timeutil.Unix(0, clock.UnixNano()) }
+		// The worker reports the duration of every timer it waits on, which
+		// fires when the test says so.
+		waits := make(chan time.Duration)
+		fire := make(chan time.Time)
+		s.after = func(d time.Duration) <-chan time.Time {
+			waits <- d
+			return fire
+		}
+
+		// The timer is armed for when the batch is due.
+		require.NoError(t, s.EmitRow(ctx, `foo`, []byte(`[1]`), []byte(`{}`)))
+		require.Equal(t, time.Second, <-waits)
+		clock.Increment(int64(400 * time.Millisecond))
+		require.NoError(t, s.EmitRow(ctx, `foo`, []byte(`[2]`), []byte(`{}`)))
+
+		// A timer which fires early doesn't send anything and is re-armed.
+		fire <- time.Time{}
+		require.Equal(t, 600*time.Millisecond, <-waits)
+		require.Equal(t, []string(nil), endpoint.Bodies())
+
+		// Once the batch is due, it's sent without any more rows arriving.
+		clock.Increment(int64(600 * time.Millisecond))
+		fire <- time.Time{}
+		testutils.SucceedsSoon(t, func() error {
+			bodies := endpoint.Bodies()
+			if len(bodies) == 0 {
+				return errors.New(`batch not sent yet`)
+			}
+			require.Equal(t, []string{
+				`{"payload":[` +
+					`{"topic":"foo","key":[1],"value":{}},` +
+					`{"topic":"foo","key":[2],"value":{}}` +
+					`],"length":2}`,
+			}, bodies)
+			return nil
+		})
+
+		// The failure of a background flush is returned by the next call.
+		endpoint.ScriptStatuses(http.StatusBadRequest)
+		require.NoError(t, s.EmitRow(ctx, `foo`, []byte(`[3]`), []byte(`{}`)))
+		require.Equal(t, time.Second, <-waits)
+		clock.Increment(int64(time.Second))
+		fire <- time.Time{}
+		testutils.SucceedsSoon(t, func() error {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.mu.flushErr == nil {
+				return errors.New(`batch not sent yet`)
+			}
+			return nil
+		})
+		err := s.Flush(ctx, hlc.Timestamp{})
+		require.True(t, testutils.IsError(err, `webhook returned 400 Bad Request: scripted failure`), err)
+		// The batch is still buffered.
+		require.NoError(t, s.Flush(ctx, hlc.Timestamp{}))
+		require.Len(t, endpoint.Bodies(), 1)
+	})
+
+	t.Run(`retries`, func(t *testing.T) {
+		s := makeSink(t, 1<<20, time.Hour)
+		defer func() { require.NoError(t, s.Close()) }()
+
+		// Transient failures are retried inside the sink.
+		endpoint.ScriptStatuses(http.StatusServiceUnavailable, http.StatusTooManyRequests)
+		require.NoError(t, s.EmitRow(ctx, `foo`, []byte(`[1]`), []byte(`{}`)))
+		require.NoError(t, s.Flush(ctx, hlc.Timestamp{}))
+		require.Len(t, endpoint.Bodies(), 1)
+
+		// Once the sink gives up, the error is retryable by the changefeed and
+		// the batch is still buffered.
+		var statuses []int
+		for i := 0; i < webhookSinkMaxAttempts; i++ {
+			statuses = append(statuses, http.StatusInternalServerError)
+		}
+		endpoint.ScriptStatuses(statuses...)
+		require.NoError(t, s.EmitRow(ctx, `foo`, []byte(`[2]`), []byte(`{}`)))
+		err := s.Flush(ctx, hlc.Timestamp{})
+		require.True(t, isRetryableSinkError(err), `expected retryable error got: %+v`, err)
+		require.Equal(t, []string(nil), endpoint.Bodies())
+		require.NoError(t, s.Flush(ctx, hlc.Timestamp{}))
+		require.Len(t, endpoint.Bodies(), 1)
+
+		// Other failures are permanent.
+		endpoint.ScriptStatuses(http.StatusBadRequest)
+		require.NoError(t, s.EmitRow(ctx, `foo`, []byte(`[3]`), []byte(`{}`)))
+		err = s.Flush(ctx, hlc.Timestamp{})
+		require.False(t, isRetryableSinkError(err), `expected permanent error got: %+v`, err)
+		require.True(t, testutils.IsError(err, `webhook returned 400 Bad Request: scripted failure`), err)
+	})
+
+	t.Run(`errors`, func(t *testing.T) {
+		_, err := makeWebhookSink(endpoint.URL(), 1, time.Hour,
+			map[string]string{optFormat: string(optFormatAvro)}, targets)
+		require.EqualError(t, err, `this sink is incompatible with format=avro`)
+
+		u := `webhook-` + endpoint.URL()
+		_, err = getSink(ctx, u+`?nope=1`, 1, opts, targets, nil)
+		require.EqualError(t, err, `unknown sink query parameter: nope`)
+		s, err := getSink(ctx, u+`?batch_size=1KiB&flush_frequency=5s`, 1, opts, targets, nil)
+		require.NoError(t, err)
+		require.Equal(t, endpoint.URL(), s.(*webhookSink).url)
+		require.Equal(t, 1<<10, s.(*webhookSink).targetBatchSize)
+		require.Equal(t, 5*time.Second, s.(*webhookSink).flushFrequency)
+		require.NoError(t, s.Close())
+		require.EqualError(t, s.EmitRow(ctx, `foo`, nil, nil), `cannot EmitRow on a closed sink`)
+	})
+}
+
+func TestWebhookSinkEndToEnd(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{UseDatabase: "d"})
+	defer s.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `SET CLUSTER SETTING changefeed.experimental_poll_interval = '0ns'`)
+	sqlDB.Exec(t, `CREATE DATABASE d`)
+	sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+	sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a'), (2, 'b')`)
+
+	endpoint := makeTestWebhookEndpoint()
+	defer endpoint.Close()
+
+	var jobID int64
+	sqlDB.QueryRow(t, `CREATE CHANGEFEED FOR foo INTO $1 WITH resolved`,
+		`webhook-`+endpoint.URL()).Scan(&jobID)
+	defer sqlDB.Exec(t, `CANCEL JOB $1`, jobID)
+	sqlDB.Exec(t, `DELETE FROM foo WHERE a = 1`)
+
+	var rows []string
+	testutils.SucceedsSoon(t, func() error {
+		for _, body := range endpoint.Bodies() {
+			var batch struct {
+				Payload []webhookSinkMessage `json:"payload"`
+			}
+			if err := gojson.Unmarshal([]byte(body), &batch); err != nil {
+				return err
+			}
+			for _, m := range batch.Payload {
+				rows = append(rows, fmt.Sprintf(`%s: %s->%s`, m.Topic, m.Key, m.Value))
+			}
+		}
+		if len(rows) < 3 {
+			return fmt.Errorf(`expected 3 rows got %d: %v`, len(rows), rows)
+		}
+		return nil
+	})
+	// The batch is encoded with encoding/json, which compacts the values.
+	require.Equal(t, []string{
+		`foo: [1]->{"a":1,"b":"a"}`,
+		`foo: [2]->{"a":2,"b":"b"}`,
+		`foo: [1]->null`,
+	}, rows)
+
+	// Resolved timestamps come through as their own requests.
+	testutils.SucceedsSoon(t, func() error {
+		for _, body := range endpoint.Bodies() {
+			if strings.Contains(body, `"resolved"`) {
+				return nil
+			}
+		}
+		return fmt.Errorf(`no resolved timestamp yet`)
+	})
+}
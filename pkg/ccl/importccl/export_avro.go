@@ -0,0 +1,306 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	gojson "encoding/json"
+	"hash/crc32"
+	"math"
+	"math/big"
+
+	"github.com/cockroachdb/apd"
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl/avroccl"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+)
+
+// Avro object container files are described in
+// https://avro.apache.org/docs/1.8.2/spec.html#Object+Container+Files
+const (
+	avroOCFMagic         = "Obj\x01"
+	avroOCFSchemaKey     = "avro.schema"
+	avroOCFCodecKey      = "avro.codec"
+	avroOCFCodecNull     = "null"
+	avroOCFCodecDeflate  = "deflate"
+	avroOCFCodecSnappy   = "snappy"
+	avroOCFSyncLength    = 16
+	avroOCFRowsPerBlock  = 1000
+	avroExportRecordName = "row"
+)
+
+// avroEncodeFn appends the avro binary encoding of a non-NULL datum to b.
+type avroEncodeFn func(b []byte, d tree.Datum) ([]byte, error)
+
+// avroExportField is one column of an exported avro record. Every field is a
+// union of null and the column's type.
+type avroExportField struct {
+	name     string
+	schema   interface{}
+	encodeFn avroEncodeFn
+}
+
+// avroOCFWriter writes rows as an Avro object container file. The schema is a
+// record with one nullable field per column.
+type avroOCFWriter struct {
+	fields []avroExportField
+	codec  string
+	sync   [avroOCFSyncLength]byte
+
+	// block holds the encoded (uncompressed) rows of the current block.
+	block     []byte
+	blockRows int64
+	out       bytes.Buffer
+}
+
+var _ exportFileWriter = &avroOCFWriter{}
+
+func newAvroOCFWriter(
+	names []string, types []sqlbase.ColumnType, compression roachpb.IOFileFormat_Compression,
+) (*avroOCFWriter, error) {
+	w := &avroOCFWriter{codec: avroOCFCodecNull}
+	switch compression {
+	case roachpb.IOFileFormat_Auto, roachpb.IOFileFormat_None:
+	case roachpb.IOFileFormat_Gzip:
+		// Avro has no gzip codec, but deflate is the same compression without
+		// the gzip framing.
+		w.codec = avroOCFCodecDeflate
+	case roachpb.IOFileFormat_Snappy:
+		w.codec = avroOCFCodecSnappy
+	default:
+		return nil, errors.Errorf(`unsupported compression for avro: %s`, compression)
+	}
+
+	schemaFields := make([]map[string]interface{}, len(types))
+	for i := range types {
+		schema, encodeFn := avroExportSchema(types[i])
+		w.fields = append(w.fields, avroExportField{
+			name:     avroccl.SQLNameToAvroName(names[i]),
+			schema:   schema,
+			encodeFn: encodeFn,
+		})
+		schemaFields[i] = map[string]interface{}{
+			`name`:    w.fields[i].name,
+			`type`:    []interface{}{`null`, schema},
+			`default`: nil,
+		}
+	}
+	schema, err := gojson.Marshal(map[string]interface{}{
+		`type`:   `record`,
+		`name`:   avroExportRecordName,
+		`fields`: schemaFields,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The sync marker only needs to be unlikely to appear in the data.
+	copy(w.sync[:], uuid.MakeV4().GetBytes())
+
+	var header []byte
+	header = append(header, avroOCFMagic...)
+	header = avroccl.AppendLong(header, 2)
+	header = avroccl.AppendString(header, avroOCFSchemaKey)
+	header = avroccl.AppendBytes(header, schema)
+	header = avroccl.AppendString(header, avroOCFCodecKey)
+	header = avroccl.AppendString(header, w.codec)
+	header = avroccl.AppendLong(header, 0)
+	header = append(header, w.sync[:]...)
+	w.out.Write(header)
+	return w, nil
+}
+
+// WriteRow implements the exportFileWriter interface.
+func (w *avroOCFWriter) WriteRow(row tree.Datums) error {
+	for i, d := range row {
+		d = tree.UnwrapDatum(nil, d)
+		if d == tree.DNull {
+			w.block = avroccl.AppendLong(w.block, 0)
+			continue
+		}
+		w.block = avroccl.AppendLong(w.block, 1)
+		var err error
+		if w.block, err = w.fields[i].encodeFn(w.block, d); err != nil {
+			return errors.Wrapf(err, `column %s`, w.fields[i].name)
+		}
+	}
+	w.blockRows++
+	if w.blockRows >= avroOCFRowsPerBlock {
+		return w.flushBlock()
+	}
+	return nil
+}
+
+// Finish implements the exportFileWriter interface.
+func (w *avroOCFWriter) Finish() ([]byte, error) {
+	if err := w.flushBlock(); err != nil {
+		return nil, err
+	}
+	return w.out.Bytes(), nil
+}
+
+func (w *avroOCFWriter) flushBlock() error {
+	if w.blockRows == 0 {
+		return nil
+	}
+	data := w.block
+	switch w.codec {
+	case avroOCFCodecDeflate:
+		var buf bytes.Buffer
+		fw, err := flate.NewWriter(&buf, flate.DefaultCompression)
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(data); err != nil {
+			return err
+		}
+		if err := fw.Close(); err != nil {
+			return err
+		}
+		data = buf.Bytes()
+	case avroOCFCodecSnappy:
+		// The snappy codec is followed by the big-endian CRC32 of the
+		// uncompressed data.
+		data = snappy.Encode(nil, data)
+		var crc [4]byte
+		binary.BigEndian.PutUint32(crc[:], crc32.ChecksumIEEE(w.block))
+		data = append(data, crc[:]...)
+	}
+	var header []byte
+	header = avroccl.AppendLong(header, w.blockRows)
+	header = avroccl.AppendLong(header, int64(len(data)))
+	w.out.Write(header)
+	w.out.Write(data)
+	w.out.Write(w.sync[:])
+	w.block, w.blockRows = w.block[:0], 0
+	return nil
+}
+
+// avroExportSchema returns the avro schema of a column type and the function
+// to encode its non-NULL values. Types that avro can't represent natively are
+// exported as their string representation.
+func avroExportSchema(typ sqlbase.ColumnType) (interface{}, avroEncodeFn) {
+	switch typ.SemanticType {
+	case sqlbase.ColumnType_BOOL:
+		return `boolean`, func(b []byte, d tree.Datum) ([]byte, error) {
+			if *d.(*tree.DBool) {
+				return append(b, 1), nil
+			}
+			return append(b, 0), nil
+		}
+	case sqlbase.ColumnType_INT, sqlbase.ColumnType_OID:
+		return `long`, func(b []byte, d tree.Datum) ([]byte, error) {
+			i, err := exportDatumInt(d)
+			return avroccl.AppendLong(b, i), err
+		}
+	case sqlbase.ColumnType_FLOAT:
+		return `double`, func(b []byte, d tree.Datum) ([]byte, error) {
+			var buf [8]byte
+			binary.LittleEndian.PutUint64(buf[:], math.Float64bits(float64(*d.(*tree.DFloat))))
+			return append(b, buf[:]...), nil
+		}
+	case sqlbase.ColumnType_DECIMAL:
+		if typ.Precision == 0 {
+			// Without a precision, there's no fixed scale to encode with.
+			break
+		}
+		schema := map[string]interface{}{
+			`type`:        `bytes`,
+			`logicalType`: `decimal`,
+			`precision`:   typ.Precision,
+			`scale`:       typ.Width,
+		}
+		return schema, func(b []byte, d tree.Datum) ([]byte, error) {
+			unscaled, err := exportDecimalUnscaled(&d.(*tree.DDecimal).Decimal, typ.Width)
+			if err != nil {
+				return nil, err
+			}
+			return avroccl.AppendBytes(b, avroccl.BigIntToTwosComplement(unscaled)), nil
+		}
+	case sqlbase.ColumnType_DATE:
+		schema := map[string]interface{}{`type`: `int`, `logicalType`: `date`}
+		return schema, func(b []byte, d tree.Datum) ([]byte, error) {
+			return avroccl.AppendLong(b, int64(*d.(*tree.DDate))), nil
+		}
+	case sqlbase.ColumnType_TIME:
+		schema := map[string]interface{}{`type`: `long`, `logicalType`: `time-micros`}
+		return schema, func(b []byte, d tree.Datum) ([]byte, error) {
+			return avroccl.AppendLong(b, int64(*d.(*tree.DTime))), nil
+		}
+	case sqlbase.ColumnType_TIMESTAMP, sqlbase.ColumnType_TIMESTAMPTZ:
+		schema := map[string]interface{}{`type`: `long`, `logicalType`: `timestamp-micros`}
+		return schema, func(b []byte, d tree.Datum) ([]byte, error) {
+			micros, err := exportDatumTimestampMicros(d)
+			return avroccl.AppendLong(b, micros), err
+		}
+	case sqlbase.ColumnType_BYTES:
+		return `bytes`, func(b []byte, d tree.Datum) ([]byte, error) {
+			return avroccl.AppendString(b, string(*d.(*tree.DBytes))), nil
+		}
+	case sqlbase.ColumnType_UUID:
+		schema := map[string]interface{}{`type`: `string`, `logicalType`: `uuid`}
+		return schema, func(b []byte, d tree.Datum) ([]byte, error) {
+			return avroccl.AppendString(b, d.(*tree.DUuid).UUID.String()), nil
+		}
+	case sqlbase.ColumnType_ARRAY:
+		if typ.ArrayContents == nil {
+			break
+		}
+		elemSchema, elemEncodeFn := avroExportSchema(sqlbase.ColumnType{
+			SemanticType: *typ.ArrayContents,
+		})
+		schema := map[string]interface{}{
+			`type`:  `array`,
+			`items`: []interface{}{`null`, elemSchema},
+		}
+		return schema, func(b []byte, d tree.Datum) ([]byte, error) {
+			elems := d.(*tree.DArray).Array
+			if len(elems) > 0 {
+				b = avroccl.AppendLong(b, int64(len(elems)))
+				for _, elem := range elems {
+					if elem == tree.DNull {
+						b = avroccl.AppendLong(b, 0)
+						continue
+					}
+					b = avroccl.AppendLong(b, 1)
+					var err error
+					if b, err = elemEncodeFn(b, tree.UnwrapDatum(nil, elem)); err != nil {
+						return nil, err
+					}
+				}
+			}
+			return avroccl.AppendLong(b, 0), nil
+		}
+	}
+	// STRING, NAME, COLLATEDSTRING, JSONB and everything else without a native
+	// avro representation.
+	return `string`, func(b []byte, d tree.Datum) ([]byte, error) {
+		return avroccl.AppendString(b, exportDatumString(d)), nil
+	}
+}
+
+// exportDecimalUnscaled returns the unscaled value of a decimal at the given
+// scale.
+func exportDecimalUnscaled(dec *apd.Decimal, scale int32) (*big.Int, error) {
+	var scaled apd.Decimal
+	if _, err := tree.HighPrecisionCtx.Quantize(&scaled, dec, -scale); err != nil {
+		return nil, err
+	}
+	unscaled := new(big.Int).Set(&scaled.Coeff)
+	if scaled.Negative {
+		unscaled.Neg(unscaled)
+	}
+	return unscaled, nil
+}
@@ -0,0 +1,639 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"math"
+	"math/bits"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl/avroccl"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+)
+
+// The Parquet format is described in https://github.com/apache/parquet-format.
+// Its metadata is defined in parquet.thrift and serialized with the thrift
+// compact protocol. Only the small subset needed to write files is
+// implemented here: every file is a single row group with one PLAIN encoded
+// data page per column.
+const parquetMagic = "PAR1"
+
+// Enum values from parquet.thrift.
+const (
+	parquetTypeBoolean   = 0
+	parquetTypeInt32     = 1
+	parquetTypeInt64     = 2
+	parquetTypeDouble    = 5
+	parquetTypeByteArray = 6
+
+	parquetConvertedNone            = -1
+	parquetConvertedUTF8            = 0
+	parquetConvertedList            = 3
+	parquetConvertedDecimal         = 5
+	parquetConvertedDate            = 6
+	parquetConvertedTimeMicros      = 8
+	parquetConvertedTimestampMicros = 10
+	parquetConvertedJSON            = 19
+
+	parquetRepetitionOptional = 1
+	parquetRepetitionRepeated = 2
+
+	parquetEncodingPlain = 0
+	parquetEncodingRLE   = 3
+
+	parquetCodecUncompressed = 0
+	parquetCodecSnappy       = 1
+	parquetCodecGzip         = 2
+
+	parquetPageTypeData = 0
+)
+
+// parquetLeaf is the physical type of a column's values and how to encode
+// them.
+type parquetLeaf struct {
+	physical  int32
+	converted int32
+	scale     int32
+	precision int32
+	// encodeFn appends the PLAIN encoding of a non-NULL datum to b. BOOLEAN
+	// values are appended as one byte each and bit-packed when the page is
+	// written.
+	encodeFn func(b []byte, d tree.Datum) ([]byte, error)
+}
+
+// parquetColumn accumulates the values of one column. Scalar columns are
+// OPTIONAL. Arrays use the standard three-level LIST structure, where the
+// column and the elements are OPTIONAL:
+//
+//	optional group <name> (LIST) {
+//	  repeated group list {
+//	    optional <type> element;
+//	  }
+//	}
+type parquetColumn struct {
+	name    string
+	leaf    parquetLeaf
+	isArray bool
+
+	// defLevels and repLevels have one entry for every value, including
+	// NULLs. repLevels are only used for arrays.
+	defLevels []int32
+	repLevels []int32
+	// values is the PLAIN encoding of the non-NULL values.
+	values    []byte
+	numValues int
+}
+
+func (c *parquetColumn) maxDef() int32 {
+	if c.isArray {
+		return 3
+	}
+	return 1
+}
+
+func (c *parquetColumn) maxRep() int32 {
+	if c.isArray {
+		return 1
+	}
+	return 0
+}
+
+// parquetWriter writes rows as a Parquet file with a single row group.
+type parquetWriter struct {
+	cols    []*parquetColumn
+	codec   int32
+	numRows int64
+}
+
+var _ exportFileWriter = &parquetWriter{}
+
+func newParquetWriter(
+	names []string, types []sqlbase.ColumnType, compression roachpb.IOFileFormat_Compression,
+) (*parquetWriter, error) {
+	w := &parquetWriter{codec: parquetCodecUncompressed}
+	switch compression {
+	case roachpb.IOFileFormat_Auto, roachpb.IOFileFormat_None:
+	case roachpb.IOFileFormat_Gzip:
+		w.codec = parquetCodecGzip
+	case roachpb.IOFileFormat_Snappy:
+		w.codec = parquetCodecSnappy
+	default:
+		return nil, errors.Errorf(`unsupported compression for parquet: %s`, compression)
+	}
+	for i, typ := range types {
+		col := &parquetColumn{name: names[i]}
+		if typ.SemanticType == sqlbase.ColumnType_ARRAY && typ.ArrayContents != nil {
+			col.isArray = true
+			typ = sqlbase.ColumnType{SemanticType: *typ.ArrayContents}
+		}
+		col.leaf = parquetLeafForType(typ)
+		w.cols = append(w.cols, col)
+	}
+	return w, nil
+}
+
+// WriteRow implements the exportFileWriter interface.
+func (w *parquetWriter) WriteRow(row tree.Datums) error {
+	for i, d := range row {
+		col := w.cols[i]
+		d = tree.UnwrapDatum(nil, d)
+		if !col.isArray {
+			if err := col.add(d, 0 /* rep */, 1 /* def */); err != nil {
+				return err
+			}
+			continue
+		}
+		if d == tree.DNull {
+			col.defLevels = append(col.defLevels, 0)
+			col.repLevels = append(col.repLevels, 0)
+			continue
+		}
+		elems := d.(*tree.DArray).Array
+		if len(elems) == 0 {
+			col.defLevels = append(col.defLevels, 1)
+			col.repLevels = append(col.repLevels, 0)
+			continue
+		}
+		for j, elem := range elems {
+			rep := int32(1)
+			if j == 0 {
+				rep = 0
+			}
+			if err := col.add(tree.UnwrapDatum(nil, elem), rep, 3 /* def */); err != nil {
+				return err
+			}
+		}
+	}
+	w.numRows++
+	return nil
+}
+
+// add appends one value of the column, which has definition level def if it's
+// non-NULL and def-1 otherwise.
+func (c *parquetColumn) add(d tree.Datum, rep, def int32) error {
+	if c.isArray {
+		c.repLevels = append(c.repLevels, rep)
+	}
+	if d == tree.DNull {
+		c.defLevels = append(c.defLevels, def-1)
+		return nil
+	}
+	c.defLevels = append(c.defLevels, def)
+	var err error
+	if c.values, err = c.leaf.encodeFn(c.values, d); err != nil {
+		return errors.Wrapf(err, `column %s`, c.name)
+	}
+	c.numValues++
+	return nil
+}
+
+// Finish implements the exportFileWriter interface.
+func (w *parquetWriter) Finish() ([]byte, error) {
+	var out bytes.Buffer
+	out.WriteString(parquetMagic)
+
+	var chunks thriftCompactWriter
+	chunks.listHeader(thriftTypeStruct, len(w.cols))
+	var totalByteSize int64
+	for _, col := range w.cols {
+		page := col.pageData()
+		compressed, err := w.compress(page)
+		if err != nil {
+			return nil, err
+		}
+
+		var header thriftCompactWriter
+		header.structBegin()
+		header.i32Field(1, parquetPageTypeData)
+		header.i32Field(2, int32(len(page)))
+		header.i32Field(3, int32(len(compressed)))
+		header.structField(5)
+		header.i32Field(1, int32(len(col.defLevels)))
+		header.i32Field(2, parquetEncodingPlain)
+		header.i32Field(3, parquetEncodingRLE)
+		header.i32Field(4, parquetEncodingRLE)
+		header.structEnd()
+		header.structEnd()
+
+		offset := int64(out.Len())
+		out.Write(header.buf)
+		out.Write(compressed)
+		uncompressedSize := int64(len(header.buf) + len(page))
+		compressedSize := int64(len(header.buf) + len(compressed))
+		totalByteSize += uncompressedSize
+
+		chunks.structBegin()
+		chunks.i64Field(2, offset)
+		chunks.structField(3)
+		chunks.i32Field(1, col.leaf.physical)
+		chunks.listField(2, thriftTypeI32, 2)
+		chunks.i32(parquetEncodingPlain)
+		chunks.i32(parquetEncodingRLE)
+		path := col.path()
+		chunks.listField(3, thriftTypeBinary, len(path))
+		for _, p := range path {
+			chunks.binary(p)
+		}
+		chunks.i32Field(4, w.codec)
+		chunks.i64Field(5, int64(len(col.defLevels)))
+		chunks.i64Field(6, uncompressedSize)
+		chunks.i64Field(7, compressedSize)
+		chunks.i64Field(9, offset)
+		chunks.structEnd()
+		chunks.structEnd()
+	}
+
+	var meta thriftCompactWriter
+	meta.structBegin()
+	meta.i32Field(1, 1 /* version */)
+	schema := w.schema()
+	meta.listField(2, thriftTypeStruct, len(schema))
+	for _, el := range schema {
+		el.write(&meta)
+	}
+	meta.i64Field(3, w.numRows)
+	meta.listField(4, thriftTypeStruct, 1)
+	meta.structBegin()
+	meta.fieldHeader(1, thriftTypeList)
+	meta.buf = append(meta.buf, chunks.buf...)
+	meta.i64Field(2, totalByteSize)
+	meta.i64Field(3, w.numRows)
+	meta.structEnd()
+	meta.binaryField(6, `CockroachDB`)
+	meta.structEnd()
+
+	out.Write(meta.buf)
+	var metaLen [4]byte
+	binary.LittleEndian.PutUint32(metaLen[:], uint32(len(meta.buf)))
+	out.Write(metaLen[:])
+	out.WriteString(parquetMagic)
+	return out.Bytes(), nil
+}
+
+func (w *parquetWriter) compress(page []byte) ([]byte, error) {
+	switch w.codec {
+	case parquetCodecSnappy:
+		return snappy.Encode(nil, page), nil
+	case parquetCodecGzip:
+		var buf bytes.Buffer
+		gw := gzip.NewWriter(&buf)
+		if _, err := gw.Write(page); err != nil {
+			return nil, err
+		}
+		if err := gw.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return page, nil
+	}
+}
+
+// pageData returns the uncompressed contents of a data page with all of the
+// column's values.
+func (c *parquetColumn) pageData() []byte {
+	var page []byte
+	if c.maxRep() > 0 {
+		page = appendParquetLevels(page, c.repLevels, c.maxRep())
+	}
+	page = appendParquetLevels(page, c.defLevels, c.maxDef())
+	if c.leaf.physical != parquetTypeBoolean {
+		return append(page, c.values...)
+	}
+	packed := make([]byte, (c.numValues+7)/8)
+	for i, v := range c.values {
+		if v != 0 {
+			packed[i/8] |= 1 << uint(i%8)
+		}
+	}
+	return append(page, packed...)
+}
+
+// path returns the path of the column's leaf in the schema.
+func (c *parquetColumn) path() []string {
+	if c.isArray {
+		return []string{c.name, `list`, `element`}
+	}
+	return []string{c.name}
+}
+
+// appendParquetLevels appends repetition or definition levels in the RLE
+// hybrid encoding (using only RLE runs), prefixed by their length.
+func appendParquetLevels(b []byte, levels []int32, maxLevel int32) []byte {
+	byteWidth := (bits.Len32(uint32(maxLevel)) + 7) / 8
+	lenIdx := len(b)
+	b = append(b, 0, 0, 0, 0)
+	var buf [binary.MaxVarintLen64]byte
+	for i := 0; i < len(levels); {
+		run := 1
+		for i+run < len(levels) && levels[i+run] == levels[i] {
+			run++
+		}
+		n := binary.PutUvarint(buf[:], uint64(run)<<1)
+		b = append(b, buf[:n]...)
+		for j := 0; j < byteWidth; j++ {
+			b = append(b, byte(levels[i]>>(8*uint(j))))
+		}
+		i += run
+	}
+	binary.LittleEndian.PutUint32(b[lenIdx:], uint32(len(b)-lenIdx-4))
+	return b
+}
+
+// parquetSchemaElement is a SchemaElement in parquet.thrift.
+type parquetSchemaElement struct {
+	name        string
+	physical    int32 // only for leaves
+	isLeaf      bool
+	repetition  int32
+	numChildren int32
+	converted   int32
+	scale       int32
+	precision   int32
+}
+
+func (w *parquetWriter) schema() []parquetSchemaElement {
+	schema := []parquetSchemaElement{{
+		name:        `schema`,
+		repetition:  -1,
+		numChildren: int32(len(w.cols)),
+		converted:   parquetConvertedNone,
+	}}
+	for _, col := range w.cols {
+		leaf := parquetSchemaElement{
+			name:       col.name,
+			physical:   col.leaf.physical,
+			isLeaf:     true,
+			repetition: parquetRepetitionOptional,
+			converted:  col.leaf.converted,
+			scale:      col.leaf.scale,
+			precision:  col.leaf.precision,
+		}
+		if col.isArray {
+			leaf.name = `element`
+			schema = append(schema, parquetSchemaElement{
+				name:        col.name,
+				repetition:  parquetRepetitionOptional,
+				numChildren: 1,
+				converted:   parquetConvertedList,
+			}, parquetSchemaElement{
+				name:        `list`,
+				repetition:  parquetRepetitionRepeated,
+				numChildren: 1,
+				converted:   parquetConvertedNone,
+			})
+		}
+		schema = append(schema, leaf)
+	}
+	return schema
+}
+
+func (el parquetSchemaElement) write(w *thriftCompactWriter) {
+	w.structBegin()
+	if el.isLeaf {
+		w.i32Field(1, el.physical)
+	}
+	if el.repetition >= 0 {
+		w.i32Field(3, el.repetition)
+	}
+	w.binaryField(4, el.name)
+	if !el.isLeaf {
+		w.i32Field(5, el.numChildren)
+	}
+	if el.converted != parquetConvertedNone {
+		w.i32Field(6, el.converted)
+	}
+	if el.converted == parquetConvertedDecimal {
+		w.i32Field(7, el.scale)
+		w.i32Field(8, el.precision)
+	}
+	w.structEnd()
+}
+
+// parquetLeafForType returns how values of a (non-array) column type are
+// stored. Types that parquet can't represent natively are exported as their
+// string representation.
+func parquetLeafForType(typ sqlbase.ColumnType) parquetLeaf {
+	switch typ.SemanticType {
+	case sqlbase.ColumnType_BOOL:
+		return parquetLeaf{
+			physical:  parquetTypeBoolean,
+			converted: parquetConvertedNone,
+			encodeFn: func(b []byte, d tree.Datum) ([]byte, error) {
+				if *d.(*tree.DBool) {
+					return append(b, 1), nil
+				}
+				return append(b, 0), nil
+			},
+		}
+	case sqlbase.ColumnType_INT, sqlbase.ColumnType_OID:
+		return parquetLeaf{
+			physical:  parquetTypeInt64,
+			converted: parquetConvertedNone,
+			encodeFn: func(b []byte, d tree.Datum) ([]byte, error) {
+				i, err := exportDatumInt(d)
+				return appendParquetInt64(b, i), err
+			},
+		}
+	case sqlbase.ColumnType_FLOAT:
+		return parquetLeaf{
+			physical:  parquetTypeDouble,
+			converted: parquetConvertedNone,
+			encodeFn: func(b []byte, d tree.Datum) ([]byte, error) {
+				f := math.Float64bits(float64(*d.(*tree.DFloat)))
+				return appendParquetInt64(b, int64(f)), nil
+			},
+		}
+	case sqlbase.ColumnType_DECIMAL:
+		if typ.Precision == 0 {
+			// Without a precision, there's no fixed scale to encode with.
+			break
+		}
+		scale := typ.Width
+		return parquetLeaf{
+			physical:  parquetTypeByteArray,
+			converted: parquetConvertedDecimal,
+			scale:     scale,
+			precision: typ.Precision,
+			encodeFn: func(b []byte, d tree.Datum) ([]byte, error) {
+				unscaled, err := exportDecimalUnscaled(&d.(*tree.DDecimal).Decimal, scale)
+				if err != nil {
+					return nil, err
+				}
+				return appendParquetByteArray(b, avroccl.BigIntToTwosComplement(unscaled)), nil
+			},
+		}
+	case sqlbase.ColumnType_DATE:
+		return parquetLeaf{
+			physical:  parquetTypeInt32,
+			converted: parquetConvertedDate,
+			encodeFn: func(b []byte, d tree.Datum) ([]byte, error) {
+				var buf [4]byte
+				binary.LittleEndian.PutUint32(buf[:], uint32(int32(*d.(*tree.DDate))))
+				return append(b, buf[:]...), nil
+			},
+		}
+	case sqlbase.ColumnType_TIME:
+		return parquetLeaf{
+			physical:  parquetTypeInt64,
+			converted: parquetConvertedTimeMicros,
+			encodeFn: func(b []byte, d tree.Datum) ([]byte, error) {
+				return appendParquetInt64(b, int64(*d.(*tree.DTime))), nil
+			},
+		}
+	case sqlbase.ColumnType_TIMESTAMP, sqlbase.ColumnType_TIMESTAMPTZ:
+		return parquetLeaf{
+			physical:  parquetTypeInt64,
+			converted: parquetConvertedTimestampMicros,
+			encodeFn: func(b []byte, d tree.Datum) ([]byte, error) {
+				micros, err := exportDatumTimestampMicros(d)
+				return appendParquetInt64(b, micros), err
+			},
+		}
+	case sqlbase.ColumnType_BYTES:
+		return parquetLeaf{
+			physical:  parquetTypeByteArray,
+			converted: parquetConvertedNone,
+			encodeFn: func(b []byte, d tree.Datum) ([]byte, error) {
+				return appendParquetByteArray(b, []byte(*d.(*tree.DBytes))), nil
+			},
+		}
+	case sqlbase.ColumnType_JSONB:
+		return parquetLeaf{
+			physical:  parquetTypeByteArray,
+			converted: parquetConvertedJSON,
+			encodeFn: func(b []byte, d tree.Datum) ([]byte, error) {
+				return appendParquetByteArray(b, []byte(d.(*tree.DJSON).JSON.String())), nil
+			},
+		}
+	}
+	// STRING, NAME, COLLATEDSTRING, UUID and everything else without a native
+	// parquet representation.
+	return parquetLeaf{
+		physical:  parquetTypeByteArray,
+		converted: parquetConvertedUTF8,
+		encodeFn: func(b []byte, d tree.Datum) ([]byte, error) {
+			return appendParquetByteArray(b, []byte(exportDatumString(d))), nil
+		},
+	}
+}
+
+func appendParquetInt64(b []byte, i int64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], uint64(i))
+	return append(b, buf[:]...)
+}
+
+func appendParquetByteArray(b []byte, data []byte) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], uint32(len(data)))
+	b = append(b, buf[:]...)
+	return append(b, data...)
+}
+
+// Type ids of the thrift compact protocol.
+const (
+	thriftTypeI32    = 5
+	thriftTypeI64    = 6
+	thriftTypeBinary = 8
+	thriftTypeList   = 9
+	thriftTypeStruct = 12
+)
+
+// thriftCompactWriter serializes thrift structs with the compact protocol.
+// Fields must be written in increasing id order within each struct.
+//
+// See https://github.com/apache/thrift/blob/master/doc/specs/thrift-compact-protocol.md
+type thriftCompactWriter struct {
+	buf []byte
+	// lastIDs is a stack of the id of the last field written in each of the
+	// currently open structs.
+	lastIDs []int16
+}
+
+func (w *thriftCompactWriter) varint(i int64) {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(buf[:], i)
+	w.buf = append(w.buf, buf[:n]...)
+}
+
+func (w *thriftCompactWriter) uvarint(i uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], i)
+	w.buf = append(w.buf, buf[:n]...)
+}
+
+func (w *thriftCompactWriter) fieldHeader(id int16, typ byte) {
+	last := &w.lastIDs[len(w.lastIDs)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		w.buf = append(w.buf, byte(delta)<<4|typ)
+	} else {
+		w.buf = append(w.buf, typ)
+		w.varint(int64(id))
+	}
+	*last = id
+}
+
+func (w *thriftCompactWriter) structBegin() {
+	w.lastIDs = append(w.lastIDs, 0)
+}
+
+func (w *thriftCompactWriter) structEnd() {
+	w.buf = append(w.buf, 0)
+	w.lastIDs = w.lastIDs[:len(w.lastIDs)-1]
+}
+
+func (w *thriftCompactWriter) structField(id int16) {
+	w.fieldHeader(id, thriftTypeStruct)
+	w.structBegin()
+}
+
+func (w *thriftCompactWriter) i32(i int32) {
+	w.varint(int64(i))
+}
+
+func (w *thriftCompactWriter) i32Field(id int16, i int32) {
+	w.fieldHeader(id, thriftTypeI32)
+	w.i32(i)
+}
+
+func (w *thriftCompactWriter) i64Field(id int16, i int64) {
+	w.fieldHeader(id, thriftTypeI64)
+	w.varint(i)
+}
+
+func (w *thriftCompactWriter) binary(s string) {
+	w.uvarint(uint64(len(s)))
+	w.buf = append(w.buf, s...)
+}
+
+func (w *thriftCompactWriter) binaryField(id int16, s string) {
+	w.fieldHeader(id, thriftTypeBinary)
+	w.binary(s)
+}
+
+func (w *thriftCompactWriter) listHeader(elemType byte, n int) {
+	if n < 15 {
+		w.buf = append(w.buf, byte(n)<<4|elemType)
+		return
+	}
+	w.buf = append(w.buf, 0xf0|elemType)
+	w.uvarint(uint64(n))
+}
+
+func (w *thriftCompactWriter) listField(id int16, elemType byte, n int) {
+	w.fieldHeader(id, thriftTypeList)
+	w.listHeader(elemType, n)
+}
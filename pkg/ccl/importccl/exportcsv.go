@@ -10,6 +10,7 @@ package importccl
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
 	"strconv"
@@ -40,23 +41,36 @@ var exportHeader = sqlbase.ResultColumns{
 }
 
 const (
-	exportOptionDelimiter = "delimiter"
-	exportOptionNullAs    = "nullas"
-	exportOptionChunkSize = "chunk_rows"
-	exportOptionFileName  = "filename"
+	exportOptionDelimiter   = "delimiter"
+	exportOptionNullAs      = "nullas"
+	exportOptionChunkSize   = "chunk_rows"
+	exportOptionFileName    = "filename"
+	exportOptionCompression = "compression"
 )
 
 var exportOptionExpectValues = map[string]bool{
-	exportOptionChunkSize: true,
-	exportOptionDelimiter: true,
-	exportOptionFileName:  true,
-	exportOptionNullAs:    true,
+	exportOptionChunkSize:   true,
+	exportOptionCompression: true,
+	exportOptionDelimiter:   true,
+	exportOptionFileName:    true,
+	exportOptionNullAs:      true,
 }
 
 const exportChunkSizeDefault = 100000
 const exportFilePatternPart = "%part%"
 const exportFilePatternDefault = exportFilePatternPart + ".csv"
 
+// exportFormats maps the format names accepted by EXPORT to their file format
+// and default file extension.
+var exportFormats = map[string]struct {
+	format    roachpb.IOFileFormat_FileFormat
+	extension string
+}{
+	"CSV":     {roachpb.IOFileFormat_CSV, ".csv"},
+	"PARQUET": {roachpb.IOFileFormat_Parquet, ".parquet"},
+	"AVRO":    {roachpb.IOFileFormat_Avro, ".avro"},
+}
+
 // exportPlanHook implements sql.PlanHook.
 func exportPlanHook(
 	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
@@ -71,7 +85,8 @@ func exportPlanHook(
 		return nil, nil, nil, err
 	}
 
-	if exportStmt.FileFormat != "CSV" {
+	format, ok := exportFormats[exportStmt.FileFormat]
+	if !ok {
 		return nil, nil, nil, errors.Errorf("unsupported export format: %q", exportStmt.FileFormat)
 	}
 
@@ -116,6 +131,10 @@ func exportPlanHook(
 		csvOpts := roachpb.CSVOptions{}
 
 		if override, ok := opts[exportOptionDelimiter]; ok {
+			if format.format != roachpb.IOFileFormat_CSV {
+				return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+					"%s is only supported for CSV", exportOptionDelimiter)
+			}
 			csvOpts.Comma, err = util.GetSingleRune(override)
 			if err != nil {
 				return pgerror.NewError(pgerror.CodeInvalidParameterValueError, "invalid delimiter")
@@ -123,9 +142,37 @@ func exportPlanHook(
 		}
 
 		if override, ok := opts[exportOptionNullAs]; ok {
+			if format.format != roachpb.IOFileFormat_CSV {
+				return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+					"%s is only supported for CSV", exportOptionNullAs)
+			}
 			csvOpts.NullEncoding = &override
 		}
 
+		pattern := exportFilePatternPart + format.extension
+		compression := roachpb.IOFileFormat_None
+		if override, ok := opts[exportOptionCompression]; ok {
+			switch strings.ToLower(override) {
+			case "none":
+			case "gzip":
+				compression = roachpb.IOFileFormat_Gzip
+				if format.format == roachpb.IOFileFormat_CSV {
+					// Parquet and avro compress their contents internally, so
+					// only CSV files are named for their compression.
+					pattern += ".gz"
+				}
+			case "snappy":
+				if format.format == roachpb.IOFileFormat_CSV {
+					return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+						"unsupported compression for CSV: %q", override)
+				}
+				compression = roachpb.IOFileFormat_Snappy
+			default:
+				return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+					"unsupported compression value: %q", override)
+			}
+		}
+
 		chunk := exportChunkSizeDefault
 		if override, ok := opts[exportOptionChunkSize]; ok {
 			chunk, err = strconv.Atoi(override)
@@ -139,9 +186,12 @@ func exportPlanHook(
 
 		out := distsqlrun.ProcessorCoreUnion{CSVWriter: &distsqlrun.CSVWriterSpec{
 			Destination: file,
-			NamePattern: exportFilePatternDefault,
+			NamePattern: pattern,
 			Options:     csvOpts,
 			ChunkRows:   int64(chunk),
+			Format:      format.format,
+			ColumnNames: sql.ExportPlanColumnNames(plans[0]),
+			Compression: compression,
 		}}
 
 		rows := sqlbase.NewRowContainer(
@@ -218,26 +268,16 @@ func (sp *csvWriter) Run(ctx context.Context, wg *sync.WaitGroup) {
 		input := distsqlrun.MakeNoMetadataRowSource(sp.input, sp.output)
 
 		alloc := &sqlbase.DatumAlloc{}
-
-		var buf bytes.Buffer
-		writer := csv.NewWriter(&buf)
-		if sp.spec.Options.Comma != 0 {
-			writer.Comma = sp.spec.Options.Comma
-		}
-		nullsAs := ""
-		if sp.spec.Options.NullEncoding != nil {
-			nullsAs = *sp.spec.Options.NullEncoding
-		}
-		f := tree.NewFmtCtxWithBuf(tree.FmtParseDatums)
-		defer f.Close()
-
-		csvRow := make([]string, len(types))
+		datums := make(tree.Datums, len(types))
 
 		chunk := 0
 		done := false
 		for {
 			var rows int64
-			buf.Reset()
+			writer, err := sp.newFileWriter(types)
+			if err != nil {
+				return err
+			}
 			for {
 				if sp.spec.ChunkRows > 0 && rows >= sp.spec.ChunkRows {
 					break
@@ -253,25 +293,22 @@ func (sp *csvWriter) Run(ctx context.Context, wg *sync.WaitGroup) {
 				rows++
 
 				for i, ed := range row {
-					if ed.IsNull() {
-						csvRow[i] = nullsAs
-						continue
-					}
 					if err := ed.EnsureDecoded(&types[i], alloc); err != nil {
 						return err
 					}
-					ed.Datum.Format(&f.FmtCtx)
-					csvRow[i] = f.String()
-					f.Reset()
+					datums[i] = ed.Datum
 				}
-				if err := writer.Write(csvRow); err != nil {
+				if err := writer.WriteRow(datums); err != nil {
 					return err
 				}
 			}
 			if rows < 1 {
 				break
 			}
-			writer.Flush()
+			contents, err := writer.Finish()
+			if err != nil {
+				return err
+			}
 
 			conf, err := storageccl.ExportStorageConfFromURI(sp.spec.Destination)
 			if err != nil {
@@ -283,12 +320,12 @@ func (sp *csvWriter) Run(ctx context.Context, wg *sync.WaitGroup) {
 			}
 			defer es.Close()
 
-			size := buf.Len()
+			size := len(contents)
 
 			part := fmt.Sprintf("n%d.%d", sp.flowCtx.EvalCtx.NodeID, chunk)
 			chunk++
 			filename := strings.Replace(pattern, exportFilePatternPart, part, -1)
-			if err := es.WriteFile(ctx, filename, bytes.NewReader(buf.Bytes())); err != nil {
+			if err := es.WriteFile(ctx, filename, bytes.NewReader(contents)); err != nil {
 				return err
 			}
 			res := sqlbase.EncDatumRow{
@@ -328,6 +365,137 @@ func (sp *csvWriter) Run(ctx context.Context, wg *sync.WaitGroup) {
 		ctx, sp.output, err, func(context.Context) {} /* pushTrailingMeta */, sp.input)
 }
 
+// newFileWriter returns an exportFileWriter for a new file in the spec's
+// format.
+func (sp *csvWriter) newFileWriter(types []sqlbase.ColumnType) (exportFileWriter, error) {
+	switch sp.spec.Format {
+	case roachpb.IOFileFormat_Unknown, roachpb.IOFileFormat_CSV:
+		return newCSVFileWriter(sp.spec.Options, len(types), sp.spec.Compression)
+	case roachpb.IOFileFormat_Parquet:
+		return newParquetWriter(sp.spec.ColumnNames, types, sp.spec.Compression)
+	case roachpb.IOFileFormat_Avro:
+		return newAvroOCFWriter(sp.spec.ColumnNames, types, sp.spec.Compression)
+	default:
+		return nil, errors.Errorf("unsupported export format: %s", sp.spec.Format)
+	}
+}
+
+// exportFileWriter accumulates the rows of a single exported file.
+type exportFileWriter interface {
+	// WriteRow appends a row to the file. The datums are not retained.
+	WriteRow(row tree.Datums) error
+	// Finish returns the contents of the file.
+	Finish() ([]byte, error)
+}
+
+// csvFileWriter writes rows as CSV, optionally gzipped.
+type csvFileWriter struct {
+	buf     bytes.Buffer
+	writer  *csv.Writer
+	gzip    *gzip.Writer
+	nullsAs string
+	f       *tree.FmtCtxWithBuf
+	csvRow  []string
+}
+
+var _ exportFileWriter = &csvFileWriter{}
+
+func newCSVFileWriter(
+	opts roachpb.CSVOptions, numCols int, compression roachpb.IOFileFormat_Compression,
+) (*csvFileWriter, error) {
+	w := &csvFileWriter{
+		f:      tree.NewFmtCtxWithBuf(tree.FmtParseDatums),
+		csvRow: make([]string, numCols),
+	}
+	switch compression {
+	case roachpb.IOFileFormat_Auto, roachpb.IOFileFormat_None:
+		w.writer = csv.NewWriter(&w.buf)
+	case roachpb.IOFileFormat_Gzip:
+		w.gzip = gzip.NewWriter(&w.buf)
+		w.writer = csv.NewWriter(w.gzip)
+	default:
+		return nil, errors.Errorf("unsupported compression for CSV: %s", compression)
+	}
+	if opts.Comma != 0 {
+		w.writer.Comma = opts.Comma
+	}
+	if opts.NullEncoding != nil {
+		w.nullsAs = *opts.NullEncoding
+	}
+	return w, nil
+}
+
+// WriteRow implements the exportFileWriter interface.
+func (w *csvFileWriter) WriteRow(row tree.Datums) error {
+	for i, d := range row {
+		if d == tree.DNull {
+			w.csvRow[i] = w.nullsAs
+			continue
+		}
+		d.Format(&w.f.FmtCtx)
+		w.csvRow[i] = w.f.String()
+		w.f.Reset()
+	}
+	return w.writer.Write(w.csvRow)
+}
+
+// Finish implements the exportFileWriter interface.
+func (w *csvFileWriter) Finish() ([]byte, error) {
+	defer w.f.Close()
+	w.writer.Flush()
+	if err := w.writer.Error(); err != nil {
+		return nil, err
+	}
+	if w.gzip != nil {
+		if err := w.gzip.Close(); err != nil {
+			return nil, err
+		}
+	}
+	return w.buf.Bytes(), nil
+}
+
+// exportDatumInt returns the value of an INT or OID datum.
+func exportDatumInt(d tree.Datum) (int64, error) {
+	switch t := d.(type) {
+	case *tree.DInt:
+		return int64(*t), nil
+	case *tree.DOid:
+		return int64(t.DInt), nil
+	default:
+		return 0, errors.Errorf("unexpected integer datum: %T", d)
+	}
+}
+
+// exportDatumTimestampMicros returns the microseconds since the unix epoch of a
+// TIMESTAMP or TIMESTAMPTZ datum.
+func exportDatumTimestampMicros(d tree.Datum) (int64, error) {
+	switch t := d.(type) {
+	case *tree.DTimestamp:
+		return t.UnixNano() / 1000, nil
+	case *tree.DTimestampTZ:
+		return t.UnixNano() / 1000, nil
+	default:
+		return 0, errors.Errorf("unexpected timestamp datum: %T", d)
+	}
+}
+
+// exportDatumString returns the string representation of a datum without any
+// SQL quoting, for types that are exported as strings.
+func exportDatumString(d tree.Datum) string {
+	switch t := d.(type) {
+	case *tree.DString:
+		return string(*t)
+	case *tree.DCollatedString:
+		return t.Contents
+	case *tree.DJSON:
+		return t.JSON.String()
+	case *tree.DUuid:
+		return t.UUID.String()
+	default:
+		return tree.AsStringWithFlags(d, tree.FmtBareStrings)
+	}
+}
+
 func init() {
 	sql.AddPlanHook(exportPlanHook)
 	distsqlrun.NewCSVWriterProcessor = newCSVWriterProcessor
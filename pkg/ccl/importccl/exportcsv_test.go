@@ -10,6 +10,7 @@ package importccl_test
 
 import (
 	"context"
+	"encoding/binary"
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
@@ -26,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/workload"
 	"github.com/cockroachdb/cockroach/pkg/workload/bank"
+	"github.com/stretchr/testify/require"
 )
 
 func setupExportableBank(t *testing.T, nodes, rows int) (*sqlutils.SQLRunner, string, func()) {
@@ -215,3 +217,286 @@ func TestExportShow(t *testing.T) {
 		t.Fatalf("expected %q, got %q", expected, got)
 	}
 }
+
+func TestExportFormats(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	dir, cleanupDir := testutils.TempDir(t)
+	defer cleanupDir()
+
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{ExternalIODir: dir})
+	defer srv.Stopper().Stop(context.Background())
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	sqlDB.Exec(t, `CREATE TABLE t (
+		a INT PRIMARY KEY, b STRING, c DECIMAL(10, 2), d TIMESTAMP, e INT[], f JSONB, g BOOL
+	)`)
+	sqlDB.Exec(t, `INSERT INTO t VALUES
+		(1, 'one', 1.25, '2018-01-01 00:00:00', ARRAY[1, NULL], '{"a": 1}', true),
+		(2, NULL, -3.5, NULL, NULL, NULL, false),
+		(3, 'three', NULL, '2018-03-01 12:34:56', ARRAY[], '[]', NULL)`)
+
+	const cols = `a INT PRIMARY KEY, b STRING, c DECIMAL(10, 2), d TIMESTAMP, e INT[], f JSONB, g BOOL`
+
+	// importAvro imports the exported file into a new table with the given
+	// columns and checks it has the same rows as the query.
+	importAvro := func(table, cols, file, query string) func(*testing.T, []byte) {
+		return func(t *testing.T, _ []byte) {
+			sqlDB.Exec(t, fmt.Sprintf(`IMPORT TABLE %s (%s) AVRO DATA ('nodelocal:///%s') WITH strict_mode`,
+				table, cols, file))
+			sqlDB.CheckQueryResults(t, fmt.Sprintf(`SELECT * FROM %s ORDER BY a`, table),
+				sqlDB.QueryStr(t, query))
+		}
+	}
+
+	for _, tc := range []struct {
+		stmt, file string
+		header     []byte
+		footer     []byte
+		check      func(t *testing.T, content []byte)
+	}{
+		{
+			stmt:   `EXPORT INTO PARQUET 'nodelocal:///p' FROM TABLE t`,
+			file:   "p/n1.0.parquet",
+			header: []byte("PAR1"),
+			footer: []byte("PAR1"),
+			check: func(t *testing.T, content []byte) {
+				checkParquetMetadata(t, content, 0 /* UNCOMPRESSED */)
+				// The values of the first column are PLAIN encoded INT64s
+				// following its definition levels.
+				values := readParquetPage(t, content, 0)
+				levelsLen := int(binary.LittleEndian.Uint32(values))
+				values = values[4+levelsLen:]
+				require.Len(t, values, 3*8)
+				for i := 0; i < 3; i++ {
+					require.Equal(t, uint64(i+1), binary.LittleEndian.Uint64(values[i*8:]))
+				}
+			},
+		},
+		{
+			stmt:   `EXPORT INTO PARQUET 'nodelocal:///ps' WITH compression = 'snappy' FROM TABLE t`,
+			file:   "ps/n1.0.parquet",
+			header: []byte("PAR1"),
+			footer: []byte("PAR1"),
+			check: func(t *testing.T, content []byte) {
+				checkParquetMetadata(t, content, 1 /* SNAPPY */)
+			},
+		},
+		{
+			stmt:   `EXPORT INTO AVRO 'nodelocal:///a' FROM TABLE t`,
+			file:   "a/n1.0.avro",
+			header: []byte("Obj\x01"),
+			check:  importAvro(`a`, cols, `a/n1.0.avro`, `SELECT * FROM t ORDER BY a`),
+		},
+		{
+			stmt:   `EXPORT INTO AVRO 'nodelocal:///ag' WITH compression = 'gzip' FROM SELECT a, e FROM t`,
+			file:   "ag/n1.0.avro",
+			header: []byte("Obj\x01"),
+			check:  importAvro(`ag`, `a INT PRIMARY KEY, e INT[]`, `ag/n1.0.avro`, `SELECT a, e FROM t ORDER BY a`),
+		},
+		{
+			stmt:   `EXPORT INTO CSV 'nodelocal:///cg' WITH compression = 'gzip' FROM TABLE t`,
+			file:   "cg/n1.0.csv.gz",
+			header: []byte{0x1f, 0x8b},
+		},
+	} {
+		t.Run(tc.stmt, func(t *testing.T) {
+			var filename string
+			var rows int
+			var bytes int
+			sqlDB.QueryRow(t, tc.stmt).Scan(&filename, &rows, &bytes)
+			require.Equal(t, 3, rows)
+
+			content, err := ioutil.ReadFile(filepath.Join(dir, tc.file))
+			require.NoError(t, err)
+			require.Equal(t, bytes, len(content))
+			require.True(t, strings.HasPrefix(string(content), string(tc.header)))
+			require.True(t, strings.HasSuffix(string(content), string(tc.footer)))
+			if tc.check != nil {
+				tc.check(t, content)
+			}
+		})
+	}
+
+	for _, tc := range []struct {
+		stmt, err string
+	}{
+		{`EXPORT INTO CSV 'nodelocal:///x' WITH compression = 'snappy' FROM TABLE t`, `unsupported compression for CSV`},
+		{`EXPORT INTO PARQUET 'nodelocal:///x' WITH delimiter = '|' FROM TABLE t`, `delimiter is only supported for CSV`},
+		{`EXPORT INTO ORC 'nodelocal:///x' FROM TABLE t`, `unsupported export format`},
+	} {
+		if _, err := sqlDB.DB.Exec(tc.stmt); !testutils.IsError(err, tc.err) {
+			t.Fatalf("%s: expected %q, got %v", tc.stmt, tc.err, err)
+		}
+	}
+}
+
+// parquetFileMetadata decodes the FileMetaData footer of a Parquet file.
+func parquetFileMetadata(t *testing.T, content []byte) map[int16]interface{} {
+	t.Helper()
+	require.True(t, len(content) >= 12)
+	footerLen := int(binary.LittleEndian.Uint32(content[len(content)-8:]))
+	require.True(t, footerLen <= len(content)-12)
+	r := thriftCompactReader{t: t, buf: content[len(content)-8-footerLen : len(content)-8]}
+	meta := r.readStruct()
+	require.Empty(t, r.buf)
+	return meta
+}
+
+// checkParquetMetadata checks the footer of an export of the table t in
+// TestExportFormats.
+func checkParquetMetadata(t *testing.T, content []byte, codec int64) {
+	t.Helper()
+	meta := parquetFileMetadata(t, content)
+	require.Equal(t, int64(3), meta[3], "num_rows")
+
+	// Fields of SchemaElement: 1 type, 3 repetition_type, 4 name, 5
+	// num_children, 6 converted_type, 7 scale, 8 precision.
+	expectedSchema := []map[int16]interface{}{
+		{4: "schema", 5: int64(7)},
+		{1: int64(2), 3: int64(1), 4: "a"},
+		{1: int64(6), 3: int64(1), 4: "b", 6: int64(0)},
+		{1: int64(6), 3: int64(1), 4: "c", 6: int64(5), 7: int64(2), 8: int64(10)},
+		{1: int64(2), 3: int64(1), 4: "d", 6: int64(10)},
+		{3: int64(1), 4: "e", 5: int64(1), 6: int64(3)},
+		{3: int64(2), 4: "list", 5: int64(1)},
+		{1: int64(2), 3: int64(1), 4: "element"},
+		{1: int64(6), 3: int64(1), 4: "f", 6: int64(19)},
+		{1: int64(0), 3: int64(1), 4: "g"},
+	}
+	schema := meta[2].([]interface{})
+	require.Len(t, schema, len(expectedSchema))
+	for i, el := range schema {
+		require.Equal(t, expectedSchema[i], el, "schema element %d", i)
+	}
+
+	rowGroups := meta[4].([]interface{})
+	require.Len(t, rowGroups, 1)
+	rowGroup := rowGroups[0].(map[int16]interface{})
+	require.Equal(t, int64(3), rowGroup[3], "row group num_rows")
+
+	// Fields of ColumnMetaData: 1 type, 3 path_in_schema, 4 codec, 5
+	// num_values. The array has one value for each element, plus one for the
+	// NULL and one for the empty array.
+	expectedColumns := []struct {
+		physical  int64
+		path      []interface{}
+		numValues int64
+	}{
+		{2, []interface{}{"a"}, 3},
+		{6, []interface{}{"b"}, 3},
+		{6, []interface{}{"c"}, 3},
+		{2, []interface{}{"d"}, 3},
+		{2, []interface{}{"e", "list", "element"}, 4},
+		{6, []interface{}{"f"}, 3},
+		{0, []interface{}{"g"}, 3},
+	}
+	chunks := rowGroup[1].([]interface{})
+	require.Len(t, chunks, len(expectedColumns))
+	for i, chunk := range chunks {
+		col := chunk.(map[int16]interface{})[3].(map[int16]interface{})
+		require.Equal(t, expectedColumns[i].physical, col[1], "column %d type", i)
+		require.Equal(t, expectedColumns[i].path, col[3], "column %d path", i)
+		require.Equal(t, codec, col[4], "column %d codec", i)
+		require.Equal(t, expectedColumns[i].numValues, col[5], "column %d num_values", i)
+	}
+}
+
+// readParquetPage returns the contents of the data page of the i-th column of
+// an uncompressed Parquet file.
+func readParquetPage(t *testing.T, content []byte, i int) []byte {
+	t.Helper()
+	meta := parquetFileMetadata(t, content)
+	chunk := meta[4].([]interface{})[0].(map[int16]interface{})[1].([]interface{})[i]
+	offset := chunk.(map[int16]interface{})[3].(map[int16]interface{})[9].(int64)
+	r := thriftCompactReader{t: t, buf: content[offset:]}
+	// Fields of PageHeader: 2 uncompressed_page_size, 3 compressed_page_size.
+	header := r.readStruct()
+	size := int(header[3].(int64))
+	require.Equal(t, header[2], header[3])
+	require.True(t, size <= len(r.buf))
+	return r.buf[:size]
+}
+
+// thriftCompactReader decodes the subset of the thrift compact protocol used by
+// Parquet metadata. Structs are decoded as maps from field id to value, lists
+// as []interface{}, integers as int64 and binaries as strings.
+type thriftCompactReader struct {
+	t   *testing.T
+	buf []byte
+}
+
+func (r *thriftCompactReader) readByte() byte {
+	if len(r.buf) == 0 {
+		r.t.Fatal("unexpected end of thrift data")
+	}
+	b := r.buf[0]
+	r.buf = r.buf[1:]
+	return b
+}
+
+func (r *thriftCompactReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.buf)
+	if n <= 0 {
+		r.t.Fatal("invalid thrift varint")
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *thriftCompactReader) varint() int64 {
+	v, n := binary.Varint(r.buf)
+	if n <= 0 {
+		r.t.Fatal("invalid thrift varint")
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *thriftCompactReader) readStruct() map[int16]interface{} {
+	fields := make(map[int16]interface{})
+	var id int16
+	for {
+		header := r.readByte()
+		if header == 0 {
+			return fields
+		}
+		if delta := int16(header >> 4); delta != 0 {
+			id += delta
+		} else {
+			id = int16(r.varint())
+		}
+		fields[id] = r.readValue(header & 0x0f)
+	}
+}
+
+func (r *thriftCompactReader) readValue(typ byte) interface{} {
+	switch typ {
+	case 1, 2: // BOOLEAN_TRUE, BOOLEAN_FALSE
+		return typ == 1
+	case 4, 5, 6: // I16, I32, I64
+		return r.varint()
+	case 8: // BINARY
+		n := r.uvarint()
+		if n > uint64(len(r.buf)) {
+			r.t.Fatal("unexpected end of thrift data")
+		}
+		s := string(r.buf[:n])
+		r.buf = r.buf[n:]
+		return s
+	case 9: // LIST
+		header := r.readByte()
+		n := int(header >> 4)
+		if n == 15 {
+			n = int(r.uvarint())
+		}
+		list := make([]interface{}, n)
+		for i := range list {
+			list[i] = r.readValue(header & 0x0f)
+		}
+		return list
+	case 12: // STRUCT
+		return r.readStruct()
+	}
+	r.t.Fatalf("unsupported thrift type %d", typ)
+	return nil
+}
@@ -22,6 +22,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/build"
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
@@ -33,12 +34,15 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/coltypes"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -67,6 +71,8 @@ const (
 	pgCopyNull      = "nullif"
 
 	pgMaxRowSize = "max_row_size"
+
+	recordStrictMode = "strict_mode"
 )
 
 var importOptionExpectValues = map[string]bool{
@@ -88,6 +94,8 @@ var importOptionExpectValues = map[string]bool{
 	importOptionSkipFKs: false,
 
 	pgMaxRowSize: true,
+
+	recordStrictMode: false,
 }
 
 const (
@@ -126,6 +134,37 @@ func readCreateTableFromStore(
 	return create, nil
 }
 
+// resolveUserDefinedTypes replaces the user-defined types of the columns of
+// the table with the types of the same names in the database the table is
+// imported into. IMPORT does not create types: they must be created before
+// the tables which use them are imported.
+func resolveUserDefinedTypes(
+	ctx context.Context, txn *client.Txn, parentID sqlbase.ID, create *tree.CreateTable,
+) error {
+	for _, def := range create.Defs {
+		d, ok := def.(*tree.ColumnTableDef)
+		if !ok {
+			continue
+		}
+		typ, ok := d.Type.(*coltypes.TUserDefined)
+		if !ok {
+			continue
+		}
+		if typ.Schema != "" && typ.Schema != tree.PublicSchema {
+			return pgerror.Unimplemented(
+				"import non-public schema",
+				fmt.Sprintf("non-public schemas unsupported: %s", typ.Schema),
+			)
+		}
+		desc, err := sql.ResolveTypeDesc(ctx, txn, parentID, typ.Name)
+		if err != nil {
+			return err
+		}
+		d.Type = &coltypes.TEnum{Typ: desc.DatumType()}
+	}
+	return nil
+}
+
 type fkHandler struct {
 	allowed  bool
 	skip     bool
@@ -447,7 +486,7 @@ func importPlanHook(
 	}
 
 	var createFileFn func() (string, error)
-	if !importStmt.Bundle && importStmt.CreateDefs == nil {
+	if !importStmt.Bundle && !importStmt.Into && importStmt.CreateDefs == nil {
 		createFileFn, err = p.TypeAsString(importStmt.CreateFile, "IMPORT")
 		if err != nil {
 			return nil, nil, nil, err
@@ -498,6 +537,16 @@ func importPlanHook(
 
 		transform := opts[importOptionTransform]
 
+		if importStmt.Into {
+			if !p.ExecCfg().Settings.Version.IsMinSupported(cluster.VersionImportIntoExisting) {
+				return errors.Errorf("IMPORT INTO requires all nodes to be upgraded to %s",
+					cluster.VersionByKey(cluster.VersionImportIntoExisting))
+			}
+			if transform != "" {
+				return errors.Errorf("the %s option is not supported by IMPORT INTO", importOptionTransform)
+			}
+		}
+
 		var parentID sqlbase.ID
 		if transform != "" {
 			// If we're not ingesting the data, we don't care what DB we pick.
@@ -654,10 +703,34 @@ func importPlanHook(
 				maxRowSize = int32(sz)
 			}
 			format.PgDump.MaxRowSize = maxRowSize
+		case "AVRO":
+			telemetry.Count("import.format.avro")
+			format.Format = roachpb.IOFileFormat_Avro
+			_, format.Avro.StrictMode = opts[recordStrictMode]
+		case "JSONLINES":
+			telemetry.Count("import.format.jsonlines")
+			format.Format = roachpb.IOFileFormat_JSONLines
+			_, format.JsonLines.StrictMode = opts[recordStrictMode]
+			maxRowSize := int32(defaultScanBuffer)
+			if override, ok := opts[pgMaxRowSize]; ok {
+				sz, err := humanizeutil.ParseBytes(override)
+				if err != nil {
+					return err
+				}
+				if sz < 1 || sz > math.MaxInt32 {
+					return errors.Errorf("%s out of range: %d", pgMaxRowSize, sz)
+				}
+				maxRowSize = int32(sz)
+			}
+			format.JsonLines.MaxRowSize = maxRowSize
 		default:
 			return pgerror.Unimplemented("import.format", "unsupported import format: %q", importStmt.FileFormat)
 		}
 
+		if importStmt.Into && isMultiTableFormat(format.Format) {
+			return errors.Errorf("IMPORT INTO does not support the %s format", importStmt.FileFormat)
+		}
+
 		if format.Format != roachpb.IOFileFormat_CSV {
 			if !p.ExecCfg().Settings.Version.IsMinSupported(cluster.VersionImportFormats) {
 				return errors.Errorf("Using %s requires all nodes to be upgraded to %s",
@@ -707,6 +780,7 @@ func importPlanHook(
 		var tableDescs []*sqlbase.TableDescriptor
 		var jobDesc string
 		var names []string
+		var targetCols []string
 		seqVals := make(map[sqlbase.ID]int64)
 		if importStmt.Bundle {
 			store, err := storageccl.ExportStorageFromURI(ctx, files[0], p.ExecCfg().Settings)
@@ -736,7 +810,7 @@ func importPlanHook(
 				tableDescs, err = readMysqlCreateTable(ctx, reader, evalCtx, defaultCSVTableID, parentID, match, fks, seqVals)
 			case roachpb.IOFileFormat_PgDump:
 				evalCtx := &p.ExtendedEvalContext().EvalContext
-				tableDescs, err = readPostgresCreateTable(reader, evalCtx, p.ExecCfg().Settings, match, parentID, walltime, fks, int(format.PgDump.MaxRowSize))
+				tableDescs, err = readPostgresCreateTable(reader, evalCtx, p.Txn(), p.ExecCfg().Settings, match, parentID, walltime, fks, int(format.PgDump.MaxRowSize))
 			default:
 				return errors.Errorf("non-bundle format %q does not support reading schemas", format.Format.String())
 			}
@@ -747,6 +821,39 @@ func importPlanHook(
 				names = []string{table.TableName.String()}
 			}
 
+			descStr, err := importJobDescription(importStmt, nil, files, opts)
+			if err != nil {
+				return err
+			}
+			jobDesc = descStr
+		} else if importStmt.Into {
+			found, descI, err := table.ResolveExisting(ctx, p, p.SessionData().Database, p.SessionData().SearchPath)
+			if err != nil {
+				return err
+			}
+			if !found {
+				return sqlbase.NewUndefinedRelationError(table)
+			}
+			existing := descI.(*sqlbase.TableDescriptor)
+			if !existing.IsTable() {
+				return sqlbase.NewWrongObjectTypeError(table, "table")
+			}
+			if err := checkImportIntoTable(existing); err != nil {
+				return err
+			}
+			for _, name := range importStmt.IntoCols {
+				col, err := existing.FindActiveColumnByName(string(name))
+				if err != nil {
+					return err
+				}
+				for _, prev := range targetCols {
+					if prev == col.Name {
+						return errors.Errorf("multiple values specified for column %q", col.Name)
+					}
+				}
+				targetCols = append(targetCols, col.Name)
+			}
+			tableDescs = []*sqlbase.TableDescriptor{protoutil.Clone(existing).(*sqlbase.TableDescriptor)}
 			descStr, err := importJobDescription(importStmt, nil, files, opts)
 			if err != nil {
 				return err
@@ -776,6 +883,9 @@ func importPlanHook(
 				}
 			}
 
+			if err := resolveUserDefinedTypes(ctx, p.Txn(), parentID, create); err != nil {
+				return err
+			}
 			tbl, err := MakeSimpleTableDescriptor(
 				ctx, p.ExecCfg().Settings, create, parentID, defaultCSVTableID, NoFKs, walltime)
 			if err != nil {
@@ -801,7 +911,7 @@ func importPlanHook(
 				return err
 			}
 			telemetry.Count("import.transform")
-		} else {
+		} else if !importStmt.Into {
 			for _, tableDesc := range tableDescs {
 				if err := backupccl.CheckTableExists(ctx, p.Txn(), parentID, tableDesc.Name); err != nil {
 					return err
@@ -832,12 +942,22 @@ func importPlanHook(
 
 		tableDetails := make([]jobspb.ImportDetails_Table, 0, len(tableDescs))
 		for _, tbl := range tableDescs {
-			tableDetails = append(tableDetails, jobspb.ImportDetails_Table{Desc: tbl, SeqVal: seqVals[tbl.ID]})
+			tableDetails = append(tableDetails, jobspb.ImportDetails_Table{
+				Desc: tbl, SeqVal: seqVals[tbl.ID], Existing: importStmt.Into, TargetCols: targetCols,
+			})
 		}
 		for _, name := range names {
 			tableDetails = append(tableDetails, jobspb.ImportDetails_Table{Name: name})
 		}
 
+		if importStmt.Into {
+			telemetry.Count("import.into")
+			// The data is ingested at a timestamp chosen by the job once the
+			// table has been taken offline, so that no transaction can have read
+			// the table at or after that timestamp.
+			walltime = 0
+		}
+
 		telemetry.CountBucketed("import.files", int64(len(files)))
 
 		_, errCh, err := p.ExecCfg().JobRegistry.StartJob(ctx, resultsCh, jobs.Record{
@@ -871,9 +991,12 @@ func doDistributedCSVTransform(
 	p sql.PlanHookState,
 	parentID sqlbase.ID,
 	tables map[string]*sqlbase.TableDescriptor,
+	targetCols []string,
 	transformOnly string,
 	format roachpb.IOFileFormat,
 	walltime int64,
+	rowIDBase uint64,
+	disallowShadowing bool,
 	sstSize int64,
 	oversample int64,
 ) (roachpb.BulkOpSummary, error) {
@@ -899,10 +1022,13 @@ func doDistributedCSVTransform(
 		job,
 		sql.NewRowResultWriter(rows),
 		tables,
+		targetCols,
 		files,
 		transformOnly,
 		format,
 		walltime,
+		rowIDBase,
+		disallowShadowing,
 		sstSize,
 		oversample,
 		func(descs map[sqlbase.ID]*sqlbase.TableDescriptor) (sql.KeyRewriter, error) {
@@ -1002,6 +1128,169 @@ func doDistributedCSVTransform(
 	return backupDesc.EntryCounts, finalizeCSVBackup(ctx, &backupDesc, parentID, tables, es, p.ExecCfg())
 }
 
+// checkImportIntoTable returns an error if data cannot be imported into the
+// given existing table. Imported rows are encoded directly, without evaluating
+// computed columns or validating CHECK and FOREIGN KEY constraints, so tables
+// that have any of those are rejected.
+func checkImportIntoTable(desc *sqlbase.TableDescriptor) error {
+	if len(desc.Mutations) > 0 {
+		return errors.Errorf("table %q has schema changes in progress", desc.Name)
+	}
+	for _, col := range desc.Columns {
+		if col.IsComputed() {
+			return pgerror.Unimplemented("import.into.computed",
+				"IMPORT INTO a table with computed columns is not supported")
+		}
+	}
+	if len(desc.Checks) > 0 {
+		return pgerror.Unimplemented("import.into.check",
+			"IMPORT INTO a table with CHECK constraints is not supported")
+	}
+	return desc.ForeachNonDropIndex(func(idx *sqlbase.IndexDescriptor) error {
+		if idx.ForeignKey.IsSet() {
+			return pgerror.Unimplemented("import.into.fk",
+				"IMPORT INTO a table with FOREIGN KEY constraints is not supported")
+		}
+		return nil
+	})
+}
+
+// prepareExistingTableForIngestion takes the existing table being imported
+// into offline and waits until no node is using a previous version of it. It
+// then picks the timestamp at which data will be ingested, which is thus
+// later than any read or write of the table, and records it and the offline
+// descriptor in the job details.
+func prepareExistingTableForIngestion(
+	ctx context.Context, job *jobs.Job, p sql.PlanHookState, details *jobspb.ImportDetails,
+) error {
+	tbl := &details.Tables[0]
+	reason := importingReason(*job.ID())
+	leaseMgr := p.ExecCfg().LeaseManager
+	desc, err := leaseMgr.Publish(ctx, tbl.Desc.ID, func(desc *sqlbase.TableDescriptor) error {
+		// A table taken offline by this job before it was interrupted can be
+		// taken offline again, but not one that is offline for another reason,
+		// such as another IMPORT INTO.
+		if desc.Offline() && desc.OfflineReason != reason {
+			return errors.Errorf("table %q is offline: %s", desc.Name, desc.OfflineReason)
+		}
+		if desc.State != sqlbase.TableDescriptor_PUBLIC && !desc.Offline() {
+			return errors.Errorf("table %q is not public", desc.Name)
+		}
+		if err := checkImportIntoTable(desc); err != nil {
+			return err
+		}
+		desc.State = sqlbase.TableDescriptor_OFFLINE
+		desc.OfflineReason = reason
+		return nil
+	}, nil /* logEvent */)
+	if err != nil {
+		return err
+	}
+	if _, err := leaseMgr.WaitForOneVersion(ctx, tbl.Desc.ID, base.DefaultRetryOptions()); err != nil {
+		return err
+	}
+	tbl.Desc = desc.GetTable()
+	// The table is offline, so the row IDs generated by the previous imports
+	// can't change until this job is done with it.
+	if tbl.RowIDBase, err = importIntoRowIDBase(ctx, p.ExecCfg().DB, tbl.Desc); err != nil {
+		return err
+	}
+	details.Walltime = p.ExecCfg().Clock.Now().WallTime
+	return job.SetDetails(ctx, *details)
+}
+
+// importIntoRowIDBase returns the base of the hidden row IDs generated by an
+// IMPORT INTO the given table: one past the largest one generated by the
+// previous imports into it, which are the negative ones (see
+// importIntoRowIDBit). It returns 0 if the table's primary key isn't the
+// hidden row ID.
+func importIntoRowIDBase(
+	ctx context.Context, db *client.DB, desc *sqlbase.TableDescriptor,
+) (uint64, error) {
+	if len(desc.PrimaryIndex.ColumnIDs) != 1 {
+		return 0, nil
+	}
+	col, err := desc.FindColumnByID(desc.PrimaryIndex.ColumnIDs[0])
+	if err != nil {
+		return 0, err
+	}
+	if !col.Hidden {
+		return 0, nil
+	}
+	prefix := roachpb.Key(sqlbase.MakeIndexKeyPrefix(desc, desc.PrimaryIndex.ID))
+	end := roachpb.Key(encoding.EncodeVarintAscending(append([]byte(nil), prefix...), 0))
+	kvs, err := db.ReverseScan(ctx, prefix, end, 1 /* maxRows */)
+	if err != nil {
+		return 0, err
+	}
+	if len(kvs) == 0 {
+		return 1, nil
+	}
+	_, id, err := encoding.DecodeVarintAscending(kvs[0].Key[len(prefix):])
+	if err != nil {
+		return 0, err
+	}
+	return uint64(tree.DInt(id)&^importIntoRowIDBit)>>builtins.NodeIDBits + 1, nil
+}
+
+// importingReason is the offline reason of a table being imported into by
+// the IMPORT INTO job with the given ID.
+func importingReason(jobID int64) string {
+	return fmt.Sprintf("importing (job %d)", jobID)
+}
+
+// publishExistingTable brings the table imported into by the IMPORT INTO job
+// back online, making it, and any data ingested into it, visible again.
+func publishExistingTable(
+	ctx context.Context, txn *client.Txn, id sqlbase.ID, jobID int64,
+) error {
+	desc, err := sqlbase.GetTableDescFromID(ctx, txn, id)
+	if err != nil {
+		return err
+	}
+	if !desc.Offline() || desc.OfflineReason != importingReason(jobID) {
+		return nil
+	}
+	// Needed to trigger the lease manager to pick up the new version.
+	if err := txn.SetSystemConfigTrigger(); err != nil {
+		return err
+	}
+	desc.State = sqlbase.TableDescriptor_PUBLIC
+	desc.OfflineReason = ""
+	desc.Version++
+	desc.ModificationTime = txn.CommitTimestamp()
+	return txn.Put(ctx, sqlbase.MakeDescMetadataKey(desc.ID), sqlbase.WrapDescriptor(desc))
+}
+
+// revertImportedKeys deletes the keys in span that were ingested at walltime
+// by a failed or canceled IMPORT INTO. Since the ingestion of keys colliding
+// with existing ones is disallowed, this restores the previous contents of the
+// table.
+func revertImportedKeys(ctx context.Context, db *client.DB, span roachpb.Span, walltime int64) error {
+	const batchSize = 10000
+	for start := span.Key; ; {
+		kvs, err := db.Scan(ctx, start, span.EndKey, batchSize)
+		if err != nil {
+			return err
+		}
+		var imported []interface{}
+		for _, kv := range kvs {
+			if kv.Value.Timestamp.WallTime == walltime {
+				imported = append(imported, kv.Key)
+			}
+		}
+		if len(imported) > 0 {
+			if err := db.Del(ctx, imported...); err != nil {
+				return err
+			}
+		}
+		if len(kvs) < batchSize {
+			return nil
+		}
+		start = kvs[len(kvs)-1].Key.Next()
+	}
+}
+
 type importResumer struct {
 	settings *cluster.Settings
 	res      roachpb.BulkOpSummary
@@ -1023,6 +1312,24 @@ func (r *importResumer) Resume(
 	format := details.Format
 	oversample := details.Oversample
 
+	// IMPORT INTO an existing table takes the table offline before picking the
+	// timestamp at which to ingest the data. This happens once; a resumed job
+	// finds the timestamp in its details.
+	var existing bool
+	var targetCols []string
+	var rowIDBase uint64
+	if len(details.Tables) == 1 && details.Tables[0].Existing {
+		existing = true
+		targetCols = details.Tables[0].TargetCols
+		if walltime == 0 {
+			if err := prepareExistingTableForIngestion(ctx, job, p, &details); err != nil {
+				return err
+			}
+			walltime = details.Walltime
+		}
+		rowIDBase = details.Tables[0].RowIDBase
+	}
+
 	if sstSize == 0 {
 		// The distributed importer will correctly chunk up large ranges into
 		// multiple ssts that can be imported. In order to reduce the number of
@@ -1049,7 +1356,8 @@ func (r *importResumer) Resume(
 	}
 
 	res, err := doDistributedCSVTransform(
-		ctx, job, files, p, parentID, tables, transform, format, walltime, sstSize, oversample,
+		ctx, job, files, p, parentID, tables, targetCols, transform, format, walltime, rowIDBase,
+		existing /* disallowShadowing */, sstSize, oversample,
 	)
 	if err != nil {
 		return err
@@ -1061,13 +1369,25 @@ func (r *importResumer) Resume(
 // OnFailOrCancel removes KV data that has been committed from a import that
 // has failed or been canceled. It does this by adding the table descriptors
 // in DROP state, which causes the schema change stuff to delete the keys
-// in the background.
+// in the background. For IMPORT INTO an existing table, the imported keys are
+// instead deleted directly and the table is brought back online.
 func (r *importResumer) OnFailOrCancel(ctx context.Context, txn *client.Txn, job *jobs.Job) error {
 	details := job.Details().(jobspb.ImportDetails)
 	if details.BackupPath != "" {
 		return nil
 	}
 
+	if len(details.Tables) == 1 && details.Tables[0].Existing {
+		tableDesc := details.Tables[0].Desc
+		if details.Walltime != 0 {
+			log.Event(ctx, "reverting imported data")
+			if err := revertImportedKeys(ctx, txn.DB(), tableDesc.TableSpan(), details.Walltime); err != nil {
+				return errors.Wrap(err, "reverting imported data")
+			}
+		}
+		return publishExistingTable(ctx, txn, tableDesc.ID, *job.ID())
+	}
+
 	// Needed to trigger the schema change manager.
 	if err := txn.SetSystemConfigTrigger(); err != nil {
 		return err
@@ -1096,6 +1416,10 @@ func (r *importResumer) OnSuccess(ctx context.Context, txn *client.Txn, job *job
 		return nil
 	}
 
+	if len(details.Tables) == 1 && details.Tables[0].Existing {
+		return publishExistingTable(ctx, txn, details.Tables[0].Desc.ID, *job.ID())
+	}
+
 	toWrite := make([]*sqlbase.TableDescriptor, len(details.Tables))
 	var seqs []roachpb.KeyValue
 	for i := range details.Tables {
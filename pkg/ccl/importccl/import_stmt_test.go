@@ -603,6 +603,68 @@ COPY t (a, b, c) FROM stdin;
 			},
 		},
 
+		{
+			name:   "normal",
+			create: `i int primary key, s string, f float, d decimal, b bool, j jsonb, a int[]`,
+			typ:    "JSONLINES",
+			data: `{"i": 1, "s": "one", "f": 1.5, "d": 2.25, "b": true, "j": {"k": [1, "v"]}, "a": [1, null]}
+
+{"s": "two", "i": 2, "j": null, "a": []}
+{"i": 3, "s": null, "f": "NaN", "d": 3.5, "unknown": 4}`,
+			query: map[string][][]string{
+				`SELECT * from t`: {
+					{"1", "one", "1.5", "2.25", "true", `{"k": [1, "v"]}`, "{1,NULL}"},
+					{"2", "two", "NULL", "NULL", "NULL", "NULL", "{}"},
+					{"3", "NULL", "NaN", "3.5", "NULL", "NULL", "NULL"},
+				},
+			},
+		},
+		{
+			name:   "strict unknown field",
+			create: `i int, s string`,
+			typ:    "JSONLINES",
+			with:   `WITH strict_mode`,
+			data:   `{"i": 1, "s": "one", "t": 2}`,
+			err:    `row 1: unknown field "t"`,
+		},
+		{
+			name:   "strict missing field",
+			create: `i int, s string`,
+			typ:    "JSONLINES",
+			with:   `WITH strict_mode`,
+			data:   `{"i": 1}`,
+			err:    `row 1: missing field "s"`,
+		},
+		{
+			name:   "not an object",
+			create: `i int`,
+			typ:    "JSONLINES",
+			data:   `[1]`,
+			err:    `row 1: json: cannot unmarshal array`,
+		},
+		{
+			name:   "bad value",
+			create: `i int`,
+			typ:    "JSONLINES",
+			data:   `{"i": "x"}`,
+			err:    `row 1: parse "i" as`,
+		},
+		{
+			name:   "not avro",
+			create: `i int`,
+			typ:    "AVRO",
+			data:   `{"i": 1}`,
+			err:    `not an avro object container file`,
+		},
+		{
+			name:   "avro length too large",
+			create: `i int`,
+			typ:    "AVRO",
+			// A header with one entry whose key claims to be 1TiB long.
+			data: "Obj\x01\x02\x80\x80\x80\x80\x80\x40",
+			err:  `reading avro header: invalid length: 1099511627776`,
+		},
+
 		// Error
 		{
 			name:   "unsupported import format",
@@ -2138,6 +2200,145 @@ func TestImportMysqlOutfile(t *testing.T) {
 	}
 }
 
+func TestImportAvro(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	dir, cleanupDir := testutils.TempDir(t)
+	defer cleanupDir()
+
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{ExternalIODir: dir})
+	defer s.Stopper().Stop(context.Background())
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	const cols = `a INT PRIMARY KEY, b STRING, c DECIMAL(10, 2), d TIMESTAMP, e INT[], f JSONB, ` +
+		`g BOOL, h DATE, i BYTES, j UUID, k FLOAT`
+	sqlDB.Exec(t, fmt.Sprintf(`CREATE TABLE src (%s)`, cols))
+	sqlDB.Exec(t, `INSERT INTO src VALUES
+		(1, 'one', 1.25, '2018-01-01 00:00:00.123456', ARRAY[1, NULL], '{"a": 1}', true, '2018-01-02',
+			b'\x00\x01', '63616665-6630-3064-6465-616462656566', 1.5),
+		(2, NULL, -3.5, NULL, NULL, NULL, false, NULL, NULL, NULL, NULL),
+		(3, 'three', NULL, '1969-03-01 12:34:56', ARRAY[]:::INT[], '[]', NULL, '1960-01-01', b'', NULL, -0.25)`)
+	expected := sqlDB.QueryStr(t, `SELECT * FROM src ORDER BY a`)
+
+	for i, compression := range []string{"none", "gzip", "snappy"} {
+		t.Run(compression, func(t *testing.T) {
+			sqlDB.Exec(t, fmt.Sprintf(
+				`EXPORT INTO AVRO 'nodelocal:///%s' WITH compression = '%[1]s', chunk_rows = '2' FROM TABLE src`,
+				compression))
+			sqlDB.Exec(t, fmt.Sprintf(
+				`IMPORT TABLE dst%d (%s) AVRO DATA ('nodelocal:///%s/n1.0.avro', 'nodelocal:///%[3]s/n1.1.avro')
+				WITH strict_mode`, i, cols, compression))
+			sqlDB.CheckQueryResults(t, fmt.Sprintf(`SELECT * FROM dst%d ORDER BY a`, i), expected)
+		})
+	}
+
+	t.Run("strict", func(t *testing.T) {
+		sqlDB.Exec(t, `EXPORT INTO AVRO 'nodelocal:///strict' FROM SELECT a, b FROM src`)
+		_, err := db.Exec(`IMPORT TABLE strict (a INT PRIMARY KEY, b STRING, z STRING)
+			AVRO DATA ('nodelocal:///strict/n1.0.avro') WITH strict_mode`)
+		if !testutils.IsError(err, `row 1: missing field "z"`) {
+			t.Fatalf("unexpected: %v", err)
+		}
+		sqlDB.Exec(t, `IMPORT TABLE lenient (a INT PRIMARY KEY, z STRING)
+			AVRO DATA ('nodelocal:///strict/n1.0.avro')`)
+		sqlDB.CheckQueryResults(t, `SELECT * FROM lenient ORDER BY a`,
+			[][]string{{"1", "NULL"}, {"2", "NULL"}, {"3", "NULL"}})
+	})
+}
+
+func TestImportIntoCSV(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	dir, cleanupDir := testutils.TempDir(t)
+	defer cleanupDir()
+
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{ExternalIODir: dir})
+	defer s.Stopper().Stop(context.Background())
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	writeFile := func(name, contents string) string {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0666); err != nil {
+			t.Fatal(err)
+		}
+		return fmt.Sprintf("nodelocal:///%s", name)
+	}
+
+	t.Run("append", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE TABLE t (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO t VALUES (1, 'one'), (2, 'two')`)
+		f := writeFile("append.csv", "3,three\n4,four\n")
+		sqlDB.Exec(t, `IMPORT INTO t CSV DATA ($1)`, f)
+		sqlDB.CheckQueryResults(t, `SELECT * FROM t ORDER BY a`, [][]string{
+			{"1", "one"}, {"2", "two"}, {"3", "three"}, {"4", "four"},
+		})
+		// The table is writable again.
+		sqlDB.Exec(t, `INSERT INTO t VALUES (5, 'five')`)
+	})
+
+	t.Run("target-columns", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE TABLE cols (a INT PRIMARY KEY, b STRING, c INT DEFAULT 7)`)
+		f := writeFile("cols.csv", "one,1\ntwo,2\n")
+		sqlDB.Exec(t, `IMPORT INTO cols (b, a) CSV DATA ($1)`, f)
+		sqlDB.CheckQueryResults(t, `SELECT * FROM cols ORDER BY a`, [][]string{
+			{"1", "one", "7"}, {"2", "two", "7"},
+		})
+
+		if _, err := db.Exec(`IMPORT INTO cols (a, z) CSV DATA ($1)`, f); !testutils.IsError(
+			err, `column "z" does not exist`,
+		) {
+			t.Fatalf("unexpected: %v", err)
+		}
+		if _, err := db.Exec(`IMPORT INTO cols (a, a) CSV DATA ($1)`, f); !testutils.IsError(
+			err, `multiple values specified for column "a"`,
+		) {
+			t.Fatalf("unexpected: %v", err)
+		}
+	})
+
+	t.Run("collision", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE TABLE collide (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO collide VALUES (1, 'one'), (2, 'two')`)
+		f := writeFile("collide.csv", "2,deux\n3,trois\n")
+		if _, err := db.Exec(`IMPORT INTO collide CSV DATA ($1)`, f); !testutils.IsError(
+			err, `ingested key collides with an existing one`,
+		) {
+			t.Fatalf("unexpected: %v", err)
+		}
+		// The failed import is rolled back and the table brought back online.
+		sqlDB.CheckQueryResults(t, `SELECT * FROM collide ORDER BY a`, [][]string{
+			{"1", "one"}, {"2", "two"},
+		})
+		sqlDB.Exec(t, `INSERT INTO collide VALUES (3, 'three')`)
+	})
+
+	t.Run("implicit-primary-key", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE TABLE rowid (a INT, b STRING)`)
+		f := writeFile("rowid.csv", "1,one\n2,two\n")
+		sqlDB.Exec(t, `IMPORT INTO rowid CSV DATA ($1)`, f)
+		sqlDB.Exec(t, `IMPORT INTO rowid CSV DATA ($1)`, f)
+		sqlDB.CheckQueryResults(t, `SELECT a, b, count(*) FROM rowid GROUP BY a, b ORDER BY a`,
+			[][]string{{"1", "one", "2"}, {"2", "two", "2"}})
+		// The imported row IDs are kept apart from those of unique_rowid().
+		sqlDB.Exec(t, `INSERT INTO rowid VALUES (3, 'three')`)
+		sqlDB.CheckQueryResults(t,
+			`SELECT rowid < 0, count(DISTINCT rowid) FROM rowid GROUP BY rowid < 0 ORDER BY 1`,
+			[][]string{{"false", "1"}, {"true", "4"}})
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE TABLE checked (a INT PRIMARY KEY CHECK (a > 0))`)
+		f := writeFile("checked.csv", "1\n")
+		if _, err := db.Exec(`IMPORT INTO checked CSV DATA ($1)`, f); !testutils.IsError(
+			err, `CHECK constraints`,
+		) {
+			t.Fatalf("unexpected: %v", err)
+		}
+		if _, err := db.Exec(`IMPORT INTO missing CSV DATA ($1)`, f); !testutils.IsError(
+			err, `relation "missing" does not exist`,
+		) {
+			t.Fatalf("unexpected: %v", err)
+		}
+	})
+}
+
 func TestImportPgCopy(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
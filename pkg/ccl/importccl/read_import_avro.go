@@ -0,0 +1,548 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"context"
+	"encoding/binary"
+	gojson "encoding/json"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"math"
+	"time"
+
+	"github.com/cockroachdb/apd"
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl/avroccl"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+)
+
+type avroReader struct {
+	conv   rowConverter
+	opts   roachpb.AvroOptions
+	fields recordFieldMapper
+}
+
+var _ inputConverter = &avroReader{}
+
+func newAvroReader(
+	kvCh chan kvBatch,
+	opts roachpb.AvroOptions,
+	tableDesc *sqlbase.TableDescriptor,
+	targetCols []string,
+	rowIDBase uint64,
+	evalCtx *tree.EvalContext,
+) (*avroReader, error) {
+	conv, err := newRowConverter(tableDesc, targetCols, rowIDBase, evalCtx, kvCh)
+	if err != nil {
+		return nil, err
+	}
+	d := &avroReader{
+		conv: *conv,
+		opts: opts,
+	}
+	d.fields = makeRecordFieldMapper(&d.conv, opts.StrictMode)
+	return d, nil
+}
+
+func (d *avroReader) start(ctx ctxgroup.Group) {
+}
+
+func (d *avroReader) inputFinished(ctx context.Context) {
+	close(d.conv.kvCh)
+}
+
+func (d *avroReader) readFile(
+	ctx context.Context, input io.Reader, inputIdx int32, inputName string, progressFn progressFn,
+) error {
+	ocf, err := newAvroOCFReader(input)
+	if err != nil {
+		return errors.Wrapf(err, "%q", inputName)
+	}
+	var count int64
+	for {
+		record, err := ocf.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// The error may come from a block as well as from the record, so
+			// it is reported after the last row read successfully.
+			return errors.Wrapf(err, "%q: after row %d", inputName, count)
+		}
+		count++
+		if err := d.fields.fill(record); err != nil {
+			return makeRowErr(inputName, count, "%s", err)
+		}
+		if err := d.conv.row(ctx, inputIdx, count); err != nil {
+			return makeRowErr(inputName, count, "%s", err)
+		}
+	}
+	return d.conv.sendBatch(ctx)
+}
+
+// avroOCFReader reads the records of an Avro object container file, one block
+// at a time.
+type avroOCFReader struct {
+	r      *bufio.Reader
+	schema *avroSchema
+	codec  string
+	sync   [avroOCFSyncLength]byte
+
+	// block is the undecoded remainder of the current block, which has
+	// remaining records.
+	block     avroDecoder
+	remaining int64
+}
+
+func newAvroOCFReader(input io.Reader) (*avroOCFReader, error) {
+	r := &avroOCFReader{r: bufio.NewReader(input)}
+	var magic [len(avroOCFMagic)]byte
+	if _, err := io.ReadFull(r.r, magic[:]); err != nil {
+		return nil, errors.Wrap(err, "reading avro header")
+	}
+	if string(magic[:]) != avroOCFMagic {
+		return nil, errors.New("not an avro object container file")
+	}
+
+	var rawSchema []byte
+	r.codec = avroOCFCodecNull
+	for {
+		n, err := binary.ReadVarint(r.r)
+		if err != nil {
+			return nil, errors.Wrap(err, "reading avro header")
+		}
+		if n == 0 {
+			break
+		}
+		if n < 0 {
+			// A negative count is followed by the size of the block in bytes.
+			n = -n
+			if _, err := binary.ReadVarint(r.r); err != nil {
+				return nil, errors.Wrap(err, "reading avro header")
+			}
+		}
+		for ; n > 0; n-- {
+			key, err := r.readBytes()
+			if err != nil {
+				return nil, errors.Wrap(err, "reading avro header")
+			}
+			value, err := r.readBytes()
+			if err != nil {
+				return nil, errors.Wrap(err, "reading avro header")
+			}
+			switch string(key) {
+			case avroOCFSchemaKey:
+				rawSchema = value
+			case avroOCFCodecKey:
+				r.codec = string(value)
+			}
+		}
+	}
+	if _, err := io.ReadFull(r.r, r.sync[:]); err != nil {
+		return nil, errors.Wrap(err, "reading avro header")
+	}
+
+	switch r.codec {
+	case avroOCFCodecNull, avroOCFCodecDeflate, avroOCFCodecSnappy:
+	default:
+		return nil, errors.Errorf("unsupported avro codec: %s", r.codec)
+	}
+	if rawSchema == nil {
+		return nil, errors.New("avro file is missing a schema")
+	}
+	var err error
+	if r.schema, err = parseAvroSchema(rawSchema); err != nil {
+		return nil, err
+	}
+	if r.schema.typ != "record" {
+		return nil, errors.Errorf("expected an avro record schema, got %s", r.schema.typ)
+	}
+	return r, nil
+}
+
+// avroOCFMaxBytes bounds the length of the header values and blocks of an
+// Avro object container file, which are read in full.
+const avroOCFMaxBytes = 64 << 20
+
+func (r *avroOCFReader) readBytes() ([]byte, error) {
+	n, err := binary.ReadVarint(r.r)
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 || n > avroOCFMaxBytes {
+		return nil, errors.Errorf("invalid length: %d", n)
+	}
+	// The buffer only grows as the data is read, so a truncated file cannot
+	// make us allocate the whole length it claims.
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, r.r, n); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// next returns the next record in the file, or io.EOF if there are none.
+func (r *avroOCFReader) next() (map[string]interface{}, error) {
+	for r.remaining == 0 {
+		if err := r.readBlock(); err != nil {
+			return nil, err
+		}
+	}
+	r.remaining--
+	v, err := r.block.decode(r.schema)
+	if err != nil {
+		return nil, err
+	}
+	return v.(map[string]interface{}), nil
+}
+
+func (r *avroOCFReader) readBlock() error {
+	count, err := binary.ReadVarint(r.r)
+	if err != nil {
+		if err == io.EOF {
+			return io.EOF
+		}
+		return errors.Wrap(err, "reading avro block")
+	}
+	data, err := r.readBytes()
+	if err != nil {
+		return errors.Wrap(err, "reading avro block")
+	}
+	var sync [avroOCFSyncLength]byte
+	if _, err := io.ReadFull(r.r, sync[:]); err != nil {
+		return errors.Wrap(err, "reading avro block")
+	}
+	if sync != r.sync {
+		return errors.New("avro block has an invalid sync marker")
+	}
+
+	switch r.codec {
+	case avroOCFCodecDeflate:
+		if data, err = ioutil.ReadAll(flate.NewReader(bytes.NewReader(data))); err != nil {
+			return errors.Wrap(err, "decompressing avro block")
+		}
+	case avroOCFCodecSnappy:
+		// The snappy codec is followed by the big-endian CRC32 of the
+		// uncompressed data.
+		if len(data) < 4 {
+			return errors.New("avro snappy block is too short")
+		}
+		checksum := binary.BigEndian.Uint32(data[len(data)-4:])
+		if data, err = snappy.Decode(nil, data[:len(data)-4]); err != nil {
+			return errors.Wrap(err, "decompressing avro block")
+		}
+		if crc32.ChecksumIEEE(data) != checksum {
+			return errors.New("avro snappy block has an invalid checksum")
+		}
+	}
+	r.block = avroDecoder{buf: data}
+	r.remaining = count
+	return nil
+}
+
+// avroSchema is a parsed avro schema. Only the fields relevant to typ are set.
+type avroSchema struct {
+	typ     string
+	logical string
+	scale   int32
+
+	// fields is set for records.
+	fields []avroSchemaField
+	// items is set for arrays and values for maps.
+	items, values *avroSchema
+	// symbols is set for enums.
+	symbols []string
+	// size is set for fixed.
+	size int
+	// union is set for unions, where typ is empty.
+	union []*avroSchema
+}
+
+type avroSchemaField struct {
+	name   string
+	schema *avroSchema
+}
+
+func parseAvroSchema(raw []byte) (*avroSchema, error) {
+	var v interface{}
+	if err := gojson.Unmarshal(raw, &v); err != nil {
+		return nil, errors.Wrap(err, "parsing avro schema")
+	}
+	return makeAvroSchema(v, make(map[string]*avroSchema))
+}
+
+// makeAvroSchema converts a schema decoded from JSON. Named types (records,
+// enums and fixed) are added to named so later references to them resolve.
+func makeAvroSchema(v interface{}, named map[string]*avroSchema) (*avroSchema, error) {
+	switch t := v.(type) {
+	case string:
+		switch t {
+		case "null", "boolean", "int", "long", "float", "double", "bytes", "string":
+			return &avroSchema{typ: t}, nil
+		}
+		if s, ok := named[t]; ok {
+			return s, nil
+		}
+		return nil, errors.Errorf("unknown avro type: %s", t)
+	case []interface{}:
+		s := &avroSchema{}
+		for _, branch := range t {
+			b, err := makeAvroSchema(branch, named)
+			if err != nil {
+				return nil, err
+			}
+			s.union = append(s.union, b)
+		}
+		return s, nil
+	case map[string]interface{}:
+		typ, _ := t["type"].(string)
+		if typ == "" {
+			// The type of a complex schema may itself be a schema.
+			return makeAvroSchema(t["type"], named)
+		}
+		s := &avroSchema{typ: typ}
+		s.logical, _ = t["logicalType"].(string)
+		if scale, ok := t["scale"].(float64); ok {
+			s.scale = int32(scale)
+		}
+		if name, ok := t["name"].(string); ok {
+			named[name] = s
+		}
+		switch typ {
+		case "record":
+			fields, _ := t["fields"].([]interface{})
+			for _, f := range fields {
+				field, _ := f.(map[string]interface{})
+				name, _ := field["name"].(string)
+				fs, err := makeAvroSchema(field["type"], named)
+				if err != nil {
+					return nil, errors.Wrapf(err, "field %q", name)
+				}
+				s.fields = append(s.fields, avroSchemaField{name: name, schema: fs})
+			}
+		case "array":
+			var err error
+			if s.items, err = makeAvroSchema(t["items"], named); err != nil {
+				return nil, err
+			}
+		case "map":
+			var err error
+			if s.values, err = makeAvroSchema(t["values"], named); err != nil {
+				return nil, err
+			}
+		case "enum":
+			symbols, _ := t["symbols"].([]interface{})
+			for _, sym := range symbols {
+				name, _ := sym.(string)
+				s.symbols = append(s.symbols, name)
+			}
+		case "fixed":
+			size, _ := t["size"].(float64)
+			s.size = int(size)
+		case "null", "boolean", "int", "long", "float", "double", "bytes", "string":
+		default:
+			return nil, errors.Errorf("unknown avro type: %s", typ)
+		}
+		return s, nil
+	default:
+		return nil, errors.Errorf("invalid avro schema: %v", v)
+	}
+}
+
+// avroDecoder decodes avro binary encoded values.
+type avroDecoder struct {
+	buf []byte
+}
+
+func (d *avroDecoder) long() (int64, error) {
+	v, n := binary.Varint(d.buf)
+	if n <= 0 {
+		return 0, errors.New("invalid avro long")
+	}
+	d.buf = d.buf[n:]
+	return v, nil
+}
+
+func (d *avroDecoder) bytes() ([]byte, error) {
+	n, err := d.long()
+	if err != nil {
+		return nil, err
+	}
+	return d.fixed(int(n))
+}
+
+func (d *avroDecoder) fixed(n int) ([]byte, error) {
+	if n < 0 || n > len(d.buf) {
+		return nil, errors.New("avro value is truncated")
+	}
+	b := d.buf[:n:n]
+	d.buf = d.buf[n:]
+	return b, nil
+}
+
+// decode decodes a value of the given schema. Records and maps are decoded as
+// map[string]interface{} and arrays as []interface{}. Logical types are decoded
+// as the corresponding time.Time, timeofday.TimeOfDay or *apd.Decimal.
+func (d *avroDecoder) decode(s *avroSchema) (interface{}, error) {
+	if s.union != nil {
+		branch, err := d.long()
+		if err != nil {
+			return nil, err
+		}
+		if branch < 0 || int(branch) >= len(s.union) {
+			return nil, errors.Errorf("invalid avro union branch: %d", branch)
+		}
+		return d.decode(s.union[branch])
+	}
+
+	switch s.typ {
+	case "null":
+		return nil, nil
+	case "boolean":
+		b, err := d.fixed(1)
+		if err != nil {
+			return nil, err
+		}
+		return b[0] != 0, nil
+	case "int", "long":
+		i, err := d.long()
+		if err != nil {
+			return nil, err
+		}
+		switch s.logical {
+		case "date":
+			return time.Unix(i*24*60*60, 0).UTC(), nil
+		case "time-millis":
+			return timeofday.FromInt(i * 1000), nil
+		case "time-micros":
+			return timeofday.FromInt(i), nil
+		case "timestamp-millis":
+			return time.Unix(0, i*int64(time.Millisecond)).UTC(), nil
+		case "timestamp-micros":
+			return time.Unix(0, i*int64(time.Microsecond)).UTC(), nil
+		}
+		return i, nil
+	case "float":
+		b, err := d.fixed(4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(b))), nil
+	case "double":
+		b, err := d.fixed(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(b)), nil
+	case "bytes", "fixed":
+		var b []byte
+		var err error
+		if s.typ == "fixed" {
+			b, err = d.fixed(s.size)
+		} else {
+			b, err = d.bytes()
+		}
+		if err != nil {
+			return nil, err
+		}
+		if s.logical == "decimal" {
+			return apd.NewWithBigInt(avroccl.TwosComplementToBigInt(b), -s.scale), nil
+		}
+		return b, nil
+	case "string":
+		b, err := d.bytes()
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	case "enum":
+		i, err := d.long()
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 || int(i) >= len(s.symbols) {
+			return nil, errors.Errorf("invalid avro enum symbol: %d", i)
+		}
+		return s.symbols[i], nil
+	case "record":
+		record := make(map[string]interface{}, len(s.fields))
+		for _, f := range s.fields {
+			v, err := d.decode(f.schema)
+			if err != nil {
+				return nil, errors.Wrapf(err, "field %q", f.name)
+			}
+			record[f.name] = v
+		}
+		return record, nil
+	case "array":
+		var res []interface{}
+		err := d.blocks(func() error {
+			v, err := d.decode(s.items)
+			res = append(res, v)
+			return err
+		})
+		if res == nil {
+			res = []interface{}{}
+		}
+		return res, err
+	case "map":
+		res := make(map[string]interface{})
+		err := d.blocks(func() error {
+			k, err := d.bytes()
+			if err != nil {
+				return err
+			}
+			v, err := d.decode(s.values)
+			res[string(k)] = v
+			return err
+		})
+		return res, err
+	default:
+		return nil, errors.Errorf("unknown avro type: %s", s.typ)
+	}
+}
+
+// blocks calls fn once for each item of an array or map encoded as a series of
+// blocks.
+func (d *avroDecoder) blocks(fn func() error) error {
+	for {
+		n, err := d.long()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return nil
+		}
+		if n < 0 {
+			// A negative count is followed by the size of the block in bytes.
+			n = -n
+			if _, err := d.long(); err != nil {
+				return err
+			}
+		}
+		for ; n > 0; n-- {
+			if err := fn(); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -31,6 +31,8 @@ type csvInputReader struct {
 	batch        csvRecord
 	opts         roachpb.CSVOptions
 	tableDesc    *sqlbase.TableDescriptor
+	targetCols   []string
+	rowIDBase    uint64
 	expectedCols int
 }
 
@@ -40,14 +42,22 @@ func newCSVInputReader(
 	kvCh chan kvBatch,
 	opts roachpb.CSVOptions,
 	tableDesc *sqlbase.TableDescriptor,
+	targetCols []string,
+	rowIDBase uint64,
 	flowCtx *distsqlrun.FlowCtx,
 ) *csvInputReader {
+	expectedCols := len(tableDesc.VisibleColumns())
+	if len(targetCols) > 0 {
+		expectedCols = len(targetCols)
+	}
 	return &csvInputReader{
 		flowCtx:      flowCtx,
 		opts:         opts,
 		kvCh:         kvCh,
-		expectedCols: len(tableDesc.VisibleColumns()),
+		expectedCols: expectedCols,
 		tableDesc:    tableDesc,
+		targetCols:   targetCols,
+		rowIDBase:    rowIDBase,
 		recordCh:     make(chan csvRecord),
 		batchSize:    500,
 	}
@@ -149,7 +159,7 @@ type csvRecord struct {
 func (c *csvInputReader) convertRecordWorker(ctx context.Context) error {
 	// Create a new evalCtx per converter so each go routine gets its own
 	// collationenv, which can't be accessed in parallel.
-	conv, err := newRowConverter(c.tableDesc, c.flowCtx.NewEvalCtx(), c.kvCh)
+	conv, err := newRowConverter(c.tableDesc, c.targetCols, c.rowIDBase, c.flowCtx.NewEvalCtx(), c.kvCh)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,284 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	gojson "encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/apd"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
+	"github.com/pkg/errors"
+)
+
+type jsonLinesReader struct {
+	conv   rowConverter
+	opts   roachpb.JSONLinesOptions
+	fields recordFieldMapper
+}
+
+var _ inputConverter = &jsonLinesReader{}
+
+func newJSONLinesReader(
+	kvCh chan kvBatch,
+	opts roachpb.JSONLinesOptions,
+	tableDesc *sqlbase.TableDescriptor,
+	targetCols []string,
+	rowIDBase uint64,
+	evalCtx *tree.EvalContext,
+) (*jsonLinesReader, error) {
+	conv, err := newRowConverter(tableDesc, targetCols, rowIDBase, evalCtx, kvCh)
+	if err != nil {
+		return nil, err
+	}
+	d := &jsonLinesReader{
+		conv: *conv,
+		opts: opts,
+	}
+	d.fields = makeRecordFieldMapper(&d.conv, opts.StrictMode)
+	return d, nil
+}
+
+func (d *jsonLinesReader) start(ctx ctxgroup.Group) {
+}
+
+func (d *jsonLinesReader) inputFinished(ctx context.Context) {
+	close(d.conv.kvCh)
+}
+
+func (d *jsonLinesReader) readFile(
+	ctx context.Context, input io.Reader, inputIdx int32, inputName string, progressFn progressFn,
+) error {
+	maxRowSize := int(d.opts.MaxRowSize)
+	if maxRowSize == 0 {
+		maxRowSize = defaultScanBuffer
+	}
+	s := bufio.NewScanner(input)
+	s.Buffer(nil, maxRowSize)
+
+	var count int64
+	for s.Scan() {
+		count++
+		line := bytes.TrimSpace(s.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		dec := gojson.NewDecoder(bytes.NewReader(line))
+		dec.UseNumber()
+		var record map[string]interface{}
+		if err := dec.Decode(&record); err != nil {
+			return makeRowErr(inputName, count, "%s", err)
+		}
+		if dec.More() {
+			return makeRowErr(inputName, count, "unexpected data after JSON object")
+		}
+		if record == nil {
+			return makeRowErr(inputName, count, "expected a JSON object")
+		}
+		if err := d.fields.fill(record); err != nil {
+			return makeRowErr(inputName, count, "%s", err)
+		}
+		if err := d.conv.row(ctx, inputIdx, count); err != nil {
+			return makeRowErr(inputName, count, "%s", err)
+		}
+	}
+	if err := s.Err(); err != nil {
+		if err == bufio.ErrTooLong {
+			err = errors.New("line too long")
+		}
+		return errors.Wrapf(err, "%q: row %d", inputName, count+1)
+	}
+	return d.conv.sendBatch(ctx)
+}
+
+// recordFieldMapper maps the named fields of a record, such as a JSON object or
+// an Avro record, to the visible columns of the table being imported. In
+// strict mode a record must have exactly one field per visible column;
+// otherwise unknown fields are ignored and missing fields are NULL.
+type recordFieldMapper struct {
+	conv   *rowConverter
+	strict bool
+	cols   map[string]int
+	seen   []bool
+}
+
+func makeRecordFieldMapper(conv *rowConverter, strict bool) recordFieldMapper {
+	m := recordFieldMapper{
+		conv:   conv,
+		strict: strict,
+		cols:   make(map[string]int, len(conv.visibleCols)),
+		seen:   make([]bool, len(conv.visibleCols)),
+	}
+	for i, col := range conv.visibleCols {
+		m.cols[col.Name] = i
+	}
+	return m
+}
+
+// fill sets the visible datums of the row converter from the fields of a
+// record.
+func (m *recordFieldMapper) fill(record map[string]interface{}) error {
+	for i := range m.seen {
+		m.seen[i] = false
+		m.conv.datums[i] = tree.DNull
+	}
+	for name, v := range record {
+		idx, ok := m.cols[name]
+		if !ok {
+			if m.strict {
+				return errors.Errorf("unknown field %q", name)
+			}
+			continue
+		}
+		d, err := importNativeToDatum(v, m.conv.visibleColTypes[idx], m.conv.evalCtx)
+		if err != nil {
+			col := m.conv.visibleCols[idx]
+			return errors.Wrapf(err, "parse %q as %s", col.Name, col.Type.SQLString())
+		}
+		m.conv.datums[idx] = d
+		m.seen[idx] = true
+	}
+	if m.strict {
+		for i, seen := range m.seen {
+			if !seen {
+				return errors.Errorf("missing field %q", m.conv.visibleCols[i].Name)
+			}
+		}
+	}
+	return nil
+}
+
+// importNativeToDatum converts a decoded JSON or Avro value to a datum of the
+// given type. Values without a natural mapping to the type are formatted as
+// strings and parsed as the type.
+func importNativeToDatum(v interface{}, typ types.T, evalCtx *tree.EvalContext) (tree.Datum, error) {
+	if v == nil {
+		return tree.DNull, nil
+	}
+	if typ == types.JSON {
+		j, err := json.MakeJSON(importNativeToJSON(v))
+		if err != nil {
+			return nil, err
+		}
+		return tree.NewDJSON(j), nil
+	}
+	if arr, ok := typ.(types.TArray); ok {
+		elems, ok := v.([]interface{})
+		if !ok {
+			if s, ok := v.(string); ok {
+				return tree.ParseStringAs(typ, s, evalCtx)
+			}
+			return nil, errors.Errorf("expected an array, got %T", v)
+		}
+		d := tree.NewDArray(arr.Typ)
+		for _, elem := range elems {
+			e, err := importNativeToDatum(elem, arr.Typ, evalCtx)
+			if err != nil {
+				return nil, err
+			}
+			if err := d.Append(e); err != nil {
+				return nil, err
+			}
+		}
+		return d, nil
+	}
+
+	switch t := v.(type) {
+	case string:
+		return tree.ParseStringAs(typ, t, evalCtx)
+	case bool:
+		if typ == types.Bool {
+			return tree.MakeDBool(tree.DBool(t)), nil
+		}
+		return tree.ParseStringAs(typ, strconv.FormatBool(t), evalCtx)
+	case int64:
+		if typ == types.Int {
+			return tree.NewDInt(tree.DInt(t)), nil
+		}
+		return tree.ParseStringAs(typ, strconv.FormatInt(t, 10), evalCtx)
+	case float64:
+		if typ == types.Float {
+			return tree.NewDFloat(tree.DFloat(t)), nil
+		}
+		return tree.ParseStringAs(typ, strconv.FormatFloat(t, 'g', -1, 64), evalCtx)
+	case gojson.Number:
+		return tree.ParseStringAs(typ, string(t), evalCtx)
+	case []byte:
+		if typ == types.Bytes {
+			return tree.NewDBytes(tree.DBytes(t)), nil
+		}
+		return tree.ParseStringAs(typ, string(t), evalCtx)
+	case *apd.Decimal:
+		if typ == types.Decimal {
+			d := &tree.DDecimal{}
+			d.Set(t)
+			return d, nil
+		}
+		return tree.ParseStringAs(typ, t.String(), evalCtx)
+	case time.Time:
+		switch typ {
+		case types.Timestamp:
+			return tree.MakeDTimestamp(t, time.Microsecond), nil
+		case types.TimestampTZ:
+			return tree.MakeDTimestampTZ(t, time.Microsecond), nil
+		case types.Date:
+			return tree.NewDDateFromTime(t, time.UTC), nil
+		}
+		return tree.ParseStringAs(typ, t.Format(time.RFC3339Nano), evalCtx)
+	case timeofday.TimeOfDay:
+		if typ == types.Time {
+			return tree.MakeDTime(t), nil
+		}
+		return tree.ParseStringAs(typ, t.String(), evalCtx)
+	default:
+		return nil, errors.Errorf("unsupported value of type %T", v)
+	}
+}
+
+// importNativeToJSON converts a decoded JSON or Avro value to one accepted by
+// json.MakeJSON.
+func importNativeToJSON(v interface{}) interface{} {
+	switch t := v.(type) {
+	case []interface{}:
+		res := make([]interface{}, len(t))
+		for i := range t {
+			res[i] = importNativeToJSON(t[i])
+		}
+		return res
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(t))
+		for k, e := range t {
+			res[k] = importNativeToJSON(e)
+		}
+		return res
+	case []byte:
+		return string(t)
+	case *apd.Decimal:
+		return gojson.Number(t.String())
+	case float32:
+		return float64(t)
+	case time.Time:
+		return t.Format(time.RFC3339Nano)
+	case timeofday.TimeOfDay:
+		return t.String()
+	default:
+		return v
+	}
+}
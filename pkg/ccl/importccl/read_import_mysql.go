@@ -60,7 +60,7 @@ func newMysqldumpReader(
 			converters[name] = nil
 			continue
 		}
-		conv, err := newRowConverter(table, evalCtx, kvCh)
+		conv, err := newRowConverter(table, nil /* targetCols */, 0 /* rowIDBase */, evalCtx, kvCh)
 		if err != nil {
 			return nil, err
 		}
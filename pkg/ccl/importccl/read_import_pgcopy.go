@@ -39,9 +39,11 @@ func newPgCopyReader(
 	kvCh chan kvBatch,
 	opts roachpb.PgCopyOptions,
 	tableDesc *sqlbase.TableDescriptor,
+	targetCols []string,
+	rowIDBase uint64,
 	evalCtx *tree.EvalContext,
 ) (*pgCopyReader, error) {
-	conv, err := newRowConverter(tableDesc, evalCtx, kvCh)
+	conv, err := newRowConverter(tableDesc, targetCols, rowIDBase, evalCtx, kvCh)
 	if err != nil {
 		return nil, err
 	}
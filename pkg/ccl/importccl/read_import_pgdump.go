@@ -16,6 +16,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql"
@@ -205,10 +206,12 @@ func removeDefaultRegclass(create *tree.CreateTable) {
 }
 
 // readPostgresCreateTable returns table descriptors for all tables or the
-// matching table from SQL statements.
+// matching table from SQL statements. The types used by the tables are
+// resolved in txn.
 func readPostgresCreateTable(
 	input io.Reader,
 	evalCtx *tree.EvalContext,
+	txn *client.Txn,
 	settings *cluster.Settings,
 	match string,
 	parentID sqlbase.ID,
@@ -253,6 +256,9 @@ func readPostgresCreateTable(
 					continue
 				}
 				removeDefaultRegclass(create)
+				if err := resolveUserDefinedTypes(evalCtx.Ctx(), txn, parentID, create); err != nil {
+					return nil, err
+				}
 				id := sqlbase.ID(int(defaultCSVTableID) + len(ret))
 				desc, err := MakeSimpleTableDescriptor(evalCtx.Ctx(), settings, create, parentID, id, fks, walltime)
 				if err != nil {
@@ -372,10 +378,41 @@ func readPostgresCreateTable(
 			if match == "" || match == name {
 				createSeq[name] = stmt
 			}
+		case *tree.CreateType:
+			if err := checkPostgresEnumType(evalCtx.Ctx(), txn, parentID, stmt); err != nil {
+				return nil, err
+			}
 		}
 	}
 }
 
+// checkPostgresEnumType checks that an enum type of the dump exists in the
+// database the tables are imported into, with the same labels in the same
+// order. IMPORT does not create types.
+func checkPostgresEnumType(
+	ctx context.Context, txn *client.Txn, parentID sqlbase.ID, create *tree.CreateType,
+) error {
+	name, err := getTableName(create.Name)
+	if err != nil {
+		return err
+	}
+	desc, err := sql.ResolveTypeDesc(ctx, txn, parentID, name)
+	if err != nil {
+		return errors.Wrapf(err, "type %q must be created before IMPORT", name)
+	}
+	labels := make([]string, len(desc.EnumMembers))
+	same := len(labels) == len(create.EnumLabels)
+	for i := range desc.EnumMembers {
+		labels[i] = desc.EnumMembers[i].LogicalRepresentation
+		same = same && labels[i] == create.EnumLabels[i]
+	}
+	if !same {
+		return errors.Errorf("type %q has labels (%s), but the dump defines it with labels (%s)",
+			name, strings.Join(labels, ", "), strings.Join(create.EnumLabels, ", "))
+	}
+	return nil
+}
+
 func getTableName(n tree.NormalizableTableName) (string, error) {
 	tn, err := n.Normalize()
 	if err != nil {
@@ -409,7 +446,7 @@ func newPgDumpReader(
 	converters := make(map[string]*rowConverter, len(descs))
 	for name, desc := range descs {
 		if desc.IsTable() {
-			conv, err := newRowConverter(desc, evalCtx, kvCh)
+			conv, err := newRowConverter(desc, nil /* targetCols */, 0 /* rowIDBase */, evalCtx, kvCh)
 			if err != nil {
 				return nil, err
 			}
@@ -14,6 +14,7 @@ import (
 	"context"
 	"io"
 	"io/ioutil"
+	"math"
 	"math/rand"
 	"strings"
 	"sync"
@@ -34,6 +35,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/golang/snappy"
 	"github.com/pkg/errors"
 )
 
@@ -153,6 +155,8 @@ func decompressingReader(
 		return gzip.NewReader(in)
 	case roachpb.IOFileFormat_Bzip:
 		return ioutil.NopCloser(bzip2.NewReader(in)), nil
+	case roachpb.IOFileFormat_Snappy:
+		return ioutil.NopCloser(snappy.NewReader(in)), nil
 	default:
 		return ioutil.NopCloser(in), nil
 	}
@@ -197,6 +201,9 @@ type rowConverter struct {
 	batchCap int
 
 	tableDesc *sqlbase.TableDescriptor
+	// rowIDBase, when importing into an existing table, is added to the row
+	// number when generating the hidden row ID (see row).
+	rowIDBase uint64
 
 	// The rest of these are derived from tableDesc, just cached here.
 	hidden                int
@@ -211,39 +218,80 @@ type rowConverter struct {
 
 const kvBatchSize = 1000
 
+// importIntoRowIDBit is set in the hidden row IDs generated when importing
+// into an existing table. unique_rowid() won't set it until its timestamps
+// overflow 48 bits, so these row IDs can't collide with those generated by
+// inserts, before or after the import.
+const importIntoRowIDBit = tree.DInt(math.MinInt64)
+
+// newRowConverter returns a rowConverter for the given table. If targetCols is
+// non-empty, the input provides values only for the named columns, in that
+// order, and the remaining columns are filled with their default values;
+// otherwise the input provides values for all visible columns. A non-zero
+// rowIDBase indicates an import into an existing table, whose previous imports
+// generated row IDs below it (see importIntoRowIDBase).
 func newRowConverter(
-	tableDesc *sqlbase.TableDescriptor, evalCtx *tree.EvalContext, kvCh chan<- kvBatch,
+	tableDesc *sqlbase.TableDescriptor,
+	targetCols []string,
+	rowIDBase uint64,
+	evalCtx *tree.EvalContext,
+	kvCh chan<- kvBatch,
 ) (*rowConverter, error) {
 	c := &rowConverter{
 		tableDesc: tableDesc,
 		kvCh:      kvCh,
 		evalCtx:   evalCtx,
+		rowIDBase: rowIDBase,
+	}
+
+	c.visibleCols = tableDesc.VisibleColumns()
+	if len(targetCols) > 0 {
+		c.visibleCols = make([]sqlbase.ColumnDescriptor, len(targetCols))
+		for i, name := range targetCols {
+			col, _, err := tableDesc.FindColumnByName(tree.Name(name))
+			if err != nil {
+				return nil, err
+			}
+			c.visibleCols[i] = col
+		}
+	}
+	c.visibleColTypes = make([]types.T, len(c.visibleCols))
+	for i := range c.visibleCols {
+		c.visibleColTypes[i] = c.visibleCols[i].DatumType()
+	}
+
+	// The columns provided by the input come first, followed by any others
+	// (including the hidden _rowid one), which are filled with their defaults.
+	insertCols := append([]sqlbase.ColumnDescriptor(nil), c.visibleCols...)
+	provided := make(map[sqlbase.ColumnID]struct{}, len(c.visibleCols))
+	for _, col := range c.visibleCols {
+		provided[col.ID] = struct{}{}
+	}
+	for _, col := range tableDesc.Columns {
+		if _, ok := provided[col.ID]; !ok {
+			insertCols = append(insertCols, col)
+		}
 	}
 
 	ri, err := sqlbase.MakeRowInserter(nil /* txn */, tableDesc, nil, /* fkTables */
-		tableDesc.Columns, false /* checkFKs */, &sqlbase.DatumAlloc{})
+		insertCols, false /* checkFKs */, &sqlbase.DatumAlloc{})
 	if err != nil {
 		return nil, errors.Wrap(err, "make row inserter")
 	}
 	c.ri = ri
 
 	var txCtx transform.ExprTransformContext
-	// Although we don't yet support DEFAULT expressions on visible columns,
-	// we do on hidden columns (which is only the default _rowid one). This
+	// Although we don't yet support DEFAULT expressions on visible columns of a
+	// new table, we do on hidden columns (which is only the default _rowid one)
+	// and on the columns of an existing table not provided by the input. This
 	// allows those expressions to run.
-	cols, defaultExprs, err := sqlbase.ProcessDefaultColumns(tableDesc.Columns, tableDesc, &txCtx, c.evalCtx)
+	cols, defaultExprs, err := sqlbase.ProcessDefaultColumns(insertCols, tableDesc, &txCtx, c.evalCtx)
 	if err != nil {
 		return nil, errors.Wrap(err, "process default columns")
 	}
 	c.cols = cols
 	c.defaultExprs = defaultExprs
-
-	c.visibleCols = tableDesc.VisibleColumns()
-	c.visibleColTypes = make([]types.T, len(c.visibleCols))
-	for i := range c.visibleCols {
-		c.visibleColTypes[i] = c.visibleCols[i].DatumType()
-	}
-	c.datums = make([]tree.Datum, len(c.visibleCols), len(cols))
+	c.datums = make([]tree.Datum, len(cols))
 
 	// Check for a hidden column. This should be the unique_rowid PK if present.
 	c.hidden = -1
@@ -253,11 +301,10 @@ func newRowConverter(
 				return nil, errors.New("unexpected hidden column")
 			}
 			c.hidden = i
-			c.datums = append(c.datums, nil)
 		}
 	}
-	if len(c.datums) != len(cols) {
-		return nil, errors.New("unexpected hidden column")
+	if len(cols) != len(insertCols) {
+		return nil, errors.New("unexpected mutation column")
 	}
 
 	padding := 2 * (len(tableDesc.Indexes) + len(tableDesc.Families))
@@ -286,7 +333,32 @@ func (c *rowConverter) row(ctx context.Context, fileIndex int32, rowIndex int64)
 		// number in the node id portion. The 15 bits in that portion should account
 		// for up to 32k CSV files in a single IMPORT. In the case of > 32k files,
 		// the data is xor'd so the final bits are flipped instead of set.
-		c.datums[c.hidden] = tree.NewDInt(builtins.GenerateUniqueID(fileIndex, uint64(rowIndex)))
+		//
+		// When importing into an existing table, its existing rows, including
+		// those added by earlier imports, may already use these numbers. The
+		// line numbers are instead offset by a base above those used by the
+		// earlier imports, and the top bit is set to stay clear of the numbers
+		// used by unique_rowid(), whether before or after this import.
+		id := builtins.GenerateUniqueID(fileIndex, c.rowIDBase+uint64(rowIndex))
+		if c.rowIDBase != 0 {
+			id |= importIntoRowIDBit
+		}
+		c.datums[c.hidden] = tree.NewDInt(id)
+	}
+	// Fill in the defaults of any other columns not provided by the input.
+	for i := len(c.visibleCols); i < len(c.cols); i++ {
+		if i == c.hidden {
+			continue
+		}
+		if c.defaultExprs == nil {
+			c.datums[i] = tree.DNull
+			continue
+		}
+		d, err := c.defaultExprs[i].Eval(c.evalCtx)
+		if err != nil {
+			return errors.Wrapf(err, "default expression for column %s", c.cols[i].Name)
+		}
+		c.datums[i] = d
 	}
 
 	// TODO(justin): we currently disallow computed columns in import statements.
@@ -426,15 +498,19 @@ func (cp *readImportDataProcessor) doRun(ctx context.Context, wg *sync.WaitGroup
 	var err error
 	switch cp.spec.Format.Format {
 	case roachpb.IOFileFormat_CSV:
-		conv = newCSVInputReader(kvCh, cp.spec.Format.Csv, singleTable, cp.flowCtx)
+		conv = newCSVInputReader(kvCh, cp.spec.Format.Csv, singleTable, cp.spec.TargetCols, cp.spec.RowIDBase, cp.flowCtx)
 	case roachpb.IOFileFormat_MysqlOutfile:
-		conv, err = newMysqloutfileReader(kvCh, cp.spec.Format.MysqlOut, singleTable, evalCtx)
+		conv, err = newMysqloutfileReader(kvCh, cp.spec.Format.MysqlOut, singleTable, cp.spec.TargetCols, cp.spec.RowIDBase, evalCtx)
 	case roachpb.IOFileFormat_Mysqldump:
 		conv, err = newMysqldumpReader(kvCh, cp.spec.Tables, evalCtx)
 	case roachpb.IOFileFormat_PgCopy:
-		conv, err = newPgCopyReader(kvCh, cp.spec.Format.PgCopy, singleTable, evalCtx)
+		conv, err = newPgCopyReader(kvCh, cp.spec.Format.PgCopy, singleTable, cp.spec.TargetCols, cp.spec.RowIDBase, evalCtx)
 	case roachpb.IOFileFormat_PgDump:
 		conv, err = newPgDumpReader(kvCh, cp.spec.Format.PgDump, cp.spec.Tables, evalCtx)
+	case roachpb.IOFileFormat_Avro:
+		conv, err = newAvroReader(kvCh, cp.spec.Format.Avro, singleTable, cp.spec.TargetCols, cp.spec.RowIDBase, evalCtx)
+	case roachpb.IOFileFormat_JSONLines:
+		conv, err = newJSONLinesReader(kvCh, cp.spec.Format.JsonLines, singleTable, cp.spec.TargetCols, cp.spec.RowIDBase, evalCtx)
 	default:
 		err = errors.Errorf("Requested IMPORT format (%d) not supported by this node", cp.spec.Format.Format)
 	}
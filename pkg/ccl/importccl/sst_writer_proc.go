@@ -192,7 +192,9 @@ func (sp *sstWriter) Run(ctx context.Context, wg *sync.WaitGroup) {
 							// throughput.
 							log.Errorf(ctx, "failed to scatter span %s: %s", roachpb.PrettyPrintKey(nil, end), pErr)
 						}
-						if err := storageccl.AddSSTable(ctx, sp.db, sst.span.Key, sst.span.EndKey, sst.data); err != nil {
+						if err := storageccl.AddSSTable(
+							ctx, sp.db, sst.span.Key, sst.span.EndKey, sst.data, sp.spec.DisallowShadowing,
+						); err != nil {
 							return err
 						}
 					} else {
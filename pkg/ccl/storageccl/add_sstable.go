@@ -9,6 +9,7 @@
 package storageccl
 
 import (
+	"bytes"
 	"context"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl/engineccl"
@@ -21,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage/storagebase"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/pkg/errors"
 )
 
@@ -41,6 +43,12 @@ func evalAddSSTable(
 	// defer tracing.FinishSpan(span)
 	log.Eventf(ctx, "evaluating AddSSTable [%s,%s)", mvccStartKey.Key, mvccEndKey.Key)
 
+	if args.DisallowShadowing {
+		if err := checkForKeyCollisions(batch, args.Data, mvccEndKey); err != nil {
+			return result.Result{}, errors.Wrap(err, "checking for key collisions")
+		}
+	}
+
 	// Compute the stats for any existing data in the affected span. The sstable
 	// being ingested can overwrite all, some, or none of the existing kvs.
 	// (Note: the expected case is that it's none or, in the case of a retry of
@@ -131,3 +139,70 @@ func verifySSTable(
 	}
 	return stats, nil
 }
+
+// checkForKeyCollisions returns an error if any key in the sstable collides
+// with an existing live key, i.e. if ingesting the sstable would shadow data
+// that is already present. Existing deletion tombstones are not collisions,
+// nor are existing keys identical (in timestamp and value) to the ingested
+// ones, so that a retried request is idempotent. An existing intent on a key
+// results in a WriteIntentError.
+func checkForKeyCollisions(batch engine.Reader, data []byte, end engine.MVCCKey) error {
+	dataIter, err := engineccl.NewMemSSTIterator(data, false)
+	if err != nil {
+		return err
+	}
+	defer dataIter.Close()
+
+	existingIter := batch.NewIterator(engine.IterOptions{UpperBound: end.Key})
+	defer existingIter.Close()
+
+	var prevKey roachpb.Key
+	for dataIter.Seek(engine.MVCCKey{Key: keys.MinKey}); ; dataIter.Next() {
+		if ok, err := dataIter.Valid(); err != nil {
+			return err
+		} else if !ok {
+			return nil
+		}
+		sstKey := dataIter.UnsafeKey()
+		if prevKey != nil && sstKey.Key.Equal(prevKey) {
+			// Only the newest version of each key needs to be checked.
+			continue
+		}
+		prevKey = append(prevKey[:0], sstKey.Key...)
+
+		existingIter.Seek(engine.MakeMVCCMetadataKey(sstKey.Key))
+		if ok, err := existingIter.Valid(); err != nil {
+			return err
+		} else if !ok {
+			continue
+		}
+		existingKey := existingIter.UnsafeKey()
+		if !existingKey.Key.Equal(sstKey.Key) {
+			continue
+		}
+		existingValue := existingIter.UnsafeValue()
+
+		if !existingKey.IsValue() {
+			var meta enginepb.MVCCMetadata
+			if err := protoutil.Unmarshal(existingValue, &meta); err != nil {
+				return err
+			}
+			if meta.Txn != nil {
+				return &roachpb.WriteIntentError{Intents: []roachpb.Intent{{
+					Span: roachpb.Span{Key: append(roachpb.Key(nil), sstKey.Key...)},
+					Txn:  *meta.Txn,
+				}}}
+			}
+			return errors.Errorf("ingested key collides with an existing inline value: %s", sstKey.Key)
+		}
+		if len(existingValue) == 0 {
+			// The newest existing version is a deletion tombstone.
+			continue
+		}
+		if existingKey.Timestamp == sstKey.Timestamp &&
+			bytes.Equal(existingValue, dataIter.UnsafeValue()) {
+			continue
+		}
+		return errors.Errorf("ingested key collides with an existing one: %s", sstKey.Key)
+	}
+}
@@ -90,13 +90,13 @@ func runTestDBAddSSTable(ctx context.Context, t *testing.T, db *client.DB, store
 
 		// Key is before the range in the request span.
 		if err := db.AddSSTable(
-			ctx, "d", "e", data,
+			ctx, "d", "e", data, false, /* disallowShadowing */
 		); !testutils.IsError(err, "not in request range") {
 			t.Fatalf("expected request range error got: %+v", err)
 		}
 		// Key is after the range in the request span.
 		if err := db.AddSSTable(
-			ctx, "a", "b", data,
+			ctx, "a", "b", data, false, /* disallowShadowing */
 		); !testutils.IsError(err, "not in request range") {
 			t.Fatalf("expected request range error got: %+v", err)
 		}
@@ -104,7 +104,7 @@ func runTestDBAddSSTable(ctx context.Context, t *testing.T, db *client.DB, store
 		// Do an initial ingest.
 		ingestCtx, collect, cancel := tracing.ContextWithRecordingSpan(ctx, "test-recording")
 		defer cancel()
-		if err := db.AddSSTable(ingestCtx, "b", "c", data); err != nil {
+		if err := db.AddSSTable(ingestCtx, "b", "c", data, false /* disallowShadowing */); err != nil {
 			t.Fatalf("%+v", err)
 		}
 		formatted := tracing.FormatRecordedSpans(collect())
@@ -145,7 +145,7 @@ func runTestDBAddSSTable(ctx context.Context, t *testing.T, db *client.DB, store
 			t.Fatalf("%+v", err)
 		}
 
-		if err := db.AddSSTable(ctx, "b", "c", data); err != nil {
+		if err := db.AddSSTable(ctx, "b", "c", data, false /* disallowShadowing */); err != nil {
 			t.Fatalf("%+v", err)
 		}
 		if r, err := db.Get(ctx, "bb"); err != nil {
@@ -180,7 +180,7 @@ func runTestDBAddSSTable(ctx context.Context, t *testing.T, db *client.DB, store
 			ingestCtx, collect, cancel := tracing.ContextWithRecordingSpan(ctx, "test-recording")
 			defer cancel()
 
-			if err := db.AddSSTable(ingestCtx, "b", "c", data); err != nil {
+			if err := db.AddSSTable(ingestCtx, "b", "c", data, false /* disallowShadowing */); err != nil {
 				t.Fatalf("%+v", err)
 			}
 			if err := testutils.MatchInOrder(tracing.FormatRecordedSpans(collect()),
@@ -216,6 +216,42 @@ func runTestDBAddSSTable(ctx context.Context, t *testing.T, db *client.DB, store
 		}
 	}
 
+	// With shadowing disallowed, ingesting a key that collides with an existing
+	// live key is an error, while new keys and identical retries are not.
+	{
+		key := engine.MVCCKey{Key: []byte("bb"), Timestamp: hlc.Timestamp{WallTime: 3}}
+		data, err := singleKVSSTable(key, roachpb.MakeValueFromString("4").RawBytes)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		if err := db.AddSSTable(
+			ctx, "b", "c", data, true, /* disallowShadowing */
+		); !testutils.IsError(err, "ingested key collides with an existing one") {
+			t.Fatalf("expected key collision error got: %+v", err)
+		}
+		if r, err := db.Get(ctx, "bb"); err != nil {
+			t.Fatalf("%+v", err)
+		} else if expected := []byte("1"); !bytes.Equal(expected, r.ValueBytes()) {
+			t.Errorf("expected %q, got %q", expected, r.ValueBytes())
+		}
+
+		key = engine.MVCCKey{Key: []byte("bd"), Timestamp: hlc.Timestamp{WallTime: 1}}
+		data, err = singleKVSSTable(key, roachpb.MakeValueFromString("5").RawBytes)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		for i := 0; i < 2; i++ {
+			if err := db.AddSSTable(ctx, "b", "c", data, true /* disallowShadowing */); err != nil {
+				t.Fatalf("%+v", err)
+			}
+		}
+		if r, err := db.Get(ctx, "bd"); err != nil {
+			t.Fatalf("%+v", err)
+		} else if expected := []byte("5"); !bytes.Equal(expected, r.ValueBytes()) {
+			t.Errorf("expected %q, got %q", expected, r.ValueBytes())
+		}
+	}
+
 	// Invalid key/value entry checksum.
 	{
 		key := engine.MVCCKey{Key: []byte("bb"), Timestamp: hlc.Timestamp{WallTime: 1}}
@@ -226,7 +262,9 @@ func runTestDBAddSSTable(ctx context.Context, t *testing.T, db *client.DB, store
 			t.Fatalf("%+v", err)
 		}
 
-		if err := db.AddSSTable(ctx, "b", "c", data); !testutils.IsError(err, "invalid checksum") {
+		if err := db.AddSSTable(
+			ctx, "b", "c", data, false, /* disallowShadowing */
+		); !testutils.IsError(err, "invalid checksum") {
 			t.Fatalf("expected 'invalid checksum' error got: %+v", err)
 		}
 	}
@@ -0,0 +1,122 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package storageccl
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// Encrypted files are laid out as follows:
+//
+//	encryptionPreamble | encryptionVersion | nonce | ciphertext
+//
+// where the ciphertext is the file contents sealed with AES-256 in GCM mode,
+// which both encrypts and authenticates them.
+const (
+	encryptionPreamble = "encrypt"
+	encryptionVersion  = 1
+	encryptionSaltSize = 16
+	// EncryptionKeySize is the size of the keys used to encrypt files. It
+	// selects AES-256.
+	EncryptionKeySize = 32
+	// kdfIterations is the number of PBKDF2 iterations used to derive a key
+	// from a passphrase.
+	kdfIterations = 64000
+)
+
+const headerSize = len(encryptionPreamble) + 1
+
+// ErrWrongEncryptionKey is returned when decrypting a file with a key that is
+// not the one it was encrypted with.
+var ErrWrongEncryptionKey = errors.New("wrong encryption passphrase or key")
+
+// GenerateSalt returns a new random salt for GenerateKey.
+func GenerateSalt() ([]byte, error) {
+	salt := make([]byte, encryptionSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+// GenerateKey derives an encryption key from the given secret, such as a
+// passphrase, and salt.
+func GenerateKey(secret, salt []byte) []byte {
+	return pbkdf2.Key(secret, salt, kdfIterations, EncryptionKeySize, sha256.New)
+}
+
+// AppearsEncrypted returns true if the given file contents look like they
+// were written by EncryptFile.
+func AppearsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(encryptionPreamble))
+}
+
+// EncryptFile encrypts and authenticates the given file contents with key.
+func EncryptFile(plaintext, key []byte) ([]byte, error) {
+	gcm, err := aesgcm(key)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext := make([]byte, headerSize+gcm.NonceSize(), headerSize+gcm.NonceSize()+len(plaintext)+gcm.Overhead())
+	copy(ciphertext, encryptionPreamble)
+	ciphertext[len(encryptionPreamble)] = encryptionVersion
+	nonce := ciphertext[headerSize:]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(ciphertext, nonce, plaintext, nil), nil
+}
+
+// DecryptFile decrypts file contents written by EncryptFile with the given
+// key. It returns ErrWrongEncryptionKey if the contents were encrypted with a
+// different key or were tampered with.
+func DecryptFile(ciphertext, key []byte) ([]byte, error) {
+	if !AppearsEncrypted(ciphertext) {
+		return nil, errors.New("file does not appear to be encrypted")
+	}
+	if len(ciphertext) < headerSize {
+		return nil, errors.New("invalid encryption header")
+	}
+	if v := ciphertext[len(encryptionPreamble)]; v != encryptionVersion {
+		return nil, errors.Errorf("unexpected encryption version %d", v)
+	}
+	gcm, err := aesgcm(key)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext = ciphertext[headerSize:]
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("invalid encryption header")
+	}
+	nonce, ciphertext := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		// GCM does not distinguish a wrong key from corrupted contents.
+		return nil, ErrWrongEncryptionKey
+	}
+	return plaintext, nil
+}
+
+func aesgcm(key []byte) (cipher.AEAD, error) {
+	if len(key) != EncryptionKeySize {
+		return nil, errors.Errorf("invalid encryption key size %d, expected %d", len(key), EncryptionKeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
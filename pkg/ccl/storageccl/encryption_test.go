@@ -0,0 +1,85 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package storageccl
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	salt, err := GenerateSalt()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := GenerateKey([]byte("hunter2"), salt)
+	if len(key) != EncryptionKeySize {
+		t.Fatalf("expected a %d byte key, got %d", EncryptionKeySize, len(key))
+	}
+	if !bytes.Equal(key, GenerateKey([]byte("hunter2"), salt)) {
+		t.Fatal("expected key derivation to be deterministic")
+	}
+
+	for _, plaintext := range [][]byte{nil, []byte("a"), bytes.Repeat([]byte("data"), 1<<16)} {
+		ciphertext, err := EncryptFile(plaintext, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !AppearsEncrypted(ciphertext) {
+			t.Fatal("expected ciphertext to appear encrypted")
+		}
+		if len(plaintext) > 0 && bytes.Contains(ciphertext, plaintext) {
+			t.Fatal("expected ciphertext to not contain the plaintext")
+		}
+		decrypted, err := DecryptFile(ciphertext, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(decrypted, plaintext) {
+			t.Fatalf("expected %q, got %q", plaintext, decrypted)
+		}
+	}
+
+	ciphertext, err := EncryptFile([]byte("secret"), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherSalt, err := GenerateSalt()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := append([]byte(nil), ciphertext...)
+	tampered[len(tampered)-1] ^= 1
+
+	for _, tc := range []struct {
+		name       string
+		ciphertext []byte
+		key        []byte
+		err        string
+	}{
+		{"wrong passphrase", ciphertext, GenerateKey([]byte("hunter3"), salt), "wrong encryption passphrase or key"},
+		{"wrong salt", ciphertext, GenerateKey([]byte("hunter2"), otherSalt), "wrong encryption passphrase or key"},
+		{"tampered", tampered, key, "wrong encryption passphrase or key"},
+		{"truncated", ciphertext[:headerSize+2], key, "invalid encryption header"},
+		{"plaintext", []byte("secret"), key, "does not appear to be encrypted"},
+		{"short key", ciphertext, key[:16], "invalid encryption key size"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := DecryptFile(tc.ciphertext, tc.key); !testutils.IsError(err, tc.err) {
+				t.Fatalf("expected %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
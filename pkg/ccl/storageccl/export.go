@@ -228,7 +228,15 @@ func evalExport(
 
 	if exportStore != nil {
 		exported.Path = fmt.Sprintf("%d.sst", builtins.GenerateUniqueInt(cArgs.EvalCtx.NodeID()))
-		if err := exportStore.WriteFile(ctx, exported.Path, bytes.NewReader(sstContents)); err != nil {
+		data := sstContents
+		if args.Encryption != nil {
+			// The checksum is of the plaintext, which is verified after it is
+			// decrypted.
+			if data, err = EncryptFile(sstContents, args.Encryption.Key); err != nil {
+				return result.Result{}, err
+			}
+		}
+		if err := exportStore.WriteFile(ctx, exported.Path, bytes.NewReader(data)); err != nil {
 			return result.Result{}, err
 		}
 	}
@@ -124,7 +124,7 @@ func (b *sstBatcher) flush(ctx context.Context, db *client.DB) error {
 	if err != nil {
 		return errors.Wrapf(err, "finishing constructed sstable")
 	}
-	if err := AddSSTable(ctx, db, start, end, sstBytes); err != nil {
+	if err := AddSSTable(ctx, db, start, end, sstBytes, false /* disallowShadowing */); err != nil {
 		// TODO(dt): if we get a RangeKeyMismatchError, update batching split points
 		// and then tell the caller to try again.
 		return err
@@ -138,18 +138,27 @@ func (b *sstBatcher) Close() {
 	b.sstWriter.Close()
 }
 
-// AddSSTable retries db.AddSSTable if retryable errors occur.
-func AddSSTable(ctx context.Context, db *client.DB, start, end roachpb.Key, sstBytes []byte) error {
+// AddSSTable retries db.AddSSTable if retryable errors occur. If
+// disallowShadowing is set, ingesting a key that collides with an existing
+// live key is an error.
+func AddSSTable(
+	ctx context.Context,
+	db *client.DB,
+	start, end roachpb.Key,
+	sstBytes []byte,
+	disallowShadowing bool,
+) error {
 	const maxAddSSTableRetries = 10
 	for i := 0; ; i++ {
 		log.VEventf(ctx, 2, "sending AddSSTable [%s,%s)", start, end)
 		// TODO(dan): This will fail if the range has split.
-		err := db.AddSSTable(ctx, start, end, sstBytes)
+		err := db.AddSSTable(ctx, start, end, sstBytes, disallowShadowing)
 		if err == nil {
 			return nil
 		}
 		if m, ok := errors.Cause(err).(*roachpb.RangeKeyMismatchError); ok {
-			return addSplitSSTable(ctx, db, sstBytes, start, m.MismatchedRange.EndKey.AsRawKey())
+			split := m.MismatchedRange.EndKey.AsRawKey()
+			return addSplitSSTable(ctx, db, sstBytes, start, split, disallowShadowing)
 		}
 		if _, ok := err.(*roachpb.AmbiguousResultError); i == maxAddSSTableRetries || !ok {
 			return errors.Wrapf(err, "addsstable [%s,%s)", start, end)
@@ -162,7 +171,11 @@ func AddSSTable(ctx context.Context, db *client.DB, start, end roachpb.Key, sstB
 }
 
 func addSplitSSTable(
-	ctx context.Context, db *client.DB, sstBytes []byte, start, splitKey roachpb.Key,
+	ctx context.Context,
+	db *client.DB,
+	sstBytes []byte,
+	start, splitKey roachpb.Key,
+	disallowShadowing bool,
 ) error {
 	iter, err := engineccl.NewMemSSTIterator(sstBytes, false)
 	if err != nil {
@@ -194,7 +207,7 @@ func addSplitSSTable(
 			if err != nil {
 				return err
 			}
-			if err := AddSSTable(ctx, db, first, last.PrefixEnd(), res); err != nil {
+			if err := AddSSTable(ctx, db, first, last.PrefixEnd(), res, disallowShadowing); err != nil {
 				return err
 			}
 			w.Close()
@@ -224,7 +237,7 @@ func addSplitSSTable(
 	if err != nil {
 		return err
 	}
-	return AddSSTable(ctx, db, first, last.PrefixEnd(), res)
+	return AddSSTable(ctx, db, first, last.PrefixEnd(), res, disallowShadowing)
 }
 
 // evalImport bulk loads key/value entries.
@@ -271,6 +284,15 @@ func evalImport(ctx context.Context, cArgs batcheval.CommandArgs) (*roachpb.Impo
 		dataSize := int64(len(fileContents))
 		log.Eventf(ctx, "fetched file (%s)", humanizeutil.IBytes(dataSize))
 
+		if file.Encryption != nil {
+			fileContents, err = DecryptFile(fileContents, file.Encryption.Key)
+			if err != nil {
+				return nil, errors.Wrapf(err, "decrypting %q", file.Path)
+			}
+		} else if AppearsEncrypted(fileContents) {
+			return nil, errors.Errorf("%q is encrypted but no encryption key was provided", file.Path)
+		}
+
 		if len(file.Sha512) > 0 {
 			checksum, err := SHA512ChecksumData(fileContents)
 			if err != nil {
@@ -0,0 +1,111 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+// Package avroccl contains the pieces of the avro binary encoding shared by
+// CHANGEFEED, EXPORT and IMPORT.
+package avroccl
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+// SQLNameToAvroName escapes a SQL identifier into a valid avro name. Avro names
+// must match `[A-Za-z_][A-Za-z0-9_]*`, so everything else is replaced with an
+// underscore.
+func SQLNameToAvroName(s string) string {
+	r := []rune(s)
+	for i, c := range r {
+		switch {
+		case c == '_', c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z':
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			r[i] = '_'
+		}
+	}
+	return string(r)
+}
+
+// AppendLong appends the avro encoding of an int or long, which is a zig-zag
+// encoded varint. This happens to be exactly what the encoding/binary package
+// does.
+func AppendLong(b []byte, i int64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(buf[:], i)
+	return append(b, buf[:n]...)
+}
+
+// ConsumeLong decodes an int or long from the front of b and returns it along
+// with the rest of b.
+func ConsumeLong(b []byte) (int64, []byte, error) {
+	i, n := binary.Varint(b)
+	if n <= 0 {
+		return 0, nil, errors.New(`insufficient bytes to decode long`)
+	}
+	return i, b[n:], nil
+}
+
+// AppendBytes appends the avro encoding of bytes or a string, which is the
+// length encoded as a long followed by the raw bytes.
+func AppendBytes(b []byte, s []byte) []byte {
+	b = AppendLong(b, int64(len(s)))
+	return append(b, s...)
+}
+
+// AppendString is AppendBytes for a string.
+func AppendString(b []byte, s string) []byte {
+	b = AppendLong(b, int64(len(s)))
+	return append(b, s...)
+}
+
+// ConsumeBytes decodes bytes or a string from the front of b and returns it
+// along with the rest of b. The returned bytes alias b.
+func ConsumeBytes(b []byte) ([]byte, []byte, error) {
+	l, b, err := ConsumeLong(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	if l < 0 || int64(len(b)) < l {
+		return nil, nil, errors.Errorf(`insufficient bytes to decode %d bytes`, l)
+	}
+	return b[:l], b[l:], nil
+}
+
+// BigIntToTwosComplement returns the big-endian two's-complement encoding of i
+// with the minimum number of bytes, as required by the avro decimal logical
+// type (and the parquet one).
+func BigIntToTwosComplement(i *big.Int) []byte {
+	switch i.Sign() {
+	case 0:
+		return []byte{0}
+	case 1:
+		b := i.Bytes()
+		if b[0]&0x80 != 0 {
+			b = append([]byte{0}, b...)
+		}
+		return b
+	default:
+		// For negative numbers, the two's-complement encoding in n bytes is
+		// 2^(8n) + i. The smallest n that fits i is the one that leaves room for
+		// the sign bit above ^i = -i-1, which makes the result exactly n bytes
+		// long with the sign bit set.
+		n := uint(new(big.Int).Not(i).BitLen()+8) / 8
+		return new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 8*n), i).Bytes()
+	}
+}
+
+// TwosComplementToBigInt is the inverse of BigIntToTwosComplement.
+func TwosComplementToBigInt(b []byte) *big.Int {
+	i := new(big.Int).SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		i.Sub(i, new(big.Int).Lsh(big.NewInt(1), uint(len(b)*8)))
+	}
+	return i
+}
@@ -0,0 +1,78 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package avroccl
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLNameToAvroName(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	require.Equal(t, `foo`, SQLNameToAvroName(`foo`))
+	require.Equal(t, `foo_bar`, SQLNameToAvroName(`foo.bar`))
+	require.Equal(t, `_1foo`, SQLNameToAvroName(`1foo`))
+	require.Equal(t, `f_o`, SQLNameToAvroName(`f☃o`))
+}
+
+func TestLongAndBytes(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	var b []byte
+	b = AppendLong(b, -3)
+	b = AppendBytes(b, []byte(`foo`))
+	b = AppendString(b, `bar`)
+
+	i, b, err := ConsumeLong(b)
+	require.NoError(t, err)
+	require.Equal(t, int64(-3), i)
+	s, b, err := ConsumeBytes(b)
+	require.NoError(t, err)
+	require.Equal(t, `foo`, string(s))
+	s, b, err = ConsumeBytes(b)
+	require.NoError(t, err)
+	require.Equal(t, `bar`, string(s))
+	require.Len(t, b, 0)
+
+	_, _, err = ConsumeBytes(AppendLong(nil, 4))
+	require.EqualError(t, err, `insufficient bytes to decode 4 bytes`)
+	_, _, err = ConsumeLong(nil)
+	require.EqualError(t, err, `insufficient bytes to decode long`)
+}
+
+func TestTwosComplement(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, tc := range []struct {
+		i        int64
+		expected []byte
+	}{
+		{0, []byte{0x00}},
+		{1, []byte{0x01}},
+		{-1, []byte{0xff}},
+		{127, []byte{0x7f}},
+		{128, []byte{0x00, 0x80}},
+		{-128, []byte{0x80}},
+		{-129, []byte{0xff, 0x7f}},
+		{255, []byte{0x00, 0xff}},
+		{256, []byte{0x01, 0x00}},
+		{-256, []byte{0xff, 0x00}},
+		{-32768, []byte{0x80, 0x00}},
+		{1 << 40, []byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x00}},
+		{-1 << 40, []byte{0xff, 0x00, 0x00, 0x00, 0x00, 0x00}},
+	} {
+		b := BigIntToTwosComplement(big.NewInt(tc.i))
+		require.Equal(t, tc.expected, b, "%d", tc.i)
+		require.Equal(t, tc.i, TwosComplementToBigInt(b).Int64())
+	}
+}
@@ -2336,6 +2336,7 @@ writing ` + os.DevNull + `
   debug/nodes/1/ranges/20
   debug/nodes/1/ranges/21
   debug/nodes/1/ranges/22
+  debug/nodes/1/ranges/23
   debug/reports/problemranges
   debug/schema/defaultdb@details
   debug/schema/postgres@details
@@ -2348,6 +2349,7 @@ writing ` + os.DevNull + `
   debug/schema/system/namespace
   debug/schema/system/rangelog
   debug/schema/system/role_members
+  debug/schema/system/scheduled_jobs
   debug/schema/system/settings
   debug/schema/system/table_statistics
   debug/schema/system/ui
@@ -106,8 +106,13 @@ func runDump(cmd *cobra.Command, args []string) error {
 	w := os.Stdout
 
 	if dumpCtx.dumpMode != dumpDataOnly {
+		// Types are dumped first since the tables can use them.
+		hasTypes, err := dumpCreateTypes(w, conn, dbName, ts)
+		if err != nil {
+			return err
+		}
 		for i, md := range mds {
-			if i > 0 {
+			if i > 0 || hasTypes {
 				fmt.Fprintln(w)
 			}
 			if err := dumpCreateTable(w, md); err != nil {
@@ -477,6 +482,53 @@ func dumpCreateTable(w io.Writer, md basicMetadata) error {
 	return nil
 }
 
+// dumpCreateTypes dumps the CREATE statements of the user-defined types of
+// the specified database to w. It returns whether there were any.
+func dumpCreateTypes(w io.Writer, conn *sqlConn, dbName string, ts string) (bool, error) {
+	rows, err := conn.Query(fmt.Sprintf(`
+		SELECT t.typname::STRING, e.enumlabel
+		FROM %[1]s.pg_catalog.pg_type t, %[1]s.pg_catalog.pg_enum e
+		AS OF SYSTEM TIME %[2]s
+		WHERE e.enumtypid = t.oid
+		ORDER BY t.typname, e.enumsortorder
+		`, tree.NameString(dbName), lex.EscapeSQLString(ts)), nil)
+	if err != nil {
+		return false, err
+	}
+
+	var typeNames []string
+	labels := make(map[string][]string)
+	vals := make([]driver.Value, 2)
+	for {
+		if err := rows.Next(vals); err == io.EOF {
+			break
+		} else if err != nil {
+			return false, err
+		}
+		name, ok := vals[0].(string)
+		if !ok {
+			return false, fmt.Errorf("unexpected value: %T", vals[0])
+		}
+		label, ok := vals[1].(string)
+		if !ok {
+			return false, fmt.Errorf("unexpected value: %T", vals[1])
+		}
+		if _, ok := labels[name]; !ok {
+			typeNames = append(typeNames, name)
+		}
+		labels[name] = append(labels[name], lex.EscapeSQLString(label))
+	}
+	if err := rows.Close(); err != nil {
+		return false, err
+	}
+
+	for _, name := range typeNames {
+		fmt.Fprintf(w, "CREATE TYPE %s AS ENUM (%s);\n",
+			tree.NameString(name), strings.Join(labels[name], ", "))
+	}
+	return len(typeNames) > 0, nil
+}
+
 const (
 	// insertRows is the number of rows per INSERT statement.
 	insertRows = 100
@@ -647,6 +699,9 @@ func dumpTableData(w io.Writer, conn *sqlConn, clusterTS string, bmd basicMetada
 							if err != nil {
 								return err
 							}
+						} else if _, ok := ct.(*coltypes.TUserDefined); ok {
+							// Enum values are inserted from their labels.
+							d = tree.NewDString(string(t))
 						} else {
 							return errors.Errorf("unknown []byte type: %s, %v: %s", t, cols[si], md.columnTypes[cols[si]])
 						}
@@ -509,6 +509,18 @@ var specs = []stmtSpec{
 		unlink:  []string{"integer", "sequence_name"},
 		nosplit: true,
 	},
+	{
+		name:   "create_schedule_for_backup_stmt",
+		inline: []string{"opt_schedule_label", "opt_full_backup_clause"},
+		replace: map[string]string{
+			"'SCHEDULE' string_or_placeholder":      "'SCHEDULE' label",
+			"'INTO' string_or_placeholder":          "'INTO' location",
+			"'RECURRING' string_or_placeholder":     "'RECURRING' cron_expression",
+			"'FULL' 'BACKUP' string_or_placeholder": "'FULL' 'BACKUP' cron_expression",
+			"targets":                               "( ( 'TABLE' | ) table_pattern ( ( ',' table_pattern ) )* | 'DATABASE' database_name ( ( ',' database_name ) )* )",
+		},
+		unlink: []string{"label", "location", "cron_expression"},
+	},
 	{
 		name:   "create_table_as_stmt",
 		inline: []string{"opt_column_list", "name_list"},
@@ -1038,6 +1050,10 @@ var specs = []stmtSpec{
 		inline:  []string{"ranges_kw"},
 		exclude: []*regexp.Regexp{regexp.MustCompile("'TESTING_RANGES'")},
 	},
+	{
+		name: "show_schedules",
+		stmt: "show_schedules_stmt",
+	},
 	{
 		name: "show_schemas",
 		stmt: "show_schemas_stmt",
@@ -351,12 +351,29 @@ func (b *Batch) AddRawRequest(reqs ...roachpb.Request) {
 //
 // key can be either a byte slice or a string.
 func (b *Batch) Get(key interface{}) {
+	b.get(key, false /* forUpdate */)
+}
+
+// GetForUpdate is like Get, but also acquires an exclusive lock over the key
+// that is held until the transaction finishes. It can only be used in
+// transactional batches.
+//
+// key can be either a byte slice or a string.
+func (b *Batch) GetForUpdate(key interface{}) {
+	b.get(key, true /* forUpdate */)
+}
+
+func (b *Batch) get(key interface{}, forUpdate bool) {
 	k, err := marshalKey(key)
 	if err != nil {
 		b.initResult(0, 1, notRaw, err)
 		return
 	}
-	b.appendReqs(roachpb.NewGet(k))
+	if !forUpdate {
+		b.appendReqs(roachpb.NewGet(k))
+	} else {
+		b.appendReqs(roachpb.NewLockingGet(k, roachpb.LOCK_EXCLUSIVE))
+	}
 	b.initResult(1, 1, notRaw, nil)
 }
 
@@ -475,7 +492,7 @@ func (b *Batch) Inc(key interface{}, value int64) {
 	b.initResult(1, 1, notRaw, nil)
 }
 
-func (b *Batch) scan(s, e interface{}, isReverse bool) {
+func (b *Batch) scan(s, e interface{}, isReverse, forUpdate bool) {
 	begin, err := marshalKey(s)
 	if err != nil {
 		b.initResult(0, 0, notRaw, err)
@@ -486,10 +503,14 @@ func (b *Batch) scan(s, e interface{}, isReverse bool) {
 		b.initResult(0, 0, notRaw, err)
 		return
 	}
+	strength := roachpb.LOCK_NONE
+	if forUpdate {
+		strength = roachpb.LOCK_EXCLUSIVE
+	}
 	if !isReverse {
-		b.appendReqs(roachpb.NewScan(begin, end))
+		b.appendReqs(roachpb.NewLockingScan(begin, end, strength))
 	} else {
-		b.appendReqs(roachpb.NewReverseScan(begin, end))
+		b.appendReqs(roachpb.NewLockingReverseScan(begin, end, strength))
 	}
 	b.initResult(1, 0, notRaw, nil)
 }
@@ -502,7 +523,16 @@ func (b *Batch) scan(s, e interface{}, isReverse bool) {
 //
 // key can be either a byte slice or a string.
 func (b *Batch) Scan(s, e interface{}) {
-	b.scan(s, e, false)
+	b.scan(s, e, false /* isReverse */, false /* forUpdate */)
+}
+
+// ScanForUpdate is like Scan, but also acquires an exclusive lock over the
+// scanned keys that is held until the transaction finishes. It can only be used
+// in transactional batches.
+//
+// key can be either a byte slice or a string.
+func (b *Batch) ScanForUpdate(s, e interface{}) {
+	b.scan(s, e, false /* isReverse */, true /* forUpdate */)
 }
 
 // ReverseScan retrieves the rows between begin (inclusive) and end (exclusive)
@@ -513,7 +543,16 @@ func (b *Batch) Scan(s, e interface{}) {
 //
 // key can be either a byte slice or a string.
 func (b *Batch) ReverseScan(s, e interface{}) {
-	b.scan(s, e, true)
+	b.scan(s, e, true /* isReverse */, false /* forUpdate */)
+}
+
+// ReverseScanForUpdate is like ReverseScan, but also acquires an exclusive
+// lock over the scanned keys that is held until the transaction finishes. It
+// can only be used in transactional batches.
+//
+// key can be either a byte slice or a string.
+func (b *Batch) ReverseScanForUpdate(s, e interface{}) {
+	b.scan(s, e, true /* isReverse */, true /* forUpdate */)
 }
 
 // Del deletes one or more keys.
@@ -659,7 +698,7 @@ func (b *Batch) writeBatch(s, e interface{}, data []byte) {
 }
 
 // addSSTable is only exported on DB.
-func (b *Batch) addSSTable(s, e interface{}, data []byte) {
+func (b *Batch) addSSTable(s, e interface{}, data []byte, disallowShadowing bool) {
 	begin, err := marshalKey(s)
 	if err != nil {
 		b.initResult(0, 0, notRaw, err)
@@ -675,7 +714,8 @@ func (b *Batch) addSSTable(s, e interface{}, data []byte) {
 			Key:    begin,
 			EndKey: end,
 		},
-		Data: data,
+		Data:              data,
+		DisallowShadowing: disallowShadowing,
 	}
 	b.appendReqs(req)
 	b.initResult(1, 0, notRaw, nil)
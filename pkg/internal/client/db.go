@@ -516,10 +516,15 @@ func (db *DB) WriteBatch(ctx context.Context, begin, end interface{}, data []byt
 }
 
 // AddSSTable links a file into the RocksDB log-structured merge-tree. Existing
-// data in the range is cleared.
-func (db *DB) AddSSTable(ctx context.Context, begin, end interface{}, data []byte) error {
+// data in the range is cleared. If disallowShadowing is set, the request fails
+// instead if any key in the file collides with an existing live key. The
+// request is admitted by the stores with bulk priority.
+func (db *DB) AddSSTable(
+	ctx context.Context, begin, end interface{}, data []byte, disallowShadowing bool,
+) error {
 	b := &Batch{}
-	b.addSSTable(begin, end, data)
+	b.Header.AdmissionPriority = roachpb.ADMISSION_BULK
+	b.addSSTable(begin, end, data, disallowShadowing)
 	return getOneErr(db.Run(ctx, b), b)
 }
 
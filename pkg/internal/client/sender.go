@@ -195,6 +195,35 @@ type TxnSender interface {
 	// However, this is used by DistSQL for sending the transaction over the wire
 	// when it creates flows.
 	SerializeTxn() *roachpb.Transaction
+
+	// CreateSavepoint establishes a savepoint. The returned token can be used
+	// to roll back the writes performed after this point, or to release the
+	// savepoint.
+	//
+	// Savepoints can only be created on root transactions.
+	CreateSavepoint(context.Context) (SavepointToken, error)
+
+	// RollbackToSavepoint rolls back all the writes performed since the given
+	// savepoint was created. The savepoint remains valid and can be rolled back
+	// to again.
+	//
+	// Rolling back is possible after the transaction encountered a
+	// non-retriable error, in which case the transaction becomes usable again.
+	// It is not possible once the transaction was restarted after the savepoint
+	// was created.
+	RollbackToSavepoint(context.Context, SavepointToken) error
+
+	// ReleaseSavepoint releases the given savepoint. The writes performed since
+	// the savepoint was created are kept.
+	ReleaseSavepoint(context.Context, SavepointToken) error
+}
+
+// SavepointToken represents a savepoint in a transaction. It is opaque to
+// everything but the TxnSender which created it.
+type SavepointToken interface {
+	// Initial returns true if the savepoint was created before the
+	// transaction performed any operation.
+	Initial() bool
 }
 
 // TxnStatusOpt represents options for TxnSender.GetMeta().
@@ -354,6 +383,21 @@ func (m *MockTransactionalSender) UpdateStateOnRemoteRetryableErr(
 // DisablePipelining is part of the client.TxnSender interface.
 func (m *MockTransactionalSender) DisablePipelining() error { return nil }
 
+// CreateSavepoint is part of the client.TxnSender interface.
+func (m *MockTransactionalSender) CreateSavepoint(context.Context) (SavepointToken, error) {
+	panic("unimplemented")
+}
+
+// RollbackToSavepoint is part of the client.TxnSender interface.
+func (m *MockTransactionalSender) RollbackToSavepoint(context.Context, SavepointToken) error {
+	panic("unimplemented")
+}
+
+// ReleaseSavepoint is part of the client.TxnSender interface.
+func (m *MockTransactionalSender) ReleaseSavepoint(context.Context, SavepointToken) error {
+	panic("unimplemented")
+}
+
 // MockTxnSenderFactory is a TxnSenderFactory producing MockTxnSenders.
 type MockTxnSenderFactory struct {
 	senderFunc func(context.Context, *roachpb.Transaction, roachpb.BatchRequest) (
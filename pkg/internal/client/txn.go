@@ -79,6 +79,14 @@ type Txn struct {
 		// The txn has to be committed by this deadline. A nil value indicates no
 		// deadline.
 		deadline *hlc.Timestamp
+
+		// routingPolicy is attached to all requests sent through this
+		// transaction. It is set by NegotiateAndSetFixedTimestamp.
+		routingPolicy roachpb.RoutingPolicy
+
+		// admissionPriority is attached to all requests sent through this
+		// transaction. It is set by SetAdmissionPriority.
+		admissionPriority roachpb.AdmissionPriority
 	}
 }
 
@@ -221,6 +229,17 @@ func (txn *Txn) UserPriority() roachpb.UserPriority {
 	return txn.mu.userPriority
 }
 
+// SetAdmissionPriority sets the priority with which the requests of the
+// transaction are admitted by the stores they are sent to when these are
+// overloaded. Transactions default to ADMISSION_USER; background work, such as
+// schema change backfills, should use ADMISSION_BULK so as not to compete with
+// foreground traffic.
+func (txn *Txn) SetAdmissionPriority(pri roachpb.AdmissionPriority) {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	txn.mu.admissionPriority = pri
+}
+
 // SetDebugName sets the debug name associated with the transaction which will
 // appear in log files and the web UI.
 func (txn *Txn) SetDebugName(name string) {
@@ -317,6 +336,43 @@ func (txn *Txn) DisablePipelining() error {
 	return txn.mu.sender.DisablePipelining()
 }
 
+// CreateSavepoint establishes a savepoint in the transaction. The writes
+// performed after this point can later be rolled back with
+// RollbackToSavepoint.
+//
+// CreateSavepoint can only be called on root transactions.
+func (txn *Txn) CreateSavepoint(ctx context.Context) (SavepointToken, error) {
+	if txn.typ != RootTxn {
+		return nil, errors.Errorf("CreateSavepoint() called on leaf txn")
+	}
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.CreateSavepoint(ctx)
+}
+
+// RollbackToSavepoint rolls back all the writes performed since the given
+// savepoint was created. If the transaction had encountered a non-retriable
+// error, it becomes usable again.
+func (txn *Txn) RollbackToSavepoint(ctx context.Context, s SavepointToken) error {
+	if txn.typ != RootTxn {
+		return errors.Errorf("RollbackToSavepoint() called on leaf txn")
+	}
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.RollbackToSavepoint(ctx, s)
+}
+
+// ReleaseSavepoint releases the given savepoint, keeping the writes performed
+// since it was created.
+func (txn *Txn) ReleaseSavepoint(ctx context.Context, s SavepointToken) error {
+	if txn.typ != RootTxn {
+		return errors.Errorf("ReleaseSavepoint() called on leaf txn")
+	}
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.ReleaseSavepoint(ctx, s)
+}
+
 // NewBatch creates and returns a new empty batch object for use with the Txn.
 func (txn *Txn) NewBatch() *Batch {
 	return &Batch{txn: txn}
@@ -335,6 +391,17 @@ func (txn *Txn) Get(ctx context.Context, key interface{}) (KeyValue, error) {
 	return getOneRow(txn.Run(ctx, b), b)
 }
 
+// GetForUpdate is like Get, but also acquires an exclusive lock over the key.
+// The lock makes the writes and the locking reads of other transactions wait
+// until this transaction finishes.
+//
+// key can be either a byte slice or a string.
+func (txn *Txn) GetForUpdate(ctx context.Context, key interface{}) (KeyValue, error) {
+	b := txn.NewBatch()
+	b.GetForUpdate(key)
+	return getOneRow(txn.Run(ctx, b), b)
+}
+
 // GetProto retrieves the value for a key and decodes the result as a proto
 // message. If the key doesn't exist, the proto will simply be reset.
 //
@@ -401,17 +468,13 @@ func (txn *Txn) Inc(ctx context.Context, key interface{}, value int64) (KeyValue
 }
 
 func (txn *Txn) scan(
-	ctx context.Context, begin, end interface{}, maxRows int64, isReverse bool,
+	ctx context.Context, begin, end interface{}, maxRows int64, isReverse, forUpdate bool,
 ) ([]KeyValue, error) {
 	b := txn.NewBatch()
 	if maxRows > 0 {
 		b.Header.MaxSpanRequestKeys = maxRows
 	}
-	if !isReverse {
-		b.Scan(begin, end)
-	} else {
-		b.ReverseScan(begin, end)
-	}
+	b.scan(begin, end, isReverse, forUpdate)
 	r, err := getOneResult(txn.Run(ctx, b), b)
 	return r.Rows, err
 }
@@ -426,7 +489,18 @@ func (txn *Txn) scan(
 func (txn *Txn) Scan(
 	ctx context.Context, begin, end interface{}, maxRows int64,
 ) ([]KeyValue, error) {
-	return txn.scan(ctx, begin, end, maxRows, false)
+	return txn.scan(ctx, begin, end, maxRows, false /* isReverse */, false /* forUpdate */)
+}
+
+// ScanForUpdate is like Scan, but also acquires an exclusive lock over the
+// scanned keys. The lock makes the writes and the locking reads of other
+// transactions wait until this transaction finishes.
+//
+// key can be either a byte slice or a string.
+func (txn *Txn) ScanForUpdate(
+	ctx context.Context, begin, end interface{}, maxRows int64,
+) ([]KeyValue, error) {
+	return txn.scan(ctx, begin, end, maxRows, false /* isReverse */, true /* forUpdate */)
 }
 
 // ReverseScan retrieves the rows between begin (inclusive) and end (exclusive)
@@ -439,7 +513,18 @@ func (txn *Txn) Scan(
 func (txn *Txn) ReverseScan(
 	ctx context.Context, begin, end interface{}, maxRows int64,
 ) ([]KeyValue, error) {
-	return txn.scan(ctx, begin, end, maxRows, true)
+	return txn.scan(ctx, begin, end, maxRows, true /* isReverse */, false /* forUpdate */)
+}
+
+// ReverseScanForUpdate is like ReverseScan, but also acquires an exclusive
+// lock over the scanned keys. The lock makes the writes and the locking reads
+// of other transactions wait until this transaction finishes.
+//
+// key can be either a byte slice or a string.
+func (txn *Txn) ReverseScanForUpdate(
+	ctx context.Context, begin, end interface{}, maxRows int64,
+) ([]KeyValue, error) {
+	return txn.scan(ctx, begin, end, maxRows, true /* isReverse */, true /* forUpdate */)
 }
 
 // Iterate performs a paginated scan and applying the function f to every page.
@@ -802,6 +887,12 @@ func (txn *Txn) Send(
 	txn.mu.Lock()
 	requestTxnID := txn.mu.ID
 	sender := txn.mu.sender
+	if txn.mu.routingPolicy != roachpb.ROUTE_TO_LEASEHOLDER {
+		ba.Header.RoutingPolicy = txn.mu.routingPolicy
+	}
+	if txn.mu.admissionPriority != roachpb.ADMISSION_USER {
+		ba.Header.AdmissionPriority = txn.mu.admissionPriority
+	}
 	txn.mu.Unlock()
 	br, pErr := txn.db.sendUsingSender(ctx, ba, sender)
 	if pErr == nil {
@@ -952,6 +1043,61 @@ func (txn *Txn) SetFixedTimestamp(ctx context.Context, ts hlc.Timestamp) {
 	txn.mu.sender.SetFixedTimestamp(ctx, ts)
 }
 
+// NegotiateAndSetFixedTimestamp is like SetFixedTimestamp, but picks the
+// timestamp itself: it is the newest timestamp at which the nearest replicas of
+// all the ranges overlapping the given spans can serve reads, and the
+// transaction's reads are then routed to these replicas. If the nearest
+// replicas can't serve reads at minTS, the transaction's timestamp is left
+// untouched and its reads are served by the lease holders at that timestamp.
+//
+// This is used to support bounded staleness reads. The timestamp the
+// transaction reads at is returned. Like SetFixedTimestamp, this method must
+// be called on every transaction retry.
+func (txn *Txn) NegotiateAndSetFixedTimestamp(
+	ctx context.Context, minTS hlc.Timestamp, spans []roachpb.Span,
+) (hlc.Timestamp, error) {
+	// Reads are only routed to the nearest replicas if this negotiation
+	// succeeds, regardless of the outcome of a previous one.
+	txn.setRoutingPolicy(roachpb.ROUTE_TO_LEASEHOLDER)
+	if len(spans) == 0 {
+		return txn.OrigTimestamp(), nil
+	}
+	// The requests are sent as inconsistent reads so that they are served by
+	// the nearest replicas, which is where the transaction's reads will be
+	// routed to.
+	var ba roachpb.BatchRequest
+	ba.ReadConsistency = roachpb.INCONSISTENT
+	for _, span := range spans {
+		ba.Add(&roachpb.QueryResolvedTimestampRequest{
+			RequestHeader: roachpb.RequestHeaderFromSpan(span),
+		})
+	}
+	br, pErr := txn.db.NonTransactionalSender().Send(ctx, ba)
+	if pErr != nil {
+		return hlc.Timestamp{}, pErr.GoError()
+	}
+	resolvedTS := hlc.MaxTimestamp
+	for _, ru := range br.Responses {
+		resolvedTS.Backward(ru.GetInner().(*roachpb.QueryResolvedTimestampResponse).ResolvedTS)
+	}
+	if resolvedTS.Less(minTS) {
+		log.VEventf(ctx, 2, "resolved timestamp %s is below %s; reading from lease holders",
+			resolvedTS, minTS)
+		txn.setRoutingPolicy(roachpb.ROUTE_TO_LEASEHOLDER)
+		return txn.OrigTimestamp(), nil
+	}
+
+	txn.SetFixedTimestamp(ctx, resolvedTS)
+	txn.setRoutingPolicy(roachpb.ROUTE_TO_NEAREST)
+	return resolvedTS, nil
+}
+
+func (txn *Txn) setRoutingPolicy(policy roachpb.RoutingPolicy) {
+	txn.mu.Lock()
+	txn.mu.routingPolicy = policy
+	txn.mu.Unlock()
+}
+
 // GenerateForcedRetryableError returns a HandledRetryableTxnError that will
 // cause the txn to be retried.
 //
@@ -297,6 +297,9 @@ func (r *Registry) Start(
 		for {
 			select {
 			case <-time.After(adoptInterval):
+				if err := r.maybeRunScheduledJobs(ctx); err != nil {
+					log.Errorf(ctx, "error while running scheduled jobs: %s", err)
+				}
 				if err := r.maybeAdoptJob(ctx, nl); err != nil {
 					log.Errorf(ctx, "error while adopting jobs: %s", err)
 				}
@@ -0,0 +1,203 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/cron"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// ScheduledJob is a schedule of recurring jobs, stored in the
+// `system.scheduled_jobs` table.
+//
+// Every time a schedule is due, the Registry of some node claims it by
+// advancing its next run to the next time matched by its cron expression, and
+// then hands it to the ScheduledJobExecutor registered for its executor type.
+// A schedule whose next run is NULL is paused.
+type ScheduledJob struct {
+	ID           int64
+	Name         string
+	Owner        string
+	ScheduleExpr string
+	ExecutorType string
+	// ExecutionArgs holds the executor-specific state of the schedule, such as
+	// a marshaled proto. Executors may update it when they run.
+	ExecutionArgs []byte
+	// Status is a human readable description of the last run. Executors may
+	// update it when they run.
+	Status string
+}
+
+// ScheduledJobExecutor runs the scheduled jobs of one executor type.
+type ScheduledJobExecutor interface {
+	// ExecuteJob is called every time the schedule is due. phs is a
+	// sql.PlanHookState for the owner of the schedule. Any changes made to the
+	// Status and ExecutionArgs of the schedule are persisted once ExecuteJob
+	// returns, even if it returns an error.
+	ExecuteJob(ctx context.Context, r *Registry, phs interface{}, schedule *ScheduledJob) error
+}
+
+var scheduledJobExecutors struct {
+	syncutil.Mutex
+	m map[string]ScheduledJobExecutor
+}
+
+// RegisterScheduledJobExecutor registers the executor of the scheduled jobs of
+// the given executor type. It is meant to be called from init functions.
+func RegisterScheduledJobExecutor(executorType string, ex ScheduledJobExecutor) {
+	scheduledJobExecutors.Lock()
+	defer scheduledJobExecutors.Unlock()
+	if scheduledJobExecutors.m == nil {
+		scheduledJobExecutors.m = make(map[string]ScheduledJobExecutor)
+	}
+	scheduledJobExecutors.m[executorType] = ex
+}
+
+func getScheduledJobExecutor(executorType string) (ScheduledJobExecutor, error) {
+	scheduledJobExecutors.Lock()
+	defer scheduledJobExecutors.Unlock()
+	ex, ok := scheduledJobExecutors.m[executorType]
+	if !ok {
+		return nil, errors.Errorf("no executor registered for scheduled jobs of type %q", executorType)
+	}
+	return ex, nil
+}
+
+// CreateSchedule inserts a new schedule using the specified txn (may be nil)
+// and returns its ID. The schedule first runs at the first time matched by its
+// cron expression.
+func (r *Registry) CreateSchedule(
+	ctx context.Context, txn *client.Txn, schedule *ScheduledJob,
+) (int64, error) {
+	sched, err := cron.Parse(schedule.ScheduleExpr)
+	if err != nil {
+		return 0, err
+	}
+	nextRun := sched.Next(r.clock.PhysicalTime())
+	if nextRun.IsZero() {
+		return 0, errors.Errorf("cron expression %q never matches", schedule.ScheduleExpr)
+	}
+	const stmt = `INSERT INTO system.scheduled_jobs
+  (schedule_name, owner, schedule_expr, next_run, schedule_status, executor_type, execution_args)
+  VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING schedule_id`
+	row, err := r.ex.QueryRow(ctx, "create-schedule", txn, stmt,
+		schedule.Name, schedule.Owner, schedule.ScheduleExpr, nextRun, schedule.Status,
+		schedule.ExecutorType, schedule.ExecutionArgs)
+	if err != nil {
+		return 0, err
+	}
+	schedule.ID = int64(tree.MustBeDInt(row[0]))
+	return schedule.ID, nil
+}
+
+// maybeRunScheduledJobs runs all the schedules that are due.
+func (r *Registry) maybeRunScheduledJobs(ctx context.Context) error {
+	const stmt = `SELECT schedule_id FROM system.scheduled_jobs WHERE next_run <= $1 ORDER BY next_run`
+	rows, _ /* cols */, err := r.ex.Query(
+		ctx, "find-scheduled-jobs", nil /* txn */, stmt, r.clock.PhysicalTime(),
+	)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		id := int64(tree.MustBeDInt(row[0]))
+		if err := r.runScheduledJob(ctx, id); err != nil {
+			log.Warningf(ctx, "schedule %d: %s", id, err)
+		}
+	}
+	return nil
+}
+
+// runScheduledJob claims the schedule with the given ID, if it is still due,
+// and executes it.
+//
+// Claiming the schedule advances its next run in a transaction, so that every
+// run is executed by a single node. The executor itself runs outside of that
+// transaction: it typically starts a job, which must not be started again if
+// the transaction is retried.
+func (r *Registry) runScheduledJob(ctx context.Context, id int64) error {
+	var schedule *ScheduledJob
+	if err := r.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		schedule = nil
+		now := r.clock.PhysicalTime()
+		const selectStmt = `SELECT schedule_name, owner, schedule_expr, executor_type, execution_args
+  FROM system.scheduled_jobs WHERE schedule_id = $1 AND next_run <= $2`
+		row, err := r.ex.QueryRow(ctx, "claim-scheduled-job", txn, selectStmt, id, now)
+		if err != nil || row == nil {
+			// Either the schedule was claimed by another node or it was paused.
+			return err
+		}
+		s := &ScheduledJob{
+			ID:            id,
+			Name:          string(tree.MustBeDString(row[0])),
+			Owner:         string(tree.MustBeDString(row[1])),
+			ScheduleExpr:  string(tree.MustBeDString(row[2])),
+			ExecutorType:  string(tree.MustBeDString(row[3])),
+			ExecutionArgs: []byte(*row[4].(*tree.DBytes)),
+		}
+		var nextRun time.Time
+		if sched, err := cron.Parse(s.ScheduleExpr); err == nil {
+			nextRun = sched.Next(now)
+		}
+		if nextRun.IsZero() {
+			// The schedule never runs again: pause it.
+			const pauseStmt = `UPDATE system.scheduled_jobs SET next_run = NULL, schedule_status = $2 WHERE schedule_id = $1`
+			_, err := r.ex.Exec(ctx, "pause-scheduled-job", txn, pauseStmt, id,
+				fmt.Sprintf("paused: cron expression %q has no next run", s.ScheduleExpr))
+			return err
+		}
+		const updateStmt = `UPDATE system.scheduled_jobs SET next_run = $2 WHERE schedule_id = $1`
+		if _, err := r.ex.Exec(ctx, "claim-scheduled-job", txn, updateStmt, id, nextRun); err != nil {
+			return err
+		}
+		schedule = s
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, "unable to claim schedule")
+	}
+	if schedule == nil {
+		return nil
+	}
+
+	err := func() error {
+		ex, err := getScheduledJobExecutor(schedule.ExecutorType)
+		if err != nil {
+			return err
+		}
+		phs, cleanup := r.planFn("run-scheduled-job", schedule.Owner)
+		defer cleanup()
+		return ex.ExecuteJob(ctx, r, phs, schedule)
+	}()
+	if err != nil {
+		schedule.Status = fmt.Sprintf("error: %s", err)
+	}
+
+	const updateStmt = `UPDATE system.scheduled_jobs SET schedule_status = $2, execution_args = $3 WHERE schedule_id = $1`
+	if _, updateErr := r.ex.Exec(
+		ctx, "update-scheduled-job", nil /* txn */, updateStmt, schedule.ID, schedule.Status, schedule.ExecutionArgs,
+	); updateErr != nil {
+		return errors.Wrap(updateErr, "unable to update schedule")
+	}
+	return err
+}
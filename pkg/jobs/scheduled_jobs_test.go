@@ -0,0 +1,132 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package jobs_test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// countingExecutor counts its runs in the execution args of the schedules it
+// executes. It fails if err is set.
+type countingExecutor struct {
+	calls int32
+	err   error
+}
+
+func (e *countingExecutor) ExecuteJob(
+	_ context.Context, _ *jobs.Registry, _ interface{}, schedule *jobs.ScheduledJob,
+) error {
+	n := atomic.AddInt32(&e.calls, 1)
+	schedule.ExecutionArgs = []byte(fmt.Sprintf("%s+%d", schedule.ExecutionArgs, n))
+	schedule.Status = fmt.Sprintf("run %d", n)
+	return e.err
+}
+
+func TestScheduledJobs(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	defer func(oldInterval time.Duration) {
+		jobs.DefaultAdoptInterval = oldInterval
+	}(jobs.DefaultAdoptInterval)
+	jobs.DefaultAdoptInterval = 10 * time.Millisecond
+
+	ok, failing := &countingExecutor{}, &countingExecutor{err: errors.New("boom")}
+	jobs.RegisterScheduledJobExecutor("test-ok", ok)
+	jobs.RegisterScheduledJobExecutor("test-failing", failing)
+
+	ctx := context.TODO()
+	s, outerDB, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(outerDB)
+	registry := s.JobRegistry().(*jobs.Registry)
+
+	createSchedule := func(executorType string) int64 {
+		id, err := registry.CreateSchedule(ctx, nil /* txn */, &jobs.ScheduledJob{
+			Name:          executorType,
+			Owner:         security.RootUser,
+			ScheduleExpr:  "@yearly",
+			ExecutorType:  executorType,
+			ExecutionArgs: []byte("args"),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+	makeDue := func(id int64) {
+		sqlDB.Exec(t, `UPDATE system.scheduled_jobs SET next_run = $2 WHERE schedule_id = $1`,
+			id, timeutil.Now().Add(-time.Second))
+	}
+	waitForStatus := func(id int64, expectedStatus, expectedArgs string) {
+		testutils.SucceedsSoon(t, func() error {
+			var status, args string
+			sqlDB.QueryRow(t,
+				`SELECT schedule_status, execution_args FROM system.scheduled_jobs WHERE schedule_id = $1`, id,
+			).Scan(&status, &args)
+			if status != expectedStatus || args != expectedArgs {
+				return errors.Errorf("expected status %q and args %q, got %q and %q",
+					expectedStatus, expectedArgs, status, args)
+			}
+			return nil
+		})
+	}
+
+	okID := createSchedule("test-ok")
+	failingID := createSchedule("test-failing")
+
+	// Neither schedule is due before next year.
+	var nextRun time.Time
+	sqlDB.QueryRow(t, `SELECT next_run FROM system.scheduled_jobs WHERE schedule_id = $1`, okID).Scan(&nextRun)
+	if !nextRun.After(timeutil.Now()) {
+		t.Fatalf("expected the next run to be in the future, got %s", nextRun)
+	}
+	time.Sleep(10 * jobs.DefaultAdoptInterval)
+	if calls := atomic.LoadInt32(&ok.calls); calls != 0 {
+		t.Fatalf("expected no runs, got %d", calls)
+	}
+
+	makeDue(okID)
+	waitForStatus(okID, "run 1", "args+1")
+	makeDue(okID)
+	waitForStatus(okID, "run 2", "args+1+2")
+
+	// Running the schedule advanced its next run.
+	sqlDB.QueryRow(t, `SELECT next_run FROM system.scheduled_jobs WHERE schedule_id = $1`, okID).Scan(&nextRun)
+	if !nextRun.After(timeutil.Now()) {
+		t.Fatalf("expected the next run to be in the future, got %s", nextRun)
+	}
+	if calls := atomic.LoadInt32(&ok.calls); calls != 2 {
+		t.Fatalf("expected 2 runs, got %d", calls)
+	}
+
+	// Errors are reported in the status of the schedule.
+	makeDue(failingID)
+	waitForStatus(failingID, "error: boom", "args+1")
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/rpc/nodedialer"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage/closedts"
 	"github.com/cockroachdb/cockroach/pkg/util/grpcutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -434,9 +435,13 @@ func (ds *DistSender) sendSingleRange(
 	replicas := NewReplicaSlice(ds.gossip, desc)
 
 	// If this request needs to go to a lease holder and we know who that is, move
-	// it to the front.
+	// it to the front. Reads that any replica can serve via follower reads go to
+	// the nearest replica instead, which redirects them to the lease holder with
+	// a NotLeaseHolderError if it can't serve them after all.
 	var knowLeaseholder bool
-	if !ba.IsReadOnly() || ba.ReadConsistency.RequiresReadLease() {
+	if ds.canSendToFollower(ba) {
+		log.Event(ctx, "sending to nearest replica for follower read")
+	} else if !ba.IsReadOnly() || ba.ReadConsistency.RequiresReadLease() {
 		if storeID, ok := ds.leaseHolderCache.Lookup(ctx, desc.RangeID); ok {
 			if i := replicas.FindReplica(storeID); i >= 0 {
 				replicas.MoveToFront(i)
@@ -473,6 +478,40 @@ func (ds *DistSender) sendSingleRange(
 	return br, pErr
 }
 
+// canSendToFollower returns whether the batch is a consistent read at a
+// timestamp old enough for every replica of the range to be expected to be
+// able to serve it via follower reads, or a read whose timestamp was
+// negotiated with the nearest replicas (see roachpb.ROUTE_TO_NEAREST).
+func (ds *DistSender) canSendToFollower(ba roachpb.BatchRequest) bool {
+	if !closedts.FollowerReadsEnabled.Get(&ds.st.SV) ||
+		!ba.IsReadOnly() || !ba.ReadConsistency.RequiresReadLease() {
+		return false
+	}
+	// Consistent batches without a timestamp are assigned a current one by the
+	// replica that serves them.
+	if ba.Timestamp == (hlc.Timestamp{}) {
+		return false
+	}
+	// A transaction may observe values up to its max timestamp, and needs to
+	// read its own intents, which followers may not have applied yet.
+	ts := ba.Timestamp
+	if ba.Txn != nil {
+		if ba.Txn.Writing {
+			return false
+		}
+		ts.Forward(ba.Txn.MaxTimestamp)
+	}
+	if ba.RoutingPolicy == roachpb.ROUTE_TO_NEAREST {
+		return true
+	}
+	offset := closedts.FollowerReadOffset(&ds.st.SV)
+	if offset == 0 {
+		return false
+	}
+	threshold := ds.clock.Now().Add(-offset.Nanoseconds(), 0)
+	return ts.Less(threshold)
+}
+
 // initAndVerifyBatch initializes timestamp-related information and
 // verifies batch constraints before splitting.
 func (ds *DistSender) initAndVerifyBatch(
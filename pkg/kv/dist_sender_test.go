@@ -36,6 +36,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/rpc"
 	"github.com/cockroachdb/cockroach/pkg/rpc/nodedialer"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage/closedts"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/util"
@@ -577,6 +578,279 @@ func TestRetryOnNotLeaseHolderError(t *testing.T) {
 	}
 }
 
+// TestFollowerReadRouting verifies that consistent reads at old enough
+// timestamps, or with the ROUTE_TO_NEAREST routing policy, are sent to the
+// nearest replica instead of the lease holder when follower reads are enabled,
+// and that they are redirected to the lease holder if that replica can't serve
+// them.
+func TestFollowerReadRouting(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+
+	g, clock := makeGossip(t, stopper)
+	st := cluster.MakeTestingClusterSettings()
+
+	// The range has a replica on the local node 1 and its lease holder on node 2.
+	descriptor := roachpb.RangeDescriptor{
+		RangeID:  1,
+		StartKey: roachpb.RKeyMin,
+		EndKey:   roachpb.RKeyMax,
+		Replicas: []roachpb.ReplicaDescriptor{
+			{NodeID: 1, StoreID: 1},
+			{NodeID: 2, StoreID: 2},
+		},
+	}
+	leaseHolder := descriptor.Replicas[1]
+	nd := &roachpb.NodeDescriptor{
+		NodeID:  leaseHolder.NodeID,
+		Address: util.MakeUnresolvedAddr("tcp", "node2:1"),
+	}
+	if err := g.AddInfoProto(gossip.MakeNodeIDKey(nd.NodeID), nd, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	// sentTo records the nodes the last read was sent to. Replicas other than
+	// the lease holder reject it if canServe is false.
+	var sentTo []roachpb.NodeID
+	var canServe bool
+	var testFn simpleSendFn = func(
+		_ context.Context, _ SendOptions, _ ReplicaSlice, ba roachpb.BatchRequest,
+	) (*roachpb.BatchResponse, error) {
+		sentTo = append(sentTo, ba.Replica.NodeID)
+		if ba.Replica != leaseHolder && !canServe {
+			reply := &roachpb.BatchResponse{}
+			reply.Error = roachpb.NewError(&roachpb.NotLeaseHolderError{LeaseHolder: &leaseHolder})
+			return reply, nil
+		}
+		return ba.CreateReply(), nil
+	}
+
+	cfg := DistSenderConfig{
+		AmbientCtx: log.AmbientContext{Tracer: tracing.NewTracer()},
+		Settings:   st,
+		Clock:      clock,
+		TestingKnobs: ClientTestingKnobs{
+			TransportFactory: adaptSimpleTransport(testFn),
+		},
+		RangeDescriptorDB: mockRangeDescriptorDBForDescs(descriptor),
+		NodeDialer:        nodedialer.New(nil, gossip.AddressResolver(g)),
+	}
+	ds := NewDistSender(cfg, g)
+
+	old := clock.Now().Add(-time.Minute.Nanoseconds(), 0)
+	const nearest = roachpb.ROUTE_TO_NEAREST
+	for _, tc := range []struct {
+		name     string
+		enabled  bool
+		ts       hlc.Timestamp
+		routing  roachpb.RoutingPolicy
+		args     roachpb.Request
+		canServe bool
+		expected []roachpb.NodeID
+	}{
+		{"disabled", false, old, 0, &roachpb.GetRequest{}, true, []roachpb.NodeID{2}},
+		{"no timestamp", true, hlc.Timestamp{}, 0, &roachpb.GetRequest{}, true, []roachpb.NodeID{2}},
+		{"recent", true, clock.Now(), 0, &roachpb.GetRequest{}, true, []roachpb.NodeID{2}},
+		{"write", true, old, 0, &roachpb.PutRequest{}, true, []roachpb.NodeID{2}},
+		{"follower read", true, old, 0, &roachpb.GetRequest{}, true, []roachpb.NodeID{1}},
+		{"redirect", true, old, 0, &roachpb.GetRequest{}, false, []roachpb.NodeID{1, 2}},
+		{"nearest disabled", false, clock.Now(), nearest, &roachpb.GetRequest{}, true, []roachpb.NodeID{2}},
+		{"nearest write", true, clock.Now(), nearest, &roachpb.PutRequest{}, true, []roachpb.NodeID{2}},
+		{"nearest", true, clock.Now(), nearest, &roachpb.GetRequest{}, true, []roachpb.NodeID{1}},
+		{"nearest redirect", true, clock.Now(), nearest, &roachpb.GetRequest{}, false, []roachpb.NodeID{1, 2}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			closedts.FollowerReadsEnabled.Override(&st.SV, tc.enabled)
+			ds.leaseHolderCache.Update(context.TODO(), descriptor.RangeID, leaseHolder.StoreID)
+			sentTo, canServe = nil, tc.canServe
+
+			args := tc.args
+			header := args.Header()
+			header.Key = roachpb.Key("a")
+			args.SetHeader(header)
+			if _, err := client.SendWrappedWith(context.Background(), ds, roachpb.Header{
+				Timestamp:     tc.ts,
+				RoutingPolicy: tc.routing,
+			}, args); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(sentTo, tc.expected) {
+				t.Fatalf("expected the request to be sent to nodes %d, got %d", tc.expected, sentTo)
+			}
+		})
+	}
+}
+
+// TestNegotiateBoundedStaleness verifies that a bounded staleness read picks
+// the oldest of the closed timestamps of the nearest replicas of the ranges it
+// reads, and that its reads are then routed to these replicas. If that
+// timestamp is below the bound, the reads go to the lease holders.
+func TestNegotiateBoundedStaleness(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	g, clock := makeGossip(t, stopper)
+	st := cluster.MakeTestingClusterSettings()
+	closedts.FollowerReadsEnabled.Override(&st.SV, true)
+
+	// Both ranges have a replica on the local node 1 and their lease holder on
+	// node 2.
+	replicas := []roachpb.ReplicaDescriptor{
+		{NodeID: 1, StoreID: 1},
+		{NodeID: 2, StoreID: 2},
+	}
+	descs := []roachpb.RangeDescriptor{
+		{RangeID: 1, StartKey: roachpb.RKeyMin, EndKey: roachpb.RKey("m"), Replicas: replicas},
+		{RangeID: 2, StartKey: roachpb.RKey("m"), EndKey: roachpb.RKeyMax, Replicas: replicas},
+	}
+	leaseHolder := replicas[1]
+	nd := &roachpb.NodeDescriptor{
+		NodeID:  leaseHolder.NodeID,
+		Address: util.MakeUnresolvedAddr("tcp", "node2:1"),
+	}
+	if err := g.AddInfoProto(gossip.MakeNodeIDKey(nd.NodeID), nd, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	closed := map[roachpb.RangeID]hlc.Timestamp{
+		1: {WallTime: 200},
+		2: {WallTime: 100},
+	}
+
+	// sent records the method, range, node and timestamp of every request.
+	var mu syncutil.Mutex
+	var sent []string
+	var testFn simpleSendFn = func(
+		_ context.Context, _ SendOptions, _ ReplicaSlice, ba roachpb.BatchRequest,
+	) (*roachpb.BatchResponse, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		br := ba.CreateReply()
+		br.Txn = ba.Txn
+		for i, ru := range ba.Requests {
+			req := ru.GetInner()
+			ts := ba.Timestamp
+			if req.Method() == roachpb.QueryResolvedTimestamp {
+				// Inconsistent requests are assigned the current time.
+				ts = hlc.Timestamp{}
+				br.Responses[i].GetInner().(*roachpb.QueryResolvedTimestampResponse).ResolvedTS =
+					closed[ba.RangeID]
+			}
+			sent = append(sent, fmt.Sprintf("%s r%d n%d %d", req.Method(), ba.RangeID, ba.Replica.NodeID, ts.WallTime))
+		}
+		return br, nil
+	}
+
+	ambient := log.AmbientContext{Tracer: tracing.NewTracer()}
+	ds := NewDistSender(DistSenderConfig{
+		AmbientCtx: ambient,
+		Settings:   st,
+		Clock:      clock,
+		TestingKnobs: ClientTestingKnobs{
+			TransportFactory: adaptSimpleTransport(testFn),
+		},
+		RangeDescriptorDB: mockRangeDescriptorDBForDescs(descs...),
+		NodeDialer:        nodedialer.New(nil, gossip.AddressResolver(g)),
+	}, g)
+	tsf := NewTxnCoordSenderFactory(TxnCoordSenderFactoryConfig{
+		AmbientCtx: ambient,
+		Settings:   st,
+		Clock:      clock,
+		Stopper:    stopper,
+	}, ds)
+	db := client.NewDB(ambient, tsf, clock)
+
+	spans := []roachpb.Span{{Key: roachpb.Key("a"), EndKey: roachpb.Key("z")}}
+	for _, tc := range []struct {
+		name     string
+		minTS    hlc.Timestamp
+		expected func(now hlc.Timestamp) []string
+	}{
+		{"nearest", hlc.Timestamp{WallTime: 50}, func(hlc.Timestamp) []string {
+			return []string{
+				"Get r1 n1 100",
+				"Get r2 n1 100",
+				"QueryResolvedTimestamp r1 n1 0",
+				"QueryResolvedTimestamp r2 n1 0",
+			}
+		}},
+		{"lease holders", hlc.Timestamp{WallTime: 150}, func(now hlc.Timestamp) []string {
+			return []string{
+				fmt.Sprintf("Get r1 n2 %d", now.WallTime),
+				fmt.Sprintf("Get r2 n2 %d", now.WallTime),
+				"QueryResolvedTimestamp r1 n1 0",
+				"QueryResolvedTimestamp r2 n1 0",
+			}
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, desc := range descs {
+				ds.leaseHolderCache.Update(ctx, desc.RangeID, leaseHolder.StoreID)
+			}
+			mu.Lock()
+			sent = nil
+			mu.Unlock()
+
+			txn := client.NewTxn(ctx, db, 0 /* gatewayNodeID */, client.RootTxn)
+			now := txn.OrigTimestamp()
+			ts, err := txn.NegotiateAndSetFixedTimestamp(ctx, tc.minTS, spans)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.minTS.Less(closed[2]) {
+				if ts != closed[2] {
+					t.Fatalf("expected the minimum closed timestamp %s, got %s", closed[2], ts)
+				}
+			} else if ts != now {
+				t.Fatalf("expected the transaction's timestamp %s, got %s", now, ts)
+			}
+			for _, key := range []string{"b", "n"} {
+				if _, err := txn.Get(ctx, key); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			sort.Strings(sent)
+			if expected := tc.expected(now); !reflect.DeepEqual(sent, expected) {
+				t.Fatalf("expected requests %s, got %s", expected, sent)
+			}
+		})
+	}
+
+	// A negotiation which falls back to the lease holders undoes the routing
+	// of a previous successful one, e.g. when the transaction is retried.
+	t.Run("renegotiate", func(t *testing.T) {
+		for _, desc := range descs {
+			ds.leaseHolderCache.Update(ctx, desc.RangeID, leaseHolder.StoreID)
+		}
+		txn := client.NewTxn(ctx, db, 0 /* gatewayNodeID */, client.RootTxn)
+		for _, minTS := range []hlc.Timestamp{{WallTime: 50}, {WallTime: 150}} {
+			if _, err := txn.NegotiateAndSetFixedTimestamp(ctx, minTS, spans); err != nil {
+				t.Fatal(err)
+			}
+		}
+		mu.Lock()
+		sent = nil
+		mu.Unlock()
+		for _, key := range []string{"b", "n"} {
+			if _, err := txn.Get(ctx, key); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		sort.Strings(sent)
+		if expected := []string{"Get r1 n2 100", "Get r2 n2 100"}; !reflect.DeepEqual(sent, expected) {
+			t.Fatalf("expected requests %s, got %s", expected, sent)
+		}
+	})
+}
+
 // This test verifies that when we have a cached leaseholder that is down
 // it is ejected from the cache.
 func TestDistSenderDownNodeEvictLeaseholder(t *testing.T) {
@@ -0,0 +1,184 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kv
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+)
+
+// savepoint captures the state of the transaction at the time a savepoint was
+// created. It is the TxnCoordSender's implementation of client.SavepointToken.
+type savepoint struct {
+	// txnID and epoch are used to reject rollbacks to savepoints created by a
+	// different transaction, or by an earlier epoch of this one.
+	txnID uuid.UUID
+	epoch uint32
+
+	// seqNum is the value of the txnSeqNumAllocator's counter at the time the
+	// savepoint was created. All the writes performed after it get a higher
+	// sequence number, which is what rolling back to the savepoint ignores.
+	seqNum int32
+}
+
+var _ client.SavepointToken = &savepoint{}
+
+// Initial is part of the client.SavepointToken interface.
+func (s *savepoint) Initial() bool {
+	return s.seqNum == 0
+}
+
+// CreateSavepoint is part of the client.TxnSender interface.
+func (tc *TxnCoordSender) CreateSavepoint(ctx context.Context) (client.SavepointToken, error) {
+	if tc.typ != client.RootTxn {
+		return nil, errors.Errorf("cannot create savepoint in non-root txn")
+	}
+	// Nodes running an older version would ignore the sequence numbers rolled
+	// back by the savepoint, and commit the writes they cover.
+	if !tc.st.Version.IsMinSupported(cluster.VersionSavepoints) {
+		return nil, errors.Errorf("savepoints require all nodes to be upgraded to %s",
+			cluster.VersionByKey(cluster.VersionSavepoints))
+	}
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if pErr := tc.maybeRejectClientLocked(ctx, nil /* ba */); pErr != nil {
+		return nil, pErr.GoError()
+	}
+
+	return &savepoint{
+		txnID:  tc.mu.txn.ID,
+		epoch:  tc.mu.txn.Epoch,
+		seqNum: tc.interceptorAlloc.txnSeqNumAllocator.seqNumCounter,
+	}, nil
+}
+
+// RollbackToSavepoint is part of the client.TxnSender interface.
+func (tc *TxnCoordSender) RollbackToSavepoint(
+	ctx context.Context, s client.SavepointToken,
+) error {
+	if tc.typ != client.RootTxn {
+		return errors.Errorf("cannot rollback savepoint in non-root txn")
+	}
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	sp := s.(*savepoint)
+	if err := tc.checkSavepointLocked(sp); err != nil {
+		return err
+	}
+
+	// Writes performed after the savepoint get ignored from now on, both by
+	// this transaction's reads and when its intents are resolved.
+	if curSeq := tc.interceptorAlloc.txnSeqNumAllocator.seqNumCounter; curSeq > sp.seqNum {
+		ignored := enginepb.IgnoredSeqNumRange{Start: sp.seqNum + 1, End: curSeq}
+		tc.mu.txn.AddIgnoredSeqNumRange(ignored)
+		tc.interceptorAlloc.txnPipeliner.rollbackToSavepointLocked(ctx, ignored)
+		if err := tc.rollbackIntentsLocked(ctx); err != nil {
+			tc.mu.txnState = txnError
+			return err
+		}
+	}
+
+	// Rolling back a savepoint undoes the writes that may have caused a
+	// non-retriable error, so the transaction can be used again.
+	tc.mu.txnState = txnPending
+	return nil
+}
+
+// ReleaseSavepoint is part of the client.TxnSender interface.
+func (tc *TxnCoordSender) ReleaseSavepoint(ctx context.Context, s client.SavepointToken) error {
+	if tc.typ != client.RootTxn {
+		return errors.Errorf("cannot release savepoint in non-root txn")
+	}
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if tc.mu.txnState == txnError {
+		return &roachpb.TxnAlreadyEncounteredErrorError{}
+	}
+	return tc.checkSavepointLocked(s.(*savepoint))
+}
+
+// checkSavepointLocked verifies that the given savepoint can still be used by
+// this transaction.
+func (tc *TxnCoordSender) checkSavepointLocked(s *savepoint) error {
+	if tc.mu.txnState == txnFinalized {
+		return roachpb.NewTransactionStatusError(
+			"client already committed or rolled back the transaction")
+	}
+	if s.txnID != tc.mu.txn.ID {
+		return errors.New("cannot use savepoint across transaction retries")
+	}
+	if s.epoch != tc.mu.txn.Epoch {
+		return errors.New("cannot use savepoint after a transaction restart")
+	}
+	return nil
+}
+
+// rollbackIntentsLocked eagerly reverts the transaction's intents that were
+// written at an ignored sequence number, so that the values they replaced
+// become visible again. The intents are resolved as PENDING with the
+// transaction's current list of ignored seqnum ranges, which is what reverts
+// them; intents that aren't affected are left alone.
+//
+// The resolution is sent outside of the interceptor stack: it is not part of
+// the transaction's own operations and must not allocate sequence numbers or
+// be accounted as intents. Pipelined writes that haven't been proven yet are
+// still ordered before it, as they hold their latches until they apply.
+func (tc *TxnCoordSender) rollbackIntentsLocked(ctx context.Context) error {
+	intents := tc.interceptorAlloc.txnIntentCollector.intents
+	if len(intents) == 0 {
+		return nil
+	}
+
+	var ba roachpb.BatchRequest
+	ba.Requests = make([]roachpb.RequestUnion, 0, len(intents))
+	for _, span := range intents {
+		if len(span.EndKey) == 0 {
+			ba.Add(&roachpb.ResolveIntentRequest{
+				RequestHeader:  roachpb.RequestHeaderFromSpan(span),
+				IntentTxn:      tc.mu.txn.TxnMeta,
+				Status:         roachpb.PENDING,
+				IgnoredSeqNums: tc.mu.txn.IgnoredSeqNums,
+			})
+		} else {
+			ba.Add(&roachpb.ResolveIntentRangeRequest{
+				RequestHeader:  roachpb.RequestHeaderFromSpan(span),
+				IntentTxn:      tc.mu.txn.TxnMeta,
+				Status:         roachpb.PENDING,
+				IgnoredSeqNums: tc.mu.txn.IgnoredSeqNums,
+			})
+		}
+	}
+	log.VEventf(ctx, 2, "rolling back intents over %d spans", len(intents))
+
+	// Unlocks while sending then re-locks.
+	if _, pErr := tc.interceptorAlloc.txnLockGatekeeper.SendLocked(ctx, ba); pErr != nil {
+		return pErr.GoError()
+	}
+	return nil
+}
@@ -30,6 +30,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
@@ -2512,3 +2513,45 @@ func TestLeafTxnClientRejectError(t *testing.T) {
 		t.Fatalf("expected UnhandledRetryableError(TransactionAbortedError), got: (%T) %v", err, err)
 	}
 }
+
+// TestTxnCoordSenderSavepointsRequireVersion verifies that savepoints can't be
+// created until all the nodes have been upgraded to a version which honors
+// them.
+func TestTxnCoordSenderSavepointsRequireVersion(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	clock := hlc.NewClock(hlc.UnixNano, time.Nanosecond)
+	ambient := log.AmbientContext{Tracer: tracing.NewTracer()}
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	for _, tc := range []struct {
+		version roachpb.Version
+		expErr  string
+	}{
+		{cluster.VersionByKey(cluster.VersionVirtualComputedColumns), "savepoints require all nodes to be upgraded"},
+		{cluster.VersionByKey(cluster.VersionSavepoints), ""},
+	} {
+		t.Run(tc.version.String(), func(t *testing.T) {
+			factory := NewTxnCoordSenderFactory(
+				TxnCoordSenderFactoryConfig{
+					AmbientCtx: ambient,
+					Settings:   cluster.MakeTestingClusterSettingsWithVersion(tc.version, tc.version),
+					Clock:      clock,
+					Stopper:    stopper,
+				},
+				&mockSender{},
+			)
+			db := client.NewDB(ambient, factory, clock)
+			txn := client.NewTxn(ctx, db, roachpb.NodeID(1), client.RootTxn)
+			_, err := txn.CreateSavepoint(ctx)
+			if tc.expErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+			} else if !testutils.IsError(err, tc.expErr) {
+				t.Fatalf("expected %q, got: %v", tc.expErr, err)
+			}
+		})
+	}
+}
@@ -543,6 +543,10 @@ func (h *txnHeartbeat) abortTxnAsyncLocked(ctx context.Context) {
 // BatchRequest. Returns -1 if the batch has not intention to write. It also
 // verifies that if an EndTransactionRequest is included, then it is the last
 // request in the batch.
+//
+// Locking reads are treated as writes: the locks they acquire are released
+// when the transaction finishes, so the transaction needs a record that other
+// transactions waiting on its locks can push, and an EndTransaction.
 func firstWriteIndex(ba *roachpb.BatchRequest) (int, *roachpb.Error) {
 	for i, ru := range ba.Requests {
 		args := ru.GetInner()
@@ -551,7 +555,7 @@ func firstWriteIndex(ba *roachpb.BatchRequest) (int, *roachpb.Error) {
 				return -1, roachpb.NewErrorf("%s sent as non-terminal call", args.Method())
 			}
 		}
-		if roachpb.IsTransactionWrite(args) {
+		if roachpb.IsLocking(args) {
 			return i, nil
 		}
 	}
@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 )
 
@@ -433,6 +434,34 @@ func (tp *txnPipeliner) outstandingWritesLen() int {
 	return tp.outstandingWrites.Len()
 }
 
+// rollbackToSavepointLocked forgets about the outstanding writes performed at
+// a sequence number in the given range, which was just rolled back. These
+// writes are about to be reverted, so there is nothing left to prove about
+// them. A write to the same key that preceded them was already proven, since
+// they were chained onto it.
+func (tp *txnPipeliner) rollbackToSavepointLocked(
+	ctx context.Context, ignored enginepb.IgnoredSeqNumRange,
+) {
+	if tp.outstandingWrites == nil {
+		return
+	}
+	var toDelete []*outstandingWrite
+	tp.outstandingWrites.Ascend(func(item btree.Item) bool {
+		w := item.(*outstandingWrite)
+		if ignored.Start <= w.Sequence && w.Sequence <= ignored.End {
+			toDelete = append(toDelete, w)
+		}
+		return true
+	})
+	for _, w := range toDelete {
+		log.VEventf(ctx, 2, "forgetting rolled back write to %s", w.Key)
+		delItem := tp.outstandingWrites.Delete(w)
+		if delItem != nil {
+			*delItem.(*outstandingWrite) = outstandingWrite{} // for GC
+		}
+	}
+}
+
 // maybeInsertOutstandingWriteLocked attempts to insert an outstanding write
 // that has not been proven to have succeeded into the txnPipeliners outstanding
 // write map.
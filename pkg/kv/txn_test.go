@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
@@ -702,3 +703,123 @@ func TestTxnResolveIntentsFromMultipleEpochs(t *testing.T) {
 		}
 	}
 }
+
+// TestTxnLockingReadBlocksWriter verifies that a locking read makes a
+// concurrent writer to the same key wait until the locking txn commits,
+// instead of making the locking txn restart.
+func TestTxnLockingReadBlocksWriter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	s := createTestDB(t)
+	defer s.Stop()
+	ctx := context.Background()
+
+	key := roachpb.Key("a")
+	if err := s.DB.Put(ctx, key, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	txn := client.NewTxn(ctx, s.DB, 0 /* gatewayNodeID */, client.RootTxn)
+	kv, err := txn.GetForUpdate(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- s.DB.Put(ctx, key, 10)
+	}()
+	select {
+	case err := <-errChan:
+		t.Fatalf("writer did not wait for the lock: %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	// The locking txn's own write and commit go through without a restart.
+	if err := txn.Put(ctx, key, kv.ValueInt()+1); err != nil {
+		t.Fatal(err)
+	}
+	if err := txn.Commit(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errChan; err != nil {
+		t.Fatal(err)
+	}
+	if kv, err := s.DB.Get(ctx, key); err != nil {
+		t.Fatal(err)
+	} else if v := kv.ValueInt(); v != 10 {
+		t.Fatalf("expected the writer's value 10, got %d", v)
+	}
+}
+
+// TestTxnLockingReadWaitPolicies verifies the behavior of locking reads that
+// run into keys locked by other txns when they are asked not to wait, and
+// that locks are released when their txn commits or aborts.
+func TestTxnLockingReadWaitPolicies(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	s := createTestDB(t)
+	defer s.Stop()
+	ctx := context.Background()
+
+	for _, k := range []string{"a", "b", "c"} {
+		if err := s.DB.Put(ctx, k, k); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testutils.RunTrueAndFalse(t, "commit", func(t *testing.T, commit bool) {
+		holder := client.NewTxn(ctx, s.DB, 0 /* gatewayNodeID */, client.RootTxn)
+		if _, err := holder.GetForUpdate(ctx, "b"); err != nil {
+			t.Fatal(err)
+		}
+
+		// NOWAIT returns an error instead of waiting for the lock.
+		noWait := client.NewTxn(ctx, s.DB, 0 /* gatewayNodeID */, client.RootTxn)
+		b := noWait.NewBatch()
+		b.Header.WaitPolicy = roachpb.LOCK_WAIT_ERROR
+		b.GetForUpdate("b")
+		err := noWait.Run(ctx, b)
+		if _, ok := err.(*roachpb.LockNotAvailableError); !ok {
+			t.Fatalf("expected LockNotAvailableError, got %T: %v", err, err)
+		}
+		if err := noWait.Rollback(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		// SKIP LOCKED leaves out the locked key.
+		skip := client.NewTxn(ctx, s.DB, 0 /* gatewayNodeID */, client.RootTxn)
+		b = skip.NewBatch()
+		b.Header.WaitPolicy = roachpb.LOCK_WAIT_SKIP
+		b.ScanForUpdate("a", "d")
+		if err := skip.Run(ctx, b); err != nil {
+			t.Fatal(err)
+		}
+		var keys []string
+		for _, kv := range b.Results[0].Rows {
+			keys = append(keys, string(kv.Key))
+		}
+		if exp := []string{"a", "c"}; !reflect.DeepEqual(keys, exp) {
+			t.Fatalf("expected keys %v, got %v", exp, keys)
+		}
+		if err := skip.Rollback(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		// Once the holder finishes, its lock is released.
+		if commit {
+			err = holder.Commit(ctx)
+		} else {
+			err = holder.Rollback(ctx)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.DB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+			b := txn.NewBatch()
+			b.Header.WaitPolicy = roachpb.LOCK_WAIT_ERROR
+			b.GetForUpdate("b")
+			return txn.Run(ctx, b)
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
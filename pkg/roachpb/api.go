@@ -141,6 +141,33 @@ func IsTransactionWrite(args Request) bool {
 	return (args.flags() & isTxnWrite) != 0
 }
 
+// KeyLockingOf returns the strength of the locks that the request acquires
+// over the keys it reads, which is LOCK_NONE for all but locking reads.
+func KeyLockingOf(args Request) KeyLockingStrength {
+	switch t := args.(type) {
+	case *GetRequest:
+		return t.KeyLocking
+	case *ScanRequest:
+		return t.KeyLocking
+	case *ReverseScanRequest:
+		return t.KeyLocking
+	}
+	return LOCK_NONE
+}
+
+// IsLockingRead returns true if the request is a read that acquires locks
+// over the keys it reads when used within a transaction.
+func IsLockingRead(args Request) bool {
+	return KeyLockingOf(args) != LOCK_NONE
+}
+
+// IsLocking returns true if the request either produces write intents or
+// acquires locks when used within a transaction. Like intents, these locks
+// need to be released when the transaction finishes.
+func IsLocking(args Request) bool {
+	return IsTransactionWrite(args) || IsLockingRead(args)
+}
+
 // IsRange returns true if the command is range-based and must include
 // a start and an end key.
 func IsRange(args Request) bool {
@@ -348,6 +375,20 @@ func (r *AdminScatterResponse) combine(c combinable) error {
 
 var _ combinable = &AdminScatterResponse{}
 
+// combine implements the combinable interface.
+func (r *QueryResolvedTimestampResponse) combine(c combinable) error {
+	if r != nil {
+		otherR := c.(*QueryResolvedTimestampResponse)
+		if err := r.ResponseHeader.combine(otherR.Header()); err != nil {
+			return err
+		}
+		r.ResolvedTS.Backward(otherR.ResolvedTS)
+	}
+	return nil
+}
+
+var _ combinable = &QueryResolvedTimestampResponse{}
+
 // Header implements the Request interface.
 func (rh RequestHeader) Header() RequestHeader {
 	return rh
@@ -578,6 +619,9 @@ func (*SubsumeRequest) Method() Method { return Subsume }
 // Method implements the Request interface.
 func (*RangeStatsRequest) Method() Method { return RangeStats }
 
+// Method implements the Request interface.
+func (*QueryResolvedTimestampRequest) Method() Method { return QueryResolvedTimestamp }
+
 // ShallowCopy implements the Request interface.
 func (gr *GetRequest) ShallowCopy() Request {
 	shallowCopy := *gr
@@ -818,6 +862,12 @@ func (r *RangeStatsRequest) ShallowCopy() Request {
 	return &shallowCopy
 }
 
+// ShallowCopy implements the Request interface.
+func (r *QueryResolvedTimestampRequest) ShallowCopy() Request {
+	shallowCopy := *r
+	return &shallowCopy
+}
+
 // NewGet returns a Request initialized to get the value at key.
 func NewGet(key Key) Request {
 	return &GetRequest{
@@ -827,6 +877,17 @@ func NewGet(key Key) Request {
 	}
 }
 
+// NewLockingGet returns a Request initialized to get the value at key and to
+// acquire a lock of the given strength over it.
+func NewLockingGet(key Key, strength KeyLockingStrength) Request {
+	return &GetRequest{
+		RequestHeader: RequestHeader{
+			Key: key,
+		},
+		KeyLocking: strength,
+	}
+}
+
 // NewIncrement returns a Request initialized to increment the value at
 // key by increment.
 func NewIncrement(key Key, increment int64) Request {
@@ -930,6 +991,18 @@ func NewScan(key, endKey Key) Request {
 	}
 }
 
+// NewLockingScan returns a Request initialized to scan from start to end keys
+// and to acquire locks of the given strength over the scanned keys.
+func NewLockingScan(key, endKey Key, strength KeyLockingStrength) Request {
+	return &ScanRequest{
+		RequestHeader: RequestHeader{
+			Key:    key,
+			EndKey: endKey,
+		},
+		KeyLocking: strength,
+	}
+}
+
 // NewReverseScan returns a Request initialized to reverse scan from end to
 // start keys with max results.
 func NewReverseScan(key, endKey Key) Request {
@@ -941,6 +1014,19 @@ func NewReverseScan(key, endKey Key) Request {
 	}
 }
 
+// NewLockingReverseScan returns a Request initialized to reverse scan from end
+// to start keys and to acquire locks of the given strength over the scanned
+// keys.
+func NewLockingReverseScan(key, endKey Key, strength KeyLockingStrength) Request {
+	return &ReverseScanRequest{
+		RequestHeader: RequestHeader{
+			Key:    key,
+			EndKey: endKey,
+		},
+		KeyLocking: strength,
+	}
+}
+
 func (*GetRequest) flags() int { return isRead | isTxn | updatesReadTSCache | needsRefresh }
 func (*PutRequest) flags() int { return isWrite | isTxn | isTxnWrite | consultsTSCache }
 
@@ -1075,6 +1161,10 @@ func (*SubsumeRequest) flags() int { return isRead | isAlone | updatesReadTSCach
 
 func (*RangeStatsRequest) flags() int { return isRead }
 
+// QueryResolvedTimestampRequest is usually executed in an INCONSISTENT batch,
+// so that it can be served by any replica.
+func (*QueryResolvedTimestampRequest) flags() int { return isRead | isRange }
+
 // Keys returns credentials in an aws.Config.
 func (b *ExportStorage_S3) Keys() *aws.Config {
 	return &aws.Config{
@@ -17,6 +17,8 @@ package roachpb
 import (
 	"reflect"
 	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 )
 
 // TestCombinable tests the correct behavior of some types that implement
@@ -91,6 +93,24 @@ func TestCombinable(t *testing.T) {
 	if !reflect.DeepEqual(dr1, wantedDR) {
 		t.Errorf("wanted %v, got %v", wantedDR, dr1)
 	}
+
+	// Test that QueryResolvedTimestampResponse keeps the minimum resolved
+	// timestamp.
+	qr1 := &QueryResolvedTimestampResponse{ResolvedTS: hlc.Timestamp{WallTime: 2}}
+	if _, ok := interface{}(qr1).(combinable); !ok {
+		t.Fatalf("QueryResolvedTimestampResponse does not implement combinable")
+	}
+	qr2 := &QueryResolvedTimestampResponse{ResolvedTS: hlc.Timestamp{WallTime: 1}}
+	qr3 := &QueryResolvedTimestampResponse{ResolvedTS: hlc.Timestamp{WallTime: 3}}
+	if err := qr1.combine(qr2); err != nil {
+		t.Fatal(err)
+	}
+	if err := qr1.combine(qr3); err != nil {
+		t.Fatal(err)
+	}
+	if expected := (hlc.Timestamp{WallTime: 1}); qr1.ResolvedTS != expected {
+		t.Errorf("wanted %s, got %s", expected, qr1.ResolvedTS)
+	}
 }
 
 // TestMustSetInner makes sure that calls to MustSetInner correctly reset the
@@ -117,6 +117,20 @@ func (ba *BatchRequest) IsTransactionWrite() bool {
 	return ba.hasFlag(isTxnWrite)
 }
 
+// IsLocking returns true iff the BatchRequest contains a txn write or a
+// locking read.
+func (ba *BatchRequest) IsLocking() bool {
+	if ba.IsTransactionWrite() {
+		return true
+	}
+	for _, union := range ba.Requests {
+		if IsLockingRead(union.GetInner()) {
+			return true
+		}
+	}
+	return false
+}
+
 // IsRange returns true iff the BatchRequest contains range-based requests.
 func (ba *BatchRequest) IsRange() bool {
 	return ba.hasFlag(isRange)
@@ -256,14 +270,18 @@ func (br *BatchResponse) String() string {
 }
 
 // IntentSpanIterate calls the passed method with the key ranges of the
-// transactional writes contained in the batch. Usually the key spans
-// contained in the requests are used, but when a response contains a
+// transactional writes and locking reads contained in the batch. Usually the
+// key spans contained in the requests are used, but when a response contains a
 // ResumeSpan the ResumeSpan is subtracted from the request span to provide a
 // more minimal span of keys affected by the request.
+//
+// The spans of locking reads are included because the locks they acquire are
+// released the same way as intents: by resolving them when the transaction
+// finishes.
 func (ba *BatchRequest) IntentSpanIterate(br *BatchResponse, fn func(Span)) {
 	for i, arg := range ba.Requests {
 		req := arg.GetInner()
-		if !IsTransactionWrite(req) {
+		if !IsLocking(req) {
 			continue
 		}
 		var resp Response
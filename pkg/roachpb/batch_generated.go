@@ -70,6 +70,8 @@ func (ru ErrorDetail) GetInner() error {
 		return t.MergeInProgress
 	case *ErrorDetail_RangefeedRetry:
 		return t.RangefeedRetry
+	case *ErrorDetail_LockNotAvailable:
+		return t.LockNotAvailable
 	default:
 		return nil
 	}
@@ -158,6 +160,8 @@ func (ru RequestUnion) GetInner() Request {
 		return t.Subsume
 	case *RequestUnion_RangeStats:
 		return t.RangeStats
+	case *RequestUnion_QueryResolvedTimestamp:
+		return t.QueryResolvedTimestamp
 	default:
 		return nil
 	}
@@ -244,6 +248,8 @@ func (ru ResponseUnion) GetInner() Response {
 		return t.Subsume
 	case *ResponseUnion_RangeStats:
 		return t.RangeStats
+	case *ResponseUnion_QueryResolvedTimestamp:
+		return t.QueryResolvedTimestamp
 	default:
 		return nil
 	}
@@ -311,6 +317,8 @@ func (ru *ErrorDetail) SetInner(r error) bool {
 		union = &ErrorDetail_MergeInProgress{t}
 	case *RangeFeedRetryError:
 		union = &ErrorDetail_RangefeedRetry{t}
+	case *LockNotAvailableError:
+		union = &ErrorDetail_LockNotAvailable{t}
 	default:
 		return false
 	}
@@ -402,6 +410,8 @@ func (ru *RequestUnion) SetInner(r Request) bool {
 		union = &RequestUnion_Subsume{t}
 	case *RangeStatsRequest:
 		union = &RequestUnion_RangeStats{t}
+	case *QueryResolvedTimestampRequest:
+		union = &RequestUnion_QueryResolvedTimestamp{t}
 	default:
 		return false
 	}
@@ -491,6 +501,8 @@ func (ru *ResponseUnion) SetInner(r Response) bool {
 		union = &ResponseUnion_Subsume{t}
 	case *RangeStatsResponse:
 		union = &ResponseUnion_RangeStats{t}
+	case *QueryResolvedTimestampResponse:
+		union = &ResponseUnion_QueryResolvedTimestamp{t}
 	default:
 		return false
 	}
@@ -498,7 +510,7 @@ func (ru *ResponseUnion) SetInner(r Response) bool {
 	return true
 }
 
-type reqCounts [40]int32
+type reqCounts [41]int32
 
 // getReqCounts returns the number of times each
 // request type appears in the batch.
@@ -586,6 +598,8 @@ func (ba *BatchRequest) getReqCounts() reqCounts {
 			counts[38]++
 		case *RequestUnion_RangeStats:
 			counts[39]++
+		case *RequestUnion_QueryResolvedTimestamp:
+			counts[40]++
 		default:
 			panic(fmt.Sprintf("unsupported request: %+v", ru))
 		}
@@ -634,6 +648,7 @@ var requestNames = []string{
 	"RefreshRng",
 	"Subsume",
 	"RngStats",
+	"QueryResolvedTimestamp",
 }
 
 // Summary prints a short summary of the requests in a batch.
@@ -817,6 +832,10 @@ type rangeStatsResponseAlloc struct {
 	union ResponseUnion_RangeStats
 	resp  RangeStatsResponse
 }
+type queryResolvedTimestampResponseAlloc struct {
+	union ResponseUnion_QueryResolvedTimestamp
+	resp  QueryResolvedTimestampResponse
+}
 
 // CreateReply creates replies for each of the contained requests, wrapped in a
 // BatchResponse. The response objects are batch allocated to minimize
@@ -867,6 +886,7 @@ func (ba *BatchRequest) CreateReply() *BatchResponse {
 	var buf37 []refreshRangeResponseAlloc
 	var buf38 []subsumeResponseAlloc
 	var buf39 []rangeStatsResponseAlloc
+	var buf40 []queryResolvedTimestampResponseAlloc
 
 	for i, r := range ba.Requests {
 		switch r.GetValue().(type) {
@@ -1150,6 +1170,13 @@ func (ba *BatchRequest) CreateReply() *BatchResponse {
 			buf39[0].union.RangeStats = &buf39[0].resp
 			br.Responses[i].Value = &buf39[0].union
 			buf39 = buf39[1:]
+		case *RequestUnion_QueryResolvedTimestamp:
+			if buf40 == nil {
+				buf40 = make([]queryResolvedTimestampResponseAlloc, counts[40])
+			}
+			buf40[0].union.QueryResolvedTimestamp = &buf40[0].resp
+			br.Responses[i].Value = &buf40[0].union
+			buf40 = buf40[1:]
 		default:
 			panic(fmt.Sprintf("unsupported request: %+v", r))
 		}
@@ -175,6 +175,8 @@ func TestIntentSpanIterate(t *testing.T) {
 			Span{Key("d"), Key("f")}, Span{Key("d"), Key("e")}},
 		{&DeleteRangeRequest{}, &DeleteRangeResponse{},
 			Span{Key("g"), Key("i")}, Span{Key("h"), Key("i")}},
+		{&ScanRequest{KeyLocking: LOCK_EXCLUSIVE}, &ScanResponse{},
+			Span{Key("j"), Key("l")}, Span{Key("k"), Key("l")}},
 	}
 
 	// A batch request with a batch response with no ResumeSpan.
@@ -191,12 +193,10 @@ func TestIntentSpanIterate(t *testing.T) {
 		spans = append(spans, span)
 	}
 	ba.IntentSpanIterate(&br, fn)
-	// Only DeleteRangeResponse is a write request.
-	if e := 1; len(spans) != e {
-		t.Fatalf("unexpected number of spans: e = %d, found = %d", e, len(spans))
-	}
-	if e := testCases[2].span; !reflect.DeepEqual(e, spans[0]) {
-		t.Fatalf("unexpected spans: e = %+v, found = %+v", e, spans[0])
+	// Only DeleteRangeResponse is a write request, and only the last
+	// ScanRequest is a locking read.
+	if e := []Span{testCases[2].span, testCases[3].span}; !reflect.DeepEqual(e, spans) {
+		t.Fatalf("unexpected spans: e = %+v, found = %+v", e, spans)
 	}
 
 	// A batch request with a batch response with a ResumeSpan.
@@ -211,12 +211,10 @@ func TestIntentSpanIterate(t *testing.T) {
 
 	spans = []Span{}
 	ba.IntentSpanIterate(&br, fn)
-	// Only DeleteRangeResponse is a write request.
-	if e := 1; len(spans) != e {
-		t.Fatalf("unexpected number of spans: e = %d, found = %d", e, len(spans))
-	}
-	if e := (Span{Key("g"), Key("h")}); !reflect.DeepEqual(e, spans[0]) {
-		t.Fatalf("unexpected spans: e = %+v, found = %+v", e, spans[0])
+	// Only DeleteRangeResponse is a write request, and only the last
+	// ScanRequest is a locking read.
+	if e := []Span{{Key("g"), Key("h")}, {Key("j"), Key("k")}}; !reflect.DeepEqual(e, spans) {
+		t.Fatalf("unexpected spans: e = %+v, found = %+v", e, spans)
 	}
 }
 
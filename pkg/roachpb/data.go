@@ -805,6 +805,7 @@ func (t Transaction) Clone() Transaction {
 	// Note that we're not cloning the span keys under the assumption that the
 	// keys themselves are not mutable.
 	t.Intents = append([]Span(nil), t.Intents...)
+	t.IgnoredSeqNums = append([]enginepb.IgnoredSeqNumRange(nil), t.IgnoredSeqNums...)
 	return t
 }
 
@@ -924,6 +925,9 @@ func (t *Transaction) Restart(
 	t.WriteTooOld = false
 	t.RetryOnPush = false
 	t.Sequence = 0
+	// Sequence numbers start over in the new epoch, so the ranges rolled back
+	// in the old one no longer mean anything.
+	t.IgnoredSeqNums = nil
 	// Reset Writing. Since we're using a new epoch, we don't care about the abort
 	// cache.
 	t.Writing = false
@@ -984,6 +988,15 @@ func (t *Transaction) Update(o *Transaction) {
 		t.OrigTimestampWasObserved = t.OrigTimestampWasObserved || o.OrigTimestampWasObserved
 	}
 
+	// The ignored seqnum ranges are reset when the epoch is bumped and only
+	// ever grow within an epoch, so the list reaching the highest sequence
+	// number is the most recent one.
+	if t.Epoch < o.Epoch {
+		t.IgnoredSeqNums = o.IgnoredSeqNums
+	} else if t.Epoch == o.Epoch && lastIgnoredSeqNum(t.IgnoredSeqNums) < lastIgnoredSeqNum(o.IgnoredSeqNums) {
+		t.IgnoredSeqNums = o.IgnoredSeqNums
+	}
+
 	if t.Epoch < o.Epoch {
 		t.Epoch = o.Epoch
 	}
@@ -1018,6 +1031,42 @@ func (t *Transaction) Update(o *Transaction) {
 	}
 }
 
+// lastIgnoredSeqNum returns the highest sequence number covered by the given
+// list of ignored seqnum ranges, or zero if the list is empty.
+func lastIgnoredSeqNum(ignored []enginepb.IgnoredSeqNumRange) int32 {
+	if len(ignored) == 0 {
+		return 0
+	}
+	return ignored[len(ignored)-1].End
+}
+
+// AddIgnoredSeqNumRange adds the given range to the transaction's list of
+// ignored seqnum ranges, keeping the list sorted and merging the ranges that
+// overlap or abut the new one.
+func (t *Transaction) AddIgnoredSeqNumRange(newRange enginepb.IgnoredSeqNumRange) {
+	ret := make([]enginepb.IgnoredSeqNumRange, 0, len(t.IgnoredSeqNums)+1)
+	for _, r := range t.IgnoredSeqNums {
+		switch {
+		case r.End+1 < newRange.Start || newRange.End+1 < r.Start:
+			ret = append(ret, r)
+		default:
+			if r.Start < newRange.Start {
+				newRange.Start = r.Start
+			}
+			if r.End > newRange.End {
+				newRange.End = r.End
+			}
+		}
+	}
+	idx := sort.Search(len(ret), func(i int) bool {
+		return ret[i].Start > newRange.Start
+	})
+	ret = append(ret, enginepb.IgnoredSeqNumRange{})
+	copy(ret[idx+1:], ret[idx:])
+	ret[idx] = newRange
+	t.IgnoredSeqNums = ret
+}
+
 // UpgradePriority sets transaction priority to the maximum of current
 // priority and the specified minPriority. The exception is if the
 // current priority is set to the minimum, in which case the minimum
@@ -1049,6 +1098,9 @@ func (t Transaction) String() string {
 	if ni := len(t.Intents); t.Status != PENDING && ni > 0 {
 		fmt.Fprintf(&buf, " int=%d", ni)
 	}
+	if ni := len(t.IgnoredSeqNums); ni > 0 {
+		fmt.Fprintf(&buf, " isn=%d", ni)
+	}
 	return buf.String()
 }
 
@@ -1409,16 +1461,23 @@ func (l *Lease) Equal(that interface{}) bool {
 	return true
 }
 
+// MakeIntent makes an intent for the given span of the given transaction,
+// carrying its status and ignored seqnum ranges.
+func MakeIntent(txn *Transaction, span Span) Intent {
+	return Intent{
+		Span:           span,
+		Txn:            txn.TxnMeta,
+		Status:         txn.Status,
+		IgnoredSeqNums: txn.IgnoredSeqNums,
+	}
+}
+
 // AsIntents takes a slice of spans and returns it as a slice of intents for
 // the given transaction.
 func AsIntents(spans []Span, txn *Transaction) []Intent {
 	ret := make([]Intent, len(spans))
 	for i := range spans {
-		ret[i] = Intent{
-			Span:   spans[i],
-			Txn:    txn.TxnMeta,
-			Status: txn.Status,
-		}
+		ret[i] = MakeIntent(txn, spans[i])
 	}
 	return ret
 }
@@ -703,3 +703,23 @@ func (e *RangeFeedRetryError) message(pErr *Error) string {
 }
 
 var _ ErrorDetailInterface = &RangeFeedRetryError{}
+
+// NewLockNotAvailableError creates a new LockNotAvailableError.
+func NewLockNotAvailableError(lock Intent) *LockNotAvailableError {
+	return &LockNotAvailableError{
+		Lock: &lock,
+	}
+}
+
+func (e *LockNotAvailableError) Error() string {
+	return e.message(nil)
+}
+
+func (e *LockNotAvailableError) message(_ *Error) string {
+	if e.Lock == nil {
+		return "could not acquire lock"
+	}
+	return fmt.Sprintf("could not acquire lock on %s held by txn %s", e.Lock.Span, e.Lock.Txn.ID.Short())
+}
+
+var _ ErrorDetailInterface = &LockNotAvailableError{}
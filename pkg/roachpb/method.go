@@ -147,4 +147,7 @@ const (
 	Subsume
 	// RangeStats returns the MVCC statistics for a range.
 	RangeStats
+	// QueryResolvedTimestamp returns the timestamp at or below which a replica
+	// can serve consistent reads without consulting the lease holder.
+	QueryResolvedTimestamp
 )
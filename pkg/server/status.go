@@ -66,6 +66,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
 
 const (
@@ -1489,6 +1490,84 @@ func (s *statusServer) ListSessions(
 	return response, nil
 }
 
+// ListLocalTransactionWaits returns the transactions waiting on other
+// transactions in the txn wait queues of the stores on this node.
+func (s *statusServer) ListLocalTransactionWaits(
+	ctx context.Context, req *serverpb.ListTransactionWaitsRequest,
+) (*serverpb.ListTransactionWaitsResponse, error) {
+	ctx = propagateGatewayMetadata(ctx)
+	if !debug.GatewayRemoteAllowed(ctx, s.st) {
+		return nil, remoteDebuggingErr
+	}
+
+	nodeID := s.gossip.NodeID.Get()
+	response := &serverpb.ListTransactionWaitsResponse{
+		Waits: make([]serverpb.TransactionWait, 0),
+	}
+	err := s.stores.VisitStores(func(store *storage.Store) error {
+		for _, w := range store.TxnWaits() {
+			wait := serverpb.TransactionWait{
+				NodeID:        nodeID,
+				RangeID:       w.RangeID,
+				BlockingTxnID: w.Pushee.ID,
+				Key:           w.Key,
+				WaitStart:     w.Start,
+			}
+			if w.Pusher.ID != (uuid.UUID{}) {
+				pusherID := w.Pusher.ID
+				wait.WaitingTxnID = &pusherID
+			}
+			response.Waits = append(response.Waits, wait)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// ListTransactionWaits returns the transactions waiting on other transactions
+// in the txn wait queues of all nodes in the cluster.
+func (s *statusServer) ListTransactionWaits(
+	ctx context.Context, req *serverpb.ListTransactionWaitsRequest,
+) (*serverpb.ListTransactionWaitsResponse, error) {
+	ctx = propagateGatewayMetadata(ctx)
+	if !debug.GatewayRemoteAllowed(ctx, s.st) {
+		return nil, remoteDebuggingErr
+	}
+
+	ctx = s.AnnotateCtx(ctx)
+
+	response := &serverpb.ListTransactionWaitsResponse{
+		Waits:  make([]serverpb.TransactionWait, 0),
+		Errors: make([]serverpb.ListTransactionWaitsError, 0),
+	}
+
+	dialFn := func(ctx context.Context, nodeID roachpb.NodeID) (interface{}, error) {
+		client, err := s.dialNode(ctx, nodeID)
+		return client, err
+	}
+	nodeFn := func(ctx context.Context, client interface{}, _ roachpb.NodeID) (interface{}, error) {
+		status := client.(serverpb.StatusClient)
+		return status.ListLocalTransactionWaits(ctx, req)
+	}
+	responseFn := func(_ roachpb.NodeID, nodeResp interface{}) {
+		waits := nodeResp.(*serverpb.ListTransactionWaitsResponse)
+		response.Waits = append(response.Waits, waits.Waits...)
+	}
+	errorFn := func(nodeID roachpb.NodeID, err error) {
+		errResponse := serverpb.ListTransactionWaitsError{NodeID: nodeID, Message: err.Error()}
+		response.Errors = append(response.Errors, errResponse)
+	}
+
+	if err := s.iterateNodes(ctx, "transaction wait list", dialFn, nodeFn, responseFn, errorFn); err != nil {
+		err := serverpb.ListTransactionWaitsError{Message: err.Error()}
+		response.Errors = append(response.Errors, err)
+	}
+	return response, nil
+}
+
 // CancelSession responds to a session cancellation request by canceling the
 // target session's associated context.
 func (s *statusServer) CancelSession(
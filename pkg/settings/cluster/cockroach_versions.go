@@ -70,6 +70,12 @@ const (
 	VersionCreateChangefeed
 	VersionRangeMerges
 	VersionBitArrayColumns
+	VersionImportIntoExisting
+	VersionVirtualComputedColumns
+	VersionSavepoints
+	VersionBoundedStaleness
+	VersionEnums
+	VersionBackupEncryption
 
 	// Add new versions here (step one of two).
 
@@ -277,6 +283,45 @@ var versionsSingleton = keyedVersions([]keyedVersion{
 		Key:     VersionBitArrayColumns,
 		Version: roachpb.Version{Major: 2, Minor: 0, Unstable: 13},
 	},
+	{
+		// VersionImportIntoExisting is IMPORT INTO an existing table, which
+		// relies on AddSSTable's disallow_shadowing flag.
+		Key:     VersionImportIntoExisting,
+		Version: roachpb.Version{Major: 2, Minor: 0, Unstable: 14},
+	},
+	{
+		// VersionVirtualComputedColumns is computed columns whose values are
+		// not stored in the primary index.
+		Key:     VersionVirtualComputedColumns,
+		Version: roachpb.Version{Major: 2, Minor: 0, Unstable: 15},
+	},
+	{
+		// VersionSavepoints is regular (i.e. not cockroach_restart) savepoints,
+		// which rely on intents keeping their history and on the ignored
+		// sequence numbers of a txn being honored when its intents are
+		// resolved.
+		Key:     VersionSavepoints,
+		Version: roachpb.Version{Major: 2, Minor: 0, Unstable: 16},
+	},
+	{
+		// VersionBoundedStaleness is the QueryResolvedTimestamp request, which
+		// bounded staleness reads use to pick their timestamp.
+		Key:     VersionBoundedStaleness,
+		Version: roachpb.Version{Major: 2, Minor: 0, Unstable: 17},
+	},
+	{
+		// VersionEnums is user-defined ENUM types, which are stored as type
+		// descriptors and referenced by ENUM columns.
+		Key:     VersionEnums,
+		Version: roachpb.Version{Major: 2, Minor: 0, Unstable: 18},
+	},
+	{
+		// VersionBackupEncryption is the encryption field of ExportRequest and
+		// ImportRequest, which encrypted backups use to encrypt and decrypt the
+		// files they write and read.
+		Key:     VersionBackupEncryption,
+		Version: roachpb.Version{Major: 2, Minor: 0, Unstable: 19},
+	},
 
 	// Add new versions here (step two of two).
 
@@ -126,6 +126,15 @@ func (n *alterTableNode) startExec(params runParams) error {
 			if err != nil {
 				return err
 			}
+			if col.Virtual {
+				// The values of virtual columns are not backfilled, which is
+				// where the expressions of stored computed columns get checked.
+				if err := validateComputedColumn(
+					*n.tableDesc, nil /* t */, d, &params.p.semaCtx, params.EvalContext(),
+				); err != nil {
+					return err
+				}
+			}
 			// If the new column has a DEFAULT expression that uses a sequence, add references between
 			// its descriptor and this column descriptor.
 			if d.HasDefaultExpr() {
@@ -195,7 +204,21 @@ func (n *alterTableNode) startExec(params runParams) error {
 					Unique:           true,
 					StoreColumnNames: d.Storing.ToStrings(),
 				}
-				if err := idx.FillColumns(d.Columns); err != nil {
+				tableName, err := n.n.Table.Normalize()
+				if err != nil {
+					return err
+				}
+				columns, err := replaceIndexExprs(
+					params.ctx, n.tableDesc, tableName, d.Columns,
+					func(col sqlbase.ColumnDescriptor) {
+						n.tableDesc.AddColumnMutation(col, sqlbase.DescriptorMutation_ADD)
+					},
+					&params.p.semaCtx, params.EvalContext(),
+				)
+				if err != nil {
+					return err
+				}
+				if err := idx.FillColumns(columns); err != nil {
 					return err
 				}
 				if d.PartitionBy != nil {
@@ -327,6 +350,15 @@ func (n *alterTableNode) startExec(params runParams) error {
 			if n.tableDesc.PrimaryIndex.ContainsColumnID(col.ID) {
 				return fmt.Errorf("column %q is referenced by the primary key", col.Name)
 			}
+			if col.IsIndexExpr() {
+				return fmt.Errorf("column %q holds the values of an index expression, drop the index instead", col.Name)
+			}
+			// The columns holding the values of index expressions over the
+			// column are dropped along with their indexes.
+			exprColIDs, err := indexExprColumnsUsing(n.tableDesc, col.ID)
+			if err != nil {
+				return err
+			}
 			for _, idx := range n.tableDesc.AllNonDropIndexes() {
 				// We automatically drop indexes on that column that only
 				// index that column (and no other columns). If CASCADE is
@@ -351,7 +383,7 @@ func (n *alterTableNode) startExec(params runParams) error {
 
 				// Analyze the index.
 				for _, id := range idx.ColumnIDs {
-					if id == col.ID {
+					if _, ok := exprColIDs[id]; ok || id == col.ID {
 						containsThisColumn = true
 					} else {
 						containsOnlyThisColumn = false
@@ -664,6 +696,12 @@ func applyColumnMutation(
 ) error {
 	switch t := mut.(type) {
 	case *tree.AlterTableAlterColumnType:
+		toType, err := params.p.semaCtx.ResolveType(t.ToType)
+		if err != nil {
+			return err
+		}
+		t.ToType = toType
+
 		// Convert the parsed type into one of the basic datum types.
 		datum := coltypes.CastTargetToDatumType(t.ToType)
 
@@ -726,6 +764,9 @@ func applyColumnMutation(
 		if t.Default == nil {
 			col.DefaultExpr = nil
 		} else {
+			if err := sqlbase.CheckNoUserDefinedTypeReferences(t.Default, "DEFAULT"); err != nil {
+				return err
+			}
 			colDatumType := col.Type.ToDatumType()
 			expr, err := sqlbase.SanitizeVarFreeExpr(
 				t.Default, colDatumType, "DEFAULT", &params.p.semaCtx, params.EvalContext(), true, /* allowImpure */
@@ -752,6 +793,11 @@ func applyColumnMutation(
 		col.Nullable = true
 
 	case *tree.AlterTableDropStored:
+		if col.Virtual {
+			// The column has no stored values to keep.
+			return pgerror.NewErrorf(pgerror.CodeInvalidColumnDefinitionError,
+				"column %q is a virtual computed column", col.Name)
+		}
 		col.ComputeExpr = nil
 	}
 	return nil
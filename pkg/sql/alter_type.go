@@ -0,0 +1,164 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+type alterTypeNode struct {
+	n    *tree.AlterTypeAddValue
+	desc *sqlbase.TypeDescriptor
+}
+
+// AlterTypeAddValue adds a value to an enum type.
+// Privileges: CREATE on type.
+//   notes: postgres requires owner on type.
+func (p *planner) AlterTypeAddValue(
+	ctx context.Context, n *tree.AlterTypeAddValue,
+) (planNode, error) {
+	name, err := n.Name.Normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	desc, err := p.resolveTypeDesc(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.CheckPrivilege(ctx, desc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	return &alterTypeNode{n: n, desc: desc}, nil
+}
+
+func (n *alterTypeNode) startExec(params runParams) error {
+	members := n.desc.EnumMembers
+	for i := range members {
+		if members[i].LogicalRepresentation == n.n.NewLabel {
+			if n.n.IfNotExists {
+				return nil
+			}
+			return pgerror.NewErrorf(pgerror.CodeDuplicateObjectError,
+				"enum label %q already exists", n.n.NewLabel)
+		}
+	}
+
+	// By default, the new value is added after all the existing ones.
+	pos := len(members)
+	if pl := n.n.Placement; pl != nil {
+		pos = -1
+		for i := range members {
+			if members[i].LogicalRepresentation == pl.ExistingLabel {
+				pos = i
+				break
+			}
+		}
+		if pos == -1 {
+			return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+				"%q is not an existing enum label", pl.ExistingLabel)
+		}
+		if !pl.Before {
+			pos++
+		}
+	}
+	var prev, next []byte
+	if pos > 0 {
+		prev = members[pos-1].PhysicalRepresentation
+	}
+	if pos < len(members) {
+		next = members[pos].PhysicalRepresentation
+	}
+	physical, err := sqlbase.GenByteStringBetween(prev, next)
+	if err != nil {
+		return err
+	}
+	member := sqlbase.TypeDescriptor_EnumMember{
+		PhysicalRepresentation: physical,
+		LogicalRepresentation:  n.n.NewLabel,
+	}
+
+	// The members of the type descriptor are always writable: the type
+	// descriptor is not leased, and is only used to resolve the type of new
+	// values. The copies of the members held by the columns of the tables
+	// which use the type are what prevents writes of the new value until all
+	// the nodes can decode it.
+	n.desc.EnumMembers = append(n.desc.EnumMembers, sqlbase.TypeDescriptor_EnumMember{})
+	copy(n.desc.EnumMembers[pos+1:], n.desc.EnumMembers[pos:])
+	n.desc.EnumMembers[pos] = member
+	if err := n.desc.Validate(); err != nil {
+		return err
+	}
+
+	descKey := sqlbase.MakeDescMetadataKey(n.desc.ID)
+	descVal := sqlbase.WrapDescriptor(n.desc)
+	if params.p.extendedEvalCtx.Tracing.KVTracingEnabled() {
+		log.VEventf(params.ctx, 2, "Put %s -> %s", descKey, descVal)
+	}
+	if err := params.p.txn.Put(params.ctx, descKey, descVal); err != nil {
+		return err
+	}
+
+	// Tables in any database can use the type.
+	descs, err := GetAllDescriptors(params.ctx, params.p.txn)
+	if err != nil {
+		return err
+	}
+	for _, d := range descs {
+		tableDesc, ok := d.(*sqlbase.TableDescriptor)
+		if !ok || tableDesc.Dropped() {
+			continue
+		}
+		if !tableDesc.AddEnumMember(n.desc.ID, member) {
+			continue
+		}
+		if params.p.Tables().isCreatedTable(tableDesc.ID) {
+			// No other node knows about the table yet.
+			tableDesc.MakeEnumMembersWritable()
+		}
+		if err := params.p.writeSchemaChange(
+			params.ctx, tableDesc, sqlbase.InvalidMutationID,
+		); err != nil {
+			return err
+		}
+	}
+
+	// Log Alter Type event. This is an auditable log event and is
+	// recorded in the same transaction as the type descriptor update.
+	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+		params.ctx,
+		params.p.txn,
+		EventLogAlterType,
+		int32(n.desc.ID),
+		int32(params.extendedEvalCtx.NodeID),
+		struct {
+			TypeName  string
+			Statement string
+			User      string
+		}{n.desc.Name, n.n.String(), params.SessionData().User},
+	)
+}
+
+func (*alterTypeNode) Next(runParams) (bool, error) { return false, nil }
+func (*alterTypeNode) Values() tree.Datums          { return tree.Datums{} }
+func (*alterTypeNode) Close(context.Context)        {}
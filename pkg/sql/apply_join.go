@@ -0,0 +1,170 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// applyJoinNode implements an apply join, that is a join whose right side
+// refers to the columns of its left side, for instance a LATERAL subquery
+// which the optimizer could not decorrelate. For every row of the left side,
+// the right side is planned with these references bound to the values of the
+// row, then run, and its rows are joined with the left row.
+//
+// Only the join types which don't need to know about the right rows that
+// match no left row are supported: inner, left outer, semi and anti joins.
+type applyJoinNode struct {
+	joinType sqlbase.JoinType
+
+	// input is the left side of the join.
+	input planDataSource
+
+	// right is the plan of the right side with NULL values for the columns of
+	// the left side. It is never run: it is only there for EXPLAIN.
+	right planNode
+
+	// planRightSideFn creates the plan of the right side for the given left
+	// row.
+	planRightSideFn applyJoinPlanRightSideFn
+
+	// pred evaluates the ON condition, and describes the columns of the join.
+	pred    *joinPredicate
+	columns sqlbase.ResultColumns
+
+	run applyJoinRun
+}
+
+// applyJoinPlanRightSideFn creates the plan of the right side of an
+// applyJoinNode for the given row of its left side.
+type applyJoinPlanRightSideFn func(params runParams, leftRow tree.Datums) (planNode, error)
+
+// applyJoinRun contains the run-time state of applyJoinNode during local
+// execution.
+type applyJoinRun struct {
+	// leftRow is the current row of the left side.
+	leftRow tree.Datums
+	// rightPlan is the plan of the right side for leftRow, or nil once all its
+	// rows were read.
+	rightPlan planNode
+	// matched is set once a row of rightPlan satisfied the ON condition.
+	matched bool
+	// out is the row produced by the join.
+	out tree.Datums
+}
+
+func (n *applyJoinNode) startExec(params runParams) error {
+	// The left side was not started along with the rest of the plan (see
+	// startExec), so that the right side isn't started either.
+	if err := startExec(params, n.input.plan); err != nil {
+		return err
+	}
+	n.run.out = make(tree.Datums, len(n.columns))
+	return nil
+}
+
+func (n *applyJoinNode) Next(params runParams) (bool, error) {
+	for {
+		if err := params.p.cancelChecker.Check(); err != nil {
+			return false, err
+		}
+		if n.run.rightPlan == nil {
+			// Move on to the next left row, and plan the right side for it.
+			next, err := n.input.plan.Next(params)
+			if err != nil || !next {
+				return false, err
+			}
+			n.run.leftRow = n.input.plan.Values()
+			n.run.matched = false
+			plan, err := n.planRightSideFn(params, n.run.leftRow)
+			if err != nil {
+				return false, err
+			}
+			n.run.rightPlan = plan
+			if err := startPlan(params, plan); err != nil {
+				return false, err
+			}
+		}
+
+		next, err := n.run.rightPlan.Next(params)
+		if err != nil {
+			return false, err
+		}
+		if !next {
+			n.closeRightPlan(params.ctx)
+			if n.run.matched {
+				continue
+			}
+			switch n.joinType {
+			case sqlbase.LeftOuterJoin:
+				// The left row is emitted with NULL values for the right side.
+				copy(n.run.out, n.run.leftRow)
+				for i := len(n.run.leftRow); i < len(n.run.out); i++ {
+					n.run.out[i] = tree.DNull
+				}
+				return true, nil
+			case sqlbase.LeftAntiJoin:
+				copy(n.run.out, n.run.leftRow)
+				return true, nil
+			}
+			continue
+		}
+
+		rightRow := n.run.rightPlan.Values()
+		if pass, err := n.pred.eval(params.EvalContext(), n.run.leftRow, rightRow); err != nil {
+			return false, err
+		} else if !pass {
+			continue
+		}
+		n.run.matched = true
+		switch n.joinType {
+		case sqlbase.LeftSemiJoin:
+			// The left row is emitted once, so the rest of the right side
+			// doesn't need to be read.
+			n.closeRightPlan(params.ctx)
+			copy(n.run.out, n.run.leftRow)
+			return true, nil
+		case sqlbase.LeftAntiJoin:
+			n.closeRightPlan(params.ctx)
+			continue
+		}
+		n.pred.prepareRow(n.run.out, n.run.leftRow, rightRow)
+		return true, nil
+	}
+}
+
+// closeRightPlan closes the plan of the right side for the current left row.
+func (n *applyJoinNode) closeRightPlan(ctx context.Context) {
+	n.run.rightPlan.Close(ctx)
+	n.run.rightPlan = nil
+}
+
+func (n *applyJoinNode) Values() tree.Datums {
+	return n.run.out
+}
+
+func (n *applyJoinNode) Close(ctx context.Context) {
+	n.input.plan.Close(ctx)
+	if n.right != nil {
+		n.right.Close(ctx)
+		n.right = nil
+	}
+	if n.run.rightPlan != nil {
+		n.closeRightPlan(ctx)
+	}
+}
@@ -171,10 +171,11 @@ func init() {
 }
 
 // TypeForNonKeywordTypeName returns the column type for the string name of a
-// type, if one exists.
-func TypeForNonKeywordTypeName(name string) (T, error) {
+// type. Names which are not the names of predefined types are references to
+// user-defined types, which are resolved later.
+func TypeForNonKeywordTypeName(name string) T {
 	if typ, ok := typNameLiterals[name]; ok {
-		return typ, nil
+		return typ
 	}
-	return nil, pgerror.NewError(pgerror.CodeUndefinedObjectError, "type does not exist")
+	return &TUserDefined{Name: name}
 }
@@ -133,6 +133,10 @@ func DatumTypeToColumnType(t types.T) (T, error) {
 		return colTyp, nil
 	case types.TOidWrapper:
 		return DatumTypeToColumnType(typ.T)
+	case types.TEnum:
+		if !typ.IsAmbiguous() {
+			return &TEnum{Typ: typ}, nil
+		}
 	}
 
 	return nil, pgerror.NewErrorf(pgerror.CodeInvalidTableDefinitionError,
@@ -205,6 +209,12 @@ func CastTargetToDatumType(t CastTargetType) types.T {
 		return ret
 	case *TOid:
 		return TOidToType(ct)
+	case *TEnum:
+		return ct.Typ
+	case *TUserDefined:
+		// The name hasn't been resolved yet. Enums are the only user-defined
+		// types, so the type is in the enum family.
+		return types.FamEnum
 	default:
 		panic(fmt.Sprintf("unexpected CastTarget %T", t))
 	}
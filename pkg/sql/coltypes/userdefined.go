@@ -0,0 +1,58 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package coltypes
+
+import (
+	"bytes"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+)
+
+// This file contains the column types which refer to user-defined types.
+
+// TUserDefined is a reference by name to a user-defined type, as it is
+// parsed. It must be resolved (see tree.SemaContext.ResolveType) into the type
+// it names before it is used.
+type TUserDefined struct {
+	// Schema is the schema the name was qualified with, if any.
+	Schema string
+	Name   string
+}
+
+// TypeName implements the ColTypeFormatter interface.
+func (node *TUserDefined) TypeName() string { return node.Name }
+
+// Format implements the ColTypeFormatter interface.
+func (node *TUserDefined) Format(buf *bytes.Buffer, f lex.EncodeFlags) {
+	if node.Schema != "" {
+		lex.EncodeRestrictedSQLIdent(buf, node.Schema, f)
+		buf.WriteByte('.')
+	}
+	lex.EncodeRestrictedSQLIdent(buf, node.Name, f)
+}
+
+// TEnum represents a user-defined enum type.
+type TEnum struct {
+	Typ types.TEnum
+}
+
+// TypeName implements the ColTypeFormatter interface.
+func (node *TEnum) TypeName() string { return node.Typ.Name }
+
+// Format implements the ColTypeFormatter interface.
+func (node *TEnum) Format(buf *bytes.Buffer, f lex.EncodeFlags) {
+	lex.EncodeRestrictedSQLIdent(buf, node.Typ.Name, f)
+}
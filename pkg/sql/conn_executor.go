@@ -696,6 +696,9 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 	_ = ex.synchronizeParallelStmts(ctx)
 
 	if closeType == normalClose {
+		// The KV txn of an Aborted SQL txn might not have been rolled back yet if
+		// the txn had savepoints.
+		ex.state.maybeRollbackAbortedTxn()
 		// We'll cleanup the SQL txn by creating a non-retriable (commit:true) event.
 		// This event is guaranteed to be accepted in every state.
 		ev := eventNonRetriableErr{IsCommit: fsm.FromBool(true)}
@@ -1379,7 +1382,7 @@ func (ex *connExecutor) setTxnRewindPos(ctx context.Context, pos CmdPos) {
 // statement do not change with retries.
 func (ex *connExecutor) stmtDoesntNeedRetry(stmt tree.Statement) bool {
 	wrap := Statement{AST: stmt}
-	return isSavepoint(wrap) || isSetTransaction(wrap)
+	return isRestartSavepoint(wrap) || isSetTransaction(wrap)
 }
 
 // tryReusePreparedState checks whether it's possible to reuse information that
@@ -1819,6 +1822,7 @@ func (ex *connExecutor) resetPlanner(
 	p.semaCtx = tree.MakeSemaContext(ex.sessionData.User == security.RootUser)
 	p.semaCtx.Location = &ex.sessionData.DataConversion.Location
 	p.semaCtx.SearchPath = ex.sessionData.SearchPath
+	p.semaCtx.TypeResolver = p
 	p.semaCtx.AsOfTimestamp = nil
 
 	p.extendedEvalCtx = ex.evalCtx(ctx, p, stmtTS)
@@ -1831,6 +1835,7 @@ func (ex *connExecutor) resetPlanner(
 	p.autoCommit = false
 	p.isPreparing = false
 	p.avoidCachedDescriptors = false
+	p.boundedStaleness = false
 }
 
 // txnStateTransitionsApplyWrapper is a wrapper on top of Machine built with the
@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/coltypes"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -220,8 +221,11 @@ func (ex *connExecutor) execStmtInOpenState(
 		return ev, payload, nil
 
 	case *tree.ReleaseSavepoint:
-		if err := tree.ValidateRestartCheckpoint(s.Savepoint); err != nil {
-			return makeErrEvent(err)
+		if !tree.IsRestartSavepoint(s.Savepoint) {
+			if err := ex.execReleaseSavepointInOpenState(ctx, s.Savepoint); err != nil {
+				return makeErrEvent(err)
+			}
+			return nil, nil, nil
 		}
 		if !ex.machine.CurState().(stateOpen).RetryIntent.Get() {
 			return makeErrEvent(errSavepointNotUsed)
@@ -238,8 +242,11 @@ func (ex *connExecutor) execStmtInOpenState(
 		return ev, payload, nil
 
 	case *tree.Savepoint:
-		if err := tree.ValidateRestartCheckpoint(s.Name); err != nil {
-			return makeErrEvent(err)
+		if !tree.IsRestartSavepoint(s.Name) {
+			if err := ex.execSavepointInOpenState(ctx, s.Name); err != nil {
+				return makeErrEvent(err)
+			}
+			return nil, nil, nil
 		}
 		// We want to disallow SAVEPOINTs to be issued after a transaction has
 		// started running. The client txn's statement count indicates how many
@@ -255,8 +262,11 @@ func (ex *connExecutor) execStmtInOpenState(
 		return eventRetryIntentSet{}, nil /* payload */, nil
 
 	case *tree.RollbackToSavepoint:
-		if err := tree.ValidateRestartCheckpoint(s.Savepoint); err != nil {
-			return makeErrEvent(err)
+		if !tree.IsRestartSavepoint(s.Savepoint) {
+			if err := ex.execRollbackToSavepoint(ctx, s.Savepoint); err != nil {
+				return makeErrEvent(err)
+			}
+			return nil, nil, nil
 		}
 		if !os.RetryIntent.Get() {
 			return makeErrEvent(errSavepointNotUsed)
@@ -278,6 +288,12 @@ func (ex *connExecutor) execStmtInOpenState(
 		}
 		typeHints := make(tree.PlaceholderTypes, len(s.Types))
 		for i, t := range s.Types {
+			if _, ok := t.(*coltypes.TUserDefined); ok {
+				// Type names are resolved by the planner, which the
+				// placeholder types are needed to create.
+				return makeErrEvent(pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+					"user-defined type %s cannot be used as a parameter type", t))
+			}
 			typeHints[strconv.Itoa(i+1)] = coltypes.CastTargetToDatumType(t)
 		}
 		if _, err := ex.addPreparedStmt(ctx, name, Statement{AST: s.Statement}, typeHints); err != nil {
@@ -337,31 +353,42 @@ func (ex *connExecutor) execStmtInOpenState(
 	}
 
 	if os.ImplicitTxn.Get() {
-		ts, err := p.isAsOf(stmt.AST, ex.server.cfg.Clock.Now())
+		asOf, err := p.isAsOf(stmt.AST, ex.server.cfg.Clock.Now())
 		if err != nil {
 			return makeErrEvent(err)
 		}
-		if ts != nil {
-			p.semaCtx.AsOfTimestamp = ts
-			p.avoidCachedDescriptors = true
-			ex.state.mu.txn.SetFixedTimestamp(ctx, *ts)
+		if asOf != nil {
+			p.semaCtx.AsOfTimestamp = &asOf.Timestamp
+			if asOf.BoundedStaleness {
+				// Bounded staleness reads are planned like regular statements. Their
+				// timestamp is picked once the plan is known; see
+				// negotiateBoundedStaleness.
+				p.boundedStaleness = true
+			} else {
+				p.avoidCachedDescriptors = true
+				ex.state.mu.txn.SetFixedTimestamp(ctx, asOf.Timestamp)
+			}
 		}
 	} else {
 		// If we're in an explicit txn, we allow AOST but only if it matches with
 		// the transaction's timestamp. This is useful for running AOST statements
 		// using the InternalExecutor inside an external transaction; one might want
 		// to do that to force p.avoidCachedDescriptors to be set below.
-		ts, err := p.isAsOf(stmt.AST, ex.server.cfg.Clock.Now())
+		asOf, err := p.isAsOf(stmt.AST, ex.server.cfg.Clock.Now())
 		if err != nil {
 			return makeErrEvent(err)
 		}
-		if ts != nil {
-			if *ts != ex.state.mu.txn.OrigTimestamp() {
+		if asOf != nil {
+			if asOf.BoundedStaleness {
+				return makeErrEvent(pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+					"%s can only be used in implicit transactions", tree.WithMaxStalenessFunctionName))
+			}
+			if asOf.Timestamp != ex.state.mu.txn.OrigTimestamp() {
 				return makeErrEvent(errors.Errorf("inconsistent \"as of system time\" timestamp. Expected: %s. "+
 					"Generally \"as of system time\" cannot be used inside a transaction.",
 					ex.state.mu.txn.OrigTimestamp()))
 			}
-			p.semaCtx.AsOfTimestamp = ts
+			p.semaCtx.AsOfTimestamp = &asOf.Timestamp
 			p.avoidCachedDescriptors = true
 		}
 	}
@@ -782,6 +809,13 @@ func (ex *connExecutor) dispatchToExecutionEngine(
 		return nil
 	}
 
+	if planner.boundedStaleness {
+		if err := ex.negotiateBoundedStaleness(ctx, planner); err != nil {
+			res.SetError(err)
+			return nil
+		}
+	}
+
 	var cols sqlbase.ResultColumns
 	if stmt.AST.StatementType() == tree.Rows {
 		cols = planColumns(planner.curPlan.plan)
@@ -844,6 +878,39 @@ func (ex *connExecutor) dispatchToExecutionEngine(
 	return nil
 }
 
+// negotiateBoundedStaleness picks the timestamp of a bounded staleness read
+// once its plan is known. The transaction's timestamp is set to the newest
+// timestamp at which the nearest replicas of all the ranges the plan reads can
+// serve it, in which case the reads are routed to those replicas. If the
+// nearest replicas can't serve the oldest timestamp allowed by
+// with_max_staleness(), the statement runs as a regular read against the
+// leaseholders.
+//
+// The statement was planned using the current versions of the table
+// descriptors, so the timestamp is never moved below the time at which these
+// versions were written.
+func (ex *connExecutor) negotiateBoundedStaleness(ctx context.Context, p *planner) error {
+	if !ex.server.cfg.Settings.Version.IsMinSupported(cluster.VersionBoundedStaleness) {
+		// Nodes that don't know about QueryResolvedTimestamp requests would
+		// reject them, so read from the leaseholders instead.
+		log.VEventf(ctx, 2, "bounded staleness read requires all nodes to be upgraded to %s; "+
+			"reading from lease holders", cluster.VersionByKey(cluster.VersionBoundedStaleness))
+		return nil
+	}
+	spans, descModTime, err := p.curPlan.collectReadSpans(ctx)
+	if err != nil {
+		return err
+	}
+	minTS := *p.semaCtx.AsOfTimestamp
+	minTS.Forward(descModTime)
+	ts, err := ex.state.mu.txn.NegotiateAndSetFixedTimestamp(ctx, minTS, spans)
+	if err != nil {
+		return err
+	}
+	log.VEventf(ctx, 2, "bounded staleness read at %s", ts)
+	return nil
+}
+
 // canFallbackFromOpt returns whether we can fallback on the heuristic planner
 // when the optimizer hits an error.
 func canFallbackFromOpt(err error, optMode sessiondata.OptimizerMode, stmt Statement) bool {
@@ -1060,8 +1127,10 @@ func (ex *connExecutor) execStmtInNoTxnState(
 // execStmtInAbortedState executes a statement in a txn that's in state
 // Aborted or RestartWait. All statements result in error events except:
 // - COMMIT / ROLLBACK: aborts the current transaction.
-// - ROLLBACK TO SAVEPOINT / SAVEPOINT: reopens the current transaction,
-//   allowing it to be retried.
+// - ROLLBACK TO SAVEPOINT / SAVEPOINT cockroach_restart: reopens the current
+//   transaction, allowing it to be retried.
+// - ROLLBACK TO SAVEPOINT for a regular savepoint: undoes the writes performed
+//   since the savepoint and resumes the current transaction.
 func (ex *connExecutor) execStmtInAbortedState(
 	ctx context.Context, stmt Statement, res RestrictedCommandResult,
 ) (fsm.Event, fsm.EventPayload) {
@@ -1092,10 +1161,17 @@ func (ex *connExecutor) execStmtInAbortedState(
 		default:
 			panic("unreachable")
 		}
-		if err := tree.ValidateRestartCheckpoint(spName); err != nil {
+		if !tree.IsRestartSavepoint(spName) {
+			if _, ok := s.(*tree.RollbackToSavepoint); ok && !inRestartWait {
+				return ex.execRollbackToSavepointInAbortedState(ctx, spName)
+			}
 			ev := eventNonRetriableErr{IsCommit: fsm.False}
 			payload := eventNonRetriableErrPayload{
-				err: err,
+				err: sqlbase.NewTransactionAbortedError("" /* customMsg */),
+			}
+			if inRestartWait {
+				payload.err = sqlbase.NewTransactionAbortedError(
+					"Expected \"ROLLBACK TO SAVEPOINT COCKROACH_RESTART\"" /* customMsg */)
 			}
 			return ev, payload
 		}
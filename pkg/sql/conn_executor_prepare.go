@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
 	"github.com/cockroachdb/cockroach/pkg/util/fsm"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/lib/pq/oid"
@@ -192,17 +193,21 @@ func (ex *connExecutor) prepare(
 		p.extendedEvalCtx.ActiveMemAcc = &constantMemAcc
 		defer constantMemAcc.Close(ctx)
 
-		protoTS, err := p.isAsOf(stmt.AST, ex.server.cfg.Clock.Now() /* max */)
+		asOf, err := p.isAsOf(stmt.AST, ex.server.cfg.Clock.Now() /* max */)
 		if err != nil {
 			return err
 		}
-		if protoTS != nil {
-			p.semaCtx.AsOfTimestamp = protoTS
-			// We can't use cached descriptors anywhere in this query, because
-			// we want the descriptors at the timestamp given, not the latest
-			// known to the cache.
-			p.avoidCachedDescriptors = true
-			txn.SetFixedTimestamp(ctx, *protoTS)
+		if asOf != nil {
+			p.semaCtx.AsOfTimestamp = &asOf.Timestamp
+			// Bounded staleness reads are prepared like regular statements; their
+			// timestamp is only picked when they are executed.
+			if !asOf.BoundedStaleness {
+				// We can't use cached descriptors anywhere in this query, because
+				// we want the descriptors at the timestamp given, not the latest
+				// known to the cache.
+				p.avoidCachedDescriptors = true
+				txn.SetFixedTimestamp(ctx, asOf.Timestamp)
+			}
 		}
 
 		// PREPARE has a limited subset of statements it can be run with. Postgres
@@ -336,6 +341,13 @@ func (ex *connExecutor) execBind(
 			if arg == nil {
 				// nil indicates a NULL argument value.
 				qargs[k] = tree.DNull
+			} else if typ, ok := ps.Types[k].(types.TEnum); ok {
+				// Enum values are sent as their labels in both formats.
+				d, err := tree.MakeDEnumFromLogicalRepresentation(typ, string(arg))
+				if err != nil {
+					return retErr(err)
+				}
+				qargs[k] = d
 			} else {
 				d, err := pgwirebase.DecodeOidDatum(t, qArgFormatCodes[i], arg)
 				if err != nil {
@@ -0,0 +1,111 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/fsm"
+)
+
+// savepoint is a regular savepoint established with a SAVEPOINT statement.
+type savepoint struct {
+	name  string
+	token client.SavepointToken
+}
+
+// savepointStack is the stack of savepoints of a SQL txn, innermost last.
+type savepointStack []savepoint
+
+// find returns the index of the innermost savepoint with the given name, or
+// -1 if there is none. As in Postgres, a savepoint can shadow an older one with
+// the same name.
+func (s savepointStack) find(name string) int {
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i].name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func errSavepointDoesNotExist(name string) error {
+	return pgerror.NewErrorf(pgerror.CodeInvalidSavepointSpecificationError,
+		"savepoint %s does not exist", name)
+}
+
+// execSavepointInOpenState executes a SAVEPOINT statement for a regular
+// savepoint.
+func (ex *connExecutor) execSavepointInOpenState(ctx context.Context, name string) error {
+	if !ex.server.cfg.Settings.Version.IsMinSupported(cluster.VersionSavepoints) {
+		return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+			"SAVEPOINT %s requires all nodes to be upgraded to %s",
+			name, cluster.VersionByKey(cluster.VersionSavepoints))
+	}
+	token, err := ex.state.mu.txn.CreateSavepoint(ctx)
+	if err != nil {
+		return err
+	}
+	ex.state.savepoints = append(ex.state.savepoints, savepoint{name: name, token: token})
+	return nil
+}
+
+// execReleaseSavepointInOpenState executes a RELEASE SAVEPOINT statement for a
+// regular savepoint. The savepoint and all the savepoints established after it
+// are destroyed; the writes performed since then are kept.
+func (ex *connExecutor) execReleaseSavepointInOpenState(ctx context.Context, name string) error {
+	idx := ex.state.savepoints.find(name)
+	if idx == -1 {
+		return errSavepointDoesNotExist(name)
+	}
+	if err := ex.state.mu.txn.ReleaseSavepoint(ctx, ex.state.savepoints[idx].token); err != nil {
+		return err
+	}
+	ex.state.savepoints = ex.state.savepoints[:idx]
+	return nil
+}
+
+// execRollbackToSavepoint executes a ROLLBACK TO SAVEPOINT statement for a
+// regular savepoint, in either the Open or the Aborted state. The writes
+// performed since the savepoint was established are undone and the savepoints
+// established after it are destroyed; the savepoint itself remains.
+func (ex *connExecutor) execRollbackToSavepoint(ctx context.Context, name string) error {
+	idx := ex.state.savepoints.find(name)
+	if idx == -1 {
+		return errSavepointDoesNotExist(name)
+	}
+	if err := ex.state.mu.txn.RollbackToSavepoint(ctx, ex.state.savepoints[idx].token); err != nil {
+		return err
+	}
+	ex.state.savepoints = ex.state.savepoints[:idx+1]
+	return nil
+}
+
+// execRollbackToSavepointInAbortedState is like execRollbackToSavepoint, but
+// also moves the txn out of the Aborted state on success.
+func (ex *connExecutor) execRollbackToSavepointInAbortedState(
+	ctx context.Context, name string,
+) (fsm.Event, fsm.EventPayload) {
+	if err := ex.execRollbackToSavepoint(ctx, name); err != nil {
+		return eventNonRetriableErr{IsCommit: fsm.False}, eventNonRetriableErrPayload{err: err}
+	}
+	// The KV txn has been resumed; it no longer needs to be rolled back when the
+	// SQL txn finishes.
+	ex.state.abortedTxnErr = nil
+	return eventSavepointRollback{}, nil
+}
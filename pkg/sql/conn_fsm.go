@@ -172,6 +172,12 @@ func (e eventTxnFinishPayload) toEvent() txnEvent {
 
 type eventTxnRestart struct{}
 
+// eventSavepointRollback is generated in the Aborted state when a ROLLBACK TO
+// SAVEPOINT for a regular (i.e. not cockroach_restart) savepoint is seen. The
+// writes performed after the savepoint have been undone by the time the event
+// is applied.
+type eventSavepointRollback struct{}
+
 type eventNonRetriableErr struct {
 	IsCommit Bool
 }
@@ -221,13 +227,14 @@ type payloadWithError interface {
 	errorCause() error
 }
 
-func (eventRetryIntentSet) Event()  {}
-func (eventTxnStart) Event()        {}
-func (eventTxnFinish) Event()       {}
-func (eventTxnRestart) Event()      {}
-func (eventNonRetriableErr) Event() {}
-func (eventRetriableErr) Event()    {}
-func (eventTxnReleased) Event()     {}
+func (eventRetryIntentSet) Event()    {}
+func (eventTxnStart) Event()          {}
+func (eventTxnFinish) Event()         {}
+func (eventTxnRestart) Event()        {}
+func (eventSavepointRollback) Event() {}
+func (eventNonRetriableErr) Event()   {}
+func (eventRetriableErr) Event()      {}
+func (eventTxnReleased) Event()       {}
 
 // TxnStateTransitions describe the transitions used by a connExecutor's
 // fsm.Machine. Args.Extended is a txnState, which is muted by the Actions.
@@ -307,8 +314,12 @@ var TxnStateTransitions = Compile(Pattern{
 			Description: "Retriable err; will auto-retry",
 			Next:        stateOpen{ImplicitTxn: Var("implicitTxn"), RetryIntent: Var("retryIntent")},
 			Action: func(args Args) error {
+				ts := args.Extended.(*txnState)
+				// All the statements, including any SAVEPOINTs, will be executed
+				// again.
+				ts.savepoints = nil
 				// The caller will call rewCap.rewindAndUnlock().
-				args.Extended.(*txnState).setAdvanceInfo(
+				ts.setAdvanceInfo(
 					rewind,
 					args.Payload.(eventRetriableErrPayload).rewCap,
 					txnRestart)
@@ -333,7 +344,13 @@ var TxnStateTransitions = Compile(Pattern{
 			Next: stateAborted{RetryIntent: Var("retryIntent")},
 			Action: func(args Args) error {
 				ts := args.Extended.(*txnState)
-				ts.mu.txn.CleanupOnError(ts.Ctx, args.Payload.(payloadWithError).errorCause())
+				err := args.Payload.(payloadWithError).errorCause()
+				if len(ts.savepoints) > 0 {
+					// Keep the KV txn around; a ROLLBACK TO SAVEPOINT can resume it.
+					ts.abortedTxnErr = err
+				} else {
+					ts.mu.txn.CleanupOnError(ts.Ctx, err)
+				}
 				ts.setAdvanceInfo(skipBatch, noRewind, txnAborted)
 				ts.txnAbortCount.Inc(1)
 				return nil
@@ -397,7 +414,9 @@ var TxnStateTransitions = Compile(Pattern{
 				// timestamp in that case. In the special case of the cockroach_restart
 				// savepoint, it's not clear to me what a user's expectation might be.
 				state.mu.txn.ManualRestart(args.Ctx, hlc.Timestamp{})
-				args.Extended.(*txnState).setAdvanceInfo(advanceOne, noRewind, txnRestart)
+				// The regular savepoints belonged to the previous epoch.
+				state.savepoints = nil
+				state.setAdvanceInfo(advanceOne, noRewind, txnRestart)
 				return nil
 			},
 		},
@@ -428,6 +447,14 @@ var TxnStateTransitions = Compile(Pattern{
 				return nil
 			},
 		},
+		eventSavepointRollback{}: {
+			Description: "ROLLBACK TO SAVEPOINT (not cockroach_restart)",
+			Next:        stateOpen{ImplicitTxn: False, RetryIntent: Var("retryIntent")},
+			Action: func(args Args) error {
+				args.Extended.(*txnState).setAdvanceInfo(advanceOne, noRewind, noEvent)
+				return nil
+			},
+		},
 	},
 	stateAborted{RetryIntent: True}: {
 		// ROLLBACK TO SAVEPOINT. We accept this in the Aborted state for the
@@ -475,7 +502,9 @@ var TxnStateTransitions = Compile(Pattern{
 			Description: "ROLLBACK TO SAVEPOINT cockroach_restart",
 			Next:        stateOpen{ImplicitTxn: False, RetryIntent: True},
 			Action: func(args Args) error {
-				args.Extended.(*txnState).setAdvanceInfo(advanceOne, noRewind, txnRestart)
+				ts := args.Extended.(*txnState)
+				ts.savepoints = nil
+				ts.setAdvanceInfo(advanceOne, noRewind, txnRestart)
 				return nil
 			},
 		},
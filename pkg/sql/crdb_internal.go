@@ -42,6 +42,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
@@ -59,6 +60,7 @@ var crdbInternal = virtualSchema{
 		crdbInternalClusterQueriesTable,
 		crdbInternalClusterSessionsTable,
 		crdbInternalClusterSettingsTable,
+		crdbInternalClusterTransactionWaitsTable,
 		crdbInternalCreateStmtsTable,
 		crdbInternalForwardDependenciesTable,
 		crdbInternalGossipNodesTable,
@@ -73,6 +75,7 @@ var crdbInternal = virtualSchema{
 		crdbInternalLocalQueriesTable,
 		crdbInternalLocalSessionsTable,
 		crdbInternalLocalMetricsTable,
+		crdbInternalLocalTransactionWaitsTable,
 		crdbInternalPartitionsTable,
 		crdbInternalRangesTable,
 		crdbInternalRuntimeInfoTable,
@@ -923,6 +926,135 @@ func populateSessionsTable(
 	return nil
 }
 
+const transactionWaitsSchemaPattern = `
+CREATE TABLE crdb_internal.%s (
+  node_id             INT NOT NULL,   -- the node on which the transaction is waiting
+  range_id            INT,            -- the range whose txn wait queue holds the wait
+  waiting_txn_id      STRING,         -- the ID of the waiting KV transaction, if any
+  waiting_session_id  STRING,         -- the ID of the session running the waiting transaction
+  blocking_txn_id     STRING,         -- the ID of the KV transaction being waited on
+  blocking_session_id STRING,         -- the ID of the session running the blocking transaction
+  key                 STRING,         -- the contended key, if known
+  wait_start          TIMESTAMP,      -- the time when the wait started
+  wait_duration       INTERVAL        -- the time spent waiting so far
+);
+`
+
+// crdbInternalLocalTransactionWaitsTable exposes the transactions waiting
+// on other transactions in the txn wait queues on the current node.
+var crdbInternalLocalTransactionWaitsTable = virtualSchemaTable{
+	schema: fmt.Sprintf(transactionWaitsSchemaPattern, "node_transaction_waits"),
+	populate: func(ctx context.Context, p *planner, _ *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if err := p.RequireSuperUser(ctx, "read crdb_internal.node_transaction_waits"); err != nil {
+			return err
+		}
+		req := serverpb.ListTransactionWaitsRequest{}
+		response, err := p.extendedEvalCtx.StatusServer.ListLocalTransactionWaits(ctx, &req)
+		if err != nil {
+			return err
+		}
+		return populateTransactionWaitsTable(ctx, p, addRow, response)
+	},
+}
+
+// crdbInternalClusterTransactionWaitsTable exposes the transactions waiting
+// on other transactions in the txn wait queues on the entire cluster.
+var crdbInternalClusterTransactionWaitsTable = virtualSchemaTable{
+	schema: fmt.Sprintf(transactionWaitsSchemaPattern, "cluster_transaction_waits"),
+	populate: func(ctx context.Context, p *planner, _ *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if err := p.RequireSuperUser(ctx, "read crdb_internal.cluster_transaction_waits"); err != nil {
+			return err
+		}
+		req := serverpb.ListTransactionWaitsRequest{}
+		response, err := p.extendedEvalCtx.StatusServer.ListTransactionWaits(ctx, &req)
+		if err != nil {
+			return err
+		}
+		return populateTransactionWaitsTable(ctx, p, addRow, response)
+	},
+}
+
+func populateTransactionWaitsTable(
+	ctx context.Context,
+	p *planner,
+	addRow func(...tree.Datum) error,
+	response *serverpb.ListTransactionWaitsResponse,
+) error {
+	// Map the KV transactions to the SQL sessions running them, if any.
+	sessionsReq := serverpb.ListSessionsRequest{Username: p.SessionData().User}
+	sessions, err := p.extendedEvalCtx.StatusServer.ListSessions(ctx, &sessionsReq)
+	if err != nil {
+		return err
+	}
+	sessionIDs := make(map[string]tree.Datum, len(sessions.Sessions))
+	for _, session := range sessions.Sessions {
+		if session.KvTxnID != nil {
+			sessionID := BytesToClusterWideID(session.ID)
+			sessionIDs[session.KvTxnID.String()] = tree.NewDString(sessionID.String())
+		}
+	}
+	sessionIDDatum := func(txnID string) tree.Datum {
+		if d, ok := sessionIDs[txnID]; ok {
+			return d
+		}
+		return tree.DNull
+	}
+
+	now := timeutil.Now()
+	for _, wait := range response.Waits {
+		waitingTxnIDDatum := tree.DNull
+		waitingSessionIDDatum := tree.DNull
+		if wait.WaitingTxnID != nil {
+			waitingTxnID := wait.WaitingTxnID.String()
+			waitingTxnIDDatum = tree.NewDString(waitingTxnID)
+			waitingSessionIDDatum = sessionIDDatum(waitingTxnID)
+		}
+		blockingTxnID := wait.BlockingTxnID.String()
+
+		keyDatum := tree.DNull
+		if len(wait.Key) > 0 {
+			keyDatum = tree.NewDString(wait.Key.String())
+		}
+
+		if err := addRow(
+			tree.NewDInt(tree.DInt(wait.NodeID)),
+			tree.NewDInt(tree.DInt(wait.RangeID)),
+			waitingTxnIDDatum,
+			waitingSessionIDDatum,
+			tree.NewDString(blockingTxnID),
+			sessionIDDatum(blockingTxnID),
+			keyDatum,
+			tree.MakeDTimestamp(wait.WaitStart, time.Microsecond),
+			&tree.DInterval{Duration: duration.Duration{Nanos: now.Sub(wait.WaitStart).Nanoseconds()}},
+		); err != nil {
+			return err
+		}
+	}
+
+	for _, rpcErr := range response.Errors {
+		log.Warning(ctx, rpcErr.Message)
+		if rpcErr.NodeID != 0 {
+			// Add a row with this node ID, error in the key column, and nulls
+			// for all other columns.
+			if err := addRow(
+				tree.NewDInt(tree.DInt(rpcErr.NodeID)), // node ID
+				tree.DNull,                             // range ID
+				tree.DNull,                             // waiting txn ID
+				tree.DNull,                             // waiting session ID
+				tree.DNull,                             // blocking txn ID
+				tree.DNull,                             // blocking session ID
+				tree.NewDString("-- "+rpcErr.Message),  // key
+				tree.DNull,                             // wait start
+				tree.DNull,                             // wait duration
+			); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 // crdbInternalLocalMetricsTable exposes a snapshot of the metrics on the
 // current node.
 var crdbInternalLocalMetricsTable = virtualSchemaTable{
@@ -18,9 +18,12 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 )
 
@@ -84,6 +87,164 @@ func MakeIndexDescriptor(n *tree.CreateIndex) (*sqlbase.IndexDescriptor, error)
 	return &indexDesc, nil
 }
 
+// indexExprColumnName is the name, possibly suffixed with a number, of the
+// hidden columns which hold the values of index expressions.
+const indexExprColumnName = "crdb_idx_expr"
+
+// replaceIndexExprs returns the index elements with each expression replaced
+// by the hidden stored computed column which holds its values. A column is
+// created and passed to addColumn if the table has no column for the
+// expression yet. The elements passed in are not modified.
+func replaceIndexExprs(
+	ctx context.Context,
+	desc *sqlbase.TableDescriptor,
+	tableName *tree.TableName,
+	elems tree.IndexElemList,
+	addColumn func(sqlbase.ColumnDescriptor),
+	semaCtx *tree.SemaContext,
+	evalCtx *tree.EvalContext,
+) (tree.IndexElemList, error) {
+	var res tree.IndexElemList
+	for i := range elems {
+		if elems[i].Expr == nil {
+			continue
+		}
+		if res == nil {
+			res = append(tree.IndexElemList(nil), elems...)
+		}
+		name, err := makeIndexExprColumn(
+			ctx, desc, tableName, elems[i].Expr, addColumn, semaCtx, evalCtx,
+		)
+		if err != nil {
+			return nil, err
+		}
+		res[i].Column = name
+		res[i].Expr = nil
+	}
+	if res == nil {
+		return elems, nil
+	}
+	return res, nil
+}
+
+// makeIndexExprColumn returns the name of the column which holds the values
+// of an index expression, creating it if needed.
+func makeIndexExprColumn(
+	ctx context.Context,
+	desc *sqlbase.TableDescriptor,
+	tableName *tree.TableName,
+	expr tree.Expr,
+	addColumn func(sqlbase.ColumnDescriptor),
+	semaCtx *tree.SemaContext,
+	evalCtx *tree.EvalContext,
+) (tree.Name, error) {
+	// A parenthesized column name is just the column.
+	if vBase, ok := expr.(tree.VarName); ok {
+		v, err := vBase.NormalizeVarName()
+		if err != nil {
+			return "", err
+		}
+		if c, ok := v.(*tree.ColumnItem); ok && c.TableName.NumParts == 0 {
+			return c.ColumnName, nil
+		}
+	}
+
+	sources := sqlbase.MultiSourceInfo{sqlbase.NewSourceInfoForSingleTable(
+		*tableName, sqlbase.ResultColumnsFromColDescs(desc.Columns),
+	)}
+	expr, err := dequalifyColumnRefs(ctx, sources, expr)
+	if err != nil {
+		return "", err
+	}
+	if err := iterColDescriptorsInExpr(*desc, expr, func(c sqlbase.ColumnDescriptor) error {
+		if c.IsComputed() {
+			return pgerror.NewError(pgerror.CodeInvalidTableDefinitionError,
+				"index expressions cannot reference computed columns")
+		}
+		return nil
+	}); err != nil {
+		return "", err
+	}
+
+	// Replace the column references with dummies of the same types to
+	// typecheck the expression.
+	replacedExpr, _, err := replaceVars(*desc, expr)
+	if err != nil {
+		return "", err
+	}
+	typedExpr, err := sqlbase.SanitizeVarFreeExpr(
+		replacedExpr, types.Any, "index expression", semaCtx, evalCtx, false, /* allowImpure */
+	)
+	if err != nil {
+		return "", err
+	}
+	colType, err := coltypes.DatumTypeToColumnType(typedExpr.ResolvedType())
+	if err != nil {
+		return "", err
+	}
+
+	// Indexes on the same expression share its column.
+	serialized := tree.Serialize(expr)
+	for i := range desc.Columns {
+		if col := &desc.Columns[i]; col.IsIndexExpr() && *col.ComputeExpr == serialized {
+			return tree.Name(col.Name), nil
+		}
+	}
+	for _, m := range desc.Mutations {
+		if m.MutationID != desc.NextMutationID || m.Direction != sqlbase.DescriptorMutation_ADD {
+			continue
+		}
+		if col := m.GetColumn(); col != nil && col.IsIndexExpr() && *col.ComputeExpr == serialized {
+			return tree.Name(col.Name), nil
+		}
+	}
+
+	name := indexExprColumnName
+	for i := 1; ; i++ {
+		if _, _, err := desc.FindColumnByName(tree.Name(name)); err != nil {
+			break
+		}
+		name = fmt.Sprintf("%s_%d", indexExprColumnName, i)
+	}
+	d := &tree.ColumnTableDef{Name: tree.Name(name), Type: colType}
+	d.Computed.Computed = true
+	d.Computed.Expr = expr
+	col, _, _, err := sqlbase.MakeColumnDefDescs(d, semaCtx, evalCtx)
+	if err != nil {
+		return "", err
+	}
+	col.Hidden = true
+	addColumn(*col)
+	return d.Name, nil
+}
+
+// indexExprColumnsUsing returns the IDs of the columns holding the values of
+// index expressions which reference the given column.
+func indexExprColumnsUsing(
+	desc *sqlbase.TableDescriptor, colID sqlbase.ColumnID,
+) (map[sqlbase.ColumnID]struct{}, error) {
+	res := make(map[sqlbase.ColumnID]struct{})
+	for i := range desc.Columns {
+		col := &desc.Columns[i]
+		if !col.IsIndexExpr() {
+			continue
+		}
+		expr, err := parser.ParseExpr(*col.ComputeExpr)
+		if err != nil {
+			return nil, err
+		}
+		if err := iterColDescriptorsInExpr(*desc, expr, func(c sqlbase.ColumnDescriptor) error {
+			if c.ID == colID {
+				res[col.ID] = struct{}{}
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
 func (n *createIndexNode) startExec(params runParams) error {
 	_, dropped, err := n.tableDesc.FindIndexByName(string(n.n.Name))
 	if err == nil {
@@ -95,7 +256,22 @@ func (n *createIndexNode) startExec(params runParams) error {
 		}
 	}
 
-	indexDesc, err := MakeIndexDescriptor(n.n)
+	// The columns holding the values of the index expressions are added in
+	// the same mutation as the index, and so are backfilled before it.
+	columns, err := replaceIndexExprs(
+		params.ctx, n.tableDesc, n.n.Table.TableName(), n.n.Columns,
+		func(col sqlbase.ColumnDescriptor) {
+			n.tableDesc.AddColumnMutation(col, sqlbase.DescriptorMutation_ADD)
+		},
+		&params.p.semaCtx, params.EvalContext(),
+	)
+	if err != nil {
+		return err
+	}
+	createIndex := *n.n
+	createIndex.Columns = columns
+
+	indexDesc, err := MakeIndexDescriptor(&createIndex)
 	if err != nil {
 		return err
 	}
@@ -1039,7 +1039,13 @@ func MakeTableDesc(
 			if d.Inverted {
 				idx.Type = sqlbase.IndexDescriptor_INVERTED
 			}
-			if err := idx.FillColumns(d.Columns); err != nil {
+			columns, err := replaceIndexExprs(
+				ctx, &desc, tableName, d.Columns, desc.AddColumn, semaCtx, evalCtx,
+			)
+			if err != nil {
+				return desc, err
+			}
+			if err := idx.FillColumns(columns); err != nil {
 				return desc, err
 			}
 			if d.PartitionBy != nil {
@@ -1061,7 +1067,23 @@ func MakeTableDesc(
 				Unique:           true,
 				StoreColumnNames: d.Storing.ToStrings(),
 			}
-			if err := idx.FillColumns(d.Columns); err != nil {
+			columns := d.Columns
+			if d.PrimaryKey {
+				for _, c := range columns {
+					if c.Expr != nil {
+						return desc, pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+							"expressions are not supported in primary keys")
+					}
+				}
+			} else {
+				columns, err = replaceIndexExprs(
+					ctx, &desc, tableName, columns, desc.AddColumn, semaCtx, evalCtx,
+				)
+				if err != nil {
+					return desc, err
+				}
+			}
+			if err := idx.FillColumns(columns); err != nil {
 				return desc, err
 			}
 			if d.PartitionBy != nil {
@@ -1095,6 +1117,10 @@ func MakeTableDesc(
 		// Primary index columns are not nullable.
 		for i := range desc.Columns {
 			if _, ok := primaryIndexColumnSet[desc.Columns[i].Name]; ok {
+				if desc.Columns[i].Virtual {
+					return desc, pgerror.NewErrorf(pgerror.CodeInvalidTableDefinitionError,
+						"virtual computed column %q cannot be part of the primary key", desc.Columns[i].Name)
+				}
 				desc.Columns[i].Nullable = false
 			}
 		}
@@ -1105,6 +1131,12 @@ func MakeTableDesc(
 	// explicit allocations before AllocateIDs adds implicit ones).
 	for _, def := range n.Defs {
 		if d, ok := def.(*tree.FamilyTableDef); ok {
+			for _, name := range d.Columns {
+				if col, err := desc.FindActiveColumnByName(string(name)); err == nil && col.Virtual {
+					return desc, pgerror.NewErrorf(pgerror.CodeInvalidTableDefinitionError,
+						"virtual computed column %q cannot be assigned to a column family", col.Name)
+				}
+			}
 			fam := sqlbase.ColumnFamilyDescriptor{
 				Name:        string(d.Name),
 				ColumnNames: d.Columns.ToStrings(),
@@ -1363,7 +1395,8 @@ func iterColDescriptorsInExpr(
 }
 
 // validateComputedColumn checks that a computed column satisfies a number of
-// validity constraints, for instance, that it typechecks.
+// validity constraints, for instance, that it typechecks. t is nil when the
+// column is added to an existing table.
 func validateComputedColumn(
 	desc sqlbase.TableDescriptor,
 	t *tree.CreateTable,
@@ -1395,7 +1428,11 @@ func validateComputedColumn(
 	// TODO(justin,bram): allow depending on columns like this. We disallow it
 	// for now because cascading changes must hook into the computed column
 	// update path.
-	for _, def := range t.Defs {
+	var defs tree.TableDefs
+	if t != nil {
+		defs = t.Defs
+	}
+	for _, def := range defs {
 		switch c := def.(type) {
 		case *tree.ColumnTableDef:
 			if _, ok := dependencies[string(c.Name)]; !ok {
@@ -1487,6 +1524,10 @@ func MakeCheckConstraint(
 		}
 	}
 
+	if err := sqlbase.CheckNoUserDefinedTypeReferences(d.Expr, "CHECK"); err != nil {
+		return nil, err
+	}
+
 	expr, colIDsUsed, err := replaceVars(desc, d.Expr)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,263 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+type createTypeNode struct {
+	n      *tree.CreateType
+	name   *ObjectName
+	dbDesc *sqlbase.DatabaseDescriptor
+}
+
+// CreateType creates an enum type.
+// Privileges: CREATE on database.
+//   Notes: postgres requires CREATE on the schema.
+func (p *planner) CreateType(ctx context.Context, n *tree.CreateType) (planNode, error) {
+	if !p.ExecCfg().Settings.Version.IsMinSupported(cluster.VersionEnums) {
+		return nil, pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+			"CREATE TYPE requires all nodes to be upgraded to %s",
+			cluster.VersionByKey(cluster.VersionEnums))
+	}
+
+	name, err := n.Name.Normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	dbDesc, err := p.ResolveUncachedDatabase(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{}, len(n.EnumLabels))
+	for _, label := range n.EnumLabels {
+		if _, ok := seen[label]; ok {
+			return nil, pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+				"enum label %q used more than once", label)
+		}
+		seen[label] = struct{}{}
+	}
+
+	return &createTypeNode{n: n, name: name, dbDesc: dbDesc}, nil
+}
+
+func (n *createTypeNode) startExec(params runParams) error {
+	key := tableKey{parentID: n.dbDesc.ID, name: n.name.Table()}
+	if exists, err := descExists(params.ctx, params.p.txn, key.Key()); err == nil && exists {
+		// Types share the namespace of tables.
+		if typ, err := getTypeDesc(params.ctx, params.p.txn, n.dbDesc.ID, key.Name()); err != nil {
+			return err
+		} else if typ != nil {
+			return pgerror.NewErrorf(pgerror.CodeDuplicateObjectError,
+				"type %q already exists", key.Name())
+		}
+		return sqlbase.NewRelationAlreadyExistsError(key.Name())
+	} else if err != nil {
+		return err
+	}
+
+	id, err := GenerateUniqueDescID(params.ctx, params.p.ExecCfg().DB)
+	if err != nil {
+		return err
+	}
+
+	physical := sqlbase.GenerateEnumPhysicalRepresentations(len(n.n.EnumLabels))
+	desc := sqlbase.TypeDescriptor{
+		Name:     n.name.Table(),
+		ID:       id,
+		ParentID: n.dbDesc.ID,
+		// Inherit permissions from the database descriptor.
+		Privileges:  n.dbDesc.GetPrivileges(),
+		EnumMembers: make([]sqlbase.TypeDescriptor_EnumMember, len(n.n.EnumLabels)),
+	}
+	for i, label := range n.n.EnumLabels {
+		desc.EnumMembers[i] = sqlbase.TypeDescriptor_EnumMember{
+			PhysicalRepresentation: physical[i],
+			LogicalRepresentation:  label,
+		}
+	}
+	if err := desc.Validate(); err != nil {
+		return err
+	}
+
+	if err := params.p.createDescriptorWithID(
+		params.ctx, key.Key(), id, &desc, params.EvalContext().Settings,
+	); err != nil {
+		return err
+	}
+
+	// Log Create Type event. This is an auditable log event and is
+	// recorded in the same transaction as the type descriptor update.
+	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+		params.ctx,
+		params.p.txn,
+		EventLogCreateType,
+		int32(desc.ID),
+		int32(params.extendedEvalCtx.NodeID),
+		struct {
+			TypeName  string
+			Statement string
+			User      string
+		}{n.name.FQString(), n.n.String(), params.SessionData().User},
+	)
+}
+
+func (*createTypeNode) Next(runParams) (bool, error) { return false, nil }
+func (*createTypeNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createTypeNode) Close(context.Context)        {}
+
+// getTypeDesc looks up the descriptor of the type with the given name in the
+// given database. It returns nil if there is no such type, including if the
+// name is that of a table.
+func getTypeDesc(
+	ctx context.Context, txn *client.Txn, parentID sqlbase.ID, name string,
+) (*sqlbase.TypeDescriptor, error) {
+	gr, err := txn.Get(ctx, tableKey{parentID: parentID, name: name}.Key())
+	if err != nil || !gr.Exists() {
+		return nil, err
+	}
+	desc := &sqlbase.Descriptor{}
+	if err := txn.GetProto(ctx, sqlbase.MakeDescMetadataKey(sqlbase.ID(gr.ValueInt())), desc); err != nil {
+		return nil, err
+	}
+	typ := desc.GetType()
+	if typ == nil {
+		return nil, nil
+	}
+	if err := typ.Validate(); err != nil {
+		return nil, err
+	}
+	return typ, nil
+}
+
+// getTypeDescsByID returns the type descriptors among the descriptors with
+// the given IDs, keyed by ID.
+func getTypeDescsByID(
+	ctx context.Context, txn *client.Txn, ids []sqlbase.ID,
+) (map[sqlbase.ID]*sqlbase.TypeDescriptor, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	b := txn.NewBatch()
+	for _, id := range ids {
+		b.Get(sqlbase.MakeDescMetadataKey(id))
+	}
+	if err := txn.Run(ctx, b); err != nil {
+		return nil, err
+	}
+	typs := make(map[sqlbase.ID]*sqlbase.TypeDescriptor)
+	for _, r := range b.Results {
+		for i := range r.Rows {
+			if !r.Rows[i].Exists() {
+				continue
+			}
+			desc := &sqlbase.Descriptor{}
+			if err := r.Rows[i].ValueProto(desc); err != nil {
+				return nil, err
+			}
+			if typ := desc.GetType(); typ != nil {
+				typs[typ.ID] = typ
+			}
+		}
+	}
+	return typs, nil
+}
+
+// getTypeDescsInDatabase returns the descriptors of the types of the given
+// database.
+func getTypeDescsInDatabase(
+	ctx context.Context, txn *client.Txn, dbID sqlbase.ID,
+) ([]*sqlbase.TypeDescriptor, error) {
+	prefix := sqlbase.MakeNameMetadataKey(dbID, "")
+	sr, err := txn.Scan(ctx, prefix, prefix.PrefixEnd(), 0)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]sqlbase.ID, len(sr))
+	for i := range sr {
+		ids[i] = sqlbase.ID(sr[i].ValueInt())
+	}
+	typs, err := getTypeDescsByID(ctx, txn, ids)
+	if err != nil {
+		return nil, err
+	}
+	var res []*sqlbase.TypeDescriptor
+	for _, id := range ids {
+		if typ, ok := typs[id]; ok {
+			res = append(res, typ)
+		}
+	}
+	return res, nil
+}
+
+// resolveTypeDesc resolves the name of a user-defined type to its
+// descriptor, or returns an error if there is no such type.
+func (p *planner) resolveTypeDesc(
+	ctx context.Context, name *ObjectName,
+) (*sqlbase.TypeDescriptor, error) {
+	dbDesc, err := p.ResolveUncachedDatabase(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return ResolveTypeDesc(ctx, p.txn, dbDesc.ID, name.Table())
+}
+
+// ResolveTypeDesc returns the descriptor of the user-defined type with the
+// given name in the database with the given ID, or an error if there is no
+// such type.
+func ResolveTypeDesc(
+	ctx context.Context, txn *client.Txn, dbID sqlbase.ID, name string,
+) (*sqlbase.TypeDescriptor, error) {
+	desc, err := getTypeDesc(ctx, txn, dbID, name)
+	if err != nil {
+		return nil, err
+	}
+	if desc == nil {
+		return nil, pgerror.NewErrorf(pgerror.CodeUndefinedObjectError,
+			"type %q does not exist", tree.ErrNameString(&name))
+	}
+	return desc, nil
+}
+
+var _ tree.TypeResolver = &planner{}
+
+// ResolveType implements the tree.TypeResolver interface.
+func (p *planner) ResolveType(name *coltypes.TUserDefined) (coltypes.T, error) {
+	tn := tree.MakeUnqualifiedTableName(tree.Name(name.Name))
+	if name.Schema != "" {
+		tn.SchemaName = tree.Name(name.Schema)
+		tn.ExplicitSchema = true
+	}
+	desc, err := p.resolveTypeDesc(p.EvalContext().Context, &tn)
+	if err != nil {
+		return nil, err
+	}
+	return &coltypes.TEnum{Typ: desc.DatumType()}, nil
+}
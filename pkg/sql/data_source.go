@@ -171,6 +171,12 @@ func (p *planner) getDataSource(
 	case *tree.AliasedTableExpr:
 		// Alias clause: source AS alias(cols...)
 
+		if t.Lateral {
+			// Lateral references are only resolved by the cost-based optimizer.
+			return planDataSource{}, pgerror.UnimplementedWithIssueError(24560,
+				"LATERAL is only supported by the cost-based optimizer")
+		}
+
 		if t.IndexFlags != nil {
 			indexFlags = t.IndexFlags
 		}
@@ -202,6 +202,16 @@ func getDescriptorByID(
 			return err
 		}
 		*t = *database
+	case *sqlbase.TypeDescriptor:
+		typ := desc.GetType()
+		if typ == nil {
+			return errors.Errorf("%q is not a type", desc.String())
+		}
+
+		if err := typ.Validate(); err != nil {
+			return err
+		}
+		*t = *typ
 	}
 	return nil
 }
@@ -225,6 +235,8 @@ func GetAllDescriptors(ctx context.Context, txn *client.Txn) ([]sqlbase.Descript
 			descs[i] = desc.GetTable()
 		case *sqlbase.Descriptor_Database:
 			descs[i] = desc.GetDatabase()
+		case *sqlbase.Descriptor_Type:
+			descs[i] = desc.GetType()
 		default:
 			return nil, errors.Errorf("Descriptor.Union has unexpected type %T", t)
 		}
@@ -194,9 +194,14 @@ func (v *distSQLExprCheckVisitor) VisitPre(expr tree.Expr) (recurse bool, newExp
 	case *tree.DOid:
 		v.err = newQueryNotSupportedError("OID expressions are not supported by distsql")
 		return false, expr
+	case *tree.DEnum:
+		// The remote nodes parse the expressions again, without a way to
+		// resolve the enum type.
+		v.err = newQueryNotSupportedError("enum expressions are not supported by distsql")
+		return false, expr
 	case *tree.CastExpr:
 		switch t.Type.(type) {
-		case *coltypes.TOid:
+		case *coltypes.TOid, *coltypes.TEnum:
 			v.err = newQueryNotSupportedErrorf("cast to %s is not supported by distsql", t.Type)
 			return false, expr
 		}
@@ -350,6 +355,11 @@ func (dsp *DistSQLPlanner) checkSupportForNode(node planNode) (distRecommendatio
 		return rec, nil
 
 	case *scanNode:
+		if n.lockingStrength != tree.ForNone {
+			// Table readers don't acquire locks.
+			return cannotDistribute, newQueryNotSupportedError(
+				"SELECT with a locking clause is not supported by distsql")
+		}
 		rec := canDistribute
 		if n.softLimit != 0 {
 			// We don't yet recommend distributing plans where soft limits propagate
@@ -2445,6 +2455,14 @@ func (dsp *DistSQLPlanner) wrapPlan(planCtx *PlanningCtx, n planNode) (PhysicalP
 				// deleteNodes.
 				// TODO(jordan): fix deleteNode to stop doing that.
 				return false, nil
+			case *recursiveCTENode:
+				// A recursive CTE plans and runs its queries itself, one after
+				// the other, so they are never planned by DistSQL.
+				return false, nil
+			case *applyJoinNode:
+				// An apply join runs its left side and plans and runs its right
+				// side itself, so they are never planned by DistSQL.
+				return false, nil
 			}
 			if !seenTop {
 				// We know we're wrapping the first node, so ignore it.
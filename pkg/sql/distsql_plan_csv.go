@@ -45,6 +45,17 @@ var ExportPlanResultTypes = []sqlbase.ColumnType{
 	{SemanticType: sqlbase.ColumnType_INT},    // bytes
 }
 
+// ExportPlanColumnNames returns the names of the columns of the input to an
+// EXPORT plan, which are needed by the formats that write a schema.
+func ExportPlanColumnNames(in PlanNode) []string {
+	cols := planColumns(in)
+	names := make([]string, len(cols))
+	for i, col := range cols {
+		names[i] = col.Name
+	}
+	return names
+}
+
 // PlanAndRunExport makes and runs an EXPORT plan for the given input and output
 // planNode and spec respectively.  The input planNode must be runnable via
 // DistSQL. The output spec's results must conform to the ExportResultTypes.
@@ -165,17 +176,23 @@ type KeyRewriter interface {
 }
 
 // LoadCSV performs a distributed transformation of the CSV files at from
-// and stores them in enterprise backup format at to.
+// and stores them in enterprise backup format at to. If targetCols is
+// non-empty, the input provides values only for those columns of the single
+// table being imported. If disallowShadowing is set, ingesting a key that
+// collides with an existing one fails the import.
 func LoadCSV(
 	ctx context.Context,
 	phs PlanHookState,
 	job *jobs.Job,
 	resultRows *RowResultWriter,
 	tables map[string]*sqlbase.TableDescriptor,
+	targetCols []string,
 	from []string,
 	to string,
 	format roachpb.IOFileFormat,
 	walltime int64,
+	rowIDBase uint64,
+	disallowShadowing bool,
 	splitSize int64,
 	oversample int64,
 	makeRewriter func(map[sqlbase.ID]*sqlbase.TableDescriptor) (KeyRewriter, error),
@@ -224,7 +241,13 @@ func LoadCSV(
 					JobID: *job.ID(),
 					Slot:  int32(i),
 				},
-				Uri: make(map[int32]string),
+				Uri:        make(map[int32]string),
+				TargetCols: targetCols,
+			}
+			if disallowShadowing {
+				// Importing into an existing table: generated row IDs must not
+				// collide with those already in it.
+				spec.RowIDBase = rowIDBase
 			}
 			inputSpecs = append(inputSpecs, spec)
 		}
@@ -241,8 +264,9 @@ func LoadCSV(
 	sstSpecs := make([]distsqlrun.SSTWriterSpec, len(nodes))
 	for i := range nodes {
 		sstSpecs[i] = distsqlrun.SSTWriterSpec{
-			Destination:   to,
-			WalltimeNanos: walltime,
+			Destination:       to,
+			WalltimeNanos:     walltime,
+			DisallowShadowing: disallowShadowing,
 		}
 	}
 
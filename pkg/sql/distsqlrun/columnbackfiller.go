@@ -71,6 +71,7 @@ func (cb *columnBackfiller) runChunk(
 	tableDesc := cb.backfiller.spec.Table
 	var key roachpb.Key
 	err := cb.flowCtx.ClientDB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		txn.SetAdmissionPriority(roachpb.ADMISSION_BULK)
 		if cb.flowCtx.testingKnobs.RunBeforeBackfillChunk != nil {
 			if err := cb.flowCtx.testingKnobs.RunBeforeBackfillChunk(sp); err != nil {
 				return err
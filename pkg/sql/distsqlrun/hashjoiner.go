@@ -188,7 +188,7 @@ func newHashJoiner(
 		// The hashJoiner will overflow to disk if this limit is not enough.
 		limit := h.flowCtx.testingKnobs.MemoryLimitBytes
 		if limit <= 0 {
-			limit = settingWorkMemBytes.Get(&st.SV)
+			limit = SettingWorkMemBytes.Get(&st.SV)
 		}
 		limitedMon := mon.MakeMonitorInheritWithLimit("hashjoiner-limited", limit, flowCtx.EvalCtx.Mon)
 		limitedMon.Start(ctx, flowCtx.EvalCtx.Mon, mon.BoundAccount{})
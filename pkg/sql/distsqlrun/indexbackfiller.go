@@ -90,6 +90,7 @@ func (ib *indexBackfiller) runChunk(
 	var key roachpb.Key
 	transactionalChunk := func(ctx context.Context) error {
 		return ib.flowCtx.ClientDB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+			txn.SetAdmissionPriority(roachpb.ADMISSION_BULK)
 			// TODO(knz): do KV tracing in DistSQL processors.
 			var err error
 			key, err = ib.RunIndexBackfillChunk(
@@ -113,6 +114,7 @@ func (ib *indexBackfiller) runChunk(
 	var entries []sqlbase.IndexEntry
 	if err := ib.flowCtx.ClientDB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
 		txn.SetFixedTimestamp(ctx, readAsOf)
+		txn.SetAdmissionPriority(roachpb.ADMISSION_BULK)
 
 		// TODO(knz): do KV tracing in DistSQL processors.
 		var err error
@@ -125,6 +127,7 @@ func (ib *indexBackfiller) runChunk(
 	retried := false
 	// Write the new index values.
 	if err := ib.flowCtx.ClientDB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		txn.SetAdmissionPriority(roachpb.ADMISSION_BULK)
 		batch := txn.NewBatch()
 
 		for _, entry := range entries {
@@ -100,7 +100,9 @@ var settingUseTempStorageJoins = settings.RegisterBoolSetting(
 	true,
 )
 
-var settingWorkMemBytes = settings.RegisterByteSizeSetting(
+// SettingWorkMemBytes is the amount of memory a processor, or a planNode
+// which can use temporary storage, may use before spilling to disk.
+var SettingWorkMemBytes = settings.RegisterByteSizeSetting(
 	"sql.distsql.temp_storage.workmem",
 	"maximum amount of memory in bytes a processor can use before falling back to temp storage",
 	64*1024*1024, /* 64MB */
@@ -32,6 +32,7 @@ type dropDatabaseNode struct {
 	n      *tree.DropDatabase
 	dbDesc *sqlbase.DatabaseDescriptor
 	td     []toDelete
+	types  []*sqlbase.TypeDescriptor
 }
 
 // DropDatabase drops a database.
@@ -70,7 +71,12 @@ func (p *planner) DropDatabase(ctx context.Context, n *tree.DropDatabase) (planN
 		return nil, err
 	}
 
-	if len(tbNames) > 0 {
+	types, err := getTypeDescsInDatabase(ctx, p.txn, dbDesc.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tbNames) > 0 || len(types) > 0 {
 		switch n.DropBehavior {
 		case tree.DropRestrict:
 			return nil, pgerror.NewErrorf(pgerror.CodeDependentObjectsStillExistError,
@@ -112,7 +118,7 @@ func (p *planner) DropDatabase(ctx context.Context, n *tree.DropDatabase) (planN
 		return nil, err
 	}
 
-	return &dropDatabaseNode{n: n, dbDesc: dbDesc, td: td}, nil
+	return &dropDatabaseNode{n: n, dbDesc: dbDesc, td: td, types: types}, nil
 }
 
 func (n *dropDatabaseNode) startExec(params runParams) error {
@@ -143,6 +149,20 @@ func (n *dropDatabaseNode) startExec(params runParams) error {
 	zoneKeyPrefix := config.MakeZoneKeyPrefix(uint32(n.dbDesc.ID))
 
 	b := &client.Batch{}
+	// The columns of the tables in other databases which use the types keep
+	// their own copies of the members, and are unaffected.
+	for _, typ := range n.types {
+		typNameKey := tableKey{parentID: typ.ParentID, name: typ.Name}.Key()
+		typDescKey := sqlbase.MakeDescMetadataKey(typ.ID)
+		if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+			log.VEventf(ctx, 2, "Del %s", typDescKey)
+			log.VEventf(ctx, 2, "Del %s", typNameKey)
+		}
+		b.Del(typDescKey)
+		b.Del(typNameKey)
+		tn := tree.MakeTableName(tree.Name(n.dbDesc.Name), tree.Name(typ.Name))
+		tbNameStrings = append(tbNameStrings, tn.FQString())
+	}
 	if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
 		log.VEventf(ctx, 2, "Del %s", descKey)
 		log.VEventf(ctx, 2, "Del %s", nameKey)
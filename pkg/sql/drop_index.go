@@ -202,6 +202,9 @@ func (p *planner) dropIndexByName(
 	if !found {
 		return fmt.Errorf("index %q in the middle of being added, try again later", idxName)
 	}
+	for _, id := range idx.ColumnIDs {
+		dropUnusedIndexExprColumn(tableDesc, id)
+	}
 
 	if err := tableDesc.Validate(ctx, p.txn, p.EvalContext().Settings); err != nil {
 		return err
@@ -233,3 +236,20 @@ func (p *planner) dropIndexByName(
 			droppedViews},
 	)
 }
+
+// dropUnusedIndexExprColumn queues the drop of the column with the given ID if
+// it holds the values of an index expression and no index uses it anymore.
+func dropUnusedIndexExprColumn(tableDesc *sqlbase.TableDescriptor, id sqlbase.ColumnID) {
+	for _, idx := range tableDesc.AllNonDropIndexes() {
+		if idx.ContainsColumnID(id) {
+			return
+		}
+	}
+	for i := range tableDesc.Columns {
+		if col := tableDesc.Columns[i]; col.ID == id && col.IsIndexExpr() {
+			tableDesc.AddColumnMutation(col, sqlbase.DescriptorMutation_DROP)
+			tableDesc.Columns = append(tableDesc.Columns[:i], tableDesc.Columns[i+1:]...)
+			return
+		}
+	}
+}
@@ -64,6 +64,11 @@ const (
 	// EventLogAlterSequence is recorded when a sequence is altered.
 	EventLogAlterSequence EventLogType = "alter_sequence"
 
+	// EventLogCreateType is recorded when a type is created.
+	EventLogCreateType EventLogType = "create_type"
+	// EventLogAlterType is recorded when a type is altered.
+	EventLogAlterType EventLogType = "alter_type"
+
 	// EventLogReverseSchemaChange is recorded when an in-progress schema change
 	// encounters a problem and is reversed.
 	EventLogReverseSchemaChange EventLogType = "reverse_schema_change"
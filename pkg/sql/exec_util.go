@@ -634,6 +634,7 @@ func checkResultType(typ types.T) error {
 				return pgerror.Unimplemented("nested arrays", "arrays cannot have arrays as element type")
 			}
 		case istype(types.FamCollatedString):
+		case istype(types.FamEnum):
 		case istype(types.FamTuple):
 		case istype(types.FamPlaceholder):
 			return errors.Errorf("could not determine data type of %s", typ)
@@ -663,13 +664,13 @@ func ParseHLC(s string) (hlc.Timestamp, error) {
 
 // isAsOf analyzes a statement to bypass the logic in newPlan(), since
 // that requires the transaction to be started already. If the returned
-// timestamp is not nil, it is the timestamp to which a transaction
+// value is not nil, its timestamp is the timestamp to which a transaction
 // should be set. The statements that will be checked are Select,
 // ShowTrace (of a Select statement), and Scrub.
 //
 // max is a lower bound on what the transaction's timestamp will be.
 // Used to check that the user didn't specify a timestamp in the future.
-func (p *planner) isAsOf(stmt tree.Statement, max hlc.Timestamp) (*hlc.Timestamp, error) {
+func (p *planner) isAsOf(stmt tree.Statement, max hlc.Timestamp) (*tree.AsOfSystemTime, error) {
 	var asOf tree.AsOfClause
 	switch s := stmt.(type) {
 	case *tree.Select:
@@ -700,14 +701,15 @@ func (p *planner) isAsOf(stmt tree.Statement, max hlc.Timestamp) (*hlc.Timestamp
 		return nil, nil
 	}
 
-	ts, err := p.EvalAsOfTimestamp(asOf, max)
-	return &ts, err
+	ast, err := tree.EvalAsOfClause(asOf, max, &p.semaCtx, p.EvalContext())
+	return &ast, err
 }
 
-// isSavepoint returns true if stmt is a SAVEPOINT statement.
-func isSavepoint(stmt Statement) bool {
-	_, isSavepoint := stmt.AST.(*tree.Savepoint)
-	return isSavepoint
+// isRestartSavepoint returns true if stmt is a SAVEPOINT cockroach_restart
+// statement.
+func isRestartSavepoint(stmt Statement) bool {
+	s, isSavepoint := stmt.AST.(*tree.Savepoint)
+	return isSavepoint && tree.IsRestartSavepoint(s.Name)
 }
 
 // isSetTransaction returns true if stmt is a "SET TRANSACTION ..." statement.
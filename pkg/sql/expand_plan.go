@@ -178,6 +178,13 @@ func doExpandPlan(
 		}
 		n.left, err = doExpandPlan(ctx, p, params, n.left)
 
+	case *recursiveCTENode:
+		n.initial, err = doExpandPlan(ctx, p, noParams, n.initial)
+		if err != nil {
+			return plan, err
+		}
+		n.recursive, err = doExpandPlan(ctx, p, noParams, n.recursive)
+
 	case *filterNode:
 		plan, err = expandFilterNode(ctx, p, params, n)
 
@@ -330,9 +337,11 @@ func doExpandPlan(
 		n.source, err = doExpandPlan(ctx, p, noParams, n.source)
 
 	case *valuesNode:
+	case *workTableScanNode:
 	case *alterIndexNode:
 	case *alterTableNode:
 	case *alterSequenceNode:
+	case *alterTypeNode:
 	case *alterUserSetPasswordNode:
 	case *scrubNode:
 	case *createDatabaseNode:
@@ -340,6 +349,7 @@ func doExpandPlan(
 	case *CreateUserNode:
 	case *createViewNode:
 	case *createSequenceNode:
+	case *createTypeNode:
 	case *createStatsNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
@@ -703,6 +713,10 @@ func (p *planner) simplifyOrderings(plan planNode, usefulOrdering sqlbase.Column
 		n.right = p.simplifyOrderings(n.right, nil)
 		n.left = p.simplifyOrderings(n.left, nil)
 
+	case *recursiveCTENode:
+		n.initial = p.simplifyOrderings(n.initial, nil)
+		n.recursive = p.simplifyOrderings(n.recursive, nil)
+
 	case *filterNode:
 		n.source.plan = p.simplifyOrderings(n.source.plan, usefulOrdering)
 		n.computePhysicalProps(p.EvalContext())
@@ -831,9 +845,11 @@ func (p *planner) simplifyOrderings(plan planNode, usefulOrdering sqlbase.Column
 		n.rows = p.simplifyOrderings(n.rows, nil)
 
 	case *valuesNode:
+	case *workTableScanNode:
 	case *alterIndexNode:
 	case *alterTableNode:
 	case *alterSequenceNode:
+	case *alterTypeNode:
 	case *alterUserSetPasswordNode:
 	case *scrubNode:
 	case *createDatabaseNode:
@@ -841,6 +857,7 @@ func (p *planner) simplifyOrderings(plan planNode, usefulOrdering sqlbase.Column
 	case *CreateUserNode:
 	case *createViewNode:
 	case *createSequenceNode:
+	case *createTypeNode:
 	case *createStatsNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
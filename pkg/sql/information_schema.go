@@ -1223,6 +1223,38 @@ func forEachDatabaseDesc(
 	return nil
 }
 
+// forEachTypeDesc retrieves all the type descriptors of the databases
+// visible in the given database context, and calls fn with each type and its
+// database.
+func forEachTypeDesc(
+	ctx context.Context,
+	p *planner,
+	dbContext *DatabaseDescriptor,
+	fn func(*sqlbase.DatabaseDescriptor, *sqlbase.TypeDescriptor) error,
+) error {
+	descs, err := p.Tables().getAllDescriptors(ctx, p.txn)
+	if err != nil {
+		return err
+	}
+	lCtx := newInternalLookupCtx(descs, dbContext)
+
+	for _, desc := range descs {
+		typ, ok := desc.(*sqlbase.TypeDescriptor)
+		if !ok {
+			continue
+		}
+		db, ok := lCtx.dbDescs[typ.ParentID]
+		if !ok || (dbContext != nil && dbContext.ID != db.ID) ||
+			!userCanSeeDatabase(ctx, p, db) || p.CheckAnyPrivilege(ctx, typ) != nil {
+			continue
+		}
+		if err := fn(db, typ); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // forEachTableDesc retrieves all table descriptors from the current
 // database and all system databases and iterates through them. For
 // each table, the function will call fn with its respective database
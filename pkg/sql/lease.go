@@ -1658,13 +1658,15 @@ func (m *LeaseManager) RefreshLeases(s *stop.Stopper, db *client.DB, g *gossip.G
 							log.Infof(ctx, "%s: refreshing lease table: %d (%s), version: %d, dropped: %t",
 								kv.Key, table.ID, table.Name, table.Version, table.Dropped())
 						}
-						// Try to refresh the table lease to one >= this version.
+						// Try to refresh the table lease to one >= this version. An
+						// offline table cannot be leased, so like a dropped table its
+						// existing leases are released as soon as they're unused.
 						if err := purgeOldVersions(
-							ctx, db, table.ID, table.Dropped(), table.Version, m); err != nil {
+							ctx, db, table.ID, table.Dropped() || table.Offline(), table.Version, m); err != nil {
 							log.Warningf(ctx, "error purging leases for table %d(%s): %s",
 								table.ID, table.Name, err)
 						}
-					case *sqlbase.Descriptor_Database:
+					case *sqlbase.Descriptor_Database, *sqlbase.Descriptor_Type:
 						// Ignore.
 					}
 				})
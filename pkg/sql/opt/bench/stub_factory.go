@@ -69,6 +69,16 @@ func (f *stubFactory) ConstructHashJoin(
 	return struct{}{}, nil
 }
 
+func (f *stubFactory) ConstructApplyJoin(
+	joinType sqlbase.JoinType,
+	left exec.Node,
+	rightColumns sqlbase.ResultColumns,
+	onCond tree.TypedExpr,
+	planRightSideFn exec.ApplyJoinPlanRightSideFn,
+) (exec.Node, error) {
+	return struct{}{}, nil
+}
+
 func (f *stubFactory) ConstructMergeJoin(
 	joinType sqlbase.JoinType,
 	left, right exec.Node,
@@ -148,6 +158,16 @@ func (f *stubFactory) ConstructProjectSet(
 	return struct{}{}, nil
 }
 
+func (f *stubFactory) ConstructRecursiveCTE(
+	initial exec.Node, iterationFn exec.RecursiveCTEIterationFn, label string, all bool,
+) (exec.Node, error) {
+	return struct{}{}, nil
+}
+
+func (f *stubFactory) ConstructWorkTableScan(recursiveCTE exec.Node) (exec.Node, error) {
+	return struct{}{}, nil
+}
+
 func (f *stubFactory) RenameColumns(input exec.Node, colNames []string) (exec.Node, error) {
 	return struct{}{}, nil
 }
@@ -152,6 +152,15 @@ type Column interface {
 	// IsHidden returns true if the column is hidden (e.g., there is always a
 	// hidden column called rowid if there is no primary key on the table).
 	IsHidden() bool
+
+	// IsComputed returns true if the column is a computed column, whose values
+	// are computed from the other columns of the table.
+	IsComputed() bool
+
+	// ComputedExprStr returns the expression which computes the values of the
+	// column, in its serialized form. It is only valid if IsComputed returns
+	// true.
+	ComputedExprStr() string
 }
 
 // IndexColumn describes a single column that is part of an index definition.
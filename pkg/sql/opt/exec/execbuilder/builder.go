@@ -15,6 +15,7 @@
 package execbuilder
 
 import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -33,6 +34,15 @@ type Builder struct {
 	// expressions we built. Each entry is associated with a tree.Subquery
 	// expression node.
 	subqueries []exec.Subquery
+
+	// workTables maps the IDs of the working tables of the recursive CTEs
+	// whose recursive query is being built to the nodes of these CTEs (see
+	// buildRecursiveCTE).
+	workTables map[int]exec.Node
+
+	// outerBindings maps the outer columns of the right side of the apply joins
+	// being built to the values of the current left row (see buildApplyJoin).
+	outerBindings map[opt.ColumnID]tree.Datum
 }
 
 // New constructs an instance of the execution node builder using the
@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
@@ -145,17 +146,25 @@ func (b *Builder) buildRelational(ev memo.ExprView) (execPlan, error) {
 	case opt.ZipOp:
 		ep, err = b.buildZip(ev)
 
+	case opt.RecursiveCTEOp:
+		ep, err = b.buildRecursiveCTE(ev)
+
+	case opt.WorkTableScanOp:
+		ep, err = b.buildWorkTableScan(ev)
+
 	default:
 		if ev.IsJoinNonApply() {
 			ep, err = b.buildHashJoin(ev)
 			break
 		}
 		if ev.IsJoinApply() {
-			if ev.Child(1).Operator() == opt.ZipOp {
+			if ev.Operator() == opt.InnerJoinApplyOp && ev.Child(1).Operator() == opt.ZipOp &&
+				ev.Child(2).Operator() == opt.TrueOp {
 				ep, err = b.buildProjectSet(ev)
 				break
 			}
-			return execPlan{}, b.decorrelationError()
+			ep, err = b.buildApplyJoin(ev)
+			break
 		}
 		return execPlan{}, errors.Errorf("unsupported relational op %s", ev.Operator())
 	}
@@ -439,6 +448,103 @@ func (b *Builder) buildMergeJoin(ev memo.ExprView) (execPlan, error) {
 	return ep, nil
 }
 
+// buildApplyJoin builds an apply join which could not be decorrelated. Its
+// right side is built anew for every row of the left side at execution time,
+// with a new builder in which the outer columns of the right side are bound to
+// the values of the row. Note that this happens at execution time, so the memo
+// must not be modified until the execution is over.
+func (b *Builder) buildApplyJoin(ev memo.ExprView) (execPlan, error) {
+	joinType := joinOpToJoinType(ev.Operator())
+	switch joinType {
+	case sqlbase.InnerJoin, sqlbase.LeftOuterJoin, sqlbase.LeftSemiJoin, sqlbase.LeftAntiJoin:
+	default:
+		// The right rows which don't match any left row can't be found by
+		// running the right side for every left row.
+		return execPlan{}, b.decorrelationError()
+	}
+
+	left, err := b.buildRelational(ev.Child(0))
+	if err != nil {
+		return execPlan{}, err
+	}
+
+	// The plans of the right side produce its columns in the order of their
+	// IDs.
+	md := ev.Metadata()
+	right := ev.Child(1)
+	rightProps := right.Logical().Relational
+	rightCols := make(opt.ColList, 0, rightProps.OutputCols.Len())
+	rightProps.OutputCols.ForEach(func(col int) {
+		rightCols = append(rightCols, opt.ColumnID(col))
+	})
+	rightResultCols := make(sqlbase.ResultColumns, len(rightCols))
+	var rightOutputCols opt.ColMap
+	for i, col := range rightCols {
+		rightResultCols[i].Name = md.ColumnLabel(col)
+		rightResultCols[i].Typ = md.ColumnType(col)
+		rightOutputCols.Set(int(col), i)
+	}
+
+	// Find the outer columns of the right side which are produced by the left
+	// side; the others are bound by an enclosing apply join, if any.
+	var boundCols opt.ColList
+	var boundOrds []exec.ColumnOrdinal
+	rightProps.OuterCols.ForEach(func(col int) {
+		if ord, ok := left.outputCols.Get(col); ok {
+			boundCols = append(boundCols, opt.ColumnID(col))
+			boundOrds = append(boundOrds, exec.ColumnOrdinal(ord))
+		}
+	})
+
+	planRightSideFn := func(ef exec.Factory, leftRow tree.Datums) (exec.Node, error) {
+		rb := New(ef, right, b.evalCtx)
+		rb.workTables = b.workTables
+		rb.outerBindings = make(map[opt.ColumnID]tree.Datum, len(b.outerBindings)+len(boundCols))
+		for col, d := range b.outerBindings {
+			rb.outerBindings[col] = d
+		}
+		for i, col := range boundCols {
+			rb.outerBindings[col] = leftRow[boundOrds[i]]
+		}
+
+		plan, err := rb.buildRelational(right)
+		if err != nil {
+			return nil, err
+		}
+		if len(rb.subqueries) > 0 {
+			// The subqueries would have to be evaluated for every left row.
+			return nil, pgerror.Unimplemented("apply join",
+				"subqueries are not supported within the right side of an apply join")
+		}
+		return rb.ensureColumns(plan, rightCols)
+	}
+
+	allCols := joinOutputMap(left.outputCols, rightOutputCols)
+	ctx := buildScalarCtx{
+		ivh:     tree.MakeIndexedVarHelper(nil /* container */, allCols.Len()),
+		ivarMap: allCols,
+	}
+	onExpr, err := b.buildScalar(&ctx, ev.Child(2))
+	if err != nil {
+		return execPlan{}, err
+	}
+
+	node, err := b.factory.ConstructApplyJoin(
+		joinType, left.root, rightResultCols, onExpr, planRightSideFn,
+	)
+	if err != nil {
+		return execPlan{}, err
+	}
+	ep := execPlan{root: node}
+	if joinType == sqlbase.LeftSemiJoin || joinType == sqlbase.LeftAntiJoin {
+		// For semi and anti join, only the left columns are output.
+		ep.outputCols = left.outputCols
+	} else {
+		ep.outputCols = allCols
+	}
+	return ep, nil
+}
+
 // initJoinBuild builds the inputs to the join as well as the ON expression.
 func (b *Builder) initJoinBuild(
 	leftChild memo.ExprView,
@@ -486,22 +592,22 @@ func joinOutputMap(left, right opt.ColMap) opt.ColMap {
 
 func joinOpToJoinType(op opt.Operator) sqlbase.JoinType {
 	switch op {
-	case opt.InnerJoinOp:
+	case opt.InnerJoinOp, opt.InnerJoinApplyOp:
 		return sqlbase.InnerJoin
 
-	case opt.LeftJoinOp:
+	case opt.LeftJoinOp, opt.LeftJoinApplyOp:
 		return sqlbase.LeftOuterJoin
 
-	case opt.RightJoinOp:
+	case opt.RightJoinOp, opt.RightJoinApplyOp:
 		return sqlbase.RightOuterJoin
 
-	case opt.FullJoinOp:
+	case opt.FullJoinOp, opt.FullJoinApplyOp:
 		return sqlbase.FullOuterJoin
 
-	case opt.SemiJoinOp:
+	case opt.SemiJoinOp, opt.SemiJoinApplyOp:
 		return sqlbase.LeftSemiJoin
 
-	case opt.AntiJoinOp:
+	case opt.AntiJoinOp, opt.AntiJoinApplyOp:
 		return sqlbase.LeftAntiJoin
 
 	default:
@@ -962,6 +1068,70 @@ func (b *Builder) buildZip(ev memo.ExprView) (execPlan, error) {
 	return ep, nil
 }
 
+func (b *Builder) buildRecursiveCTE(ev memo.ExprView) (execPlan, error) {
+	initial, err := b.buildRelational(ev.Child(0))
+	if err != nil {
+		return execPlan{}, err
+	}
+	def := ev.Private().(*memo.RecursiveCTEDef)
+	initialNode, err := b.ensureColumns(initial, def.Initial)
+	if err != nil {
+		return execPlan{}, err
+	}
+
+	// The recursive query is built anew for every iteration, with a new builder
+	// in which its working table refers to the node of the recursive CTE. Note
+	// that this happens at execution time, so the memo must not be modified
+	// until the execution is over.
+	recursive := ev.Child(1)
+	iterationFn := func(ef exec.Factory, recursiveCTE exec.Node) (exec.Node, error) {
+		ib := New(ef, recursive, b.evalCtx)
+		ib.workTables = make(map[int]exec.Node, len(b.workTables)+1)
+		for id, n := range b.workTables {
+			ib.workTables[id] = n
+		}
+		ib.workTables[def.WorkTableID] = recursiveCTE
+
+		plan, err := ib.buildRelational(recursive)
+		if err != nil {
+			return nil, err
+		}
+		if len(ib.subqueries) > 0 {
+			// The subqueries would have to be evaluated before every iteration.
+			return nil, pgerror.Unimplemented("with recursive",
+				"subqueries are not supported within the recursive query %q", def.Name)
+		}
+		return ib.ensureColumns(plan, def.Recursive)
+	}
+
+	node, err := b.factory.ConstructRecursiveCTE(initialNode, iterationFn, def.Name, def.All)
+	if err != nil {
+		return execPlan{}, err
+	}
+	ep := execPlan{root: node}
+	for i, col := range def.Out {
+		ep.outputCols.Set(int(col), i)
+	}
+	return ep, nil
+}
+
+func (b *Builder) buildWorkTableScan(ev memo.ExprView) (execPlan, error) {
+	def := ev.Private().(*memo.WorkTableScanDef)
+	recursiveCTE, ok := b.workTables[def.ID]
+	if !ok {
+		return execPlan{}, errors.Errorf("working table %d scanned outside of its recursive CTE", def.ID)
+	}
+	node, err := b.factory.ConstructWorkTableScan(recursiveCTE)
+	if err != nil {
+		return execPlan{}, err
+	}
+	ep := execPlan{root: node}
+	for i, col := range def.Cols {
+		ep.outputCols.Set(int(col), i)
+	}
+	return ep, nil
+}
+
 func (b *Builder) buildProjectSet(ev memo.ExprView) (execPlan, error) {
 	input, err := b.buildRelational(ev.Child(0))
 	if err != nil {
@@ -123,7 +123,15 @@ func (b *Builder) buildNull(ctx *buildScalarCtx, ev memo.ExprView) (tree.TypedEx
 }
 
 func (b *Builder) buildVariable(ctx *buildScalarCtx, ev memo.ExprView) (tree.TypedExpr, error) {
-	return b.indexedVar(ctx, ev.Metadata(), ev.Private().(opt.ColumnID)), nil
+	colID := ev.Private().(opt.ColumnID)
+	if _, ok := ctx.ivarMap.Get(int(colID)); !ok {
+		// An outer column of the right side of an apply join is replaced by its
+		// value in the current left row.
+		if d, ok := b.outerBindings[colID]; ok {
+			return d, nil
+		}
+	}
+	return b.indexedVar(ctx, ev.Metadata(), colID), nil
 }
 
 func (b *Builder) indexedVar(
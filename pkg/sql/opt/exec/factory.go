@@ -89,6 +89,20 @@ type Factory interface {
 	// using IndexedVars (first the left columns, then the right columns).
 	ConstructHashJoin(joinType sqlbase.JoinType, left, right Node, onCond tree.TypedExpr) (Node, error)
 
+	// ConstructApplyJoin returns a node that runs an apply join between the
+	// results of the left node and a right side which is planned by
+	// planRightSideFn once for every left row, since it refers to the values of
+	// that row. The right side produces the given columns. The ON expression can
+	// refer to columns from both sides using IndexedVars (first the left
+	// columns, then the right columns).
+	ConstructApplyJoin(
+		joinType sqlbase.JoinType,
+		left Node,
+		rightColumns sqlbase.ResultColumns,
+		onCond tree.TypedExpr,
+		planRightSideFn ApplyJoinPlanRightSideFn,
+	) (Node, error)
+
 	// ConstructMergeJoin returns a node that (under distsql) runs a merge join.
 	// The ON expression can refer to columns from both inputs using IndexedVars
 	// (first the left columns, then the right columns). In addition, the i-th
@@ -178,6 +192,22 @@ type Factory interface {
 		n Node, exprs tree.TypedExprs, zipCols sqlbase.ResultColumns, numColsPerGen []int,
 	) (Node, error)
 
+	// ConstructRecursiveCTE returns a node that evaluates a recursive CTE. It
+	// emits the rows of the initial node, then evaluates the recursive query
+	// over and over, until an iteration produces no rows. Each iteration is
+	// built by iterationFn, and reads the rows produced by the previous
+	// iteration through a node created by ConstructWorkTableScan. Unless all
+	// is set, rows which were already produced are discarded. The initial node
+	// and the iterations must have the same number of columns.
+	ConstructRecursiveCTE(
+		initial Node, iterationFn RecursiveCTEIterationFn, label string, all bool,
+	) (Node, error)
+
+	// ConstructWorkTableScan returns a node that scans the working table of the
+	// given node created by ConstructRecursiveCTE, that is the rows produced by
+	// the previous iteration of its recursive query.
+	ConstructWorkTableScan(recursiveCTE Node) (Node, error)
+
 	// RenameColumns modifies the column names of a node.
 	RenameColumns(input Node, colNames []string) (Node, error)
 
@@ -194,6 +224,19 @@ type Factory interface {
 	ConstructShowTrace(typ tree.ShowTraceType, compact bool) (Node, error)
 }
 
+// RecursiveCTEIterationFn builds the plan of an iteration of the recursive
+// query of the given recursive CTE node (see ConstructRecursiveCTE), using the
+// given factory. It is called once when the recursive CTE is constructed, and
+// again at execution time for every subsequent iteration, since a plan can
+// only be run once.
+type RecursiveCTEIterationFn func(ef Factory, recursiveCTE Node) (Node, error)
+
+// ApplyJoinPlanRightSideFn builds the plan of the right side of an apply join
+// (see ConstructApplyJoin) for the given row of its left side, using the given
+// factory. It is called at execution time for every left row, since a plan can
+// only be run once.
+type ApplyJoinPlanRightSideFn func(ef Factory, leftRow tree.Datums) (Node, error)
+
 // OutputOrdering indicates the required output ordering on a Node that is being
 // created. It refers to the output columns of the node by ordinal.
 //
@@ -105,6 +105,13 @@ func (m *Memo) CheckExpr(eid ExprID) {
 			panic(fmt.Sprintf("lookup join with no lookup columns"))
 		}
 
+	case opt.RecursiveCTEOp:
+		def := expr.Private(m).(*RecursiveCTEDef)
+		if len(def.Initial) != len(def.Out) || len(def.Recursive) != len(def.Out) {
+			panic(fmt.Sprintf("recursive CTE with mismatched columns: %v, %v, %v",
+				def.Initial, def.Recursive, def.Out))
+		}
+
 	case opt.SelectOp:
 		filter := m.NormExpr(expr.AsSelect().Filter())
 		switch filter.Operator() {
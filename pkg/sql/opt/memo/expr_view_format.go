@@ -131,7 +131,8 @@ func (ev ExprView) formatRelational(f *ExprFmtCtx, tp treeprinter.Node) {
 		formatPrivate(f, def, physProps)
 		f.Buffer.WriteByte(')')
 
-	case opt.ScanOp, opt.VirtualScanOp, opt.IndexJoinOp, opt.ShowTraceForSessionOp:
+	case opt.ScanOp, opt.VirtualScanOp, opt.IndexJoinOp, opt.ShowTraceForSessionOp,
+		opt.RecursiveCTEOp:
 		fmt.Fprintf(f.Buffer, "%v", ev.op)
 		formatPrivate(f, ev.Private(), physProps)
 
@@ -172,6 +173,14 @@ func (ev ExprView) formatRelational(f *ExprFmtCtx, tp treeprinter.Node) {
 			colMap := ev.Private().(*SetOpColMap)
 			ev.formatColList(f, tp, "columns:", colMap.Out)
 
+		case opt.RecursiveCTEOp:
+			def := ev.Private().(*RecursiveCTEDef)
+			ev.formatColList(f, tp, "columns:", def.Out)
+
+		case opt.WorkTableScanOp:
+			def := ev.Private().(*WorkTableScanDef)
+			ev.formatColList(f, tp, "columns:", def.Cols)
+
 		default:
 			// Fall back to writing output columns in column id order, with
 			// best guess label.
@@ -204,6 +213,13 @@ func (ev ExprView) formatRelational(f *ExprFmtCtx, tp treeprinter.Node) {
 		ev.formatColList(f, tp, "left columns:", colMap.Left)
 		ev.formatColList(f, tp, "right columns:", colMap.Right)
 
+		// Similarly, show the columns of the initial and recursive queries of a
+		// recursive CTE.
+	case opt.RecursiveCTEOp:
+		def := ev.Private().(*RecursiveCTEDef)
+		ev.formatColList(f, tp, "initial columns:", def.Initial)
+		ev.formatColList(f, tp, "recursive columns:", def.Recursive)
+
 	case opt.ScanOp:
 		def := ev.Private().(*ScanOpDef)
 		if def.Constraint != nil {
@@ -540,12 +556,19 @@ func formatPrivate(f *ExprFmtCtx, private interface{}, physProps *props.Physical
 	case *MergeOnDef:
 		fmt.Fprintf(f.Buffer, " %s,%s,%s", t.JoinType, t.LeftEq, t.RightEq)
 
+	case *RecursiveCTEDef:
+		fmt.Fprintf(f.Buffer, " %s", t.Name)
+		if t.All {
+			f.Buffer.WriteString(",all")
+		}
+
 	case *props.OrderingChoice:
 		if !t.Any() {
 			fmt.Fprintf(f.Buffer, " ordering=%s", t)
 		}
 
-	case *ExplainOpDef, *ProjectionsOpDef, opt.ColSet, opt.ColList, *SetOpColMap, types.T:
+	case *ExplainOpDef, *ProjectionsOpDef, opt.ColSet, opt.ColList, *SetOpColMap, types.T,
+		*WorkTableScanDef:
 		// Don't show anything, because it's mostly redundant.
 
 	default:
@@ -108,6 +108,12 @@ func (b *logicalPropsBuilder) buildRelationalProps(ev ExprView) props.Logical {
 	case opt.ZipOp:
 		logical = b.buildZipProps(ev)
 
+	case opt.RecursiveCTEOp:
+		logical = b.buildRecursiveCTEProps(ev)
+
+	case opt.WorkTableScanOp:
+		logical = b.buildWorkTableScanProps(ev)
+
 	default:
 		panic(fmt.Sprintf("unrecognized relational expression type: %v", ev.op))
 	}
@@ -1058,6 +1064,94 @@ func (b *logicalPropsBuilder) buildZipProps(ev ExprView) props.Logical {
 	return logical
 }
 
+func (b *logicalPropsBuilder) buildRecursiveCTEProps(ev ExprView) props.Logical {
+	logical := props.Logical{Relational: b.allocRelationalProps()}
+	relational := logical.Relational
+
+	initialProps := ev.childGroup(0).logical.Relational
+	recursiveProps := ev.childGroup(1).logical.Relational
+	def := ev.Private().(*RecursiveCTEDef)
+
+	// Output Columns
+	// --------------
+	// Output columns are stored in the definition.
+	relational.OutputCols = def.Out.ToSet()
+
+	// Not Null Columns
+	// ----------------
+	// Columns have to be not-null in both inputs to be not-null in result.
+	for i := range def.Out {
+		if initialProps.NotNullCols.Contains(int(def.Initial[i])) &&
+			recursiveProps.NotNullCols.Contains(int(def.Recursive[i])) {
+			relational.NotNullCols.Add(int(def.Out[i]))
+		}
+	}
+
+	// Outer Columns
+	// -------------
+	// Outer columns from either input are outer columns of the CTE.
+	relational.OuterCols = initialProps.OuterCols.Union(recursiveProps.OuterCols)
+
+	// Functional Dependencies
+	// -----------------------
+	// UNION eliminates duplicates, so a strict key exists.
+	if !def.All {
+		relational.FuncDeps.AddStrictKey(relational.OutputCols, relational.OutputCols)
+	}
+
+	// Cardinality
+	// -----------
+	// The number of iterations is unknown, so don't make any assumptions about
+	// cardinality of output beyond the rows of the initial query.
+	relational.Cardinality = props.AnyCardinality
+	if initialProps.Cardinality.Min > 0 {
+		relational.Cardinality = props.Cardinality{Min: 1, Max: math.MaxUint32}
+	}
+
+	// Statistics
+	// ----------
+	b.sb.init(b.evalCtx, ev.Metadata())
+	b.sb.buildRecursiveCTE(ev, relational)
+
+	return logical
+}
+
+func (b *logicalPropsBuilder) buildWorkTableScanProps(ev ExprView) props.Logical {
+	logical := props.Logical{Relational: b.allocRelationalProps()}
+	relational := logical.Relational
+
+	def := ev.Private().(*WorkTableScanDef)
+
+	// Output Columns
+	// --------------
+	// Output columns are stored in the definition.
+	relational.OutputCols = def.Cols.ToSet()
+
+	// Not Null Columns
+	// ----------------
+	// All columns are assumed to be nullable.
+
+	// Outer Columns
+	// -------------
+	// WorkTableScan doesn't have outer columns.
+
+	// Functional Dependencies
+	// -----------------------
+	// WorkTableScan operator has an empty FD set.
+
+	// Cardinality
+	// -----------
+	// Don't make any assumptions about cardinality of output.
+	relational.Cardinality = props.AnyCardinality
+
+	// Statistics
+	// ----------
+	b.sb.init(b.evalCtx, ev.Metadata())
+	b.sb.buildWorkTableScan(ev, relational)
+
+	return logical
+}
+
 func (b *logicalPropsBuilder) buildScalarProps(ev ExprView) props.Logical {
 	logical := props.Logical{Scalar: b.allocScalarProps()}
 	scalar := logical.Scalar
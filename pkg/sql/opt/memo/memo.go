@@ -259,6 +259,26 @@ func (m *Memo) Metadata() *opt.Metadata {
 	return &m.metadata
 }
 
+var indexedComputedColsAnnID = opt.NewTableAnnID()
+
+// IndexedComputedCols maps the memo groups of the expressions of the indexed
+// computed columns of a table to the IDs of these columns. Filters on these
+// expressions can be used to constrain scans of the indexes.
+type IndexedComputedCols map[GroupID]opt.ColumnID
+
+// SetIndexedComputedCols associates the given indexed computed columns with the
+// given table.
+func (m *Memo) SetIndexedComputedCols(tabID opt.TableID, cols IndexedComputedCols) {
+	m.metadata.SetTableAnnotation(tabID, indexedComputedColsAnnID, cols)
+}
+
+// IndexedComputedCols returns the indexed computed columns of the given table,
+// previously set by SetIndexedComputedCols, or nil if there are none.
+func (m *Memo) IndexedComputedCols(tabID opt.TableID) IndexedComputedCols {
+	cols, _ := m.metadata.TableAnnotation(tabID, indexedComputedColsAnnID).(IndexedComputedCols)
+	return cols
+}
+
 // RootGroup returns the root memo group previously set via a call to SetRoot.
 func (m *Memo) RootGroup() GroupID {
 	return m.rootGroup
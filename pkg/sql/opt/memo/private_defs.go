@@ -410,6 +410,37 @@ type SetOpColMap struct {
 	Out   opt.ColList
 }
 
+// RecursiveCTEDef defines the value of the Def private field of the
+// RecursiveCTE operator. Like SetOpColMap, it matches the columns of the
+// Initial and Recursive inputs of the operator with its output columns.
+type RecursiveCTEDef struct {
+	// Name is the name of the CTE.
+	Name string
+
+	// WorkTableID identifies the WorkTableScan operators which scan the working
+	// table of this CTE within its Recursive input.
+	WorkTableID int
+
+	// All is set for UNION ALL, in which case duplicate rows are not removed.
+	All bool
+
+	Initial   opt.ColList
+	Recursive opt.ColList
+	Out       opt.ColList
+}
+
+// WorkTableScanDef defines the value of the Def private field of the
+// WorkTableScan operator.
+type WorkTableScanDef struct {
+	// ID identifies the RecursiveCTE whose working table is scanned; it matches
+	// its RecursiveCTEDef.WorkTableID.
+	ID int
+
+	// Cols are the columns of the working table, which match the output
+	// columns of the Initial input of the RecursiveCTE.
+	Cols opt.ColList
+}
+
 // MergeOnDef contains information on the equality columns we are doing a merge
 // join on.
 type MergeOnDef struct {
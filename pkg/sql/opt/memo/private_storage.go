@@ -485,6 +485,49 @@ func (ps *privateStorage) internSetOpColMap(setOpColMap *SetOpColMap) PrivateID
 	return ps.addValue(privateKey{iface: typ, str: ps.keyBuf.String()}, setOpColMap)
 }
 
+// internRecursiveCTEDef adds the given value to storage and returns an id that
+// can later be used to retrieve the value by calling the lookup method. If the
+// value has been previously added to storage, then internRecursiveCTEDef
+// always returns the same private id that was returned from the previous call.
+func (ps *privateStorage) internRecursiveCTEDef(def *RecursiveCTEDef) PrivateID {
+	// The below code is carefully constructed to not allocate in the case where
+	// the value is already in the map. Be careful when modifying.
+	// Write the values of each column list. This works with no length or
+	// separator values because the lists are always the same length.
+	ps.keyBuf.Reset()
+	ps.keyBuf.writeUvarint(uint64(def.WorkTableID))
+	if def.All {
+		ps.keyBuf.WriteByte(1)
+	} else {
+		ps.keyBuf.WriteByte(0)
+	}
+	ps.keyBuf.writeUvarint(uint64(len(def.Name)))
+	ps.keyBuf.WriteString(def.Name)
+	ps.keyBuf.writeColList(def.Initial)
+	ps.keyBuf.writeColList(def.Recursive)
+	ps.keyBuf.writeColList(def.Out)
+	typ := (*RecursiveCTEDef)(nil)
+	if id, ok := ps.privatesMap[privateKey{iface: typ, str: ps.keyBuf.String()}]; ok {
+		return id
+	}
+	return ps.addValue(privateKey{iface: typ, str: ps.keyBuf.String()}, def)
+}
+
+// internWorkTableScanDef adds the given value to storage and returns an id
+// that can later be used to retrieve the value by calling the lookup method. If
+// the value has been previously added to storage, then internWorkTableScanDef
+// always returns the same private id that was returned from the previous call.
+func (ps *privateStorage) internWorkTableScanDef(def *WorkTableScanDef) PrivateID {
+	ps.keyBuf.Reset()
+	ps.keyBuf.writeUvarint(uint64(def.ID))
+	ps.keyBuf.writeColList(def.Cols)
+	typ := (*WorkTableScanDef)(nil)
+	if id, ok := ps.privatesMap[privateKey{iface: typ, str: ps.keyBuf.String()}]; ok {
+		return id
+	}
+	return ps.addValue(privateKey{iface: typ, str: ps.keyBuf.String()}, def)
+}
+
 // internDatum adds the given value to storage and returns an id that can later
 // be used to retrieve the value by calling the lookup method. If the value has
 // been previously added to storage, then internDatum always returns the same
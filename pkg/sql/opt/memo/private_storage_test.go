@@ -620,6 +620,63 @@ func TestInternSetOpColMap(t *testing.T) {
 	test(&SetOpColMap{list1, list2, list3}, &SetOpColMap{list1, list3, list2}, false)
 }
 
+func TestInternRecursiveCTEDef(t *testing.T) {
+	var ps privateStorage
+	ps.init()
+
+	test := func(left, right *RecursiveCTEDef, expected bool) {
+		t.Helper()
+		leftID := ps.internRecursiveCTEDef(left)
+		rightID := ps.internRecursiveCTEDef(right)
+		if (leftID == rightID) != expected {
+			t.Errorf("%v == %v, expected %v, got %v", left, right, expected, !expected)
+		}
+	}
+
+	list1 := opt.ColList{1, 2}
+	list2 := opt.ColList{3, 4}
+	list3 := opt.ColList{5, 6}
+	def := RecursiveCTEDef{Name: "a", WorkTableID: 1, Initial: list1, Recursive: list2, Out: list3}
+
+	def2 := def
+	def2.Initial = opt.ColList{1, 2}
+	test(&def, &def2, true)
+
+	def2 = def
+	def2.Name = "b"
+	test(&def, &def2, false)
+
+	def2 = def
+	def2.WorkTableID = 2
+	test(&def, &def2, false)
+
+	def2 = def
+	def2.All = true
+	test(&def, &def2, false)
+
+	def2 = def
+	def2.Recursive, def2.Out = list3, list2
+	test(&def, &def2, false)
+}
+
+func TestInternWorkTableScanDef(t *testing.T) {
+	var ps privateStorage
+	ps.init()
+
+	test := func(left, right *WorkTableScanDef, expected bool) {
+		t.Helper()
+		leftID := ps.internWorkTableScanDef(left)
+		rightID := ps.internWorkTableScanDef(right)
+		if (leftID == rightID) != expected {
+			t.Errorf("%v == %v, expected %v, got %v", left, right, expected, !expected)
+		}
+	}
+
+	test(&WorkTableScanDef{ID: 1, Cols: opt.ColList{1, 2}}, &WorkTableScanDef{ID: 1, Cols: opt.ColList{1, 2}}, true)
+	test(&WorkTableScanDef{ID: 1, Cols: opt.ColList{1, 2}}, &WorkTableScanDef{ID: 2, Cols: opt.ColList{1, 2}}, false)
+	test(&WorkTableScanDef{ID: 1, Cols: opt.ColList{1, 2}}, &WorkTableScanDef{ID: 1, Cols: opt.ColList{2, 1}}, false)
+}
+
 func TestInternOperator(t *testing.T) {
 	var ps privateStorage
 	ps.init()
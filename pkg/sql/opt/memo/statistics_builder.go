@@ -299,6 +299,10 @@ func (sb *statisticsBuilder) colStat(colSet opt.ColSet, ev ExprView) *props.Colu
 	case opt.ZipOp:
 		return sb.colStatZip(colSet, ev)
 
+	case opt.RecursiveCTEOp, opt.WorkTableScanOp:
+		relProps := ev.Logical().Relational
+		return sb.colStatLeaf(colSet, &relProps.Stats, &relProps.FuncDeps)
+
 	case opt.ExplainOp, opt.ShowTraceForSessionOp:
 		relProps := ev.Logical().Relational
 		return sb.colStatLeaf(colSet, &relProps.Stats, &relProps.FuncDeps)
@@ -1198,6 +1202,40 @@ func (sb *statisticsBuilder) colStatZip(colSet opt.ColSet, ev ExprView) *props.C
 	return colStat
 }
 
+// +--------------+
+// | RecursiveCTE |
+// +--------------+
+
+func (sb *statisticsBuilder) buildRecursiveCTE(ev ExprView, relProps *props.Relational) {
+	s := &relProps.Stats
+	if zeroCardinality := s.Init(relProps); zeroCardinality {
+		// Short cut if cardinality is 0.
+		return
+	}
+
+	// The number of iterations of the recursive query isn't known, so the row
+	// count is at least that of the initial query.
+	initialStats := &ev.childGroup(0).logical.Relational.Stats
+	s.RowCount = max(initialStats.RowCount, unknownRowCount)
+	sb.finalizeFromCardinality(relProps)
+}
+
+// +---------------+
+// | WorkTableScan |
+// +---------------+
+
+func (sb *statisticsBuilder) buildWorkTableScan(ev ExprView, relProps *props.Relational) {
+	s := &relProps.Stats
+	if zeroCardinality := s.Init(relProps); zeroCardinality {
+		// Short cut if cardinality is 0.
+		return
+	}
+
+	// The size of the working table depends on the iteration.
+	s.RowCount = unknownRowCount
+	sb.finalizeFromCardinality(relProps)
+}
+
 /////////////////////////////////////////////////
 // General helper functions for building stats //
 /////////////////////////////////////////////////
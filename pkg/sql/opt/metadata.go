@@ -79,6 +79,7 @@ func (t TableID) index() int {
 // Currently, the following annotations are in use:
 //   - WeakKeys: weak keys derived from the base table
 //   - Stats: statistics derived from the base table
+//   - IndexedComputedCols: expressions of the indexed computed columns
 //
 // To add an additional annotation, increase the value of maxTableAnnIDCount and
 // add a call to NewTableAnnID.
@@ -91,7 +92,7 @@ var tableAnnIDCount TableAnnID
 // called. Calling more than this number of times results in a panic. Having
 // a maximum enables a static annotation array to be inlined into the metadata
 // table struct.
-const maxTableAnnIDCount = 2
+const maxTableAnnIDCount = 3
 
 // Metadata assigns unique ids to the columns, tables, and other metadata used
 // within the scope of a particular query. Because it is specific to one query,
@@ -93,6 +93,10 @@ type Builder struct {
 	// are referenced multiple times in the same query.
 	views map[opt.View]*tree.Select
 
+	// numWorkTables is the number of working tables of recursive CTEs which
+	// have been built so far. It is used to assign them unique IDs.
+	numWorkTables int
+
 	// subquery contains a pointer to the subquery which is currently being built
 	// (if any).
 	subquery *subquery
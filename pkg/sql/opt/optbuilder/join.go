@@ -34,19 +34,39 @@ import (
 // return values.
 func (b *Builder) buildJoin(join *tree.JoinTableExpr, inScope *scope) (outScope *scope) {
 	leftScope := b.buildDataSource(join.Left, nil /* indexFlags */, inScope)
-	rightScope := b.buildDataSource(join.Right, nil /* indexFlags */, inScope)
+
+	joinType := sqlbase.JoinTypeFromAstString(join.Join)
+
+	// The columns of the left side are visible to a lateral right side. Only
+	// inner and left joins can be lateral, since the other joins need the right
+	// rows which don't match any left row.
+	lateral := false
+	rightInScope := inScope
+	if isLateral(join.Right) {
+		switch joinType {
+		case sqlbase.InnerJoin, sqlbase.LeftOuterJoin:
+			lateral = true
+			rightInScope = leftScope
+		default:
+			// Set-returning functions are only implicitly lateral when they can
+			// be.
+			if join.Right.(*tree.AliasedTableExpr).Lateral {
+				panic(builderError{pgerror.NewErrorf(pgerror.CodeInvalidColumnReferenceError,
+					"the combining JOIN type must be INNER or LEFT for a LATERAL reference")})
+			}
+		}
+	}
+	rightScope := b.buildDataSource(join.Right, nil /* indexFlags */, rightInScope)
 
 	// Check that the same table name is not used on both sides.
 	b.validateJoinTableNames(leftScope, rightScope)
 
-	joinType := sqlbase.JoinTypeFromAstString(join.Join)
-
 	switch cond := join.Cond.(type) {
 	case tree.NaturalJoinCond, *tree.UsingJoinCond:
 		outScope = inScope.push()
 
 		var jb usingJoinBuilder
-		jb.init(b, joinType, leftScope, rightScope, outScope)
+		jb.init(b, joinType, lateral, leftScope, rightScope, outScope)
 
 		switch t := cond.(type) {
 		case tree.NaturalJoinCond:
@@ -75,7 +95,9 @@ func (b *Builder) buildJoin(join *tree.JoinTableExpr, inScope *scope) (outScope
 			filter = b.factory.ConstructTrue()
 		}
 
-		outScope.group = b.constructJoin(joinType, leftScope.group, rightScope.group, filter)
+		outScope.group = b.constructJoin(
+			joinType, lateral, leftScope.group, rightScope.group, filter,
+		)
 		return outScope
 
 	default:
@@ -138,9 +160,21 @@ func (b *Builder) findJoinColsToValidate(scope *scope) util.FastIntSet {
 	return ords
 }
 
+// constructJoin constructs a join of the given type. If the right side is
+// lateral and refers to the columns of the left side, the join is an apply
+// join, which the optimizer may still be able to decorrelate.
 func (b *Builder) constructJoin(
-	joinType sqlbase.JoinType, left, right, filter memo.GroupID,
+	joinType sqlbase.JoinType, lateral bool, left, right, filter memo.GroupID,
 ) memo.GroupID {
+	if lateral && b.isCorrelated(right, left) {
+		switch joinType {
+		case sqlbase.InnerJoin:
+			return b.factory.ConstructInnerJoinApply(left, right, filter)
+		case sqlbase.LeftOuterJoin:
+			return b.factory.ConstructLeftJoinApply(left, right, filter)
+		}
+	}
+
 	switch joinType {
 	case sqlbase.InnerJoin:
 		return b.factory.ConstructInnerJoin(left, right, filter)
@@ -155,6 +189,14 @@ func (b *Builder) constructJoin(
 	}
 }
 
+// isCorrelated returns true if the right expression refers to the output
+// columns of the left expression.
+func (b *Builder) isCorrelated(right, left memo.GroupID) bool {
+	mem := b.factory.Memo()
+	outerCols := mem.GroupProperties(right).Relational.OuterCols
+	return outerCols.Intersects(mem.GroupProperties(left).Relational.OutputCols)
+}
+
 // usingJoinBuilder helps to build a USING join or natural join. It finds the
 // columns in the left and right relations that match the columns provided in
 // the names parameter (or names common to both sides in case of natural join),
@@ -220,6 +262,7 @@ type usingJoinBuilder struct {
 	b          *Builder
 	lb         norm.ListBuilder
 	joinType   sqlbase.JoinType
+	lateral    bool
 	leftScope  *scope
 	rightScope *scope
 	outScope   *scope
@@ -238,11 +281,12 @@ type usingJoinBuilder struct {
 }
 
 func (jb *usingJoinBuilder) init(
-	b *Builder, joinType sqlbase.JoinType, leftScope, rightScope, outScope *scope,
+	b *Builder, joinType sqlbase.JoinType, lateral bool, leftScope, rightScope, outScope *scope,
 ) {
 	jb.b = b
 	jb.lb = norm.MakeListBuilder(b.factory.CustomFuncs())
 	jb.joinType = joinType
+	jb.lateral = lateral
 	jb.leftScope = leftScope
 	jb.rightScope = rightScope
 	jb.outScope = outScope
@@ -310,6 +354,7 @@ func (jb *usingJoinBuilder) finishBuild() {
 
 	jb.outScope.group = jb.b.constructJoin(
 		jb.joinType,
+		jb.lateral,
 		jb.leftScope.group,
 		jb.rightScope.group,
 		jb.b.factory.ConstructFilters(jb.lb.BuildList()),
@@ -72,6 +72,9 @@ type scope struct {
 	// context is the current context in the SQL query (e.g., "SELECT" or
 	// "HAVING"). It is used for error messages.
 	context string
+
+	// ctes contains the CTEs which were defined at this scope level, by name.
+	ctes map[tree.Name]*cteSource
 }
 
 // groupByStrSet is a set of stringified GROUP BY expressions that map to the
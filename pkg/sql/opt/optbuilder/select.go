@@ -66,6 +66,11 @@ func (b *Builder) buildDataSource(
 			panic(builderError{err})
 		}
 
+		// If there's a CTE with this name, it takes priority over the catalog.
+		if cte := inScope.resolveCTE(tn); cte != nil {
+			return b.buildCTERef(cte, tn, inScope)
+		}
+
 		ds := b.resolveDataSource(tn)
 		switch t := ds.(type) {
 		case opt.Table:
@@ -317,10 +322,45 @@ func (b *Builder) buildScan(
 		}
 
 		outScope.group = b.factory.ConstructScan(b.factory.InternScanOpDef(&def))
+
+		if ordinals == nil {
+			b.buildIndexedComputedCols(tab, tabID, outScope)
+		}
 	}
 	return outScope
 }
 
+// buildIndexedComputedCols builds memo groups for the expressions of the
+// computed columns of the table which are key columns of an index, and
+// associates them with the table in the memo. outScope must contain all the
+// columns of the table.
+func (b *Builder) buildIndexedComputedCols(tab opt.Table, tabID opt.TableID, outScope *scope) {
+	var cols memo.IndexedComputedCols
+	for i := 0; i < tab.IndexCount(); i++ {
+		idx := tab.Index(i)
+		for j := 0; j < idx.KeyColumnCount(); j++ {
+			idxCol := idx.Column(j)
+			if !idxCol.Column.IsComputed() {
+				continue
+			}
+			colID := tabID.ColumnID(idxCol.Ordinal)
+			expr, err := parser.ParseExpr(idxCol.Column.ComputedExprStr())
+			if err != nil {
+				panic(builderError{err})
+			}
+			texpr := outScope.resolveAndRequireType(expr, idxCol.Column.DatumType())
+			group := b.buildScalar(texpr, outScope, nil /* outScope */, nil /* outCol */, nil /* colRefs */)
+			if cols == nil {
+				cols = make(memo.IndexedComputedCols)
+			}
+			cols[group] = colID
+		}
+	}
+	if cols != nil {
+		b.factory.Memo().SetIndexedComputedCols(tabID, cols)
+	}
+}
+
 // buildWithOrdinality builds a group which appends an increasing integer column to
 // the output. colName optionally denotes the name this column is given, or can
 // be blank for none.
@@ -351,7 +391,10 @@ func (b *Builder) buildWithOrdinality(colName string, inScope *scope) (outScope
 // return values.
 func (b *Builder) buildSelect(stmt *tree.Select, inScope *scope) (outScope *scope) {
 	if stmt.With != nil {
-		panic(unimplementedf("with clause not supported"))
+		inScope = b.buildCTEs(stmt.With, inScope)
+	}
+	if stmt.Locking != nil {
+		panic(unimplementedf("locking clause not supported"))
 	}
 
 	wrapped := stmt.Select
@@ -361,6 +404,9 @@ func (b *Builder) buildSelect(stmt *tree.Select, inScope *scope) (outScope *scop
 	for s, ok := wrapped.(*tree.ParenSelect); ok; s, ok = wrapped.(*tree.ParenSelect) {
 		stmt = s.Select
 		wrapped = stmt.Select
+		if stmt.With != nil {
+			inScope = b.buildCTEs(stmt.With, inScope)
+		}
 		if stmt.OrderBy != nil {
 			if orderBy != nil {
 				panic(builderError{pgerror.NewErrorf(
@@ -377,6 +423,9 @@ func (b *Builder) buildSelect(stmt *tree.Select, inScope *scope) (outScope *scop
 			}
 			limit = stmt.Limit
 		}
+		if stmt.Locking != nil {
+			panic(unimplementedf("locking clause not supported"))
+		}
 	}
 
 	// NB: The case statements are sorted lexicographically.
@@ -528,9 +577,18 @@ func (b *Builder) buildFrom(from *tree.From, where *tree.Where, inScope *scope)
 //
 //   SELECT * FROM a JOIN (b JOIN c ON true) ON true
 //
+// If any of the tables is lateral, they are joined in the order that they
+// appear in the list instead (see buildFromTablesWithLateral).
+//
 // See Builder.buildStmt for a description of the remaining input and
 // return values.
 func (b *Builder) buildFromTables(tables tree.TableExprs, inScope *scope) (outScope *scope) {
+	for _, table := range tables[1:] {
+		if isLateral(table) {
+			return b.buildFromTablesWithLateral(tables, inScope)
+		}
+	}
+
 	outScope = b.buildDataSource(tables[0], nil /* indexFlags */, inScope)
 
 	// Recursively build table join.
@@ -550,6 +608,70 @@ func (b *Builder) buildFromTables(tables tree.TableExprs, inScope *scope) (outSc
 	return outScope
 }
 
+// buildFromTablesWithLateral builds a series of joins that join together the
+// given FROM tables, some of which are lateral. The tables are joined in the
+// order that they appear in the list, so that a lateral table can refer to the
+// columns of all the tables before it. For example:
+//
+//   SELECT * FROM a, b, LATERAL (SELECT * FROM c WHERE c.x = a.x AND c.y = b.y)
+//
+// is joined like:
+//
+//   SELECT * FROM (a JOIN b ON true) JOIN LATERAL (SELECT ...) ON true
+//
+// A lateral table which refers to the columns of the tables before it is joined
+// with an InnerJoinApply operator, which the optimizer may still be able to
+// decorrelate.
+//
+// See Builder.buildStmt for a description of the remaining input and
+// return values.
+func (b *Builder) buildFromTablesWithLateral(
+	tables tree.TableExprs, inScope *scope,
+) (outScope *scope) {
+	outScope = b.buildDataSource(tables[0], nil /* indexFlags */, inScope)
+
+	for _, table := range tables[1:] {
+		lateral := isLateral(table)
+		tableInScope := inScope
+		if lateral {
+			// The columns of the tables before it are visible to a lateral table.
+			tableInScope = outScope
+		}
+		tableScope := b.buildDataSource(table, nil /* indexFlags */, tableInScope)
+
+		// Check that the same table name is not used multiple times.
+		b.validateJoinTableNames(outScope, tableScope)
+
+		outScope.appendColumnsFromScope(tableScope)
+		if lateral && b.isCorrelated(tableScope.group, outScope.group) {
+			outScope.group = b.factory.ConstructInnerJoinApply(
+				outScope.group, tableScope.group, b.factory.ConstructTrue(),
+			)
+		} else {
+			outScope.group = b.factory.ConstructInnerJoin(
+				outScope.group, tableScope.group, b.factory.ConstructTrue(),
+			)
+		}
+	}
+	return outScope
+}
+
+// isLateral returns true if the given table expression can refer to the
+// columns of the tables that precede it in the FROM clause: this is the case
+// of the expressions marked with LATERAL, and of set-returning functions, which
+// are implicitly lateral.
+func isLateral(texpr tree.TableExpr) bool {
+	source, ok := texpr.(*tree.AliasedTableExpr)
+	if !ok {
+		return false
+	}
+	if source.Lateral {
+		return true
+	}
+	_, ok = source.Expr.(*tree.RowsFromExpr)
+	return ok
+}
+
 // validateAsOf ensures that any AS OF SYSTEM TIME timestamp is consistent with
 // that of the root statement.
 func (b *Builder) validateAsOf(asOf tree.AsOfClause) {
@@ -32,7 +32,14 @@ import (
 func (b *Builder) buildUnion(clause *tree.UnionClause, inScope *scope) (outScope *scope) {
 	leftScope := b.buildSelect(clause.Left, inScope)
 	rightScope := b.buildSelect(clause.Right, inScope)
+	return b.buildSetOp(clause, inScope, leftScope, rightScope)
+}
 
+// buildSetOp builds a set of memo groups that represent the given union
+// clause, given the scopes of its already built left and right inputs.
+func (b *Builder) buildSetOp(
+	clause *tree.UnionClause, inScope, leftScope, rightScope *scope,
+) (outScope *scope) {
 	// Remove any hidden columns, as they are not included in the Union.
 	leftScope.removeHiddenCols()
 	rightScope.removeHiddenCols()
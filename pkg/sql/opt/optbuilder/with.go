@@ -0,0 +1,222 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+)
+
+// cteSource represents a CTE defined by a WITH clause. The CTE is built when
+// it is defined, and its group is used by the one data source which may
+// reference it.
+type cteSource struct {
+	cols  []scopeColumn
+	group memo.GroupID
+
+	// used is set once the CTE has been referenced.
+	used bool
+
+	// recursive is set for the reference of a recursive CTE to itself within
+	// its recursive query, in which case group is a WorkTableScan.
+	recursive bool
+
+	// err, if set, is raised when the CTE is referenced. It is used to reject
+	// references to a recursive CTE where they are not allowed.
+	err error
+}
+
+// resolveCTE looks up the given table name in the CTEs defined in this scope
+// and its ancestors, and returns nil if it isn't the name of a CTE.
+func (s *scope) resolveCTE(tn *tree.TableName) *cteSource {
+	if tn.ExplicitSchema {
+		// If the name was prefixed, it cannot be a CTE.
+		return nil
+	}
+	for ; s != nil; s = s.parent {
+		if cte, ok := s.ctes[tn.TableName]; ok {
+			return cte
+		}
+	}
+	return nil
+}
+
+// buildCTEs builds the CTEs of the given WITH clause, and returns a scope in
+// which they can be referenced by name. Each CTE can reference the ones which
+// precede it.
+func (b *Builder) buildCTEs(with *tree.With, inScope *scope) (outScope *scope) {
+	outScope = inScope.push()
+	outScope.ctes = make(map[tree.Name]*cteSource, len(with.CTEList))
+	for _, cte := range with.CTEList {
+		if _, ok := outScope.ctes[cte.Name.Alias]; ok {
+			panic(builderError{pgerror.NewErrorf(
+				pgerror.CodeDuplicateAliasError,
+				"WITH query name %s specified more than once", cte.Name.Alias,
+			)})
+		}
+
+		var cteScope *scope
+		if with.Recursive {
+			cteScope = b.buildRecursiveCTE(cte, outScope)
+		} else {
+			cteScope = b.buildStmt(cte.Stmt, outScope)
+		}
+		cteScope.removeHiddenCols()
+		b.renameSource(cte.Name, cteScope)
+		outScope.ctes[cte.Name.Alias] = &cteSource{cols: cteScope.cols, group: cteScope.group}
+	}
+	return outScope
+}
+
+// buildCTERef builds a data source which references the given CTE.
+//
+// See Builder.buildStmt for a description of the remaining input and
+// return values.
+func (b *Builder) buildCTERef(
+	cte *cteSource, tn *tree.TableName, inScope *scope,
+) (outScope *scope) {
+	if cte.err != nil {
+		panic(builderError{cte.err})
+	}
+	if cte.used {
+		if cte.recursive {
+			panic(builderError{pgerror.NewErrorf(pgerror.CodeInvalidRecursionError,
+				"recursive reference to query %q must not appear more than once", tree.ErrString(tn))})
+		}
+		panic(unimplementedf("unsupported multiple use of CTE clause %q", tree.ErrString(tn)))
+	}
+	cte.used = true
+
+	outScope = inScope.push()
+	outScope.cols = append(outScope.cols, cte.cols...)
+	outScope.group = cte.group
+	return outScope
+}
+
+// recursiveCTEUnion returns the UNION of the initial and recursive queries of
+// a CTE of a WITH RECURSIVE clause, if the CTE has the form:
+//
+//	<initial query> UNION [ALL] <recursive query>
+//
+// Only CTEs of this form may reference themselves.
+func recursiveCTEUnion(stmt tree.Statement) (*tree.UnionClause, bool) {
+	sel, ok := stmt.(*tree.Select)
+	if !ok || sel.With != nil || sel.OrderBy != nil || sel.Limit != nil || sel.Locking != nil {
+		return nil, false
+	}
+	union, ok := sel.Select.(*tree.UnionClause)
+	if !ok || union.Type != tree.UnionOp {
+		return nil, false
+	}
+	return union, true
+}
+
+// buildRecursiveCTE builds a CTE of a WITH RECURSIVE clause. If the CTE
+// references itself, it is built as a RecursiveCTE operator. Otherwise, it is
+// built like any other CTE.
+//
+// See Builder.buildStmt for a description of the remaining input and
+// return values.
+func (b *Builder) buildRecursiveCTE(cte *tree.CTE, inScope *scope) (outScope *scope) {
+	name := cte.Name.Alias
+	union, ok := recursiveCTEUnion(cte.Stmt)
+	if !ok {
+		inScope.ctes[name] = &cteSource{err: pgerror.NewErrorf(pgerror.CodeInvalidRecursionError,
+			"recursive query %q does not have the form non-recursive-term UNION [ALL] recursive-term",
+			tree.ErrString(&name))}
+		defer delete(inScope.ctes, name)
+		return b.buildStmt(cte.Stmt, inScope)
+	}
+
+	inScope.ctes[name] = &cteSource{err: pgerror.NewErrorf(pgerror.CodeInvalidRecursionError,
+		"recursive reference to query %q must not appear within its non-recursive term",
+		tree.ErrString(&name))}
+	initialScope := b.buildSelect(union.Left, inScope)
+	delete(inScope.ctes, name)
+	initialScope.removeHiddenCols()
+
+	// Within the recursive query, the name of the CTE refers to its working
+	// table, which has the columns of the initial query.
+	b.numWorkTables++
+	workTableID := b.numWorkTables
+	workTableScope := inScope.push()
+	for i := range initialScope.cols {
+		col := &initialScope.cols[i]
+		b.synthesizeColumn(workTableScope, string(col.name), col.typ, nil, 0 /* group */)
+	}
+	workTableScope.group = b.factory.ConstructWorkTableScan(
+		b.factory.InternWorkTableScanDef(&memo.WorkTableScanDef{
+			ID:   workTableID,
+			Cols: colsToColList(workTableScope.cols),
+		}),
+	)
+	b.renameSource(cte.Name, workTableScope)
+	workTable := &cteSource{cols: workTableScope.cols, group: workTableScope.group, recursive: true}
+
+	recursiveInScope := inScope.push()
+	recursiveInScope.ctes = map[tree.Name]*cteSource{name: workTable}
+	recursiveScope := b.buildSelect(union.Right, recursiveInScope)
+	if !workTable.used {
+		// The CTE doesn't reference itself: it is a plain UNION.
+		return b.buildSetOp(union, inScope, initialScope, recursiveScope)
+	}
+	recursiveScope.removeHiddenCols()
+
+	if len(initialScope.cols) != len(recursiveScope.cols) {
+		panic(builderError{pgerror.NewErrorf(
+			pgerror.CodeSyntaxError,
+			"each UNION query must have the same number of columns: %d vs %d",
+			len(initialScope.cols), len(recursiveScope.cols),
+		)})
+	}
+	for i := range initialScope.cols {
+		l := &initialScope.cols[i]
+		r := &recursiveScope.cols[i]
+		if !(l.typ.Equivalent(r.typ) || r.typ == types.Unknown) {
+			panic(builderError{pgerror.NewErrorf(pgerror.CodeDatatypeMismatchError,
+				"recursive query %q column %d has type %s in non-recursive term but type %s in recursive term",
+				tree.ErrString(&name), i+1, l.typ, r.typ)})
+		}
+	}
+
+	// The iterations of the recursive query are planned independently of the
+	// rest of the query, so they can't reference its columns.
+	initialEv := memo.MakeNormExprView(b.factory.Memo(), initialScope.group)
+	recursiveEv := memo.MakeNormExprView(b.factory.Memo(), recursiveScope.group)
+	if !initialEv.Logical().Relational.OuterCols.Empty() ||
+		!recursiveEv.Logical().Relational.OuterCols.Empty() {
+		panic(unimplementedf("correlated recursive query %q is not supported", tree.ErrString(&name)))
+	}
+
+	outScope = inScope.push()
+	for i := range initialScope.cols {
+		col := &initialScope.cols[i]
+		b.synthesizeColumn(outScope, string(col.name), col.typ, nil, 0 /* group */)
+	}
+	def := memo.RecursiveCTEDef{
+		Name:        string(name),
+		WorkTableID: workTableID,
+		All:         union.All,
+		Initial:     colsToColList(initialScope.cols),
+		Recursive:   colsToColList(recursiveScope.cols),
+		Out:         colsToColList(outScope.cols),
+	}
+	outScope.group = b.factory.ConstructRecursiveCTE(
+		initialScope.group, recursiveScope.group, b.factory.InternRecursiveCTEDef(&def),
+	)
+	return outScope
+}
@@ -74,6 +74,10 @@ func mapPrivateType(typ string) string {
 		return "*memo.RowNumberDef"
 	case "SetOpColMap":
 		return "*memo.SetOpColMap"
+	case "RecursiveCTEDef":
+		return "*memo.RecursiveCTEDef"
+	case "WorkTableScanDef":
+		return "*memo.WorkTableScanDef"
 	case "ExplainOpDef":
 		return "*memo.ExplainOpDef"
 	case "ShowTraceOpDef":
@@ -227,6 +227,10 @@ func (tt *Table) addColumn(def *tree.ColumnTableDef) {
 	nullable := !def.PrimaryKey && def.Nullable.Nullability != tree.NotNull
 	typ := coltypes.CastTargetToDatumType(def.Type)
 	col := &Column{Name: string(def.Name), Type: typ, Nullable: nullable}
+	if def.IsComputed() {
+		s := tree.Serialize(def.Computed.Expr)
+		col.ComputedExpr = &s
+	}
 	tt.Columns = append(tt.Columns, col)
 }
 
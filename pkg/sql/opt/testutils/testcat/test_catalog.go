@@ -427,10 +427,11 @@ func (ti *Index) Column(i int) opt.IndexColumn {
 
 // Column implements the opt.Column interface for testing purposes.
 type Column struct {
-	Hidden   bool
-	Nullable bool
-	Name     string
-	Type     types.T
+	Hidden       bool
+	Nullable     bool
+	Name         string
+	Type         types.T
+	ComputedExpr *string
 }
 
 var _ opt.Column = &Column{}
@@ -455,6 +456,19 @@ func (tc *Column) IsHidden() bool {
 	return tc.Hidden
 }
 
+// IsComputed is part of the opt.Column interface.
+func (tc *Column) IsComputed() bool {
+	return tc.ComputedExpr != nil
+}
+
+// ComputedExprStr is part of the opt.Column interface.
+func (tc *Column) ComputedExprStr() string {
+	if tc.ComputedExpr == nil {
+		return ""
+	}
+	return *tc.ComputedExpr
+}
+
 // TableStat implements the opt.TableStatistic interface for testing purposes.
 type TableStat struct {
 	js stats.JSONStatistic
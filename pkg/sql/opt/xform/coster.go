@@ -134,6 +134,12 @@ func (c *coster) ComputeCost(candidate *memo.BestExpr, logical *props.Logical) m
 	case opt.ZipOp:
 		cost = c.computeZipCost(candidate, logical)
 
+	case opt.RecursiveCTEOp:
+		cost = c.computeRecursiveCTECost(candidate, logical)
+
+	case opt.WorkTableScanOp:
+		cost = c.computeWorkTableScanCost(candidate, logical)
+
 	case opt.ExplainOp:
 		// Technically, the cost of an Explain operation is independent of the cost
 		// of the underlying plan. However, we want to explain the plan we would get
@@ -392,6 +398,30 @@ func (c *coster) computeZipCost(candidate *memo.BestExpr, logical *props.Logical
 	return cost + c.computeChildrenCost(candidate)
 }
 
+func (c *coster) computeRecursiveCTECost(
+	candidate *memo.BestExpr, logical *props.Logical,
+) memo.Cost {
+	// Add the CPU cost of emitting the rows, which are also saved in the working
+	// table. Unless duplicates are kept, every row also requires a hash table
+	// lookup.
+	cost := memo.Cost(logical.Relational.Stats.RowCount) * cpuCostFactor
+	if !candidate.Private(c.mem).(*memo.RecursiveCTEDef).All {
+		cost *= 2
+	}
+
+	// The recursive query is evaluated once per iteration. The number of
+	// iterations is unknown, but the child cost is counted at least once.
+	return cost + c.computeChildrenCost(candidate)
+}
+
+func (c *coster) computeWorkTableScanCost(
+	candidate *memo.BestExpr, logical *props.Logical,
+) memo.Cost {
+	// Add the CPU cost of emitting the rows, which are held in memory or in
+	// temporary storage.
+	return memo.Cost(logical.Relational.Stats.RowCount) * cpuCostFactor
+}
+
 func (c *coster) computeChildrenCost(candidate *memo.BestExpr) memo.Cost {
 	var cost memo.Cost
 	for i := 0; i < candidate.ChildCount(); i++ {
@@ -190,6 +190,10 @@ func (c *CustomFuncs) GenerateConstrainedScans(
 	var sb indexScanBuilder
 	sb.init(c, scanOpDef.Table)
 
+	// Filters on the expressions of indexed computed columns can constrain
+	// the indexes over these columns.
+	computedFilter := c.replaceIndexedComputedExprs(filter, scanOpDef.Table)
+
 	// Iterate over all indexes.
 	var iter scanIndexIter
 	iter.init(c.e.mem, scanOpDef)
@@ -197,6 +201,13 @@ func (c *CustomFuncs) GenerateConstrainedScans(
 		// Check whether the filter can constrain the index.
 		constraint, remaining, ok := c.tryConstrainIndex(
 			filter, scanOpDef.Table, iter.indexOrdinal, false /* isInverted */)
+		if !ok && computedFilter != 0 {
+			// The whole filter still needs to be applied, since it is in terms
+			// of the other columns of the table.
+			constraint, _, ok = c.tryConstrainIndex(
+				computedFilter, scanOpDef.Table, iter.indexOrdinal, false /* isInverted */)
+			remaining = filter
+		}
 		if !ok {
 			continue
 		}
@@ -243,6 +254,34 @@ func (c *CustomFuncs) GenerateConstrainedScans(
 	return c.e.exprs
 }
 
+// replaceIndexedComputedExprs returns the filter with the expressions of the
+// indexed computed columns of the table replaced by references to these
+// columns, or 0 if the filter contains none of these expressions.
+func (c *CustomFuncs) replaceIndexedComputedExprs(
+	filter memo.GroupID, tabID opt.TableID,
+) memo.GroupID {
+	cols := c.e.mem.IndexedComputedCols(tabID)
+	if cols == nil {
+		return 0
+	}
+	var replace memo.ReplaceChildFunc
+	replace = func(child memo.GroupID) memo.GroupID {
+		if colID, ok := cols[child]; ok {
+			return c.e.f.ConstructVariable(c.e.f.InternColumnID(colID))
+		}
+		ev := memo.MakeNormExprView(c.e.mem, child)
+		if ev.Logical().Scalar == nil {
+			// Don't replace expressions within subqueries.
+			return child
+		}
+		return ev.Replace(c.e.evalCtx, replace).Group()
+	}
+	if replaced := replace(filter); replaced != filter {
+		return replaced
+	}
+	return 0
+}
+
 // HasInvertedIndexes returns true if at least one inverted index is defined on
 // the Scan operator's table.
 func (c *CustomFuncs) HasInvertedIndexes(def memo.PrivateID) bool {
@@ -272,6 +272,54 @@ func (ef *execFactory) ConstructHashJoin(
 	return p.makeJoinNode(leftSrc, rightSrc, pred), nil
 }
 
+// ConstructApplyJoin is part of the exec.Factory interface.
+func (ef *execFactory) ConstructApplyJoin(
+	joinType sqlbase.JoinType,
+	left exec.Node,
+	rightColumns sqlbase.ResultColumns,
+	onCond tree.TypedExpr,
+	planRightSideFn exec.ApplyJoinPlanRightSideFn,
+) (exec.Node, error) {
+	leftSrc := asDataSource(left)
+	rightInfo := &sqlbase.DataSourceInfo{SourceColumns: rightColumns}
+	pred, err := makePredicate(joinType, leftSrc.info, rightInfo, nil /* usingColumns */)
+	if err != nil {
+		return nil, err
+	}
+	if onCond != nil && onCond != tree.DBoolTrue {
+		pred.onCond = pred.iVarHelper.Rebind(
+			onCond, false /* alsoReset */, false, /* normalizeToNonNil */
+		)
+	}
+
+	// The right side is also planned with NULL values for the columns of the
+	// left side, so that EXPLAIN can show it. This plan is never run.
+	nulls := make(tree.Datums, len(leftSrc.info.SourceColumns))
+	for i := range nulls {
+		nulls[i] = tree.DNull
+	}
+	right, err := planRightSideFn(ef, nulls)
+	if err != nil {
+		return nil, err
+	}
+
+	return &applyJoinNode{
+		joinType: joinType,
+		input:    leftSrc,
+		right:    right.(planNode),
+		planRightSideFn: func(params runParams, leftRow tree.Datums) (planNode, error) {
+			ef := makeExecFactory(params.p)
+			plan, err := planRightSideFn(&ef, leftRow)
+			if err != nil {
+				return nil, err
+			}
+			return plan.(planNode), nil
+		},
+		pred:    pred,
+		columns: pred.info.SourceColumns,
+	}, nil
+}
+
 // ConstructMergeJoin is part of the exec.Factory interface.
 func (ef *execFactory) ConstructMergeJoin(
 	joinType sqlbase.JoinType,
@@ -649,6 +697,39 @@ func (ef *execFactory) ConstructProjectSet(
 	return p, nil
 }
 
+// ConstructRecursiveCTE is part of the exec.Factory interface.
+func (ef *execFactory) ConstructRecursiveCTE(
+	initial exec.Node, iterationFn exec.RecursiveCTEIterationFn, label string, all bool,
+) (exec.Node, error) {
+	initialPlan := initial.(planNode)
+	n := &recursiveCTENode{
+		initial: initialPlan,
+		columns: append(sqlbase.ResultColumns(nil), planColumns(initialPlan)...),
+		label:   label,
+		all:     all,
+	}
+	recursive, err := iterationFn(ef, n)
+	if err != nil {
+		return nil, err
+	}
+	n.recursive = recursive.(planNode)
+	n.genIterationFn = func(params runParams, n *recursiveCTENode) (planNode, error) {
+		ef := makeExecFactory(params.p)
+		plan, err := iterationFn(&ef, n)
+		if err != nil {
+			return nil, err
+		}
+		return plan.(planNode), nil
+	}
+	return n, nil
+}
+
+// ConstructWorkTableScan is part of the exec.Factory interface.
+func (ef *execFactory) ConstructWorkTableScan(recursiveCTE exec.Node) (exec.Node, error) {
+	n := recursiveCTE.(*recursiveCTENode)
+	return &workTableScanNode{cte: n, columns: n.columns}, nil
+}
+
 // ConstructPlan is part of the exec.Factory interface.
 func (ef *execFactory) ConstructPlan(
 	root exec.Node, subqueries []exec.Subquery,
@@ -259,6 +259,17 @@ func (p *planner) propagateFilters(
 			return plan, extraFilter, err
 		}
 
+	case *recursiveCTENode:
+		// The filter can't be propagated into the queries of a recursive CTE:
+		// the rows it would remove could produce more rows in the next
+		// iterations.
+		if n.initial, err = p.triggerFilterPropagation(ctx, n.initial); err != nil {
+			return plan, extraFilter, err
+		}
+		if n.recursive, err = p.triggerFilterPropagation(ctx, n.recursive); err != nil {
+			return plan, extraFilter, err
+		}
+
 	case *createTableNode:
 		if n.n.As() {
 			if n.sourcePlan, err = p.triggerFilterPropagation(ctx, n.sourcePlan); err != nil {
@@ -359,6 +370,7 @@ func (p *planner) propagateFilters(
 	case *alterIndexNode:
 	case *alterTableNode:
 	case *alterSequenceNode:
+	case *alterTypeNode:
 	case *alterUserSetPasswordNode:
 	case *scrubNode:
 	case *createDatabaseNode:
@@ -366,6 +378,7 @@ func (p *planner) propagateFilters(
 	case *CreateUserNode:
 	case *createViewNode:
 	case *createSequenceNode:
+	case *createTypeNode:
 	case *createStatsNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
@@ -375,6 +388,7 @@ func (p *planner) propagateFilters(
 	case *DropUserNode:
 	case *hookFnNode:
 	case *valuesNode:
+	case *workTableScanNode:
 	case *sequenceSelectNode:
 	case *setVarNode:
 	case *setClusterSettingNode:
@@ -112,6 +112,16 @@ func (p *planner) applyLimit(plan planNode, numRows int64, soft bool) {
 			p.applyLimit(n.left, numRows, true)
 		}
 
+	case *recursiveCTENode:
+		// The limit doesn't carry over to the queries of a recursive CTE: all
+		// the rows of an iteration are read before the next one starts.
+		if n.initial != nil {
+			p.setUnlimited(n.initial)
+		}
+		if n.recursive != nil {
+			p.setUnlimited(n.recursive)
+		}
+
 	case *distinctNode:
 		p.applyLimit(n.plan, numRows, true)
 
@@ -202,9 +212,11 @@ func (p *planner) applyLimit(plan planNode, numRows int64, soft bool) {
 		p.setUnlimited(n.rows)
 
 	case *valuesNode:
+	case *workTableScanNode:
 	case *alterIndexNode:
 	case *alterTableNode:
 	case *alterSequenceNode:
+	case *alterTypeNode:
 	case *alterUserSetPasswordNode:
 	case *scrubNode:
 	case *createDatabaseNode:
@@ -212,6 +224,7 @@ func (p *planner) applyLimit(plan planNode, numRows int64, soft bool) {
 	case *CreateUserNode:
 	case *createViewNode:
 	case *createSequenceNode:
+	case *createTypeNode:
 	case *createStatsNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
@@ -235,6 +248,14 @@ func (p *planner) applyLimit(plan planNode, numRows int64, soft bool) {
 	case *lookupJoinNode:
 		// The lookup join node is only planned by the optimizer.
 
+	case *applyJoinNode:
+		// The apply join node is only planned by the optimizer. A limit doesn't
+		// carry over to the right side, which is planned for every left row.
+		p.setUnlimited(n.input.plan)
+		if n.right != nil {
+			p.setUnlimited(n.right)
+		}
+
 	default:
 		panic(fmt.Sprintf("unhandled node type: %T", plan))
 	}
@@ -75,6 +75,14 @@ func setNeededColumns(plan planNode, needed []bool) {
 		setNeededColumns(n.right, needed)
 		markOmitted(n.columns, needed)
 
+	case *recursiveCTENode:
+		// All the columns are needed: they make up the working table, which
+		// the recursive query reads, and the set of rows seen so far for UNION.
+		setNeededColumns(n.initial, allColumns(n.initial))
+		setNeededColumns(n.recursive, allColumns(n.recursive))
+
+	case *workTableScanNode:
+
 	case *joinNode:
 		// Note: getNeededColumns takes into account both the columns
 		// tested for equality and the join predicate expression.
@@ -251,6 +259,7 @@ func setNeededColumns(plan planNode, needed []bool) {
 	case *alterIndexNode:
 	case *alterTableNode:
 	case *alterSequenceNode:
+	case *alterTypeNode:
 	case *alterUserSetPasswordNode:
 	case *scrubNode:
 	case *createDatabaseNode:
@@ -258,6 +267,7 @@ func setNeededColumns(plan planNode, needed []bool) {
 	case *CreateUserNode:
 	case *createViewNode:
 	case *createSequenceNode:
+	case *createTypeNode:
 	case *createStatsNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
@@ -247,6 +247,8 @@ func TestContextualHelp(t *testing.T) {
 
 		{`SHOW JOBS ??`, `SHOW JOBS`},
 
+		{`SHOW SCHEDULES ??`, `SHOW SCHEDULES`},
+
 		{`SHOW BACKUP 'foo' ??`, `SHOW BACKUP`},
 
 		{`SHOW CLUSTER SETTING all ??`, `SHOW CLUSTER SETTING`},
@@ -363,6 +365,9 @@ func TestContextualHelp(t *testing.T) {
 		{`BACKUP DATABASE ??`, `BACKUP`},
 		{`BACKUP foo TO 'bar' AS OF ??`, `BACKUP`},
 
+		{`CREATE SCHEDULE ??`, `CREATE SCHEDULE FOR BACKUP`},
+		{`CREATE SCHEDULE FOR BACKUP foo INTO 'bar' ??`, `CREATE SCHEDULE FOR BACKUP`},
+
 		{`RESTORE foo FROM 'bar' ??`, `RESTORE`},
 		{`RESTORE DATABASE ??`, `RESTORE`},
 
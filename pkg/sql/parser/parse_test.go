@@ -80,6 +80,12 @@ func TestParse(t *testing.T) {
 		{`CREATE INDEX ON a (b) INTERLEAVE IN PARENT c (d)`},
 		{`CREATE INDEX ON a (b) INTERLEAVE IN PARENT c.d (e)`},
 		{`CREATE INDEX ON a (b ASC, c DESC)`},
+		{`CREATE INDEX ON a (lower(b))`},
+		{`CREATE INDEX ON a (lower(b) DESC, c)`},
+		{`CREATE INDEX ON a ((b + c) ASC)`},
+		{`CREATE INDEX ON a ((b))`},
+		{`CREATE UNIQUE INDEX a ON b (lower(c)) STORING (d)`},
+		{`CREATE TABLE a (b STRING, INDEX (lower(b)))`},
 		{`CREATE UNIQUE INDEX a ON b (c)`},
 		{`CREATE UNIQUE INDEX a ON b (c) STORING (d)`},
 		{`CREATE UNIQUE INDEX a ON b (c) INTERLEAVE IN PARENT d (e, f)`},
@@ -184,6 +190,7 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a.b (b INT)`},
 		{`CREATE TABLE IF NOT EXISTS a (b INT)`},
 		{`CREATE TABLE a (b INT AS (a + b) STORED)`},
+		{`CREATE TABLE a (b INT AS (a + b) VIRTUAL)`},
 		{`CREATE TABLE view (view INT)`},
 
 		{`CREATE TABLE a (b INT CONSTRAINT c PRIMARY KEY)`},
@@ -264,6 +271,13 @@ func TestParse(t *testing.T) {
 		{`CREATE SEQUENCE a INCREMENT 5 NO CYCLE NO MAXVALUE MINVALUE 1 START 3 CACHE 1`},
 		{`CREATE SEQUENCE a VIRTUAL`},
 
+		{`CREATE TYPE a AS ENUM ()`},
+		{`CREATE TYPE a AS ENUM ('b')`},
+		{`CREATE TYPE a.b AS ENUM ('c', 'd', 'e')`},
+		{`CREATE TABLE a (b mood)`},
+		{`CREATE TABLE a (b public.mood DEFAULT 'happy')`},
+		{`CREATE TABLE a (b "Mood")`},
+
 		{`CREATE STATISTICS a ON col1 FROM t`},
 		{`CREATE STATISTICS a ON col1, col2 FROM t`},
 		{`CREATE STATISTICS a ON col1 FROM d.t`},
@@ -362,6 +376,7 @@ func TestParse(t *testing.T) {
 		{`SHOW ROLES`},
 		{`SHOW USERS`},
 		{`SHOW JOBS`},
+		{`SHOW SCHEDULES`},
 		{`SHOW CLUSTER QUERIES`},
 		{`SHOW LOCAL QUERIES`},
 		{`SHOW CLUSTER SESSIONS`},
@@ -645,6 +660,10 @@ func TestParse(t *testing.T) {
 		{`SELECT "FROM" FROM t`},
 		{`SELECT CAST(1 AS STRING)`},
 		{`SELECT ANNOTATE_TYPE(1, STRING)`},
+		{`SELECT CAST(1 AS mood)`},
+		{`SELECT ANNOTATE_TYPE('happy', mood)`},
+		{`SELECT 'happy'::public.mood`},
+		{`SELECT mood 'happy'`},
 		{`SELECT a FROM t AS bar`},
 		{`SELECT a FROM t AS bar (bar1)`},
 		{`SELECT a FROM t AS bar (bar1, bar2, bar3)`},
@@ -657,6 +676,10 @@ func TestParse(t *testing.T) {
 		{`SELECT a FROM (SELECT 1 FROM t) WITH ORDINALITY`},
 		{`SELECT a FROM (SELECT 1 FROM t) WITH ORDINALITY AS bar`},
 		{`SELECT a FROM ROWS FROM (a(x), b(y), c(z))`},
+		{`SELECT * FROM ab, LATERAL (SELECT * FROM kv)`},
+		{`SELECT * FROM ab, LATERAL (SELECT * FROM kv) WITH ORDINALITY AS x`},
+		{`SELECT * FROM ab LEFT JOIN LATERAL (SELECT * FROM kv WHERE k = a) AS x ON true`},
+		{`SELECT * FROM ab, LATERAL ROWS FROM (foo(a), bar(b))`},
 		{`SELECT a FROM t1, t2`},
 		{`SELECT a FROM t AS t1`},
 		{`SELECT a FROM t AS t1 (c1)`},
@@ -858,6 +881,19 @@ func TestParse(t *testing.T) {
 		{`SELECT a FROM t LIMIT a`},
 		{`SELECT a FROM t OFFSET b`},
 		{`SELECT a FROM t LIMIT a OFFSET b`},
+		{`SELECT a FROM t FOR UPDATE`},
+		{`SELECT a FROM t FOR NO KEY UPDATE`},
+		{`SELECT a FROM t FOR SHARE`},
+		{`SELECT a FROM t FOR KEY SHARE`},
+		{`SELECT a FROM t FOR UPDATE NOWAIT`},
+		{`SELECT a FROM t FOR UPDATE SKIP LOCKED`},
+		{`SELECT a FROM t, u FOR UPDATE OF t, u`},
+		{`SELECT a FROM t, u FOR UPDATE OF t FOR SHARE OF u SKIP LOCKED`},
+		{`SELECT a FROM t ORDER BY a LIMIT 1 FOR UPDATE`},
+		{`WITH a AS (SELECT 1) SELECT * FROM t FOR UPDATE`},
+		{`WITH RECURSIVE a AS (SELECT 1) SELECT * FROM a`},
+		{`WITH RECURSIVE a (x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM a WHERE x < 10) SELECT * FROM a`},
+		{`WITH RECURSIVE a AS (SELECT 1 UNION SELECT 2 FROM a), b AS (SELECT * FROM a) INSERT INTO t SELECT * FROM b`},
 		{`SELECT DISTINCT * FROM t`},
 		{`SELECT DISTINCT a, b FROM t`},
 		{`SELECT DISTINCT ON (a, b) c FROM t`},
@@ -1050,6 +1086,11 @@ func TestParse(t *testing.T) {
 		{`ALTER TABLE t EXPERIMENTAL_AUDIT SET OFF`},
 
 		{`ALTER SEQUENCE a RENAME TO b`},
+
+		{`ALTER TYPE a ADD VALUE 'b'`},
+		{`ALTER TYPE a ADD VALUE IF NOT EXISTS 'b'`},
+		{`ALTER TYPE a ADD VALUE 'b' BEFORE 'c'`},
+		{`ALTER TYPE a.b ADD VALUE IF NOT EXISTS 'c' AFTER 'd'`},
 		{`ALTER SEQUENCE IF EXISTS a RENAME TO b`},
 		{`ALTER SEQUENCE a INCREMENT BY 5 START WITH 1000`},
 		{`ALTER SEQUENCE IF EXISTS a INCREMENT BY 5 START WITH 1000`},
@@ -1072,9 +1113,14 @@ func TestParse(t *testing.T) {
 		{`SHOW BACKUP 'bar'`},
 		{`SHOW BACKUP RANGES 'bar'`},
 		{`SHOW BACKUP FILES 'bar'`},
+		{`SHOW BACKUP 'bar' WITH encryption_passphrase = 'secret'`},
+		{`SHOW BACKUP RANGES 'bar' WITH encryption_key_file = 'nodelocal:///key'`},
 		{`BACKUP TABLE foo TO 'bar' AS OF SYSTEM TIME '1' INCREMENTAL FROM 'baz'`},
 		{`BACKUP TABLE foo TO $1 INCREMENTAL FROM 'bar', $2, 'baz'`},
 		{`BACKUP DATABASE foo TO 'bar'`},
+		{`CREATE SCHEDULE FOR BACKUP TABLE foo INTO 'bar' RECURRING '@hourly'`},
+		{`CREATE SCHEDULE 'my schedule' FOR BACKUP DATABASE foo, baz INTO 'bar' RECURRING '@hourly' FULL BACKUP '@daily'`},
+		{`CREATE SCHEDULE $1 FOR BACKUP TABLE foo INTO $2 WITH key1, key2 = 'value' RECURRING $3 FULL BACKUP $4`},
 		{`BACKUP DATABASE foo, baz TO 'bar'`},
 		{`BACKUP DATABASE foo TO 'bar' AS OF SYSTEM TIME '1' INCREMENTAL FROM 'baz'`},
 		{`RESTORE TABLE foo FROM 'bar'`},
@@ -1085,6 +1131,9 @@ func TestParse(t *testing.T) {
 		{`RESTORE DATABASE foo FROM 'bar'`},
 		{`RESTORE DATABASE foo, baz FROM 'bar'`},
 		{`RESTORE DATABASE foo, baz FROM 'bar' AS OF SYSTEM TIME '1'`},
+		{`RESTORE TABLE foo FROM LATEST IN 'bar'`},
+		{`RESTORE TABLE foo FROM LATEST IN $1 AS OF SYSTEM TIME '1'`},
+		{`RESTORE DATABASE foo FROM LATEST IN 'bar' AS OF SYSTEM TIME '1' WITH key1, key2 = 'value'`},
 		{`BACKUP TABLE foo TO 'bar' WITH key1, key2 = 'value'`},
 		{`RESTORE TABLE foo FROM 'bar' WITH key1, key2 = 'value'`},
 		{`IMPORT TABLE foo CREATE USING 'nodelocal:///some/file' CSV DATA ('path/to/some/file', $1) WITH temp = 'path/to/temp'`},
@@ -1093,10 +1142,16 @@ func TestParse(t *testing.T) {
 		{`IMPORT TABLE foo (id INT, email STRING, age INT) CSV DATA ('path/to/some/file', $1) WITH comma = ',', "nullif" = 'n/a', temp = $2`},
 		{`IMPORT TABLE foo FROM PGDUMPCREATE 'nodelocal:///foo/bar' WITH temp = 'path/to/temp'`},
 		{`IMPORT PGDUMP 'nodelocal:///foo/bar' WITH temp = 'path/to/temp'`},
+		{`IMPORT TABLE foo (id INT PRIMARY KEY, email STRING) AVRO DATA ('path/to/some/file') WITH strict_mode`},
+		{`IMPORT TABLE foo CREATE USING 'nodelocal:///some/file' JSONLINES DATA ('path/to/some/file')`},
+		{`IMPORT INTO foo CSV DATA ('path/to/some/file', $1) WITH temp = 'path/to/temp'`},
+		{`IMPORT INTO foo (id, email) CSV DATA ('path/to/some/file', $1) WITH delimiter = '|'`},
 		{`EXPORT INTO CSV 'a' FROM TABLE a`},
 		{`EXPORT INTO CSV 'a' FROM SELECT * FROM a`},
 		{`EXPORT INTO CSV 's3://my/path/%part%.csv' WITH delimiter = '|' FROM TABLE a`},
 		{`EXPORT INTO CSV 's3://my/path/%part%.csv' WITH delimiter = '|' FROM SELECT a, sum(b) FROM c WHERE d = 1 ORDER BY sum(b) DESC LIMIT 10`},
+		{`EXPORT INTO PARQUET 'a' FROM TABLE a`},
+		{`EXPORT INTO AVRO 's3://my/path' WITH compression = 'snappy' FROM SELECT * FROM a`},
 		{`SET ROW (1, true, NULL)`},
 
 		{`CREATE CHANGEFEED FOR TABLE foo`},
@@ -1107,6 +1162,9 @@ func TestParse(t *testing.T) {
 		// {`CREATE CHANGEFEED FOR TABLE foo PARTITION bar, baz INTO 'sink'`},
 		// {`CREATE CHANGEFEED FOR DATABASE foo INTO 'sink'`},
 		{`CREATE CHANGEFEED FOR TABLE foo INTO 'sink' WITH bar = 'baz'`},
+		{`CREATE CHANGEFEED AS SELECT * FROM foo`},
+		{`CREATE CHANGEFEED INTO 'sink' AS SELECT a, b FROM db.foo WHERE a > 1`},
+		{`CREATE CHANGEFEED INTO 'sink' WITH updated, bar = 'baz' AS SELECT a, b + 1 AS c FROM foo WHERE (a > 1) AND (b IS NOT NULL)`},
 
 		// Regression for #15926
 		{`SELECT * FROM ((t1 NATURAL JOIN t2 WITH ORDINALITY AS o1)) WITH ORDINALITY AS o2`},
@@ -1145,6 +1203,7 @@ func TestParse2(t *testing.T) {
 		{`CREATE TABLE a (UNIQUE INDEX (b) PARTITION BY LIST (c) (PARTITION d VALUES IN (1)))`,
 			`CREATE TABLE a (UNIQUE (b) PARTITION BY LIST (c) (PARTITION d VALUES IN (1)))`},
 		{`CREATE INDEX ON a (b) COVERING (c)`, `CREATE INDEX ON a (b) STORING (c)`},
+		{`CREATE INDEX ON a ((lower(b)))`, `CREATE INDEX ON a (lower(b))`},
 
 		{`CREATE INDEX a ON b USING GIN (c)`,
 			`CREATE INVERTED INDEX a ON b (c)`},
@@ -1298,6 +1357,12 @@ func TestParse2(t *testing.T) {
 		// We allow OFFSET before LIMIT, but always output LIMIT first.
 		{`SELECT a FROM t OFFSET a LIMIT b`,
 			`SELECT a FROM t LIMIT b OFFSET a`},
+		// The locking clause may come before or after LIMIT, but is always output
+		// after it.
+		{`SELECT a FROM t FOR UPDATE LIMIT 1`,
+			`SELECT a FROM t LIMIT 1 FOR UPDATE`},
+		{`SELECT a FROM t FOR READ ONLY`,
+			`SELECT a FROM t`},
 		// FETCH FIRST ... is alternative syntax for LIMIT.
 		{`SELECT a FROM t FETCH FIRST 3 ROWS ONLY`,
 			`SELECT a FROM t LIMIT 3`},
@@ -1447,6 +1512,10 @@ func TestParse2(t *testing.T) {
 			`SELECT a FROM ROWS FROM (generate_series(1, 32)) AS s (x)`},
 		{`SELECT a FROM generate_series(1, 32) WITH ORDINALITY AS s (x)`,
 			`SELECT a FROM ROWS FROM (generate_series(1, 32)) WITH ORDINALITY AS s (x)`},
+		{`SELECT * FROM ab, LATERAL foo(a)`,
+			`SELECT * FROM ab, LATERAL ROWS FROM (foo(a))`},
+		{`SELECT * FROM ab, LATERAL generate_series(1, a) WITH ORDINALITY AS s (x)`,
+			`SELECT * FROM ab, LATERAL ROWS FROM (generate_series(1, a)) WITH ORDINALITY AS s (x)`},
 
 		// Tuples
 		{`SELECT 1 IN (b)`, `SELECT 1 IN (b,)`},
@@ -1618,6 +1687,9 @@ func TestParse2(t *testing.T) {
 
 		{`CREATE CHANGEFEED FOR foo INTO 'sink'`, `CREATE CHANGEFEED FOR TABLE foo INTO 'sink'`},
 
+		{`CREATE SCHEDULE FOR BACKUP foo INTO 'bar' RECURRING '@hourly'`,
+			`CREATE SCHEDULE FOR BACKUP TABLE foo INTO 'bar' RECURRING '@hourly'`},
+
 		{`GRANT SELECT ON foo TO root`,
 			`GRANT SELECT ON TABLE foo TO root`},
 		{`GRANT SELECT, DELETE, UPDATE ON foo, db.foo TO root, bar`,
@@ -1928,11 +2000,6 @@ HINT: try \h SELECT`},
 SELECT 1e-
        ^
 HINT: try \h SELECT`},
-		{"SELECT foo''",
-			`type does not exist at or near ""
-SELECT foo''
-          ^
-`},
 		{
 			`SELECT 0x FROM t`,
 			`invalid hexadecimal numeric literal
@@ -2009,20 +2076,6 @@ HINT: try \h INSERT`,
 ALTER TABLE t RENAME COLUMN x TO family
                                  ^
 HINT: try \h ALTER TABLE`,
-		},
-		{
-			`SELECT CAST(1.2+2.3 AS notatype)`,
-			`type does not exist at or near "notatype"
-SELECT CAST(1.2+2.3 AS notatype)
-                       ^
-`,
-		},
-		{
-			`SELECT ANNOTATE_TYPE(1.2+2.3, notatype)`,
-			`type does not exist at or near "notatype"
-SELECT ANNOTATE_TYPE(1.2+2.3, notatype)
-                              ^
-`,
 		},
 		{
 			`CREATE USER foo WITH PASSWORD`,
@@ -2085,13 +2138,6 @@ SELECT EXISTS(SELECT 1)[1]
 			`+ ANY <array> is invalid because "+" is not a boolean operator at or near "EOF"
 SELECT 1 + ANY ARRAY[1, 2, 3]
                              ^
-`,
-		},
-		{
-			`SELECT 'f'::"blah"`,
-			`type does not exist at or near "blah"
-SELECT 'f'::"blah"
-            ^
 `,
 		},
 		{
@@ -2121,20 +2167,6 @@ HINT: See: https://github.com/cockroachdb/cockroach/issues/8318`,
 UPDATE foo SET a.b = 1
                  ^
 HINT: See: https://github.com/cockroachdb/cockroach/issues/8318`,
-		},
-		{
-			`SELECT * FROM ab, LATERAL (SELECT * FROM kv)`,
-			`unimplemented at or near "EOF"
-SELECT * FROM ab, LATERAL (SELECT * FROM kv)
-                                            ^
-HINT: See: https://github.com/cockroachdb/cockroach/issues/24560`,
-		},
-		{
-			`SELECT * FROM ab, LATERAL foo(a)`,
-			`unimplemented at or near "EOF"
-SELECT * FROM ab, LATERAL foo(a)
-                                ^
-HINT: See: https://github.com/cockroachdb/cockroach/issues/24560`,
 		},
 		// Ensure that the support for ON ROLE <namelist> doesn't leak
 		// where it should not be recognized.
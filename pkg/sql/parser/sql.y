@@ -305,6 +305,18 @@ func (u *sqlSymUnion) onConflict() *tree.OnConflict {
 func (u *sqlSymUnion) orderBy() tree.OrderBy {
     return u.val.(tree.OrderBy)
 }
+func (u *sqlSymUnion) lockingClause() tree.LockingClause {
+    return u.val.(tree.LockingClause)
+}
+func (u *sqlSymUnion) lockingItem() *tree.LockingItem {
+    return u.val.(*tree.LockingItem)
+}
+func (u *sqlSymUnion) lockingStrength() tree.LockingStrength {
+    return u.val.(tree.LockingStrength)
+}
+func (u *sqlSymUnion) lockingWaitPolicy() tree.LockingWaitPolicy {
+    return u.val.(tree.LockingWaitPolicy)
+}
 func (u *sqlSymUnion) order() *tree.Order {
     return u.val.(*tree.Order)
 }
@@ -438,6 +450,9 @@ func (u *sqlSymUnion) newNormalizableTableNameFromUnresolvedName() *tree.Normali
 func (u *sqlSymUnion) resolvableFuncRefFromName() tree.ResolvableFunctionReference {
     return tree.ResolvableFunctionReference{FunctionReference: u.unresolvedName()}
 }
+func (u *sqlSymUnion) alterTypeAddValuePlacement() *tree.AlterTypeAddValuePlacement {
+    return u.val.(*tree.AlterTypeAddValuePlacement)
+}
 func (u *sqlSymUnion) rowsFromExpr() *tree.RowsFromExpr {
     return u.val.(*tree.RowsFromExpr)
 }
@@ -463,11 +478,11 @@ func newNameFromStr(s string) *tree.Name {
 // below; search this file for "Keyword category lists".
 
 // Ordinary key words in alphabetical order.
-%token <str> ABORT ACTION ADD ADMIN
+%token <str> ABORT ACTION ADD ADMIN AFTER
 %token <str> ALL ALTER ANALYSE ANALYZE AND ANY ANNOTATE_TYPE ARRAY AS ASC
 %token <str> ASYMMETRIC AT
 
-%token <str> BACKUP BEGIN BETWEEN BIGINT BIGSERIAL BIT
+%token <str> BACKUP BEFORE BEGIN BETWEEN BIGINT BIGSERIAL BIT
 %token <str> BLOB BOOL BOOLEAN BOTH BTREE BY BYTEA BYTES
 
 %token <str> CACHE CANCEL CASCADE CASE CAST CHANGEFEED CHAR
@@ -507,14 +522,14 @@ func newNameFromStr(s string) *tree.Name {
 
 %token <str> KEY KEYS KV
 
-%token <str> LATERAL LC_CTYPE LC_COLLATE
-%token <str> LEADING LEASE LEAST LEFT LESS LEVEL LIKE LIMIT LIST LOCAL
+%token <str> LATEST LATERAL LC_CTYPE LC_COLLATE
+%token <str> LEADING LEASE LEAST LEFT LESS LEVEL LIKE LIMIT LIST LOCAL LOCKED
 %token <str> LOCALTIME LOCALTIMESTAMP LOW LSHIFT
 
 %token <str> MATCH MINVALUE MAXVALUE MINUTE MONTH
 
 %token <str> NAN NAME NAMES NATURAL NEXT NO NO_INDEX_JOIN NORMAL
-%token <str> NOT NOTHING NOTNULL NULL NULLIF NUMERIC
+%token <str> NOT NOTHING NOTNULL NOWAIT NULL NULLIF NUMERIC
 
 %token <str> OF OFF OFFSET OID OIDVECTOR ON ONLY OPTION OPTIONS OR
 %token <str> ORDER ORDINALITY OUT OUTER OVER OVERLAPS OVERLAY OWNED
@@ -524,16 +539,16 @@ func newNameFromStr(s string) *tree.Name {
 
 %token <str> QUERIES QUERY
 
-%token <str> RANGE RANGES READ REAL RECURSIVE REF REFERENCES
+%token <str> RANGE RANGES READ REAL RECURRING RECURSIVE REF REFERENCES
 %token <str> REGCLASS REGPROC REGPROCEDURE REGNAMESPACE REGTYPE
 %token <str> REMOVE_PATH RENAME REPEATABLE
 %token <str> RELEASE RESET RESTORE RESTRICT RESUME RETURNING REVOKE RIGHT
 %token <str> ROLE ROLES ROLLBACK ROLLUP ROW ROWS RSHIFT
 
-%token <str> SAVEPOINT SCATTER SCHEMA SCHEMAS SCRUB SEARCH SECOND SELECT SEQUENCE SEQUENCES
+%token <str> SAVEPOINT SCATTER SCHEDULE SCHEDULES SCHEMA SCHEMAS SCRUB SEARCH SECOND SELECT SEQUENCE SEQUENCES
 %token <str> SERIAL SERIAL2 SERIAL4 SERIAL8
 %token <str> SERIALIZABLE SESSION SESSIONS SESSION_USER SET SETTING SETTINGS
-%token <str> SHOW SIMILAR SIMPLE SMALLINT SMALLSERIAL SNAPSHOT SOME SPLIT SQL
+%token <str> SHARE SHOW SIMILAR SIMPLE SKIP SMALLINT SMALLSERIAL SNAPSHOT SOME SPLIT SQL
 
 %token <str> START STATISTICS STATUS STDIN STRICT STRING STORE STORED STORING SUBSTRING
 %token <str> SYMMETRIC SYNTAX SYSTEM
@@ -581,6 +596,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Statement> alter_index_stmt
 %type <tree.Statement> alter_view_stmt
 %type <tree.Statement> alter_sequence_stmt
+%type <tree.Statement> alter_type_stmt
 %type <tree.Statement> alter_database_stmt
 %type <tree.Statement> alter_user_stmt
 %type <tree.Statement> alter_range_stmt
@@ -642,6 +658,7 @@ func newNameFromStr(s string) *tree.Name {
 
 %type <tree.Statement> create_stmt
 %type <tree.Statement> create_changefeed_stmt
+%type <tree.Statement> create_schedule_for_backup_stmt
 %type <tree.Statement> create_ddl_stmt
 %type <tree.Statement> create_database_stmt
 %type <tree.Statement> create_index_stmt
@@ -698,6 +715,7 @@ func newNameFromStr(s string) *tree.Name {
 
 %type <tree.Statement> show_stmt
 %type <tree.Statement> show_backup_stmt
+%type <tree.Statement> show_schedules_stmt
 %type <tree.Statement> show_columns_stmt
 %type <tree.Statement> show_constraints_stmt
 %type <tree.Statement> show_create_stmt
@@ -812,7 +830,12 @@ func newNameFromStr(s string) *tree.Name {
 %type <*tree.UpdateExpr> set_clause multiple_set_clause
 %type <tree.ArraySubscripts> array_subscripts
 %type <tree.GroupBy> group_clause
-%type <*tree.Limit> select_limit
+%type <*tree.Limit> select_limit opt_select_limit
+%type <tree.LockingClause> for_locking_clause opt_for_locking_clause for_locking_items
+%type <*tree.LockingItem> for_locking_item
+%type <tree.LockingStrength> for_locking_strength
+%type <tree.LockingWaitPolicy> opt_nowait_or_skip
+%type <tree.NormalizableTableNames> opt_locked_rels
 %type <tree.NormalizableTableNames> relation_expr_list
 %type <tree.ReturningClause> returning_clause
 
@@ -886,9 +909,12 @@ func newNameFromStr(s string) *tree.Name {
 %type <*tree.UpdateExpr> single_set_clause
 %type <tree.AsOfClause> as_of_clause opt_as_of_clause
 %type <tree.Expr> opt_changefeed_sink
+%type <tree.Expr> opt_schedule_label opt_full_backup_clause
 
 %type <str> explain_option_name
 %type <[]string> explain_option_list
+%type <[]string> enum_val_list opt_enum_val_list
+%type <*tree.AlterTypeAddValuePlacement> opt_add_val_placement
 
 %type <coltypes.T> typename simple_typename const_typename
 %type <coltypes.T> numeric opt_numeric_modifiers
@@ -1094,7 +1120,8 @@ stmt:
 
 // %Help: ALTER
 // %Category: Group
-// %Text: ALTER TABLE, ALTER INDEX, ALTER VIEW, ALTER SEQUENCE, ALTER DATABASE, ALTER USER
+// %Text: ALTER TABLE, ALTER INDEX, ALTER VIEW, ALTER SEQUENCE, ALTER DATABASE, ALTER USER,
+// ALTER TYPE
 alter_stmt:
   alter_ddl_stmt      // help texts in sub-rule
 | alter_user_stmt     // EXTEND WITH HELP: ALTER USER
@@ -1107,6 +1134,7 @@ alter_ddl_stmt:
 | alter_sequence_stmt // EXTEND WITH HELP: ALTER SEQUENCE
 | alter_database_stmt // EXTEND WITH HELP: ALTER DATABASE
 | alter_range_stmt    // EXTEND WITH HELP: ALTER RANGE
+| alter_type_stmt     // EXTEND WITH HELP: ALTER TYPE
 
 // %Help: ALTER TABLE - change the definition of a table
 // %Category: DDL
@@ -1195,6 +1223,45 @@ alter_sequence_options_stmt:
     $$.val = &tree.AlterSequence{Name: $5.normalizableTableNameFromUnresolvedName(), Options: $6.seqOpts(), IfExists: true}
   }
 
+// %Help: ALTER TYPE - change the definition of a type
+// %Category: DDL
+// %Text:
+// ALTER TYPE <typename> ADD VALUE [IF NOT EXISTS] <label> [{BEFORE | AFTER} <label>]
+// %SeeAlso: CREATE TYPE
+alter_type_stmt:
+  ALTER TYPE type_name ADD VALUE SCONST opt_add_val_placement
+  {
+    $$.val = &tree.AlterTypeAddValue{
+      Name: $3.normalizableTableNameFromUnresolvedName(),
+      NewLabel: $6,
+      Placement: $7.alterTypeAddValuePlacement(),
+    }
+  }
+| ALTER TYPE type_name ADD VALUE IF NOT EXISTS SCONST opt_add_val_placement
+  {
+    $$.val = &tree.AlterTypeAddValue{
+      Name: $3.normalizableTableNameFromUnresolvedName(),
+      IfNotExists: true,
+      NewLabel: $9,
+      Placement: $10.alterTypeAddValuePlacement(),
+    }
+  }
+| ALTER TYPE error // SHOW HELP: ALTER TYPE
+
+opt_add_val_placement:
+  BEFORE SCONST
+  {
+    $$.val = &tree.AlterTypeAddValuePlacement{Before: true, ExistingLabel: $2}
+  }
+| AFTER SCONST
+  {
+    $$.val = &tree.AlterTypeAddValuePlacement{Before: false, ExistingLabel: $2}
+  }
+| /* EMPTY */
+  {
+    $$.val = (*tree.AlterTypeAddValuePlacement)(nil)
+  }
+
 // %Help: ALTER USER - change user properties
 // %Category: Priv
 // %Text:
@@ -1636,6 +1703,8 @@ opt_validate_behavior:
 // Options:
 //    INTO_DB
 //    SKIP_MISSING_FOREIGN_KEYS
+//    ENCRYPTION_PASSPHRASE = '...'
+//    ENCRYPTION_KEY_FILE = '<location>'
 //
 // %SeeAlso: RESTORE, WEBDOCS/backup.html
 backup_stmt:
@@ -1651,6 +1720,9 @@ backup_stmt:
 // RESTORE <targets...> FROM <location...>
 //         [ AS OF SYSTEM TIME <expr> ]
 //         [ WITH <option> [= <value>] [, ...] ]
+// RESTORE <targets...> FROM LATEST IN <collection>
+//         [ AS OF SYSTEM TIME <expr> ]
+//         [ WITH <option> [= <value>] [, ...] ]
 //
 // Targets:
 //    TABLE <pattern> [, ...]
@@ -1659,9 +1731,14 @@ backup_stmt:
 // Locations:
 //    "[scheme]://[host]/[path to backup]?[parameters]"
 //
+// Collection:
+//    "[scheme]://[host]/[path to collection of backups]?[parameters]"
+//
 // Options:
 //    INTO_DB
 //    SKIP_MISSING_FOREIGN_KEYS
+//    ENCRYPTION_PASSPHRASE = '...'
+//    ENCRYPTION_KEY_FILE = '<location>'
 //
 // %SeeAlso: BACKUP, WEBDOCS/restore.html
 restore_stmt:
@@ -1673,6 +1750,14 @@ restore_stmt:
   {
     $$.val = &tree.Restore{Targets: $2.targetList(), From: $4.exprs(), AsOf: $5.asOfClause(), Options: $6.kvOptions()}
   }
+| RESTORE targets FROM LATEST IN string_or_placeholder opt_with_options
+  {
+    $$.val = &tree.Restore{Targets: $2.targetList(), From: tree.Exprs{$6.expr()}, Latest: true, Options: $7.kvOptions()}
+  }
+| RESTORE targets FROM LATEST IN string_or_placeholder as_of_clause opt_with_options
+  {
+    $$.val = &tree.Restore{Targets: $2.targetList(), From: tree.Exprs{$6.expr()}, Latest: true, AsOf: $7.asOfClause(), Options: $8.kvOptions()}
+  }
 | RESTORE error // SHOW HELP: RESTORE
 
 import_format:
@@ -1694,12 +1779,19 @@ import_format:
 //        DATA ( <datafile> [, ...] )
 //        [ WITH <option> [= <value>] [, ...] ]
 //
+// IMPORT INTO <tablename> [ ( <columns> ) ]
+//        <format>
+//        DATA ( <datafile> [, ...] )
+//        [ WITH <option> [= <value>] [, ...] ]
+//
 // Formats:
 //    CSV
 //    MYSQLOUTFILE
 //    MYSQLDUMP (mysqldump's SQL output)
 //    PGCOPY
 //    PGDUMP
+//    AVRO (object container files)
+//    JSONLINES (newline-delimited JSON objects)
 //
 // Options:
 //    distributed = '...'
@@ -1708,6 +1800,7 @@ import_format:
 //    delimiter = '...'      [CSV, PGCOPY-specific]
 //    nullif = '...'         [CSV, PGCOPY-specific]
 //    comment = '...'        [CSV-specific]
+//    strict_mode            [AVRO, JSONLINES-specific]
 //
 // %SeeAlso: CREATE TABLE
 import_stmt:
@@ -1735,6 +1828,14 @@ import_stmt:
   {
     $$.val = &tree.Import{Table: $3.normalizableTableNameFromUnresolvedName(), CreateDefs: $5.tblDefs(), FileFormat: $7, Files: $10.exprs(), Options: $12.kvOptions()}
   }
+| IMPORT INTO table_name '(' insert_column_list ')' import_format DATA '(' string_or_placeholder_list ')' opt_with_options
+  {
+    $$.val = &tree.Import{Table: $3.normalizableTableNameFromUnresolvedName(), Into: true, IntoCols: $5.nameList(), FileFormat: $7, Files: $10.exprs(), Options: $12.kvOptions()}
+  }
+| IMPORT INTO table_name import_format DATA '(' string_or_placeholder_list ')' opt_with_options
+  {
+    $$.val = &tree.Import{Table: $3.normalizableTableNameFromUnresolvedName(), Into: true, FileFormat: $4, Files: $7.exprs(), Options: $9.kvOptions()}
+  }
 | IMPORT error // SHOW HELP: IMPORT
 
 
@@ -1745,9 +1846,14 @@ import_stmt:
 //
 // Formats:
 //    CSV
+//    PARQUET
+//    AVRO
 //
 // Options:
 //    delimiter = '...'   [CSV-specific]
+//    nullas = '...'      [CSV-specific]
+//    chunk_rows = '...'
+//    compression = 'gzip' | 'snappy' | 'none'   [snappy is PARQUET and AVRO only]
 //
 // %SeeAlso: SELECT
 export_stmt:
@@ -1964,12 +2070,13 @@ comment_text:
 // %Text:
 // CREATE DATABASE, CREATE TABLE, CREATE INDEX, CREATE TABLE AS,
 // CREATE USER, CREATE VIEW, CREATE SEQUENCE, CREATE STATISTICS,
-// CREATE ROLE
+// CREATE ROLE, CREATE TYPE
 create_stmt:
   create_user_stmt     // EXTEND WITH HELP: CREATE USER
 | create_role_stmt     // EXTEND WITH HELP: CREATE ROLE
 | create_ddl_stmt      // help texts in sub-rule
 | create_stats_stmt    // EXTEND WITH HELP: CREATE STATISTICS
+| create_schedule_for_backup_stmt // EXTEND WITH HELP: CREATE SCHEDULE FOR BACKUP
 | CREATE error         // SHOW HELP: CREATE
 
 create_ddl_stmt:
@@ -1980,7 +2087,7 @@ create_ddl_stmt:
 | create_table_as_stmt // EXTEND WITH HELP: CREATE TABLE
 // Error case for both CREATE TABLE and CREATE TABLE ... AS in one
 | CREATE TABLE error   // SHOW HELP: CREATE TABLE
-| create_type_stmt     { /* SKIP DOC */ }
+| create_type_stmt     // EXTEND WITH HELP: CREATE TYPE
 | create_view_stmt     // EXTEND WITH HELP: CREATE VIEW
 | create_sequence_stmt // EXTEND WITH HELP: CREATE SEQUENCE
 
@@ -2011,6 +2118,77 @@ create_changefeed_stmt:
       Options: $6.kvOptions(),
     }
   }
+| CREATE CHANGEFEED opt_changefeed_sink opt_with_options AS SELECT target_list FROM table_name where_clause
+  {
+    $$.val = &tree.CreateChangefeed{
+      Targets: tree.TargetList{Tables: tree.TablePatterns{$9.unresolvedName()}},
+      SinkURI: $3.expr(),
+      Options: $4.kvOptions(),
+      Select: &tree.SelectClause{
+        Exprs: $7.selExprs(),
+        From: &tree.From{Tables: tree.TableExprs{
+          &tree.AliasedTableExpr{Expr: $9.newNormalizableTableNameFromUnresolvedName()},
+        }},
+        Where: tree.NewWhere(tree.AstWhere, $10.expr()),
+      },
+    }
+  }
+
+// %Help: CREATE SCHEDULE FOR BACKUP - create a schedule of recurring backups
+// %Category: CCL
+// %Text:
+// CREATE SCHEDULE [<label>]
+// FOR BACKUP <targets...> INTO <location>
+// [ WITH <option> [= <value>] [, ...] ]
+// RECURRING <cron expression>
+// [ FULL BACKUP <cron expression> ]
+//
+// Targets:
+//    TABLE <pattern> [, ...]
+//    DATABASE <databasename> [, ...]
+//
+// Location:
+//    "[scheme]://[host]/[path to collection]?[parameters]"
+//
+// Each backup is taken into a new directory of the collection. Backups are
+// incremental from the latest full backup, taken on the FULL BACKUP
+// schedule, and the incremental backups since. Without FULL BACKUP, every
+// backup is a full backup.
+//
+// %SeeAlso: BACKUP, SHOW SCHEDULES
+create_schedule_for_backup_stmt:
+  CREATE SCHEDULE opt_schedule_label FOR BACKUP targets INTO string_or_placeholder opt_with_options RECURRING string_or_placeholder opt_full_backup_clause
+  {
+    $$.val = &tree.ScheduledBackup{
+      ScheduleName:  $3.expr(),
+      Targets:       $6.targetList(),
+      To:            $8.expr(),
+      BackupOptions: $9.kvOptions(),
+      Recurrence:    $11.expr(),
+      FullBackup:    $12.expr(),
+    }
+  }
+| CREATE SCHEDULE error // SHOW HELP: CREATE SCHEDULE FOR BACKUP
+
+opt_schedule_label:
+  string_or_placeholder
+  {
+    $$.val = $1.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = nil
+  }
+
+opt_full_backup_clause:
+  FULL BACKUP string_or_placeholder
+  {
+    $$.val = $3.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = nil
+  }
 
 changefeed_targets:
   single_table_pattern_list
@@ -2881,6 +3059,7 @@ show_stmt:
 | show_queries_stmt         // EXTEND WITH HELP: SHOW QUERIES
 | show_ranges_stmt          // EXTEND WITH HELP: SHOW RANGES
 | show_roles_stmt           // EXTEND WITH HELP: SHOW ROLES
+| show_schedules_stmt       // EXTEND WITH HELP: SHOW SCHEDULES
 | show_schemas_stmt         // EXTEND WITH HELP: SHOW SCHEMAS
 | show_session_stmt         // EXTEND WITH HELP: SHOW SESSION
 | show_sessions_stmt        // EXTEND WITH HELP: SHOW SESSIONS
@@ -2962,30 +3141,33 @@ show_histogram_stmt:
 
 // %Help: SHOW BACKUP - list backup contents
 // %Category: CCL
-// %Text: SHOW BACKUP [FILES|RANGES] <location>
+// %Text: SHOW BACKUP [FILES|RANGES] <location> [WITH <option> [= <value>] [, ...]]
 // %SeeAlso: WEBDOCS/show-backup.html
 show_backup_stmt:
-  SHOW BACKUP string_or_placeholder
+  SHOW BACKUP string_or_placeholder opt_with_options
   {
     $$.val = &tree.ShowBackup{
       Details: tree.BackupDefaultDetails,
       Path:    $3.expr(),
+      Options: $4.kvOptions(),
     }
   }
-| SHOW BACKUP RANGES string_or_placeholder
+| SHOW BACKUP RANGES string_or_placeholder opt_with_options
   {
     /* SKIP DOC */
     $$.val = &tree.ShowBackup{
       Details: tree.BackupRangeDetails,
       Path:    $4.expr(),
+      Options: $5.kvOptions(),
     }
   }
-| SHOW BACKUP FILES string_or_placeholder
+| SHOW BACKUP FILES string_or_placeholder opt_with_options
   {
     /* SKIP DOC */
     $$.val = &tree.ShowBackup{
       Details: tree.BackupFileDetails,
       Path:    $4.expr(),
+      Options: $5.kvOptions(),
     }
   }
 | SHOW BACKUP error // SHOW HELP: SHOW BACKUP
@@ -3122,6 +3304,17 @@ show_jobs_stmt:
   }
 | SHOW JOBS error // SHOW HELP: SHOW JOBS
 
+// %Help: SHOW SCHEDULES - list schedules of recurring jobs
+// %Category: Misc
+// %Text: SHOW SCHEDULES
+// %SeeAlso: CREATE SCHEDULE FOR BACKUP, SHOW JOBS
+show_schedules_stmt:
+  SHOW SCHEDULES
+  {
+    $$.val = &tree.ShowSchedules{}
+  }
+| SHOW SCHEDULES error // SHOW HELP: SHOW SCHEDULES
+
 // %Help: SHOW TRACE - display an execution trace
 // %Category: Misc
 // %Text:
@@ -3909,11 +4102,11 @@ col_qualification_elem:
  }
 | AS '(' a_expr ')' VIRTUAL
  {
-    return unimplemented(sqllex, "virtual computed columns")
+    $$.val = &tree.ColumnComputedDef{Expr: $3.expr(), Virtual: true}
  }
 | AS error
  {
-    sqllex.Error("syntax error: use AS ( <expr> ) STORED")
+    sqllex.Error("syntax error: use AS ( <expr> ) STORED or AS ( <expr> ) VIRTUAL")
     return 1
  }
 
@@ -4260,13 +4453,24 @@ create_view_stmt:
 
 // TODO(a-robinson): CREATE OR REPLACE VIEW support (#2971).
 
-// CREATE TYPE/DOMAIN is not yet supported by CockroachDB but we
-// want to report it with the right issue number.
+// %Help: CREATE TYPE - create a new type
+// %Category: DDL
+// %Text: CREATE TYPE <typename> AS ENUM ( [<label> [, ...]] )
+// %SeeAlso: ALTER TYPE
 create_type_stmt:
-  // Record/Composite types.
-  CREATE TYPE type_name AS '(' error      { return unimplementedWithIssue(sqllex, 27792) }
   // Enum types.
-| CREATE TYPE type_name AS ENUM '(' error { return unimplementedWithIssue(sqllex, 24873) }
+  CREATE TYPE type_name AS ENUM '(' opt_enum_val_list ')'
+  {
+    $$.val = &tree.CreateType{
+      Name: $3.normalizableTableNameFromUnresolvedName(),
+      EnumLabels: $7.strs(),
+    }
+  }
+| CREATE TYPE error // SHOW HELP: CREATE TYPE
+// Other types are not yet supported by CockroachDB but we want to
+// report them with the right issue number.
+  // Record/Composite types.
+| CREATE TYPE type_name AS '(' error      { return unimplementedWithIssue(sqllex, 27792) }
   // Range types.
 | CREATE TYPE type_name AS RANGE error    { return unimplementedWithIssue(sqllex, 27791) }
   // Base (primitive) types.
@@ -4276,6 +4480,23 @@ create_type_stmt:
   // Domain types.
 | CREATE DOMAIN type_name error           { return unimplementedWithIssue(sqllex, 27796) }
 
+opt_enum_val_list:
+  enum_val_list
+| /* EMPTY */
+  {
+    $$.val = []string(nil)
+  }
+
+enum_val_list:
+  SCONST
+  {
+    $$.val = []string{$1}
+  }
+| enum_val_list ',' SCONST
+  {
+    $$.val = append($1.strs(), $3)
+  }
+
 // %Help: CREATE INDEX - create a new index
 // %Category: DDL
 // %Text:
@@ -4388,8 +4609,14 @@ index_elem:
   {
     $$.val = tree.IndexElem{Column: tree.Name($1), Direction: $3.dir()}
   }
-| func_expr_windowless opt_collate_unimpl opt_asc_desc { return unimplemented(sqllex, "index_elem func expr (computed indexes)") }
-| '(' a_expr ')' opt_collate_unimpl opt_asc_desc { return unimplemented(sqllex, "index_elem a_expr (computed indexes)") }
+| func_expr_windowless opt_collate_unimpl opt_asc_desc
+  {
+    $$.val = tree.IndexElem{Expr: $1.expr(), Direction: $3.dir()}
+  }
+| '(' a_expr ')' opt_collate_unimpl opt_asc_desc
+  {
+    $$.val = tree.IndexElem{Expr: $2.expr(), Direction: $5.dir()}
+  }
 
 opt_collate:
   COLLATE collation_name { $$ = $2 }
@@ -5050,9 +5277,13 @@ select_no_parens:
   {
     $$.val = &tree.Select{Select: $1.selectStmt(), OrderBy: $2.orderBy()}
   }
-| select_clause opt_sort_clause select_limit
+| select_clause opt_sort_clause for_locking_clause opt_select_limit
   {
-    $$.val = &tree.Select{Select: $1.selectStmt(), OrderBy: $2.orderBy(), Limit: $3.limit()}
+    $$.val = &tree.Select{Select: $1.selectStmt(), OrderBy: $2.orderBy(), Limit: $4.limit(), Locking: $3.lockingClause()}
+  }
+| select_clause opt_sort_clause select_limit opt_for_locking_clause
+  {
+    $$.val = &tree.Select{Select: $1.selectStmt(), OrderBy: $2.orderBy(), Limit: $3.limit(), Locking: $4.lockingClause()}
   }
 | with_clause select_clause
   {
@@ -5062,9 +5293,89 @@ select_no_parens:
   {
     $$.val = &tree.Select{With: $1.with(), Select: $2.selectStmt(), OrderBy: $3.orderBy()}
   }
-| with_clause select_clause opt_sort_clause select_limit
+| with_clause select_clause opt_sort_clause for_locking_clause opt_select_limit
+  {
+    $$.val = &tree.Select{With: $1.with(), Select: $2.selectStmt(), OrderBy: $3.orderBy(), Limit: $5.limit(), Locking: $4.lockingClause()}
+  }
+| with_clause select_clause opt_sort_clause select_limit opt_for_locking_clause
+  {
+    $$.val = &tree.Select{With: $1.with(), Select: $2.selectStmt(), OrderBy: $3.orderBy(), Limit: $4.limit(), Locking: $5.lockingClause()}
+  }
+
+for_locking_clause:
+  for_locking_items
+| FOR READ ONLY
+  {
+    $$.val = tree.LockingClause(nil)
+  }
+
+opt_for_locking_clause:
+  for_locking_clause
+| /* EMPTY */
+  {
+    $$.val = tree.LockingClause(nil)
+  }
+
+for_locking_items:
+  for_locking_item
+  {
+    $$.val = tree.LockingClause{$1.lockingItem()}
+  }
+| for_locking_items for_locking_item
+  {
+    $$.val = append($1.lockingClause(), $2.lockingItem())
+  }
+
+for_locking_item:
+  for_locking_strength opt_locked_rels opt_nowait_or_skip
+  {
+    $$.val = &tree.LockingItem{
+      Strength:   $1.lockingStrength(),
+      Targets:    $2.normalizableTableNames(),
+      WaitPolicy: $3.lockingWaitPolicy(),
+    }
+  }
+
+for_locking_strength:
+  FOR UPDATE
+  {
+    $$.val = tree.ForUpdate
+  }
+| FOR NO KEY UPDATE
+  {
+    $$.val = tree.ForNoKeyUpdate
+  }
+| FOR SHARE
+  {
+    $$.val = tree.ForShare
+  }
+| FOR KEY SHARE
   {
-    $$.val = &tree.Select{With: $1.with(), Select: $2.selectStmt(), OrderBy: $3.orderBy(), Limit: $4.limit()}
+    $$.val = tree.ForKeyShare
+  }
+
+opt_locked_rels:
+  /* EMPTY */
+  {
+    $$.val = tree.NormalizableTableNames{}
+  }
+| OF table_name_list
+  {
+    $$.val = $2.normalizableTableNames()
+  }
+
+opt_nowait_or_skip:
+  /* EMPTY */
+  {
+    $$.val = tree.LockWaitBlock
+  }
+| SKIP LOCKED
+  {
+    $$.val = tree.LockWaitSkip
+  }
+| NOWAIT
+  {
+    $$.val = tree.LockWaitError
   }
 
 select_clause:
@@ -5228,7 +5539,10 @@ with_clause:
     $$.val = &tree.With{CTEList: $2.ctes()}
   }
 | WITH_LA cte_list { return unimplemented(sqllex, "with cte_list") }
-| WITH RECURSIVE cte_list { return unimplemented(sqllex, "with recursive") }
+| WITH RECURSIVE cte_list
+  {
+    $$.val = &tree.With{Recursive: true, CTEList: $3.ctes()}
+  }
 
 cte_list:
   common_table_expr
@@ -5364,6 +5678,13 @@ select_limit:
 | limit_clause
 | offset_clause
 
+opt_select_limit:
+  select_limit
+| /* EMPTY */
+  {
+    $$.val = (*tree.Limit)(nil)
+  }
+
 opt_limit_clause:
   limit_clause
 | /* EMPTY */ { $$.val = (*tree.Limit)(nil) }
@@ -5617,7 +5938,15 @@ table_ref:
       As:         $3.aliasClause(),
     }
   }
-| LATERAL select_with_parens opt_ordinality opt_alias_clause { return unimplementedWithIssue(sqllex, 24560) }
+| LATERAL select_with_parens opt_ordinality opt_alias_clause
+  {
+    $$.val = &tree.AliasedTableExpr{
+      Expr:       &tree.Subquery{Select: $2.selectStmt()},
+      Ordinality: $3.bool(),
+      Lateral:    true,
+      As:         $4.aliasClause(),
+    }
+  }
 | joined_table
   {
     $$.val = $1.tblExpr()
@@ -5632,7 +5961,10 @@ table_ref:
     $$.val = &tree.AliasedTableExpr{Expr: f, Ordinality: $2.bool(), As: $3.aliasClause()}
   }
 | LATERAL func_table opt_ordinality opt_alias_clause
-  { return unimplementedWithIssue(sqllex, 24560) }
+  {
+    f := $2.tblExpr()
+    $$.val = &tree.AliasedTableExpr{Expr: f, Ordinality: $3.bool(), Lateral: true, As: $4.aliasClause()}
+  }
 // The following syntax is a CockroachDB extension:
 //     SELECT ... FROM [ EXPLAIN .... ] WHERE ...
 //     SELECT ... FROM [ SHOW .... ] WHERE ...
@@ -5934,6 +6266,12 @@ const_json:
 
 simple_typename:
   const_typename
+| IDENT '.' IDENT
+  {
+    // A user-defined type qualified with the name of its schema, as pg_dump
+    // writes them.
+    $$.val = &coltypes.TUserDefined{Schema: $1, Name: $3}
+  }
 | bit_with_length
 | character_with_length
 | const_interval opt_interval // TODO(pmattis): Support opt_interval?
@@ -6026,17 +6364,12 @@ const_typename:
     // See https://www.postgresql.org/docs/9.1/static/datatype-character.html
     // Postgres supports a special character type named "char" (with the quotes)
     // that is a single-character column type. It's used by system tables.
-    // Eventually this clause will be used to parse user-defined types as well,
-    // since their names can be quoted.
+    // Other identifiers are the names of predefined types which are not
+    // keywords, or of user-defined types, whose names can be quoted.
     if $1 == "char" {
       $$.val = coltypes.QChar
     } else {
-      var err error
-      $$.val, err = coltypes.TypeForNonKeywordTypeName($1)
-      if err != nil {
-        sqllex.Error(err.Error())
-        return 1
-      }
+      $$.val = coltypes.TypeForNonKeywordTypeName($1)
     }
   }
 
@@ -8223,9 +8556,11 @@ unreserved_keyword:
 | ACTION
 | ADD
 | ADMIN
+| AFTER
 | ALTER
 | AT
 | BACKUP
+| BEFORE
 | BEGIN
 | BIGSERIAL
 | BLOB
@@ -8312,6 +8647,7 @@ unreserved_keyword:
 | KEY
 | KEYS
 | KV
+| LATEST
 | LC_COLLATE
 | LC_CTYPE
 | LEASE
@@ -8319,6 +8655,7 @@ unreserved_keyword:
 | LEVEL
 | LIST
 | LOCAL
+| LOCKED
 | LOW
 | MATCH
 | MINUTE
@@ -8329,6 +8666,7 @@ unreserved_keyword:
 | NEXT
 | NO
 | NORMAL
+| NOWAIT
 | NO_INDEX_JOIN
 | OF
 | OFF
@@ -8354,6 +8692,7 @@ unreserved_keyword:
 | RANGE
 | RANGES
 | READ
+| RECURRING
 | RECURSIVE
 | REF
 | REGCLASS
@@ -8379,6 +8718,8 @@ unreserved_keyword:
 | STATUS
 | SAVEPOINT
 | SCATTER
+| SCHEDULE
+| SCHEDULES
 | SCHEMA
 | SCHEMAS
 | SCRUB
@@ -8394,8 +8735,10 @@ unreserved_keyword:
 | SESSION
 | SESSIONS
 | SET
+| SHARE
 | SHOW
 | SIMPLE
+| SKIP
 | SMALLSERIAL
 | SNAPSHOT
 | SQL
@@ -832,9 +832,23 @@ CREATE TABLE pg_catalog.pg_enum (
   enumlabel STRING
 );
 `,
-	populate: func(_ context.Context, p *planner, _ *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		// Enum types are not currently supported.
-		return nil
+	populate: func(ctx context.Context, p *planner, dbContext *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		h := makeOidHasher()
+		return forEachTypeDesc(ctx, p, dbContext, func(_ *DatabaseDescriptor, typ *sqlbase.TypeDescriptor) error {
+			enumTypeOid := typOid(typ.DatumType())
+			for i := range typ.EnumMembers {
+				member := &typ.EnumMembers[i]
+				if err := addRow(
+					h.EnumMemberOid(typ, member),                  // oid
+					enumTypeOid,                                   // enumtypid
+					tree.NewDFloat(tree.DFloat(i+1)),              // enumsortorder
+					tree.NewDString(member.LogicalRepresentation), // enumlabel
+				); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
 	},
 }
 
@@ -1750,7 +1764,6 @@ var (
 	// Avoid unused warning for constants.
 	_ = typTypeComposite
 	_ = typTypeDomain
-	_ = typTypeEnum
 	_ = typTypePseudo
 	_ = typTypeRange
 
@@ -1773,7 +1786,6 @@ var (
 
 	// Avoid unused warning for constants.
 	_ = typCategoryComposite
-	_ = typCategoryEnum
 	_ = typCategoryGeometric
 	_ = typCategoryRange
 	_ = typCategoryBitString
@@ -1821,7 +1833,7 @@ CREATE TABLE pg_catalog.pg_type (
 `,
 	populate: func(ctx context.Context, p *planner, dbContext *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
 		h := makeOidHasher()
-		return forEachDatabaseDesc(ctx, p, dbContext, func(db *DatabaseDescriptor) error {
+		if err := forEachDatabaseDesc(ctx, p, dbContext, func(db *DatabaseDescriptor) error {
 			nspOid := h.NamespaceOid(db, pgCatalogName)
 
 			for o, typ := range types.OidToType {
@@ -1895,6 +1907,49 @@ CREATE TABLE pg_catalog.pg_type (
 				}
 			}
 			return nil
+		}); err != nil {
+			return err
+		}
+
+		// User-defined types.
+		return forEachTypeDesc(ctx, p, dbContext, func(db *DatabaseDescriptor, typ *sqlbase.TypeDescriptor) error {
+			datumType := typ.DatumType()
+			return addRow(
+				typOid(datumType),                     // oid
+				tree.NewDName(typ.Name),               // typname
+				h.NamespaceOid(db, tree.PublicSchema), // typnamespace
+				tree.DNull,                            // typowner
+				typLen(datumType),                     // typlen
+				typByVal(datumType),                   // typbyval
+				typTypeEnum,                           // typtype
+				typCategory(datumType),                // typcategory
+				tree.DBoolFalse,                       // typispreferred
+				tree.DBoolTrue,                        // typisdefined
+				typDelim,                              // typdelim
+				oidZero,                               // typrelid
+				oidZero,                               // typelem
+				oidZero,                               // typarray
+
+				// regproc references
+				h.RegProc("enum_in"),   // typinput
+				h.RegProc("enum_out"),  // typoutput
+				h.RegProc("enum_recv"), // typreceive
+				h.RegProc("enum_send"), // typsend
+				oidZero,                // typmodin
+				oidZero,                // typmodout
+				oidZero,                // typanalyze
+
+				tree.DNull,      // typalign
+				tree.DNull,      // typstorage
+				tree.DBoolFalse, // typnotnull
+				oidZero,         // typbasetype
+				negOneVal,       // typtypmod
+				zeroVal,         // typndims
+				oidZero,         // typcollation
+				tree.DNull,      // typdefaultbin
+				tree.DNull,      // typdefault
+				tree.DNull,      // typacl
+			)
 		})
 	},
 }
@@ -2068,6 +2123,7 @@ var datumToTypeCategory = map[reflect.Type]*tree.DString{
 	reflect.TypeOf(types.FamTuple):    typCategoryPseudo,
 	reflect.TypeOf(types.Oid):         typCategoryNumeric,
 	reflect.TypeOf(types.UUID):        typCategoryUserDefined,
+	reflect.TypeOf(types.FamEnum):     typCategoryEnum,
 	reflect.TypeOf(types.INet):        typCategoryNetworkAddr,
 }
 
@@ -2191,6 +2247,7 @@ const (
 	userTypeTag
 	collationTypeTag
 	operatorTypeTag
+	enumMemberTypeTag
 )
 
 func (h oidHasher) writeTypeTag(tag oidTypeTag) {
@@ -2372,6 +2429,15 @@ func (h oidHasher) CollationOid(collation string) *tree.DOid {
 	return h.getOid()
 }
 
+func (h oidHasher) EnumMemberOid(
+	typ *sqlbase.TypeDescriptor, member *sqlbase.TypeDescriptor_EnumMember,
+) *tree.DOid {
+	h.writeTypeTag(enumMemberTypeTag)
+	h.writeUInt32(uint32(typ.ID))
+	h.writeStr(member.LogicalRepresentation)
+	return h.getOid()
+}
+
 func (h oidHasher) OperatorOid(name string, leftType, rightType, returnType *tree.DOid) *tree.DOid {
 	h.writeTypeTag(operatorTypeTag)
 	h.writeStr(name)
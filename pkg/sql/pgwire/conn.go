@@ -495,6 +495,11 @@ func (c *conn) handleParse(ctx context.Context, buf *pgwirebase.ReadBuffer) erro
 		if t == 0 {
 			continue
 		}
+		if _, ok := types.IsUserDefinedTypeOid(t); ok {
+			// User-defined types are resolved by name during type checking.
+			// The raw hint is kept to decode the argument.
+			continue
+		}
 		v, ok := types.OidToType[t]
 		if !ok {
 			err := pgwirebase.NewProtocolViolationErrorf("unknown oid type: %v", t)
@@ -789,6 +794,8 @@ func convertToErrWithPGCode(err error) error {
 		// different mechanism to marshal AmbiguousResultErrors from the executing
 		// nodes.
 		return sqlbase.NewStatementCompletionUnknownError(tErr)
+	case *roachpb.LockNotAvailableError:
+		return pgerror.NewError(pgerror.CodeLockNotAvailableError, tErr.Error())
 	default:
 		return err
 	}
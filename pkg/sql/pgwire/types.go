@@ -120,6 +120,9 @@ func (b *writeBuffer) writeTextDatum(
 	case *tree.DCollatedString:
 		b.writeLengthPrefixedString(v.Contents)
 
+	case *tree.DEnum:
+		b.writeLengthPrefixedString(v.Logical)
+
 	case *tree.DDate:
 		t := timeutil.Unix(int64(*v)*secondsInDay, 0)
 		// Start at offset 4 because `putInt32` clobbers the first 4 bytes.
@@ -340,6 +343,9 @@ func (b *writeBuffer) writeBinaryDatum(
 	case *tree.DCollatedString:
 		b.writeLengthPrefixedString(v.Contents)
 
+	case *tree.DEnum:
+		b.writeLengthPrefixedString(v.Logical)
+
 	case *tree.DTimestamp:
 		b.putInt32(8)
 		b.putInt64(timeToPgBinary(v.Time, nil))
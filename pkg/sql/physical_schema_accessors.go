@@ -89,8 +89,21 @@ func (a UncachedPhysicalAccessor) GetObjectNames(
 		return nil, err
 	}
 
+	// Types share the namespace of tables, but are not objects.
+	ids := make([]sqlbase.ID, len(sr))
+	for i := range sr {
+		ids[i] = sqlbase.ID(sr[i].ValueInt())
+	}
+	typs, err := getTypeDescsByID(flags.ctx, flags.txn, ids)
+	if err != nil {
+		return nil, err
+	}
+
 	var tableNames tree.TableNames
-	for _, row := range sr {
+	for i, row := range sr {
+		if _, ok := typs[ids[i]]; ok {
+			continue
+		}
 		_, tableName, err := encoding.DecodeUnsafeStringAscending(
 			bytes.TrimPrefix(row.Key, prefix), nil)
 		if err != nil {
@@ -141,6 +154,9 @@ func (a UncachedPhysicalAccessor) GetObjectDesc(
 				// We'll keep that despite the ADD state.
 				return desc, dbDesc, nil
 			}
+			if desc.Offline() && flags.required {
+				return nil, nil, err
+			}
 			// Bad state: the descriptor is essentially invisible.
 			desc = nil
 		}
@@ -161,11 +161,14 @@ type planNodeFastPath interface {
 var _ planNode = &alterIndexNode{}
 var _ planNode = &alterSequenceNode{}
 var _ planNode = &alterTableNode{}
+var _ planNode = &alterTypeNode{}
+var _ planNode = &applyJoinNode{}
 var _ planNode = &createDatabaseNode{}
 var _ planNode = &createIndexNode{}
 var _ planNode = &createSequenceNode{}
 var _ planNode = &createStatsNode{}
 var _ planNode = &createTableNode{}
+var _ planNode = &createTypeNode{}
 var _ planNode = &CreateUserNode{}
 var _ planNode = &createViewNode{}
 var _ planNode = &delayedNode{}
@@ -188,6 +191,7 @@ var _ planNode = &joinNode{}
 var _ planNode = &limitNode{}
 var _ planNode = &ordinalityNode{}
 var _ planNode = &projectSetNode{}
+var _ planNode = &recursiveCTENode{}
 var _ planNode = &relocateNode{}
 var _ planNode = &renderNode{}
 var _ planNode = &rowCountNode{}
@@ -205,6 +209,7 @@ var _ planNode = &updateNode{}
 var _ planNode = &upsertNode{}
 var _ planNode = &valuesNode{}
 var _ planNode = &windowNode{}
+var _ planNode = &workTableScanNode{}
 var _ planNode = &zeroNode{}
 
 var _ planNodeFastPath = &CreateUserNode{}
@@ -625,6 +630,14 @@ func startExec(params runParams, plan planNode) error {
 			case *showTraceNode:
 				// showTrace needs to override the params struct, and does so in its startExec() method.
 				return false, nil
+			case *recursiveCTENode:
+				// The queries of a recursive CTE are started by its startExec()
+				// method, one after the other.
+				return false, nil
+			case *applyJoinNode:
+				// The left side of an apply join is started by its startExec()
+				// method, and the right side is planned anew for every left row.
+				return false, nil
 			case *createStatsNode:
 				return false, errors.Errorf("statistics can only be created via DistSQL")
 			}
@@ -758,6 +771,8 @@ func (p *planner) newPlan(
 		return p.AlterTable(ctx, n)
 	case *tree.AlterSequence:
 		return p.AlterSequence(ctx, n)
+	case *tree.AlterTypeAddValue:
+		return p.AlterTypeAddValue(ctx, n)
 	case *tree.AlterUserSetPassword:
 		return p.AlterUserSetPassword(ctx, n)
 	case *tree.CancelQueries:
@@ -774,6 +789,8 @@ func (p *planner) newPlan(
 		return p.CreateIndex(ctx, n)
 	case *tree.CreateTable:
 		return p.CreateTable(ctx, n)
+	case *tree.CreateType:
+		return p.CreateType(ctx, n)
 	case *tree.CreateUser:
 		return p.CreateUser(ctx, n)
 	case *tree.CreateView:
@@ -861,6 +878,8 @@ func (p *planner) newPlan(
 		return p.ShowQueries(ctx, n)
 	case *tree.ShowJobs:
 		return p.ShowJobs(ctx, n)
+	case *tree.ShowSchedules:
+		return p.ShowSchedules(ctx, n)
 	case *tree.ShowRoleGrants:
 		return p.ShowRoleGrants(ctx, n)
 	case *tree.ShowRoles:
@@ -986,6 +1005,8 @@ func (p *planner) doPrepare(ctx context.Context, stmt tree.Statement) (planNode,
 		return p.ShowQueries(ctx, n)
 	case *tree.ShowJobs:
 		return p.ShowJobs(ctx, n)
+	case *tree.ShowSchedules:
+		return p.ShowSchedules(ctx, n)
 	case *tree.ShowRoleGrants:
 		return p.ShowRoleGrants(ctx, n)
 	case *tree.ShowRoles:
@@ -15,10 +15,12 @@
 package sql
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/pkg/errors"
 )
@@ -34,6 +36,7 @@ func collectSpans(params runParams, plan planNode) (reads, writes roachpb.Spans,
 	switch n := plan.(type) {
 	case
 		*valuesNode,
+		*workTableScanNode,
 		*zeroNode,
 		*unaryNode:
 		return nil, nil, nil
@@ -94,6 +97,14 @@ func collectSpans(params runParams, plan planNode) (reads, writes roachpb.Spans,
 		return concatSpans(params, n.left.plan, n.right.plan)
 	case *unionNode:
 		return concatSpans(params, n.left, n.right)
+	case *recursiveCTENode:
+		// The later iterations of the recursive query are planned like the
+		// first one, so they read the same spans.
+		return concatSpans(params, n.initial, n.recursive)
+	case *applyJoinNode:
+		// The right side is planned for every left row like it is planned for
+		// EXPLAIN, so it reads the same spans.
+		return concatSpans(params, n.input.plan, n.right)
 	}
 
 	panic(fmt.Sprintf("don't know how to collect spans for node %T", plan))
@@ -219,3 +230,35 @@ func concatSpans(params runParams, left, right planNode) (reads, writes roachpb.
 	}
 	return append(leftReads, rightReads...), append(leftWrites, rightWrites...), nil
 }
+
+// collectReadSpans collects the spans that the plan and its subqueries may
+// read from scanNodes, along with the newest modification time of the table
+// descriptors they use. Unlike collectSpans, it supports all plans: scans
+// whose spans are only known when they are executed, like the table side of
+// an index join, report the span of their entire index.
+func (p *planTop) collectReadSpans(
+	ctx context.Context,
+) (spans roachpb.Spans, descModTime hlc.Timestamp, err error) {
+	observer := planObserver{
+		enterNode: func(_ context.Context, _ string, plan planNode) (bool, error) {
+			if n, ok := plan.(*scanNode); ok {
+				if len(n.spans) > 0 {
+					spans = append(spans, n.spans...)
+				} else {
+					spans = append(spans, n.desc.IndexSpan(n.index.ID))
+				}
+				descModTime.Forward(n.desc.ModificationTime)
+			}
+			return true, nil
+		},
+	}
+	if err := walkPlan(ctx, p.plan, observer); err != nil {
+		return nil, hlc.Timestamp{}, err
+	}
+	for i := range p.subqueryPlans {
+		if err := walkPlan(ctx, p.subqueryPlans[i].plan, observer); err != nil {
+			return nil, hlc.Timestamp{}, err
+		}
+	}
+	return spans, descModTime, nil
+}
@@ -124,6 +124,12 @@ type planner struct {
 	// if it is SNAPSHOT.
 	avoidCachedDescriptors bool
 
+	// boundedStaleness is set when the current statement is a bounded
+	// staleness read, i.e. it uses AS OF SYSTEM TIME with_max_staleness(). The
+	// statement is planned like a regular one, and its timestamp is negotiated
+	// once the plan is known.
+	boundedStaleness bool
+
 	// If set, the planner should skip checking for the SELECT privilege when
 	// initializing plans to read from a table. This should be used with care.
 	skipSelectPrivilegeChecks bool
@@ -242,6 +248,7 @@ func newInternalPlanner(
 	p.semaCtx = tree.MakeSemaContext(sd.User == security.RootUser /* privileged */)
 	p.semaCtx.Location = &sd.DataConversion.Location
 	p.semaCtx.SearchPath = sd.SearchPath
+	p.semaCtx.TypeResolver = p
 
 	plannerMon := mon.MakeUnlimitedMonitor(ctx,
 		"internal-planner",
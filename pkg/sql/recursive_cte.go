@@ -0,0 +1,234 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/distsqlrun"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+)
+
+// recursiveCTENode implements the logic for a recursive CTE:
+//  1. Evaluate the initial query; emit the results and also save them in
+//     a "working" table.
+//  2. So long as the working table is not empty:
+//     - evaluate the recursive query, substituting the current contents of
+//     the working table for the recursive self-reference;
+//     - emit all resulting rows, and save them as the next iteration's
+//     working table.
+//
+// The recursion terminates when an iteration produces no rows. With UNION (as
+// opposed to UNION ALL), rows which were already produced are discarded
+// before being emitted or saved in the working table, which guarantees that
+// the recursion terminates when it only ever produces a finite set of
+// distinct rows, for instance when it traverses a graph containing cycles.
+//
+// The working tables and the set of rows produced so far are held in memory
+// until they exceed the distsql working memory limit, at which point they
+// spill to temporary storage.
+type recursiveCTENode struct {
+	initial planNode
+	// recursive is the plan of the first iteration of the recursive query.
+	// Since it reads the working table, it is only started once the initial
+	// query has been read fully, rather than along with the rest of the plan.
+	recursive planNode
+	// genIterationFn creates the plans of the subsequent iterations: a plan can
+	// only be run once, so every iteration is planned anew.
+	genIterationFn recursiveCTEIterationFn
+
+	columns sqlbase.ResultColumns
+	// label is the name of the CTE.
+	label string
+	// all is set for UNION ALL, which doesn't remove duplicate rows.
+	all bool
+
+	run recursiveCTERun
+}
+
+// recursiveCTEIterationFn creates the plan of an iteration of the recursive
+// query of the given recursiveCTENode.
+type recursiveCTEIterationFn func(params runParams, n *recursiveCTENode) (planNode, error)
+
+// recursiveCTERun contains the run-time state of recursiveCTENode during
+// local execution.
+type recursiveCTERun struct {
+	// cur is the plan whose rows are being emitted: the initial query, then
+	// the iterations of the recursive query.
+	cur planNode
+	// iteration is the number of the iteration of cur, 0 for the initial
+	// query.
+	iteration int
+
+	memMon  mon.BytesMonitor
+	storage tempStorage
+	// workingRows holds the rows produced by the previous iteration, which
+	// are read by the current iteration through a workTableScanNode.
+	workingRows *spillingRowBuffer
+	// nextRows accumulates the rows produced by the current iteration.
+	nextRows *spillingRowBuffer
+	// seen holds all the rows produced so far, in order to remove duplicate
+	// rows for UNION.
+	seen    spillingRowSet
+	scratch []byte
+}
+
+func (n *recursiveCTENode) startExec(params runParams) error {
+	// The working tables and the set of rows produced so far are limited to
+	// the working memory of a distsql processor before they spill to disk.
+	parentMon := params.EvalContext().Mon
+	limit := distsqlrun.SettingWorkMemBytes.Get(&params.p.ExecCfg().Settings.SV)
+	n.run.memMon = mon.MakeMonitorInheritWithLimit("recursive-cte-limited", limit, parentMon)
+	n.run.memMon.Start(params.ctx, parentMon, mon.BoundAccount{})
+	n.run.storage = makeTempStorage(params.p.ExecCfg(), "recursive-cte-disk")
+	n.run.workingRows = newSpillingRowBuffer(n.run.memMon.MakeBoundAccount(), n.columns, &n.run.storage)
+	n.run.nextRows = newSpillingRowBuffer(n.run.memMon.MakeBoundAccount(), n.columns, &n.run.storage)
+	if !n.all {
+		n.run.seen = makeSpillingRowSet(n.run.memMon.MakeBoundAccount(), &n.run.storage)
+	}
+
+	// The initial query was not started along with the rest of the plan (see
+	// startExec).
+	if err := startPlan(params, n.initial); err != nil {
+		return err
+	}
+	n.run.cur = n.initial
+	n.initial = nil
+	return nil
+}
+
+func (n *recursiveCTENode) Next(params runParams) (bool, error) {
+	for {
+		if err := params.p.cancelChecker.Check(); err != nil {
+			return false, err
+		}
+		next, err := n.run.cur.Next(params)
+		if err != nil {
+			return false, err
+		}
+		if !next {
+			if more, err := n.nextIteration(params); err != nil || !more {
+				return false, err
+			}
+			continue
+		}
+
+		row := n.run.cur.Values()
+		if !n.all {
+			n.run.scratch, err = sqlbase.EncodeDatumsKeyAscending(n.run.scratch[:0], row)
+			if err != nil {
+				return false, err
+			}
+			if added, err := n.run.seen.Add(params.ctx, n.run.scratch); err != nil {
+				return false, err
+			} else if !added {
+				continue
+			}
+		}
+		if err := n.run.nextRows.AddRow(params.ctx, row); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+}
+
+// nextIteration closes the plan whose rows were all emitted and, unless it
+// produced no rows, starts the next iteration of the recursive query with
+// these rows as its working table. It returns false once the recursion is
+// over.
+func (n *recursiveCTENode) nextIteration(params runParams) (bool, error) {
+	n.run.cur.Close(params.ctx)
+	n.run.cur = nil
+	if n.run.nextRows.Len() == 0 {
+		return false, nil
+	}
+	n.run.workingRows, n.run.nextRows = n.run.nextRows, n.run.workingRows
+	n.run.nextRows.Clear(params.ctx)
+	n.run.iteration++
+	log.VEventf(params.ctx, 2, "recursive CTE %q: iteration %d, %d rows in the working table",
+		n.label, n.run.iteration, n.run.workingRows.Len())
+
+	plan := n.recursive
+	n.recursive = nil
+	if plan == nil {
+		var err error
+		if plan, err = n.genIterationFn(params, n); err != nil {
+			return false, err
+		}
+	}
+	n.run.cur = plan
+	if err := startPlan(params, plan); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (n *recursiveCTENode) Values() tree.Datums {
+	return n.run.cur.Values()
+}
+
+func (n *recursiveCTENode) Close(ctx context.Context) {
+	for _, plan := range []planNode{n.initial, n.recursive, n.run.cur} {
+		if plan != nil {
+			plan.Close(ctx)
+		}
+	}
+	n.initial, n.recursive, n.run.cur = nil, nil, nil
+	if n.run.workingRows != nil {
+		n.run.workingRows.Close(ctx)
+		n.run.nextRows.Close(ctx)
+		n.run.workingRows, n.run.nextRows = nil, nil
+		if !n.all {
+			n.run.seen.Close(ctx)
+		}
+		n.run.storage.close(ctx)
+		n.run.memMon.Stop(ctx)
+	}
+}
+
+// workTableScanNode scans the working table of a recursive CTE, that is the
+// rows produced by the previous iteration of its recursive query. It is the
+// self-reference of the CTE in its recursive query.
+type workTableScanNode struct {
+	cte     *recursiveCTENode
+	columns sqlbase.ResultColumns
+
+	run struct {
+		iter *spillingRowBufferIterator
+	}
+}
+
+func (n *workTableScanNode) startExec(params runParams) error {
+	n.run.iter = n.cte.run.workingRows.NewIterator()
+	return nil
+}
+
+func (n *workTableScanNode) Next(params runParams) (bool, error) {
+	return n.run.iter.Next()
+}
+
+func (n *workTableScanNode) Values() tree.Datums {
+	return n.run.iter.Row()
+}
+
+func (n *workTableScanNode) Close(ctx context.Context) {
+	if n.run.iter != nil {
+		n.run.iter.Close()
+		n.run.iter = nil
+	}
+}
@@ -98,6 +98,7 @@ func (p *planner) Select(
 	limit := n.Limit
 	orderBy := n.OrderBy
 	with := n.With
+	locking := n.Locking
 
 	for s, ok := wrapped.(*tree.ParenSelect); ok; s, ok = wrapped.(*tree.ParenSelect) {
 		wrapped = s.Select.Select
@@ -117,13 +118,25 @@ func (p *planner) Select(
 			}
 			limit = s.Select.Limit
 		}
+		locking = append(locking, s.Select.Locking...)
 	}
 
 	switch s := wrapped.(type) {
 	case *tree.SelectClause:
+		if err := checkLockingClause(locking, s); err != nil {
+			return nil, err
+		}
 		// Select can potentially optimize index selection if it's being ordered,
 		// so we allow it to do its own sorting.
-		return p.SelectClause(ctx, s, orderBy, limit, with, desiredTypes, publicColumns)
+		plan, err := p.SelectClause(ctx, s, orderBy, limit, with, desiredTypes, publicColumns)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.applyLocking(ctx, plan, locking); err != nil {
+			plan.Close(ctx)
+			return nil, err
+		}
+		return plan, nil
 
 	// TODO(dan): Union can also do optimizations when it has an ORDER BY, but
 	// currently expects the ordering to be done externally, so we let it fall
@@ -133,6 +146,15 @@ func (p *planner) Select(
 	// TODO(jordan): this limitation also applies to CTEs, which do not yet
 	// propagate into VALUES and UNION clauses
 	default:
+		if len(locking) > 0 {
+			switch s.(type) {
+			case *tree.ValuesClause:
+				return nil, pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+					"%s cannot be applied to VALUES", locking[0].Strength)
+			case *tree.UnionClause:
+				return nil, lockingNotAllowedErr(locking[0].Strength, "UNION/INTERSECT/EXCEPT")
+			}
+		}
 		plan, err := p.newPlan(ctx, s, desiredTypes)
 		if err != nil {
 			return nil, err
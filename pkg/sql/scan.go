@@ -98,6 +98,12 @@ type scanNode struct {
 
 	disableBatchLimits bool
 
+	// lockingStrength and lockingWaitPolicy are set when the scan is part of a
+	// SELECT ... FOR UPDATE/SHARE. They make the scan acquire locks over the
+	// rows it reads.
+	lockingStrength   tree.LockingStrength
+	lockingWaitPolicy tree.LockingWaitPolicy
+
 	run scanRun
 
 	// This struct must be allocated on the heap and its location stay
@@ -202,8 +208,42 @@ func (n *scanNode) startExec(params runParams) error {
 		Cols:             n.cols,
 		ValNeededForCol:  n.valNeededForCol.Copy(),
 	}
-	return n.run.fetcher.Init(n.reverse, false, /* returnRangeInfo */
-		false /* isCheck */, &params.p.alloc, tableArgs)
+	if err := n.run.fetcher.Init(n.reverse, false, /* returnRangeInfo */
+		false /* isCheck */, &params.p.alloc, tableArgs); err != nil {
+		return err
+	}
+	n.run.fetcher.SetLocking(n.kvLocking())
+	return nil
+}
+
+// kvLocking returns the KV locking strength and wait policy that implement the
+// row-level locking of the scan. Like in Postgres, FOR KEY SHARE and FOR SHARE
+// acquire shared locks, and FOR NO KEY UPDATE and FOR UPDATE acquire exclusive
+// locks.
+func (n *scanNode) kvLocking() (roachpb.KeyLockingStrength, roachpb.LockWaitPolicy) {
+	var strength roachpb.KeyLockingStrength
+	switch n.lockingStrength {
+	case tree.ForNone:
+		strength = roachpb.LOCK_NONE
+	case tree.ForKeyShare, tree.ForShare:
+		strength = roachpb.LOCK_SHARED
+	case tree.ForNoKeyUpdate, tree.ForUpdate:
+		strength = roachpb.LOCK_EXCLUSIVE
+	default:
+		panic(fmt.Sprintf("unknown locking strength %d", n.lockingStrength))
+	}
+	var waitPolicy roachpb.LockWaitPolicy
+	switch n.lockingWaitPolicy {
+	case tree.LockWaitBlock:
+		waitPolicy = roachpb.LOCK_WAIT_BLOCK
+	case tree.LockWaitSkip:
+		waitPolicy = roachpb.LOCK_WAIT_SKIP
+	case tree.LockWaitError:
+		waitPolicy = roachpb.LOCK_WAIT_ERROR
+	default:
+		panic(fmt.Sprintf("unknown locking wait policy %d", n.lockingWaitPolicy))
+	}
+	return strength, waitPolicy
 }
 
 func (n *scanNode) Close(context.Context) {
@@ -546,6 +546,34 @@ func (sc *SchemaChanger) drainNames(
 	return err
 }
 
+// Make the enum values added by ALTER TYPE ... ADD VALUE writable.
+func (sc *SchemaChanger) makeEnumMembersWritable(
+	ctx context.Context, lease *sqlbase.TableDescriptor_SchemaChangeLease,
+) error {
+	if err := sc.ExtendLease(ctx, lease); err != nil {
+		return err
+	}
+
+	// Publish a new version with the values writable after everyone has seen
+	// the version which can read them, so that no node reads a value it cannot
+	// decode.
+	_, err := sc.leaseMgr.Publish(
+		ctx,
+		sc.tableID,
+		func(desc *sqlbase.TableDescriptor) error {
+			// As in drainNames, a schema change which ran in the meantime
+			// requires another version increment first.
+			if desc.UpVersion {
+				return errSchemaChangeDuringDrain
+			}
+			desc.MakeEnumMembersWritable()
+			return nil
+		},
+		nil, /* logEvent */
+	)
+	return err
+}
+
 // Execute the entire schema change in steps.
 // inSession is set to false when this is called from the asynchronous
 // schema change execution path.
@@ -599,6 +627,12 @@ func (sc *SchemaChanger) exec(
 		}
 	}
 
+	if tableDesc.HasReadOnlyEnumMembers() {
+		if err := sc.makeEnumMembersWritable(ctx, &lease); err != nil {
+			return err
+		}
+	}
+
 	if drop, err := sc.maybeAddDrop(ctx, inSession, &lease, tableDesc, evalCtx); err != nil {
 		return err
 	} else if drop {
@@ -1461,7 +1495,7 @@ func (s *SchemaChangeManager) Start(stopper *stop.Stopper) {
 							delete(s.schemaChangers, table.ID)
 						}
 
-					case *sqlbase.Descriptor_Database:
+					case *sqlbase.Descriptor_Database, *sqlbase.Descriptor_Type:
 						// Ignore.
 					}
 				})
@@ -0,0 +1,133 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// checkLockingClause verifies that the locking clause of a SELECT statement
+// can be applied to the given select clause. As in Postgres, rows can only be
+// locked when each result row corresponds to a single row of each table.
+func checkLockingClause(locking tree.LockingClause, s *tree.SelectClause) error {
+	if len(locking) == 0 {
+		return nil
+	}
+	strength := locking[0].Strength
+	switch {
+	case s.Distinct:
+		return lockingNotAllowedErr(strength, "DISTINCT clause")
+	case len(s.GroupBy) > 0:
+		return lockingNotAllowedErr(strength, "GROUP BY clause")
+	case s.Having != nil:
+		return lockingNotAllowedErr(strength, "HAVING clause")
+	case len(s.Window) > 0:
+		return lockingNotAllowedErr(strength, "window functions")
+	}
+	return nil
+}
+
+func lockingNotAllowedErr(strength tree.LockingStrength, what string) error {
+	return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+		"%s is not allowed with %s", strength, what)
+}
+
+// applyLocking configures the table scans in the plan of a SELECT statement
+// to acquire row-level locks as specified by its locking clause. A locking
+// item without targets applies to all the tables in the FROM clause; when
+// several items apply to a table, the strongest strength and wait policy win.
+// Targets are matched against table names; aliases are not supported.
+//
+// Privileges: UPDATE on the locked tables.
+//   Notes: postgres requires UPDATE, DELETE or TRUNCATE.
+func (p *planner) applyLocking(
+	ctx context.Context, plan planNode, locking tree.LockingClause,
+) error {
+	if len(locking) == 0 {
+		return nil
+	}
+	targetFound := make([][]bool, len(locking))
+	for i, item := range locking {
+		targetFound[i] = make([]bool, len(item.Targets))
+	}
+
+	if err := walkPlan(ctx, plan, planObserver{
+		enterNode: func(ctx context.Context, _ string, plan planNode) (bool, error) {
+			switch n := plan.(type) {
+			case *groupNode:
+				return false, lockingNotAllowedErr(locking[0].Strength, "aggregate functions")
+			case *windowNode:
+				return false, lockingNotAllowedErr(locking[0].Strength, "window functions")
+			case *scanNode:
+				locked := false
+				for i, item := range locking {
+					if !lockingItemAppliesTo(item, n, targetFound[i]) {
+						continue
+					}
+					locked = true
+					if item.Strength > n.lockingStrength {
+						n.lockingStrength = item.Strength
+					}
+					if item.WaitPolicy > n.lockingWaitPolicy {
+						n.lockingWaitPolicy = item.WaitPolicy
+					}
+				}
+				if locked {
+					if err := p.CheckPrivilege(ctx, n.desc, privilege.UPDATE); err != nil {
+						return false, err
+					}
+				}
+			}
+			return true, nil
+		},
+	}); err != nil {
+		return err
+	}
+
+	for i, item := range locking {
+		for j, found := range targetFound[i] {
+			if !found {
+				return pgerror.NewErrorf(pgerror.CodeUndefinedTableError,
+					"relation %q in %s clause not found in FROM clause",
+					tree.ErrString(&item.Targets[j]), item.Strength)
+			}
+		}
+	}
+	return nil
+}
+
+// lockingItemAppliesTo returns whether the locking item applies to the given
+// scan, recording which of the item's targets matched in found.
+func lockingItemAppliesTo(item *tree.LockingItem, scan *scanNode, found []bool) bool {
+	if len(item.Targets) == 0 {
+		return true
+	}
+	applies := false
+	for i := range item.Targets {
+		tn, err := item.Targets[i].Normalize()
+		if err != nil {
+			continue
+		}
+		if string(tn.TableName) == scan.desc.Name {
+			found[i] = true
+			applies = true
+		}
+	}
+	return applies
+}
@@ -45,6 +45,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/storage/closedts"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
@@ -1776,6 +1777,64 @@ may increase either contention or retry errors, or both.`,
 		},
 	),
 
+	tree.FollowerReadTimestampFunctionName: makeBuiltin(
+		tree.FunctionProperties{
+			Category: categoryDateAndTime,
+			Impure:   true,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{},
+			ReturnType: tree.FixedReturnType(types.TimestampTZ),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				offset := closedts.FollowerReadOffset(&ctx.Settings.SV)
+				if offset == 0 {
+					return nil, pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+						"%s: closed timestamps are disabled", tree.FollowerReadTimestampFunctionName)
+				}
+				return tree.MakeDTimestampTZ(ctx.GetStmtTimestamp().Add(-offset), time.Microsecond), nil
+			},
+			Info: `Returns a timestamp which is very likely to be safe to perform
+against a follower replica.
+
+This function is intended to be used with an AS OF SYSTEM TIME clause to perform
+historical reads against a time which is recent but sufficiently old for reads
+to be performed against the closest replica as opposed to the current
+leaseholder for a given range.
+
+Note that this function requires follower reads to be enabled with the
+kv.closed_timestamp.follower_reads_enabled cluster setting.`,
+		},
+	),
+
+	tree.WithMaxStalenessFunctionName: makeBuiltin(
+		tree.FunctionProperties{
+			Category: categoryDateAndTime,
+			Impure:   true,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"max_staleness", types.Interval}},
+			ReturnType: tree.FixedReturnType(types.TimestampTZ),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				maxStaleness := args[0].(*tree.DInterval).Duration
+				if maxStaleness.Compare(duration.Duration{}) < 0 {
+					return nil, pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+						"%s: interval must be non-negative", tree.WithMaxStalenessFunctionName)
+				}
+				ts := duration.Add(ctx.GetStmtTimestamp(), maxStaleness.Mul(-1))
+				return tree.MakeDTimestampTZ(ts, time.Microsecond), nil
+			},
+			Info: `Returns the statement time minus the given interval, which is the
+oldest timestamp a bounded staleness read may be performed at.
+
+When used in an AS OF SYSTEM TIME clause, the query is run at the newest
+timestamp which is at most this stale and at which every range the query reads
+can be served by its nearest replica. If the nearest replicas cannot serve the
+query within the given bound, it runs as a regular read against the
+leaseholders. Bounded staleness reads can only be used in implicit
+transactions.`,
+		},
+	),
+
 	"extract": makeBuiltin(
 		tree.FunctionProperties{Category: categoryDateAndTime},
 		tree.Overload{
@@ -26,10 +26,50 @@ import (
 	"github.com/pkg/errors"
 )
 
+// FollowerReadTimestampFunctionName is the name of the function which returns a
+// timestamp that is old enough for reads to be served by follower replicas.
+// Though impure, it is allowed in AS OF SYSTEM TIME clauses.
+const FollowerReadTimestampFunctionName = "follower_read_timestamp"
+
+// WithMaxStalenessFunctionName is the name of the function which requests a
+// bounded staleness read when used in an AS OF SYSTEM TIME clause. It returns
+// the oldest timestamp the read is allowed to be performed at; the timestamp
+// actually used is negotiated with the replicas touched by the query.
+const WithMaxStalenessFunctionName = "with_max_staleness"
+
+func isAsOfFunction(expr TypedExpr, name string) bool {
+	fe, ok := expr.(*FuncExpr)
+	if !ok {
+		return false
+	}
+	def, ok := fe.Func.FunctionReference.(*FunctionDefinition)
+	return ok && def.Name == name
+}
+
+// AsOfSystemTime is the result of evaluating an AS OF SYSTEM TIME clause.
+type AsOfSystemTime struct {
+	// Timestamp is the timestamp to read at. For bounded staleness reads, it is
+	// the oldest timestamp the read may be performed at.
+	Timestamp hlc.Timestamp
+	// BoundedStaleness is set if the clause requested a bounded staleness read
+	// through with_max_staleness(). The timestamp of such a read can be moved
+	// forward, up to the present, to one that the nearest replicas can serve.
+	BoundedStaleness bool
+}
+
 // EvalAsOfTimestamp evaluates the timestamp argument to an AS OF SYSTEM TIME query.
 func EvalAsOfTimestamp(
 	asOf AsOfClause, max hlc.Timestamp, semaCtx *SemaContext, evalCtx *EvalContext,
 ) (hlc.Timestamp, error) {
+	ast, err := EvalAsOfClause(asOf, max, semaCtx, evalCtx)
+	return ast.Timestamp, err
+}
+
+// EvalAsOfClause is like EvalAsOfTimestamp, but also reports whether the clause
+// requested a bounded staleness read.
+func EvalAsOfClause(
+	asOf AsOfClause, max hlc.Timestamp, semaCtx *SemaContext, evalCtx *EvalContext,
+) (AsOfSystemTime, error) {
 	// We need to save and restore the previous value of the field in
 	// semaCtx in case we are recursively called within a subquery
 	// context.
@@ -39,14 +79,16 @@ func EvalAsOfTimestamp(
 
 	te, err := asOf.Expr.TypeCheck(semaCtx, types.String)
 	if err != nil {
-		return hlc.Timestamp{}, err
+		return AsOfSystemTime{}, err
 	}
-	if !IsConst(evalCtx, te) {
-		return hlc.Timestamp{}, errors.Errorf("AS OF SYSTEM TIME: only constant expressions are allowed")
+	boundedStaleness := isAsOfFunction(te, WithMaxStalenessFunctionName)
+	if !IsConst(evalCtx, te) && !boundedStaleness &&
+		!isAsOfFunction(te, FollowerReadTimestampFunctionName) {
+		return AsOfSystemTime{}, errors.Errorf("AS OF SYSTEM TIME: only constant expressions are allowed")
 	}
 	d, err := te.Eval(evalCtx)
 	if err != nil {
-		return hlc.Timestamp{}, err
+		return AsOfSystemTime{}, err
 	}
 
 	var ts hlc.Timestamp
@@ -78,20 +120,23 @@ func EvalAsOfTimestamp(
 		ts, convErr = DecimalToHLC(&d.Decimal)
 	case *DInterval:
 		ts.WallTime = duration.Add(evalCtx.GetStmtTimestamp(), d.Duration).UnixNano()
+	case *DTimestampTZ:
+		ts.WallTime = d.Time.UnixNano()
 	default:
 		convErr = errors.Errorf("AS OF SYSTEM TIME: expected timestamp, decimal, or interval, got %s (%T)", d.ResolvedType(), d)
 	}
+	res := AsOfSystemTime{Timestamp: ts, BoundedStaleness: boundedStaleness}
 	if convErr != nil {
-		return ts, convErr
+		return res, convErr
 	}
 
 	var zero hlc.Timestamp
 	if ts == zero {
-		return ts, errors.Errorf("AS OF SYSTEM TIME: zero timestamp is invalid")
+		return res, errors.Errorf("AS OF SYSTEM TIME: zero timestamp is invalid")
 	} else if max.Less(ts) {
-		return ts, errors.Errorf("AS OF SYSTEM TIME: cannot specify timestamp in the future")
+		return res, errors.Errorf("AS OF SYSTEM TIME: cannot specify timestamp in the future")
 	}
-	return ts, nil
+	return res, nil
 }
 
 // DecimalToHLC performs the conversion from an inputted DECIMAL datum for an
@@ -45,10 +45,50 @@ func (node *Backup) Format(ctx *FmtCtx) {
 	}
 }
 
+// ScheduledBackup represents a CREATE SCHEDULE FOR BACKUP statement.
+type ScheduledBackup struct {
+	ScheduleName Expr
+	Recurrence   Expr
+	// FullBackup is the schedule of full backups; nil means that every backup
+	// is a full backup.
+	FullBackup    Expr
+	Targets       TargetList
+	To            Expr
+	BackupOptions KVOptions
+}
+
+var _ Statement = &ScheduledBackup{}
+
+// Format implements the NodeFormatter interface.
+func (node *ScheduledBackup) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE SCHEDULE ")
+	if node.ScheduleName != nil {
+		ctx.FormatNode(node.ScheduleName)
+		ctx.WriteString(" ")
+	}
+	ctx.WriteString("FOR BACKUP ")
+	ctx.FormatNode(&node.Targets)
+	ctx.WriteString(" INTO ")
+	ctx.FormatNode(node.To)
+	if node.BackupOptions != nil {
+		ctx.WriteString(" WITH ")
+		ctx.FormatNode(&node.BackupOptions)
+	}
+	ctx.WriteString(" RECURRING ")
+	ctx.FormatNode(node.Recurrence)
+	if node.FullBackup != nil {
+		ctx.WriteString(" FULL BACKUP ")
+		ctx.FormatNode(node.FullBackup)
+	}
+}
+
 // Restore represents a RESTORE statement.
 type Restore struct {
 	Targets TargetList
+	// From lists the backups to restore from or, if Latest is set, holds the
+	// collection whose latest backup chain is restored from.
 	From    Exprs
+	Latest  bool
 	AsOf    AsOfClause
 	Options KVOptions
 }
@@ -60,6 +100,9 @@ func (node *Restore) Format(ctx *FmtCtx) {
 	ctx.WriteString("RESTORE ")
 	ctx.FormatNode(&node.Targets)
 	ctx.WriteString(" FROM ")
+	if node.Latest {
+		ctx.WriteString("LATEST IN ")
+	}
 	ctx.FormatNode(&node.From)
 	if node.AsOf.Expr != nil {
 		ctx.WriteString(" ")
@@ -19,14 +19,21 @@ type CreateChangefeed struct {
 	Targets TargetList
 	SinkURI Expr
 	Options KVOptions
+	// Select, if non-nil, is the projection and filter of a CREATE CHANGEFEED
+	// ... AS SELECT statement. Its FROM clause always names exactly the one
+	// table in Targets.
+	Select *SelectClause
 }
 
 var _ Statement = &CreateChangefeed{}
 
 // Format implements the NodeFormatter interface.
 func (node *CreateChangefeed) Format(ctx *FmtCtx) {
-	ctx.WriteString("CREATE CHANGEFEED FOR ")
-	ctx.FormatNode(&node.Targets)
+	ctx.WriteString("CREATE CHANGEFEED")
+	if node.Select == nil {
+		ctx.WriteString(" FOR ")
+		ctx.FormatNode(&node.Targets)
+	}
 	if node.SinkURI != nil {
 		ctx.WriteString(" INTO ")
 		ctx.FormatNode(node.SinkURI)
@@ -35,4 +42,8 @@ func (node *CreateChangefeed) Format(ctx *FmtCtx) {
 		ctx.WriteString(" WITH ")
 		ctx.FormatNode(&node.Options)
 	}
+	if node.Select != nil {
+		ctx.WriteString(" AS ")
+		ctx.FormatNode(node.Select)
+	}
 }
@@ -423,6 +423,7 @@ var (
 		types.INet,
 		types.JSON,
 		types.BitArray,
+		types.FamEnum,
 	}
 	// StrValAvailBytes is the set of types convertible to byte array.
 	StrValAvailBytes = []types.T{types.Bytes, types.UUID, types.String}
@@ -70,15 +70,27 @@ func (node *CreateDatabase) Format(ctx *FmtCtx) {
 	}
 }
 
-// IndexElem represents a column with a direction in a CREATE INDEX statement.
+// IndexElem represents a column or an expression with a direction in a CREATE
+// INDEX statement.
 type IndexElem struct {
-	Column    Name
+	Column Name
+	// Expr is set instead of Column when the element is an expression.
+	Expr      Expr
 	Direction Direction
 }
 
 // Format implements the NodeFormatter interface.
 func (node *IndexElem) Format(ctx *FmtCtx) {
-	ctx.FormatNode(&node.Column)
+	if node.Expr == nil {
+		ctx.FormatNode(&node.Column)
+	} else if _, ok := node.Expr.(*FuncExpr); ok {
+		// Like in postgres, function calls do not need parentheses.
+		ctx.FormatNode(node.Expr)
+	} else {
+		ctx.WriteByte('(')
+		ctx.FormatNode(node.Expr)
+		ctx.WriteByte(')')
+	}
 	if node.Direction != DefaultDirection {
 		ctx.WriteByte(' ')
 		ctx.WriteString(node.Direction.String())
@@ -214,6 +226,7 @@ type ColumnTableDef struct {
 	}
 	Computed struct {
 		Computed bool
+		Virtual  bool
 		Expr     Expr
 	}
 	Family struct {
@@ -318,6 +331,7 @@ func NewColumnTableDef(
 			d.References.Actions = t.Actions
 		case *ColumnComputedDef:
 			d.Computed.Computed = true
+			d.Computed.Virtual = t.Virtual
 			d.Computed.Expr = t.Expr
 		case *ColumnFamilyConstraint:
 			if d.HasColumnFamily() {
@@ -419,7 +433,11 @@ func (node *ColumnTableDef) Format(ctx *FmtCtx) {
 	if node.IsComputed() {
 		ctx.WriteString(" AS (")
 		ctx.FormatNode(node.Computed.Expr)
-		ctx.WriteString(") STORED")
+		if node.Computed.Virtual {
+			ctx.WriteString(") VIRTUAL")
+		} else {
+			ctx.WriteString(") STORED")
+		}
 	}
 	if node.HasColumnFamily() {
 		if node.Family.Create {
@@ -495,7 +513,8 @@ type ColumnFKConstraint struct {
 
 // ColumnComputedDef represents the description of a computed column.
 type ColumnComputedDef struct {
-	Expr Expr
+	Expr    Expr
+	Virtual bool
 }
 
 // ColumnFamilyConstraint represents FAMILY on a column.
@@ -959,6 +978,28 @@ func (node *CreateSequence) Format(ctx *FmtCtx) {
 	ctx.FormatNode(&node.Options)
 }
 
+// CreateType represents a CREATE TYPE statement. Only enum types can be
+// created. Types share the namespace of tables, so their names are resolved
+// like the names of tables.
+type CreateType struct {
+	Name       NormalizableTableName
+	EnumLabels []string
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateType) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE TYPE ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" AS ENUM (")
+	for i, label := range node.EnumLabels {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		lex.EncodeSQLStringWithFlags(ctx.Buffer, label, ctx.flags.EncodeFlags())
+	}
+	ctx.WriteByte(')')
+}
+
 // SequenceOptions represents a list of sequence options.
 type SequenceOptions []SequenceOption
 
@@ -1245,6 +1245,120 @@ func (d *DCollatedString) IsComposite() bool {
 	return true
 }
 
+// DEnum is the Datum for values of an enum type. The struct members are
+// intended to be immutable.
+type DEnum struct {
+	Typ types.TEnum
+	// Physical is the encoding of the value, which determines its order.
+	Physical []byte
+	// Logical is the label of the value.
+	Logical string
+}
+
+// MakeDEnumFromPhysicalRepresentation returns the value of the enum type
+// with the given physical representation.
+func MakeDEnumFromPhysicalRepresentation(typ types.TEnum, physical []byte) (*DEnum, error) {
+	idx := typ.MemberByPhysical(physical)
+	if idx < 0 {
+		return nil, pgerror.NewErrorf(pgerror.CodeInternalError,
+			"could not find physical representation %x of enum %s", physical, typ)
+	}
+	return &DEnum{Typ: typ, Physical: typ.Members()[idx].Physical, Logical: typ.Members()[idx].Logical}, nil
+}
+
+// MakeDEnumFromLogicalRepresentation returns the value of the enum type with
+// the given label.
+func MakeDEnumFromLogicalRepresentation(typ types.TEnum, logical string) (*DEnum, error) {
+	idx := typ.MemberByLogical(logical)
+	if idx < 0 {
+		return nil, pgerror.NewErrorf(pgerror.CodeInvalidTextRepresentationError,
+			"invalid input value for enum %s: %q", typ, logical)
+	}
+	return &DEnum{Typ: typ, Physical: typ.Members()[idx].Physical, Logical: logical}, nil
+}
+
+// AmbiguousFormat implements the Datum interface. An enum value is formatted
+// as its label, which is typed as a value of the enum from its context, as
+// the name of its type can't be resolved everywhere the expression may be
+// parsed again.
+func (*DEnum) AmbiguousFormat() bool { return false }
+
+// Format implements the NodeFormatter interface.
+func (d *DEnum) Format(ctx *FmtCtx) {
+	if ctx.flags.HasFlags(FmtFlags(lex.EncBareStrings)) {
+		ctx.WriteString(d.Logical)
+		return
+	}
+	lex.EncodeSQLString(ctx.Buffer, d.Logical)
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (d *DEnum) ResolvedType() types.T {
+	return d.Typ
+}
+
+// Compare implements the Datum interface.
+func (d *DEnum) Compare(ctx *EvalContext, other Datum) int {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1
+	}
+	v, ok := UnwrapDatum(ctx, other).(*DEnum)
+	if !ok || d.Typ.ID != v.Typ.ID {
+		panic(makeUnsupportedComparisonMessage(d, other))
+	}
+	return bytes.Compare(d.Physical, v.Physical)
+}
+
+func (d *DEnum) memberAt(idx int) (Datum, bool) {
+	members := d.Typ.Members()
+	if idx < 0 || idx >= len(members) {
+		return nil, false
+	}
+	return &DEnum{Typ: d.Typ, Physical: members[idx].Physical, Logical: members[idx].Logical}, true
+}
+
+// Prev implements the Datum interface.
+func (d *DEnum) Prev(_ *EvalContext) (Datum, bool) {
+	return d.memberAt(d.Typ.MemberByPhysical(d.Physical) - 1)
+}
+
+// Next implements the Datum interface.
+func (d *DEnum) Next(_ *EvalContext) (Datum, bool) {
+	idx := d.Typ.MemberByPhysical(d.Physical)
+	if idx < 0 {
+		return nil, false
+	}
+	return d.memberAt(idx + 1)
+}
+
+// IsMax implements the Datum interface.
+func (d *DEnum) IsMax(_ *EvalContext) bool {
+	members := d.Typ.Members()
+	return len(members) > 0 && bytes.Equal(d.Physical, members[len(members)-1].Physical)
+}
+
+// IsMin implements the Datum interface.
+func (d *DEnum) IsMin(_ *EvalContext) bool {
+	members := d.Typ.Members()
+	return len(members) > 0 && bytes.Equal(d.Physical, members[0].Physical)
+}
+
+// Min implements the Datum interface.
+func (d *DEnum) Min(_ *EvalContext) (Datum, bool) {
+	return d.memberAt(0)
+}
+
+// Max implements the Datum interface.
+func (d *DEnum) Max(_ *EvalContext) (Datum, bool) {
+	return d.memberAt(len(d.Typ.Members()) - 1)
+}
+
+// Size implements the Datum interface.
+func (d *DEnum) Size() uintptr {
+	return unsafe.Sizeof(*d) + uintptr(len(d.Physical)) + uintptr(len(d.Logical))
+}
+
 // DBytes is the bytes Datum. The underlying type is a string because we want
 // the immutability, but this may contain arbitrary bytes.
 type DBytes string
@@ -2447,6 +2561,8 @@ func AsJSON(d Datum) (json.JSON, error) {
 		return json.FromString(string(*t)), nil
 	case *DCollatedString:
 		return json.FromString(t.Contents), nil
+	case *DEnum:
+		return json.FromString(t.Logical), nil
 	case *DJSON:
 		return t.JSON, nil
 	case *DArray:
@@ -3526,6 +3642,9 @@ func DatumTypeSize(t types.T) (uintptr, bool) {
 	case types.TCollatedString:
 		return unsafe.Sizeof(DCollatedString{"", "", nil}), variableSize
 
+	case types.TEnum:
+		return unsafe.Sizeof(DEnum{}), variableSize
+
 	case types.TTuple:
 		sz := uintptr(0)
 		variable := false
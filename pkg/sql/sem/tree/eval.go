@@ -1678,6 +1678,7 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 		makeEqFn(types.Date, types.Date),
 		makeEqFn(types.Decimal, types.Decimal),
 		makeEqFn(types.FamCollatedString, types.FamCollatedString),
+		makeEqFn(types.FamEnum, types.FamEnum),
 		makeEqFn(types.Float, types.Float),
 		makeEqFn(types.INet, types.INet),
 		makeEqFn(types.Int, types.Int),
@@ -1722,6 +1723,7 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 		makeLtFn(types.Date, types.Date),
 		makeLtFn(types.Decimal, types.Decimal),
 		makeLtFn(types.FamCollatedString, types.FamCollatedString),
+		makeLtFn(types.FamEnum, types.FamEnum),
 		makeLtFn(types.Float, types.Float),
 		makeLtFn(types.INet, types.INet),
 		makeLtFn(types.Int, types.Int),
@@ -1765,6 +1767,7 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 		makeLeFn(types.Date, types.Date),
 		makeLeFn(types.Decimal, types.Decimal),
 		makeLeFn(types.FamCollatedString, types.FamCollatedString),
+		makeLeFn(types.FamEnum, types.FamEnum),
 		makeLeFn(types.Float, types.Float),
 		makeLeFn(types.INet, types.INet),
 		makeLeFn(types.Int, types.Int),
@@ -1816,6 +1819,7 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 		makeIsFn(types.Date, types.Date),
 		makeIsFn(types.Decimal, types.Decimal),
 		makeIsFn(types.FamCollatedString, types.FamCollatedString),
+		makeIsFn(types.FamEnum, types.FamEnum),
 		makeIsFn(types.Float, types.Float),
 		makeIsFn(types.INet, types.INet),
 		makeIsFn(types.Int, types.Int),
@@ -1863,6 +1867,7 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 		makeEvalTupleIn(types.Date),
 		makeEvalTupleIn(types.Decimal),
 		makeEvalTupleIn(types.FamCollatedString),
+		makeEvalTupleIn(types.FamEnum),
 		makeEvalTupleIn(types.FamTuple),
 		makeEvalTupleIn(types.Float),
 		makeEvalTupleIn(types.INet),
@@ -3080,6 +3085,8 @@ func PerformCast(ctx *EvalContext, d Datum, t coltypes.CastTargetType) (Datum, e
 			s = t.name
 		case *DJSON:
 			s = t.JSON.String()
+		case *DEnum:
+			s = t.Logical
 		}
 		switch c := t.(type) {
 		case *coltypes.TString:
@@ -3112,6 +3119,16 @@ func PerformCast(ctx *EvalContext, d Datum, t coltypes.CastTargetType) (Datum, e
 			return d, nil
 		}
 
+	case *coltypes.TEnum:
+		switch t := d.(type) {
+		case *DString:
+			return MakeDEnumFromLogicalRepresentation(typ.Typ, string(*t))
+		case *DCollatedString:
+			return MakeDEnumFromLogicalRepresentation(typ.Typ, t.Contents)
+		case *DEnum:
+			return d, nil
+		}
+
 	case *coltypes.TUUID:
 		switch t := d.(type) {
 		case *DString:
@@ -3908,6 +3925,11 @@ func (t *DCollatedString) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
 }
 
+// Eval implements the TypedExpr interface.
+func (t *DEnum) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
 // Eval implements the TypedExpr interface.
 func (t *DTimestamp) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
@@ -1438,7 +1438,8 @@ var (
 	stringCastTypes = []types.T{types.Unknown, types.Bool, types.Int, types.Float, types.Decimal, types.String, types.FamCollatedString,
 		types.BitArray,
 		types.FamArray, types.FamTuple,
-		types.Bytes, types.Timestamp, types.TimestampTZ, types.Interval, types.UUID, types.Date, types.Time, types.Oid, types.INet, types.JSON}
+		types.Bytes, types.Timestamp, types.TimestampTZ, types.Interval, types.UUID, types.Date, types.Time, types.Oid, types.INet, types.JSON,
+		types.FamEnum}
 	bytesCastTypes = []types.T{types.Unknown, types.String, types.FamCollatedString, types.Bytes, types.UUID}
 	dateCastTypes  = []types.T{types.Unknown, types.String, types.FamCollatedString, types.Date, types.Timestamp, types.TimestampTZ, types.Int}
 	timeCastTypes  = []types.T{types.Unknown, types.String, types.FamCollatedString, types.Time,
@@ -1450,6 +1451,7 @@ var (
 	inetCastTypes      = []types.T{types.Unknown, types.String, types.FamCollatedString, types.INet}
 	arrayCastTypes     = []types.T{types.Unknown, types.String}
 	jsonCastTypes      = []types.T{types.Unknown, types.String, types.JSON}
+	enumCastTypes      = []types.T{types.Unknown, types.String, types.FamCollatedString, types.FamEnum}
 )
 
 // validCastTypes returns a set of types that can be cast into the provided type.
@@ -1490,6 +1492,8 @@ func validCastTypes(t types.T) []types.T {
 		// directly to collated string.
 		if t.FamilyEqual(types.FamCollatedString) {
 			return stringCastTypes
+		} else if t.FamilyEqual(types.FamEnum) {
+			return enumCastTypes
 		} else if t.FamilyEqual(types.FamArray) {
 			ret := make([]types.T, len(arrayCastTypes))
 			copy(ret, arrayCastTypes)
@@ -1655,6 +1659,7 @@ func (node *DUuid) String() string            { return AsString(node) }
 func (node *DIPAddr) String() string          { return AsString(node) }
 func (node *DString) String() string          { return AsString(node) }
 func (node *DCollatedString) String() string  { return AsString(node) }
+func (node *DEnum) String() string            { return AsString(node) }
 func (node *DTimestamp) String() string       { return AsString(node) }
 func (node *DTimestampTZ) String() string     { return AsString(node) }
 func (node *DTuple) String() string           { return AsString(node) }
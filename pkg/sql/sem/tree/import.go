@@ -23,6 +23,11 @@ type Import struct {
 	Files      Exprs
 	Bundle     bool
 	Options    KVOptions
+
+	// Into is set for IMPORT INTO an existing table, in which case IntoCols
+	// optionally names the columns the data provides values for.
+	Into     bool
+	IntoCols NameList
 }
 
 var _ Statement = &Import{}
@@ -40,6 +45,19 @@ func (node *Import) Format(ctx *FmtCtx) {
 		ctx.WriteString(node.FileFormat)
 		ctx.WriteString(" ")
 		ctx.FormatNode(&node.Files)
+	} else if node.Into {
+		ctx.WriteString("INTO ")
+		ctx.FormatNode(&node.Table)
+		if node.IntoCols != nil {
+			ctx.WriteString(" (")
+			ctx.FormatNode(&node.IntoCols)
+			ctx.WriteString(")")
+		}
+		ctx.WriteString(" ")
+		ctx.WriteString(node.FileFormat)
+		ctx.WriteString(" DATA (")
+		ctx.FormatNode(&node.Files)
+		ctx.WriteString(")")
 	} else {
 		ctx.WriteString("TABLE ")
 		ctx.FormatNode(&node.Table)
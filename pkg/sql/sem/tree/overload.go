@@ -768,7 +768,7 @@ func checkReturn(
 		o := s.overloads[idx]
 		p := o.params()
 		for _, i := range s.constIdxs {
-			des := p.GetAt(i)
+			des := concreteFamilyType(s, p.GetAt(i))
 			typ, err := s.exprs[i].TypeCheck(ctx, des)
 			if err != nil {
 				return s.typedExprs, nil, true, errors.Wrap(err, "error type checking constant value")
@@ -780,7 +780,7 @@ func checkReturn(
 		}
 
 		for _, i := range s.placeholderIdxs {
-			des := p.GetAt(i)
+			des := concreteFamilyType(s, p.GetAt(i))
 			typ, err := s.exprs[i].TypeCheck(ctx, des)
 			if err != nil {
 				if des.IsAmbiguous() {
@@ -796,6 +796,25 @@ func checkReturn(
 	}
 }
 
+// concreteFamilyType returns the type of the already typed arguments of the
+// overload whose parameter type is the given type family, if it is the enum
+// family. Unlike other families, the values of an enum type can only be typed
+// from constants once the concrete enum type is known.
+func concreteFamilyType(s typeCheckOverloadState, des types.T) types.T {
+	if des == nil || !des.FamilyEqual(types.FamEnum) || !des.IsAmbiguous() {
+		return des
+	}
+	for _, e := range s.typedExprs {
+		if e == nil {
+			continue
+		}
+		if typ := e.ResolvedType(); !typ.IsAmbiguous() && des.Equivalent(typ) {
+			return typ
+		}
+	}
+	return des
+}
+
 func formatCandidates(prefix string, candidates []overloadImpl) string {
 	var buf bytes.Buffer
 	for _, candidate := range candidates {
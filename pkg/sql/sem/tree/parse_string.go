@@ -17,6 +17,8 @@ package tree
 import (
 	"time"
 
+	"github.com/pkg/errors"
+
 	"github.com/cockroachdb/cockroach/pkg/sql/coltypes"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
@@ -71,8 +73,9 @@ type locationContext interface {
 var _ locationContext = &EvalContext{}
 var _ locationContext = &SemaContext{}
 
-// parseStringAs parses s as type t for simple types. Bytes, arrays, collated
-// strings are not handled. nil, nil is returned if t is not a supported type.
+// parseStringAs parses s as type t for simple types and enums. Bytes, arrays,
+// collated strings are not handled. nil, nil is returned if t is not a
+// supported type.
 func parseStringAs(t types.T, s string, ctx locationContext) (Datum, error) {
 	switch t {
 	case types.BitArray:
@@ -104,6 +107,12 @@ func parseStringAs(t types.T, s string, ctx locationContext) (Datum, error) {
 	case types.UUID:
 		return ParseDUuidFromString(s)
 	default:
+		if e, ok := t.(types.TEnum); ok {
+			if e.IsAmbiguous() {
+				return nil, makeParseError(s, t, errors.New("the enum type is not known"))
+			}
+			return MakeDEnumFromLogicalRepresentation(e, s)
+		}
 		return nil, nil
 	}
 }
@@ -569,7 +569,11 @@ func (node *With) docRow(p *PrettyCfg) pretty.RLTableRow {
 			pretty.Bracket("AS (", p.Doc(cte.Stmt), ")"),
 		)
 	}
-	return p.row("WITH", pretty.Join(",", d...))
+	kw := "WITH"
+	if node.Recursive {
+		kw = "WITH RECURSIVE"
+	}
+	return p.row(kw, pretty.Join(",", d...))
 }
 
 func (node *Subquery) doc(p *PrettyCfg) pretty.Doc {
@@ -588,6 +592,12 @@ func (node *Subquery) doc(p *PrettyCfg) pretty.Doc {
 
 func (node *AliasedTableExpr) doc(p *PrettyCfg) pretty.Doc {
 	d := p.Doc(node.Expr)
+	if node.Lateral {
+		d = pretty.Concat(
+			pretty.Text("LATERAL "),
+			d,
+		)
+	}
 	if node.IndexFlags != nil {
 		d = pretty.Concat(
 			d,
@@ -45,6 +45,7 @@ type Select struct {
 	Select  SelectStatement
 	OrderBy OrderBy
 	Limit   *Limit
+	Locking LockingClause
 }
 
 // Format implements the NodeFormatter interface.
@@ -59,6 +60,10 @@ func (node *Select) Format(ctx *FmtCtx) {
 		ctx.WriteByte(' ')
 		ctx.FormatNode(node.Limit)
 	}
+	if len(node.Locking) > 0 {
+		ctx.WriteByte(' ')
+		ctx.FormatNode(&node.Locking)
+	}
 }
 
 // ParenSelect represents a parenthesized SELECT/UNION/VALUES statement.
@@ -332,11 +337,15 @@ type AliasedTableExpr struct {
 	Expr       TableExpr
 	IndexFlags *IndexFlags
 	Ordinality bool
+	Lateral    bool
 	As         AliasClause
 }
 
 // Format implements the NodeFormatter interface.
 func (node *AliasedTableExpr) Format(ctx *FmtCtx) {
+	if node.Lateral {
+		ctx.WriteString("LATERAL ")
+	}
 	ctx.FormatNode(node.Expr)
 	if node.IndexFlags != nil {
 		ctx.FormatNode(node.IndexFlags)
@@ -603,6 +612,99 @@ func (node *Limit) Format(ctx *FmtCtx) {
 	}
 }
 
+// LockingClause represents the locking clause of a SELECT statement, e.g.
+// FOR UPDATE OF t NOWAIT.
+type LockingClause []*LockingItem
+
+// Format implements the NodeFormatter interface.
+func (node *LockingClause) Format(ctx *FmtCtx) {
+	for i, n := range *node {
+		if i > 0 {
+			ctx.WriteByte(' ')
+		}
+		ctx.FormatNode(n)
+	}
+}
+
+// LockingItem represents a single locking item in a locking clause.
+type LockingItem struct {
+	Strength   LockingStrength
+	Targets    NormalizableTableNames
+	WaitPolicy LockingWaitPolicy
+}
+
+// Format implements the NodeFormatter interface.
+func (node *LockingItem) Format(ctx *FmtCtx) {
+	ctx.FormatNode(node.Strength)
+	if len(node.Targets) > 0 {
+		ctx.WriteString(" OF ")
+		ctx.FormatNode(&node.Targets)
+	}
+	ctx.FormatNode(node.WaitPolicy)
+}
+
+// LockingStrength represents the strength of the row-level locks acquired by
+// a locking item. The strengths are ordered from weakest to strongest.
+type LockingStrength byte
+
+// The possible locking strengths.
+const (
+	ForNone LockingStrength = iota
+	ForKeyShare
+	ForShare
+	ForNoKeyUpdate
+	ForUpdate
+)
+
+var lockingStrengthName = [...]string{
+	ForNone:        "",
+	ForKeyShare:    "FOR KEY SHARE",
+	ForShare:       "FOR SHARE",
+	ForNoKeyUpdate: "FOR NO KEY UPDATE",
+	ForUpdate:      "FOR UPDATE",
+}
+
+func (s LockingStrength) String() string {
+	return lockingStrengthName[s]
+}
+
+// Format implements the NodeFormatter interface.
+func (s LockingStrength) Format(ctx *FmtCtx) {
+	ctx.WriteString(s.String())
+}
+
+// LockingWaitPolicy represents what a locking item does when it runs into a
+// row that is locked by another transaction.
+type LockingWaitPolicy byte
+
+// The possible locking wait policies.
+const (
+	// LockWaitBlock waits for the conflicting lock to be released.
+	LockWaitBlock LockingWaitPolicy = iota
+	// LockWaitSkip skips the locked rows.
+	LockWaitSkip
+	// LockWaitError returns an error.
+	LockWaitError
+)
+
+var lockingWaitPolicyName = [...]string{
+	LockWaitBlock: "",
+	LockWaitSkip:  "SKIP LOCKED",
+	LockWaitError: "NOWAIT",
+}
+
+func (p LockingWaitPolicy) String() string {
+	return lockingWaitPolicyName[p]
+}
+
+// Format implements the NodeFormatter interface.
+func (p LockingWaitPolicy) Format(ctx *FmtCtx) {
+	if p != LockWaitBlock {
+		ctx.WriteByte(' ')
+		ctx.WriteString(p.String())
+	}
+}
+
 // RowsFromExpr represents a ROWS FROM(...) expression.
 type RowsFromExpr struct {
 	Items Exprs
@@ -185,6 +185,12 @@ func (*AlterSequence) StatementType() StatementType { return DDL }
 // StatementTag returns a short string identifying the type of statement.
 func (*AlterSequence) StatementTag() string { return "ALTER SEQUENCE" }
 
+// StatementType implements the Statement interface.
+func (*AlterTypeAddValue) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*AlterTypeAddValue) StatementTag() string { return "ALTER TYPE" }
+
 // StatementType implements the Statement interface.
 func (*AlterUserSetPassword) StatementType() StatementType { return RowsAffected }
 
@@ -251,6 +257,14 @@ func (*CreateChangefeed) StatementType() StatementType { return Rows }
 // StatementTag returns a short string identifying the type of statement.
 func (*CreateChangefeed) StatementTag() string { return "CREATE CHANGEFEED" }
 
+// StatementType implements the Statement interface.
+func (*ScheduledBackup) StatementType() StatementType { return Rows }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*ScheduledBackup) StatementTag() string { return "CREATE SCHEDULE FOR BACKUP" }
+
+func (*ScheduledBackup) hiddenFromShowQueries() {}
+
 // StatementType implements the Statement interface.
 func (*CreateDatabase) StatementType() StatementType { return DDL }
 
@@ -310,6 +324,12 @@ func (*CreateSequence) StatementType() StatementType { return DDL }
 // StatementTag returns a short string identifying the type of statement.
 func (*CreateSequence) StatementTag() string { return "CREATE SEQUENCE" }
 
+// StatementType implements the Statement interface.
+func (*CreateType) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreateType) StatementTag() string { return "CREATE TYPE" }
+
 // StatementType implements the Statement interface.
 func (*CreateStats) StatementType() StatementType { return DDL }
 
@@ -661,6 +681,14 @@ func (*ShowJobs) StatementTag() string { return "SHOW JOBS" }
 
 func (*ShowJobs) independentFromParallelizedPriors() {}
 
+// StatementType implements the Statement interface.
+func (*ShowSchedules) StatementType() StatementType { return Rows }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*ShowSchedules) StatementTag() string { return "SHOW SCHEDULES" }
+
+func (*ShowSchedules) independentFromParallelizedPriors() {}
+
 // StatementType implements the Statement interface.
 func (*ShowRoleGrants) StatementType() StatementType { return Rows }
 
@@ -796,6 +824,7 @@ func (n *AlterTableDropStored) String() string      { return AsString(n) }
 func (n *AlterTableSetDefault) String() string      { return AsString(n) }
 func (n *AlterUserSetPassword) String() string      { return AsString(n) }
 func (n *AlterSequence) String() string             { return AsString(n) }
+func (n *AlterTypeAddValue) String() string         { return AsString(n) }
 func (n *Backup) String() string                    { return AsString(n) }
 func (n *BeginTransaction) String() string          { return AsString(n) }
 func (n *ControlJobs) String() string               { return AsString(n) }
@@ -810,6 +839,7 @@ func (n *CreateRole) String() string                { return AsString(n) }
 func (n *CreateTable) String() string               { return AsString(n) }
 func (n *CreateSequence) String() string            { return AsString(n) }
 func (n *CreateStats) String() string               { return AsString(n) }
+func (n *CreateType) String() string                { return AsString(n) }
 func (n *CreateUser) String() string                { return AsString(n) }
 func (n *CreateView) String() string                { return AsString(n) }
 func (n *Deallocate) String() string                { return AsString(n) }
@@ -843,6 +873,7 @@ func (n *RollbackToSavepoint) String() string       { return AsString(n) }
 func (n *RollbackTransaction) String() string       { return AsString(n) }
 func (n *Savepoint) String() string                 { return AsString(n) }
 func (n *Scatter) String() string                   { return AsString(n) }
+func (n *ScheduledBackup) String() string           { return AsString(n) }
 func (n *Scrub) String() string                     { return AsString(n) }
 func (n *Select) String() string                    { return AsString(n) }
 func (n *SelectClause) String() string              { return AsString(n) }
@@ -866,6 +897,7 @@ func (n *ShowQueries) String() string               { return AsString(n) }
 func (n *ShowRanges) String() string                { return AsString(n) }
 func (n *ShowRoleGrants) String() string            { return AsString(n) }
 func (n *ShowRoles) String() string                 { return AsString(n) }
+func (n *ShowSchedules) String() string             { return AsString(n) }
 func (n *ShowSchemas) String() string               { return AsString(n) }
 func (n *ShowSessions) String() string              { return AsString(n) }
 func (n *ShowSyntax) String() string                { return AsString(n) }
@@ -177,19 +177,16 @@ func (node *RollbackTransaction) Format(ctx *FmtCtx) {
 	ctx.WriteString("ROLLBACK TRANSACTION")
 }
 
-// RestartSavepointName is the only savepoint name that we accept, modulo
-// capitalization.
+// RestartSavepointName is the magic savepoint name used to signal the intent
+// to retry a transaction, modulo capitalization.
 const RestartSavepointName string = "COCKROACH_RESTART"
 
-// ValidateRestartCheckpoint checks that a checkpoint name is our magic restart
-// value.
+// IsRestartSavepoint returns true if the savepoint name is our magic restart
+// value. Savepoints with any other name are regular, nestable savepoints.
 // We accept everything with the desired prefix because at least the C++ libpqxx
 // appends sequence numbers to the savepoint name specified by the user.
-func ValidateRestartCheckpoint(savepoint string) error {
-	if !strings.HasPrefix(strings.ToUpper(savepoint), RestartSavepointName) {
-		return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError, "SAVEPOINT not supported except for %s", RestartSavepointName)
-	}
-	return nil
+func IsRestartSavepoint(savepoint string) bool {
+	return strings.HasPrefix(strings.ToUpper(savepoint), RestartSavepointName)
 }
 
 // Savepoint represents a SAVEPOINT <name> statement.
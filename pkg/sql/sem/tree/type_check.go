@@ -62,9 +62,45 @@ type SemaContext struct {
 	// globally for the entire txn and this field would not be needed.
 	AsOfTimestamp *hlc.Timestamp
 
+	// TypeResolver is used to resolve the names of user-defined types. If it
+	// is nil, no user-defined type can be referenced.
+	TypeResolver TypeResolver
+
 	Properties SemaProperties
 }
 
+// TypeResolver resolves the names of user-defined types.
+type TypeResolver interface {
+	// ResolveType returns the column type of the user-defined type with the
+	// given name.
+	ResolveType(name *coltypes.TUserDefined) (coltypes.T, error)
+}
+
+// ResolveType returns the given column type, or the user-defined type it
+// references, as resolved by the TypeResolver of the SemaContext.
+func (sc *SemaContext) ResolveType(t coltypes.T) (coltypes.T, error) {
+	ud, ok := t.(*coltypes.TUserDefined)
+	if !ok {
+		return t, nil
+	}
+	if sc == nil || sc.TypeResolver == nil {
+		return nil, pgerror.NewErrorf(pgerror.CodeUndefinedObjectError,
+			"type %q does not exist", ud.Name)
+	}
+	return sc.TypeResolver.ResolveType(ud)
+}
+
+// resolveCastTargetType is like ResolveType, for cast target types.
+func (sc *SemaContext) resolveCastTargetType(
+	t coltypes.CastTargetType,
+) (coltypes.CastTargetType, error) {
+	ud, ok := t.(*coltypes.TUserDefined)
+	if !ok {
+		return t, nil
+	}
+	return sc.ResolveType(ud)
+}
+
 // SemaProperties is a holder for required and derived properties
 // during semantic analysis. It provides scoping semantics via its
 // Restore() method, see below.
@@ -397,6 +433,10 @@ func isCastDeepValid(castFrom, castTo types.T) bool {
 	if castTo.FamilyEqual(types.FamArray) && castFrom.FamilyEqual(types.FamArray) {
 		return isCastDeepValid(castFrom.(types.TArray).Typ, castTo.(types.TArray).Typ)
 	}
+	if castTo.FamilyEqual(types.FamEnum) && castFrom.FamilyEqual(types.FamEnum) {
+		// Values can't be cast between different enum types.
+		return castFrom.Equivalent(castTo)
+	}
 	for _, t := range validCastTypes(castTo) {
 		if castFrom.FamilyEqual(t) {
 			return true
@@ -407,6 +447,11 @@ func isCastDeepValid(castFrom, castTo types.T) bool {
 
 // TypeCheck implements the Expr interface.
 func (expr *CastExpr) TypeCheck(ctx *SemaContext, _ types.T) (TypedExpr, error) {
+	colType, err := ctx.resolveCastTargetType(expr.Type)
+	if err != nil {
+		return nil, err
+	}
+	expr.Type = colType
 	returnType := expr.castType()
 
 	// The desired type provided to a CastExpr is ignored. Instead,
@@ -425,7 +470,7 @@ func (expr *CastExpr) TypeCheck(ctx *SemaContext, _ types.T) (TypedExpr, error)
 			// precision), the CastExpr becomes a no-op and can be elided.
 			switch expr.Type.(type) {
 			case *coltypes.TBool, *coltypes.TDate, *coltypes.TTime, *coltypes.TTimestamp, *coltypes.TTimestampTZ,
-				*coltypes.TInterval, *coltypes.TBytes:
+				*coltypes.TInterval, *coltypes.TBytes, *coltypes.TEnum:
 				return expr.Expr.TypeCheck(ctx, returnType)
 			}
 		}
@@ -486,6 +531,11 @@ func (expr *IndirectionExpr) TypeCheck(ctx *SemaContext, desired types.T) (Typed
 
 // TypeCheck implements the Expr interface.
 func (expr *AnnotateTypeExpr) TypeCheck(ctx *SemaContext, desired types.T) (TypedExpr, error) {
+	colType, err := ctx.resolveCastTargetType(expr.Type)
+	if err != nil {
+		return nil, err
+	}
+	expr.Type = colType
 	annotType := expr.annotationType()
 	subExpr, err := typeCheckAndRequire(ctx, expr.Expr, annotType,
 		fmt.Sprintf("type annotation for %v as %s, found", expr.Expr, annotType))
@@ -1331,6 +1381,10 @@ func (d *DString) TypeCheck(_ *SemaContext, _ types.T) (TypedExpr, error) { retu
 // identity function for Datum.
 func (d *DCollatedString) TypeCheck(_ *SemaContext, _ types.T) (TypedExpr, error) { return d, nil }
 
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DEnum) TypeCheck(_ *SemaContext, _ types.T) (TypedExpr, error) { return d, nil }
+
 // TypeCheck implements the Expr interface. It is implemented as an idempotent
 // identity function for Datum.
 func (d *DBytes) TypeCheck(_ *SemaContext, _ types.T) (TypedExpr, error) { return d, nil }
@@ -1669,9 +1723,10 @@ func typeCheckComparisonOp(
 	// Throw a typing error if overload resolution found either no compatible candidates
 	// or if it found an ambiguity.
 	collationMismatch := leftReturn.FamilyEqual(types.FamCollatedString) && !leftReturn.Equivalent(rightReturn)
-	if len(fns) != 1 || collationMismatch {
+	enumMismatch := leftReturn.FamilyEqual(types.FamEnum) && !leftReturn.Equivalent(rightReturn)
+	if len(fns) != 1 || collationMismatch || enumMismatch {
 		sig := fmt.Sprintf(compSignatureFmt, leftReturn, op, rightReturn)
-		if len(fns) == 0 || collationMismatch {
+		if len(fns) == 0 || collationMismatch || enumMismatch {
 			return nil, nil, nil, false,
 				pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError, unsupportedCompErrFmt, sig)
 		}
@@ -2032,6 +2087,11 @@ type annotationState struct {
 func (v *placeholderAnnotationVisitor) VisitPre(expr Expr) (recurse bool, newExpr Expr) {
 	switch t := expr.(type) {
 	case *AnnotateTypeExpr:
+		if _, ok := t.Type.(*coltypes.TUserDefined); ok {
+			// The type is only known once its name is resolved during type
+			// checking, which types the placeholder.
+			return true, expr
+		}
 		if arg, ok := t.Expr.(*Placeholder); ok {
 			assertType := t.annotationType()
 			if state, ok := v.placeholders[arg.Name]; ok && state.sawAssertion {
@@ -2050,6 +2110,10 @@ func (v *placeholderAnnotationVisitor) VisitPre(expr Expr) (recurse bool, newExp
 			return false, expr
 		}
 	case *CastExpr:
+		if _, ok := t.Type.(*coltypes.TUserDefined); ok {
+			// See above.
+			return true, expr
+		}
 		if arg, ok := t.Expr.(*Placeholder); ok {
 			castType := t.castType()
 			if state, ok := v.placeholders[arg.Name]; ok {
@@ -652,6 +652,9 @@ func (expr *DString) Walk(_ Visitor) Expr { return expr }
 // Walk implements the Expr interface.
 func (expr *DCollatedString) Walk(_ Visitor) Expr { return expr }
 
+// Walk implements the Expr interface.
+func (expr *DEnum) Walk(_ Visitor) Expr { return expr }
+
 // Walk implements the Expr interface.
 func (expr *DTimestamp) Walk(_ Visitor) Expr { return expr }
 
@@ -16,7 +16,8 @@ package tree
 
 // With represents a WITH statement.
 type With struct {
-	CTEList []*CTE
+	Recursive bool
+	CTEList   []*CTE
 }
 
 // CTE represents a common table expression inside of a WITH clause.
@@ -31,6 +32,9 @@ func (node *With) Format(ctx *FmtCtx) {
 		return
 	}
 	ctx.WriteString("WITH ")
+	if node.Recursive {
+		ctx.WriteString("RECURSIVE ")
+	}
 	for i, cte := range node.CTEList {
 		if i != 0 {
 			ctx.WriteString(", ")
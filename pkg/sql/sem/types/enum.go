@@ -0,0 +1,142 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package types
+
+import (
+	"bytes"
+
+	"github.com/lib/pq/oid"
+)
+
+// UserDefinedTypeOidOffset is added to the ID of the descriptor of a
+// user-defined type to form its OID. It is well above the OIDs of the
+// predefined Postgres types, so that the two never collide.
+const UserDefinedTypeOidOffset = 100000
+
+// UserDefinedTypeOid returns the OID of the user-defined type with the given
+// descriptor ID.
+func UserDefinedTypeOid(id uint32) oid.Oid {
+	return oid.Oid(UserDefinedTypeOidOffset + id)
+}
+
+// IsUserDefinedTypeOid returns whether the OID is the OID of a user-defined
+// type, along with the ID of its descriptor.
+func IsUserDefinedTypeOid(o oid.Oid) (uint32, bool) {
+	if o <= UserDefinedTypeOidOffset {
+		return 0, false
+	}
+	return uint32(o) - UserDefinedTypeOidOffset, true
+}
+
+// EnumMember is a value of an enum type.
+type EnumMember struct {
+	// Logical is the label of the value, as written in SQL.
+	Logical string
+	// Physical is the encoding of the value. The physical representations
+	// of the members of an enum sort in the order of the members.
+	Physical []byte
+	// ReadOnly is set while a value added by ALTER TYPE ... ADD VALUE is not
+	// yet known to all the nodes, during which it can be read but not
+	// written.
+	ReadOnly bool
+}
+
+// TEnum is the type of a DEnum, a value of a user-defined enum type.
+type TEnum struct {
+	// ID is the ID of the descriptor of the type. The type family FamEnum has
+	// ID 0.
+	ID uint32
+	// Name is the name of the type.
+	Name string
+
+	// members is a pointer so that TEnum values remain comparable with ==.
+	// The slice it points to must not be modified.
+	members *[]EnumMember
+}
+
+// MakeEnum returns the enum type with the given ID, name and members, which
+// must be ordered by their physical representations. The members must not be
+// modified afterwards.
+func MakeEnum(id uint32, name string, members []EnumMember) TEnum {
+	return TEnum{ID: id, Name: name, members: &members}
+}
+
+// Members returns the members of the enum, in order. The returned slice must
+// not be modified.
+func (t TEnum) Members() []EnumMember {
+	if t.members == nil {
+		return nil
+	}
+	return *t.members
+}
+
+// MemberByLogical returns the index of the member with the given label, or
+// -1 if there is none.
+func (t TEnum) MemberByLogical(logical string) int {
+	for i, m := range t.Members() {
+		if m.Logical == logical {
+			return i
+		}
+	}
+	return -1
+}
+
+// MemberByPhysical returns the index of the member with the given physical
+// representation, or -1 if there is none.
+func (t TEnum) MemberByPhysical(physical []byte) int {
+	for i, m := range t.Members() {
+		if bytes.Equal(m.Physical, physical) {
+			return i
+		}
+	}
+	return -1
+}
+
+// String implements the fmt.Stringer interface.
+func (t TEnum) String() string {
+	if t.ID == 0 {
+		return "enum"
+	}
+	return t.Name
+}
+
+// Equivalent implements the T interface.
+func (t TEnum) Equivalent(other T) bool {
+	if other == Any {
+		return true
+	}
+	u, ok := UnwrapType(other).(TEnum)
+	if ok {
+		return t.ID == 0 || u.ID == 0 || t.ID == u.ID
+	}
+	return false
+}
+
+// FamilyEqual implements the T interface.
+func (TEnum) FamilyEqual(other T) bool {
+	_, ok := UnwrapType(other).(TEnum)
+	return ok
+}
+
+// Oid implements the T interface.
+func (t TEnum) Oid() oid.Oid { return UserDefinedTypeOid(t.ID) }
+
+// SQLName implements the T interface.
+func (t TEnum) SQLName() string { return t.String() }
+
+// IsAmbiguous implements the T interface.
+func (t TEnum) IsAmbiguous() bool {
+	return t.ID == 0
+}
@@ -108,6 +108,8 @@ var (
 	// FamCollatedString is the type family of a DString. CANNOT be
 	// compared with ==.
 	FamCollatedString T = TCollatedString{}
+	// FamEnum is the type family of a DEnum. CANNOT be compared with ==.
+	FamEnum T = TEnum{}
 	// FamTuple is the type family of a DTuple. CANNOT be compared with ==.
 	FamTuple T = TTuple{}
 	// FamArray is the type family of a DArray. CANNOT be compared with ==.
@@ -501,6 +503,10 @@ func IsStringType(t T) bool {
 // IsValidArrayElementType returns true if the T
 // can be used in TArray.
 func IsValidArrayElementType(t T) bool {
+	switch t.(type) {
+	case TEnum:
+		return false
+	}
 	switch t {
 	case JSON:
 		return false
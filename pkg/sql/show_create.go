@@ -189,7 +189,7 @@ func ShowCreateTable(
 		if idx.ID != desc.PrimaryIndex.ID {
 			// Showing the primary index is handled above.
 			f.WriteString(",\n\t")
-			f.WriteString(idx.SQLString(&sqlbase.AnonymousTable))
+			f.WriteString(desc.IndexSQLString(idx, &sqlbase.AnonymousTable))
 			// Showing the INTERLEAVE and PARTITION BY for the primary index are
 			// handled last.
 			if err := showCreateInterleave(ctx, idx, f.Buffer, dbPrefix, lCtx); err != nil {
@@ -206,7 +206,9 @@ func ShowCreateTable(
 	for _, fam := range desc.Families {
 		activeColumnNames := make([]string, 0, len(fam.ColumnNames))
 		for i, colID := range fam.ColumnIDs {
-			if _, err := desc.FindActiveColumnByID(colID); err == nil {
+			// The columns holding the values of index expressions are created
+			// along with the indexes.
+			if col, err := desc.FindActiveColumnByID(colID); err == nil && !col.IsIndexExpr() {
 				activeColumnNames = append(activeColumnNames, fam.ColumnNames[i])
 			}
 		}
@@ -0,0 +1,337 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/distsqlrun"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/storage/diskmap"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+)
+
+// This file contains containers used by planNodes which need to hold on to an
+// unbounded number of rows. They keep their contents in memory until their
+// memory account denies an allocation, at which point they move them to a
+// temporary disk map.
+
+// isOutOfMemoryError returns whether the error is the error returned when a
+// memory monitor denies an allocation.
+func isOutOfMemoryError(err error) bool {
+	pgErr, ok := pgerror.GetPGCause(err)
+	return ok && pgErr.Code == pgerror.CodeOutOfMemoryError
+}
+
+// tempStorage creates the disk maps of the containers of a planNode, and
+// monitors their disk usage.
+type tempStorage struct {
+	engine diskmap.Factory
+	// parentMon is the monitor of all the temporary storage of the node. mon
+	// is created from it when the containers first spill to disk.
+	parentMon *mon.BytesMonitor
+	mon       *mon.BytesMonitor
+	name      string
+}
+
+func makeTempStorage(execCfg *ExecutorConfig, name string) tempStorage {
+	return tempStorage{
+		engine:    execCfg.DistSQLSrv.TempStorage,
+		parentMon: execCfg.DistSQLSrv.DiskMonitor,
+		name:      name,
+	}
+}
+
+// newDiskMap returns a new disk map, along with an account for its disk usage.
+func (ts *tempStorage) newDiskMap(ctx context.Context) (diskmap.SortedDiskMap, mon.BoundAccount) {
+	if ts.mon == nil {
+		ts.mon = distsqlrun.NewMonitor(ctx, ts.parentMon, ts.name)
+	}
+	return ts.engine.NewSortedDiskMap(), ts.mon.MakeBoundAccount()
+}
+
+// close releases the monitor of the temporary storage. All the disk maps must
+// have been closed.
+func (ts *tempStorage) close(ctx context.Context) {
+	if ts.mon != nil {
+		ts.mon.Stop(ctx)
+		ts.mon = nil
+	}
+}
+
+// spillingRowBuffer is an append-only buffer of rows which are read back in
+// the order in which they were added.
+type spillingRowBuffer struct {
+	columns sqlbase.ResultColumns
+	storage *tempStorage
+	rows    *sqlbase.RowContainer
+
+	// disk is set once the buffer has spilled to disk; it then holds all the
+	// rows of the buffer. Its keys are the ordinals of the rows, so that they
+	// are iterated over in the order in which they were added.
+	disk    diskmap.SortedDiskMap
+	diskAcc mon.BoundAccount
+	numRows int
+	key     []byte
+	value   []byte
+	scratch []byte
+}
+
+// newSpillingRowBuffer returns a spillingRowBuffer for rows of the given
+// columns, which holds them in memory using the given account.
+func newSpillingRowBuffer(
+	acc mon.BoundAccount, columns sqlbase.ResultColumns, storage *tempStorage,
+) *spillingRowBuffer {
+	return &spillingRowBuffer{
+		columns: columns,
+		storage: storage,
+		rows:    sqlbase.NewRowContainer(acc, sqlbase.ColTypeInfoFromResCols(columns), 0),
+	}
+}
+
+// Len returns the number of rows in the buffer.
+func (b *spillingRowBuffer) Len() int {
+	return b.numRows
+}
+
+// AddRow adds a copy of the row to the buffer.
+func (b *spillingRowBuffer) AddRow(ctx context.Context, row tree.Datums) error {
+	if b.disk == nil {
+		_, err := b.rows.AddRow(ctx, row)
+		if err == nil {
+			b.numRows++
+			return nil
+		}
+		if !isOutOfMemoryError(err) {
+			return err
+		}
+		if err := b.spill(ctx); err != nil {
+			return err
+		}
+		log.VEventf(ctx, 2, "spilled to disk: %v", err)
+	}
+	return b.addDiskRow(ctx, row)
+}
+
+// spill moves the rows held in memory to a disk map.
+func (b *spillingRowBuffer) spill(ctx context.Context) error {
+	b.disk, b.diskAcc = b.storage.newDiskMap(ctx)
+	numRows := b.numRows
+	b.numRows = 0
+	for i := 0; i < numRows; i++ {
+		if err := b.addDiskRow(ctx, b.rows.At(i)); err != nil {
+			return err
+		}
+	}
+	b.rows.Clear(ctx)
+	return nil
+}
+
+func (b *spillingRowBuffer) addDiskRow(ctx context.Context, row tree.Datums) error {
+	b.key = encoding.EncodeUvarintAscending(b.key[:0], uint64(b.numRows))
+	b.value = b.value[:0]
+	for _, d := range row {
+		var err error
+		b.value, err = sqlbase.EncodeTableValue(
+			b.value, sqlbase.ColumnID(encoding.NoColumnID), d, b.scratch,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	if err := b.diskAcc.Grow(ctx, int64(len(b.key)+len(b.value))); err != nil {
+		return err
+	}
+	if err := b.disk.Put(b.key, b.value); err != nil {
+		return err
+	}
+	b.numRows++
+	return nil
+}
+
+// Clear removes all the rows from the buffer. Any iterator over the buffer
+// must have been closed.
+func (b *spillingRowBuffer) Clear(ctx context.Context) {
+	b.rows.Clear(ctx)
+	if b.disk != nil {
+		b.disk.Close(ctx)
+		b.disk = nil
+		b.diskAcc.Clear(ctx)
+	}
+	b.numRows = 0
+}
+
+// Close releases the resources of the buffer.
+func (b *spillingRowBuffer) Close(ctx context.Context) {
+	b.Clear(ctx)
+	b.rows.Close(ctx)
+}
+
+// NewIterator returns an iterator over the rows of the buffer. The buffer
+// must not be modified while the iterator is in use.
+func (b *spillingRowBuffer) NewIterator() *spillingRowBufferIterator {
+	it := &spillingRowBufferIterator{b: b, idx: -1}
+	if b.disk != nil {
+		it.diskIter = b.disk.NewIterator()
+		it.diskIter.Rewind()
+		it.row = make(tree.Datums, len(b.columns))
+	}
+	return it
+}
+
+// spillingRowBufferIterator iterates over the rows of a spillingRowBuffer.
+type spillingRowBufferIterator struct {
+	b        *spillingRowBuffer
+	idx      int
+	diskIter diskmap.SortedDiskMapIterator
+	row      tree.Datums
+	alloc    sqlbase.DatumAlloc
+}
+
+// Next advances the iterator to the next row, and returns false once there
+// are no more rows.
+func (i *spillingRowBufferIterator) Next() (bool, error) {
+	if i.diskIter == nil {
+		i.idx++
+		if i.idx >= i.b.Len() {
+			return false, nil
+		}
+		i.row = i.b.rows.At(i.idx)
+		return true, nil
+	}
+
+	if i.idx >= 0 {
+		i.diskIter.Next()
+	}
+	i.idx++
+	if ok, err := i.diskIter.Valid(); err != nil || !ok {
+		return false, err
+	}
+	b := i.diskIter.UnsafeValue()
+	for j := range i.row {
+		var err error
+		i.row[j], b, err = sqlbase.DecodeTableValue(&i.alloc, i.b.columns[j].Typ, b)
+		if err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// Row returns the current row of the iterator. It is only valid until the
+// next call to Next.
+func (i *spillingRowBufferIterator) Row() tree.Datums {
+	return i.row
+}
+
+// Close releases the resources of the iterator.
+func (i *spillingRowBufferIterator) Close() {
+	if i.diskIter != nil {
+		i.diskIter.Close()
+		i.diskIter = nil
+	}
+}
+
+// spillingRowSet is a set of encoded rows.
+type spillingRowSet struct {
+	storage *tempStorage
+	acc     mon.BoundAccount
+	rows    map[string]struct{}
+
+	// disk is set once the set has spilled to disk; it then holds all the rows
+	// of the set as its keys.
+	disk    diskmap.SortedDiskMap
+	diskAcc mon.BoundAccount
+}
+
+// presentValue is the value of the keys of the disk map of a spillingRowSet.
+// It isn't empty so that it can be told apart from a missing key.
+var presentValue = []byte{1}
+
+func makeSpillingRowSet(acc mon.BoundAccount, storage *tempStorage) spillingRowSet {
+	return spillingRowSet{
+		storage: storage,
+		acc:     acc,
+		rows:    make(map[string]struct{}),
+	}
+}
+
+// Add adds the encoded row to the set, and returns whether it wasn't already
+// in the set.
+func (s *spillingRowSet) Add(ctx context.Context, encoded []byte) (bool, error) {
+	if s.disk == nil {
+		// NB: the compiler optimizes out the string allocation in
+		// `myMap[string(myBytes)]`.
+		if _, ok := s.rows[string(encoded)]; ok {
+			return false, nil
+		}
+		err := s.acc.Grow(ctx, int64(len(encoded)))
+		if err == nil {
+			s.rows[string(encoded)] = struct{}{}
+			return true, nil
+		}
+		if !isOutOfMemoryError(err) {
+			return false, err
+		}
+		if err := s.spill(ctx); err != nil {
+			return false, err
+		}
+		log.VEventf(ctx, 2, "spilled to disk: %v", err)
+	}
+
+	v, err := s.disk.Get(encoded)
+	if err != nil {
+		return false, err
+	}
+	if v != nil {
+		return false, nil
+	}
+	return true, s.addDiskRow(ctx, encoded)
+}
+
+// spill moves the rows held in memory to a disk map.
+func (s *spillingRowSet) spill(ctx context.Context) error {
+	s.disk, s.diskAcc = s.storage.newDiskMap(ctx)
+	for k := range s.rows {
+		if err := s.addDiskRow(ctx, []byte(k)); err != nil {
+			return err
+		}
+	}
+	s.rows = nil
+	s.acc.Clear(ctx)
+	return nil
+}
+
+func (s *spillingRowSet) addDiskRow(ctx context.Context, encoded []byte) error {
+	if err := s.diskAcc.Grow(ctx, int64(len(encoded)+len(presentValue))); err != nil {
+		return err
+	}
+	return s.disk.Put(encoded, presentValue)
+}
+
+// Close releases the resources of the set.
+func (s *spillingRowSet) Close(ctx context.Context) {
+	s.rows = nil
+	s.acc.Close(ctx)
+	if s.disk != nil {
+		s.disk.Close(ctx)
+		s.disk = nil
+		s.diskAcc.Close(ctx)
+	}
+}
@@ -131,6 +131,11 @@ func EncodeTableKey(b []byte, val tree.Datum, dir encoding.Direction) ([]byte, e
 			return encoding.EncodeBytesAscending(b, data), nil
 		}
 		return encoding.EncodeBytesDescending(b, data), nil
+	case *tree.DEnum:
+		if dir == encoding.Ascending {
+			return encoding.EncodeBytesAscending(b, t.Physical), nil
+		}
+		return encoding.EncodeBytesDescending(b, t.Physical), nil
 	case *tree.DTuple:
 		for _, datum := range t.D {
 			var err error
@@ -333,6 +338,18 @@ func DecodeTableKey(
 				return nil, nil, err
 			}
 			return tree.NewDCollatedString(r, t.Locale, &a.env), rkey, err
+		case types.TEnum:
+			var r []byte
+			if dir == encoding.Ascending {
+				rkey, r, err = encoding.DecodeBytesAscending(key, nil)
+			} else {
+				rkey, r, err = encoding.DecodeBytesDescending(key, nil)
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+			d, err := tree.MakeDEnumFromPhysicalRepresentation(t, r)
+			return d, rkey, err
 		}
 		return nil, nil, errors.Errorf("TODO(pmattis): decoded index key: %s", valType)
 	}
@@ -402,6 +419,8 @@ func EncodeTableValue(
 		return encoding.EncodeBytesValue(appendTo, uint32(colID), []byte(t.Contents)), nil
 	case *tree.DOid:
 		return encoding.EncodeIntValue(appendTo, uint32(colID), int64(t.DInt)), nil
+	case *tree.DEnum:
+		return encoding.EncodeBytesValue(appendTo, uint32(colID), t.Physical), nil
 	}
 	return nil, errors.Errorf("unable to encode table value: %T", val)
 }
@@ -539,6 +558,13 @@ func decodeUntaggedDatum(a *DatumAlloc, t types.T, buf []byte) (tree.Datum, []by
 			return decodeArray(a, typ.Typ, buf)
 		case types.TTuple:
 			return decodeTuple(a, typ, buf)
+		case types.TEnum:
+			b, data, err := encoding.DecodeUntaggedBytesValue(buf)
+			if err != nil {
+				return nil, b, err
+			}
+			d, err := tree.MakeDEnumFromPhysicalRepresentation(typ, data)
+			return d, b, err
 		}
 		return nil, buf, errors.Errorf("couldn't decode type %s", t)
 	}
@@ -606,6 +632,13 @@ func CheckColumnValueType(valType types.T, colType ColumnType, colName string) e
 				return NewMismatchedLocaleError(t.Locale, *colType.Locale, colName)
 			}
 		}
+	case ColumnType_ENUM:
+		// The members of the enum in the value's type may be more recent than
+		// those of the column's copy; only the identity of the enum matters.
+		if t, ok := valType.(types.TEnum); ok && ID(t.ID) == colType.enumTypeID() {
+			return nil
+		}
+		return NewMismatchedTypeError(valType, colType.SemanticType, colName)
 	}
 	valColType, err := DatumTypeToColumnType(valType)
 	if err != nil {
@@ -741,6 +774,11 @@ func MarshalColumnValue(col ColumnDescriptor, val tree.Datum) (roachpb.Value, er
 			r.SetInt(int64(v.DInt))
 			return r, nil
 		}
+	case ColumnType_ENUM:
+		if v, ok := val.(*tree.DEnum); ok && ID(v.Typ.ID) == col.Type.enumTypeID() {
+			r.SetBytes(v.Physical)
+			return r, nil
+		}
 	default:
 		return r, errors.Errorf("unsupported column type: %s", col.Type.SemanticType)
 	}
@@ -870,6 +908,12 @@ func UnmarshalColumnValue(a *DatumAlloc, typ ColumnType, value roachpb.Value) (t
 			return nil, err
 		}
 		return a.NewDOid(tree.MakeDOid(tree.DInt(v))), nil
+	case ColumnType_ENUM:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		return tree.MakeDEnumFromPhysicalRepresentation(typ.ToDatumType().(types.TEnum), v)
 	default:
 		return nil, errors.Errorf("unsupported column type: %s", typ.SemanticType)
 	}
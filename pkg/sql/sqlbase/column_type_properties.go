@@ -94,6 +94,12 @@ func DatumTypeToColumnType(ptyp types.T) (ColumnType, error) {
 		}
 		ctyp.TupleLabels = t.Labels
 		return ctyp, nil
+	case types.TEnum:
+		if t.IsAmbiguous() {
+			return ColumnType{}, pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+				"unsupported result type: %s", t)
+		}
+		return MakeEnumColumnType(t), nil
 	default:
 		semanticType, err := datumTypeToColumnSemanticType(ptyp)
 		if err != nil {
@@ -176,6 +182,10 @@ func PopulateTypeAttrs(base ColumnType, typ coltypes.T) (ColumnType, error) {
 			return ColumnType{}, errors.Errorf("vectors of type %s are unsupported", t.ParamType)
 		}
 
+	case *coltypes.TEnum:
+		// The ColumnType of an enum is fully populated by
+		// DatumTypeToColumnType.
+
 	case *coltypes.TBool:
 	case *coltypes.TBytes:
 	case *coltypes.TDate:
@@ -291,6 +301,8 @@ func (c *ColumnType) SQLString() string {
 		}
 	case ColumnType_ARRAY:
 		return c.elementColumnType().SQLString() + "[]"
+	case ColumnType_ENUM:
+		return tree.NameString(*c.EnumTypeName)
 	}
 	if c.VisibleType != ColumnType_NONE {
 		return c.VisibleType.String()
@@ -359,6 +371,8 @@ func (c *ColumnType) InformationSchemaVisibleType() string {
 		return "record"
 	case ColumnType_ARRAY:
 		return "ARRAY"
+	case ColumnType_ENUM:
+		return "USER-DEFINED"
 	}
 
 	// The name of the remaining semantic type constants are suitable
@@ -542,6 +556,9 @@ func datumTypeToColumnSemanticType(ptyp types.T) (ColumnType_SemanticType, error
 		if ptyp.FamilyEqual(types.FamTuple) {
 			return ColumnType_TUPLE, nil
 		}
+		if ptyp.FamilyEqual(types.FamEnum) {
+			return ColumnType_ENUM, nil
+		}
 		if wrapper, ok := ptyp.(types.TOidWrapper); ok {
 			return datumTypeToColumnSemanticType(wrapper.T)
 		}
@@ -601,6 +618,8 @@ func columnSemanticTypeToDatumType(c *ColumnType, k ColumnType_SemanticType) typ
 		return types.IntVector
 	case ColumnType_OIDVECTOR:
 		return types.OidVector
+	case ColumnType_ENUM:
+		return c.enumDatumType()
 	}
 	return nil
 }
@@ -698,6 +717,10 @@ func CheckValueWidth(typ ColumnType, val tree.Datum, name string) error {
 				}
 			}
 		}
+	case ColumnType_ENUM:
+		if v, ok := val.(*tree.DEnum); ok {
+			return checkEnumValueWritable(typ, v, name)
+		}
 	}
 	return nil
 }
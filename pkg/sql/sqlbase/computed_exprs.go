@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/transform"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
 )
 
 // RowIndexedVarContainer is used to evaluate expressions over various rows.
@@ -195,3 +196,81 @@ func MakeComputedExprs(
 	}
 	return computedExprs, nil
 }
+
+// makeVirtualColumnExpr returns the expression computing the values of the
+// given virtual column from a row of the given columns, along with the
+// ordinals of the columns it depends on. The column references of the
+// expression are replaced by ordinal references into cols, so it must be
+// evaluated with an IndexedVarContainer over such rows.
+func makeVirtualColumnExpr(
+	col *ColumnDescriptor, cols []ColumnDescriptor,
+) (tree.TypedExpr, util.FastIntSet, error) {
+	var deps util.FastIntSet
+	expr, err := parser.ParseExpr(*col.ComputeExpr)
+	if err != nil {
+		return nil, deps, err
+	}
+	expr, err = tree.SimpleVisit(expr, func(expr tree.Expr) (err error, recurse bool, newExpr tree.Expr) {
+		vBase, ok := expr.(tree.VarName)
+		if !ok {
+			return nil, true, expr
+		}
+		v, err := vBase.NormalizeVarName()
+		if err != nil {
+			return err, false, nil
+		}
+		c, ok := v.(*tree.ColumnItem)
+		if !ok {
+			return nil, true, expr
+		}
+		for i := range cols {
+			if cols[i].Name == string(c.ColumnName) {
+				deps.Add(i)
+				return nil, false, tree.NewOrdinalReference(i)
+			}
+		}
+		return NewUndefinedColumnError(string(c.ColumnName)), false, nil
+	})
+	if err != nil {
+		return nil, deps, err
+	}
+
+	semaCtx := tree.MakeSemaContext(false)
+	semaCtx.IVarContainer = &descContainer{cols}
+	typedExpr, err := tree.TypeCheck(expr, &semaCtx, col.Type.ToDatumType())
+	if err != nil {
+		return nil, deps, err
+	}
+	return typedExpr, deps, nil
+}
+
+// virtualColContainer is used to evaluate the expressions of virtual columns
+// over the rows decoded by a RowFetcher.
+type virtualColContainer struct {
+	cols  []ColumnDescriptor
+	row   EncDatumRow
+	alloc *DatumAlloc
+}
+
+var _ tree.IndexedVarContainer = &virtualColContainer{}
+
+// IndexedVarEval implements tree.IndexedVarContainer.
+func (c *virtualColContainer) IndexedVarEval(idx int, ctx *tree.EvalContext) (tree.Datum, error) {
+	if c.row[idx].IsUnset() {
+		return tree.DNull, nil
+	}
+	if err := c.row[idx].EnsureDecoded(&c.cols[idx].Type, c.alloc); err != nil {
+		return nil, err
+	}
+	return c.row[idx].Datum, nil
+}
+
+// IndexedVarResolvedType implements tree.IndexedVarContainer.
+func (c *virtualColContainer) IndexedVarResolvedType(idx int) types.T {
+	return c.cols[idx].Type.ToDatumType()
+}
+
+// IndexedVarNodeFormatter implements tree.IndexedVarContainer.
+func (*virtualColContainer) IndexedVarNodeFormatter(idx int) tree.NodeFormatter {
+	return nil
+}
@@ -0,0 +1,320 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sqlbase
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// SetID implements the DescriptorProto interface.
+func (desc *TypeDescriptor) SetID(id ID) {
+	desc.ID = id
+}
+
+// TypeName returns the plain type of this descriptor.
+func (desc *TypeDescriptor) TypeName() string {
+	return "type"
+}
+
+// SetName implements the DescriptorProto interface.
+func (desc *TypeDescriptor) SetName(name string) {
+	desc.Name = name
+}
+
+// GetAuditMode is part of the DescriptorProto interface.
+// Types are not audited.
+func (desc *TypeDescriptor) GetAuditMode() TableDescriptor_AuditMode {
+	return TableDescriptor_DISABLED
+}
+
+// Validate validates that the type descriptor is well formed: its members
+// must have distinct labels, and be ordered by their physical
+// representations.
+func (desc *TypeDescriptor) Validate() error {
+	if err := validateName(desc.Name, "type"); err != nil {
+		return err
+	}
+	if desc.ID == 0 {
+		return fmt.Errorf("invalid type ID %d", desc.ID)
+	}
+	if desc.ParentID == 0 {
+		return fmt.Errorf("invalid parent ID %d", desc.ParentID)
+	}
+	labels := make(map[string]struct{}, len(desc.EnumMembers))
+	for i := range desc.EnumMembers {
+		m := &desc.EnumMembers[i]
+		if _, ok := labels[m.LogicalRepresentation]; ok {
+			return fmt.Errorf("duplicate enum label %q", m.LogicalRepresentation)
+		}
+		labels[m.LogicalRepresentation] = struct{}{}
+		if len(m.PhysicalRepresentation) == 0 {
+			return fmt.Errorf("enum label %q has no physical representation", m.LogicalRepresentation)
+		}
+		if i > 0 && bytes.Compare(desc.EnumMembers[i-1].PhysicalRepresentation, m.PhysicalRepresentation) >= 0 {
+			return fmt.Errorf("enum labels %q and %q are not ordered by their physical representations",
+				desc.EnumMembers[i-1].LogicalRepresentation, m.LogicalRepresentation)
+		}
+	}
+	return desc.Privileges.Validate(desc.GetID())
+}
+
+// DatumType returns the types.TEnum of the values of the type.
+func (desc *TypeDescriptor) DatumType() types.TEnum {
+	return makeEnumDatumType(desc.ID, desc.Name, desc.EnumMembers)
+}
+
+// MakeEnumColumnType returns the ColumnType of a column of the given enum
+// type.
+func MakeEnumColumnType(t types.TEnum) ColumnType {
+	id := ID(t.ID)
+	name := t.Name
+	members := make([]TypeDescriptor_EnumMember, len(t.Members()))
+	for i, m := range t.Members() {
+		members[i] = TypeDescriptor_EnumMember{
+			PhysicalRepresentation: m.Physical,
+			LogicalRepresentation:  m.Logical,
+			ReadOnly:               m.ReadOnly,
+		}
+	}
+	return ColumnType{
+		SemanticType: ColumnType_ENUM,
+		EnumTypeID:   &id,
+		EnumTypeName: &name,
+		EnumMembers:  members,
+	}
+}
+
+// enumTypeID returns the ID of the TypeDescriptor of an ENUM column type, or 0
+// if the column type is not an enum.
+func (c *ColumnType) enumTypeID() ID {
+	if c.EnumTypeID == nil {
+		return 0
+	}
+	return *c.EnumTypeID
+}
+
+// enumDatumType returns the types.TEnum of the values of an ENUM column type.
+func (c *ColumnType) enumDatumType() types.TEnum {
+	if c.EnumTypeID == nil || c.EnumTypeName == nil {
+		panic("enum type ID and name are required for ENUM")
+	}
+	return makeEnumDatumType(*c.EnumTypeID, *c.EnumTypeName, c.EnumMembers)
+}
+
+// enumDatumTypes interns the types.TEnum values created from descriptors.
+// types.T values are compared with ==, and the members of a types.TEnum are
+// compared by pointer; interning ensures that two enum types with the same
+// members are equal.
+var enumDatumTypes struct {
+	syncutil.Mutex
+	m map[string]types.TEnum
+}
+
+func makeEnumDatumType(id ID, name string, members []TypeDescriptor_EnumMember) types.TEnum {
+	var key strings.Builder
+	fmt.Fprintf(&key, "%d/%q", id, name)
+	for i := range members {
+		fmt.Fprintf(&key, "/%x/%q/%t", members[i].PhysicalRepresentation,
+			members[i].LogicalRepresentation, members[i].ReadOnly)
+	}
+
+	enumDatumTypes.Lock()
+	defer enumDatumTypes.Unlock()
+	if t, ok := enumDatumTypes.m[key.String()]; ok {
+		return t
+	}
+	enumMembers := make([]types.EnumMember, len(members))
+	for i := range members {
+		enumMembers[i] = types.EnumMember{
+			Logical:  members[i].LogicalRepresentation,
+			Physical: members[i].PhysicalRepresentation,
+			ReadOnly: members[i].ReadOnly,
+		}
+	}
+	t := types.MakeEnum(uint32(id), name, enumMembers)
+	if enumDatumTypes.m == nil {
+		enumDatumTypes.m = make(map[string]types.TEnum)
+	}
+	enumDatumTypes.m[key.String()] = t
+	return t
+}
+
+// checkEnumValueWritable returns an error if the value is a member of the enum
+// column type which cannot be written yet.
+func checkEnumValueWritable(typ ColumnType, val *tree.DEnum, name string) error {
+	for i := range typ.EnumMembers {
+		m := &typ.EnumMembers[i]
+		if !bytes.Equal(m.PhysicalRepresentation, val.Physical) {
+			continue
+		}
+		if m.ReadOnly {
+			return pgerror.NewErrorf(pgerror.CodeObjectNotInPrerequisiteStateError,
+				"enum value %q is not yet available for writing (column %q)", m.LogicalRepresentation, name)
+		}
+		return nil
+	}
+	return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+		"invalid value %q for enum %s (column %q)", val.Logical, *typ.EnumTypeName, name)
+}
+
+// CheckNoUserDefinedTypeReferences returns an error if the expression names a
+// user-defined type. Expressions stored in descriptors are parsed again
+// without a way to resolve those names.
+func CheckNoUserDefinedTypeReferences(expr tree.Expr, context string) error {
+	_, err := tree.SimpleVisit(expr, func(expr tree.Expr) (err error, recurse bool, newExpr tree.Expr) {
+		var t coltypes.CastTargetType
+		switch e := expr.(type) {
+		case *tree.CastExpr:
+			t = e.Type
+		case *tree.AnnotateTypeExpr:
+			t = e.Type
+		default:
+			return nil, true, expr
+		}
+		switch t.(type) {
+		case *coltypes.TUserDefined, *coltypes.TEnum:
+			return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+				"user-defined types cannot be referenced in %s", context), false, expr
+		}
+		return nil, true, expr
+	})
+	return err
+}
+
+// GenerateEnumPhysicalRepresentations returns the physical representations of
+// the n members of a new enum type. They are spread evenly over the space of
+// byte strings of the smallest length that fits them, so that members added
+// later by GenByteStringBetween stay short.
+func GenerateEnumPhysicalRepresentations(n int) [][]byte {
+	width := 1
+	for space := uint64(256); space <= uint64(n+1); space *= 256 {
+		width++
+	}
+	space := uint64(1) << (8 * uint(width))
+	result := make([][]byte, n)
+	for i := range result {
+		v := uint64(i+1) * (space / uint64(n+1))
+		b := make([]byte, width)
+		for j := width - 1; j >= 0; j-- {
+			b[j] = byte(v)
+			v >>= 8
+		}
+		// Trailing zero bytes are stripped; see GenByteStringBetween.
+		result[i] = bytes.TrimRight(b, "\x00")
+	}
+	return result
+}
+
+// GenByteStringBetween returns a byte string which sorts strictly between
+// prev and next. A nil prev sorts before all the byte strings, and a nil next
+// after all of them. None of the inputs or outputs end with a zero byte, which
+// guarantees that there is always room between two distinct byte strings.
+func GenByteStringBetween(prev, next []byte) ([]byte, error) {
+	if next != nil && bytes.Compare(prev, next) >= 0 {
+		return nil, errors.Errorf("%x does not sort before %x", prev, next)
+	}
+	var result []byte
+	for i := 0; ; i++ {
+		lo := 0
+		if i < len(prev) {
+			lo = int(prev[i])
+		}
+		hi := 256
+		if next != nil {
+			// next cannot be exhausted while it still constrains the result,
+			// since prev sorts before it.
+			hi = int(next[i])
+		}
+		if hi-lo > 1 {
+			return append(result, byte((lo+hi)/2)), nil
+		}
+		result = append(result, byte(lo))
+		if hi-lo == 1 {
+			// The result now sorts before next whatever its remaining bytes.
+			next = nil
+		}
+	}
+}
+
+// forEachEnumColumnType calls fn on the type of every column of the table,
+// including the columns being added or dropped, which is an enum type.
+func (desc *TableDescriptor) forEachEnumColumnType(fn func(*ColumnType)) {
+	for i := range desc.Columns {
+		if t := &desc.Columns[i].Type; t.SemanticType == ColumnType_ENUM {
+			fn(t)
+		}
+	}
+	for i := range desc.Mutations {
+		if col := desc.Mutations[i].GetColumn(); col != nil && col.Type.SemanticType == ColumnType_ENUM {
+			fn(&col.Type)
+		}
+	}
+}
+
+// AddEnumMember adds a member to the copies of the members of the enum type
+// with the given ID held by the columns of the table. The member is added as
+// read-only: it only becomes writable once the schema changer knows that all
+// the nodes can decode it. Returns whether any column of the table has the
+// enum type.
+func (desc *TableDescriptor) AddEnumMember(typeID ID, member TypeDescriptor_EnumMember) bool {
+	member.ReadOnly = true
+	found := false
+	desc.forEachEnumColumnType(func(t *ColumnType) {
+		if t.enumTypeID() != typeID {
+			return
+		}
+		found = true
+		i := sort.Search(len(t.EnumMembers), func(i int) bool {
+			return bytes.Compare(t.EnumMembers[i].PhysicalRepresentation, member.PhysicalRepresentation) >= 0
+		})
+		t.EnumMembers = append(t.EnumMembers, TypeDescriptor_EnumMember{})
+		copy(t.EnumMembers[i+1:], t.EnumMembers[i:])
+		t.EnumMembers[i] = member
+	})
+	return found
+}
+
+// HasReadOnlyEnumMembers returns true if a column of the table has an enum
+// type with a member which cannot be written yet.
+func (desc *TableDescriptor) HasReadOnlyEnumMembers() bool {
+	found := false
+	desc.forEachEnumColumnType(func(t *ColumnType) {
+		for i := range t.EnumMembers {
+			found = found || t.EnumMembers[i].ReadOnly
+		}
+	})
+	return found
+}
+
+// MakeEnumMembersWritable makes all the members of the enum types of the
+// columns of the table writable.
+func (desc *TableDescriptor) MakeEnumMembersWritable() {
+	desc.forEachEnumColumnType(func(t *ColumnType) {
+		for i := range t.EnumMembers {
+			t.EnumMembers[i].ReadOnly = false
+		}
+	})
+}
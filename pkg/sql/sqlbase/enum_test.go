@@ -0,0 +1,101 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sqlbase
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+)
+
+func TestGenerateEnumPhysicalRepresentations(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	for _, n := range []int{0, 1, 2, 10, 254, 255, 256, 1000, 70000} {
+		reps := GenerateEnumPhysicalRepresentations(n)
+		if len(reps) != n {
+			t.Fatalf("%d: expected %d representations, got %d", n, n, len(reps))
+		}
+		for i, r := range reps {
+			if len(r) == 0 || r[len(r)-1] == 0 {
+				t.Fatalf("%d: invalid representation %x", n, r)
+			}
+			if i > 0 && bytes.Compare(reps[i-1], r) >= 0 {
+				t.Fatalf("%d: %x does not sort before %x", n, reps[i-1], r)
+			}
+		}
+	}
+}
+
+func TestGenByteStringBetween(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	testCases := []struct {
+		prev, next []byte
+	}{
+		{nil, nil},
+		{nil, []byte{1}},
+		{nil, []byte{0, 1}},
+		{[]byte{255}, nil},
+		{[]byte{255, 255}, nil},
+		{[]byte{1}, []byte{2}},
+		{[]byte{1}, []byte{1, 1}},
+		{[]byte{1, 255}, []byte{2}},
+		{[]byte{1, 255, 255}, []byte{2, 0, 1}},
+		{[]byte{128}, []byte{128, 0, 0, 1}},
+	}
+	for _, tc := range testCases {
+		r, err := GenByteStringBetween(tc.prev, tc.next)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(r) == 0 || r[len(r)-1] == 0 {
+			t.Errorf("%x, %x: invalid result %x", tc.prev, tc.next, r)
+		}
+		if bytes.Compare(tc.prev, r) >= 0 || (tc.next != nil && bytes.Compare(r, tc.next) >= 0) {
+			t.Errorf("%x, %x: %x does not sort between them", tc.prev, tc.next, r)
+		}
+	}
+
+	if _, err := GenByteStringBetween([]byte{2}, []byte{1}); err == nil {
+		t.Error("expected an error for out of order inputs")
+	}
+
+	// Repeatedly inserting values at random positions keeps them ordered.
+	rng, _ := randutil.NewPseudoRand()
+	reps := GenerateEnumPhysicalRepresentations(3)
+	for i := 0; i < 1000; i++ {
+		pos := rng.Intn(len(reps) + 1)
+		var prev, next []byte
+		if pos > 0 {
+			prev = reps[pos-1]
+		}
+		if pos < len(reps) {
+			next = reps[pos]
+		}
+		r, err := GenByteStringBetween(prev, next)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reps = append(reps, nil)
+		copy(reps[pos+1:], reps[pos:])
+		reps[pos] = r
+	}
+	for i := 1; i < len(reps); i++ {
+		if bytes.Compare(reps[i-1], reps[i]) >= 0 {
+			t.Fatalf("%x does not sort before %x", reps[i-1], reps[i])
+		}
+	}
+}
@@ -153,6 +153,10 @@ type txnKVFetcher struct {
 	// returnRangeInfo, if set, causes the kvFetcher to populate rangeInfos.
 	// See also rowFetcher.returnRangeInfo.
 	returnRangeInfo bool
+	// lockStrength and lockWaitPolicy, if set, make the scans acquire locks
+	// over the keys they read. See also RowFetcher.SetLocking.
+	lockStrength   roachpb.KeyLockingStrength
+	lockWaitPolicy roachpb.LockWaitPolicy
 
 	fetchEnd  bool
 	batchIdx  int
@@ -237,6 +241,8 @@ func makeKVFetcher(
 	useBatchLimit bool,
 	firstBatchLimit int64,
 	returnRangeInfo bool,
+	lockStrength roachpb.KeyLockingStrength,
+	lockWaitPolicy roachpb.LockWaitPolicy,
 ) (txnKVFetcher, error) {
 	if firstBatchLimit < 0 || (!useBatchLimit && firstBatchLimit != 0) {
 		return txnKVFetcher{}, errors.Errorf("invalid batch limit %d (useBatchLimit: %t)",
@@ -270,6 +276,8 @@ func makeKVFetcher(
 		useBatchLimit:   useBatchLimit,
 		firstBatchLimit: firstBatchLimit,
 		returnRangeInfo: returnRangeInfo,
+		lockStrength:    lockStrength,
+		lockWaitPolicy:  lockWaitPolicy,
 	}, nil
 }
 
@@ -278,11 +286,13 @@ func (f *txnKVFetcher) fetch(ctx context.Context) error {
 	var ba roachpb.BatchRequest
 	ba.Header.MaxSpanRequestKeys = f.getBatchSize()
 	ba.Header.ReturnRangeInfo = f.returnRangeInfo
+	ba.Header.WaitPolicy = f.lockWaitPolicy
 	ba.Requests = make([]roachpb.RequestUnion, len(f.spans))
 	if f.reverse {
 		scans := make([]roachpb.ReverseScanRequest, len(f.spans))
 		for i := range f.spans {
 			scans[i].ScanFormat = roachpb.BATCH_RESPONSE
+			scans[i].KeyLocking = f.lockStrength
 			scans[i].SetSpan(f.spans[i])
 			ba.Requests[i].MustSetInner(&scans[i])
 		}
@@ -290,6 +300,7 @@ func (f *txnKVFetcher) fetch(ctx context.Context) error {
 		scans := make([]roachpb.ScanRequest, len(f.spans))
 		for i := range f.spans {
 			scans[i].ScanFormat = roachpb.BATCH_RESPONSE
+			scans[i].KeyLocking = f.lockStrength
 			scans[i].SetSpan(f.spans[i])
 			ba.Requests[i].MustSetInner(&scans[i])
 		}
@@ -27,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/scrub"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
@@ -87,6 +88,14 @@ type tableInfo struct {
 	// index (into cols); -1 if we don't need the value for that column.
 	indexColIdx []int
 
+	// The indexes (into cols) of the needed virtual columns, and the
+	// expressions computing their values. These are only set when scanning the
+	// primary index, which does not store the values of virtual columns.
+	virtualCols  []int
+	virtualExprs []tree.TypedExpr
+	// virtualIVars is used to evaluate virtualExprs over row.
+	virtualIVars *virtualColContainer
+
 	// -- Fields updated during a scan --
 
 	keyValTypes []ColumnType
@@ -204,6 +213,11 @@ type RowFetcher struct {
 	// If set, GetRangeInfo() can be used to retrieve the accumulated info.
 	returnRangeInfo bool
 
+	// lockStrength and lockWaitPolicy control the locks acquired by the scans
+	// of the fetcher. See SetLocking.
+	lockStrength   roachpb.KeyLockingStrength
+	lockWaitPolicy roachpb.LockWaitPolicy
+
 	// traceKV indicates whether or not session tracing is enabled. It is set
 	// when beginning a new scan.
 	traceKV bool
@@ -237,6 +251,11 @@ type RowFetcher struct {
 
 	// Buffered allocation of decoded datums.
 	alloc *DatumAlloc
+
+	// virtualEvalCtx is used to compute the values of virtual columns. The
+	// expressions of computed columns are pure, so they do not depend on the
+	// session.
+	virtualEvalCtx tree.EvalContext
 }
 
 // Init sets up a RowFetcher for a given table and index. If we are using a
@@ -304,10 +323,20 @@ func (rf *RowFetcher) Init(
 			table.equivSignature = equivSignatures[len(equivSignatures)-1]
 		}
 
+		// The values of virtual columns are computed from the columns they
+		// depend on, which are thus needed as well.
+		valNeededForCol := tableArgs.ValNeededForCol
+		if !table.isSecondaryIndex {
+			valNeededForCol, err = table.initVirtualCols(valNeededForCol, alloc)
+			if err != nil {
+				return err
+			}
+		}
+
 		// Scan through the entire columns map to see which columns are
 		// required.
 		for col, idx := range table.colIdxMap {
-			if tableArgs.ValNeededForCol.Contains(idx) {
+			if valNeededForCol.Contains(idx) {
 				// The idx-th column is required.
 				table.neededCols.Add(int(col))
 			}
@@ -318,7 +347,7 @@ func (rf *RowFetcher) Init(
 		var indexColumnIDs []ColumnID
 		indexColumnIDs, table.indexColumnDirs = table.index.FullColumnIDs()
 
-		table.neededValueColsByIdx = tableArgs.ValNeededForCol.Copy()
+		table.neededValueColsByIdx = valNeededForCol.Copy()
 		neededIndexCols := 0
 		table.indexColIdx = make([]int, len(indexColumnIDs))
 		for i, id := range indexColumnIDs {
@@ -350,8 +379,9 @@ func (rf *RowFetcher) Init(
 
 		// The number of columns we need to read from the value part of the key.
 		// It's the total number of needed columns minus the ones we read from the
-		// index key, except for composite columns.
-		table.neededValueCols = table.neededCols.Len() - neededIndexCols + len(table.index.CompositeColumnIDs)
+		// index key, except for composite columns, and minus the virtual columns.
+		table.neededValueCols = table.neededCols.Len() - neededIndexCols +
+			len(table.index.CompositeColumnIDs) - len(table.virtualCols)
 
 		if table.isSecondaryIndex {
 			for i := range table.cols {
@@ -401,6 +431,17 @@ func (rf *RowFetcher) Init(
 	return nil
 }
 
+// SetLocking makes the scans started by the RowFetcher acquire locks of the
+// given strength over the keys they read (i.e. for SELECT ... FOR UPDATE), and
+// sets what they do when running into keys locked by other transactions. It
+// must be called before StartScan.
+func (rf *RowFetcher) SetLocking(
+	strength roachpb.KeyLockingStrength, waitPolicy roachpb.LockWaitPolicy,
+) {
+	rf.lockStrength = strength
+	rf.lockWaitPolicy = waitPolicy
+}
+
 // StartScan initializes and starts the key-value scan. Can be used multiple
 // times.
 func (rf *RowFetcher) StartScan(
@@ -431,7 +472,10 @@ func (rf *RowFetcher) StartScan(
 		firstBatchLimit++
 	}
 
-	f, err := makeKVFetcher(txn, spans, rf.reverse, limitBatches, firstBatchLimit, rf.returnRangeInfo)
+	f, err := makeKVFetcher(
+		txn, spans, rf.reverse, limitBatches, firstBatchLimit, rf.returnRangeInfo,
+		rf.lockStrength, rf.lockWaitPolicy,
+	)
 	if err != nil {
 		return err
 	}
@@ -1010,7 +1054,7 @@ func (rf *RowFetcher) NextRow(
 			return nil, nil, nil, err
 		}
 		if rowDone {
-			err := rf.finalizeRow()
+			err := rf.finalizeRow(ctx)
 			return rf.rowReadyTable.row, rf.rowReadyTable.desc, rf.rowReadyTable.index, err
 		}
 	}
@@ -1234,13 +1278,17 @@ func (rf *RowFetcher) checkKeyOrdering(ctx context.Context) error {
 	return nil
 }
 
-func (rf *RowFetcher) finalizeRow() error {
+func (rf *RowFetcher) finalizeRow(ctx context.Context) error {
 	table := rf.rowReadyTable
 	// Fill in any missing values with NULLs
 	for i := range table.cols {
 		if rf.valueColsFound == table.neededValueCols {
 			// Found all cols - done!
-			return nil
+			break
+		}
+		if table.cols[i].Virtual && !table.isSecondaryIndex {
+			// Computed below.
+			continue
 		}
 		if table.neededCols.Contains(int(table.cols[i].ID)) && table.row[i].IsUnset() {
 			if !table.cols[i].Nullable {
@@ -1273,6 +1321,57 @@ func (rf *RowFetcher) finalizeRow() error {
 			rf.valueColsFound++
 		}
 	}
+	return rf.computeVirtualCols(ctx, table)
+}
+
+// initVirtualCols prepares the expressions computing the values of the
+// needed virtual columns of a primary index scan, and returns valNeededForCol
+// extended with the columns they depend on.
+func (table *tableInfo) initVirtualCols(
+	valNeededForCol util.FastIntSet, alloc *DatumAlloc,
+) (util.FastIntSet, error) {
+	for i := range table.cols {
+		col := &table.cols[i]
+		if !col.Virtual || !valNeededForCol.Contains(i) {
+			continue
+		}
+		expr, deps, err := makeVirtualColumnExpr(col, table.cols)
+		if err != nil {
+			return util.FastIntSet{}, err
+		}
+		if table.virtualIVars == nil {
+			valNeededForCol = valNeededForCol.Copy()
+			table.virtualIVars = &virtualColContainer{
+				cols:  table.cols,
+				row:   table.row,
+				alloc: alloc,
+			}
+		}
+		valNeededForCol.UnionWith(deps)
+		table.virtualCols = append(table.virtualCols, i)
+		table.virtualExprs = append(table.virtualExprs, expr)
+	}
+	return valNeededForCol, nil
+}
+
+// computeVirtualCols sets the values of the needed virtual columns of the
+// given table's current row.
+func (rf *RowFetcher) computeVirtualCols(ctx context.Context, table *tableInfo) error {
+	if len(table.virtualCols) == 0 {
+		return nil
+	}
+	if rf.virtualEvalCtx.SessionData == nil {
+		rf.virtualEvalCtx.SessionData = &sessiondata.SessionData{}
+	}
+	rf.virtualEvalCtx.Context = ctx
+	rf.virtualEvalCtx.IVarContainer = table.virtualIVars
+	for j, idx := range table.virtualCols {
+		d, err := table.virtualExprs[j].Eval(&rf.virtualEvalCtx)
+		if err != nil {
+			return err
+		}
+		table.row[idx] = DatumToEncDatum(table.cols[idx].Type, d)
+	}
 	return nil
 }
 
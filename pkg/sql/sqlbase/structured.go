@@ -225,11 +225,17 @@ func (desc *IndexDescriptor) allocateName(tableDesc *TableDescriptor) {
 	desc.Name = name
 }
 
-// FillColumns sets the column names and directions in desc.
+// FillColumns sets the column names and directions in desc. The index
+// expressions must already have been replaced by the columns which hold their
+// values.
 func (desc *IndexDescriptor) FillColumns(elems tree.IndexElemList) error {
 	desc.ColumnNames = make([]string, 0, len(elems))
 	desc.ColumnDirections = make([]IndexDescriptor_Direction, 0, len(elems))
 	for _, c := range elems {
+		if c.Expr != nil {
+			return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+				"index expression %s is not supported here", c.Expr)
+		}
 		desc.ColumnNames = append(desc.ColumnNames, string(c.Column))
 		switch c.Direction {
 		case tree.Ascending, tree.DefaultDirection:
@@ -290,11 +296,26 @@ func (desc *IndexDescriptor) FullColumnIDs() ([]ColumnID, []encoding.Direction)
 // ColNamesFormat writes a string describing the column names and directions
 // in this index to the given buffer.
 func (desc *IndexDescriptor) ColNamesFormat(ctx *tree.FmtCtxWithBuf) {
+	desc.colNamesFormat(ctx, nil /* tableDesc */)
+}
+
+// colNamesFormat is like ColNamesFormat. In addition, if tableDesc is not nil,
+// the columns of the table which hold the values of index expressions are
+// written as these expressions.
+func (desc *IndexDescriptor) colNamesFormat(
+	ctx *tree.FmtCtxWithBuf, tableDesc *TableDescriptor,
+) {
 	for i := range desc.ColumnNames {
 		if i > 0 {
 			ctx.WriteString(", ")
 		}
-		ctx.FormatNameP(&desc.ColumnNames[i])
+		if col := tableDesc.findIndexExprColumn(desc.ColumnNames[i]); col != nil {
+			ctx.WriteByte('(')
+			ctx.WriteString(*col.ComputeExpr)
+			ctx.WriteByte(')')
+		} else {
+			ctx.FormatNameP(&desc.ColumnNames[i])
+		}
 		if desc.Type != IndexDescriptor_INVERTED {
 			ctx.WriteByte(' ')
 			ctx.WriteString(desc.ColumnDirections[i].String())
@@ -313,6 +334,21 @@ func (desc *IndexDescriptor) ColNamesString() string {
 // SQLString returns the SQL string describing this index. If non-empty,
 // "ON tableName" is included in the output in the correct place.
 func (desc *IndexDescriptor) SQLString(tableName *tree.TableName) string {
+	return desc.sqlString(tableName, nil /* tableDesc */)
+}
+
+// IndexSQLString is like IndexDescriptor.SQLString, but the index expressions
+// of the given index of the table are written as such rather than as the
+// hidden columns which hold their values.
+func (desc *TableDescriptor) IndexSQLString(
+	idx *IndexDescriptor, tableName *tree.TableName,
+) string {
+	return idx.sqlString(tableName, desc)
+}
+
+func (desc *IndexDescriptor) sqlString(
+	tableName *tree.TableName, tableDesc *TableDescriptor,
+) string {
 	f := tree.NewFmtCtxWithBuf(tree.FmtSimple)
 	if desc.Unique {
 		f.WriteString("UNIQUE ")
@@ -327,7 +363,7 @@ func (desc *IndexDescriptor) SQLString(tableName *tree.TableName) string {
 	}
 	f.FormatNameP(&desc.Name)
 	f.WriteString(" (")
-	desc.ColNamesFormat(f)
+	desc.colNamesFormat(f, tableDesc)
 	f.WriteByte(')')
 
 	if len(desc.StoreColumnNames) > 0 {
@@ -804,6 +840,10 @@ func (desc *TableDescriptor) allocateColumnFamilyIDs(columnNames map[string]Colu
 		if _, ok := columnsInFamilies[col.ID]; ok {
 			return
 		}
+		if col.Virtual {
+			// The values of virtual columns are not stored in the primary index.
+			return
+		}
 		if _, ok := primaryIndexColIDs[col.ID]; ok {
 			// Primary index columns are required to be assigned to family 0.
 			desc.Families[0].ColumnNames = append(desc.Families[0].ColumnNames, col.Name)
@@ -1137,6 +1177,22 @@ func (desc *TableDescriptor) ValidateTable(st *cluster.Settings) error {
 				}
 			}
 		}
+		if !st.Version.IsMinSupported(cluster.VersionVirtualComputedColumns) {
+			for _, def := range desc.Columns {
+				if def.Virtual {
+					return fmt.Errorf("cluster version does not support virtual computed columns (required: %s)",
+						cluster.VersionByKey(cluster.VersionVirtualComputedColumns))
+				}
+			}
+		}
+		if !st.Version.IsMinSupported(cluster.VersionEnums) {
+			for _, def := range desc.Columns {
+				if def.Type.SemanticType == ColumnType_ENUM {
+					return fmt.Errorf("cluster version does not support ENUM (required: %s)",
+						cluster.VersionByKey(cluster.VersionEnums))
+				}
+			}
+		}
 	}
 
 	for _, m := range desc.Mutations {
@@ -1194,6 +1250,20 @@ func (desc *TableDescriptor) validateColumnFamilies(
 		return nil, fmt.Errorf("the 0th family must have ID 0")
 	}
 
+	// Virtual columns are not stored in the primary index, and so are not in
+	// any family.
+	virtualColIDs := map[ColumnID]struct{}{}
+	for _, col := range desc.Columns {
+		if col.Virtual {
+			virtualColIDs[col.ID] = struct{}{}
+		}
+	}
+	for _, m := range desc.Mutations {
+		if col := m.GetColumn(); col != nil && col.Virtual {
+			virtualColIDs[col.ID] = struct{}{}
+		}
+	}
+
 	familyNames := map[string]struct{}{}
 	familyIDs := map[FamilyID]string{}
 	colIDToFamilyID := map[ColumnID]FamilyID{}
@@ -1232,6 +1302,9 @@ func (desc *TableDescriptor) validateColumnFamilies(
 				return nil, fmt.Errorf("family %q column %d should have name %q, but found name %q",
 					family.Name, colID, name, family.ColumnNames[i])
 			}
+			if _, ok := virtualColIDs[colID]; ok {
+				return nil, fmt.Errorf("family %q contains virtual column %q", family.Name, name)
+			}
 		}
 
 		for _, colID := range family.ColumnIDs {
@@ -1242,6 +1315,9 @@ func (desc *TableDescriptor) validateColumnFamilies(
 		}
 	}
 	for colID := range columnIDs {
+		if _, ok := virtualColIDs[colID]; ok {
+			continue
+		}
 		if _, ok := colIDToFamilyID[colID]; !ok {
 			return nil, fmt.Errorf("column %d is not in any column family", colID)
 		}
@@ -1796,6 +1872,20 @@ func (desc *TableDescriptor) FindColumnByName(name tree.Name) (ColumnDescriptor,
 	return ColumnDescriptor{}, false, NewUndefinedColumnError(string(name))
 }
 
+// findIndexExprColumn returns the column with the given name, including the
+// columns being added or dropped, if it holds the values of an index
+// expression. It returns nil otherwise, or if desc is nil.
+func (desc *TableDescriptor) findIndexExprColumn(name string) *ColumnDescriptor {
+	if desc == nil {
+		return nil
+	}
+	col, _, err := desc.FindColumnByName(tree.Name(name))
+	if err != nil || !col.IsIndexExpr() {
+		return nil
+	}
+	return &col
+}
+
 // ColumnIdxMap returns a map from Column ID to the ordinal position of that
 // column.
 func (desc *TableDescriptor) ColumnIdxMap() map[ColumnID]int {
@@ -2076,8 +2166,13 @@ func (desc *TableDescriptor) FinalizeMutation() (MutationID, error) {
 }
 
 // ColumnNeedsBackfill returns true if adding the given column requires a
-// backfill (dropping a column always requires a backfill).
+// backfill (dropping a column always requires a backfill). The values of a
+// virtual column are not stored, but a NOT NULL virtual column still needs
+// the backfill to check the existing rows.
 func ColumnNeedsBackfill(desc *ColumnDescriptor) bool {
+	if desc.Virtual {
+		return !desc.Nullable
+	}
 	return desc.DefaultExpr != nil || !desc.Nullable || desc.IsComputed()
 }
 
@@ -2110,6 +2205,12 @@ func (desc *TableDescriptor) Adding() bool {
 	return desc.State == TableDescriptor_ADD
 }
 
+// Offline returns true if the table is offline, e.g. while being imported
+// into.
+func (desc *TableDescriptor) Offline() bool {
+	return desc.State == TableDescriptor_OFFLINE
+}
+
 // HasDrainingNames returns true if a draining name exists.
 func (desc *TableDescriptor) HasDrainingNames() bool {
 	return len(desc.DrainingNames) > 0
@@ -2206,6 +2307,8 @@ func (desc *Descriptor) GetID() ID {
 		return t.Table.ID
 	case *Descriptor_Database:
 		return t.Database.ID
+	case *Descriptor_Type:
+		return t.Type.ID
 	default:
 		return 0
 	}
@@ -2218,6 +2321,8 @@ func (desc *Descriptor) GetName() string {
 		return t.Table.Name
 	case *Descriptor_Database:
 		return t.Database.Name
+	case *Descriptor_Type:
+		return t.Type.Name
 	default:
 		return ""
 	}
@@ -2302,7 +2407,11 @@ func (desc *ColumnDescriptor) SQLString() string {
 	if desc.IsComputed() {
 		f.WriteString(" AS (")
 		f.WriteString(*desc.ComputeExpr)
-		f.WriteString(") STORED")
+		if desc.Virtual {
+			f.WriteString(") VIRTUAL")
+		} else {
+			f.WriteString(") STORED")
+		}
 	}
 	return f.CloseAndGetString()
 }
@@ -2402,11 +2511,27 @@ func (desc *ColumnDescriptor) IsHidden() bool {
 	return desc.Hidden
 }
 
-// IsComputed returns whether the given column is computed.
+// IsComputed returns whether the given column is computed. It is part of
+// the opt.Column interface.
 func (desc *ColumnDescriptor) IsComputed() bool {
 	return desc.ComputeExpr != nil
 }
 
+// ComputedExprStr is part of the opt.Column interface.
+func (desc *ColumnDescriptor) ComputedExprStr() string {
+	if desc.ComputeExpr == nil {
+		return ""
+	}
+	return *desc.ComputeExpr
+}
+
+// IsIndexExpr returns whether the given column holds the values of an index
+// expression. Such columns are the only columns which are both hidden and
+// computed.
+func (desc *ColumnDescriptor) IsIndexExpr() bool {
+	return desc.Hidden && desc.IsComputed()
+}
+
 // CheckCanBeFKRef returns whether the given column is computed.
 func (desc *ColumnDescriptor) CheckCanBeFKRef() error {
 	if desc.IsComputed() {
@@ -343,6 +343,21 @@ func TestValidateTableDesc(t *testing.T) {
 				NextColumnID: 2,
 				NextFamilyID: 1,
 			}},
+		{`family "baz" contains virtual column "bar"`,
+			TableDescriptor{
+				ID:            2,
+				ParentID:      1,
+				Name:          "foo",
+				FormatVersion: FamilyFormatVersion,
+				Columns: []ColumnDescriptor{
+					{ID: 1, Name: "bar", Virtual: true},
+				},
+				Families: []ColumnFamilyDescriptor{
+					{ID: 0, Name: "baz", ColumnIDs: []ColumnID{1}, ColumnNames: []string{"bar"}},
+				},
+				NextColumnID: 2,
+				NextFamilyID: 1,
+			}},
 		{`column 1 is in both family 0 and 1`,
 			TableDescriptor{
 				ID:            2,
@@ -624,6 +639,52 @@ func TestValidateTableDesc(t *testing.T) {
 	}
 }
 
+// TestValidateTableDescEnumsRequireVersion verifies that tables with ENUM
+// columns don't validate until all the nodes have been upgraded to a version
+// which can decode them.
+func TestValidateTableDescEnumsRequireVersion(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	desc := TableDescriptor{
+		ID:            2,
+		ParentID:      1,
+		Name:          "foo",
+		FormatVersion: FamilyFormatVersion,
+		Columns: []ColumnDescriptor{
+			{ID: 1, Name: "bar", Type: ColumnType{SemanticType: ColumnType_ENUM}},
+		},
+		Families: []ColumnFamilyDescriptor{
+			{ID: 0, Name: "primary", ColumnIDs: []ColumnID{1}, ColumnNames: []string{"bar"}},
+		},
+		PrimaryIndex: IndexDescriptor{
+			ID: 1, Name: "primary", ColumnIDs: []ColumnID{1}, ColumnNames: []string{"bar"},
+			ColumnDirections: []IndexDescriptor_Direction{IndexDescriptor_ASC},
+		},
+		NextColumnID: 2,
+		NextFamilyID: 1,
+		NextIndexID:  2,
+		Privileges:   NewDefaultPrivilegeDescriptor(),
+	}
+	for _, tc := range []struct {
+		version roachpb.Version
+		expErr  string
+	}{
+		{cluster.VersionByKey(cluster.VersionBoundedStaleness), "cluster version does not support ENUM"},
+		{cluster.VersionByKey(cluster.VersionEnums), ""},
+	} {
+		t.Run(tc.version.String(), func(t *testing.T) {
+			err := desc.ValidateTable(cluster.MakeTestingClusterSettingsWithVersion(tc.version, tc.version))
+			if tc.expErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+			} else if !testutils.IsError(err, tc.expErr) {
+				t.Fatalf("expected %q, got: %v", tc.expErr, err)
+			}
+		})
+	}
+}
+
 func TestValidateCrossTableReferences(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	ctx := context.Background()
@@ -184,6 +184,23 @@ CREATE TABLE system.role_members (
   INDEX ("role"),
   INDEX ("member")
 );`
+
+	// scheduled_jobs stores schedules that periodically create jobs, such as
+	// recurring backups. A NULL next_run means the schedule is paused.
+	ScheduledJobsTableSchema = `
+CREATE TABLE system.scheduled_jobs (
+	schedule_id     INT       DEFAULT unique_rowid() NOT NULL PRIMARY KEY,
+	schedule_name   STRING    NOT NULL,
+	created         TIMESTAMP NOT NULL DEFAULT now(),
+	owner           STRING    NOT NULL,
+	schedule_expr   STRING    NOT NULL,
+	next_run        TIMESTAMP,
+	schedule_status STRING,
+	executor_type   STRING    NOT NULL,
+	execution_args  BYTES     NOT NULL,
+	INDEX (next_run),
+	FAMILY (schedule_id, schedule_name, created, owner, schedule_expr, next_run, schedule_status, executor_type, execution_args)
+);`
 )
 
 func pk(name string) IndexDescriptor {
@@ -223,6 +240,7 @@ var SystemAllowedPrivileges = map[ID]privilege.List{
 	keys.TableStatisticsTableID: privilege.ReadWriteData,
 	keys.LocationsTableID:       privilege.ReadWriteData,
 	keys.RoleMembersTableID:     privilege.ReadWriteData,
+	keys.ScheduledJobsTableID:   privilege.ReadWriteData,
 }
 
 // Helpers used to make some of the TableDescriptor literals below more concise.
@@ -792,6 +810,61 @@ var (
 		FormatVersion:  InterleavedFormatVersion,
 		NextMutationID: 1,
 	}
+
+	// ScheduledJobsTable is the descriptor for the scheduled_jobs table.
+	ScheduledJobsTable = TableDescriptor{
+		Name:     "scheduled_jobs",
+		ID:       keys.ScheduledJobsTableID,
+		ParentID: keys.SystemDatabaseID,
+		Version:  1,
+		Columns: []ColumnDescriptor{
+			{Name: "schedule_id", ID: 1, Type: colTypeInt, DefaultExpr: &uniqueRowIDString},
+			{Name: "schedule_name", ID: 2, Type: colTypeString},
+			{Name: "created", ID: 3, Type: colTypeTimestamp, DefaultExpr: &nowString},
+			{Name: "owner", ID: 4, Type: colTypeString},
+			{Name: "schedule_expr", ID: 5, Type: colTypeString},
+			{Name: "next_run", ID: 6, Type: colTypeTimestamp, Nullable: true},
+			{Name: "schedule_status", ID: 7, Type: colTypeString, Nullable: true},
+			{Name: "executor_type", ID: 8, Type: colTypeString},
+			{Name: "execution_args", ID: 9, Type: colTypeBytes},
+		},
+		NextColumnID: 10,
+		Families: []ColumnFamilyDescriptor{
+			{
+				Name: "fam_0_schedule_id_schedule_name_created_owner_schedule_expr_next_run_schedule_status_executor_type_execution_args",
+				ID:   0,
+				ColumnNames: []string{
+					"schedule_id",
+					"schedule_name",
+					"created",
+					"owner",
+					"schedule_expr",
+					"next_run",
+					"schedule_status",
+					"executor_type",
+					"execution_args",
+				},
+				ColumnIDs: []ColumnID{1, 2, 3, 4, 5, 6, 7, 8, 9},
+			},
+		},
+		NextFamilyID: 1,
+		PrimaryIndex: pk("schedule_id"),
+		Indexes: []IndexDescriptor{
+			{
+				Name:             "scheduled_jobs_next_run_idx",
+				ID:               2,
+				Unique:           false,
+				ColumnNames:      []string{"next_run"},
+				ColumnDirections: []IndexDescriptor_Direction{IndexDescriptor_ASC},
+				ColumnIDs:        []ColumnID{6},
+				ExtraColumnIDs:   []ColumnID{1},
+			},
+		},
+		NextIndexID:    3,
+		Privileges:     NewCustomSuperuserPrivilegeDescriptor(SystemAllowedPrivileges[keys.ScheduledJobsTableID]),
+		FormatVersion:  InterleavedFormatVersion,
+		NextMutationID: 1,
+	}
 )
 
 // Create a kv pair for the zone config for the given key and config value.
@@ -106,6 +106,33 @@ func MakeColumnDefDescs(
 		return nil, nil, nil, errors.New("unexpected column REFERENCED constraint")
 	}
 
+	if d.HasDefaultExpr() {
+		if err := CheckNoUserDefinedTypeReferences(d.DefaultExpr.Expr, "DEFAULT"); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if d.IsComputed() {
+		if err := CheckNoUserDefinedTypeReferences(d.Computed.Expr, "computed column expressions"); err != nil {
+			return nil, nil, nil, err
+		}
+		if d.Computed.Virtual {
+			if d.PrimaryKey {
+				return nil, nil, nil, pgerror.NewErrorf(pgerror.CodeInvalidTableDefinitionError,
+					"virtual computed column %q cannot be part of the primary key", d.Name)
+			}
+			if d.HasColumnFamily() {
+				return nil, nil, nil, pgerror.NewErrorf(pgerror.CodeInvalidTableDefinitionError,
+					"virtual computed column %q cannot be assigned to a column family", d.Name)
+			}
+		}
+	}
+
+	colType, err := semaCtx.ResolveType(d.Type)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	d.Type = colType
+
 	col := &ColumnDescriptor{
 		Name:     string(d.Name),
 		Nullable: d.Nullable.Nullability != tree.NotNull && !d.PrimaryKey,
@@ -143,6 +170,7 @@ func MakeColumnDefDescs(
 	if d.IsComputed() {
 		s := tree.Serialize(d.Computed.Expr)
 		col.ComputeExpr = &s
+		col.Virtual = d.Computed.Virtual
 	}
 
 	var idx *IndexDescriptor
@@ -196,6 +196,11 @@ var (
 
 func init() {
 	for k := range ColumnType_SemanticType_name {
+		if ColumnType_SemanticType(k) == ColumnType_ENUM {
+			// Enum types are user-defined and cannot be generated randomly
+			// without a TypeDescriptor.
+			continue
+		}
 		columnSemanticTypes = append(columnSemanticTypes, ColumnType_SemanticType(k))
 		if ColumnType_SemanticType(k) != ColumnType_ARRAY {
 			arrayElemSemanticTypes = append(arrayElemSemanticTypes, ColumnType_SemanticType(k))
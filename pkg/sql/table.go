@@ -111,12 +111,18 @@ func (p *planner) getVirtualTabler() VirtualTabler {
 var errTableDropped = errors.New("table is being dropped")
 var errTableAdding = errors.New("table is being added")
 
+func errTableOffline(tableDesc *sqlbase.TableDescriptor) error {
+	return errors.Errorf("table %q is offline: %s", tableDesc.Name, tableDesc.OfflineReason)
+}
+
 func filterTableState(tableDesc *sqlbase.TableDescriptor) error {
 	switch {
 	case tableDesc.Dropped():
 		return errTableDropped
 	case tableDesc.Adding():
 		return errTableAdding
+	case tableDesc.Offline():
+		return errTableOffline(tableDesc)
 	case tableDesc.State != sqlbase.TableDescriptor_PUBLIC:
 		return errors.Errorf("table in unknown state: %s", tableDesc.State.String())
 	}
@@ -96,6 +96,17 @@ type txnState struct {
 	// The schema change closures to run when this txn is done.
 	schemaChangers schemaChangerCollection
 
+	// savepoints is the stack of regular savepoints established by the SQL txn
+	// through SAVEPOINT statements, innermost last. The cockroach_restart
+	// savepoint is not part of it; it is tracked by the state machine.
+	savepoints savepointStack
+
+	// abortedTxnErr is set when the SQL txn moved to the Aborted state while it
+	// had savepoints. The KV txn is not rolled back in that case, so that a
+	// ROLLBACK TO SAVEPOINT can resume it. Instead, it is rolled back when the
+	// SQL txn finishes.
+	abortedTxnErr error
+
 	// adv is overwritten after every transition. It represents instructions for
 	// for moving the cursor over the stream of input statements to the next
 	// statement to be executed.
@@ -218,12 +229,15 @@ func (ts *txnState) resetForNewSQLTxn(
 
 	// Discard the old schemaChangers, if any.
 	ts.schemaChangers = schemaChangerCollection{}
+	ts.savepoints = nil
+	ts.abortedTxnErr = nil
 }
 
 // finishSQLTxn finalizes a transaction's results and closes the root span for
 // the current SQL txn. This needs to be called before resetForNewSQLTxn() is
 // called for starting another SQL txn.
 func (ts *txnState) finishSQLTxn() {
+	ts.maybeRollbackAbortedTxn()
 	ts.mon.Stop(ts.Ctx)
 	if ts.cancel != nil {
 		ts.cancel()
@@ -252,6 +266,17 @@ func (ts *txnState) finishSQLTxn() {
 	ts.Ctx = nil
 	ts.mu.txn = nil
 	ts.recordingThreshold = 0
+	ts.savepoints = nil
+}
+
+// maybeRollbackAbortedTxn rolls back the KV txn if the SQL txn was moved to the
+// Aborted state without doing so because it had savepoints.
+func (ts *txnState) maybeRollbackAbortedTxn() {
+	if ts.abortedTxnErr == nil {
+		return
+	}
+	ts.mu.txn.CleanupOnError(ts.Ctx, ts.abortedTxnErr)
+	ts.abortedTxnErr = nil
 }
 
 // finishExternalTxn is a stripped-down version of finishSQLTxn used by
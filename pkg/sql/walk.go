@@ -215,6 +215,19 @@ func (v *planVisitor) visitInternal(plan planNode, name string) {
 		n.input = v.visit(n.input)
 		v.visitConcrete(n.table)
 
+	case *applyJoinNode:
+		if v.observer.attr != nil {
+			v.observer.attr(name, "type", joinTypeStr(n.joinType))
+		}
+		if v.observer.expr != nil && n.pred.onCond != nil {
+			v.expr(name, "pred", -1, n.pred.onCond)
+		}
+		n.input.plan = v.visit(n.input.plan)
+		// The right side is set to nil when the node is closed.
+		if n.right != nil {
+			n.right = v.visit(n.right)
+		}
+
 	case *joinNode:
 		if v.observer.attr != nil {
 			jType := joinTypeStr(n.joinType)
@@ -363,6 +376,23 @@ func (v *planVisitor) visitInternal(plan planNode, name string) {
 		n.left = v.visit(n.left)
 		n.right = v.visit(n.right)
 
+	case *recursiveCTENode:
+		if v.observer.attr != nil {
+			v.observer.attr(name, "label", n.label)
+		}
+		// The queries are set to nil as they are executed.
+		if n.initial != nil {
+			n.initial = v.visit(n.initial)
+		}
+		if n.recursive != nil {
+			n.recursive = v.visit(n.recursive)
+		}
+
+	case *workTableScanNode:
+		if v.observer.attr != nil {
+			v.observer.attr(name, "label", n.cte.label)
+		}
+
 	case *splitNode:
 		n.rows = v.visit(n.rows)
 
@@ -621,7 +651,9 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&alterIndexNode{}):           "alter index",
 	reflect.TypeOf(&alterSequenceNode{}):        "alter sequence",
 	reflect.TypeOf(&alterTableNode{}):           "alter table",
+	reflect.TypeOf(&alterTypeNode{}):            "alter type",
 	reflect.TypeOf(&alterUserSetPasswordNode{}): "alter user",
+	reflect.TypeOf(&applyJoinNode{}):            "apply-join",
 	reflect.TypeOf(&cancelQueriesNode{}):        "cancel queries",
 	reflect.TypeOf(&cancelSessionsNode{}):       "cancel sessions",
 	reflect.TypeOf(&controlJobsNode{}):          "control jobs",
@@ -630,6 +662,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&createSequenceNode{}):       "create sequence",
 	reflect.TypeOf(&createStatsNode{}):          "create statistics",
 	reflect.TypeOf(&createTableNode{}):          "create table",
+	reflect.TypeOf(&createTypeNode{}):           "create type",
 	reflect.TypeOf(&CreateUserNode{}):           "create user/role",
 	reflect.TypeOf(&createViewNode{}):           "create view",
 	reflect.TypeOf(&delayedNode{}):              "virtual table",
@@ -653,6 +686,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&lookupJoinNode{}):           "lookup-join",
 	reflect.TypeOf(&ordinalityNode{}):           "ordinality",
 	reflect.TypeOf(&projectSetNode{}):           "project set",
+	reflect.TypeOf(&recursiveCTENode{}):         "recursive cte",
 	reflect.TypeOf(&relocateNode{}):             "relocate",
 	reflect.TypeOf(&renderNode{}):               "render",
 	reflect.TypeOf(&rowCountNode{}):             "count",
@@ -679,5 +713,6 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&upsertNode{}):               "upsert",
 	reflect.TypeOf(&valuesNode{}):               "values",
 	reflect.TypeOf(&windowNode{}):               "window",
+	reflect.TypeOf(&workTableScanNode{}):        "working table",
 	reflect.TypeOf(&zeroNode{}):                 "norows",
 }
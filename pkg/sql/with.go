@@ -19,6 +19,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 )
 
@@ -56,6 +57,12 @@ type cteSource struct {
 	// alias holds the name of the CTE and the renaming of its columns, if
 	// present.
 	alias tree.AliasClause
+	// recursive is set for the reference of a recursive CTE to itself within
+	// its recursive query, in which case plan is a workTableScanNode.
+	recursive bool
+	// err, if set, is returned when the CTE is referenced. It is used to
+	// reject references to a recursive CTE where they are not allowed.
+	err error
 }
 
 func (e cteNameEnvironment) push(frame cteNameEnvironmentFrame) cteNameEnvironment {
@@ -70,6 +77,20 @@ func popCteNameEnvironment(p *planner) {
 	p.curPlan.cteNameEnvironment = p.curPlan.cteNameEnvironment.pop()
 }
 
+// numUsed returns the number of CTEs of the environment which have been used
+// as a statement source.
+func (e cteNameEnvironment) numUsed() int {
+	n := 0
+	for _, frame := range e {
+		for _, cteSource := range frame {
+			if cteSource.used {
+				n++
+			}
+		}
+	}
+	return n
+}
+
 // initWith pushes a new environment frame onto the planner's CTE name
 // environment, with all of the CTE clauses defined in the given tree.With.
 // It returns a resetter function that must be called once the enclosing scope
@@ -85,7 +106,13 @@ func (p *planner) initWith(ctx context.Context, with *tree.With) (func(p *planne
 					"WITH query name %s specified more than once",
 					cte.Name.Alias)
 			}
-			ctePlan, err := p.newPlan(ctx, cte.Stmt, nil)
+			var ctePlan planNode
+			var err error
+			if with.Recursive {
+				ctePlan, err = p.newRecursiveCTEPlan(ctx, cte)
+			} else {
+				ctePlan, err = p.newPlan(ctx, cte.Stmt, nil)
+			}
 			if err != nil {
 				return nil, err
 			}
@@ -111,7 +138,14 @@ func (p *planner) getCTEDataSource(tn *tree.TableName) (planDataSource, bool, er
 	for i := range p.curPlan.cteNameEnvironment {
 		frame := p.curPlan.cteNameEnvironment[len(p.curPlan.cteNameEnvironment)-1-i]
 		if cteSource, ok := frame[tn.TableName]; ok {
+			if cteSource.err != nil {
+				return planDataSource{}, false, cteSource.err
+			}
 			if cteSource.used {
+				if cteSource.recursive {
+					return planDataSource{}, false, pgerror.NewErrorf(pgerror.CodeInvalidRecursionError,
+						"recursive reference to query %q must not appear more than once", tree.ErrString(tn))
+				}
 				// TODO(jordan): figure out how to lift this restriction.
 				// CTE expressions that are used more than once will need to be
 				// pre-evaluated like subqueries, I think.
@@ -137,3 +171,186 @@ func (p *planner) getCTEDataSource(tn *tree.TableName) (planDataSource, bool, er
 	}
 	return planDataSource{}, false, nil
 }
+
+// recursiveCTEUnion returns the UNION of the initial and recursive queries of
+// a CTE of a WITH RECURSIVE clause, if the CTE has the form:
+//
+//	<initial query> UNION [ALL] <recursive query>
+//
+// Only CTEs of this form may reference themselves.
+func recursiveCTEUnion(stmt tree.Statement) (*tree.UnionClause, bool) {
+	sel, ok := stmt.(*tree.Select)
+	if !ok || sel.With != nil || sel.OrderBy != nil || sel.Limit != nil || sel.Locking != nil {
+		return nil, false
+	}
+	union, ok := sel.Select.(*tree.UnionClause)
+	if !ok || union.Type != tree.UnionOp {
+		return nil, false
+	}
+	return union, true
+}
+
+// newRecursiveCTEPlan plans a CTE of a WITH RECURSIVE clause. If the CTE
+// references itself, it is planned as a recursiveCTENode. Otherwise, it is
+// planned like any other CTE.
+func (p *planner) newRecursiveCTEPlan(ctx context.Context, cte *tree.CTE) (planNode, error) {
+	name := cte.Name.Alias
+	frame := p.curPlan.cteNameEnvironment[len(p.curPlan.cteNameEnvironment)-1]
+	union, ok := recursiveCTEUnion(cte.Stmt)
+	if !ok {
+		frame[name] = cteSource{err: pgerror.NewErrorf(pgerror.CodeInvalidRecursionError,
+			"recursive query %q does not have the form non-recursive-term UNION [ALL] recursive-term",
+			tree.ErrString(&name))}
+		defer delete(frame, name)
+		return p.newPlan(ctx, cte.Stmt, nil)
+	}
+
+	frame[name] = cteSource{err: pgerror.NewErrorf(pgerror.CodeInvalidRecursionError,
+		"recursive reference to query %q must not appear within its non-recursive term",
+		tree.ErrString(&name))}
+	initial, err := p.newPlan(ctx, union.Left, nil)
+	delete(frame, name)
+	if err != nil {
+		return nil, err
+	}
+
+	n := &recursiveCTENode{
+		initial: initial,
+		columns: append(sqlbase.ResultColumns(nil), planColumns(initial)...),
+		label:   string(name),
+		all:     union.All,
+	}
+	numUsed := p.curPlan.cteNameEnvironment.numUsed()
+	numSubqueries := len(p.curPlan.subqueryPlans)
+	recursive, used, err := p.planRecursiveCTEIteration(ctx, n, cte.Name, union.Right)
+	if err != nil {
+		initial.Close(ctx)
+		return nil, err
+	}
+	if !used {
+		// The CTE doesn't reference itself: it is a plain UNION.
+		return p.newUnionNode(tree.UnionOp, union.All, initial, recursive)
+	}
+	if err := p.checkRecursiveCTEIteration(
+		ctx, n, recursive, numUsed, p.curPlan.subqueryPlans[numSubqueries:],
+	); err != nil {
+		n.recursive = recursive
+		n.Close(ctx)
+		return nil, err
+	}
+
+	n.recursive = recursive
+	n.genIterationFn = func(params runParams, n *recursiveCTENode) (planNode, error) {
+		return params.p.newRecursiveCTEIteration(params, n, cte.Name, union.Right)
+	}
+	return n, nil
+}
+
+// planRecursiveCTEIteration plans an iteration of the recursive query of a
+// recursive CTE, in which the name of the CTE refers to its working table. It
+// also returns whether the query references the working table.
+func (p *planner) planRecursiveCTEIteration(
+	ctx context.Context, n *recursiveCTENode, alias tree.AliasClause, stmt *tree.Select,
+) (planNode, bool, error) {
+	frame := cteNameEnvironmentFrame{
+		alias.Alias: cteSource{
+			plan:      &workTableScanNode{cte: n, columns: n.columns},
+			alias:     alias,
+			recursive: true,
+		},
+	}
+	p.curPlan.cteNameEnvironment = p.curPlan.cteNameEnvironment.push(frame)
+	defer popCteNameEnvironment(p)
+	plan, err := p.newPlan(ctx, stmt, nil)
+	return plan, frame[alias.Alias].used, err
+}
+
+// checkRecursiveCTEIteration checks that the plan of the recursive query of
+// a recursive CTE, along with the subqueries which were planned with it, can
+// be planned anew for every iteration, and that it produces rows which fit in
+// the columns of the CTE.
+func (p *planner) checkRecursiveCTEIteration(
+	ctx context.Context, n *recursiveCTENode, plan planNode, numUsed int, subqueries []subquery,
+) error {
+	if p.curPlan.cteNameEnvironment.numUsed() != numUsed {
+		// The iterations are planned without the other CTEs in scope, since
+		// their plans can only be used once.
+		return pgerror.Unimplemented("with recursive",
+			"recursive query %q must not reference other WITH queries", n.label)
+	}
+	for i := range subqueries {
+		if planScansWorkTable(ctx, subqueries[i].plan, n) {
+			// The subqueries are evaluated before the iteration starts, so they
+			// can't read its working table.
+			return pgerror.NewErrorf(pgerror.CodeInvalidRecursionError,
+				"recursive reference to query %q must not appear within a subquery", n.label)
+		}
+	}
+
+	cols := planColumns(plan)
+	if len(cols) != len(n.columns) {
+		return pgerror.NewErrorf(pgerror.CodeSyntaxError,
+			"each UNION query must have the same number of columns: %d vs %d",
+			len(n.columns), len(cols))
+	}
+	for i := range cols {
+		if !(n.columns[i].Typ.Equivalent(cols[i].Typ) || cols[i].Typ == types.Unknown) {
+			return pgerror.NewErrorf(pgerror.CodeDatatypeMismatchError,
+				"recursive query %q column %d has type %s in non-recursive term but type %s in recursive term",
+				n.label, i+1, n.columns[i].Typ, cols[i].Typ)
+		}
+	}
+	return nil
+}
+
+// planScansWorkTable returns whether the plan scans the working table of the
+// given recursive CTE.
+func planScansWorkTable(ctx context.Context, plan planNode, n *recursiveCTENode) bool {
+	found := false
+	_ = walkPlan(ctx, plan, planObserver{
+		enterNode: func(_ context.Context, _ string, plan planNode) (bool, error) {
+			if s, ok := plan.(*workTableScanNode); ok && s.cte == n {
+				found = true
+			}
+			return !found, nil
+		},
+	})
+	return found
+}
+
+// newRecursiveCTEIteration plans an iteration of the recursive query of a
+// recursive CTE after the first, at execution time, and evaluates the
+// subqueries of the new plan.
+func (p *planner) newRecursiveCTEIteration(
+	params runParams, n *recursiveCTENode, alias tree.AliasClause, stmt *tree.Select,
+) (planNode, error) {
+	ctx := params.ctx
+	// The query doesn't reference any of the other CTEs in scope (see
+	// checkRecursiveCTEIteration), so it can be planned without them.
+	defer func(env cteNameEnvironment) {
+		p.curPlan.cteNameEnvironment = env
+	}(p.curPlan.cteNameEnvironment)
+	p.curPlan.cteNameEnvironment = nil
+
+	numSubqueries := len(p.curPlan.subqueryPlans)
+	plan, _, err := p.planRecursiveCTEIteration(ctx, n, alias, stmt)
+	if err != nil {
+		return nil, err
+	}
+	plan, err = p.optimizePlan(ctx, plan, allColumns(plan))
+	if err != nil {
+		plan.Close(ctx)
+		return nil, err
+	}
+	for i := numSubqueries; i < len(p.curPlan.subqueryPlans); i++ {
+		if err := p.optimizeSubquery(ctx, &p.curPlan.subqueryPlans[i]); err != nil {
+			plan.Close(ctx)
+			return nil, err
+		}
+	}
+	if err := p.curPlan.evalSubqueries(params); err != nil {
+		plan.Close(ctx)
+		return nil, err
+	}
+	return plan, nil
+}
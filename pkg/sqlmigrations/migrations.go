@@ -190,6 +190,12 @@ var backwardCompatibleMigrations = []migrationDescriptor{
 		name:   "add progress to system.jobs",
 		workFn: addJobsProgress,
 	},
+	{
+		// Introduced in v2.2.
+		name:             "create system.scheduled_jobs table",
+		workFn:           createScheduledJobsTable,
+		newDescriptorIDs: staticIDs(keys.ScheduledJobsTableID),
+	},
 }
 
 func staticIDs(ids ...sqlbase.ID) func(ctx context.Context, db db) ([]sqlbase.ID, error) {
@@ -724,6 +730,9 @@ func upgradeDescsWithFn(
 						}
 					}
 
+				case *sqlbase.Descriptor_Type:
+					// Type descriptors need no upgrade.
+
 				default:
 					return errors.Errorf("Descriptor.Union has unexpected type %T", t)
 				}
@@ -841,3 +850,7 @@ func addJobsProgress(ctx context.Context, r runner) error {
 		return txn.Put(ctx, sqlbase.MakeDescMetadataKey(desc.ID), sqlbase.WrapDescriptor(desc))
 	})
 }
+
+func createScheduledJobsTable(ctx context.Context, r runner) error {
+	return createSystemTable(ctx, r, sqlbase.ScheduledJobsTable)
+}
@@ -0,0 +1,103 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package admission
+
+import (
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+)
+
+// Metrics holds all metrics relating to a WorkQueue.
+type Metrics struct {
+	Requested     *metric.Counter
+	Admitted      *metric.Counter
+	Bypassed      *metric.Counter
+	Canceled      *metric.Counter
+	WaitDurations *metric.Histogram
+	QueueLength   *metric.Gauge
+	Slots         *metric.Gauge
+	UsedSlots     *metric.Gauge
+}
+
+// MetricStruct implements the metrics.Struct interface.
+func (Metrics) MetricStruct() {}
+
+var _ metric.Struct = Metrics{}
+
+var (
+	metaRequested = metric.Metadata{
+		Name:        "admission.requested",
+		Help:        "Number of batches which requested admission",
+		Measurement: "Batches",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaAdmitted = metric.Metadata{
+		Name:        "admission.admitted",
+		Help:        "Number of batches which were admitted",
+		Measurement: "Batches",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaBypassed = metric.Metadata{
+		Name:        "admission.bypassed",
+		Help:        "Number of batches which were admitted without being subject to the admission slots",
+		Measurement: "Batches",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaCanceled = metric.Metadata{
+		Name:        "admission.canceled",
+		Help:        "Number of batches whose context was canceled while waiting for admission",
+		Measurement: "Batches",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaWaitDurations = metric.Metadata{
+		Name:        "admission.wait_durations",
+		Help:        "Wait time durations for batches that were queued for admission",
+		Measurement: "Wait time Duration",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+	metaQueueLength = metric.Metadata{
+		Name:        "admission.queue_length",
+		Help:        "Number of batches waiting for admission",
+		Measurement: "Batches",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaSlots = metric.Metadata{
+		Name:        "admission.slots",
+		Help:        "Number of batches that can be executed concurrently by the store",
+		Measurement: "Slots",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaUsedSlots = metric.Metadata{
+		Name:        "admission.slots_used",
+		Help:        "Number of batches admitted by the store that are executing",
+		Measurement: "Slots",
+		Unit:        metric.Unit_COUNT,
+	}
+)
+
+// makeMetrics returns a Metrics struct.
+func makeMetrics(histogramWindow time.Duration) Metrics {
+	return Metrics{
+		Requested:     metric.NewCounter(metaRequested),
+		Admitted:      metric.NewCounter(metaAdmitted),
+		Bypassed:      metric.NewCounter(metaBypassed),
+		Canceled:      metric.NewCounter(metaCanceled),
+		WaitDurations: metric.NewLatency(metaWaitDurations, histogramWindow),
+		QueueLength:   metric.NewGauge(metaQueueLength),
+		Slots:         metric.NewGauge(metaSlots),
+		UsedSlots:     metric.NewGauge(metaUsedSlots),
+	}
+}
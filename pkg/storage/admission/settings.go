@@ -0,0 +1,64 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package admission
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+)
+
+// Enabled controls whether the work sent to stores goes through admission
+// control.
+var Enabled = settings.RegisterBoolSetting(
+	"kv.admission_control.enabled",
+	"when true, work sent to an overloaded store is queued and admitted in order of priority",
+	false,
+)
+
+// l0FileCountThreshold is the number of files in level 0 of a store's LSM
+// above which the store is considered overloaded. Files in L0 overlap each
+// other, so reads get slower with each of them; a growing count means that
+// compactions can't keep up with the incoming writes.
+var l0FileCountThreshold = settings.RegisterValidatedIntSetting(
+	"kv.admission_control.l0_file_count_threshold",
+	"the number of files in level 0 of a store above which work sent to the store is throttled",
+	20,
+	func(v int64) error {
+		if v <= 0 {
+			return errors.Errorf("value %d must be positive", v)
+		}
+		return nil
+	},
+)
+
+// runQueueThreshold is the number of runnable threads per CPU above which a
+// node is considered overloaded. Threads blocked on I/O are not runnable.
+var runQueueThreshold = settings.RegisterValidatedFloatSetting(
+	"kv.admission_control.run_queue_threshold",
+	"the number of runnable threads per CPU above which work sent to the stores of a node is throttled",
+	2,
+	func(v float64) error {
+		if v <= 0 {
+			return errors.Errorf("value %v must be positive", v)
+		}
+		return nil
+	},
+)
+
+func init() {
+	l0FileCountThreshold.Hide()
+	runQueueThreshold.Hide()
+}
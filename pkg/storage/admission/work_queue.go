@@ -0,0 +1,267 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package admission contains the admission control of the work sent to a
+// store, which protects the store from overload by limiting the number of
+// batches it executes concurrently and by queueing the others in order of
+// priority.
+package admission
+
+import (
+	"container/heap"
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+const (
+	// MinSlots is the lowest number of slots a WorkQueue is ever sized to.
+	MinSlots = 4
+	// MaxSlots is the highest number of slots a WorkQueue is ever sized to. It
+	// is also the number of slots of a WorkQueue which hasn't seen overload,
+	// so that admission control doesn't get in the way until it is needed.
+	MaxSlots = 1024
+)
+
+// Signals are measurements of the load of a store, which a WorkQueue uses to
+// decide how many batches the store can execute concurrently.
+type Signals struct {
+	// L0FileCount is the number of files in level 0 of the store's LSM.
+	L0FileCount int
+	// RunQueueLength is the number of runnable threads per CPU of the node.
+	RunQueueLength float64
+}
+
+// rank orders the admission priorities: work with a higher rank is admitted
+// before work with a lower rank.
+func rank(pri roachpb.AdmissionPriority) int {
+	switch pri {
+	case roachpb.ADMISSION_SYSTEM:
+		return 2
+	case roachpb.ADMISSION_BULK:
+		return 0
+	default:
+		return 1
+	}
+}
+
+// waitingWork is a batch waiting in a WorkQueue.
+type waitingWork struct {
+	rank       int
+	createTime hlc.Timestamp
+	// ch is closed when the work is granted a slot.
+	ch chan struct{}
+	// index is the index of the work in the heap, or -1 once the work has been
+	// granted a slot.
+	index int
+}
+
+// workHeap implements heap.Interface and holds waitingWork. Work with a higher
+// priority comes first and, within a priority, work that was created earlier
+// comes first. Ordering by creation time rather than by arrival gives every
+// client the same treatment regardless of how much work it sends, and lets
+// old transactions, which are likely to hold locks that other work is waiting
+// on, finish first.
+type workHeap []*waitingWork
+
+func (h workHeap) Len() int { return len(h) }
+
+func (h workHeap) Less(i, j int) bool {
+	if h[i].rank != h[j].rank {
+		return h[i].rank > h[j].rank
+	}
+	return h[i].createTime.Less(h[j].createTime)
+}
+
+func (h workHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+
+func (h *workHeap) Push(x interface{}) {
+	w := x.(*waitingWork)
+	w.index = len(*h)
+	*h = append(*h, w)
+}
+
+func (h *workHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	w := old[n-1]
+	w.index = -1 // for safety
+	old[n-1] = nil
+	*h = old[:n-1]
+	return w
+}
+
+// A WorkQueue limits the number of batches that a store executes concurrently
+// to its number of slots. Batches which find all the slots in use wait in the
+// queue until they are granted one. Work of system priority bypasses the
+// queue, so that the store keeps serving the requests the cluster needs to
+// function (for instance node liveness heartbeats) however overloaded it is.
+//
+// The number of slots is adjusted periodically based on the load of the store
+// (see Adjust): it is decreased multiplicatively when the store is overloaded
+// and increased additively otherwise.
+type WorkQueue struct {
+	st      *cluster.Settings
+	Metrics Metrics
+
+	mu struct {
+		syncutil.Mutex
+		slots   int
+		used    int
+		waiting workHeap
+	}
+}
+
+// NewWorkQueue returns a WorkQueue with MaxSlots slots.
+func NewWorkQueue(st *cluster.Settings, histogramWindow time.Duration) *WorkQueue {
+	q := &WorkQueue{
+		st:      st,
+		Metrics: makeMetrics(histogramWindow),
+	}
+	q.mu.slots = MaxSlots
+	q.Metrics.Slots.Update(MaxSlots)
+	return q
+}
+
+func (q *WorkQueue) enabled() bool {
+	return Enabled.Get(&q.st.SV)
+}
+
+// Admit blocks until the work of the given priority, which was created at the
+// given time, is admitted, and returns a function which must be called once
+// the work is done. An error is returned if the context is canceled before
+// the work is admitted.
+func (q *WorkQueue) Admit(
+	ctx context.Context, pri roachpb.AdmissionPriority, createTime hlc.Timestamp,
+) (func(), error) {
+	q.Metrics.Requested.Inc(1)
+	if pri == roachpb.ADMISSION_SYSTEM || !q.enabled() {
+		q.Metrics.Bypassed.Inc(1)
+		q.Metrics.Admitted.Inc(1)
+		return func() {}, nil
+	}
+
+	q.mu.Lock()
+	if q.mu.used < q.mu.slots && q.mu.waiting.Len() == 0 {
+		q.mu.used++
+		q.Metrics.UsedSlots.Update(int64(q.mu.used))
+		q.mu.Unlock()
+		q.Metrics.Admitted.Inc(1)
+		return q.done, nil
+	}
+	w := &waitingWork{
+		rank:       rank(pri),
+		createTime: createTime,
+		ch:         make(chan struct{}),
+	}
+	heap.Push(&q.mu.waiting, w)
+	q.Metrics.QueueLength.Update(int64(q.mu.waiting.Len()))
+	q.mu.Unlock()
+
+	start := timeutil.Now()
+	select {
+	case <-w.ch:
+		q.Metrics.WaitDurations.RecordValue(timeutil.Since(start).Nanoseconds())
+		q.Metrics.Admitted.Inc(1)
+		return q.done, nil
+	case <-ctx.Done():
+		q.mu.Lock()
+		granted := w.index == -1
+		if !granted {
+			heap.Remove(&q.mu.waiting, w.index)
+			q.Metrics.QueueLength.Update(int64(q.mu.waiting.Len()))
+		}
+		q.mu.Unlock()
+		if granted {
+			// The work was granted a slot concurrently with the cancellation.
+			// Hand the slot over to the next waiting work.
+			q.done()
+		}
+		q.Metrics.Canceled.Inc(1)
+		return nil, ctx.Err()
+	}
+}
+
+// done releases the slot of a work which was admitted.
+func (q *WorkQueue) done() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.mu.used--
+	q.grantLocked()
+}
+
+// grantLocked grants the free slots to the waiting work, in order.
+func (q *WorkQueue) grantLocked() {
+	for q.mu.used < q.mu.slots && q.mu.waiting.Len() > 0 {
+		w := heap.Pop(&q.mu.waiting).(*waitingWork)
+		q.mu.used++
+		close(w.ch)
+	}
+	q.Metrics.QueueLength.Update(int64(q.mu.waiting.Len()))
+	q.Metrics.UsedSlots.Update(int64(q.mu.used))
+}
+
+// Slots returns the number of slots of the queue.
+func (q *WorkQueue) Slots() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.mu.slots
+}
+
+// Overloaded returns whether the signals indicate that the store is
+// overloaded.
+func (q *WorkQueue) Overloaded(s Signals) bool {
+	return int64(s.L0FileCount) > l0FileCountThreshold.Get(&q.st.SV) ||
+		s.RunQueueLength > runQueueThreshold.Get(&q.st.SV)
+}
+
+// Adjust resizes the queue based on the given signals. When the store is
+// overloaded, the number of slots is halved, starting from the number of
+// slots in use so that a large limit which doesn't constrain the store takes
+// effect right away. Otherwise, the number of slots is increased by one if
+// they are all in use. When admission control is disabled, the number of
+// slots is reset to MaxSlots.
+func (q *WorkQueue) Adjust(s Signals) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	slots := q.mu.slots
+	if !q.enabled() {
+		slots = MaxSlots
+	} else if q.Overloaded(s) {
+		if q.mu.used < slots {
+			slots = q.mu.used
+		}
+		slots /= 2
+	} else if q.mu.used >= slots {
+		slots++
+	}
+	if slots < MinSlots {
+		slots = MinSlots
+	} else if slots > MaxSlots {
+		slots = MaxSlots
+	}
+
+	q.mu.slots = slots
+	q.Metrics.Slots.Update(int64(slots))
+	q.grantLocked()
+}
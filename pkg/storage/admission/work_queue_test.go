@@ -0,0 +1,199 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package admission
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func newTestWorkQueue(slots int) *WorkQueue {
+	st := cluster.MakeTestingClusterSettings()
+	Enabled.Override(&st.SV, true)
+	q := NewWorkQueue(st, time.Minute)
+	q.mu.slots = slots
+	return q
+}
+
+// fillSlots admits work until all the slots of the queue are in use, and
+// returns the functions releasing them.
+func fillSlots(t *testing.T, q *WorkQueue) []func() {
+	var dones []func()
+	for i := 0; i < q.Slots(); i++ {
+		done, err := q.Admit(context.Background(), roachpb.ADMISSION_USER, hlc.Timestamp{})
+		require.NoError(t, err)
+		dones = append(dones, done)
+	}
+	return dones
+}
+
+func waitForQueueLength(t *testing.T, q *WorkQueue, n int64) {
+	testutils.SucceedsSoon(t, func() error {
+		if l := q.Metrics.QueueLength.Value(); l != n {
+			return errors.Errorf("expected %d waiting, found %d", n, l)
+		}
+		return nil
+	})
+}
+
+func TestWorkQueueOrdering(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	q := newTestWorkQueue(1)
+	dones := fillSlots(t, q)
+
+	type admitted struct {
+		name string
+		done func()
+	}
+	admittedCh := make(chan admitted)
+	works := []struct {
+		name     string
+		pri      roachpb.AdmissionPriority
+		walltime int64
+	}{
+		{"bulk-1", roachpb.ADMISSION_BULK, 1},
+		{"user-3", roachpb.ADMISSION_USER, 3},
+		{"bulk-0", roachpb.ADMISSION_BULK, 0},
+		{"user-2", roachpb.ADMISSION_USER, 2},
+	}
+	for i, w := range works {
+		w := w
+		go func() {
+			done, err := q.Admit(context.Background(), w.pri, hlc.Timestamp{WallTime: w.walltime})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			admittedCh <- admitted{name: w.name, done: done}
+		}()
+		waitForQueueLength(t, q, int64(i+1))
+	}
+
+	// System work isn't queued.
+	done, err := q.Admit(context.Background(), roachpb.ADMISSION_SYSTEM, hlc.Timestamp{WallTime: 4})
+	require.NoError(t, err)
+	done()
+	require.Equal(t, int64(1), q.Metrics.Bypassed.Count())
+
+	// Release the slots one at a time. The waiting work is admitted by
+	// decreasing priority, then by increasing creation time.
+	var order []string
+	dones[0]()
+	for range works {
+		a := <-admittedCh
+		order = append(order, a.name)
+		a.done()
+	}
+	require.Equal(t, []string{"user-2", "user-3", "bulk-0", "bulk-1"}, order)
+	require.Equal(t, int64(0), q.Metrics.UsedSlots.Value())
+	require.Equal(t, int64(0), q.Metrics.QueueLength.Value())
+	require.Equal(t, int64(len(works)), q.Metrics.WaitDurations.TotalCount())
+}
+
+func TestWorkQueueCancel(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	q := newTestWorkQueue(1)
+	dones := fillSlots(t, q)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error)
+	go func() {
+		_, err := q.Admit(ctx, roachpb.ADMISSION_USER, hlc.Timestamp{})
+		errCh <- err
+	}()
+	waitForQueueLength(t, q, 1)
+	cancel()
+	require.Equal(t, context.Canceled, <-errCh)
+	require.Equal(t, int64(0), q.Metrics.QueueLength.Value())
+	require.Equal(t, int64(1), q.Metrics.Canceled.Count())
+
+	// The canceled work doesn't hold on to a slot.
+	dones[0]()
+	done, err := q.Admit(context.Background(), roachpb.ADMISSION_USER, hlc.Timestamp{})
+	require.NoError(t, err)
+	done()
+	require.Equal(t, int64(0), q.Metrics.UsedSlots.Value())
+}
+
+func TestWorkQueueDisabled(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	q := newTestWorkQueue(1)
+	_ = fillSlots(t, q)
+
+	// When admission control is disabled, work isn't queued.
+	Enabled.Override(&q.st.SV, false)
+	done, err := q.Admit(context.Background(), roachpb.ADMISSION_BULK, hlc.Timestamp{})
+	require.NoError(t, err)
+	done()
+	require.Equal(t, int64(1), q.Metrics.Bypassed.Count())
+
+	// Adjusting the queue resets its slots.
+	q.Adjust(Signals{L0FileCount: 1000})
+	require.Equal(t, MaxSlots, q.Slots())
+}
+
+func TestWorkQueueAdjust(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	q := newTestWorkQueue(MaxSlots)
+	l0FileCountThreshold.Override(&q.st.SV, 10)
+	runQueueThreshold.Override(&q.st.SV, 2)
+
+	healthy := Signals{L0FileCount: 5, RunQueueLength: 1}
+	require.False(t, q.Overloaded(healthy))
+	require.True(t, q.Overloaded(Signals{L0FileCount: 11}))
+	require.True(t, q.Overloaded(Signals{RunQueueLength: 2.5}))
+
+	// With few slots in use, overload shrinks the queue from the number of
+	// slots in use.
+	var dones []func()
+	for i := 0; i < 40; i++ {
+		done, err := q.Admit(context.Background(), roachpb.ADMISSION_USER, hlc.Timestamp{})
+		require.NoError(t, err)
+		dones = append(dones, done)
+	}
+	q.Adjust(Signals{L0FileCount: 11})
+	require.Equal(t, 20, q.Slots())
+	q.Adjust(Signals{RunQueueLength: 3})
+	require.Equal(t, 10, q.Slots())
+	for i := 0; i < 10; i++ {
+		q.Adjust(Signals{L0FileCount: 11})
+	}
+	require.Equal(t, MinSlots, q.Slots())
+
+	// The slots are all in use, so the queue grows by one slot at a time once
+	// the overload is gone.
+	q.Adjust(healthy)
+	require.Equal(t, MinSlots+1, q.Slots())
+	q.Adjust(healthy)
+	require.Equal(t, MinSlots+2, q.Slots())
+
+	// The queue doesn't grow while it doesn't constrain the store.
+	for _, done := range dones {
+		done()
+	}
+	q.Adjust(healthy)
+	require.Equal(t, MinSlots+2, q.Slots())
+	require.Equal(t, int64(MinSlots+2), q.Metrics.Slots.Value())
+}
@@ -216,7 +216,11 @@ func evalEndTransaction(
 			}
 			// Use alwaysReturn==true because the transaction is definitely
 			// aborted, no matter what happens to this command.
-			return result.FromEndTxn(reply.Txn, true /* alwaysReturn */, args.Poison), nil
+			pd := result.FromEndTxn(reply.Txn, true /* alwaysReturn */, args.Poison)
+			if err := pd.MergeAndDestroy(result.FromReleasedLocks(reply.Txn.ID)); err != nil {
+				return result.Result{}, err
+			}
+			return pd, nil
 		}
 		// If the transaction was previously aborted by a concurrent writer's
 		// push, any intents written are still open. It's only now that we know
@@ -356,6 +360,12 @@ func evalEndTransaction(
 	if err := pd.MergeAndDestroy(intentsResult); err != nil {
 		return result.Result{}, err
 	}
+	// The locks held by the transaction on this range are released along with
+	// its local intents. Its locks on other ranges are released when its
+	// external intents are resolved.
+	if err := pd.MergeAndDestroy(result.FromReleasedLocks(reply.Txn.ID)); err != nil {
+		return result.Result{}, err
+	}
 	return pd, nil
 }
 
@@ -454,7 +464,7 @@ func resolveLocalIntents(
 				externalIntents = append(externalIntents, span)
 				return nil
 			}
-			intent := roachpb.Intent{Span: span, Txn: txn.TxnMeta, Status: txn.Status}
+			intent := roachpb.MakeIntent(txn, span)
 			if len(span.EndKey) == 0 {
 				// For single-key intents, do a KeyAddress-aware check of
 				// whether it's contained in our Range.
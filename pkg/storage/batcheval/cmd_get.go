@@ -35,9 +35,20 @@ func Get(
 	h := cArgs.Header
 	reply := resp.(*roachpb.GetResponse)
 
+	// A locking Get that skips locked keys returns no value for a key that is
+	// locked by another transaction, or that has one of its intents.
+	locking := h.Txn != nil && args.KeyLocking != roachpb.LOCK_NONE
+	skipLocked := locking && h.WaitPolicy == roachpb.LOCK_WAIT_SKIP
+	if skipLocked && len(cArgs.EvalCtx.ConflictingLocks(h.Txn, args.KeyLocking, args.Span())) > 0 {
+		return result.Result{}, nil
+	}
+
 	val, intents, err := engine.MVCCGet(ctx, batch, args.Key, h.Timestamp,
 		h.ReadConsistency == roachpb.CONSISTENT, h.Txn)
 	if err != nil {
+		if _, ok := err.(*roachpb.WriteIntentError); ok && skipLocked {
+			return result.Result{}, nil
+		}
 		return result.Result{}, err
 	}
 
@@ -55,5 +66,13 @@ func Get(
 			}
 		}
 	}
-	return result.FromIntents(intents, args), err
+	res := result.FromIntents(intents, args)
+	if locking {
+		if mergeErr := res.MergeAndDestroy(
+			result.FromAcquiredLocks(h.Txn, args.KeyLocking, args.Span()),
+		); mergeErr != nil {
+			return result.Result{}, mergeErr
+		}
+	}
+	return res, err
 }
@@ -82,15 +82,20 @@ func ResolveIntent(
 	}
 
 	intent := roachpb.Intent{
-		Span:   args.Span(),
-		Txn:    args.IntentTxn,
-		Status: args.Status,
+		Span:           args.Span(),
+		Txn:            args.IntentTxn,
+		Status:         args.Status,
+		IgnoredSeqNums: args.IgnoredSeqNums,
 	}
 	if err := engine.MVCCResolveWriteIntent(ctx, batch, ms, intent); err != nil {
 		return result.Result{}, err
 	}
 
 	var res result.Result
+	if args.Status != roachpb.PENDING {
+		// The transaction is finalized, so it no longer holds locks on the range.
+		res = result.FromReleasedLocks(args.IntentTxn.ID)
+	}
 	res.Local.Metrics = resolveToMetricType(args.Status, args.Poison)
 
 	if WriteAbortSpanOnResolve(args.Status) {
@@ -48,9 +48,10 @@ func ResolveIntentRange(
 	}
 
 	intent := roachpb.Intent{
-		Span:   args.Span(),
-		Txn:    args.IntentTxn,
-		Status: args.Status,
+		Span:           args.Span(),
+		Txn:            args.IntentTxn,
+		Status:         args.Status,
+		IgnoredSeqNums: args.IgnoredSeqNums,
 	}
 
 	// Use a time-bounded iterator as an optimization if indicated.
@@ -81,6 +82,10 @@ func ResolveIntentRange(
 	}
 
 	var res result.Result
+	if args.Status != roachpb.PENDING {
+		// The transaction is finalized, so it no longer holds locks on the range.
+		res = result.FromReleasedLocks(args.IntentTxn.ID)
+	}
 	res.Local.Metrics = resolveToMetricType(args.Status, args.Poison)
 
 	if WriteAbortSpanOnResolve(args.Status) {
@@ -111,12 +111,20 @@ func (m *mockEvalCtx) GetGCThreshold() hlc.Timestamp {
 func (m *mockEvalCtx) GetTxnSpanGCThreshold() hlc.Timestamp {
 	panic("unimplemented")
 }
+func (m *mockEvalCtx) GetClosedTimestamp() hlc.Timestamp {
+	panic("unimplemented")
+}
 func (m *mockEvalCtx) GetLastReplicaGCTimestamp(context.Context) (hlc.Timestamp, error) {
 	panic("unimplemented")
 }
 func (m *mockEvalCtx) GetLease() (roachpb.Lease, roachpb.Lease) {
 	panic("unimplemented")
 }
+func (m *mockEvalCtx) ConflictingLocks(
+	*roachpb.Transaction, roachpb.KeyLockingStrength, roachpb.Span,
+) []roachpb.Intent {
+	return nil
+}
 
 func TestDeclareKeysResolveIntent(t *testing.T) {
 	defer leaktest.AfterTest(t)()
@@ -39,33 +39,46 @@ func Scan(
 	h := cArgs.Header
 	reply := resp.(*roachpb.ScanResponse)
 
-	var err error
 	var intents []roachpb.Intent
-	var resumeSpan *roachpb.Span
-
-	switch args.ScanFormat {
-	case roachpb.BATCH_RESPONSE:
-		var kvData []byte
-		var numKvs int64
-		kvData, numKvs, resumeSpan, intents, err = engine.MVCCScanToBytes(
-			ctx, batch, args.Key, args.EndKey, cArgs.MaxKeys,
-			h.Timestamp, h.ReadConsistency == roachpb.CONSISTENT, h.Txn)
-		if err != nil {
-			return result.Result{}, err
-		}
-		reply.NumKeys = numKvs
-		reply.BatchResponse = kvData
-	case roachpb.KEY_VALUES:
-		var rows []roachpb.KeyValue
-		rows, resumeSpan, intents, err = engine.MVCCScan(ctx, batch, args.Key, args.EndKey,
-			cArgs.MaxKeys, h.Timestamp, h.ReadConsistency == roachpb.CONSISTENT, h.Txn)
-		if err != nil {
-			return result.Result{}, err
+	scanFn := func(span roachpb.Span, maxKeys int64) (int64, *roachpb.Span, error) {
+		switch args.ScanFormat {
+		case roachpb.BATCH_RESPONSE:
+			kvData, numKvs, resumeSpan, spanIntents, err := engine.MVCCScanToBytes(
+				ctx, batch, span.Key, span.EndKey, maxKeys,
+				h.Timestamp, h.ReadConsistency == roachpb.CONSISTENT, h.Txn)
+			if err != nil {
+				return 0, nil, err
+			}
+			intents = append(intents, spanIntents...)
+			reply.NumKeys += numKvs
+			if reply.BatchResponse == nil {
+				reply.BatchResponse = kvData
+			} else {
+				reply.BatchResponse = append(reply.BatchResponse, kvData...)
+			}
+			return numKvs, resumeSpan, nil
+		case roachpb.KEY_VALUES:
+			rows, resumeSpan, spanIntents, err := engine.MVCCScan(ctx, batch, span.Key, span.EndKey,
+				maxKeys, h.Timestamp, h.ReadConsistency == roachpb.CONSISTENT, h.Txn)
+			if err != nil {
+				return 0, nil, err
+			}
+			intents = append(intents, spanIntents...)
+			reply.NumKeys += int64(len(rows))
+			if reply.Rows == nil {
+				reply.Rows = rows
+			} else {
+				reply.Rows = append(reply.Rows, rows...)
+			}
+			return int64(len(rows)), resumeSpan, nil
+		default:
+			panic(fmt.Sprintf("Unknown scanFormat %d", args.ScanFormat))
 		}
-		reply.NumKeys = int64(len(rows))
-		reply.Rows = rows
-	default:
-		panic(fmt.Sprintf("Unknown scanFormat %d", args.ScanFormat))
+	}
+
+	resumeSpan, res, err := evalScan(cArgs, args.Span(), false /* reverse */, scanFn)
+	if err != nil {
+		return result.Result{}, err
 	}
 
 	if resumeSpan != nil {
@@ -76,6 +89,9 @@ func Scan(
 	if h.ReadConsistency == roachpb.READ_UNCOMMITTED {
 		reply.IntentRows, err = CollectIntentRows(ctx, batch, cArgs, intents)
 	}
-	return result.FromIntents(intents, args), err
+	if mergeErr := res.MergeAndDestroy(result.FromIntents(intents, args)); mergeErr != nil {
+		return result.Result{}, mergeErr
+	}
+	return res, err
 
 }
@@ -27,7 +27,10 @@ import (
 func DefaultDeclareKeys(
 	desc roachpb.RangeDescriptor, header roachpb.Header, req roachpb.Request, spans *spanset.SpanSet,
 ) {
-	if roachpb.IsReadOnly(req) {
+	// Transactional locking reads declare their keys as read-write, which
+	// serializes them with the writes and the other locking reads over the same
+	// keys. This makes checking for conflicting locks and acquiring locks atomic.
+	if roachpb.IsReadOnly(req) && !(header.Txn != nil && roachpb.IsLockingRead(req)) {
 		spans.Add(spanset.SpanReadOnly, req.Header().Span())
 	} else {
 		spans.Add(spanset.SpanReadWrite, req.Header().Span())
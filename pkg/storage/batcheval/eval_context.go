@@ -79,4 +79,17 @@ type EvalContext interface {
 	GetTxnSpanGCThreshold() hlc.Timestamp
 	GetLastReplicaGCTimestamp(context.Context) (hlc.Timestamp, error)
 	GetLease() (roachpb.Lease, roachpb.Lease)
+
+	// GetClosedTimestamp returns the maximum closed timestamp known to the
+	// replica, i.e. the timestamp at or below which it can serve consistent
+	// reads without consulting the lease holder.
+	GetClosedTimestamp() hlc.Timestamp
+
+	// ConflictingLocks returns the unreplicated locks held by other
+	// transactions over the span that conflict with a request from txn that
+	// acquires locks of the given strength. Writes are treated as acquiring
+	// exclusive locks.
+	ConflictingLocks(
+		txn *roachpb.Transaction, strength roachpb.KeyLockingStrength, span roachpb.Span,
+	) []roachpb.Intent
 }
@@ -0,0 +1,147 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package batcheval
+
+import (
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/batcheval/result"
+)
+
+// scanFunc scans the given span, returning at most maxKeys keys, and
+// accumulates its results into the response of a scan. It returns the number
+// of keys it returned.
+type scanFunc func(span roachpb.Span, maxKeys int64) (int64, *roachpb.Span, error)
+
+// evalScan evaluates a (reverse) scan over the given span using scanFn and
+// returns its resume span.
+//
+// Scans that skip locked keys (LOCK_WAIT_SKIP) run scanFn over each of the
+// parts of the span that aren't locked by other transactions instead, and leave
+// out the keys with conflicting intents as well. For locking reads, the
+// returned Result acquires locks over the keys that were scanned.
+func evalScan(
+	cArgs CommandArgs, span roachpb.Span, reverse bool, scanFn scanFunc,
+) (*roachpb.Span, result.Result, error) {
+	h := cArgs.Header
+	strength := roachpb.LOCK_NONE
+	if h.Txn != nil {
+		strength = roachpb.KeyLockingOf(cArgs.Args)
+	}
+
+	if strength == roachpb.LOCK_NONE || h.WaitPolicy != roachpb.LOCK_WAIT_SKIP {
+		_, resumeSpan, err := scanFn(span, cArgs.MaxKeys)
+		if err != nil {
+			return nil, result.Result{}, err
+		}
+		var res result.Result
+		if strength != roachpb.LOCK_NONE {
+			if scanned, ok := scannedSpan(span, resumeSpan, reverse); ok {
+				res = result.FromAcquiredLocks(h.Txn, strength, scanned)
+			}
+		}
+		return resumeSpan, res, nil
+	}
+
+	todo := subtractLocked(span, cArgs.EvalCtx.ConflictingLocks(h.Txn, strength, span))
+	maxKeys := cArgs.MaxKeys
+	var resumeSpan *roachpb.Span
+	var locked []roachpb.Span
+	for len(todo) > 0 {
+		var cur roachpb.Span
+		if reverse {
+			cur, todo = todo[len(todo)-1], todo[:len(todo)-1]
+		} else {
+			cur, todo = todo[0], todo[1:]
+		}
+		n, curResume, err := scanFn(cur, maxKeys)
+		if wiErr, ok := err.(*roachpb.WriteIntentError); ok {
+			// Leave out the keys with conflicting intents and scan the rest of the
+			// span again. This terminates as every iteration removes at least one
+			// key from the span.
+			rest := subtractLocked(cur, wiErr.Intents)
+			if reverse {
+				todo = append(todo, rest...)
+			} else {
+				todo = append(rest, todo...)
+			}
+			continue
+		} else if err != nil {
+			return nil, result.Result{}, err
+		}
+		maxKeys -= n
+		if scanned, ok := scannedSpan(cur, curResume, reverse); ok {
+			locked = append(locked, scanned)
+		}
+		if curResume != nil {
+			if reverse {
+				resumeSpan = &roachpb.Span{Key: span.Key, EndKey: curResume.EndKey}
+			} else {
+				resumeSpan = &roachpb.Span{Key: curResume.Key, EndKey: span.EndKey}
+			}
+			break
+		}
+	}
+	return resumeSpan, result.FromAcquiredLocks(h.Txn, strength, locked...), nil
+}
+
+// scannedSpan returns the part of the span that a scan read before stopping
+// at the given resume span, if any.
+func scannedSpan(span roachpb.Span, resumeSpan *roachpb.Span, reverse bool) (roachpb.Span, bool) {
+	if resumeSpan == nil {
+		return span, true
+	}
+	if reverse {
+		span.Key = resumeSpan.EndKey
+	} else {
+		span.EndKey = resumeSpan.Key
+	}
+	return span, span.Key.Compare(span.EndKey) < 0
+}
+
+// subtractLocked returns the parts of the span that aren't covered by any of
+// the given locks or intents, in key order.
+func subtractLocked(span roachpb.Span, locks []roachpb.Intent) []roachpb.Span {
+	holes := make([]roachpb.Span, len(locks))
+	for i := range locks {
+		holes[i] = locks[i].Span
+		if len(holes[i].EndKey) == 0 {
+			holes[i].EndKey = holes[i].Key.Next()
+		}
+	}
+	sort.Slice(holes, func(i, j int) bool {
+		return holes[i].Key.Compare(holes[j].Key) < 0
+	})
+
+	var res []roachpb.Span
+	cur := span.Key
+	for _, hole := range holes {
+		end := hole.Key
+		if end.Compare(span.EndKey) > 0 {
+			end = span.EndKey
+		}
+		if cur.Compare(end) < 0 {
+			res = append(res, roachpb.Span{Key: cur, EndKey: end})
+		}
+		if hole.EndKey.Compare(cur) > 0 {
+			cur = hole.EndKey
+		}
+	}
+	if cur.Compare(span.EndKey) < 0 {
+		res = append(res, roachpb.Span{Key: cur, EndKey: span.EndKey})
+	}
+	return res
+}
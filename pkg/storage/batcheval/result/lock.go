@@ -0,0 +1,54 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package result
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+)
+
+// LockAcquisition is an unreplicated lock acquired by a locking read over a
+// span of keys.
+type LockAcquisition struct {
+	Txn      enginepb.TxnMeta
+	Strength roachpb.KeyLockingStrength
+	Span     roachpb.Span
+}
+
+// FromAcquiredLocks creates a Result communicating that the transaction
+// acquired locks of the given strength over the given spans.
+func FromAcquiredLocks(
+	txn *roachpb.Transaction, strength roachpb.KeyLockingStrength, spans ...roachpb.Span,
+) Result {
+	var pd Result
+	if len(spans) == 0 {
+		return pd
+	}
+	acqs := make([]LockAcquisition, len(spans))
+	for i, span := range spans {
+		acqs[i] = LockAcquisition{Txn: txn.TxnMeta, Strength: strength, Span: span}
+	}
+	pd.Local.AcquiredLocks = &acqs
+	return pd
+}
+
+// FromReleasedLocks creates a Result communicating that the given transaction
+// was finalized and that all of its locks on the range must be released.
+func FromReleasedLocks(txnID uuid.UUID) Result {
+	var pd Result
+	pd.Local.ReleasedLocks = &[]uuid.UUID{txnID}
+	return pd
+}
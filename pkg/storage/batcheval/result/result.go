@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/storagebase"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
 
 // LocalResult is data belonging to an evaluated command that is
@@ -68,6 +69,13 @@ type LocalResult struct {
 	// EndTransaction or PushTxn. This is a pointer to allow the zero
 	// (and as an unwelcome side effect, all) values to be compared.
 	UpdatedTxns *[]*roachpb.Transaction
+
+	// AcquiredLocks stores the unreplicated locks acquired by locking reads,
+	// which are added to the Replica's lock table. ReleasedLocks stores the IDs
+	// of finalized transactions whose locks must be removed from it. These are
+	// pointers for the same reason as Intents.
+	AcquiredLocks *[]LockAcquisition
+	ReleasedLocks *[]uuid.UUID
 }
 
 // DetachMaybeWatchForMerge returns and falsifies the MaybeWatchForMerge flag
@@ -96,6 +104,25 @@ func (lResult *LocalResult) DetachIntents() []IntentsWithArg {
 	return r
 }
 
+// DetachLocks returns (and removes) the lock acquisitions and releases from
+// the local result.
+func (lResult *LocalResult) DetachLocks() ([]LockAcquisition, []uuid.UUID) {
+	if lResult == nil {
+		return nil, nil
+	}
+	var acquired []LockAcquisition
+	if lResult.AcquiredLocks != nil {
+		acquired = *lResult.AcquiredLocks
+	}
+	var released []uuid.UUID
+	if lResult.ReleasedLocks != nil {
+		released = *lResult.ReleasedLocks
+	}
+	lResult.AcquiredLocks = nil
+	lResult.ReleasedLocks = nil
+	return acquired, released
+}
+
 // DetachEndTxns returns (and removes) the EndTxnIntent objects from
 // the local result. If alwaysOnly is true, the slice is filtered to
 // include only those which have specified returnAlways=true, meaning
@@ -332,6 +359,24 @@ func (p *Result) MergeAndDestroy(q Result) error {
 	}
 	q.Local.UpdatedTxns = nil
 
+	if q.Local.AcquiredLocks != nil {
+		if p.Local.AcquiredLocks == nil {
+			p.Local.AcquiredLocks = q.Local.AcquiredLocks
+		} else {
+			*p.Local.AcquiredLocks = append(*p.Local.AcquiredLocks, *q.Local.AcquiredLocks...)
+		}
+	}
+	q.Local.AcquiredLocks = nil
+
+	if q.Local.ReleasedLocks != nil {
+		if p.Local.ReleasedLocks == nil {
+			p.Local.ReleasedLocks = q.Local.ReleasedLocks
+		} else {
+			*p.Local.ReleasedLocks = append(*p.Local.ReleasedLocks, *q.Local.ReleasedLocks...)
+		}
+	}
+	q.Local.ReleasedLocks = nil
+
 	if q.LogicalOpLog != nil {
 		if p.LogicalOpLog == nil {
 			p.LogicalOpLog = q.LogicalOpLog
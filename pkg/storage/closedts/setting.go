@@ -21,6 +21,17 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings"
 )
 
+// FollowerReadsEnabled controls whether replicas attempt to serve follower
+// reads, and whether DistSenders send old enough reads to the nearest replica
+// rather than to the lease holder. The closed timestamp machinery is
+// unaffected by this, i.e. the same information is collected and passed
+// around, regardless of the value of this setting.
+var FollowerReadsEnabled = settings.RegisterBoolSetting(
+	"kv.closed_timestamp.follower_reads_enabled",
+	"allow (all) replicas to serve consistent historical reads based on closed timestamp information",
+	false,
+)
+
 // TargetDuration is the follower reads closed timestamp update target duration.
 var TargetDuration = settings.RegisterNonNegativeDurationSetting(
 	"kv.closed_timestamp.target_duration",
@@ -40,3 +51,16 @@ var CloseFraction = settings.RegisterValidatedFloatSetting(
 		}
 		return nil
 	})
+
+// followerReadMultiple is the number of closed timestamp updates, beyond
+// TargetDuration, that a timestamp trails the present by in
+// FollowerReadOffset. It leaves room for updates that are late or lost.
+const followerReadMultiple = 3
+
+// FollowerReadOffset returns how far in the past a timestamp needs to be for
+// all replicas to be expected to have closed it, and thus to be able to serve
+// reads at it. It returns zero if closed timestamps are disabled.
+func FollowerReadOffset(sv *settings.Values) time.Duration {
+	target := TargetDuration.Get(sv)
+	return time.Duration(float64(target) * (1 + CloseFraction.Get(sv)*followerReadMultiple))
+}
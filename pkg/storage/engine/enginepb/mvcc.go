@@ -129,3 +129,17 @@ func (ms *MVCCStats) Subtract(oms MVCCStats) {
 func (meta MVCCMetadata) IsInline() bool {
 	return meta.RawBytes != nil
 }
+
+// GetPrevIntentSeq returns the index and the entry of the latest value in the
+// intent history whose sequence number is not ignored. The last return value
+// is false if there is no such entry.
+func (meta MVCCMetadata) GetPrevIntentSeq(
+	ignored []IgnoredSeqNumRange,
+) (int, MVCCMetadata_SequencedIntent, bool) {
+	for i := len(meta.IntentHistory) - 1; i >= 0; i-- {
+		if !TxnSeqIsIgnored(meta.IntentHistory[i].Sequence, ignored) {
+			return i, meta.IntentHistory[i], true
+		}
+	}
+	return 0, MVCCMetadata_SequencedIntent{}, false
+}
@@ -60,3 +60,18 @@ func (op *MVCCLogicalOp) MustSetValue(value interface{}) {
 		panic(fmt.Sprintf("%T excludes %T", op, value))
 	}
 }
+
+// TxnSeqIsIgnored returns true iff the sequence number overlaps with any range
+// in the ignored list. The list is expected to be sorted and non-overlapping.
+func TxnSeqIsIgnored(seq int32, ignored []IgnoredSeqNumRange) bool {
+	// The list is usually very short, so a linear scan is as good as any.
+	for _, r := range ignored {
+		if seq < r.Start {
+			return false
+		}
+		if seq <= r.End {
+			return true
+		}
+	}
+	return false
+}
@@ -882,6 +882,20 @@ func mvccGetInternal(
 					txn.Epoch, meta.Txn.Epoch)
 			}
 			seekKey = seekKey.Next()
+		} else if ownIntent && enginepb.TxnSeqIsIgnored(meta.Txn.Sequence, txn.IgnoredSeqNums) {
+			// The intent was written at a sequence number that the transaction
+			// has since rolled back. Read the latest value from the intent
+			// history that wasn't rolled back instead, and skip the intent
+			// entirely if there is none.
+			if _, prevIntent, ok := meta.GetPrevIntentSeq(txn.IgnoredSeqNums); ok {
+				value := &buf.value
+				*value = roachpb.Value{RawBytes: prevIntent.Value, Timestamp: metaTimestamp}
+				if err := value.Verify(metaKey.Key); err != nil {
+					return nil, nil, safeValue, err
+				}
+				return value, ignoredIntents, safeValue, nil
+			}
+			seekKey = seekKey.Next()
 		}
 	} else if txn != nil && timestamp.Less(txn.MaxTimestamp) {
 		// In this branch, the latest timestamp is ahead, and so the read of an
@@ -1216,6 +1230,7 @@ func mvccPutInternal(
 	var meta *enginepb.MVCCMetadata
 	var maybeTooOldErr error
 	var prevValSize int64
+	var intentHistory []enginepb.MVCCMetadata_SequencedIntent
 	if ok {
 		// There is existing metadata for this key; ensure our write is permitted.
 		meta = &buf.meta
@@ -1245,6 +1260,17 @@ func mvccPutInternal(
 				ctx, iter, metaKey, value, ok, timestamp, txn, buf, valueFn); err != nil {
 				return err
 			}
+			// Within the same epoch, remember the value we are about to
+			// overwrite so that rolling back to a savepoint preceding this write
+			// can restore it.
+			if txn.Epoch == meta.Txn.Epoch {
+				prevIntentVal, err := getIntentValue(iter, key, metaTimestamp)
+				if err != nil {
+					return err
+				}
+				intentHistory = appendIntentHistory(
+					meta.IntentHistory, meta.Txn.Sequence, prevIntentVal, txn.IgnoredSeqNums)
+			}
 			// We are replacing our own write intent. If we are writing at
 			// the same timestamp (see comments in else block) we can
 			// overwrite the existing intent; otherwise we must manually
@@ -1328,8 +1354,9 @@ func mvccPutInternal(
 			txnMeta = &txn.TxnMeta
 		}
 		buf.newMeta = enginepb.MVCCMetadata{
-			Txn:       txnMeta,
-			Timestamp: hlc.LegacyTimestamp(timestamp),
+			Txn:           txnMeta,
+			Timestamp:     hlc.LegacyTimestamp(timestamp),
+			IntentHistory: intentHistory,
 		}
 	}
 	newMeta := &buf.newMeta
@@ -1394,6 +1421,51 @@ func mvccPutInternal(
 	return maybeTooOldErr
 }
 
+// getIntentValue returns a copy of the value of the intent on the given key,
+// which lives at the given timestamp.
+func getIntentValue(iter Iterator, key roachpb.Key, timestamp hlc.Timestamp) ([]byte, error) {
+	versionKey := MVCCKey{Key: key, Timestamp: timestamp}
+	iter.Seek(versionKey)
+	if ok, err := iter.Valid(); err != nil {
+		return nil, err
+	} else if !ok || !iter.UnsafeKey().Equal(versionKey) {
+		return nil, errors.Errorf("intent value missing for %s", versionKey)
+	}
+	return iter.Value(), nil
+}
+
+// filterIntentHistory returns a copy of the given intent history without the
+// entries written at an ignored sequence number. The copy has the capacity to
+// hold extra additional entries.
+func filterIntentHistory(
+	history []enginepb.MVCCMetadata_SequencedIntent, ignored []enginepb.IgnoredSeqNumRange, extra int,
+) []enginepb.MVCCMetadata_SequencedIntent {
+	ret := make([]enginepb.MVCCMetadata_SequencedIntent, 0, len(history)+extra)
+	for _, h := range history {
+		if !enginepb.TxnSeqIsIgnored(h.Sequence, ignored) {
+			ret = append(ret, h)
+		}
+	}
+	return ret
+}
+
+// appendIntentHistory returns a copy of the given intent history, dropping
+// the entries written at an ignored sequence number, with the value written at
+// the given sequence number appended to it unless that sequence number is
+// ignored as well.
+func appendIntentHistory(
+	history []enginepb.MVCCMetadata_SequencedIntent,
+	seq int32,
+	value []byte,
+	ignored []enginepb.IgnoredSeqNumRange,
+) []enginepb.MVCCMetadata_SequencedIntent {
+	ret := filterIntentHistory(history, ignored, 1 /* extra */)
+	if !enginepb.TxnSeqIsIgnored(seq, ignored) {
+		ret = append(ret, enginepb.MVCCMetadata_SequencedIntent{Sequence: seq, Value: value})
+	}
+	return ret
+}
+
 // MVCCIncrement fetches the value for key, and assuming the value is
 // an "integer" type, increments it by inc and stores the new
 // value. The newly incremented value is returned.
@@ -2248,7 +2320,24 @@ func mvccResolveWriteIntent(
 	// restart in EndTransaction, so the replay won't resolve intents.
 	epochsMatch := meta.Txn.Epoch == intent.Txn.Epoch
 	timestampsValid := !intent.Txn.Timestamp.Less(hlc.Timestamp(meta.Timestamp))
-	commit := intent.Status == roachpb.COMMITTED && epochsMatch && timestampsValid
+
+	// If the intent's current value was written at a sequence number that the
+	// transaction rolled back through a savepoint, revert it to the latest
+	// value in its history that wasn't. If there is no such value, the intent
+	// is removed below as if it was aborted.
+	var rolledBack, removeIntent bool
+	if epochsMatch && intent.Status != roachpb.ABORTED &&
+		enginepb.TxnSeqIsIgnored(meta.Txn.Sequence, intent.IgnoredSeqNums) {
+		rolledBack = true
+		var err error
+		removeIntent, origMetaKeySize, origMetaValSize, err = mvccRollbackIntentHistory(
+			engine, ms, intent, metaKey, origMetaKeySize, origMetaValSize, buf)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	commit := intent.Status == roachpb.COMMITTED && epochsMatch && timestampsValid && !removeIntent
 
 	// Note the small difference to commit epoch handling here: We allow
 	// a push from a previous epoch to move a newer intent. That's not
@@ -2277,7 +2366,7 @@ func mvccResolveWriteIntent(
 	// testing.
 	pushed := intent.Status == roachpb.PENDING &&
 		hlc.Timestamp(meta.Timestamp).Less(intent.Txn.Timestamp) &&
-		meta.Txn.Epoch >= intent.Txn.Epoch
+		meta.Txn.Epoch >= intent.Txn.Epoch && !removeIntent
 
 	// If we're committing, or if the commit timestamp of the intent has been moved forward, and if
 	// the proposed epoch matches the existing epoch: update the meta.Txn. For commit, it's set to
@@ -2364,9 +2453,10 @@ func mvccResolveWriteIntent(
 
 	// This method shouldn't be called in this instance, but there's
 	// nothing to do if meta's epoch is greater than or equal txn's
-	// epoch and the state is still PENDING.
-	if intent.Status == roachpb.PENDING && meta.Txn.Epoch >= intent.Txn.Epoch {
-		return false, nil
+	// epoch and the state is still PENDING, unless values were rolled
+	// back above.
+	if intent.Status == roachpb.PENDING && meta.Txn.Epoch >= intent.Txn.Epoch && !removeIntent {
+		return rolledBack, nil
 	}
 
 	// Otherwise, we're deleting the intent. We must find the next
@@ -2436,6 +2526,59 @@ func mvccResolveWriteIntent(
 	return true, nil
 }
 
+// mvccRollbackIntentHistory reverts the intent on the given key, whose current
+// value was written at an ignored sequence number, to the latest value in its
+// history that wasn't. It returns true if there is no such value, in which case
+// the intent is left untouched and the caller has to remove it. Otherwise,
+// buf.meta is updated to the reverted intent, and its new sizes are returned.
+func mvccRollbackIntentHistory(
+	engine ReadWriter,
+	ms *enginepb.MVCCStats,
+	intent roachpb.Intent,
+	metaKey MVCCKey,
+	origMetaKeySize, origMetaValSize int64,
+	buf *putBuffer,
+) (bool, int64, int64, error) {
+	meta := &buf.meta
+	idx, prevIntent, ok := meta.GetPrevIntentSeq(intent.IgnoredSeqNums)
+	if !ok {
+		return true, origMetaKeySize, origMetaValSize, nil
+	}
+
+	// Drop the restored value, as well as all the rolled back ones, from the
+	// history.
+	history := filterIntentHistory(meta.IntentHistory[:idx], intent.IgnoredSeqNums, 0 /* extra */)
+
+	txnMeta := *meta.Txn
+	txnMeta.Sequence = prevIntent.Sequence
+	buf.newMeta = *meta
+	buf.newMeta.Txn = &txnMeta
+	buf.newMeta.IntentHistory = history
+	buf.newMeta.ValBytes = int64(len(prevIntent.Value))
+	buf.newMeta.Deleted = len(prevIntent.Value) == 0
+
+	metaKeySize, metaValSize, err := buf.putMeta(engine, metaKey, &buf.newMeta)
+	if err != nil {
+		return false, 0, 0, err
+	}
+	versionKey := MVCCKey{Key: intent.Key, Timestamp: hlc.Timestamp(meta.Timestamp)}
+	if err := engine.Put(versionKey, prevIntent.Value); err != nil {
+		return false, 0, 0, err
+	}
+	if ms != nil {
+		ms.Add(updateStatsOnPut(intent.Key, 0 /* prevValSize */, origMetaKeySize, origMetaValSize,
+			metaKeySize, metaValSize, meta, &buf.newMeta))
+	}
+	engine.LogLogicalOp(MVCCUpdateIntentOpType, MVCCLogicalOpDetails{
+		Txn:       txnMeta,
+		Key:       intent.Key,
+		Timestamp: hlc.Timestamp(meta.Timestamp),
+	})
+
+	*meta = buf.newMeta
+	return false, metaKeySize, metaValSize, nil
+}
+
 // IterAndBuf used to pass iterators and buffers between MVCC* calls, allowing
 // reuse without the callers needing to know the particulars.
 type IterAndBuf struct {
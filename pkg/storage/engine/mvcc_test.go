@@ -3295,6 +3295,156 @@ func TestMVCCResolveTxnNoOps(t *testing.T) {
 	}
 }
 
+// TestMVCCResolveIgnoredSeqNums verifies that resolving a pending intent with
+// ignored sequence number ranges reverts it to the latest value written at a
+// sequence number that isn't ignored, or removes it if there is none.
+func TestMVCCResolveIgnoredSeqNums(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	engine := createTestEngine()
+	defer engine.Close()
+
+	ctx := context.Background()
+	ts := hlc.Timestamp{Logical: 1}
+	txn := *txn1
+	for _, v := range []roachpb.Value{value1, value2, value3} {
+		txn.Sequence++
+		if err := MVCCPut(ctx, engine, nil, testKey1, ts, v, &txn); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expectValue := func(txn *roachpb.Transaction, expected []byte) {
+		t.Helper()
+		value, _, err := MVCCGet(ctx, engine, testKey1, ts, true, txn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if value == nil {
+			if expected != nil {
+				t.Fatalf("expected %q, found no value", expected)
+			}
+			return
+		}
+		if !bytes.Equal(expected, value.RawBytes) {
+			t.Fatalf("expected %q, got %q", expected, value.RawBytes)
+		}
+	}
+
+	// A read by the transaction itself honors the ignored ranges before the
+	// intent is resolved.
+	readTxn := txn.Clone()
+	readTxn.AddIgnoredSeqNumRange(enginepb.IgnoredSeqNumRange{Start: 3, End: 3})
+	expectValue(&readTxn, value2.RawBytes)
+
+	// Resolving the intent as pending reverts it to the value written at
+	// sequence 1, which becomes visible to reads that don't know about the
+	// ignored ranges.
+	intent := roachpb.Intent{
+		Span:           roachpb.Span{Key: testKey1},
+		Txn:            txn.TxnMeta,
+		Status:         roachpb.PENDING,
+		IgnoredSeqNums: []enginepb.IgnoredSeqNumRange{{Start: 2, End: 3}},
+	}
+	if err := MVCCResolveWriteIntent(ctx, engine, nil, intent); err != nil {
+		t.Fatal(err)
+	}
+	expectValue(&txn, value1.RawBytes)
+
+	// Ignoring all the writes removes the intent.
+	intent.IgnoredSeqNums = []enginepb.IgnoredSeqNumRange{{Start: 1, End: 3}}
+	if err := MVCCResolveWriteIntent(ctx, engine, nil, intent); err != nil {
+		t.Fatal(err)
+	}
+	expectValue(nil /* txn */, nil)
+}
+
+// TestMVCCReadIgnoredSeqNums verifies that a transaction reading its own
+// intents through the engine's MVCCGet and MVCCScan does not see the writes at
+// sequence numbers it has rolled back.
+func TestMVCCReadIgnoredSeqNums(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	engine := createTestEngine()
+	defer engine.Close()
+
+	ctx := context.Background()
+	ts1 := hlc.Timestamp{Logical: 1}
+	ts2 := hlc.Timestamp{Logical: 2}
+	if err := MVCCPut(ctx, engine, nil, testKey1, ts1, value1, nil); err != nil {
+		t.Fatal(err)
+	}
+	txn := makeTxn(*txn1, ts2)
+	for _, w := range []struct {
+		key   roachpb.Key
+		value roachpb.Value
+	}{
+		{testKey1, value2},
+		{testKey1, value3},
+		{testKey2, value4},
+	} {
+		txn.Sequence++
+		if err := MVCCPut(ctx, engine, nil, w.key, ts2, w.value, txn); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, c := range []struct {
+		ignored []enginepb.IgnoredSeqNumRange
+		// expected holds the values of testKey1 and testKey2, nil if the key
+		// should not be found.
+		expected [2][]byte
+	}{
+		{nil, [2][]byte{value3.RawBytes, value4.RawBytes}},
+		{[]enginepb.IgnoredSeqNumRange{{Start: 2, End: 2}}, [2][]byte{value2.RawBytes, value4.RawBytes}},
+		{[]enginepb.IgnoredSeqNumRange{{Start: 2, End: 3}}, [2][]byte{value2.RawBytes, nil}},
+		{[]enginepb.IgnoredSeqNumRange{{Start: 1, End: 3}}, [2][]byte{value1.RawBytes, nil}},
+	} {
+		t.Run(fmt.Sprintf("%v", c.ignored), func(t *testing.T) {
+			readTxn := txn.Clone()
+			readTxn.IgnoredSeqNums = c.ignored
+
+			var expectedKVs []roachpb.KeyValue
+			for i, key := range []roachpb.Key{testKey1, testKey2} {
+				value, _, err := MVCCGet(ctx, engine, key, ts2, true, &readTxn)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if c.expected[i] == nil {
+					if value != nil {
+						t.Fatalf("%s: expected no value, got %q", key, value.RawBytes)
+					}
+					continue
+				}
+				if value == nil {
+					t.Fatalf("%s: expected %q, found no value", key, c.expected[i])
+				}
+				if !bytes.Equal(c.expected[i], value.RawBytes) {
+					t.Fatalf("%s: expected %q, got %q", key, c.expected[i], value.RawBytes)
+				}
+				expectedKVs = append(expectedKVs, roachpb.KeyValue{Key: key, Value: *value})
+			}
+
+			kvs, _, _, err := MVCCScan(ctx, engine, testKey1, testKey3, math.MaxInt64, ts2, true, &readTxn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(expectedKVs, kvs) {
+				t.Fatalf("scan: expected %v, got %v", expectedKVs, kvs)
+			}
+
+			kvs, _, _, err = MVCCReverseScan(ctx, engine, testKey1, testKey3, math.MaxInt64, ts2, true, &readTxn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, j := 0, len(kvs)-1; i < j; i, j = i+1, j-1 {
+				kvs[i], kvs[j] = kvs[j], kvs[i]
+			}
+			if !reflect.DeepEqual(expectedKVs, kvs) {
+				t.Fatalf("reverse scan: expected %v, got %v", expectedKVs, kvs)
+			}
+		})
+	}
+}
+
 func TestMVCCResolveTxnRange(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	engine := createTestEngine()
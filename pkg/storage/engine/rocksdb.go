@@ -2400,6 +2400,15 @@ func goToCTxn(txn *roachpb.Transaction) C.DBTxn {
 		r.id = goToCSlice(txn.ID.GetBytes())
 		r.epoch = C.uint32_t(txn.Epoch)
 		r.max_timestamp = goToCTimestamp(txn.MaxTimestamp)
+		if n := len(txn.IgnoredSeqNums); n > 0 {
+			// C.DBIgnoredSeqNumRange has the same layout as
+			// enginepb.IgnoredSeqNumRange, so the ranges are passed without
+			// copying them.
+			r.ignored_seqnums = C.DBIgnoredSeqNums{
+				ranges: (*C.DBIgnoredSeqNumRange)(unsafe.Pointer(&txn.IgnoredSeqNums[0])),
+				len:    C.int(n),
+			}
+		}
 	}
 	return r
 }
@@ -290,11 +290,12 @@ func (cq *contentionQueue) add(
 					RequestHeader: roachpb.RequestHeader{
 						Key: pusheeTxn.Key,
 					},
-					PusherTxn: getPusherTxn(h),
-					PusheeTxn: *pusheeTxn,
-					PushTo:    h.Timestamp,
-					Now:       cq.store.Clock().Now(),
-					PushType:  roachpb.PUSH_ABORT,
+					PusherTxn:    getPusherTxn(h),
+					PusheeTxn:    *pusheeTxn,
+					PushTo:       h.Timestamp,
+					Now:          cq.store.Clock().Now(),
+					PushType:     roachpb.PUSH_ABORT,
+					ContendedKey: intent.Key,
 				}
 				b := &client.Batch{}
 				b.AddRawRequest(pushReq)
@@ -447,6 +448,43 @@ func (ir *intentResolver) processWriteIntentError(
 	return cleanup, nil
 }
 
+// processWriteIntentErrorNoWait is like processWriteIntentError, but is used by
+// requests that don't want to wait for the transactions that hold conflicting
+// intents or locks (see LOCK_WAIT_ERROR). The intents of finalized and
+// abandoned transactions are still cleaned up, but if any of the transactions
+// is still active, a LockNotAvailableError is returned instead of waiting.
+func (ir *intentResolver) processWriteIntentErrorNoWait(
+	ctx context.Context, wiPErr *roachpb.Error, h roachpb.Header,
+) *roachpb.Error {
+	wiErr, ok := wiPErr.GetDetail().(*roachpb.WriteIntentError)
+	if !ok {
+		return roachpb.NewErrorf("not a WriteIntentError: %v", wiPErr)
+	}
+
+	resolveIntents, pErr := ir.maybePushIntents(
+		ctx, wiErr.Intents, h, roachpb.PUSH_TOUCH, false, /* skipIfInFlight */
+	)
+	if pErr != nil {
+		if pushErr, ok := pErr.GetDetail().(*roachpb.TransactionPushError); ok {
+			lock := wiErr.Intents[0]
+			for _, intent := range wiErr.Intents {
+				if intent.Txn.ID == pushErr.PusheeTxn.ID {
+					lock = intent
+					break
+				}
+			}
+			return roachpb.NewError(roachpb.NewLockNotAvailableError(lock))
+		}
+		return pErr
+	}
+
+	if err := ir.resolveIntents(ctx, resolveIntents,
+		ResolveOptions{Wait: false, Poison: true}); err != nil {
+		return roachpb.NewError(err)
+	}
+	return nil
+}
+
 func getPusherTxn(h roachpb.Header) roachpb.Transaction {
 	// If the txn is nil, we communicate a priority by sending an empty
 	// txn with only the priority set. This is official usage of PushTxn.
@@ -494,6 +532,7 @@ func (ir *intentResolver) maybePushIntents(
 ) ([]roachpb.Intent, *roachpb.Error) {
 	// Attempt to push the transaction(s) which created the conflicting intent(s).
 	pushTxns := make(map[uuid.UUID]enginepb.TxnMeta)
+	contendedKeys := make(map[uuid.UUID]roachpb.Key)
 	for _, intent := range intents {
 		if intent.Status != roachpb.PENDING {
 			// The current intent does not need conflict resolution
@@ -503,9 +542,14 @@ func (ir *intentResolver) maybePushIntents(
 			return nil, roachpb.NewErrorf("unexpected %s intent: %+v", intent.Status, intent)
 		}
 		pushTxns[intent.Txn.ID] = intent.Txn
+		if _, ok := contendedKeys[intent.Txn.ID]; !ok {
+			contendedKeys[intent.Txn.ID] = intent.Key
+		}
 	}
 
-	pushedTxns, pErr := ir.maybePushTransactions(ctx, pushTxns, h, pushType, skipIfInFlight)
+	pushedTxns, pErr := ir.maybePushTransactions(
+		ctx, pushTxns, contendedKeys, h, pushType, skipIfInFlight,
+	)
 	if pErr != nil {
 		return nil, pErr
 	}
@@ -523,6 +567,7 @@ func (ir *intentResolver) maybePushIntents(
 		}
 		intent.Txn = pushee.TxnMeta
 		intent.Status = pushee.Status
+		intent.IgnoredSeqNums = pushee.IgnoredSeqNums
 		resolveIntents = append(resolveIntents, intent)
 	}
 	return resolveIntents, nil
@@ -531,10 +576,12 @@ func (ir *intentResolver) maybePushIntents(
 // maybePushTransactions is like maybePushIntents except it takes a set of
 // transactions to push instead of a set of intents. This set of provided
 // transactions may be modified by the method. It returns a set of transaction
-// protos corresponding to the pushed transactions.
+// protos corresponding to the pushed transactions. The optional contendedKeys
+// map provides, for each transaction, the key on which it was encountered.
 func (ir *intentResolver) maybePushTransactions(
 	ctx context.Context,
 	pushTxns map[uuid.UUID]enginepb.TxnMeta,
+	contendedKeys map[uuid.UUID]roachpb.Key,
 	h roachpb.Header,
 	pushType roachpb.PushTxnType,
 	skipIfInFlight bool,
@@ -591,8 +638,9 @@ func (ir *intentResolver) maybePushTransactions(
 			// here, we would run into busy loops because that timestamp
 			// usually stays fixed among retries, so it will never realize
 			// that a transaction has timed out. See #877.
-			Now:      now,
-			PushType: pushType,
+			Now:          now,
+			PushType:     pushType,
+			ContendedKey: contendedKeys[pushTxn.ID],
 		})
 	}
 	b := &client.Batch{}
@@ -936,18 +984,20 @@ func (ir *intentResolver) resolveIntents(
 		intent := intents[i] // avoids a race in `i, intent := range ...`
 		if len(intent.EndKey) == 0 {
 			resolveReqs = append(resolveReqs, &roachpb.ResolveIntentRequest{
-				RequestHeader: roachpb.RequestHeaderFromSpan(intent.Span),
-				IntentTxn:     intent.Txn,
-				Status:        intent.Status,
-				Poison:        opts.Poison,
+				RequestHeader:  roachpb.RequestHeaderFromSpan(intent.Span),
+				IntentTxn:      intent.Txn,
+				Status:         intent.Status,
+				Poison:         opts.Poison,
+				IgnoredSeqNums: intent.IgnoredSeqNums,
 			})
 		} else {
 			resolveRangeReqs = append(resolveRangeReqs, &roachpb.ResolveIntentRangeRequest{
-				RequestHeader: roachpb.RequestHeaderFromSpan(intent.Span),
-				IntentTxn:     intent.Txn,
-				Status:        intent.Status,
-				Poison:        opts.Poison,
-				MinTimestamp:  opts.MinTimestamp,
+				RequestHeader:  roachpb.RequestHeaderFromSpan(intent.Span),
+				IntentTxn:      intent.Txn,
+				Status:         intent.Status,
+				Poison:         opts.Poison,
+				MinTimestamp:   opts.MinTimestamp,
+				IgnoredSeqNums: intent.IgnoredSeqNums,
 			})
 		}
 	}
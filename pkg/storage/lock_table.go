@@ -0,0 +1,168 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package storage
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/batcheval/result"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/interval"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+)
+
+// A lockTable maintains the unreplicated locks acquired over the keys of a
+// range by locking reads (i.e. SELECT ... FOR UPDATE and FOR SHARE). A lock
+// held by a transaction blocks the writes and the conflicting locking reads of
+// other transactions until the transaction finishes, which makes them queue up
+// in the txnWaitQueue instead of running into each other's intents and
+// restarting.
+//
+// Locks are acquired while the command queue is holding the latches of the
+// locking read, and conflicts are checked while it is holding the latches of
+// the conflicting request, so checking for conflicts and acquiring locks is
+// atomic with respect to other requests over the same keys.
+//
+// Locks are released when their transaction is finalized: they're tracked by
+// the transaction coordinator alongside its intents, and resolving the intents
+// of a committed or aborted transaction releases all of its locks on the range.
+//
+// The lockTable lives in memory on the leaseholder, so locks are lost when the
+// lease changes hands or when the range splits or merges. Locks are only an
+// optimization for contended workloads; transactions remain serializable
+// without them.
+//
+// lockTable is safe for concurrent use.
+type lockTable struct {
+	mu struct {
+		syncutil.Mutex
+		tree    interval.Tree
+		idAlloc int64
+		// byTxn indexes the locks in the tree by the ID of the transaction
+		// holding them.
+		byTxn map[uuid.UUID][]*lock
+	}
+}
+
+// lock is a lock held by a transaction over a span of keys.
+type lock struct {
+	id       int64
+	txn      enginepb.TxnMeta
+	strength roachpb.KeyLockingStrength
+	span     roachpb.Span
+}
+
+// ID implements interval.Interface.
+func (l *lock) ID() uintptr {
+	return uintptr(l.id)
+}
+
+// Range implements interval.Interface.
+func (l *lock) Range() interval.Range {
+	return l.span.AsRange()
+}
+
+func newLockTable() *lockTable {
+	lt := &lockTable{}
+	lt.mu.tree = interval.NewTree(interval.ExclusiveOverlapper)
+	lt.mu.byTxn = make(map[uuid.UUID][]*lock)
+	return lt
+}
+
+// acquire adds the given locks to the table.
+func (lt *lockTable) acquire(acqs []result.LockAcquisition) {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	for _, acq := range acqs {
+		lt.mu.idAlloc++
+		l := &lock{
+			id:       lt.mu.idAlloc,
+			txn:      acq.Txn,
+			strength: acq.Strength,
+			span:     acq.Span,
+		}
+		if err := lt.mu.tree.Insert(l, false /* fast */); err != nil {
+			panic(err)
+		}
+		lt.mu.byTxn[acq.Txn.ID] = append(lt.mu.byTxn[acq.Txn.ID], l)
+	}
+}
+
+// releaseTxn removes all the locks held by the given transaction.
+func (lt *lockTable) releaseTxn(txnID uuid.UUID) {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	locks, ok := lt.mu.byTxn[txnID]
+	if !ok {
+		return
+	}
+	for _, l := range locks {
+		if err := lt.mu.tree.Delete(l, false /* fast */); err != nil {
+			panic(err)
+		}
+	}
+	delete(lt.mu.byTxn, txnID)
+}
+
+// clear removes all the locks from the table.
+func (lt *lockTable) clear() {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	lt.mu.tree.Clear()
+	lt.mu.byTxn = make(map[uuid.UUID][]*lock)
+}
+
+// conflicting returns the locks held by other transactions over the given span
+// that conflict with a request from txn acquiring a lock of the given strength.
+// Writes are treated as acquiring an exclusive lock. txn is nil for
+// non-transactional requests, which conflict with the locks of all
+// transactions. The locks are returned as intents, so that they can be pushed
+// and resolved like intents.
+func (lt *lockTable) conflicting(
+	txn *roachpb.Transaction, strength roachpb.KeyLockingStrength, span roachpb.Span,
+) []roachpb.Intent {
+	if strength == roachpb.LOCK_NONE {
+		return nil
+	}
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	if lt.mu.tree.Len() == 0 {
+		return nil
+	}
+	var conflicts []roachpb.Intent
+	lt.mu.tree.DoMatching(func(i interval.Interface) bool {
+		l := i.(*lock)
+		if txn != nil && l.txn.ID == txn.ID {
+			return false
+		}
+		if strength == roachpb.LOCK_SHARED && l.strength == roachpb.LOCK_SHARED {
+			return false
+		}
+		conflicts = append(conflicts, roachpb.Intent{
+			Span:   l.span,
+			Txn:    l.txn,
+			Status: roachpb.PENDING,
+		})
+		return false
+	}, span.AsRange())
+	return conflicts
+}
+
+// len returns the number of locks in the table.
+func (lt *lockTable) len() int {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	return lt.mu.tree.Len()
+}
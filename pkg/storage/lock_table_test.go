@@ -0,0 +1,105 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/batcheval/result"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func lockSpan(from, to string) roachpb.Span {
+	sp := roachpb.Span{Key: roachpb.Key(from)}
+	if to != "" {
+		sp.EndKey = roachpb.Key(to)
+	}
+	return sp
+}
+
+func TestLockTableConflicts(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	clock := hlc.NewClock(hlc.UnixNano, 0)
+	txn1 := newTransaction("txn1", roachpb.Key("a"), 1, enginepb.SERIALIZABLE, clock)
+	txn2 := newTransaction("txn2", roachpb.Key("a"), 1, enginepb.SERIALIZABLE, clock)
+
+	lt := newLockTable()
+	lt.acquire([]result.LockAcquisition{
+		{Txn: txn1.TxnMeta, Strength: roachpb.LOCK_EXCLUSIVE, Span: lockSpan("b", "d")},
+		{Txn: txn1.TxnMeta, Strength: roachpb.LOCK_SHARED, Span: lockSpan("f", "")},
+	})
+	if l := lt.len(); l != 2 {
+		t.Fatalf("expected 2 locks, got %d", l)
+	}
+
+	testCases := []struct {
+		txn      *roachpb.Transaction
+		strength roachpb.KeyLockingStrength
+		span     roachpb.Span
+		expected int
+	}{
+		// Non-locking requests never conflict.
+		{txn2, roachpb.LOCK_NONE, lockSpan("a", "z"), 0},
+		// The locks of a txn don't conflict with its own requests.
+		{txn1, roachpb.LOCK_EXCLUSIVE, lockSpan("a", "z"), 0},
+		// Exclusive locks conflict with everything.
+		{txn2, roachpb.LOCK_SHARED, lockSpan("c", ""), 1},
+		{txn2, roachpb.LOCK_EXCLUSIVE, lockSpan("a", "c"), 1},
+		{nil, roachpb.LOCK_EXCLUSIVE, lockSpan("c", "e"), 1},
+		// Shared locks only conflict with exclusive ones.
+		{txn2, roachpb.LOCK_SHARED, lockSpan("f", ""), 0},
+		{txn2, roachpb.LOCK_EXCLUSIVE, lockSpan("f", ""), 1},
+		{txn2, roachpb.LOCK_EXCLUSIVE, lockSpan("a", "z"), 2},
+		// Spans are exclusive of their end key.
+		{txn2, roachpb.LOCK_EXCLUSIVE, lockSpan("d", "f"), 0},
+	}
+	for i, c := range testCases {
+		conflicts := lt.conflicting(c.txn, c.strength, c.span)
+		if len(conflicts) != c.expected {
+			t.Errorf("%d: expected %d conflicts, got %+v", i, c.expected, conflicts)
+		}
+		for _, conflict := range conflicts {
+			if conflict.Txn.ID != txn1.ID || conflict.Status != roachpb.PENDING {
+				t.Errorf("%d: unexpected conflict %+v", i, conflict)
+			}
+		}
+	}
+
+	// Releasing the txn's locks removes all of them.
+	lt.releaseTxn(txn2.ID)
+	if l := lt.len(); l != 2 {
+		t.Fatalf("expected 2 locks, got %d", l)
+	}
+	lt.releaseTxn(txn1.ID)
+	if l := lt.len(); l != 0 {
+		t.Fatalf("expected no locks, got %d", l)
+	}
+	if conflicts := lt.conflicting(txn2, roachpb.LOCK_EXCLUSIVE, lockSpan("a", "z")); len(conflicts) != 0 {
+		t.Fatalf("expected no conflicts, got %+v", conflicts)
+	}
+
+	lt.acquire([]result.LockAcquisition{
+		{Txn: txn1.TxnMeta, Strength: roachpb.LOCK_EXCLUSIVE, Span: lockSpan("b", "d")},
+		{Txn: txn2.TxnMeta, Strength: roachpb.LOCK_SHARED, Span: lockSpan("f", "")},
+	})
+	lt.clear()
+	if l := lt.len(); l != 0 {
+		t.Fatalf("expected no locks, got %d", l)
+	}
+}
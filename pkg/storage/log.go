@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
 
 // RangeLogEventReason specifies the reason why a range-log event happened.
@@ -218,3 +219,71 @@ func selectEventTimestamp(s *Store, input hlc.Timestamp) time.Time {
 	}
 	return input.GoTime()
 }
+
+// EventLogTxnDeadlock is the type of the event recorded into the event table
+// when a dependency cycle between transactions is broken by aborting one of
+// them.
+const EventLogTxnDeadlock = "txn_deadlock"
+
+// TxnDeadlockEventInfo is the json details of a txn_deadlock event.
+type TxnDeadlockEventInfo struct {
+	// RangeID is the range holding the record of the aborted transaction.
+	RangeID roachpb.RangeID
+	// PusherTxnID is the transaction which detected the deadlock.
+	PusherTxnID uuid.UUID
+	// AbortedTxnID is the transaction aborted to break the deadlock.
+	AbortedTxnID uuid.UUID
+	// Key is the key on which the pusher was blocked, if known.
+	Key string `json:",omitempty"`
+}
+
+// logTxnDeadlock asynchronously records into the event table that the
+// pushee of the given PushTxn request is being aborted to break a deadlock
+// with the pusher.
+func (s *Store) logTxnDeadlock(
+	ctx context.Context, rangeID roachpb.RangeID, req *roachpb.PushTxnRequest,
+) {
+	log.Infof(ctx, "breaking deadlock: %s aborting %s",
+		req.PusherTxn.ID.Short(), req.PusheeTxn.ID.Short())
+	if !s.cfg.LogRangeEvents {
+		return
+	}
+	info := TxnDeadlockEventInfo{
+		RangeID:      rangeID,
+		PusherTxnID:  req.PusherTxn.ID,
+		AbortedTxnID: req.PusheeTxn.ID,
+	}
+	if len(req.ContendedKey) > 0 {
+		info.Key = req.ContendedKey.String()
+	}
+	infoBytes, err := json.Marshal(info)
+	if err != nil {
+		log.Warningf(ctx, "unable to log %s event: %s", EventLogTxnDeadlock, err)
+		return
+	}
+
+	const insertEventTableStmt = `
+	INSERT INTO system.eventlog (
+		timestamp, "eventType", "targetID", "reportingID", info
+	)
+	VALUES(
+		now(), $1, $2, $3, $4
+	)
+	`
+	// The event is logged outside of the context of the push, which must not
+	// wait for it.
+	logCtx := s.AnnotateCtx(context.Background())
+	if err := s.stopper.RunAsyncTask(logCtx, "storage.Store: log txn deadlock", func(ctx context.Context) {
+		if err := s.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+			_, err := s.cfg.SQLExecutor.Exec(
+				ctx, "log-txn-deadlock", txn, insertEventTableStmt,
+				EventLogTxnDeadlock, int32(s.StoreID()), int32(s.Ident.NodeID), string(infoBytes),
+			)
+			return err
+		}); err != nil {
+			log.Warningf(ctx, "unable to log %s event: %s", EventLogTxnDeadlock, err)
+		}
+	}); err != nil {
+		log.Warningf(ctx, "unable to log %s event: %s", EventLogTxnDeadlock, err)
+	}
+}
@@ -922,6 +922,14 @@ var (
 		Unit:        metric.Unit_COUNT,
 	}
 
+	// Txn wait queue metrics.
+	metaTxnDeadlocks = metric.Metadata{
+		Name:        "txnwaitqueue.deadlocks_total",
+		Help:        "Number of deadlocks between transactions broken by aborting one of them",
+		Measurement: "Deadlocks",
+		Unit:        metric.Unit_COUNT,
+	}
+
 	// Slow request metrics.
 	metaSlowCommandQueueRequests = metric.Metadata{
 		Name:        "requests.slow.commandqueue",
@@ -1159,6 +1167,9 @@ type StoreMetrics struct {
 	// Intent resolver metrics.
 	IntentResolverAsyncThrottled *metric.Counter
 
+	// Txn wait queue metrics.
+	TxnDeadlocks *metric.Counter
+
 	// Slow request counts.
 	SlowCommandQueueRequests *metric.Gauge
 	SlowLeaseRequests        *metric.Gauge
@@ -1362,6 +1373,9 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		// Intent resolver metrics.
 		IntentResolverAsyncThrottled: metric.NewCounter(metaIntentResolverAsyncThrottled),
 
+		// Txn wait queue metrics.
+		TxnDeadlocks: metric.NewCounter(metaTxnDeadlocks),
+
 		// Wedge request counters.
 		SlowCommandQueueRequests: metric.NewGauge(metaSlowCommandQueueRequests),
 		SlowLeaseRequests:        metric.NewGauge(metaSlowLeaseRequests),
@@ -346,11 +346,16 @@ func (p *Processor) sendStop(pErr *roachpb.Error) {
 // clean up the iterator by calling its Close method when it is finished. If the
 // iterator is nil then no catch-up scan will be performed.
 //
+// If withDiff is true, the value events published to the registration include
+// the previous value of each key. In that case, the catch-up iterator must also
+// expose the versions beneath the starting timestamp.
+//
 // NOT safe to call on nil Processor.
 func (p *Processor) Register(
 	span roachpb.RSpan,
 	startTS hlc.Timestamp,
 	catchUpIter engine.SimpleIterator,
+	withDiff bool,
 	stream Stream,
 	errC chan<- *roachpb.Error,
 ) {
@@ -359,16 +364,21 @@ func (p *Processor) Register(
 	// it should see these events during its catch up scan.
 	p.syncEventC()
 
+	if withDiff {
+		p.reg.addDiffReg()
+	}
 	r := registration{
 		span:        span.AsRawSpanWithNoLocals(),
 		startTS:     startTS,
 		catchUpIter: catchUpIter,
+		withDiff:    withDiff,
 		stream:      stream,
 		errC:        errC,
 	}
 	select {
 	case p.regC <- r:
 	case <-p.stoppedC:
+		p.reg.unregistered(&r)
 		if catchUpIter != nil {
 			catchUpIter.Close() // clean up
 		}
@@ -376,6 +386,17 @@ func (p *Processor) Register(
 	}
 }
 
+// NeedsPrevValues returns whether any registration requested the previous
+// values of keys along with value events. If none did, the logical ops passed
+// to ConsumeLogicalOps don't need to have their previous values populated.
+// Safe to call on nil Processor.
+func (p *Processor) NeedsPrevValues() bool {
+	if p == nil {
+		return false
+	}
+	return p.reg.HasDiffRegs()
+}
+
 // Len returns the number of registrations attached to the processor.
 func (p *Processor) Len() int {
 	if p == nil {
@@ -532,7 +553,7 @@ func (p *Processor) consumeLogicalOps(ctx context.Context, ops []enginepb.MVCCLo
 		switch t := op.GetValue().(type) {
 		case *enginepb.MVCCWriteValueOp:
 			// Publish the new value directly.
-			p.publishValue(ctx, t.Key, t.Timestamp, t.Value)
+			p.publishValue(ctx, t.Key, t.Timestamp, t.Value, t.PrevValue)
 
 		case *enginepb.MVCCWriteIntentOp:
 			// No updates to publish.
@@ -542,7 +563,7 @@ func (p *Processor) consumeLogicalOps(ctx context.Context, ops []enginepb.MVCCLo
 
 		case *enginepb.MVCCCommitIntentOp:
 			// Publish the newly committed value.
-			p.publishValue(ctx, t.Key, t.Timestamp, t.Value)
+			p.publishValue(ctx, t.Key, t.Timestamp, t.Value, t.PrevValue)
 
 		case *enginepb.MVCCAbortIntentOp:
 			// No updates to publish.
@@ -572,7 +593,7 @@ func (p *Processor) initResolvedTS(ctx context.Context) {
 }
 
 func (p *Processor) publishValue(
-	ctx context.Context, key roachpb.Key, timestamp hlc.Timestamp, value []byte,
+	ctx context.Context, key roachpb.Key, timestamp hlc.Timestamp, value, prevValue []byte,
 ) {
 	if !p.Span.ContainsKey(roachpb.RKey(key)) {
 		log.Fatalf(ctx, "key %v not in Processor's key range %v", key, p.Span)
@@ -586,6 +607,9 @@ func (p *Processor) publishValue(
 			RawBytes:  value,
 			Timestamp: timestamp,
 		},
+		PrevValue: roachpb.Value{
+			RawBytes: prevValue,
+		},
 	})
 	p.reg.PublishToOverlapping(span, &event)
 }
@@ -108,6 +108,14 @@ func rangeFeedValue(key roachpb.Key, val roachpb.Value) *roachpb.RangeFeedEvent
 	})
 }
 
+func rangeFeedValueWithPrev(key roachpb.Key, val, prev roachpb.Value) *roachpb.RangeFeedEvent {
+	return makeRangeFeedEvent(&roachpb.RangeFeedValue{
+		Key:       key,
+		Value:     val,
+		PrevValue: prev,
+	})
+}
+
 func rangeFeedCheckpoint(span roachpb.Span, ts hlc.Timestamp) *roachpb.RangeFeedEvent {
 	return makeRangeFeedEvent(&roachpb.RangeFeedCheckpoint{
 		Span:       span,
@@ -170,6 +178,7 @@ func TestProcessor(t *testing.T) {
 	})
 	require.NotPanics(t, func() { p.ForwardClosedTS(hlc.Timestamp{}) })
 	require.NotPanics(t, func() { p.ForwardClosedTS(hlc.Timestamp{WallTime: 1}) })
+	require.False(t, p.NeedsPrevValues())
 
 	// Add a registration.
 	r1Stream := newTestStream()
@@ -177,7 +186,8 @@ func TestProcessor(t *testing.T) {
 	p.Register(
 		roachpb.RSpan{Key: roachpb.RKey("a"), EndKey: roachpb.RKey("m")},
 		hlc.Timestamp{WallTime: 1},
-		nil, /* catchUpIter */
+		nil,   /* catchUpIter */
+		false, /* withDiff */
 		r1Stream,
 		r1ErrC,
 	)
@@ -290,7 +300,8 @@ func TestProcessor(t *testing.T) {
 	p.Register(
 		roachpb.RSpan{Key: roachpb.RKey("c"), EndKey: roachpb.RKey("z")},
 		hlc.Timestamp{WallTime: 1},
-		nil, /* catchUpIter */
+		nil,   /* catchUpIter */
+		false, /* withDiff */
 		r2Stream,
 		r2ErrC,
 	)
@@ -365,11 +376,37 @@ func TestNilProcessor(t *testing.T) {
 	require.NotPanics(t, func() { p.ConsumeLogicalOps(make([]enginepb.MVCCLogicalOp, 5)...) })
 	require.NotPanics(t, func() { p.ForwardClosedTS(hlc.Timestamp{}) })
 	require.NotPanics(t, func() { p.ForwardClosedTS(hlc.Timestamp{WallTime: 1}) })
+	require.False(t, p.NeedsPrevValues())
 
 	// The following should panic because they are not safe
 	// to call on a nil Processor.
 	require.Panics(t, func() { p.Start(stop.NewStopper(), nil) })
-	require.Panics(t, func() { p.Register(roachpb.RSpan{}, hlc.Timestamp{}, nil, nil, nil) })
+	require.Panics(t, func() { p.Register(roachpb.RSpan{}, hlc.Timestamp{}, nil, false, nil, nil) })
+}
+
+func TestProcessorNeedsPrevValues(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	p, stopper := newTestProcessor(nil /* rtsIter */)
+	defer stopper.Stop(context.Background())
+
+	// A registration without diffs doesn't need the previous values.
+	r1Stream := newTestStream()
+	r1ErrC := make(chan *roachpb.Error, 1)
+	p.Register(p.Span, hlc.Timestamp{}, nil, false /* withDiff */, r1Stream, r1ErrC)
+	require.False(t, p.NeedsPrevValues())
+
+	// They're needed as soon as a registration with diffs is added...
+	r2Stream := newTestStream()
+	r2ErrC := make(chan *roachpb.Error, 1)
+	p.Register(p.Span, hlc.Timestamp{}, nil, true /* withDiff */, r2Stream, r2ErrC)
+	require.True(t, p.NeedsPrevValues())
+
+	// ... and until it's removed.
+	r2Stream.Cancel()
+	require.NotNil(t, <-r2ErrC)
+	p.syncEventC()
+	require.False(t, p.NeedsPrevValues())
+	require.Equal(t, 1, p.Len())
 }
 
 func TestProcessorSlowConsumer(t *testing.T) {
@@ -386,7 +423,8 @@ func TestProcessorSlowConsumer(t *testing.T) {
 	p.Register(
 		roachpb.RSpan{Key: roachpb.RKey("a"), EndKey: roachpb.RKey("m")},
 		hlc.Timestamp{WallTime: 1},
-		nil, /* catchUpIter */
+		nil,   /* catchUpIter */
+		false, /* withDiff */
 		r1Stream,
 		r1ErrC,
 	)
@@ -489,7 +527,8 @@ func TestProcessorInitializeResolvedTimestamp(t *testing.T) {
 	p.Register(
 		roachpb.RSpan{Key: roachpb.RKey("a"), EndKey: roachpb.RKey("m")},
 		hlc.Timestamp{WallTime: 1},
-		nil, /* catchUpIter */
+		nil,   /* catchUpIter */
+		false, /* withDiff */
 		r1Stream,
 		make(chan *roachpb.Error, 1),
 	)
@@ -573,6 +612,7 @@ func TestProcessorCatchUpScan(t *testing.T) {
 		roachpb.RSpan{Key: roachpb.RKey("a"), EndKey: roachpb.RKey("w")},
 		hlc.Timestamp{WallTime: 2}, // too large to see key @ m
 		catchUpIter,
+		false, /* withDiff */
 		r1Stream,
 		make(chan *roachpb.Error, 1),
 	)
@@ -663,6 +703,7 @@ func TestProcessorCatchUpScan(t *testing.T) {
 		roachpb.RSpan{Key: roachpb.RKey("a"), EndKey: roachpb.RKey("m")},
 		hlc.Timestamp{WallTime: 1},
 		errCatchUpIter,
+		false, /* withDiff */
 		r2Stream,
 		r2ErrC,
 	)
@@ -865,7 +906,7 @@ func TestProcessorConcurrentStop(t *testing.T) {
 			runtime.Gosched()
 			s := newTestStream()
 			errC := make(chan<- *roachpb.Error, 1)
-			p.Register(p.Span, hlc.Timestamp{}, nil, s, errC)
+			p.Register(p.Span, hlc.Timestamp{}, nil, false, s, errC)
 		}()
 		go func() {
 			defer wg.Done()
@@ -934,7 +975,7 @@ func TestProcessorRegistrationObservesOnlyNewEvents(t *testing.T) {
 			s := newTestStream()
 			regs[s] = firstIdx
 			errC := make(chan *roachpb.Error, 1)
-			p.Register(p.Span, hlc.Timestamp{}, nil, s, errC)
+			p.Register(p.Span, hlc.Timestamp{}, nil, false, s, errC)
 			regDone <- struct{}{}
 		}
 	}()
@@ -17,6 +17,7 @@ package rangefeed
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 
 	"github.com/pkg/errors"
 
@@ -59,6 +60,9 @@ type registration struct {
 	catchUpIter engine.SimpleIterator
 	caughtUp    bool
 
+	// Whether value events should include the previous value of the key.
+	withDiff bool
+
 	// Output.
 	stream Stream
 	errC   chan<- *roachpb.Error
@@ -86,6 +90,12 @@ func (r registration) String() string {
 type registry struct {
 	tree    interval.Tree // *registration items
 	idAlloc int64
+
+	// diffRegs is the number of registrations with withDiff set. It's
+	// incremented by Processor.Register, before the registration is handed to
+	// the Processor goroutine, so that the value events consumed once Register
+	// returns are populated with previous values. Accessed atomically.
+	diffRegs int32
 }
 
 func makeRegistry() registry {
@@ -129,11 +139,22 @@ func (reg *registry) PublishToOverlapping(span roachpb.Span, event *roachpb.Rang
 	// can have while still needing to hear about this event.
 	var minTS hlc.Timestamp
 	var requireCaughtUp bool
+	// noDiffEvent is the version of a value event without the previous value,
+	// which is published to registrations that didn't ask for it.
+	var noDiffEvent *roachpb.RangeFeedEvent
 	switch t := event.GetValue().(type) {
 	case *roachpb.RangeFeedValue:
 		// Only publish values to registrations with starting
 		// timestamps equal to or greater than the value's timestamp.
 		minTS = t.Value.Timestamp
+		noDiffEvent = event
+		if t.PrevValue.RawBytes != nil {
+			noDiffEvent = &roachpb.RangeFeedEvent{}
+			noDiffEvent.MustSetValue(&roachpb.RangeFeedValue{
+				Key:   t.Key,
+				Value: t.Value,
+			})
+		}
 	case *roachpb.RangeFeedCheckpoint:
 		// Always publish checkpoint notifications, regardless
 		// of a registration's starting timestamp.
@@ -155,7 +176,11 @@ func (reg *registry) PublishToOverlapping(span roachpb.Span, event *roachpb.Rang
 			return false, nil
 		}
 
-		err := r.stream.Send(event)
+		e := event
+		if noDiffEvent != nil && !r.withDiff {
+			e = noDiffEvent
+		}
+		err := r.stream.Send(e)
 		return err != nil, roachpb.NewError(err)
 	})
 }
@@ -163,11 +188,32 @@ func (reg *registry) PublishToOverlapping(span roachpb.Span, event *roachpb.Rang
 // DisconnectRegWithError disconnects a specific registration with a provided error.
 func (reg *registry) DisconnectRegWithError(r *registration, pErr *roachpb.Error) {
 	r.errC <- pErr
+	reg.unregistered(r)
 	if err := reg.tree.Delete(r, false /* fast */); err != nil {
 		panic(err)
 	}
 }
 
+// addDiffReg records that a registration with withDiff set is being added.
+// Safe to call from any goroutine.
+func (reg *registry) addDiffReg() {
+	atomic.AddInt32(&reg.diffRegs, 1)
+}
+
+// unregistered updates the accounting of the registry for a registration
+// which is being removed.
+func (reg *registry) unregistered(r *registration) {
+	if r.withDiff {
+		atomic.AddInt32(&reg.diffRegs, -1)
+	}
+}
+
+// HasDiffRegs returns whether any registration has withDiff set. Safe to call
+// from any goroutine.
+func (reg *registry) HasDiffRegs() bool {
+	return atomic.LoadInt32(&reg.diffRegs) > 0
+}
+
 // Disconnect disconnects all registrations that overlap the specified span with
 // a nil error.
 func (reg *registry) Disconnect(span roachpb.Span) {
@@ -207,6 +253,7 @@ func (reg *registry) forOverlappingRegs(
 		dis, pErr := fn(r)
 		if dis {
 			r.errC <- pErr
+			reg.unregistered(r)
 			toDelete = append(toDelete, i)
 		}
 		return false
@@ -285,6 +285,34 @@ func TestRegistryPublishBeneathStartTimestamp(t *testing.T) {
 	require.Equal(t, []*roachpb.RangeFeedEvent{ev}, r.Events())
 }
 
+func TestRegistryPublishWithDiff(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	reg := makeRegistry()
+
+	rDiff := newTestRegistration(spAB)
+	rDiff.registration.withDiff = true
+	reg.Register(&rDiff.registration)
+	rNoDiff := newTestRegistration(spAB)
+	reg.Register(&rNoDiff.registration)
+
+	// Only the registration that asked for diffs sees the previous value.
+	val := roachpb.Value{RawBytes: []byte("val"), Timestamp: hlc.Timestamp{WallTime: 5}}
+	prev := roachpb.Value{RawBytes: []byte("prev")}
+	ev := new(roachpb.RangeFeedEvent)
+	ev.MustSetValue(&roachpb.RangeFeedValue{
+		Key:       roachpb.Key("a"),
+		Value:     val,
+		PrevValue: prev,
+	})
+	reg.PublishToOverlapping(spAB, ev)
+	require.Equal(t, []*roachpb.RangeFeedEvent{
+		rangeFeedValueWithPrev(roachpb.Key("a"), val, prev),
+	}, rDiff.Events())
+	require.Equal(t, []*roachpb.RangeFeedEvent{
+		rangeFeedValue(roachpb.Key("a"), val),
+	}, rNoDiff.Events())
+}
+
 func TestRegistryPublishCheckpointNotCaughtUp(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	reg := makeRegistry()
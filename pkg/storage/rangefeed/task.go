@@ -126,7 +126,9 @@ func (s *initResolvedTSScan) Cancel() {
 //   ignored, but all values above the registration's starting timestamp must be
 //   present. An important implication of this is that if the iterator is a
 //   TimeBoundIterator, its MinTimestamp cannot be above the registration's
-//   starting timestamp.
+//   starting timestamp. If the registration wants diffs, the newest value at or
+//   beneath the starting timestamp must also be present, so a
+//   TimeBoundIterator cannot have any lower bound.
 //
 type catchUpScan struct {
 	p  *Processor
@@ -156,6 +158,20 @@ func (s *catchUpScan) iterateAndSend(ctx context.Context) error {
 	startKey := engine.MakeMVCCMetadataKey(s.r.span.Key)
 	endKey := engine.MakeMVCCMetadataKey(s.r.span.EndKey)
 
+	// If the registration wants diffs, the event for each version is held back
+	// until the next (older) version of the same key is seen, since that's its
+	// previous value. Versions are iterated in decreasing timestamp order.
+	var pending *roachpb.RangeFeedValue
+	sendPending := func() error {
+		if pending == nil {
+			return nil
+		}
+		var event roachpb.RangeFeedEvent
+		event.MustSetValue(pending)
+		pending = nil
+		return s.r.stream.Send(&event)
+	}
+
 	// Iterate though all keys using Next. We want to publish all committed
 	// versions of each key that are after the registration's startTS, so we
 	// can't use NextKey.
@@ -169,6 +185,12 @@ func (s *catchUpScan) iterateAndSend(ctx context.Context) error {
 
 		unsafeKey := s.it.UnsafeKey()
 		unsafeVal := s.it.UnsafeValue()
+		if pending != nil && !pending.Key.Equal(unsafeKey.Key) {
+			// The previous key had no older versions.
+			if err := sendPending(); err != nil {
+				return err
+			}
+		}
 		if !unsafeKey.IsValue() {
 			// Found a metadata key.
 			if err := protoutil.Unmarshal(unsafeVal, &meta); err != nil {
@@ -185,7 +207,14 @@ func (s *catchUpScan) iterateAndSend(ctx context.Context) error {
 			unsafeVal = meta.RawBytes
 		} else if !s.r.startTS.Less(unsafeKey.Timestamp) {
 			// At or before the registration's exclusive starting timestamp.
-			// Ignore.
+			// The newest such version is the previous value of the oldest
+			// version that is published, if any. Otherwise, ignore.
+			if pending != nil {
+				s.a, pending.PrevValue.RawBytes = s.a.Copy(unsafeVal, 0)
+				if err := sendPending(); err != nil {
+					return err
+				}
+			}
 			continue
 		}
 
@@ -194,19 +223,30 @@ func (s *catchUpScan) iterateAndSend(ctx context.Context) error {
 		s.a, val = s.a.Copy(unsafeVal, 0)
 		ts := unsafeKey.Timestamp
 
-		var event roachpb.RangeFeedEvent
-		event.MustSetValue(&roachpb.RangeFeedValue{
+		if pending != nil {
+			pending.PrevValue.RawBytes = val
+			if err := sendPending(); err != nil {
+				return err
+			}
+		}
+		v := &roachpb.RangeFeedValue{
 			Key: key,
 			Value: roachpb.Value{
 				RawBytes:  val,
 				Timestamp: ts,
 			},
-		})
+		}
+		if s.r.withDiff && unsafeKey.IsValue() {
+			pending = v
+			continue
+		}
+		var event roachpb.RangeFeedEvent
+		event.MustSetValue(v)
 		if err := s.r.stream.Send(&event); err != nil {
 			return err
 		}
 	}
-	return nil
+	return sendPending()
 }
 
 func (s *catchUpScan) Cancel() {
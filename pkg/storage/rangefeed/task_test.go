@@ -277,6 +277,60 @@ func TestCatchUpScan(t *testing.T) {
 	require.Equal(t, catchUpResult{r: &r.registration}, <-p.catchUpC)
 }
 
+func TestCatchUpScanWithDiff(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	// Mock processor. We just needs its catchUpC.
+	p := Processor{catchUpC: make(chan catchUpResult, 1)}
+
+	txn1 := uuid.MakeV4()
+	iter := newTestIterator([]engine.MVCCKeyValue{
+		makeKV("a", "val1", 10),
+		makeKV("a", "val2", 3),
+		makeIntent("c", txn1, "txnKey1", 15),
+		makeKV("c", "val3", 11),
+		makeKV("c", "val4", 9),
+		makeKV("c", "", 5),
+		makeKV("c", "val5", 2),
+		makeInline("g", "val6"),
+		makeKV("m", "val7", 8),
+		makeKV("r", "val8", 4),
+		makeKV("r", "val9", 1),
+	})
+	r := newTestRegistration(roachpb.Span{
+		Key:    roachpb.Key("a"),
+		EndKey: roachpb.Key("z"),
+	})
+	r.catchUpIter = iter
+	r.startTS = hlc.Timestamp{WallTime: 4}
+	r.withDiff = true
+
+	catchUpScan := newCatchUpScan(&p, &r.registration)
+	catchUpScan.Run(context.Background())
+	require.True(t, iter.closed)
+
+	// Each value includes the next older version of its key, even if that
+	// version is at or beneath the registration's starting timestamp. A deletion
+	// has an empty value.
+	val := func(v string, ts int64) roachpb.Value {
+		return roachpb.Value{RawBytes: []byte(v), Timestamp: hlc.Timestamp{WallTime: ts}}
+	}
+	prev := func(v string) roachpb.Value {
+		return roachpb.Value{RawBytes: []byte(v)}
+	}
+	expEvents := []*roachpb.RangeFeedEvent{
+		rangeFeedValueWithPrev(roachpb.Key("a"), val("val1", 10), prev("val2")),
+		rangeFeedValueWithPrev(roachpb.Key("c"), val("val3", 11), prev("val4")),
+		rangeFeedValueWithPrev(roachpb.Key("c"), val("val4", 9), prev("")),
+		rangeFeedValueWithPrev(roachpb.Key("c"), val("", 5), prev("val5")),
+		rangeFeedValue(roachpb.Key("g"), val("val6", 0)),
+		rangeFeedValue(roachpb.Key("m"), val("val7", 8)),
+	}
+	require.Equal(t, expEvents, r.Events())
+	require.Equal(t, 1, len(p.catchUpC))
+	require.Equal(t, catchUpResult{r: &r.registration}, <-p.catchUpC)
+}
+
 type testTxnPusher struct {
 	pushTxnsFn               func([]enginepb.TxnMeta, hlc.Timestamp) ([]roachpb.Transaction, error)
 	cleanupTxnIntentsAsyncFn func([]roachpb.Transaction) error
@@ -45,6 +45,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage/abortspan"
 	"github.com/cockroachdb/cockroach/pkg/storage/batcheval"
 	"github.com/cockroachdb/cockroach/pkg/storage/batcheval/result"
+	"github.com/cockroachdb/cockroach/pkg/storage/closedts"
 	"github.com/cockroachdb/cockroach/pkg/storage/closedts/ctpb"
 	ctstorage "github.com/cockroachdb/cockroach/pkg/storage/closedts/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine"
@@ -52,6 +53,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage/rangefeed"
 	"github.com/cockroachdb/cockroach/pkg/storage/rditer"
 	"github.com/cockroachdb/cockroach/pkg/storage/spanset"
+	"github.com/cockroachdb/cockroach/pkg/storage/split"
 	"github.com/cockroachdb/cockroach/pkg/storage/stateloader"
 	"github.com/cockroachdb/cockroach/pkg/storage/storagebase"
 	"github.com/cockroachdb/cockroach/pkg/storage/txnwait"
@@ -124,16 +126,6 @@ var MaxCommandSize = settings.RegisterValidatedByteSizeSetting(
 	},
 )
 
-// FollowerReadsEnabled controls whether replicas attempt to serve follower
-// reads. The closed timestamp machinery is unaffected by this, i.e. the same
-// information is collected and passed around, regardless of the value of this
-// setting.
-var FollowerReadsEnabled = settings.RegisterBoolSetting(
-	"kv.closed_timestamp.follower_reads_enabled",
-	"allow (all) replicas to serve consistent historical reads based on closed timestamp information",
-	false,
-)
-
 type proposalRetryReason int
 
 const (
@@ -268,6 +260,7 @@ type Replica struct {
 	store        *Store
 	abortSpan    *abortspan.AbortSpan // Avoids anomalous reads after abort
 	txnWaitQueue *txnwait.Queue       // Queues push txn attempts by txn ID
+	lockTable    *lockTable           // Unreplicated locks of locking reads
 
 	// leaseholderStats tracks all incoming BatchRequests to the replica and which
 	// localities they come from in order to aid in lease rebalancing decisions.
@@ -275,6 +268,9 @@ type Replica struct {
 	// writeStats tracks the number of keys written by applied raft commands
 	// in order to aid in replica rebalancing decisions.
 	writeStats *replicaStats
+	// loadBasedSplitter tracks the QPS of the replica and the spans of the
+	// requests it receives in order to find keys to split hot ranges at.
+	loadBasedSplitter split.Decider
 
 	// creatingReplica is set when a replica is created as uninitialized
 	// via a raft message.
@@ -649,6 +645,7 @@ func newReplica(rangeID roachpb.RangeID, store *Store) *Replica {
 		store:          store,
 		abortSpan:      abortspan.New(rangeID),
 		txnWaitQueue:   txnwait.NewQueue(store),
+		lockTable:      newLockTable(),
 	}
 	r.mu.pendingLeaseRequest = makePendingLeaseRequest(r)
 	r.mu.stateLoader = stateloader.Make(r.store.cfg.Settings, rangeID)
@@ -662,6 +659,9 @@ func newReplica(rangeID roachpb.RangeID, store *Store) *Replica {
 	// Pass nil for the localityOracle because we intentionally don't track the
 	// origin locality of write load.
 	r.writeStats = newReplicaStats(store.Clock(), nil)
+	split.Init(&r.loadBasedSplitter, rand.Intn, func() float64 {
+		return float64(SplitByLoadQPSThreshold.Get(&store.cfg.Settings.SV))
+	})
 
 	// Init rangeStr with the range ID.
 	r.rangeStr.store(0, &roachpb.RangeDescriptor{RangeID: rangeID})
@@ -1663,6 +1663,14 @@ func (r *Replica) GetTxnWaitQueue() *txnwait.Queue {
 	return r.txnWaitQueue
 }
 
+// ConflictingLocks returns the locks in the Replica's lock table that conflict
+// with the given request.
+func (r *Replica) ConflictingLocks(
+	txn *roachpb.Transaction, strength roachpb.KeyLockingStrength, span roachpb.Span,
+) []roachpb.Intent {
+	return r.lockTable.conflicting(txn, strength, span)
+}
+
 // GetTerm returns the term of the given index in the raft log.
 func (r *Replica) GetTerm(i uint64) (uint64, error) {
 	r.mu.RLock()
@@ -1690,6 +1698,20 @@ func (r *Replica) GetTxnSpanGCThreshold() hlc.Timestamp {
 	return *r.mu.state.TxnSpanGCThreshold
 }
 
+// GetClosedTimestamp returns the maximum closed timestamp for the replica's
+// current lease and lease applied index. Consistent reads at or below it can
+// be served by the replica even if it isn't the lease holder.
+func (r *Replica) GetClosedTimestamp() hlc.Timestamp {
+	r.mu.RLock()
+	lai := r.mu.state.LeaseAppliedIndex
+	lease := *r.mu.state.Lease
+	r.mu.RUnlock()
+
+	return r.store.cfg.ClosedTimestamp.Provider.MaxClosed(
+		lease.Replica.NodeID, r.RangeID, ctpb.Epoch(lease.Epoch), ctpb.LAI(lai),
+	)
+}
+
 // setDesc atomically sets the replica's descriptor. It requires raftMu to be
 // locked.
 func (r *Replica) setDesc(ctx context.Context, desc *roachpb.RangeDescriptor) {
@@ -1982,6 +2004,7 @@ func (r *Replica) sendWithRangeID(
 	if r.leaseholderStats != nil && ba.Header.GatewayNodeID != 0 {
 		r.leaseholderStats.record(ba.Header.GatewayNodeID)
 	}
+	r.recordBatchForLoadBasedSplitting(ba)
 
 	// Add the range log tag.
 	ctx = r.AnnotateCtx(ctx)
@@ -2881,7 +2904,7 @@ func (r *Replica) executeReadOnlyBatch(
 	if ba.ReadConsistency.RequiresReadLease() {
 		if status, pErr = r.redirectOnOrAcquireLease(ctx); pErr != nil {
 			if lErr, ok := pErr.GetDetail().(*roachpb.NotLeaseHolderError); ok &&
-				FollowerReadsEnabled.Get(&r.store.cfg.Settings.SV) &&
+				closedts.FollowerReadsEnabled.Get(&r.store.cfg.Settings.SV) &&
 				lErr.LeaseHolder != nil && lErr.Lease.Type() == roachpb.LeaseEpoch {
 
 				r.mu.RLock()
@@ -2964,6 +2987,12 @@ func (r *Replica) executeReadOnlyBatch(
 	defer readOnly.Close()
 	br, result, pErr = evaluateBatch(ctx, storagebase.CmdIDKey(""), readOnly, rec, nil, ba)
 
+	// Add the locks acquired by locking reads to the lock table while the
+	// command queue is still holding their latches.
+	if acquired, _ := result.Local.DetachLocks(); len(acquired) > 0 && pErr == nil {
+		r.lockTable.acquire(acquired)
+	}
+
 	if result.Local.DetachMaybeWatchForMerge() {
 		if err := r.maybeWatchForMerge(ctx); err != nil {
 			return nil, roachpb.NewError(err)
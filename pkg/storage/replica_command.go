@@ -78,7 +78,9 @@ func evaluateCommand(
 	var err error
 	var pd result.Result
 
-	if cmd, ok := batcheval.LookupCommand(args.Method()); ok {
+	if lockErr := checkConflictingLocks(rec, h, args); lockErr != nil {
+		err = lockErr
+	} else if cmd, ok := batcheval.LookupCommand(args.Method()); ok {
 		cArgs := batcheval.CommandArgs{
 			EvalCtx: rec,
 			Header:  h,
@@ -125,6 +127,31 @@ func evaluateCommand(
 	return pd, pErr
 }
 
+// checkConflictingLocks returns a WriteIntentError if the request is a write
+// or a locking read that conflicts with the locks held by other transactions
+// on the range. The WriteIntentError makes the Store push the transactions
+// holding the locks and wait for them to finish, like it does for intents.
+//
+// Locking reads that skip locked keys don't conflict with locks; they leave
+// out the locked keys during evaluation instead.
+func checkConflictingLocks(
+	rec batcheval.EvalContext, h roachpb.Header, args roachpb.Request,
+) error {
+	var strength roachpb.KeyLockingStrength
+	if roachpb.IsTransactionWrite(args) {
+		strength = roachpb.LOCK_EXCLUSIVE
+	} else if h.Txn != nil && h.WaitPolicy != roachpb.LOCK_WAIT_SKIP {
+		strength = roachpb.KeyLockingOf(args)
+	}
+	if strength == roachpb.LOCK_NONE {
+		return nil
+	}
+	if locks := rec.ConflictingLocks(h.Txn, strength, args.Header().Span()); len(locks) > 0 {
+		return &roachpb.WriteIntentError{Intents: locks}
+	}
+	return nil
+}
+
 func returnRangeInfo(reply roachpb.Response, rec batcheval.EvalContext) {
 	header := reply.Header()
 	lease, _ := rec.GetLease()
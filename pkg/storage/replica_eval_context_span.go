@@ -83,6 +83,14 @@ func (rec *SpanSetReplicaEvalContext) GetTxnWaitQueue() *txnwait.Queue {
 	return rec.i.GetTxnWaitQueue()
 }
 
+// ConflictingLocks returns the locks held by other transactions that conflict
+// with the given request.
+func (rec *SpanSetReplicaEvalContext) ConflictingLocks(
+	txn *roachpb.Transaction, strength roachpb.KeyLockingStrength, span roachpb.Span,
+) []roachpb.Intent {
+	return rec.i.ConflictingLocks(txn, strength, span)
+}
+
 // NodeID returns the NodeID.
 func (rec *SpanSetReplicaEvalContext) NodeID() roachpb.NodeID {
 	return rec.i.NodeID()
@@ -163,6 +171,12 @@ func (rec SpanSetReplicaEvalContext) GetTxnSpanGCThreshold() hlc.Timestamp {
 	return rec.i.GetTxnSpanGCThreshold()
 }
 
+// GetClosedTimestamp returns the maximum closed timestamp known to the
+// Replica.
+func (rec SpanSetReplicaEvalContext) GetClosedTimestamp() hlc.Timestamp {
+	return rec.i.GetClosedTimestamp()
+}
+
 // String implements Stringer.
 func (rec SpanSetReplicaEvalContext) String() string {
 	return rec.i.String()
@@ -306,6 +306,8 @@ func (r *Replica) leasePostApply(ctx context.Context, newLease roachpb.Lease) {
 		// Also clear and disable the push transaction queue. Any waiters
 		// must be redirected to the new lease holder.
 		r.txnWaitQueue.Clear(true /* disable */)
+		// The locks held on the range are lost.
+		r.lockTable.clear()
 	}
 
 	if !iAmTheLeaseHolder && r.IsLeaseValid(newLease, r.store.Clock().Now()) &&
@@ -797,6 +799,16 @@ func (r *Replica) handleLocalEvalResult(ctx context.Context, lResult result.Loca
 		lResult.Metrics = nil
 	}
 
+	// Locks are released before waiters on the finalized transactions are
+	// notified below, so that they don't run into them again.
+	if lResult.AcquiredLocks != nil || lResult.ReleasedLocks != nil {
+		acquired, released := lResult.DetachLocks()
+		r.lockTable.acquire(acquired)
+		for _, txnID := range released {
+			r.lockTable.releaseTxn(txnID)
+		}
+	}
+
 	if lResult.UpdatedTxns != nil {
 		for _, txn := range *lResult.UpdatedTxns {
 			r.txnWaitQueue.UpdateTxn(ctx, txn)
@@ -25,7 +25,6 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/storage/batcheval/result"
-	"github.com/cockroachdb/cockroach/pkg/storage/closedts/ctpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/storage/rangefeed"
@@ -88,7 +87,7 @@ func (tp *rangefeedTxnPusher) PushTxns(
 	}
 
 	pushedTxnMap, pErr := tp.ir.maybePushTransactions(
-		ctx, pushTxnMap, h, roachpb.PUSH_TIMESTAMP, false, /* skipIfInFlight */
+		ctx, pushTxnMap, nil /* contendedKeys */, h, roachpb.PUSH_TIMESTAMP, false, /* skipIfInFlight */
 	)
 	if pErr != nil {
 		return nil, pErr.GoError()
@@ -165,12 +164,19 @@ func (r *Replica) RangeFeed(
 	// Register the stream with a catch-up iterator.
 	var catchUpIter engine.SimpleIterator
 	if !args.Timestamp.IsEmpty() {
-		catchUpIter = r.Engine().NewIterator(engine.IterOptions{
+		iterOpts := engine.IterOptions{
 			UpperBound:       args.Span.EndKey,
 			MinTimestampHint: args.Timestamp,
-		})
+		}
+		if args.WithDiff {
+			// The catch-up scan needs to see the version of each key that
+			// precedes the registration's starting timestamp, which a
+			// time-bound iterator may skip.
+			iterOpts.MinTimestampHint = hlc.Timestamp{}
+		}
+		catchUpIter = r.Engine().NewIterator(iterOpts)
 	}
-	p.Register(rspan, args.Timestamp, catchUpIter, lockedStream, errC)
+	p.Register(rspan, args.Timestamp, catchUpIter, args.WithDiff, lockedStream, errC)
 	r.raftMu.Unlock()
 
 	// When this function returns, attempt to clean up the rangefeed.
@@ -297,16 +303,18 @@ func (r *Replica) handleLogicalOpLogRaftMuLocked(
 
 	// When reading straight from the Raft log, some logical ops will not be
 	// fully populated. Read from the engine (under raftMu) to populate all
-	// fields.
+	// fields. The previous values are only read if a registration asked for
+	// them, since doing so doubles the number of reads.
+	withPrev := r.raftMu.rangefeed.NeedsPrevValues()
 	for _, op := range ops.Ops {
 		var key []byte
 		var ts hlc.Timestamp
-		var valPtr *[]byte
+		var valPtr, prevValPtr *[]byte
 		switch t := op.GetValue().(type) {
 		case *enginepb.MVCCWriteValueOp:
-			key, ts, valPtr = t.Key, t.Timestamp, &t.Value
+			key, ts, valPtr, prevValPtr = t.Key, t.Timestamp, &t.Value, &t.PrevValue
 		case *enginepb.MVCCCommitIntentOp:
-			key, ts, valPtr = t.Key, t.Timestamp, &t.Value
+			key, ts, valPtr, prevValPtr = t.Key, t.Timestamp, &t.Value, &t.PrevValue
 		case *enginepb.MVCCWriteIntentOp,
 			*enginepb.MVCCUpdateIntentOp,
 			*enginepb.MVCCAbortIntentOp:
@@ -331,6 +339,26 @@ func (r *Replica) handleLogicalOpLogRaftMuLocked(
 			return
 		}
 		*valPtr = val.RawBytes
+		if !withPrev {
+			continue
+		}
+
+		// Read the previous version of the key as well, for registrations that
+		// requested diffs. Any intent on the key has been resolved by now, so
+		// the most recent version below ts is the committed value that this
+		// write replaced. A missing version or a tombstone both leave the
+		// previous value empty.
+		prevVal, _, err := engine.MVCCGetWithTombstone(ctx, r.Engine(),
+			key, ts.Prev(), true /* consistent */, nil /* txn */)
+		if err != nil {
+			r.disconnectRangefeedWithErrRaftMuLocked(roachpb.NewErrorf(
+				"error consuming %T for key %v @ ts %v: %v", op, key, ts.Prev(), err,
+			))
+			return
+		}
+		if prevVal != nil {
+			*prevValPtr = prevVal.RawBytes
+		}
 	}
 
 	// Pass the ops to the rangefeed processor.
@@ -356,15 +384,8 @@ func (r *Replica) handleClosedTimestampUpdateRaftMuLocked() {
 		return
 	}
 
-	r.mu.RLock()
-	lai := r.mu.state.LeaseAppliedIndex
-	lease := *r.mu.state.Lease
-	r.mu.RUnlock()
-
 	// Determine what the maximum closed timestamp is for this replica.
-	closedTS := r.store.cfg.ClosedTimestamp.Provider.MaxClosed(
-		lease.Replica.NodeID, r.RangeID, ctpb.Epoch(lease.Epoch), ctpb.LAI(lai),
-	)
+	closedTS := r.GetClosedTimestamp()
 
 	// If the closed timestamp is not empty, inform the Processor.
 	if closedTS.IsEmpty() {
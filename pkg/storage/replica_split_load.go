@@ -0,0 +1,70 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package storage
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// SplitByLoadEnabled controls whether ranges are split automatically based on
+// the load they receive.
+var SplitByLoadEnabled = settings.RegisterBoolSetting(
+	"kv.range_split.by_load_enabled",
+	"allow automatic splits of ranges based on where load is concentrated",
+	true,
+)
+
+// SplitByLoadQPSThreshold is the QPS above which a range becomes a candidate
+// for load-based splitting.
+var SplitByLoadQPSThreshold = settings.RegisterValidatedIntSetting(
+	"kv.range_split.load_qps_threshold",
+	"the QPS over which the range becomes a candidate for load based splitting",
+	250,
+	func(v int64) error {
+		if v <= 0 {
+			return errors.Errorf("cannot set kv.range_split.load_qps_threshold to a non-positive value: %d", v)
+		}
+		return nil
+	},
+)
+
+// SplitByLoadEnabled returns whether load based splitting is enabled.
+func (r *Replica) SplitByLoadEnabled() bool {
+	return SplitByLoadEnabled.Get(&r.store.cfg.Settings.SV)
+}
+
+// recordBatchForLoadBasedSplitting records the span of a batch received by the
+// replica with its load-based splitter, and queues the replica for splitting
+// once the splitter has found a key that balances the load.
+func (r *Replica) recordBatchForLoadBasedSplitting(ba roachpb.BatchRequest) {
+	if !r.SplitByLoadEnabled() {
+		return
+	}
+	shouldInitSplit := r.loadBasedSplitter.Record(timeutil.Now(), len(ba.Requests), func() roachpb.Span {
+		rspan, err := keys.Range(ba)
+		if err != nil {
+			return roachpb.Span{}
+		}
+		return rspan.AsRawSpanWithNoLocals()
+	})
+	if shouldInitSplit {
+		r.store.splitQueue.MaybeAdd(r, r.store.Clock().Now())
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package split contains the logic used to decide when and where a range
+// should be split based on the load it receives.
+package split
+
+import (
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// Decider tracks the QPS of a range and, once it exceeds a threshold, samples
+// the spans of the requests made to it with a Finder in order to find a key to
+// split it at. The zero value is not usable; see Init.
+//
+// Decider is safe for concurrent use.
+type Decider struct {
+	intn         func(n int) int // supplied to Init
+	qpsThreshold func() float64  // supplied to Init
+
+	mu struct {
+		syncutil.Mutex
+		// Fields tracking the current QPS sample.
+		lastQPSRollover time.Time // most recent time recorded by requests.
+		count           int64     // number of requests recorded since last rollover.
+
+		// Fields tracking the split key search. splitFinder is only set while
+		// the QPS is above the threshold.
+		lastQPS     float64 // last reported QPS.
+		splitFinder *Finder
+	}
+}
+
+// Init initializes a Decider (which is assumed to be zero). intn returns a
+// random integer in [0, n), and qpsThreshold returns the QPS above which the
+// range should be split.
+func Init(d *Decider, intn func(n int) int, qpsThreshold func() float64) {
+	d.intn = intn
+	d.qpsThreshold = qpsThreshold
+}
+
+// Record notifies the Decider that n requests are being carried out which
+// operate on the span returned by the supplied function. The function is only
+// called when the Decider is looking for a split key and samples spans.
+// Record returns true if a split key has been found, in which case the range
+// should be queued for splitting.
+func (d *Decider) Record(now time.Time, n int, spanFn func() roachpb.Span) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.mu.count += int64(n)
+
+	// The QPS is computed every second. If it exceeds the threshold, a Finder
+	// is started (if there isn't one already); if it doesn't, the Finder is
+	// discarded as the range likely doesn't need to be split anymore.
+	if elapsed := now.Sub(d.mu.lastQPSRollover); elapsed >= time.Second {
+		d.mu.lastQPS = float64(d.mu.count) / elapsed.Seconds()
+		d.mu.count = 0
+		d.mu.lastQPSRollover = now
+		if d.mu.lastQPS >= d.qpsThreshold() {
+			if d.mu.splitFinder == nil {
+				d.mu.splitFinder = NewFinder(now)
+			}
+		} else {
+			d.mu.splitFinder = nil
+		}
+	}
+
+	if d.mu.splitFinder != nil && n != 0 {
+		if span := spanFn(); span.Key != nil {
+			d.mu.splitFinder.Record(span, d.intn)
+		}
+		if d.mu.splitFinder.Ready(now) && d.mu.splitFinder.Key() != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// LastQPS returns the most recent QPS measurement.
+func (d *Decider) LastQPS() float64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.mu.lastQPS
+}
+
+// MaybeSplitKey returns a key to split the range at in order to balance its
+// load, or nil if no such key has been found (yet).
+func (d *Decider) MaybeSplitKey(now time.Time) roachpb.Key {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.mu.splitFinder == nil || !d.mu.splitFinder.Ready(now) {
+		return nil
+	}
+	return d.mu.splitFinder.Key()
+}
+
+// Reset deactivates any current attempt at determining a split key, for
+// example after the range has been split.
+func (d *Decider) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.mu.lastQPS = 0
+	d.mu.splitFinder = nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package split
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestDecider(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	rng := rand.New(rand.NewSource(1))
+
+	var d Decider
+	Init(&d, rng.Intn, func() float64 { return 10.0 })
+
+	spanFn := func() roachpb.Span { return roachpb.Span{Key: intKey(rng.Intn(100))} }
+	ms := func(i int) time.Time {
+		return time.Unix(0, int64(i)*int64(time.Millisecond))
+	}
+
+	// The QPS is computed once a second has passed since the last computation.
+	require.False(t, d.Record(ms(100), 1, spanFn))
+	require.True(t, d.LastQPS() < 1)
+	require.False(t, d.Record(ms(600), 5, spanFn))
+	require.True(t, d.LastQPS() < 1)
+	require.False(t, d.Record(ms(1100), 5, spanFn))
+	require.Equal(t, 10.0, d.LastQPS())
+	require.NotNil(t, d.mu.splitFinder)
+
+	// Dropping below the threshold stops the split key search, and exceeding
+	// it starts a new one.
+	require.False(t, d.Record(ms(2100), 9, spanFn))
+	require.Equal(t, 9.0, d.LastQPS())
+	require.Nil(t, d.mu.splitFinder)
+	require.False(t, d.Record(ms(3100), 20, spanFn))
+	require.Equal(t, 20.0, d.LastQPS())
+	require.NotNil(t, d.mu.splitFinder)
+
+	// Keep the load up until a split key is found, which only happens after
+	// RecordDurationThreshold.
+	var found bool
+	var i int
+	for i = 3101; !found; i++ {
+		found = d.Record(ms(i), 1, spanFn)
+		if ms(i).Sub(ms(3100)) <= RecordDurationThreshold {
+			require.False(t, found)
+			require.Nil(t, d.MaybeSplitKey(ms(i)))
+		}
+	}
+	require.NotNil(t, d.MaybeSplitKey(ms(i)))
+
+	// Dropping below the threshold abandons the search.
+	require.False(t, d.Record(ms(i+1000000), 1, spanFn))
+	require.Nil(t, d.MaybeSplitKey(ms(i+1000000)))
+	require.Nil(t, d.mu.splitFinder)
+
+	// So does Reset.
+	require.False(t, d.Record(ms(i+1001000), 100, spanFn))
+	require.NotNil(t, d.mu.splitFinder)
+	d.Reset()
+	require.Nil(t, d.mu.splitFinder)
+	require.Equal(t, 0.0, d.LastQPS())
+}
@@ -0,0 +1,134 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package split
+
+import (
+	"bytes"
+	"math"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+)
+
+const (
+	// splitKeySampleSize is the number of candidate split keys kept in the
+	// reservoir.
+	splitKeySampleSize = 20
+	// splitKeyMinCounter is the minimum number of requests that must have been
+	// compared against a candidate before it can be picked.
+	splitKeyMinCounter = 100
+	// splitKeyThreshold is the maximum relative imbalance between the requests
+	// on the left and the right of a candidate for it to be picked.
+	splitKeyThreshold = 0.25
+	// splitKeyContainedThreshold is the maximum fraction of requests which
+	// span a candidate for it to be picked. Splitting at such a key turns the
+	// requests into multi-range requests, which doesn't reduce load.
+	splitKeyContainedThreshold = 0.50
+)
+
+// RecordDurationThreshold is how long a Finder samples requests before it
+// attempts to produce a split key.
+const RecordDurationThreshold = 10 * time.Second
+
+type sample struct {
+	key                    roachpb.Key
+	left, right, contained int
+}
+
+// Finder looks for a split key which balances the requests made to a range
+// between its two sides. It keeps a reservoir sample of the start keys of the
+// requests it is shown, and counts for each of them how many of the later
+// requests fall entirely on its left, entirely on its right, or span it.
+type Finder struct {
+	startTime time.Time
+	samples   [splitKeySampleSize]sample
+	count     int
+}
+
+// NewFinder initiates a Finder with the given time.
+func NewFinder(startTime time.Time) *Finder {
+	return &Finder{
+		startTime: startTime,
+	}
+}
+
+// Ready checks if the Finder has sampled requests for long enough to be
+// queried for a split key.
+func (f *Finder) Ready(nowTime time.Time) bool {
+	return nowTime.Sub(f.startTime) > RecordDurationThreshold
+}
+
+// Record informs the Finder about a request touching the given span. intNFn
+// returns a random integer in [0, n) and is used for the reservoir sampling.
+func (f *Finder) Record(span roachpb.Span, intNFn func(int) int) {
+	if f == nil {
+		return
+	}
+
+	var idx int
+	count := f.count
+	f.count++
+	if count < splitKeySampleSize {
+		idx = count
+	} else if idx = intNFn(count); idx >= splitKeySampleSize {
+		// The request isn't sampled. Count it against the existing samples.
+		for i := range f.samples {
+			s := &f.samples[i]
+			if bytes.Compare(span.Key, s.key) >= 0 {
+				s.right++
+			} else if len(span.EndKey) == 0 || bytes.Compare(span.EndKey, s.key) <= 0 {
+				s.left++
+			} else {
+				s.contained++
+			}
+		}
+		return
+	}
+
+	// The start key of the span is used as the candidate split key. A key in
+	// the middle of the span would be more precise for range requests, but
+	// isn't worth the complexity.
+	f.samples[idx] = sample{key: span.Key}
+}
+
+// Key finds an appropriate split point based on the sampled requests. It
+// returns nil if no candidate is good enough.
+func (f *Finder) Key() roachpb.Key {
+	if f == nil {
+		return nil
+	}
+
+	bestIdx := -1
+	bestScore := 2.0
+	for i, s := range f.samples {
+		if s.left+s.right+s.contained < splitKeyMinCounter {
+			continue
+		}
+		balanceScore := math.Abs(float64(s.left-s.right)) / float64(s.left+s.right)
+		containedScore := float64(s.contained) / float64(s.left+s.right+s.contained)
+		if balanceScore >= splitKeyThreshold || containedScore >= splitKeyContainedThreshold {
+			continue
+		}
+		if score := balanceScore + containedScore; score < bestScore {
+			bestIdx = i
+			bestScore = score
+		}
+	}
+
+	if bestIdx == -1 {
+		return nil
+	}
+	return f.samples[bestIdx].key
+}
@@ -0,0 +1,71 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package split
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func intKey(i int) roachpb.Key {
+	return encoding.EncodeUvarintAscending(nil, uint64(i))
+}
+
+func TestFinderKey(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	rng := rand.New(rand.NewSource(1))
+
+	// Uniform point requests over [0, 100) are balanced around the middle.
+	f := NewFinder(time.Unix(0, 0))
+	require.Nil(t, f.Key())
+	for i := 0; i < 10000; i++ {
+		f.Record(roachpb.Span{Key: intKey(rng.Intn(100))}, rng.Intn)
+	}
+	key := f.Key()
+	require.NotNil(t, key)
+	require.True(t, intKey(25).Compare(key) < 0 && key.Compare(intKey(75)) < 0,
+		"expected a key near the middle, got %s", key)
+
+	// Requests for a single key can't be balanced.
+	f = NewFinder(time.Unix(0, 0))
+	for i := 0; i < 10000; i++ {
+		f.Record(roachpb.Span{Key: intKey(1)}, rng.Intn)
+	}
+	require.Nil(t, f.Key())
+
+	// Splitting at a key which most requests span doesn't help.
+	f = NewFinder(time.Unix(0, 0))
+	for i := 0; i < 10000; i++ {
+		start := rng.Intn(10)
+		f.Record(roachpb.Span{Key: intKey(start), EndKey: intKey(start + 1000)}, rng.Intn)
+	}
+	require.Nil(t, f.Key())
+}
+
+func TestFinderReady(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	start := time.Unix(100, 0)
+	f := NewFinder(start)
+	require.False(t, f.Ready(start))
+	require.False(t, f.Ready(start.Add(RecordDurationThreshold)))
+	require.True(t, f.Ready(start.Add(RecordDurationThreshold+time.Second)))
+}
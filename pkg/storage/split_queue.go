@@ -23,10 +23,13 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/config"
 	"github.com/cockroachdb/cockroach/pkg/gossip"
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/storage/storagebase"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 )
 
 const (
@@ -46,7 +49,7 @@ const (
 	splitQueueConcurrency = 4
 )
 
-// splitQueue manages a queue of ranges slated to be split due to size
+// splitQueue manages a queue of ranges slated to be split due to size, load,
 // or along intersecting zone config boundaries.
 type splitQueue struct {
 	*baseQueue
@@ -106,11 +109,19 @@ func shouldSplitRange(
 
 // shouldQueue determines whether a range should be queued for
 // splitting. This is true if the range is intersected by a zone config
-// prefix or if the range's size in bytes exceeds the limit for the zone.
+// prefix, if the range's size in bytes exceeds the limit for the zone, or if
+// a key to split the range at in order to balance its load has been found.
 func (sq *splitQueue) shouldQueue(
 	ctx context.Context, now hlc.Timestamp, repl *Replica, sysCfg config.SystemConfig,
 ) (shouldQ bool, priority float64) {
-	return shouldSplitRange(repl.Desc(), repl.GetMVCCStats(), repl.GetMaxBytes(), sysCfg)
+	shouldQ, priority = shouldSplitRange(repl.Desc(), repl.GetMVCCStats(), repl.GetMaxBytes(), sysCfg)
+
+	if !shouldQ && repl.SplitByLoadEnabled() {
+		if splitKey := repl.loadBasedSplitter.MaybeSplitKey(timeutil.Now()); splitKey != nil {
+			shouldQ, priority = true, 1.0 // default priority
+		}
+	}
+	return shouldQ, priority
 }
 
 // unsplittableRangeError indicates that a split attempt failed because a no
@@ -174,6 +185,39 @@ func (sq *splitQueue) processAttempt(
 		)
 		return err
 	}
+
+	// Finally handle case of splitting due to load.
+	if !r.SplitByLoadEnabled() {
+		return nil
+	}
+	if splitByLoadKey := r.loadBasedSplitter.MaybeSplitKey(timeutil.Now()); splitByLoadKey != nil {
+		qps := r.loadBasedSplitter.LastQPS()
+		// We don't want to split in the middle of a SQL row.
+		splitKey, err := keys.EnsureSafeSplitKey(splitByLoadKey)
+		if err != nil {
+			return errors.Wrapf(err, "unable to split %s by load at key %q", r, splitByLoadKey)
+		}
+		if !storagebase.ContainsKey(*desc, splitKey) || desc.StartKey.Equal(splitKey) {
+			// The row containing the key starts before the range, so there's
+			// nothing to split. Start looking for a new split key.
+			r.loadBasedSplitter.Reset()
+			return nil
+		}
+		if _, err := r.adminSplitWithDescriptor(
+			ctx,
+			roachpb.AdminSplitRequest{
+				RequestHeader: roachpb.RequestHeader{
+					Key: splitKey,
+				},
+				SplitKey: splitKey,
+			},
+			desc,
+		); err != nil {
+			return errors.Wrapf(err, "unable to split %s at key %q", r, splitKey)
+		}
+		log.Infof(ctx, "split by load at key %s (%.2f QPS)", splitKey, qps)
+		r.loadBasedSplitter.Reset()
+	}
 	return nil
 }
 
@@ -45,6 +45,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/storage/admission"
 	"github.com/cockroachdb/cockroach/pkg/storage/batcheval"
 	"github.com/cockroachdb/cockroach/pkg/storage/closedts/container"
 	"github.com/cockroachdb/cockroach/pkg/storage/closedts/ctpb"
@@ -378,6 +379,7 @@ type Store struct {
 	db                 *client.DB
 	engine             engine.Engine        // The underlying key-value store
 	compactor          *compactor.Compactor // Schedules compaction of the engine
+	admissionQ         *admission.WorkQueue // Admits work in order of priority when overloaded
 	tsCache            tscache.Cache        // Most recent timestamps for keys / key ranges
 	allocator          Allocator            // Makes allocation decisions
 	replRankings       *replicaRankings
@@ -980,6 +982,9 @@ func NewStore(cfg StoreConfig, eng engine.Engine, nodeDesc *roachpb.NodeDescript
 	)
 	s.metrics.registry.AddMetricStruct(s.compactor.Metrics)
 
+	s.admissionQ = admission.NewWorkQueue(s.cfg.Settings, cfg.HistogramWindowInterval)
+	s.metrics.registry.AddMetricStruct(s.admissionQ.Metrics)
+
 	s.snapshotApplySem = make(chan struct{}, cfg.concurrentSnapshotApplyLimit)
 
 	s.renewableLeasesSignal = make(chan struct{})
@@ -1570,6 +1575,8 @@ func (s *Store) Start(ctx context.Context, stopper *stop.Stopper) error {
 		s.storeRebalancer.Start(ctx, s.stopper)
 	}
 
+	s.startAdmissionController(ctx)
+
 	// Start the storage engine compactor.
 	if envutil.EnvOrDefaultBool("COCKROACH_ENABLE_COMPACTOR", true) {
 		s.compactor.Start(s.AnnotateCtx(context.Background()), s.stopper)
@@ -2459,6 +2466,9 @@ func (s *Store) SplitRange(
 	// to ensure that no pre-split commands are inserted into the
 	// txnWaitQueue after we clear it.
 	leftRepl.txnWaitQueue.Clear(false /* disable */)
+	// The locks held on the LHS may cover keys of the RHS, which the LHS is no
+	// longer responsible for. Drop them all; see lockTable.
+	leftRepl.lockTable.clear()
 
 	// The rangefeed processor will no longer be provided logical ops for
 	// its entire range, so it needs to be shut down and all registrations
@@ -3050,6 +3060,15 @@ func (s *Store) Send(
 		}
 	}
 
+	// Wait for the batch to be admitted, which throttles it if the store is
+	// overloaded. This happens after the clock update above so that the clock
+	// isn't held back by queued work.
+	admitted, err := s.admitBatch(ctx, &ba)
+	if err != nil {
+		return nil, roachpb.NewError(err)
+	}
+	defer admitted()
+
 	defer func() {
 		if r := recover(); r != nil {
 			// On panic, don't run the defer. It's probably just going to panic
@@ -3212,8 +3231,11 @@ func (s *Store) Send(
 			// Process and resolve write intent error. We do this here because
 			// this is the code path with the requesting client waiting.
 			if pErr.Index != nil {
+				// Locking reads abort the transactions holding conflicting
+				// intents and locks like writes do, as they want to hold a lock
+				// on the keys themselves.
 				var pushType roachpb.PushTxnType
-				if ba.IsWrite() {
+				if ba.IsWrite() || ba.IsLocking() {
 					pushType = roachpb.PUSH_ABORT
 				} else {
 					pushType = roachpb.PUSH_TIMESTAMP
@@ -3244,8 +3266,16 @@ func (s *Store) Send(
 				// any other pusher queued up behind this RPC to proceed.
 				if cleanupAfterWriteIntentError != nil {
 					cleanupAfterWriteIntentError(t, nil)
+					cleanupAfterWriteIntentError = nil
 				}
-				if cleanupAfterWriteIntentError, pErr =
+				if ba.WaitPolicy == roachpb.LOCK_WAIT_ERROR {
+					// The request doesn't want to wait for the conflicting
+					// transactions, so it doesn't queue up behind them.
+					if pErr = s.intentResolver.processWriteIntentErrorNoWait(ctx, pErr, h); pErr != nil {
+						pErr.Index = index
+						return nil, pErr
+					}
+				} else if cleanupAfterWriteIntentError, pErr =
 					s.intentResolver.processWriteIntentError(ctx, pErr, args, h, pushType); pErr != nil {
 					// Do not propagate ambiguous results; assume success and retry original op.
 					if _, ok := pErr.GetDetail().(*roachpb.AmbiguousResultError); !ok {
@@ -3346,6 +3376,8 @@ func (s *Store) maybeWaitForPushee(
 			// and set the push type to ABORT.
 			pushReqCopy.Force = true
 			pushReqCopy.PushType = roachpb.PUSH_ABORT
+			s.metrics.TxnDeadlocks.Inc(1)
+			s.logTxnDeadlock(ctx, repl.RangeID, pushReq)
 		} else if pErr != nil {
 			return nil, pErr
 		} else if pushResp != nil {
@@ -4514,6 +4546,26 @@ func (s *Store) ComputeStatsForKeySpan(startKey, endKey roachpb.RKey) (StoreKeyS
 	return result, err
 }
 
+// TxnWait describes a transaction waiting in the txn wait queue of one of the
+// store's replicas.
+type TxnWait struct {
+	RangeID roachpb.RangeID
+	txnwait.Wait
+}
+
+// TxnWaits returns the transactions which are waiting on other transactions
+// in the txn wait queues of the store's replicas.
+func (s *Store) TxnWaits() []TxnWait {
+	var waits []TxnWait
+	newStoreReplicaVisitor(s).Visit(func(repl *Replica) bool {
+		for _, w := range repl.txnWaitQueue.Waits() {
+			waits = append(waits, TxnWait{RangeID: repl.RangeID, Wait: w})
+		}
+		return true
+	})
+	return waits
+}
+
 // AllocatorDryRun runs the given replica through the allocator without actually
 // carrying out any changes, returning all trace messages collected along the way.
 // Intended to help power a debug endpoint.
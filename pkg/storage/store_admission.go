@@ -0,0 +1,164 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/admission"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// admissionSignalInterval is how often the load of the store is sampled in
+// order to resize its admission queue.
+const admissionSignalInterval = time.Second
+
+// runQueueSampleInterval is how often the length of the run queue is sampled.
+// It changes much faster than admissionSignalInterval, so the samples are
+// averaged.
+const runQueueSampleInterval = 100 * time.Millisecond
+
+// admissionPriority returns the priority with which the batch is admitted by
+// the store. The writes of a transaction are admitted with the priority of the
+// transaction like any other work, so that for instance the writes of a bulk
+// backfill wait behind user work.
+func admissionPriority(ba *roachpb.BatchRequest) roachpb.AdmissionPriority {
+	coordinationOnly := true
+	for _, union := range ba.Requests {
+		arg := union.GetInner()
+		if arg.Header().Key.Compare(keys.UserTableDataMin) < 0 {
+			// Work on the system keyspace, for instance node liveness heartbeats
+			// and range addressing lookups, is needed for the cluster to function.
+			return roachpb.ADMISSION_SYSTEM
+		}
+		switch arg.Method() {
+		case roachpb.EndTransaction, roachpb.ResolveIntent, roachpb.ResolveIntentRange:
+			// The batch releases intents which other work may be waiting on. It
+			// isn't queued so that waiting work holding slots can't keep it from
+			// running.
+			return roachpb.ADMISSION_SYSTEM
+		case roachpb.PushTxn, roachpb.QueryTxn, roachpb.HeartbeatTxn, roachpb.QueryIntent:
+		default:
+			coordinationOnly = false
+		}
+	}
+	if coordinationOnly {
+		// Requests coordinating transactions allow work waiting on locks to
+		// proceed, and may themselves wait on other transactions for a long time.
+		return roachpb.ADMISSION_SYSTEM
+	}
+	return ba.AdmissionPriority
+}
+
+// admitBatch waits until the batch is admitted by the admission queue of the
+// store, and returns a function to be called once the batch has executed.
+func (s *Store) admitBatch(ctx context.Context, ba *roachpb.BatchRequest) (func(), error) {
+	// Order work by the time at which it was created, so that every client is
+	// treated the same and older transactions get to finish first.
+	createTime := ba.Timestamp
+	if ba.Txn != nil {
+		createTime = ba.Txn.OrigTimestamp
+	}
+	return s.admissionQ.Admit(ctx, admissionPriority(ba), createTime)
+}
+
+// runQueueSampler averages the number of runnable threads per CPU of the node.
+type runQueueSampler struct {
+	sum     float64
+	samples int
+}
+
+func (r *runQueueSampler) sample(ctx context.Context) {
+	n, err := runnableThreads()
+	if err != nil {
+		log.VEventf(ctx, 3, "unable to get the run queue length: %s", err)
+		return
+	}
+	r.sum += float64(n) / float64(runtime.NumCPU())
+	r.samples++
+}
+
+// take returns the average of the samples taken since the last call.
+func (r *runQueueSampler) take() float64 {
+	if r.samples == 0 {
+		return 0
+	}
+	avg := r.sum / float64(r.samples)
+	*r = runQueueSampler{}
+	return avg
+}
+
+// admissionSignals samples the load of the store, given the average length
+// of the run queue.
+func (s *Store) admissionSignals(runQueueLength float64) admission.Signals {
+	signals := admission.Signals{RunQueueLength: runQueueLength}
+	if eng, ok := s.engine.(engine.WithSSTables); ok {
+		for _, t := range eng.GetSSTables() {
+			if t.Level == 0 {
+				signals.L0FileCount++
+			}
+		}
+	}
+	return signals
+}
+
+// startAdmissionController starts a goroutine which periodically resizes the
+// admission queue of the store based on its load.
+func (s *Store) startAdmissionController(ctx context.Context) {
+	ctx = s.AnnotateCtx(ctx)
+	s.stopper.RunWorker(ctx, func(ctx context.Context) {
+		ticker := time.NewTicker(admissionSignalInterval)
+		defer ticker.Stop()
+		sampleTicker := time.NewTicker(runQueueSampleInterval)
+		defer sampleTicker.Stop()
+		var runQueue runQueueSampler
+		var overloaded bool
+		for {
+			select {
+			case <-s.stopper.ShouldQuiesce():
+				return
+			case <-sampleTicker.C:
+				if admission.Enabled.Get(&s.cfg.Settings.SV) {
+					runQueue.sample(ctx)
+				}
+				continue
+			case <-ticker.C:
+			}
+
+			if !admission.Enabled.Get(&s.cfg.Settings.SV) {
+				s.admissionQ.Adjust(admission.Signals{})
+				runQueue.take()
+				overloaded = false
+				continue
+			}
+			signals := s.admissionSignals(runQueue.take())
+			s.admissionQ.Adjust(signals)
+			if o := s.admissionQ.Overloaded(signals); o != overloaded {
+				overloaded = o
+				if overloaded {
+					log.Infof(ctx, "store is overloaded (%d L0 files, %.1f runnable threads per CPU), throttling work",
+						signals.L0FileCount, signals.RunQueueLength)
+				} else {
+					log.Infof(ctx, "store is no longer overloaded, %d admission slots", s.admissionQ.Slots())
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,56 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// +build linux
+
+package storage
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// runnableThreads returns the number of threads of the machine that are
+// running or waiting for a CPU, which the kernel reports as procs_running in
+// /proc/stat. Unlike the load average, this doesn't count threads blocked on
+// I/O and isn't smoothed over the last minute.
+func runnableThreads() (int, error) {
+	stat, err := ioutil.ReadFile("/proc/stat")
+	if err != nil {
+		return 0, err
+	}
+	return parseProcsRunning(stat)
+}
+
+func parseProcsRunning(stat []byte) (int, error) {
+	const prefix = "procs_running "
+	for _, line := range bytes.Split(stat, []byte("\n")) {
+		if !bytes.HasPrefix(line, []byte(prefix)) {
+			continue
+		}
+		n, err := strconv.Atoi(string(bytes.TrimSpace(line[len(prefix):])))
+		if err != nil {
+			return 0, errors.Wrap(err, "parsing procs_running")
+		}
+		// The thread reading the file is running too.
+		if n > 0 {
+			n--
+		}
+		return n, nil
+	}
+	return 0, errors.New("procs_running not found in /proc/stat")
+}
@@ -0,0 +1,50 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// +build linux
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestParseProcsRunning(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	stat := []byte(`cpu  2255 34 2290 22625563 6290 127 456 0 0 0
+cpu0 1132 34 1441 11311718 3675 127 438 0 0 0
+intr 114930548 113199788 3 0 5 263 0 4 [... lots more numbers ...]
+ctxt 1990473
+btime 1062191376
+processes 2915
+procs_running 5
+procs_blocked 12
+`)
+	n, err := parseProcsRunning(stat)
+	require.NoError(t, err)
+	// The thread reading the file isn't counted.
+	require.Equal(t, 4, n)
+
+	_, err = parseProcsRunning([]byte("procs_blocked 12\n"))
+	require.EqualError(t, err, "procs_running not found in /proc/stat")
+
+	n, err = runnableThreads()
+	require.NoError(t, err)
+	require.True(t, n >= 0)
+}
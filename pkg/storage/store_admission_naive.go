@@ -0,0 +1,26 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// +build !linux
+
+package storage
+
+import "github.com/pkg/errors"
+
+// runnableThreads returns the number of threads of the machine that are
+// running or waiting for a CPU. It is only implemented on Linux, so admission
+// control only reacts to the LSM of the store on other platforms.
+func runnableThreads() (int, error) {
+	return 0, errors.New("the run queue length is only available on Linux")
+}
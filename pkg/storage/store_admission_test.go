@@ -0,0 +1,142 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage/admission"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestAdmissionPriority(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	userKey := roachpb.Key(keys.MakeTablePrefix(keys.MinUserDescID + 1))
+	get := &roachpb.GetRequest{RequestHeader: roachpb.RequestHeader{Key: userKey}}
+	put := &roachpb.PutRequest{RequestHeader: roachpb.RequestHeader{Key: userKey}}
+	resolve := &roachpb.ResolveIntentRequest{RequestHeader: roachpb.RequestHeader{Key: userKey}}
+	push := &roachpb.PushTxnRequest{RequestHeader: roachpb.RequestHeader{Key: userKey}}
+	commit := &roachpb.EndTransactionRequest{RequestHeader: roachpb.RequestHeader{Key: userKey}}
+	liveness := &roachpb.ConditionalPutRequest{
+		RequestHeader: roachpb.RequestHeader{Key: keys.NodeLivenessKey(1)},
+	}
+
+	testCases := []struct {
+		name   string
+		txn    *roachpb.Transaction
+		pri    roachpb.AdmissionPriority
+		reqs   []roachpb.Request
+		expPri roachpb.AdmissionPriority
+	}{
+		{"user", nil, roachpb.ADMISSION_USER, []roachpb.Request{get}, roachpb.ADMISSION_USER},
+		{"bulk", nil, roachpb.ADMISSION_BULK, []roachpb.Request{get}, roachpb.ADMISSION_BULK},
+		{"system key", nil, roachpb.ADMISSION_BULK, []roachpb.Request{get, liveness}, roachpb.ADMISSION_SYSTEM},
+		{"coordination", nil, roachpb.ADMISSION_BULK, []roachpb.Request{resolve}, roachpb.ADMISSION_SYSTEM},
+		{"mixed", nil, roachpb.ADMISSION_BULK, []roachpb.Request{push, get}, roachpb.ADMISSION_BULK},
+		{"resolve", nil, roachpb.ADMISSION_BULK, []roachpb.Request{resolve, get}, roachpb.ADMISSION_SYSTEM},
+		{"writing txn", &roachpb.Transaction{Writing: true}, roachpb.ADMISSION_BULK,
+			[]roachpb.Request{put}, roachpb.ADMISSION_BULK},
+		{"commit", &roachpb.Transaction{Writing: true}, roachpb.ADMISSION_BULK,
+			[]roachpb.Request{put, commit}, roachpb.ADMISSION_SYSTEM},
+		{"txn", &roachpb.Transaction{}, roachpb.ADMISSION_BULK,
+			[]roachpb.Request{get}, roachpb.ADMISSION_BULK},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var ba roachpb.BatchRequest
+			ba.Txn = tc.txn
+			ba.AdmissionPriority = tc.pri
+			ba.Add(tc.reqs...)
+			require.Equal(t, tc.expPri, admissionPriority(&ba))
+		})
+	}
+}
+
+// TestAdmissionBulkWritesQueued verifies that when the store is overloaded,
+// the writes of a bulk transaction, such as an index backfill, wait behind user
+// work even though they were created earlier.
+func TestAdmissionBulkWritesQueued(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	st := cluster.MakeTestingClusterSettings()
+	admission.Enabled.Override(&st.SV, true)
+	s := &Store{admissionQ: admission.NewWorkQueue(st, time.Minute)}
+	s.admissionQ.Adjust(admission.Signals{L0FileCount: math.MaxInt32})
+	require.Equal(t, admission.MinSlots, s.admissionQ.Slots())
+
+	ctx := context.Background()
+	var dones []func()
+	for i := 0; i < admission.MinSlots; i++ {
+		done, err := s.admissionQ.Admit(ctx, roachpb.ADMISSION_USER, hlc.Timestamp{})
+		require.NoError(t, err)
+		dones = append(dones, done)
+	}
+
+	userKey := roachpb.Key(keys.MakeTablePrefix(keys.MinUserDescID + 1))
+	var backfill roachpb.BatchRequest
+	backfill.Txn = &roachpb.Transaction{Writing: true}
+	backfill.Txn.OrigTimestamp = hlc.Timestamp{WallTime: 1}
+	backfill.AdmissionPriority = roachpb.ADMISSION_BULK
+	backfill.Add(&roachpb.PutRequest{RequestHeader: roachpb.RequestHeader{Key: userKey}})
+	var user roachpb.BatchRequest
+	user.Timestamp = hlc.Timestamp{WallTime: 2}
+	user.AdmissionPriority = roachpb.ADMISSION_USER
+	user.Add(&roachpb.GetRequest{RequestHeader: roachpb.RequestHeader{Key: userKey}})
+
+	admittedCh := make(chan string)
+	for i, w := range []struct {
+		name string
+		ba   *roachpb.BatchRequest
+	}{
+		{"backfill", &backfill},
+		{"user", &user},
+	} {
+		w := w
+		go func() {
+			done, err := s.admitBatch(ctx, w.ba)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			admittedCh <- w.name
+			done()
+		}()
+		testutils.SucceedsSoon(t, func() error {
+			if l := s.admissionQ.Metrics.QueueLength.Value(); l != int64(i+1) {
+				return errors.Errorf("expected %d waiting, found %d", i+1, l)
+			}
+			return nil
+		})
+	}
+
+	dones[0]()
+	require.Equal(t, "user", <-admittedCh)
+	require.Equal(t, "backfill", <-admittedCh)
+	for _, done := range dones[1:] {
+		done()
+	}
+}
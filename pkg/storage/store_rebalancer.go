@@ -598,23 +598,45 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 			continue
 		}
 
-		// Pick the replica with the least QPS to be leaseholder;
-		// TestingRelocateRange transfers the lease to the first provided
-		// target.
-		newLeaseIdx := 0
-		newLeaseQPS := math.MaxFloat64
-		for i := 0; i < len(targets); i++ {
-			storeDesc, ok := storeMap[desc.Replicas[i].StoreID]
-			if ok && storeDesc.Capacity.QueriesPerSecond < newLeaseQPS {
-				newLeaseIdx = i
-				newLeaseQPS = storeDesc.Capacity.QueriesPerSecond
-			}
-		}
+		// Pick the new leaseholder; TestingRelocateRange transfers the lease to
+		// the first provided target.
+		preferred := sr.rq.allocator.preferredLeaseholders(zone, targetReplicas)
+		newLeaseIdx := chooseNewLeaseholderIdx(targets, preferred, storeMap)
 		targets[0], targets[newLeaseIdx] = targets[newLeaseIdx], targets[0]
 		return replWithStats, targets
 	}
 }
 
+// chooseNewLeaseholderIdx returns the index of the target which should hold
+// the lease of a range relocated to the given targets: the one on the store
+// with the least QPS, among the targets matching the zone's lease preferences
+// if any of them do.
+func chooseNewLeaseholderIdx(
+	targets []roachpb.ReplicationTarget,
+	preferred []roachpb.ReplicaDescriptor,
+	storeMap map[roachpb.StoreID]*roachpb.StoreDescriptor,
+) int {
+	newLeaseIdx := -1
+	newLeaseQPS := math.MaxFloat64
+	for i := range targets {
+		if len(preferred) > 0 && !storeHasReplica(targets[i].StoreID, preferred) {
+			continue
+		}
+		if newLeaseIdx == -1 {
+			newLeaseIdx = i
+		}
+		storeDesc, ok := storeMap[targets[i].StoreID]
+		if ok && storeDesc.Capacity.QueriesPerSecond < newLeaseQPS {
+			newLeaseIdx = i
+			newLeaseQPS = storeDesc.Capacity.QueriesPerSecond
+		}
+	}
+	if newLeaseIdx == -1 {
+		return 0
+	}
+	return newLeaseIdx
+}
+
 func shouldNotMoveAway(
 	ctx context.Context,
 	replWithStats replicaWithStats,
@@ -163,3 +163,53 @@ func TestChooseLeaseToTransfer(t *testing.T) {
 		}
 	}
 }
+
+func TestChooseNewLeaseholderIdx(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	storeMap := make(map[roachpb.StoreID]*roachpb.StoreDescriptor)
+	for _, desc := range noLocalityStores {
+		storeMap[desc.StoreID] = desc
+	}
+	makeTargets := func(storeIDs ...roachpb.StoreID) []roachpb.ReplicationTarget {
+		var targets []roachpb.ReplicationTarget
+		for _, storeID := range storeIDs {
+			targets = append(targets, roachpb.ReplicationTarget{
+				NodeID:  roachpb.NodeID(storeID),
+				StoreID: storeID,
+			})
+		}
+		return targets
+	}
+	makeReplicas := func(storeIDs ...roachpb.StoreID) []roachpb.ReplicaDescriptor {
+		var replicas []roachpb.ReplicaDescriptor
+		for _, storeID := range storeIDs {
+			replicas = append(replicas, roachpb.ReplicaDescriptor{
+				NodeID:  roachpb.NodeID(storeID),
+				StoreID: storeID,
+			})
+		}
+		return replicas
+	}
+
+	testCases := []struct {
+		targets   []roachpb.ReplicationTarget
+		preferred []roachpb.ReplicaDescriptor
+		expected  int
+	}{
+		// Without lease preferences, the store with the least QPS wins.
+		{makeTargets(1, 2, 3), nil, 2},
+		{makeTargets(5, 2, 3), nil, 0},
+		// With lease preferences, the preferred store with the least QPS wins.
+		{makeTargets(1, 2, 5), makeReplicas(1), 0},
+		{makeTargets(1, 2, 5), makeReplicas(1, 2), 1},
+		{makeTargets(1, 2, 5), makeReplicas(2, 5), 2},
+		// Preferred stores with unknown QPS can still get the lease.
+		{makeTargets(1, 6, 5), makeReplicas(6), 1},
+	}
+	for i, tc := range testCases {
+		if idx := chooseNewLeaseholderIdx(tc.targets, tc.preferred, storeMap); idx != tc.expected {
+			t.Errorf("%d: expected target %d to get the lease, got %d", i, tc.expected, idx)
+		}
+	}
+}
@@ -131,6 +131,72 @@ func TestTxnWaitQueueEnableDisable(t *testing.T) {
 	}
 }
 
+// TestTxnWaitQueueWaits verifies that a waiting push is reported by the
+// queue and by its store, along with the contended key.
+func TestTxnWaitQueueWaits(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	tc := testContext{}
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	tc.Start(t, stopper)
+
+	txn, err := createTxnForPushQueue(context.Background(), &tc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := tc.repl.txnWaitQueue
+	q.Enqueue(txn)
+	if waits := q.Waits(); len(waits) != 0 {
+		t.Fatalf("expected no waits; got %+v", waits)
+	}
+
+	pusher := newTransaction("pusher", roachpb.Key("a"), 1, enginepb.SERIALIZABLE, tc.Clock())
+	req := roachpb.PushTxnRequest{
+		PushType:     roachpb.PUSH_ABORT,
+		PusherTxn:    *pusher,
+		PusheeTxn:    txn.TxnMeta,
+		ContendedKey: roachpb.Key("a"),
+	}
+
+	retCh := make(chan RespWithErr, 1)
+	go func() {
+		resp, pErr := q.MaybeWaitForPush(context.Background(), tc.repl, &req)
+		retCh <- RespWithErr{resp, pErr}
+	}()
+
+	testutils.SucceedsSoon(t, func() error {
+		waits := tc.store.TxnWaits()
+		if len(waits) != 1 {
+			return errors.Errorf("expected 1 wait; got %+v", waits)
+		}
+		w := waits[0]
+		if w.RangeID != tc.repl.RangeID {
+			return errors.Errorf("expected range %d; got %d", tc.repl.RangeID, w.RangeID)
+		}
+		if w.Pusher.ID != pusher.ID || w.Pushee.ID != txn.ID {
+			return errors.Errorf("expected %s waiting on %s; got %+v", pusher.ID, txn.ID, w)
+		}
+		if !w.Key.Equal(roachpb.Key("a")) {
+			return errors.Errorf("expected contended key %q; got %q", roachpb.Key("a"), w.Key)
+		}
+		if w.Start.IsZero() {
+			return errors.Errorf("expected wait start to be set")
+		}
+		return nil
+	})
+
+	// Clearing the queue releases the waiter.
+	q.Clear(false /* disable */)
+	respWithErr := <-retCh
+	if respWithErr.pErr != nil {
+		t.Fatal(respWithErr.pErr)
+	}
+	if waits := q.Waits(); len(waits) != 0 {
+		t.Fatalf("expected no waits; got %+v", waits)
+	}
+}
+
 func TestTxnWaitQueueCancel(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	tc := testContext{}
@@ -87,6 +87,8 @@ func createPushTxnResponse(txn *roachpb.Transaction) *roachpb.PushTxnResponse {
 // dependency cycles.
 type waitingPush struct {
 	req *roachpb.PushTxnRequest
+	// start is the time at which the push started waiting.
+	start time.Time
 	// pending channel receives updated, pushed txn or nil if queue is cleared.
 	pending chan *roachpb.Transaction
 	mu      struct {
@@ -318,6 +320,40 @@ func (q *Queue) GetDependents(txnID uuid.UUID) []uuid.UUID {
 	return nil
 }
 
+// Wait describes a PushTxn request which is waiting in the queue for its
+// pushee transaction to commit or abort.
+type Wait struct {
+	// Pusher is the waiting transaction. Its ID is empty if the pusher is
+	// not transactional.
+	Pusher enginepb.TxnMeta
+	// Pushee is the transaction being waited on.
+	Pushee enginepb.TxnMeta
+	// Key is the key on which the pusher encountered the pushee, if known.
+	Key roachpb.Key
+	// Start is the time at which the push started waiting.
+	Start time.Time
+}
+
+// Waits returns the PushTxn requests which are currently waiting in the
+// queue.
+func (q *Queue) Waits() []Wait {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var waits []Wait
+	for _, pending := range q.mu.txns {
+		pushee := pending.getTxn().TxnMeta
+		for _, push := range pending.waitingPushes {
+			waits = append(waits, Wait{
+				Pusher: push.req.PusherTxn.TxnMeta,
+				Pushee: pushee,
+				Key:    push.req.ContendedKey,
+				Start:  push.start,
+			})
+		}
+	}
+	return waits
+}
+
 // isTxnUpdated returns whether the transaction specified in
 // the QueryTxnRequest has had its status or priority updated
 // or whether the known set of dependent transactions has
@@ -396,6 +432,7 @@ func (q *Queue) MaybeWaitForPush(
 
 	push := &waitingPush{
 		req:     req,
+		start:   q.store.Clock().PhysicalTime(),
 		pending: make(chan *roachpb.Transaction, 1),
 	}
 	pending.waitingPushes = append(pending.waitingPushes, push)
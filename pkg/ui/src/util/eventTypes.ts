@@ -34,6 +34,10 @@ export const CREATE_SEQUENCE = "create_sequence";
 export const ALTER_SEQUENCE = "alter_sequence";
 // Recorded when a sequence is dropped.
 export const DROP_SEQUENCE = "drop_sequence";
+// Recorded when a type is created.
+export const CREATE_TYPE = "create_type";
+// Recorded when a type is altered.
+export const ALTER_TYPE = "alter_type";
 // Recorded when an in-progress schema change encounters a problem and is
 // reversed.
 export const REVERSE_SCHEMA_CHANGE = "reverse_schema_change";
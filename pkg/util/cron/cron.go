@@ -0,0 +1,231 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package cron parses cron expressions and computes the times they match.
+//
+// An expression is either one of the macros @yearly (or @annually),
+// @monthly, @weekly, @daily (or @midnight) and @hourly, or five
+// space-separated fields: minute, hour, day of month, month and day of week.
+// Each field is a comma-separated list of `*`, a value or a range of values
+// `a-b`, each optionally followed by a step `/n`. Months and days of the week
+// may also be given by their three-letter English names, and both 0 and 7
+// denote Sunday. As in the traditional cron, if both the day of month and the
+// day of week are restricted, a day matches if either of them does.
+//
+// Expressions are evaluated in UTC.
+package cron
+
+import (
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Schedule is a parsed cron expression.
+type Schedule struct {
+	minute, hour, dom, month, dow bitset
+	// domStar and dowStar are set when the day of month, respectively the day
+	// of week, field is unrestricted.
+	domStar, dowStar bool
+}
+
+// bitset holds the set of values, all less than 64, matched by a field.
+type bitset uint64
+
+func (b bitset) has(v int) bool {
+	return b&(1<<uint(v)) != 0
+}
+
+type fieldBounds struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteBounds = fieldBounds{name: "minute", min: 0, max: 59}
+	hourBounds   = fieldBounds{name: "hour", min: 0, max: 23}
+	domBounds    = fieldBounds{name: "day of month", min: 1, max: 31}
+	monthBounds  = fieldBounds{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowBounds = fieldBounds{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse parses a cron expression.
+func Parse(expr string) (*Schedule, error) {
+	spec := strings.TrimSpace(expr)
+	if strings.HasPrefix(spec, "@") {
+		var ok bool
+		if spec, ok = macros[strings.ToLower(spec)]; !ok {
+			return nil, errors.Errorf("unknown cron macro %q", expr)
+		}
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, errors.Errorf(
+			"invalid cron expression %q: expected 5 fields, found %d", expr, len(fields))
+	}
+	var s Schedule
+	var err error
+	if s.minute, _, err = parseField(fields[0], minuteBounds); err != nil {
+		return nil, errors.Wrapf(err, "invalid cron expression %q", expr)
+	}
+	if s.hour, _, err = parseField(fields[1], hourBounds); err != nil {
+		return nil, errors.Wrapf(err, "invalid cron expression %q", expr)
+	}
+	if s.dom, s.domStar, err = parseField(fields[2], domBounds); err != nil {
+		return nil, errors.Wrapf(err, "invalid cron expression %q", expr)
+	}
+	if s.month, _, err = parseField(fields[3], monthBounds); err != nil {
+		return nil, errors.Wrapf(err, "invalid cron expression %q", expr)
+	}
+	if s.dow, s.dowStar, err = parseField(fields[4], dowBounds); err != nil {
+		return nil, errors.Wrapf(err, "invalid cron expression %q", expr)
+	}
+	// 7 is an alias for Sunday.
+	if s.dow.has(7) {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+	return &s, nil
+}
+
+// parseField parses a comma-separated field of a cron expression. It also
+// returns whether the field is unrestricted.
+func parseField(field string, b fieldBounds) (bitset, bool, error) {
+	var set bitset
+	star := field == "*"
+	for _, item := range strings.Split(field, ",") {
+		rangeExpr, step := item, 1
+		if i := strings.IndexByte(item, '/'); i >= 0 {
+			var err error
+			rangeExpr = item[:i]
+			if step, err = strconv.Atoi(item[i+1:]); err != nil || step <= 0 {
+				return 0, false, errors.Errorf("invalid step in %s field %q", b.name, item)
+			}
+		}
+		var lo, hi int
+		switch {
+		case rangeExpr == "*":
+			lo, hi = b.min, b.max
+		case strings.IndexByte(rangeExpr, '-') >= 0:
+			i := strings.IndexByte(rangeExpr, '-')
+			var err error
+			if lo, err = parseValue(rangeExpr[:i], b); err != nil {
+				return 0, false, err
+			}
+			if hi, err = parseValue(rangeExpr[i+1:], b); err != nil {
+				return 0, false, err
+			}
+			if lo > hi {
+				return 0, false, errors.Errorf("invalid range in %s field %q", b.name, item)
+			}
+		default:
+			var err error
+			if lo, err = parseValue(rangeExpr, b); err != nil {
+				return 0, false, err
+			}
+			hi = lo
+			if step != 1 {
+				// As in the traditional cron, `a/n` means `a-max/n`.
+				hi = b.max
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, star, nil
+}
+
+func parseValue(s string, b fieldBounds) (int, error) {
+	if v, ok := b.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, errors.Errorf("invalid value %q in %s field", s, b.name)
+	}
+	if v < b.min || v > b.max {
+		return 0, errors.Errorf("%s value %d out of range [%d, %d]", b.name, v, b.min, b.max)
+	}
+	return v, nil
+}
+
+// maxSearchYears bounds the search for the next matching time, which may
+// otherwise not terminate for expressions that never match, such as the 30th
+// of February.
+const maxSearchYears = 5
+
+// Next returns the earliest time, in UTC and at a whole minute, that is
+// strictly after t and matches the schedule. It returns the zero time if
+// there is no such time in the next few years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.Year() + maxSearchYears
+	// Each loop advances t to the start of the next month, day, hour or
+	// minute until every field matches. Whenever a field wraps around, the
+	// coarser fields need to be checked again.
+	for t.Year() <= limit {
+		if !s.month.has(int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !s.hour.has(t.Hour()) {
+			t = t.Truncate(time.Hour).Add(time.Hour)
+			continue
+		}
+		if !s.minute.has(t.Minute()) {
+			// Skip directly to the next matching minute of this hour, if any.
+			next := bitset(uint64(s.minute) &^ (1<<uint(t.Minute()+1) - 1))
+			if next == 0 {
+				t = t.Truncate(time.Hour).Add(time.Hour)
+			} else {
+				t = t.Add(time.Duration(bits.TrailingZeros64(uint64(next))-t.Minute()) * time.Minute)
+			}
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom.has(t.Day())
+	dowMatch := s.dow.has(int(t.Weekday()))
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
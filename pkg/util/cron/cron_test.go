@@ -0,0 +1,94 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+)
+
+func TestNext(t *testing.T) {
+	parse := func(s string) time.Time {
+		ts, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ts
+	}
+
+	testCases := []struct {
+		expr     string
+		from     string
+		expected string
+	}{
+		{"@hourly", "2018-06-01T10:00:00Z", "2018-06-01T11:00:00Z"},
+		{"@hourly", "2018-06-01T10:59:59Z", "2018-06-01T11:00:00Z"},
+		{"@daily", "2018-06-01T10:00:00Z", "2018-06-02T00:00:00Z"},
+		{"@DAILY", "2018-12-31T23:59:00Z", "2019-01-01T00:00:00Z"},
+		{"@weekly", "2018-06-01T10:00:00Z", "2018-06-03T00:00:00Z"},
+		{"@monthly", "2018-01-31T00:00:00Z", "2018-02-01T00:00:00Z"},
+		{"@yearly", "2018-06-01T10:00:00Z", "2019-01-01T00:00:00Z"},
+		{"*/15 * * * *", "2018-06-01T10:07:30Z", "2018-06-01T10:15:00Z"},
+		{"*/15 * * * *", "2018-06-01T10:45:00Z", "2018-06-01T11:00:00Z"},
+		{"5,35 2-4 * * *", "2018-06-01T04:35:00Z", "2018-06-02T02:05:00Z"},
+		{"30 12 * * mon-fri", "2018-06-01T13:00:00Z", "2018-06-04T12:30:00Z"},
+		{"0 0 * * 7", "2018-06-01T00:00:00Z", "2018-06-03T00:00:00Z"},
+		{"0 0 29 feb *", "2018-03-01T00:00:00Z", "2020-02-29T00:00:00Z"},
+		// Both the day of month and the day of week are restricted: either
+		// matches.
+		{"0 0 13 * fri", "2018-06-01T10:00:00Z", "2018-06-08T00:00:00Z"},
+		{"0 0 13 * fri", "2018-06-08T10:00:00Z", "2018-06-13T00:00:00Z"},
+		// Times are interpreted in UTC.
+		{"0 12 * * *", "2018-06-01T11:00:00-02:00", "2018-06-02T12:00:00Z"},
+		// Never matches.
+		{"0 0 30 2 *", "2018-06-01T00:00:00Z", "0001-01-01T00:00:00Z"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.expr+"/"+tc.from, func(t *testing.T) {
+			s, err := Parse(tc.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if next, expected := s.Next(parse(tc.from)), parse(tc.expected); !next.Equal(expected) {
+				t.Errorf("expected %s, got %s", expected, next)
+			}
+		})
+	}
+}
+
+func TestParseError(t *testing.T) {
+	testCases := []struct {
+		expr string
+		err  string
+	}{
+		{"", "expected 5 fields, found 0"},
+		{"* * * *", "expected 5 fields, found 4"},
+		{"@sometimes", "unknown cron macro"},
+		{"60 * * * *", "minute value 60 out of range"},
+		{"* * 0 * *", "day of month value 0 out of range"},
+		{"* * * foo *", `invalid value "foo" in month field`},
+		{"*/0 * * * *", "invalid step"},
+		{"5-1 * * * *", "invalid range"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.expr, func(t *testing.T) {
+			if _, err := Parse(tc.expr); !testutils.IsError(err, tc.err) {
+				t.Fatalf("expected %q, got %v", tc.err, err)
+			}
+		})
+	}
+}